@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// coverageMissingLimit is how many undocumented items are listed per metric
+const coverageMissingLimit = 10
+
+// loadCoverageReport reads a report from a running docs server or a saved JSON file
+func loadCoverageReport(url, file, header string) (*core.CoverageReport, error) {
+	var body []byte
+	switch {
+	case file != "":
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		body = content
+	case url != "":
+		content, err := fetchDocs(url, "/coverage", header)
+		if err != nil {
+			return nil, err
+		}
+		body = content
+	default:
+		return nil, fmt.Errorf("pass -url of the running docs or -file of a saved coverage report")
+	}
+
+	var report core.CoverageReport
+	if err := json.Unmarshal(body, &report); err != nil {
+		return nil, fmt.Errorf("invalid coverage report: %w", err)
+	}
+	return &report, nil
+}
+
+// printCoverage prints each metric with its first undocumented items
+func printCoverage(w io.Writer, report *core.CoverageReport) {
+	metrics := []struct {
+		name   string
+		metric core.CoverageMetric
+	}{
+		{"Routes with a summary", report.Routes},
+		{"Request bodies with an example", report.RequestExamples},
+		{"Typed response bodies", report.ResponseSchemas},
+		{"Fields with a description", report.FieldDescriptions},
+	}
+
+	for _, entry := range metrics {
+		fmt.Fprintf(w, "%-32s %5.1f%% (%d/%d)\n", entry.name, entry.metric.Percent, entry.metric.Documented, entry.metric.Total)
+		for i, missing := range entry.metric.Missing {
+			if i == coverageMissingLimit {
+				fmt.Fprintf(w, "    ... and %d more\n", len(entry.metric.Missing)-coverageMissingLimit)
+				break
+			}
+			fmt.Fprintf(w, "    - %s\n", missing)
+		}
+	}
+	fmt.Fprintf(w, "\nDocumentation coverage: %.1f%%\n", report.Score)
+}
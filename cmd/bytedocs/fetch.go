@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// fetchDocs GETs path below the docs URL of a running app. header is an
+// optional "Name: value" pair for docs behind authentication.
+func fetchDocs(url, path, header string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(url, "/")+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if header != "" {
+		name, value, found := strings.Cut(header, ":")
+		if !found {
+			return nil, fmt.Errorf("header must look like \"Name: value\", got %q", header)
+		}
+		req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", req.URL, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+const usage = `ByteDocs CLI
+
+Usage:
+  bytedocs <command> [flags]
+
+Commands:
+  init      Scaffold a ByteDocs integration into an existing project
+  coverage  Report documentation coverage and fail below a threshold
+  types     Download TypeScript types and a fetch client from running docs
+
+Run "bytedocs <command> -h" for command-specific flags.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "init":
+		err = runInit(os.Args[2:])
+	case "coverage":
+		err = runCoverage(os.Args[2:])
+	case "types":
+		err = runTypes(os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runInit parses flags for the init command and writes the scaffold files
+func runInit(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	framework := fs.String("framework", "", "web framework: "+supportedFrameworkList())
+	dir := fs.String("dir", ".", "project directory to scaffold into")
+	docsPath := fs.String("docs-path", "/docs", "path the documentation is served under")
+	title := fs.String("title", "API Documentation", "documentation title")
+	force := fs.Bool("force", false, "overwrite existing files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := scaffoldOptions{
+		Framework: *framework,
+		Dir:       *dir,
+		DocsPath:  *docsPath,
+		Title:     *title,
+		Force:     *force,
+	}
+
+	written, err := scaffold(opts)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range written {
+		fmt.Printf("✅ Created %s\n", path)
+	}
+	fmt.Println("")
+	fmt.Print(placementAdvice(opts))
+	return nil
+}
+
+// runCoverage prints the coverage report and fails when the score is below -min
+func runCoverage(args []string) error {
+	fs := flag.NewFlagSet("coverage", flag.ContinueOnError)
+	url := fs.String("url", "", "docs URL of a running app, e.g. http://localhost:8080/docs")
+	file := fs.String("file", "", "coverage report JSON saved from /docs/coverage or APIDocs.Coverage")
+	header := fs.String("header", "", "request header for protected docs, e.g. \"X-API-Key: secret\"")
+	minScore := fs.Float64("min", 0, "minimum coverage score in percent")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	report, err := loadCoverageReport(*url, *file, *header)
+	if err != nil {
+		return err
+	}
+
+	printCoverage(os.Stdout, report)
+	if report.Score < *minScore {
+		return fmt.Errorf("documentation coverage %.1f%% is below the minimum of %.1f%%", report.Score, *minScore)
+	}
+	return nil
+}
+
+// runTypes writes the TypeScript generated by /types.ts to -out or stdout
+func runTypes(args []string) error {
+	fs := flag.NewFlagSet("types", flag.ContinueOnError)
+	url := fs.String("url", "", "docs URL of a running app, e.g. http://localhost:8080/docs")
+	header := fs.String("header", "", "request header for protected docs, e.g. \"X-API-Key: secret\"")
+	out := fs.String("out", "", "file to write, e.g. src/api.ts (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *url == "" {
+		return fmt.Errorf("pass -url of the running docs")
+	}
+
+	source, err := fetchDocs(*url, "/types.ts", *header)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(source)
+		return err
+	}
+	if err := os.WriteFile(*out, source, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Created %s\n", *out)
+	return nil
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// frameworkTemplate describes how ByteDocs is wired into a specific framework
+type frameworkTemplate struct {
+	Import     string // framework import path
+	RouterType string // type accepted by the generated setup function
+	RouterVar  string // conventional variable name for the router
+	SetupCall  string // parser.SetupXDocs function name
+	Advice     string // framework-specific placement notes
+}
+
+var frameworkTemplates = map[string]frameworkTemplate{
+	"gin": {
+		Import:     "github.com/gin-gonic/gin",
+		RouterType: "*gin.Engine",
+		RouterVar:  "r",
+		SetupCall:  "SetupGinDocs",
+		Advice:     "Call setupByteDocs(r) right after gin.Default()/gin.New(). Routes are detected on the first docs request, so registration order does not matter.",
+	},
+	"echo": {
+		Import:     "github.com/labstack/echo/v4",
+		RouterType: "*echo.Echo",
+		RouterVar:  "e",
+		SetupCall:  "SetupEchoDocs",
+		Advice:     "Call setupByteDocs(e) right after echo.New(), before e.Start().",
+	},
+	"fiber": {
+		Import:     "github.com/gofiber/fiber/v2",
+		RouterType: "*fiber.App",
+		RouterVar:  "app",
+		SetupCall:  "SetupFiberDocs",
+		Advice:     "Call setupByteDocs(app) right after fiber.New(), before app.Listen().",
+	},
+	"gorilla-mux": {
+		RouterType: "*parser.GorillaMuxWrapper",
+		RouterVar:  "r",
+		SetupCall:  "SetupGorillaMuxDocs",
+		Advice:     "Replace mux.NewRouter() with parser.NewGorillaMuxWrapper() so routes can be tracked, then call setupByteDocs(r).",
+	},
+	"net-http": {
+		RouterType: "*parser.NetHTTPMuxWrapper",
+		RouterVar:  "mux",
+		SetupCall:  "SetupNetHTTPDocs",
+		Advice:     "Replace http.NewServeMux() with parser.NewNetHTTPMuxWrapper() so routes can be tracked, then call setupByteDocs(mux).",
+	},
+	"stdlib": {
+		RouterType: "*parser.StdlibMuxWrapper",
+		RouterVar:  "mux",
+		SetupCall:  "SetupStdlibDocs",
+		Advice:     "Replace http.NewServeMux() with parser.NewStdlibMuxWrapper() so routes can be tracked, then call setupByteDocs(mux).",
+	},
+}
+
+// scaffoldOptions holds the user-supplied init settings
+type scaffoldOptions struct {
+	Framework string
+	Dir       string
+	DocsPath  string
+	Title     string
+	Force     bool
+}
+
+const setupFileName = "bytedocs.go"
+const envFileName = ".env.bytedocs.example"
+
+var setupTemplate = template.Must(template.New("setup").Parse(`package {{.Package}}
+
+import (
+	"log"
+	"os"
+{{range .Imports}}
+	{{.}}{{end}}
+)
+
+// setupByteDocs wires ByteDocs into the router. Configuration is read from
+// the environment (see {{.EnvFile}}).
+func setupByteDocs({{.Framework.RouterVar}} {{.Framework.RouterType}}) {
+	config, err := core.LoadConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load ByteDocs config: %v", err)
+	}
+	// The docs path chosen with bytedocs init applies unless the environment sets one
+	if os.Getenv("BYTEDOCS_DOCS_PATH") == "" {
+		config.DocsPath = "{{.DocsPath}}"
+	}
+	if err := core.ValidateConfig(config); err != nil {
+		log.Fatalf("Invalid ByteDocs config: %v", err)
+	}
+
+	parser.{{.Framework.SetupCall}}({{.Framework.RouterVar}}, config)
+	log.Printf("📚 API Documentation available at %s", config.DocsPath)
+}
+`))
+
+var envTemplate = template.Must(template.New("env").Parse(`# ByteDocs configuration
+# Copy these values into your .env file and customize them
+
+# Basic API Information
+BYTEDOCS_TITLE={{.Title}}
+BYTEDOCS_VERSION=1.0.0
+BYTEDOCS_DESCRIPTION=Auto-generated API documentation
+BYTEDOCS_BASE_URL=http://localhost:8080
+BYTEDOCS_DOCS_PATH={{.DocsPath}}
+BYTEDOCS_AUTO_DETECT=true
+
+# Authentication (optional)
+BYTEDOCS_AUTH_ENABLED=false
+# BYTEDOCS_AUTH_TYPE=session
+# BYTEDOCS_AUTH_PASSWORD=change-this-password
+
+# UI Configuration
+BYTEDOCS_UI_THEME=auto
+BYTEDOCS_UI_SHOW_TRY_IT=true
+BYTEDOCS_UI_SHOW_SCHEMAS=true
+
+# AI Configuration (optional)
+BYTEDOCS_AI_ENABLED=false
+# BYTEDOCS_AI_PROVIDER=openai
+# BYTEDOCS_AI_API_KEY=your-api-key-here
+# BYTEDOCS_AI_MODEL=gpt-4o-mini
+`))
+
+// scaffold writes the integration files and returns the paths it created
+func scaffold(opts scaffoldOptions) ([]string, error) {
+	fw, ok := frameworkTemplates[opts.Framework]
+	if !ok {
+		if opts.Framework == "" {
+			return nil, fmt.Errorf("--framework is required (supported: %s)", supportedFrameworkList())
+		}
+		return nil, fmt.Errorf("unsupported framework: %s (supported: %s)", opts.Framework, supportedFrameworkList())
+	}
+	if !strings.HasPrefix(opts.DocsPath, "/") {
+		return nil, fmt.Errorf("docs path must start with /")
+	}
+	if opts.DocsPath != "/" {
+		opts.DocsPath = strings.TrimSuffix(opts.DocsPath, "/")
+	}
+
+	info, err := os.Stat(opts.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access project directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", opts.Dir)
+	}
+
+	imports := []string{
+		`"github.com/idnexacloud/bytedocs-go/pkg/core"`,
+		`_ "github.com/idnexacloud/bytedocs-go/pkg/llm"`,
+		`"github.com/idnexacloud/bytedocs-go/pkg/parser"`,
+	}
+	if fw.Import != "" {
+		imports = append(imports, `"`+fw.Import+`"`)
+	}
+	sort.Slice(imports, func(i, j int) bool {
+		return strings.TrimPrefix(imports[i], `_ `) < strings.TrimPrefix(imports[j], `_ `)
+	})
+
+	data := struct {
+		Package   string
+		Imports   []string
+		Framework frameworkTemplate
+		DocsPath  string
+		Title     string
+		EnvFile   string
+	}{
+		Package:   detectPackageName(opts.Dir),
+		Imports:   imports,
+		Framework: fw,
+		DocsPath:  opts.DocsPath,
+		Title:     opts.Title,
+		EnvFile:   envFileName,
+	}
+
+	files := []struct {
+		name string
+		tmpl *template.Template
+	}{
+		{setupFileName, setupTemplate},
+		{envFileName, envTemplate},
+	}
+
+	for _, file := range files {
+		path := filepath.Join(opts.Dir, file.name)
+		if _, err := os.Stat(path); err == nil && !opts.Force {
+			return nil, fmt.Errorf("%s already exists (use --force to overwrite)", path)
+		}
+	}
+
+	written := make([]string, 0, len(files))
+	for _, file := range files {
+		var buf bytes.Buffer
+		if err := file.tmpl.Execute(&buf, data); err != nil {
+			return written, fmt.Errorf("failed to render %s: %w", file.name, err)
+		}
+		path := filepath.Join(opts.Dir, file.name)
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+// detectPackageName returns the package name used by existing Go files in dir
+func detectPackageName(dir string) string {
+	fset := token.NewFileSet()
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, match := range matches {
+		if strings.HasSuffix(match, "_test.go") || filepath.Base(match) == setupFileName {
+			continue
+		}
+		file, err := parser.ParseFile(fset, match, nil, parser.PackageClauseOnly)
+		if err == nil && file.Name != nil {
+			return file.Name.Name
+		}
+	}
+	return "main"
+}
+
+// placementAdvice explains where to call the generated setup function
+func placementAdvice(opts scaffoldOptions) string {
+	fw := frameworkTemplates[opts.Framework]
+	var b strings.Builder
+	b.WriteString("📝 Next steps:\n")
+	fmt.Fprintf(&b, "   1. %s\n", fw.Advice)
+	fmt.Fprintf(&b, "   2. Copy the values from %s into your .env file.\n", envFileName)
+	b.WriteString("   3. Run `go get github.com/idnexacloud/bytedocs-go` if the module is not yet a dependency.\n")
+	fmt.Fprintf(&b, "   4. Start your app and open %s\n", opts.DocsPath)
+	return b.String()
+}
+
+func supportedFrameworkList() string {
+	names := make([]string, 0, len(frameworkTemplates))
+	for name := range frameworkTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
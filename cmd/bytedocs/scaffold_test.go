@@ -0,0 +1,173 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScaffold_WritesSetupAndEnvTemplate(t *testing.T) {
+	for name, fw := range frameworkTemplates {
+		dir := t.TempDir()
+		written, err := scaffold(scaffoldOptions{Framework: name, Dir: dir, DocsPath: "/api-docs/", Title: "Shop API"})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(written) != 2 || written[0] != filepath.Join(dir, setupFileName) || written[1] != filepath.Join(dir, envFileName) {
+			t.Fatalf("%s: expected the setup file and env template, got %v", name, written)
+		}
+
+		setup, err := parser.ParseFile(token.NewFileSet(), written[0], nil, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("%s: generated setup does not parse: %v", name, err)
+		}
+		source, _ := os.ReadFile(written[0])
+		for _, want := range []string{"parser." + fw.SetupCall + "(" + fw.RouterVar + ", config)", `config.DocsPath = "/api-docs"`, fw.RouterType} {
+			if !strings.Contains(string(source), want) {
+				t.Errorf("%s: expected %q in the setup file:\n%s", name, want, source)
+			}
+		}
+		if setup.Name.Name != "main" {
+			t.Errorf("%s: expected package main without other files, got %s", name, setup.Name.Name)
+		}
+
+		env, _ := os.ReadFile(written[1])
+		if !strings.Contains(string(env), "BYTEDOCS_TITLE=Shop API\n") || !strings.Contains(string(env), "BYTEDOCS_DOCS_PATH=/api-docs\n") {
+			t.Errorf("%s: expected the title and docs path in the env template:\n%s", name, env)
+		}
+	}
+}
+
+func TestScaffold_KeepsExistingFilesAndPackage(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "routes.go"), []byte("package api\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "routes_test.go"), []byte("package api_test\n"), 0644)
+
+	opts := scaffoldOptions{Framework: "echo", Dir: dir, DocsPath: "/docs", Title: "API"}
+	if _, err := scaffold(opts); err != nil {
+		t.Fatal(err)
+	}
+	if source, _ := os.ReadFile(filepath.Join(dir, setupFileName)); !strings.HasPrefix(string(source), "package api\n") {
+		t.Fatalf("expected the package of the existing files, got:\n%s", source)
+	}
+
+	if _, err := scaffold(opts); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected existing files kept without --force, got %v", err)
+	}
+	opts.Force = true
+	if _, err := scaffold(opts); err != nil {
+		t.Fatalf("expected --force to overwrite, got %v", err)
+	}
+
+	for _, tc := range []struct {
+		opts scaffoldOptions
+		want string
+	}{
+		{scaffoldOptions{Dir: dir, DocsPath: "/docs"}, "--framework is required"},
+		{scaffoldOptions{Framework: "martini", Dir: dir, DocsPath: "/docs"}, "unsupported framework: martini"},
+		{scaffoldOptions{Framework: "gin", Dir: dir, DocsPath: "docs"}, "must start with /"},
+		{scaffoldOptions{Framework: "gin", Dir: filepath.Join(dir, "missing"), DocsPath: "/docs"}, "cannot access project directory"},
+	} {
+		if _, err := scaffold(tc.opts); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%+v: expected %q, got %v", tc.opts, tc.want, err)
+		}
+	}
+}
+
+func TestScaffold_GeneratedProjectBuilds(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds generated projects")
+	}
+
+	for name, router := range map[string]struct{ imports, expr string }{
+		"gin":         {`"github.com/gin-gonic/gin"`, "gin.New()"},
+		"echo":        {`"github.com/labstack/echo/v4"`, "echo.New()"},
+		"fiber":       {`"github.com/gofiber/fiber/v2"`, "fiber.New()"},
+		"gorilla-mux": {`"github.com/idnexacloud/bytedocs-go/pkg/parser"`, "parser.NewGorillaMuxWrapper()"},
+		"net-http":    {`"github.com/idnexacloud/bytedocs-go/pkg/parser"`, "parser.NewNetHTTPMuxWrapper()"},
+		"stdlib":      {`"github.com/idnexacloud/bytedocs-go/pkg/parser"`, "parser.NewStdlibMuxWrapper()"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			buildGeneratedProject(t, name, "/docs", router.imports, router.expr, os.DevNull)
+		})
+	}
+}
+
+func TestScaffold_DocsPathAppliesUnlessSetInEnvironment(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a generated project")
+	}
+	binary := filepath.Join(t.TempDir(), "app")
+	dir := buildGeneratedProject(t, "stdlib", "/api-docs", `"github.com/idnexacloud/bytedocs-go/pkg/parser"`, "parser.NewStdlibMuxWrapper()", binary)
+
+	for env, want := range map[string]string{"": "/api-docs", "/reference": "/reference"} {
+		run := exec.Command(binary)
+		run.Dir = dir // no .env, so LoadConfigFromEnv sees only the process environment
+		run.Env = withoutEnv(os.Environ(), "BYTEDOCS_DOCS_PATH")
+		if env != "" {
+			run.Env = append(run.Env, "BYTEDOCS_DOCS_PATH="+env)
+		}
+		output, err := run.CombinedOutput()
+		if err != nil {
+			t.Fatalf("generated project failed: %v\n%s", err, output)
+		}
+		if !strings.Contains(string(output), "available at "+want+"\n") {
+			t.Errorf("BYTEDOCS_DOCS_PATH=%q: expected the docs at %s, got:\n%s", env, want, output)
+		}
+	}
+}
+
+// buildGeneratedProject scaffolds framework into a new module whose main
+// passes expr to setupByteDocs, builds it to output and returns its directory
+func buildGeneratedProject(t *testing.T, framework, docsPath, imports, expr, output string) string {
+	t.Helper()
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if _, err := scaffold(scaffoldOptions{Framework: framework, Dir: dir, DocsPath: docsPath, Title: "API"}); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"main.go": "package main\n\nimport " + imports + "\n\nfunc main() {\n\tsetupByteDocs(" + expr + ")\n}\n",
+		"go.mod": "module example.com/app\n\ngo 1.24.0\n\nrequire github.com/idnexacloud/bytedocs-go v0.0.0\n\n" +
+			"replace github.com/idnexacloud/bytedocs-go => " + filepath.ToSlash(root) + "\n",
+		"go.sum": string(sum),
+	}
+	for file, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Dependencies come from the module cache filled by this repo's build
+	build := exec.Command("go", "build", "-o", output, ".")
+	build.Dir = dir
+	build.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off")
+	if output, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("generated project does not build: %v\n%s", err, output)
+	}
+	return dir
+}
+
+// withoutEnv returns environ without the variable name
+func withoutEnv(environ []string, name string) []string {
+	kept := make([]string, 0, len(environ))
+	for _, entry := range environ {
+		if !strings.HasPrefix(entry, name+"=") {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
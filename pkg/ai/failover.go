@@ -0,0 +1,117 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// failoverCooldown is how long a failed provider is tried after the others
+const failoverCooldown = 30 * time.Second
+
+// failoverClient tries the configured provider and then its fallbacks until
+// one answers
+type failoverClient struct {
+	mu      sync.Mutex
+	clients []Client
+	models  []string    // configured model of each client
+	failed  []time.Time // last failure of each client
+}
+
+// newFailoverClient builds the clients of the fallbacks of config; primary
+// is the client of config itself
+func newFailoverClient(config *AIConfig, primary Client) (Client, error) {
+	c := &failoverClient{clients: []Client{primary}, models: []string{config.Features.Model}}
+	for i, fallback := range config.Fallbacks {
+		factory, exists := clientFactories[fallback.Provider]
+		if !exists {
+			return nil, fmt.Errorf("unsupported LLM provider of fallback %d: %s", i+1, fallback.Provider)
+		}
+		fallbackConfig := *config
+		fallbackConfig.Provider = fallback.Provider
+		fallbackConfig.APIKey = fallback.APIKey
+		fallbackConfig.Features.Model = fallback.Model
+		fallbackConfig.Settings = fallback.Settings
+		fallbackConfig.Fallbacks = nil
+		client, err := factory(&fallbackConfig)
+		if err != nil {
+			return nil, fmt.Errorf("fallback %d (%s): %w", i+1, fallback.Provider, err)
+		}
+		c.clients = append(c.clients, client)
+		c.models = append(c.models, fallback.Model)
+	}
+	c.failed = make([]time.Time, len(c.clients))
+	return c, nil
+}
+
+// Chat asks the providers in order until one answers. Providers that failed
+// within the cooldown are tried last, and a provider whose configured model
+// is request.Model first. request.Model is only passed to the first provider
+// tried; the others use their configured model.
+func (c *failoverClient) Chat(ctx context.Context, request ChatRequest) (*ChatResponse, error) {
+	var response *ChatResponse
+	var errs []string
+	for attempt, i := range c.order(request.Model) {
+		if attempt > 0 {
+			request.Model = ""
+		}
+		var err error
+		response, err = c.clients[i].Chat(ctx, request)
+		if err == nil {
+			c.mu.Lock()
+			c.failed[i] = time.Time{}
+			c.mu.Unlock()
+			return response, nil
+		}
+		if ctx.Err() != nil {
+			return response, err
+		}
+		c.mu.Lock()
+		c.failed[i] = time.Now()
+		c.mu.Unlock()
+		errs = append(errs, fmt.Sprintf("%s: %v", c.clients[i].GetProvider(), err))
+	}
+
+	err := fmt.Errorf("all AI providers failed: %s", strings.Join(errs, "; "))
+	if response == nil {
+		response = &ChatResponse{Provider: c.GetProvider(), Model: c.GetModel()}
+	}
+	response.Error = err.Error()
+	return response, err
+}
+
+// order returns the indexes of the clients in the order they are tried
+func (c *failoverClient) order(model string) []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var ready, cooling []int
+	for i := range c.clients {
+		if !c.failed[i].IsZero() && time.Since(c.failed[i]) < failoverCooldown {
+			cooling = append(cooling, i)
+		} else {
+			ready = append(ready, i)
+		}
+	}
+	order := append(ready, cooling...)
+	if model == "" {
+		return order
+	}
+	for position, i := range order {
+		if c.models[i] == model {
+			return append(append([]int{i}, order[:position]...), order[position+1:]...)
+		}
+	}
+	return order
+}
+
+// GetProvider returns the provider of the primary client
+func (c *failoverClient) GetProvider() string {
+	return c.clients[0].GetProvider()
+}
+
+// GetModel returns the model of the primary client
+func (c *failoverClient) GetModel() string {
+	return c.clients[0].GetModel()
+}
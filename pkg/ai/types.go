@@ -6,35 +6,64 @@ import (
 )
 
 type AIConfig struct {
-    Provider string                 `json:"provider"`     // LLM provider (openai, gemini, claude, etc.)
-    APIKey   string                 `json:"apiKey"`
-    Enabled  bool                   `json:"enabled"`
-    Features AIFeatures             `json:"features"`
+    Provider  string                 `json:"provider"`     // LLM provider (openai, gemini, claude, etc.)
+    APIKey    string                 `json:"apiKey"`
+    Enabled   bool                   `json:"enabled"`
+    Features  AIFeatures             `json:"features"`
+    Settings  map[string]interface{} `json:"settings"`
+    Fallbacks []AIFallback           `json:"fallbacks,omitempty"` // tried in order when the provider fails or is rate limited
+}
+
+// AIFallback is a provider chat fails over to
+type AIFallback struct {
+    Provider string                 `json:"provider"`
+    APIKey   string                 `json:"apiKey"` // empty uses the provider's environment variable, e.g. GEMINI_API_KEY
+    Model    string                 `json:"model"`
     Settings map[string]interface{} `json:"settings"`
 }
 
 type AIFeatures struct {
-    ChatEnabled          bool    `json:"chatEnabled"`
-    DocGenerationEnabled bool    `json:"docGenerationEnabled"`
-    Model                string  `json:"model"`
-    MaxTokens            int     `json:"maxTokens"`
-    MaxCompletionTokens  int     `json:"maxCompletionTokens"`
-    Temperature          float64 `json:"temperature"` 
+    ChatEnabled          bool     `json:"chatEnabled"`
+    DocGenerationEnabled bool     `json:"docGenerationEnabled"`
+    Model                string   `json:"model"`
+    MaxTokens            int      `json:"maxTokens"`
+    MaxCompletionTokens  int      `json:"maxCompletionTokens"`
+    Temperature          float64  `json:"temperature"` 
+    ContextBudget        int      `json:"contextBudget"`        // approximate tokens of the API context; larger specs are trimmed to the endpoints relevant to the question; 0 sends the whole spec
+    ContextEndpoints     int      `json:"contextEndpoints"`     // endpoints detailed in a trimmed context; 0 uses 10
+    AllowedModels        []string `json:"allowedModels"`        // models a chat request may ask for besides the configured ones
+    DailyTokens          int      `json:"dailyTokens"`          // tokens all chat requests may use per UTC day; 0 is unlimited
+    DailyTokensPerClient int      `json:"dailyTokensPerClient"` // tokens one user or IP may use per UTC day; 0 is unlimited
+    PromptTemplate       string   `json:"promptTemplate"`       // text/template of the whole system prompt, replacing the built-in instructions
+    PromptTemplateFile   string   `json:"promptTemplateFile"`   // file of PromptTemplate, read once
 }
 
 type ChatRequest struct {
-    Message     string                 `json:"message"`
-    Context     string                 `json:"context,omitempty"`
-    Endpoint    interface{}            `json:"endpoint,omitempty"`
-    Metadata    map[string]interface{} `json:"metadata,omitempty"`
+    Message      string                 `json:"message"`
+    Context      string                 `json:"context,omitempty"`
+    Model        string                 `json:"model,omitempty"` // overrides the configured model, see AIConfig.AllowsModel
+    SystemPrompt string                 `json:"-"`               // set from AIFeatures.PromptTemplate; replaces the built-in instructions and Context
+    Endpoint     interface{}            `json:"endpoint,omitempty"`
+    Metadata     map[string]interface{} `json:"metadata,omitempty"`
 }
 
 type ChatResponse struct {
-    Response    string `json:"response"`
-    Provider    string `json:"provider"`
-    Model       string `json:"model,omitempty"`
-    TokensUsed  int    `json:"tokensUsed,omitempty"`
-    Error       string `json:"error,omitempty"`
+    Response         string       `json:"response"`
+    Provider         string       `json:"provider"`
+    Model            string       `json:"model,omitempty"`
+    TokensUsed       int          `json:"tokensUsed,omitempty"`
+    PromptTokens     int          `json:"promptTokens,omitempty"`
+    CompletionTokens int          `json:"completionTokens,omitempty"`
+    Error            string       `json:"error,omitempty"`
+    Sources          []ChatSource `json:"sources,omitempty"` // documented operations the answer refers to
+}
+
+// ChatSource is a documented operation an answer refers to
+type ChatSource struct {
+    OperationID string `json:"operationId"`
+    Method      string `json:"method"`
+    Path        string `json:"path"`
+    Summary     string `json:"summary,omitempty"`
 }
 
 type Client interface {
@@ -61,5 +90,32 @@ func NewClient(config *AIConfig) (Client, error) {
         return nil, fmt.Errorf("unsupported LLM provider: %s", config.Provider)
     }
 
-    return factory(config)
+    client, err := factory(config)
+    if err != nil || len(config.Fallbacks) == 0 {
+        return client, err
+    }
+    return newFailoverClient(config, client)
+}
+
+// AllowsModel reports whether a chat request may ask for model: the
+// configured models of the provider and its fallbacks and
+// Features.AllowedModels are allowed
+func (c *AIConfig) AllowsModel(model string) bool {
+    if c == nil {
+        return false
+    }
+    if model == c.Features.Model {
+        return true
+    }
+    for _, fallback := range c.Fallbacks {
+        if model == fallback.Model {
+            return true
+        }
+    }
+    for _, allowed := range c.Features.AllowedModels {
+        if model == allowed {
+            return true
+        }
+    }
+    return false
 }
\ No newline at end of file
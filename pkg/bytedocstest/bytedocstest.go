@@ -0,0 +1,25 @@
+// Package bytedocstest provides test helpers that lock the documented API
+// contract in the test suite of an application using ByteDocs.
+package bytedocstest
+
+import (
+	"testing"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// AssertSpecMatchesGolden regenerates the OpenAPI spec of docs and fails t
+// with a line diff when it differs from the golden file at path, e.g.
+// "testdata/openapi.json", so contract changes fail CI until the golden file
+// is updated and reviewed.
+//
+// Run the tests with -update (define it with flag.Bool("update", false, ...) in
+// your test package) or BYTEDOCS_UPDATE_GOLDEN=1 to write the golden file.
+func AssertSpecMatchesGolden(t testing.TB, docs *core.APIDocs, path string) {
+	t.Helper()
+	if docs == nil {
+		t.Fatalf("bytedocs: no docs to compare with %s", path)
+		return
+	}
+	docs.AssertMatchesGolden(t, path)
+}
@@ -0,0 +1,26 @@
+package bytedocstest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+func TestAssertSpecMatchesGoldenWritesAndComparesTheSpec(t *testing.T) {
+	golden := filepath.Join(t.TempDir(), "testdata", "openapi.json")
+	docs := core.New(&core.Config{Title: "Contract", Version: "1.0.0", DocsPath: "/docs"})
+	docs.AddRoute("GET", "/users/:id", nil)
+
+	t.Setenv("BYTEDOCS_UPDATE_GOLDEN", "1")
+	AssertSpecMatchesGolden(t, docs, golden)
+	written, err := os.ReadFile(golden)
+	if err != nil || !strings.Contains(string(written), `"/users/{id}"`) {
+		t.Fatalf("expected the golden file to be written, got %v %s", err, written)
+	}
+
+	t.Setenv("BYTEDOCS_UPDATE_GOLDEN", "")
+	AssertSpecMatchesGolden(t, docs, golden)
+}
@@ -0,0 +1,205 @@
+package bytedocstest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// Server is an httptest.Server running an application's router with its
+// documentation mounted under the docs path
+type Server struct {
+	*httptest.Server
+	Docs *core.APIDocs
+}
+
+// NewServer starts router on an httptest.Server and serves every request
+// under the docs path with the full docs handler, including auth, assets and
+// the spec endpoints, whether or not router mounts the docs itself. router
+// may be nil to serve the docs alone. The server is closed when the test ends.
+func NewServer(t testing.TB, router http.Handler, docs *core.APIDocs) *Server {
+	t.Helper()
+	if docs == nil {
+		t.Fatalf("bytedocs: NewServer needs docs")
+		return nil
+	}
+	if router == nil {
+		router = http.NotFoundHandler()
+	}
+
+	docsPath := strings.TrimSuffix(docs.GetConfig().DocsPath, "/")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == docsPath || strings.HasPrefix(r.URL.Path, docsPath+"/") {
+			docs.ServeHTTP(w, r)
+			return
+		}
+		router.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return &Server{Server: server, Docs: docs}
+}
+
+// DocsURL returns the URL of the docs root, e.g. "http://127.0.0.1:53127/docs"
+func (s *Server) DocsURL() string {
+	return s.URL + strings.TrimSuffix(s.Docs.GetConfig().DocsPath, "/")
+}
+
+// DocsClient returns a client for the docs endpoints of the server
+func (s *Server) DocsClient() *DocsClient {
+	return &DocsClient{BaseURL: s.DocsURL(), HTTP: s.Client()}
+}
+
+// OpenAPI fetches /openapi.json, failing t on errors
+func (s *Server) OpenAPI(t testing.TB) *OpenAPISpec {
+	t.Helper()
+	spec, err := s.DocsClient().OpenAPI(context.Background())
+	if err != nil {
+		t.Fatalf("bytedocs: %v", err)
+	}
+	return spec
+}
+
+// APIData fetches /api-data.json with the filter query, e.g. tag=Users,
+// failing t on errors
+func (s *Server) APIData(t testing.TB, query url.Values) *core.Documentation {
+	t.Helper()
+	documentation, err := s.DocsClient().APIData(context.Background(), query)
+	if err != nil {
+		t.Fatalf("bytedocs: %v", err)
+	}
+	return documentation
+}
+
+// DocsClient reads the machine-readable docs endpoints of a running server
+type DocsClient struct {
+	BaseURL string       // docs root, e.g. "https://api.example.com/docs"
+	Header  http.Header  // sent with every request, e.g. docs credentials
+	HTTP    *http.Client // nil uses http.DefaultClient
+}
+
+// OpenAPI fetches and decodes /openapi.json
+func (c *DocsClient) OpenAPI(ctx context.Context) (*OpenAPISpec, error) {
+	var spec OpenAPISpec
+	if err := c.getJSON(ctx, "/openapi.json", nil, &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// APIData fetches and decodes /api-data.json; query filters and sorts the
+// endpoints, e.g. url.Values{"method": {"GET"}, "sort": {"path"}}
+func (c *DocsClient) APIData(ctx context.Context, query url.Values) (*core.Documentation, error) {
+	var documentation core.Documentation
+	if err := c.getJSON(ctx, "/api-data.json", query, &documentation); err != nil {
+		return nil, err
+	}
+	return &documentation, nil
+}
+
+// getJSON fetches a docs endpoint and decodes its JSON body into target
+func (c *DocsClient) getJSON(ctx context.Context, path string, query url.Values, target interface{}) error {
+	endpoint := strings.TrimSuffix(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", endpoint, err)
+	}
+	for name, values := range c.Header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", endpoint, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("GET %s: invalid JSON: %w", endpoint, err)
+	}
+	return nil
+}
+
+// OpenAPISpec is the OpenAPI document served by ByteDocs
+type OpenAPISpec struct {
+	OpenAPI string `json:"openapi"`
+	Info    struct {
+		Title       string `json:"title"`
+		Version     string `json:"version"`
+		Description string `json:"description"`
+	} `json:"info"`
+	Servers []struct {
+		URL         string `json:"url"`
+		Description string `json:"description,omitempty"`
+	} `json:"servers"`
+	Tags []struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+	} `json:"tags,omitempty"`
+	Paths      map[string]map[string]Operation `json:"paths"` // operations by path and lowercase method
+	Components map[string]json.RawMessage      `json:"components,omitempty"`
+}
+
+// Operation returns the operation of method and path, e.g. ("GET", "/users/{id}")
+func (s *OpenAPISpec) Operation(method, path string) (Operation, bool) {
+	operation, ok := s.Paths[path][strings.ToLower(method)]
+	return operation, ok
+}
+
+// Operation is an OpenAPI operation
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Description string              `json:"description"`
+	Tags        []string            `json:"tags"`
+	Deprecated  bool                `json:"deprecated,omitempty"`
+	Parameters  []Parameter         `json:"parameters"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"` // by status code
+}
+
+// Parameter is an operation parameter; shared parameters only set Ref
+type Parameter struct {
+	Ref         string      `json:"$ref,omitempty"`
+	Name        string      `json:"name"`
+	In          string      `json:"in"`
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Schema      interface{} `json:"schema,omitempty"`
+}
+
+// RequestBody is the request body of an operation
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"` // by content type
+}
+
+// Response is a documented response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"` // by content type
+}
+
+// MediaType is the schema and example of one content type
+type MediaType struct {
+	Schema  interface{} `json:"schema,omitempty"`
+	Example interface{} `json:"example,omitempty"`
+}
@@ -0,0 +1,57 @@
+package bytedocstest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+func TestNewServerServesRouterAndTypedDocs(t *testing.T) {
+	router := http.NewServeMux()
+	router.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, "pong") })
+
+	docs := core.New(&core.Config{
+		Title:      "Integration",
+		Version:    "1.0.0",
+		DocsPath:   "/docs",
+		AuthConfig: &core.AuthConfig{Enabled: true, Type: "api_key", APIKey: "docs-api-key", APIKeyHeader: "X-Docs-Key"},
+	})
+	docs.AddRoute("GET", "/users/:id", nil, core.WithParam("expand", "query", "string", false, "Expand relations"))
+	docs.AddRoute("POST", "/users", nil)
+
+	server := NewServer(t, router, docs)
+	resp, err := server.Client().Get(server.URL + "/ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "pong" {
+		t.Fatalf("expected the router to serve its routes, got %q", body)
+	}
+
+	spec := server.OpenAPI(t)
+	operation, ok := spec.Operation("GET", "/users/{id}")
+	if spec.Info.Title != "Integration" || !ok || len(operation.Parameters) == 0 || operation.Parameters[len(operation.Parameters)-1].Name != "expand" {
+		t.Fatalf("expected the typed spec with the user operation, got %+v", spec)
+	}
+
+	// api-data.json is behind the docs login
+	client := server.DocsClient()
+	if _, err := client.APIData(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("expected the docs login to be required, got %v", err)
+	}
+	client.Header = http.Header{"X-Docs-Key": {"docs-api-key"}}
+	documentation, err := client.APIData(context.Background(), url.Values{"method": {"POST"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(documentation.Endpoints) != 1 || len(documentation.Endpoints[0].Endpoints) != 1 || documentation.Endpoints[0].Endpoints[0].Method != "POST" {
+		t.Fatalf("expected the filtered documentation, got %+v", documentation.Endpoints)
+	}
+}
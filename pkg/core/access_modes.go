@@ -0,0 +1,204 @@
+package core
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Comment markers flagging a field as read-only or write-only
+const (
+	readOnlyMarker  = "@readonly"
+	writeOnlyMarker = "@writeonly"
+)
+
+// FieldAccessMode reports whether a field is read-only or write-only, from its
+// readonly:"true" and writeonly:"true" struct tags or an @readonly/@writeonly
+// marker in its comment. tag looks up a struct tag of the field; the comment
+// is returned without the markers, for use as the field description.
+func FieldAccessMode(tag func(key string) string, comment string) (description string, readOnly, writeOnly bool) {
+	readOnly = tagEnabled(tag("readonly"))
+	writeOnly = tagEnabled(tag("writeonly"))
+
+	words := strings.Fields(comment)
+	kept := make([]string, 0, len(words))
+	for _, word := range words {
+		switch strings.ToLower(word) {
+		case readOnlyMarker:
+			readOnly = true
+		case writeOnlyMarker:
+			writeOnly = true
+		default:
+			kept = append(kept, word)
+		}
+	}
+	if len(kept) == len(words) {
+		return comment, readOnly, writeOnly
+	}
+	return strings.Join(kept, " "), readOnly, writeOnly
+}
+
+func tagEnabled(value string) bool {
+	enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+	return err == nil && enabled
+}
+
+// SetAccessMode marks a property schema readOnly or writeOnly
+func SetAccessMode(schema map[string]interface{}, readOnly, writeOnly bool) {
+	if schema == nil {
+		return
+	}
+	if readOnly {
+		schema["readOnly"] = true
+	}
+	if writeOnly {
+		schema["writeOnly"] = true
+	}
+}
+
+// applyAccessModes drops readOnly properties, such as ids and timestamps, from
+// the request body and writeOnly properties, such as passwords, from the
+// responses, together with their examples and required entries
+func applyAccessModes(endpoint *Endpoint) {
+	if endpoint.RequestBody != nil && hasAccessMode(endpoint.RequestBody.Schema, "readOnly") {
+		body := *endpoint.RequestBody
+		body.Schema, body.Example = withoutAccessMode(body.Schema, body.Example, "readOnly")
+		if len(body.Alternatives) > 0 {
+			body.Alternatives = append([]BodyContent{}, body.Alternatives...)
+			for i, alternative := range body.Alternatives {
+				body.Alternatives[i].Schema, body.Alternatives[i].Example = withoutAccessMode(alternative.Schema, alternative.Example, "readOnly")
+			}
+		}
+		endpoint.RequestBody = &body
+	}
+
+	var responses map[string]Response
+	for status, response := range endpoint.Responses {
+		if !hasAccessMode(response.Schema, "writeOnly") {
+			continue
+		}
+		if responses == nil {
+			responses = make(map[string]Response, len(endpoint.Responses))
+			for code, existing := range endpoint.Responses {
+				responses[code] = existing
+			}
+		}
+		response.Schema, response.Example = withoutAccessMode(response.Schema, response.Example, "writeOnly")
+		responses[status] = response
+	}
+	if responses != nil {
+		endpoint.Responses = responses
+	}
+}
+
+// hasAccessMode reports whether schema holds a property flagged with mode
+func hasAccessMode(schema interface{}, mode string) bool {
+	schemaMap, ok := schema.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if flagged, _ := schemaMap[mode].(bool); flagged {
+		return true
+	}
+	if properties, ok := schemaMap["properties"].(map[string]interface{}); ok {
+		for _, property := range properties {
+			if hasAccessMode(property, mode) {
+				return true
+			}
+		}
+	}
+	return hasAccessMode(schemaMap["items"], mode)
+}
+
+// withoutAccessMode returns copies of schema and example without the
+// properties flagged with mode, leaving the originals untouched
+func withoutAccessMode(schema, example interface{}, mode string) (interface{}, interface{}) {
+	schemaMap, ok := schema.(map[string]interface{})
+	if !ok || !hasAccessMode(schemaMap, mode) {
+		return schema, example
+	}
+
+	stripped := make(map[string]interface{}, len(schemaMap))
+	for key, value := range schemaMap {
+		stripped[key] = value
+	}
+
+	if items, ok := schemaMap["items"]; ok {
+		stripped["items"], _ = withoutAccessMode(items, nil, mode)
+		list, ok := example.([]interface{})
+		if !ok {
+			return stripped, example
+		}
+		strippedItems := make([]interface{}, len(list))
+		for i, item := range list {
+			_, strippedItems[i] = withoutAccessMode(items, item, mode)
+		}
+		return stripped, strippedItems
+	}
+
+	properties, _ := schemaMap["properties"].(map[string]interface{})
+	exampleMap, _ := example.(map[string]interface{})
+	keptProperties := make(map[string]interface{}, len(properties))
+	var keptExample map[string]interface{}
+	if exampleMap != nil {
+		keptExample = make(map[string]interface{}, len(exampleMap))
+		for key, value := range exampleMap {
+			if _, known := properties[key]; !known {
+				keptExample[key] = value
+			}
+		}
+	}
+	for name, property := range properties {
+		if propertyMap, ok := property.(map[string]interface{}); ok {
+			if flagged, _ := propertyMap[mode].(bool); flagged {
+				continue
+			}
+		}
+		var propertyExample interface{}
+		if exampleMap != nil {
+			propertyExample = exampleMap[name]
+		}
+		keptProperties[name], propertyExample = withoutAccessMode(property, propertyExample, mode)
+		if exampleMap != nil {
+			if _, present := exampleMap[name]; present {
+				keptExample[name] = propertyExample
+			}
+		}
+	}
+	stripped["properties"] = keptProperties
+
+	if required := requiredNames(schemaMap["required"]); required != nil {
+		kept := make([]string, 0, len(required))
+		for _, name := range required {
+			if _, ok := keptProperties[name]; ok {
+				kept = append(kept, name)
+			}
+		}
+		if len(kept) > 0 {
+			stripped["required"] = kept
+		} else {
+			delete(stripped, "required")
+		}
+	}
+
+	if keptExample == nil {
+		return stripped, example
+	}
+	return stripped, keptExample
+}
+
+// requiredNames reads a required list built by the analyzers or decoded from JSON
+func requiredNames(value interface{}) []string {
+	switch list := value.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		names := make([]string, 0, len(list))
+		for _, item := range list {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAccessModes_ReadOnlyAndWriteOnlyFields(t *testing.T) {
+	type user struct {
+		ID        string `json:"id" binding:"required" readonly:"true"`
+		Email     string `json:"email" binding:"required"`
+		Password  string `json:"password" binding:"required" description:"@writeonly Login secret"`
+		CreatedAt string `json:"created_at" description:"Creation time @readonly"`
+	}
+	schema, example := SchemaFromValue(user{})
+	properties := schema.(map[string]interface{})["properties"].(map[string]interface{})
+	if properties["id"].(map[string]interface{})["readOnly"] != true || properties["password"].(map[string]interface{})["writeOnly"] != true {
+		t.Fatalf("expected tags and comment markers to set readOnly and writeOnly, got %+v", properties)
+	}
+	if description := properties["created_at"].(map[string]interface{})["description"]; description != "Creation time" {
+		t.Fatalf("expected the marker to be dropped from the description, got %q", description)
+	}
+
+	docs := New(&Config{DocsPath: "/docs"})
+	docs.AddRouteInfo(RouteInfo{
+		Method:      "POST",
+		Path:        "/users",
+		RequestBody: &RequestBody{ContentType: "application/json", Schema: schema, Example: example},
+		Responses:   map[string]Response{"201": {Description: "Created", Schema: schema, Example: example}},
+	})
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	endpoint := docs.GetDocumentation().Endpoints[0].Endpoints[0]
+
+	body := endpoint.RequestBody.Schema.(map[string]interface{})
+	bodyProperties := body["properties"].(map[string]interface{})
+	bodyExample := endpoint.RequestBody.Example.(map[string]interface{})
+	if _, ok := bodyProperties["id"]; ok || bodyExample["created_at"] != nil {
+		t.Fatalf("expected readOnly fields to be left out of the request body, got %+v / %+v", bodyProperties, bodyExample)
+	}
+	if fmt.Sprint(body["required"]) != "[email password]" {
+		t.Fatalf("expected readOnly fields not to be required in the request body, got %v", body["required"])
+	}
+
+	response := endpoint.Responses["201"]
+	responseExample := response.Example.(map[string]interface{})
+	if _, ok := response.Schema.(map[string]interface{})["properties"].(map[string]interface{})["password"]; ok || responseExample["password"] != nil {
+		t.Fatalf("expected writeOnly fields to be left out of the response, got %+v", response)
+	}
+	if _, ok := responseExample["id"]; !ok {
+		t.Fatalf("expected readOnly fields in the response, got %+v", responseExample)
+	}
+	if _, ok := properties["password"]; !ok {
+		t.Fatal("expected the registered schema to be left untouched")
+	}
+}
@@ -0,0 +1,446 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const defaultContextEndpoints = 10
+
+// contextStopWords are left out of the keyword index of chat questions
+var contextStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "api": true, "are": true, "can": true, "do": true, "does": true,
+	"endpoint": true, "for": true, "from": true, "how": true, "i": true, "in": true, "is": true, "it": true,
+	"me": true, "my": true, "of": true, "on": true, "or": true, "the": true, "to": true, "what": true,
+	"when": true, "which": true, "with": true,
+}
+
+// contextOperation is an operation of the spec in the keyword index
+type contextOperation struct {
+	method    string
+	path      string
+	operation map[string]interface{}
+	summary   string
+	terms     map[string]float64 // weighted term frequencies
+	score     float64
+}
+
+// GetAPIContextFor returns the API description sent with a chat question,
+// rendered from AIFeatures.PromptTemplate when one is configured.
+// When AIFeatures.ContextBudget is set and the whole spec exceeds it, only
+// the operations most relevant to question, ranked by a keyword index of
+// their paths, operationIds, summaries, tags, parameters and schema
+// properties, are included with the schemas they reference; the remaining
+// endpoints are listed by method, path and summary while the budget allows.
+// An empty question returns the whole spec.
+func (a *APIDocs) GetAPIContextFor(question string) (string, error) {
+	data, err := a.promptData(question)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := a.promptTemplate()
+	if err != nil {
+		return "", err
+	}
+	var context string
+	if tmpl != nil {
+		var rendered strings.Builder
+		if err := tmpl.Execute(&rendered, data); err != nil {
+			return "", fmt.Errorf("failed to render AI prompt template: %w", err)
+		}
+		context = rendered.String()
+	} else {
+		context = a.defaultAPIContext(data)
+	}
+
+	// Examples are redacted on generation; this also catches descriptions
+	docsRedactor, _ := a.redactors()
+	return docsRedactor.text(context), nil
+}
+
+// promptData collects the API description for question
+func (a *APIDocs) promptData(question string) (*PromptData, error) {
+	openAPIJSON, err := a.GetOpenAPIJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.MarshalIndent(openAPIJSON, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	documentation := a.GetDocumentation()
+	data := &PromptData{
+		Title:       documentation.Info.Title,
+		Version:     documentation.Info.Version,
+		Description: documentation.Info.Description,
+		Question:    question,
+	}
+	if a.config.BaseURL != "" {
+		data.BaseURLs = append(data.BaseURLs, a.config.BaseURL)
+	}
+	for _, option := range a.config.BaseURLs {
+		data.BaseURLs = append(data.BaseURLs, option.URL)
+	}
+
+	budget, endpoints := a.contextBudget()
+	if strings.TrimSpace(question) != "" && budget > 0 && estimateTokens(len(jsonBytes)) > budget {
+		spec, err := normalizeSpec(openAPIJSON)
+		if err != nil {
+			return nil, err
+		}
+		var trimmed map[string]interface{}
+		trimmed, data.OtherEndpoints = trimSpecForQuestion(spec, question, endpoints, budget)
+		if jsonBytes, err = json.MarshalIndent(trimmed, "", "  "); err != nil {
+			return nil, err
+		}
+		data.Trimmed = true
+	}
+	data.Spec = string(jsonBytes)
+	return data, nil
+}
+
+// defaultAPIContext is the API context without a prompt template; the
+// provider adds its built-in instructions in front of it
+func (a *APIDocs) defaultAPIContext(data *PromptData) string {
+	heading := "COMPLETE OPENAPI JSON SPECIFICATION"
+	if data.Trimmed {
+		heading = "OPENAPI JSON OF THE ENDPOINTS RELEVANT TO THE QUESTION"
+	}
+
+	context := fmt.Sprintf(`
+=== API SPECIFICATION FOR YOUR REFERENCE ===
+
+API Title: %s
+Version: %s
+Description: %s
+Base URLs: %v
+
+=== %s ===
+%s
+`,
+		data.Title,
+		data.Version,
+		data.Description,
+		a.config.BaseURLs,
+		heading,
+		data.Spec)
+
+	if len(data.OtherEndpoints) > 0 {
+		context += "\n=== OTHER ENDPOINTS (not detailed above) ===\n" + strings.Join(data.OtherEndpoints, "\n") + "\n"
+	}
+
+	context += `
+=== STRICT INSTRUCTIONS ===
+- ONLY answer programming or API-related questions about the OpenAPI JSON specification above.
+- DO NOT answer questions outside the context of this API or its OpenAPI spec.
+- DO NOT provide information unrelated to the API, its endpoints, or usage.
+- ONLY use the provided OpenAPI JSON as your source of truth.
+- Give code examples, endpoint usage, and parameter details strictly based on the OpenAPI spec.
+- Be precise about required/optional parameters and show real request/response JSON from the spec.
+- DO NOT speculate or invent endpoints, parameters, or behaviors not present in the OpenAPI JSON.
+- Write the method and path of every endpoint you refer to, e.g. GET /users/{id}, so it can be linked.
+`
+	if len(data.OtherEndpoints) > 0 {
+		context += "- Endpoints under OTHER ENDPOINTS exist but are not detailed; say so instead of guessing their parameters or responses.\n"
+	}
+	return context
+}
+
+// contextBudget returns AIFeatures.ContextBudget and ContextEndpoints with
+// its default
+func (a *APIDocs) contextBudget() (int, int) {
+	if a.config.AIConfig == nil {
+		return 0, 0
+	}
+	features := a.config.AIConfig.Features
+	endpoints := features.ContextEndpoints
+	if endpoints <= 0 {
+		endpoints = defaultContextEndpoints
+	}
+	return features.ContextBudget, endpoints
+}
+
+// estimateTokens approximates the tokens of n bytes of JSON or English text
+func estimateTokens(n int) int {
+	return (n + 3) / 4
+}
+
+// trimSpecForQuestion returns a copy of a normalized spec with the operations
+// most relevant to question, at most maxOperations and within budget tokens
+// but at least one, and the components they reference. The other operations
+// are returned as "METHOD path - summary" lines, as many as fit the budget.
+func trimSpecForQuestion(spec map[string]interface{}, question string, maxOperations, budget int) (map[string]interface{}, []string) {
+	operations := indexOperations(spec)
+	rankOperations(operations, contextTerms(question))
+
+	trimmed := make(map[string]interface{}, len(spec))
+	for key, value := range spec {
+		if key != "paths" && key != "components" {
+			trimmed[key] = value
+		}
+	}
+	components, _ := spec["components"].(map[string]interface{})
+	trimmedComponents := make(map[string]interface{})
+	for key, value := range components {
+		if key != "schemas" && key != "parameters" {
+			trimmedComponents[key] = value
+		}
+	}
+	trimmed["components"] = trimmedComponents
+	paths := make(map[string]interface{})
+	trimmed["paths"] = paths
+
+	size, _ := json.Marshal(trimmed)
+	used := estimateTokens(len(size))
+	var rest []*contextOperation
+	detailed := 0
+	for _, operation := range operations {
+		if detailed < maxOperations {
+			added := referencedComponents(operation.operation, components, trimmedComponents)
+			encoded, _ := json.Marshal(operation.operation)
+			cost := estimateTokens(len(encoded))
+			for _, component := range added {
+				encodedComponent, _ := json.Marshal(component)
+				cost += estimateTokens(len(encodedComponent))
+			}
+			if detailed == 0 || used+cost <= budget {
+				pathItem, _ := paths[operation.path].(map[string]interface{})
+				if pathItem == nil {
+					pathItem = make(map[string]interface{})
+					paths[operation.path] = pathItem
+				}
+				pathItem[operation.method] = operation.operation
+				used += cost
+				detailed++
+				continue
+			}
+			removeComponents(trimmedComponents, added)
+		}
+		rest = append(rest, operation)
+	}
+
+	lines := make([]string, 0, len(rest))
+	for i, operation := range rest {
+		line := strings.ToUpper(operation.method) + " " + operation.path
+		if operation.summary != "" {
+			line += " - " + operation.summary
+		}
+		if used+estimateTokens(len(line)+1) > budget {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(rest)-i))
+			break
+		}
+		used += estimateTokens(len(line) + 1)
+		lines = append(lines, line)
+	}
+	return trimmed, lines
+}
+
+// indexOperations lists the operations of a normalized spec with their terms,
+// ordered by path and method
+func indexOperations(spec map[string]interface{}) []*contextOperation {
+	paths, _ := spec["paths"].(map[string]interface{})
+	components, _ := spec["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+
+	operations := make([]*contextOperation, 0)
+	for path, value := range paths {
+		pathItem, _ := value.(map[string]interface{})
+		for _, method := range lintMethods {
+			operation, ok := pathItem[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			indexed := &contextOperation{method: method, path: path, operation: operation, terms: make(map[string]float64)}
+			indexed.summary, _ = operation["summary"].(string)
+			addTerms := func(text string, weight float64) {
+				for _, term := range contextTerms(text) {
+					indexed.terms[term] += weight
+				}
+			}
+			addTerms(method+" "+path, 3)
+			if id, ok := operation["operationId"].(string); ok {
+				addTerms(id, 3)
+			}
+			addTerms(indexed.summary, 2)
+			if description, ok := operation["description"].(string); ok {
+				addTerms(description, 1)
+			}
+			if tags, ok := operation["tags"].([]interface{}); ok {
+				for _, tag := range tags {
+					if text, ok := tag.(string); ok {
+						addTerms(text, 2)
+					}
+				}
+			}
+			if parameters, ok := operation["parameters"].([]interface{}); ok {
+				for _, parameter := range parameters {
+					if parameterMap, ok := parameter.(map[string]interface{}); ok {
+						if name, ok := parameterMap["name"].(string); ok {
+							addTerms(name, 2)
+						}
+					}
+				}
+			}
+			for _, name := range schemaPropertyNames(operation, schemas, make(map[string]bool)) {
+				addTerms(name, 1)
+			}
+			operations = append(operations, indexed)
+		}
+	}
+	sort.SliceStable(operations, func(i, j int) bool {
+		if operations[i].path != operations[j].path {
+			return operations[i].path < operations[j].path
+		}
+		return operations[i].method < operations[j].method
+	})
+	return operations
+}
+
+// schemaPropertyNames collects the property and schema names of a value,
+// following $refs to component schemas once
+func schemaPropertyNames(value interface{}, schemas map[string]interface{}, visited map[string]bool) []string {
+	var names []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, "#/components/schemas/") {
+			name := strings.TrimPrefix(ref, "#/components/schemas/")
+			if !visited[name] {
+				visited[name] = true
+				names = append(names, name)
+				names = append(names, schemaPropertyNames(schemas[name], schemas, visited)...)
+			}
+		}
+		if properties, ok := v["properties"].(map[string]interface{}); ok {
+			for name := range properties {
+				names = append(names, name)
+			}
+		}
+		for key, item := range v {
+			if key != "example" && key != "examples" {
+				names = append(names, schemaPropertyNames(item, schemas, visited)...)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			names = append(names, schemaPropertyNames(item, schemas, visited)...)
+		}
+	}
+	return names
+}
+
+// rankOperations orders operations by TF-IDF relevance to the question terms,
+// keeping the path order among equally relevant ones
+func rankOperations(operations []*contextOperation, terms []string) {
+	documentFrequency := make(map[string]int)
+	for _, operation := range operations {
+		for term := range operation.terms {
+			documentFrequency[term]++
+		}
+	}
+	for _, operation := range operations {
+		operation.score = 0
+		for _, term := range terms {
+			if weight := operation.terms[term]; weight > 0 {
+				idf := math.Log(1 + float64(len(operations))/float64(documentFrequency[term]))
+				operation.score += (1 + math.Log(weight)) * idf
+			}
+		}
+	}
+	sort.SliceStable(operations, func(i, j int) bool { return operations[i].score > operations[j].score })
+}
+
+// contextTerms splits text into lowercase index terms: camelCase, kebab-case
+// and path segments are split, stop words dropped and plurals reduced
+func contextTerms(text string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = current[:0]
+		}
+	}
+	runes := []rune(text)
+	for i, r := range runes {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]) {
+				flush()
+			}
+			current = append(current, unicode.ToLower(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if len(word) < 2 || contextStopWords[word] {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(word, "ies") && len(word) > 4:
+			word = strings.TrimSuffix(word, "ies") + "y"
+		case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && len(word) > 3:
+			word = strings.TrimSuffix(word, "s")
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// referencedComponents copies the schemas and parameters an operation
+// references, transitively, from components into trimmed and returns the
+// newly added ones by "schemas/Name" or "parameters/Name"
+func referencedComponents(value interface{}, components, trimmed map[string]interface{}) map[string]interface{} {
+	added := make(map[string]interface{})
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, "#/components/") {
+				parts := strings.SplitN(strings.TrimPrefix(ref, "#/components/"), "/", 2)
+				if len(parts) == 2 {
+					group, _ := components[parts[0]].(map[string]interface{})
+					target, exists := group[parts[1]]
+					trimmedGroup, _ := trimmed[parts[0]].(map[string]interface{})
+					if trimmedGroup == nil {
+						trimmedGroup = make(map[string]interface{})
+						trimmed[parts[0]] = trimmedGroup
+					}
+					if _, included := trimmedGroup[parts[1]]; exists && !included {
+						trimmedGroup[parts[1]] = target
+						added[parts[0]+"/"+parts[1]] = target
+						walk(target)
+					}
+				}
+			}
+			for _, item := range v {
+				walk(item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(value)
+	return added
+}
+
+// removeComponents takes back components added for an operation that did not fit
+func removeComponents(trimmed map[string]interface{}, added map[string]interface{}) {
+	for key := range added {
+		parts := strings.SplitN(key, "/", 2)
+		if group, ok := trimmed[parts[0]].(map[string]interface{}); ok {
+			delete(group, parts[1])
+		}
+	}
+}
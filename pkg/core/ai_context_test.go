@@ -0,0 +1,55 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAIContext_TrimmedToRelevantEndpoints(t *testing.T) {
+	type invoice struct {
+		ID       int     `json:"id"`
+		Amount   float64 `json:"amount"`
+		Currency string  `json:"currency"`
+	}
+
+	docs := New(&Config{Title: "Shop", Version: "1.0.0", DocsPath: "/docs", AIConfig: &AIConfig{}})
+	for _, resource := range []string{"users", "orders", "products", "carts", "reviews", "coupons", "shipments", "warehouses"} {
+		docs.AddRoute("GET", "/"+resource, nil, WithSummary("List "+resource), WithDescription("Returns every "+resource+" of the shop, paginated and sorted."))
+		docs.AddRoute("POST", "/"+resource, nil, WithSummary("Create "+resource))
+		docs.AddRoute("DELETE", "/"+resource+"/{id}", nil, WithSummary("Delete "+resource))
+	}
+	docs.AddRoute("GET", "/invoices/{id}", nil, WithSummary("Get invoice"), WithResponse(200, invoice{}))
+	docs.AddRoute("POST", "/invoices/{id}/refunds", nil, WithSummary("Refund an invoice"), WithRequest(invoice{}))
+
+	whole, err := docs.GetAPIContextFor("How do I refund an invoice?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(whole, "COMPLETE OPENAPI JSON SPECIFICATION") || !strings.Contains(whole, "/warehouses") {
+		t.Fatal("expected the whole spec without a context budget")
+	}
+
+	docs.config.AIConfig.Features.ContextBudget = 900
+	docs.config.AIConfig.Features.ContextEndpoints = 2
+	context, err := docs.GetAPIContextFor("How do I refund an invoice?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimateTokens(len(context)) >= estimateTokens(len(whole)) {
+		t.Fatalf("expected a smaller context, got %d of %d bytes", len(context), len(whole))
+	}
+	relevant := context[strings.Index(context, "RELEVANT TO THE QUESTION"):strings.Index(context, "OTHER ENDPOINTS")]
+	if !strings.Contains(relevant, `"/invoices/{id}/refunds"`) || !strings.Contains(relevant, `"/invoices/{id}"`) || !strings.Contains(relevant, "currency") {
+		t.Fatalf("expected the invoice operations detailed, got %s", relevant)
+	}
+	if strings.Contains(relevant, "/warehouses") {
+		t.Fatalf("expected unrelated operations left out, got %s", relevant)
+	}
+	if !strings.Contains(context, "GET /warehouses - List warehouses") && !strings.Contains(context, "more") {
+		t.Fatalf("expected the other endpoints indexed, got %s", context)
+	}
+
+	if context, _ := docs.GetAPIContext(); !strings.Contains(context, "COMPLETE OPENAPI JSON SPECIFICATION") {
+		t.Fatal("expected GetAPIContext to send the whole spec")
+	}
+}
@@ -0,0 +1,226 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AIUsage counts chat requests and their tokens
+type AIUsage struct {
+	Requests         int `json:"requests"`
+	PromptTokens     int `json:"promptTokens"`
+	CompletionTokens int `json:"completionTokens"`
+	TotalTokens      int `json:"totalTokens"`
+}
+
+// ModelUsage is the usage of one provider and model
+type ModelUsage struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	AIUsage
+}
+
+// AIBudgetStatus is the daily token budget of a client
+type AIBudgetStatus struct {
+	Day        string `json:"day"`                  // UTC date the counts belong to
+	Used       int    `json:"used"`                 // tokens the client used today
+	Limit      int    `json:"limit,omitempty"`      // AIFeatures.DailyTokensPerClient
+	TotalLimit int    `json:"totalLimit,omitempty"` // AIFeatures.DailyTokens
+}
+
+// AIUsageReport is the token usage of AI chat since Since. Usage of single
+// clients is not listed, only the budget of the client asking.
+type AIUsageReport struct {
+	Since  time.Time       `json:"since"`
+	Total  AIUsage         `json:"total"`
+	Today  AIUsage         `json:"today"`
+	Models []ModelUsage    `json:"models"` // most tokens first
+	Budget *AIBudgetStatus `json:"budget,omitempty"`
+}
+
+// aiUsageStore holds the chat usage counters in memory
+type aiUsageStore struct {
+	mu      sync.Mutex
+	since   time.Time
+	total   AIUsage
+	models  map[string]*ModelUsage // by "provider model"
+	day     string
+	today   AIUsage
+	clients map[string]int // tokens used today by client key
+
+	reserved        int            // tokens held today by chat calls in flight
+	clientsReserved map[string]int // held tokens by client key
+}
+
+// aiReservation holds the tokens of a chat call in flight against the budgets
+type aiReservation struct {
+	client string
+	day    string
+	tokens int
+}
+
+// rollover starts a new day of the daily counters; callers hold mu
+func (s *aiUsageStore) rollover(now time.Time) {
+	if day := now.UTC().Format("2006-01-02"); day != s.day {
+		s.day = day
+		s.today = AIUsage{}
+		s.clients = make(map[string]int)
+		s.reserved = 0
+		s.clientsReserved = make(map[string]int)
+	}
+}
+
+// aiClientKey identifies who a chat request is counted against: the identity
+// the docs auth verified, else the client IP behind Config.TrustedProxies
+func (a *APIDocs) aiClientKey(r *http.Request) string {
+	if identity, ok := authIdentity(r); ok {
+		return identity
+	}
+	return "ip:" + clientIPBehind(r, a.config.TrustedProxies)
+}
+
+// reserveAIBudget returns a friendly error when client or all clients used up
+// today's token budget. Otherwise it holds the tokens the call may use, its
+// prompt and AIFeatures.MaxTokens, so concurrent calls see each other before
+// recordAIUsage replaces the hold with the actual usage.
+func (a *APIDocs) reserveAIBudget(client string, request ChatRequest) (aiReservation, error) {
+	features := a.config.AIConfig.Features
+	if features.DailyTokens <= 0 && features.DailyTokensPerClient <= 0 {
+		return aiReservation{client: client}, nil
+	}
+
+	a.aiUsage.mu.Lock()
+	defer a.aiUsage.mu.Unlock()
+	a.aiUsage.rollover(time.Now())
+	if features.DailyTokens > 0 && a.aiUsage.today.TotalTokens+a.aiUsage.reserved >= features.DailyTokens {
+		return aiReservation{}, fmt.Errorf("the AI assistant has used up today's budget; it is available again after midnight UTC")
+	}
+	if features.DailyTokensPerClient > 0 && a.aiUsage.clients[client]+a.aiUsage.clientsReserved[client] >= features.DailyTokensPerClient {
+		return aiReservation{}, fmt.Errorf("you have used up today's AI chat budget; it resets at midnight UTC")
+	}
+
+	tokens := estimateTokens(len(request.SystemPrompt)+len(request.Context)+len(request.Message)) + features.MaxTokens
+	a.aiUsage.reserved += tokens
+	a.aiUsage.clientsReserved[client] += tokens
+	return aiReservation{client: client, day: a.aiUsage.day, tokens: tokens}, nil
+}
+
+// release drops the hold of reservation; callers hold mu. Holds of an earlier
+// day went with its counters.
+func (s *aiUsageStore) release(reservation aiReservation) {
+	if reservation.tokens == 0 || reservation.day != s.day {
+		return
+	}
+	s.reserved -= reservation.tokens
+	if s.clientsReserved[reservation.client] -= reservation.tokens; s.clientsReserved[reservation.client] <= 0 {
+		delete(s.clientsReserved, reservation.client)
+	}
+}
+
+// recordAIUsage releases the reservation of a chat call and counts the call.
+// Tokens a provider does not report are estimated from the prompt and the
+// answer.
+func (a *APIDocs) recordAIUsage(reservation aiReservation, request ChatRequest, response *ChatResponse) {
+	if response == nil {
+		a.aiUsage.mu.Lock()
+		defer a.aiUsage.mu.Unlock()
+		a.aiUsage.rollover(time.Now())
+		a.aiUsage.release(reservation)
+		return
+	}
+	prompt, completion := response.PromptTokens, response.CompletionTokens
+	total := response.TokensUsed
+	if total == 0 && prompt == 0 && completion == 0 && response.Response != "" {
+		prompt = estimateTokens(len(request.Context) + len(request.Message))
+		completion = estimateTokens(len(response.Response))
+	}
+	if total < prompt+completion {
+		total = prompt + completion
+	}
+	usage := AIUsage{Requests: 1, PromptTokens: prompt, CompletionTokens: completion, TotalTokens: total}
+
+	a.aiUsage.mu.Lock()
+	defer a.aiUsage.mu.Unlock()
+	a.aiUsage.rollover(time.Now())
+	a.aiUsage.release(reservation)
+	a.aiUsage.total.add(usage)
+	a.aiUsage.today.add(usage)
+	a.aiUsage.clients[reservation.client] += total
+	if a.aiUsage.models == nil {
+		a.aiUsage.models = make(map[string]*ModelUsage)
+	}
+	key := response.Provider + " " + response.Model
+	model, ok := a.aiUsage.models[key]
+	if !ok {
+		model = &ModelUsage{Provider: response.Provider, Model: response.Model}
+		a.aiUsage.models[key] = model
+	}
+	model.add(usage)
+}
+
+func (u *AIUsage) add(usage AIUsage) {
+	u.Requests += usage.Requests
+	u.PromptTokens += usage.PromptTokens
+	u.CompletionTokens += usage.CompletionTokens
+	u.TotalTokens += usage.TotalTokens
+}
+
+// AIUsage returns the chat usage since the docs were created
+func (a *APIDocs) AIUsage() *AIUsageReport {
+	return a.aiUsageReport("")
+}
+
+// aiUsageReport adds the daily budget of client, a key of aiClientKey, when
+// budgets are configured
+func (a *APIDocs) aiUsageReport(client string) *AIUsageReport {
+	a.aiUsage.mu.Lock()
+	defer a.aiUsage.mu.Unlock()
+	a.aiUsage.rollover(time.Now())
+
+	report := &AIUsageReport{
+		Since:  a.aiUsage.since,
+		Total:  a.aiUsage.total,
+		Today:  a.aiUsage.today,
+		Models: make([]ModelUsage, 0, len(a.aiUsage.models)),
+	}
+	for _, model := range a.aiUsage.models {
+		report.Models = append(report.Models, *model)
+	}
+	sort.Slice(report.Models, func(i, j int) bool {
+		if report.Models[i].TotalTokens != report.Models[j].TotalTokens {
+			return report.Models[i].TotalTokens > report.Models[j].TotalTokens
+		}
+		return report.Models[i].Provider+" "+report.Models[i].Model < report.Models[j].Provider+" "+report.Models[j].Model
+	})
+
+	if ai := a.config.AIConfig; client != "" && ai != nil && (ai.Features.DailyTokens > 0 || ai.Features.DailyTokensPerClient > 0) {
+		report.Budget = &AIBudgetStatus{
+			Day:        a.aiUsage.day,
+			Used:       a.aiUsage.clients[client],
+			Limit:      ai.Features.DailyTokensPerClient,
+			TotalLimit: ai.Features.DailyTokens,
+		}
+	}
+	return report
+}
+
+func (a *APIDocs) serveAIUsage(w http.ResponseWriter, r *http.Request) {
+	if a.config.AIConfig == nil || !a.config.AIConfig.Enabled {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(a.aiUsageReport(a.aiClientKey(r))); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode AI usage: %v", err), http.StatusInternalServerError)
+	}
+}
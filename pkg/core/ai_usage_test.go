@@ -0,0 +1,208 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeChatClient struct {
+	provider string
+	model    string
+	err      error
+	tokens   int      // prompt and completion tokens reported each
+	answer   string   // "answer" when empty
+	models   []string // models asked for
+	requests []ChatRequest
+}
+
+func (c *fakeChatClient) Chat(ctx context.Context, request ChatRequest) (*ChatResponse, error) {
+	model := c.model
+	if request.Model != "" {
+		model = request.Model
+	}
+	c.models = append(c.models, model)
+	c.requests = append(c.requests, request)
+	if c.err != nil {
+		return &ChatResponse{Error: c.err.Error(), Provider: c.provider, Model: model}, c.err
+	}
+	answer := c.answer
+	if answer == "" {
+		answer = "answer"
+	}
+	return &ChatResponse{Response: answer, Provider: c.provider, Model: model, PromptTokens: c.tokens, CompletionTokens: c.tokens}, nil
+}
+
+func (c *fakeChatClient) GetProvider() string { return c.provider }
+func (c *fakeChatClient) GetModel() string    { return c.model }
+
+func TestAIFailover_FallbacksAndModelOverride(t *testing.T) {
+	down := &fakeChatClient{provider: "fake-down", model: "down-1", err: errors.New("429 rate limited")}
+	up := &fakeChatClient{provider: "fake-up", model: "up-1"}
+	RegisterLLMClientFactory("fake-down", func(config *AIConfig) (LLMClient, error) { return down, nil })
+	RegisterLLMClientFactory("fake-up", func(config *AIConfig) (LLMClient, error) {
+		if config.Features.Model != "up-1" {
+			t.Fatalf("expected the fallback model, got %q", config.Features.Model)
+		}
+		return up, nil
+	})
+
+	docs := New(&Config{Title: "Test", DocsPath: "/docs", AIConfig: &AIConfig{
+		Enabled: true, Provider: "fake-down",
+		Features:  AIFeatures{ChatEnabled: true, Model: "down-1", AllowedModels: []string{"down-large"}},
+		Fallbacks: []AIFallback{{Provider: "fake-up", Model: "up-1"}},
+	}})
+	chat := func(body string) ChatResponse {
+		recorder := httptest.NewRecorder()
+		docs.ServeHTTP(recorder, httptest.NewRequest("POST", "/docs/chat", strings.NewReader(body)))
+		var response ChatResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		return response
+	}
+
+	if response := chat(`{"message":"hi"}`); response.Provider != "fake-up" || response.Model != "up-1" || response.Error != "" {
+		t.Fatalf("expected the fallback to answer, got %+v", response)
+	}
+	// The failed provider cools down and is tried last
+	if response := chat(`{"message":"hi"}`); response.Provider != "fake-up" || len(down.models) != 1 {
+		t.Fatalf("expected the failed provider skipped, got %+v after %v", response, down.models)
+	}
+
+	if response := chat(`{"message":"hi","model":"up-1"}`); response.Provider != "fake-up" || response.Model != "up-1" {
+		t.Fatalf("expected the provider of the model first, got %+v", response)
+	}
+	if response := chat(`{"message":"hi","model":"gpt-9"}`); !strings.Contains(response.Error, "not allowed") || len(up.models) != 3 {
+		t.Fatalf("expected an unknown model rejected, got %+v", response)
+	}
+
+	up.err = errors.New("503 unavailable")
+	response := chat(`{"message":"hi","model":"down-large"}`)
+	if !strings.Contains(response.Error, "all AI providers failed") || up.models[len(up.models)-1] != "down-large" || down.models[len(down.models)-1] != "down-1" {
+		t.Fatalf("expected both providers tried with the override only on the first, got %+v, %v, %v", response, up.models, down.models)
+	}
+}
+
+func TestAIUsage_TrackedAndDailyBudget(t *testing.T) {
+	RegisterLLMClientFactory("fake-metered", func(config *AIConfig) (LLMClient, error) {
+		return &fakeChatClient{provider: "fake-metered", model: "m-1", tokens: 75}, nil
+	})
+	docs := New(&Config{Title: "Test", DocsPath: "/docs", TrustedProxies: []string{"192.0.2.1"}, AIConfig: &AIConfig{
+		Enabled: true, Provider: "fake-metered",
+		Features: AIFeatures{ChatEnabled: true, Model: "m-1", DailyTokensPerClient: 250, DailyTokens: 1000},
+	}})
+	chat := func(ip string) (int, ChatResponse) {
+		request := httptest.NewRequest("POST", "/docs/chat", strings.NewReader(`{"message":"hi"}`))
+		request.Header.Set("X-Forwarded-For", ip)
+		recorder := httptest.NewRecorder()
+		docs.ServeHTTP(recorder, request)
+		var response ChatResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		return recorder.Code, response
+	}
+
+	for i := 0; i < 2; i++ {
+		if code, response := chat("10.0.0.1"); code != http.StatusOK || response.Error != "" {
+			t.Fatalf("expected chat within the budget, got %d %+v", code, response)
+		}
+	}
+	if code, response := chat("10.0.0.1"); code != http.StatusTooManyRequests || !strings.Contains(response.Error, "budget") {
+		t.Fatalf("expected the client budget enforced, got %d %+v", code, response)
+	}
+	if code, response := chat("10.0.0.2"); code != http.StatusOK || response.Error != "" {
+		t.Fatalf("expected other clients unaffected, got %d %+v", code, response)
+	}
+
+	request := httptest.NewRequest("GET", "/docs/ai/usage", nil)
+	request.Header.Set("X-Forwarded-For", "10.0.0.1")
+	recorder := httptest.NewRecorder()
+	docs.ServeHTTP(recorder, request)
+	var report AIUsageReport
+	if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Total.Requests != 3 || report.Total.TotalTokens != 450 || report.Today.PromptTokens != 225 {
+		t.Fatalf("unexpected usage: %+v", report)
+	}
+	if len(report.Models) != 1 || report.Models[0].Model != "m-1" || report.Models[0].CompletionTokens != 225 {
+		t.Fatalf("unexpected model usage: %+v", report.Models)
+	}
+	if report.Budget == nil || report.Budget.Used != 300 || report.Budget.Limit != 250 || report.Budget.TotalLimit != 1000 {
+		t.Fatalf("unexpected budget: %+v", report.Budget)
+	}
+	if strings.Contains(recorder.Body.String(), "10.0.0.2") {
+		t.Fatal("expected other clients not listed")
+	}
+}
+
+func TestAIBudget_KeysOnVerifiedIdentityAndReserves(t *testing.T) {
+	RegisterLLMClientFactory("fake-budget", func(config *AIConfig) (LLMClient, error) {
+		return &fakeChatClient{provider: "fake-budget", model: "m-1", tokens: 75}, nil
+	})
+	features := AIFeatures{ChatEnabled: true, Model: "m-1", DailyTokensPerClient: 300}
+	docs := New(&Config{Title: "Test", DocsPath: "/docs", AIConfig: &AIConfig{Enabled: true, Provider: "fake-budget", Features: features}})
+	chat := func(setup func(*http.Request)) int {
+		request := httptest.NewRequest("POST", "/docs/chat", strings.NewReader(`{"message":"hi"}`))
+		setup(request)
+		recorder := httptest.NewRecorder()
+		docs.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	// Headers of an untrusted peer don't make a new client
+	for i, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+		if code := chat(func(r *http.Request) { r.Header.Set("X-Forwarded-For", ip) }); code != http.StatusOK {
+			t.Fatalf("chat %d: expected it within the budget, got %d", i, code)
+		}
+	}
+	if code := chat(func(r *http.Request) { r.Header.Set("X-Forwarded-For", "10.0.0.3") }); code != http.StatusTooManyRequests {
+		t.Fatalf("expected a spoofed X-Forwarded-For to share the peer's budget, got %d", code)
+	}
+
+	// With docs auth the budget follows the verified user, not a claimed one
+	docs = New(&Config{Title: "Test", DocsPath: "/docs",
+		AuthConfig: &AuthConfig{Enabled: true, Type: "api_key", APIKey: "key"},
+		AIConfig:   &AIConfig{Enabled: true, Provider: "fake-budget", Features: features}})
+	if code := chat(func(r *http.Request) { r.Header.Set("X-API-Key", "key"); r.SetBasicAuth("alice", "x") }); code != http.StatusOK {
+		t.Fatalf("expected the first chat allowed, got %d", code)
+	}
+	if code := chat(func(r *http.Request) { r.Header.Set("X-API-Key", "key"); r.SetBasicAuth("bob", "x") }); code != http.StatusOK {
+		t.Fatalf("expected the second chat allowed, got %d", code)
+	}
+	if code := chat(func(r *http.Request) { r.Header.Set("X-API-Key", "key"); r.SetBasicAuth("carol", "x") }); code != http.StatusTooManyRequests {
+		t.Fatalf("expected a claimed basic auth user to share the key's budget, got %d", code)
+	}
+
+	// Calls in flight hold their tokens, so concurrent calls can't overrun the budget
+	features.MaxTokens = 149
+	docs = New(&Config{Title: "Test", DocsPath: "/docs", AIConfig: &AIConfig{Enabled: true, Provider: "fake-budget", Features: features}})
+	first, err := docs.reserveAIBudget("ip:10.0.0.1", ChatRequest{Message: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := docs.reserveAIBudget("ip:10.0.0.1", ChatRequest{Message: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := docs.reserveAIBudget("ip:10.0.0.1", ChatRequest{Message: "hi"}); err == nil {
+		t.Fatal("expected the reserved tokens counted against the budget")
+	}
+	if _, err := docs.reserveAIBudget("ip:10.0.0.2", ChatRequest{Message: "hi"}); err != nil {
+		t.Fatalf("expected other clients unaffected, got %v", err)
+	}
+	docs.recordAIUsage(first, ChatRequest{Message: "hi"}, nil)
+	docs.recordAIUsage(second, ChatRequest{Message: "hi"}, &ChatResponse{Response: "ok", PromptTokens: 10, CompletionTokens: 10})
+	if report := docs.aiUsageReport("ip:10.0.0.1"); report.Budget == nil || report.Budget.Used != 20 {
+		t.Fatalf("expected the holds replaced by the actual usage, got %+v", report.Budget)
+	}
+	if _, err := docs.reserveAIBudget("ip:10.0.0.1", ChatRequest{Message: "hi"}); err != nil {
+		t.Fatalf("expected the released tokens available again, got %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultMaxSearches = 1000
+	maxSearchLength    = 100      // longer queries are cut, so pasted payloads are not kept
+	maxUsageEvents     = 100      // events accepted per request
+	maxUsageBody       = 64 << 10 // bytes accepted per request
+)
+
+// AnalyticsConfig enables usage analytics of the docs UI: which endpoints are
+// viewed and tried and what is searched. Only counters are kept, in memory;
+// no IP addresses, user agents, sessions or timestamps of single events are
+// stored, and browsers sending Do Not Track or Global Privacy Control are
+// not counted.
+type AnalyticsConfig struct {
+	Enabled     bool
+	MaxSearches int // distinct search queries kept; later new queries are dropped; 0 uses 1000
+}
+
+// AnalyticsReport is the usage of the documentation since Since
+type AnalyticsReport struct {
+	Since     time.Time       `json:"since"`
+	Endpoints []EndpointUsage `json:"endpoints"` // every documented endpoint, most used first
+	Searches  []SearchUsage   `json:"searches"`  // most frequent first
+	NoResults []SearchUsage   `json:"noResults"` // searches that found no endpoint, most frequent first
+}
+
+// EndpointUsage counts how often an endpoint was opened and sent from the UI
+type EndpointUsage struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Views  int    `json:"views"`
+	Tries  int    `json:"tries"`
+}
+
+// SearchUsage counts a normalized search query
+type SearchUsage struct {
+	Query     string `json:"query"`
+	Count     int    `json:"count"`
+	NoResults int    `json:"noResults"` // how many of the searches found nothing
+}
+
+// usageEvent is an event sent by the docs UI
+type usageEvent struct {
+	Type    string `json:"type"` // "view", "try" or "search"
+	Method  string `json:"method,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Query   string `json:"query,omitempty"`
+	Results *int   `json:"results,omitempty"` // endpoints the search found
+}
+
+// usageStore holds the analytics counters
+type usageStore struct {
+	mu        sync.Mutex
+	since     time.Time
+	endpoints map[string]*EndpointUsage // by "METHOD path"
+	searches  map[string]*SearchUsage
+}
+
+// Analytics returns the usage counted since the docs were created or the
+// last ResetAnalytics. Endpoints no longer documented are left out.
+func (a *APIDocs) Analytics() *AnalyticsReport {
+	documentation := a.GetDocumentation()
+
+	a.usage.mu.Lock()
+	defer a.usage.mu.Unlock()
+
+	report := &AnalyticsReport{
+		Since:     a.usage.since,
+		Endpoints: make([]EndpointUsage, 0),
+		Searches:  make([]SearchUsage, 0, len(a.usage.searches)),
+		NoResults: make([]SearchUsage, 0),
+	}
+	for _, section := range documentation.Endpoints {
+		for _, endpoint := range section.Endpoints {
+			usage := EndpointUsage{Method: endpoint.Method, Path: endpoint.Path}
+			if counted, ok := a.usage.endpoints[endpoint.Method+" "+endpoint.Path]; ok {
+				usage = *counted
+			}
+			report.Endpoints = append(report.Endpoints, usage)
+		}
+	}
+	sort.SliceStable(report.Endpoints, func(i, j int) bool {
+		left, right := report.Endpoints[i], report.Endpoints[j]
+		return left.Views+left.Tries > right.Views+right.Tries
+	})
+
+	for _, search := range a.usage.searches {
+		report.Searches = append(report.Searches, *search)
+		if search.NoResults > 0 {
+			report.NoResults = append(report.NoResults, *search)
+		}
+	}
+	sort.Slice(report.Searches, func(i, j int) bool {
+		return searchLess(report.Searches[i].Count, report.Searches[j].Count, report.Searches[i].Query, report.Searches[j].Query)
+	})
+	sort.Slice(report.NoResults, func(i, j int) bool {
+		return searchLess(report.NoResults[i].NoResults, report.NoResults[j].NoResults, report.NoResults[i].Query, report.NoResults[j].Query)
+	})
+	return report
+}
+
+// ResetAnalytics drops the counters, e.g. after exporting them
+func (a *APIDocs) ResetAnalytics() {
+	a.usage.mu.Lock()
+	defer a.usage.mu.Unlock()
+	a.usage.since = time.Now().UTC()
+	a.usage.endpoints = nil
+	a.usage.searches = nil
+}
+
+// searchLess orders searches by count, then alphabetically
+func searchLess(leftCount, rightCount int, leftQuery, rightQuery string) bool {
+	if leftCount != rightCount {
+		return leftCount > rightCount
+	}
+	return leftQuery < rightQuery
+}
+
+// recordUsage counts the events; views and tries of endpoints that are not
+// documented are ignored, so clients cannot fill the store with made-up paths
+func (a *APIDocs) recordUsage(events []usageEvent) {
+	documented := make(map[string]bool)
+	for _, section := range a.GetDocumentation().Endpoints {
+		for _, endpoint := range section.Endpoints {
+			documented[endpoint.Method+" "+endpoint.Path] = true
+		}
+	}
+	maxSearches := a.config.Analytics.MaxSearches
+	if maxSearches <= 0 {
+		maxSearches = defaultMaxSearches
+	}
+
+	a.usage.mu.Lock()
+	defer a.usage.mu.Unlock()
+	for _, event := range events {
+		switch event.Type {
+		case "view", "try":
+			key := strings.ToUpper(event.Method) + " " + event.Path
+			if !documented[key] {
+				continue
+			}
+			if a.usage.endpoints == nil {
+				a.usage.endpoints = make(map[string]*EndpointUsage)
+			}
+			usage, ok := a.usage.endpoints[key]
+			if !ok {
+				usage = &EndpointUsage{Method: strings.ToUpper(event.Method), Path: event.Path}
+				a.usage.endpoints[key] = usage
+			}
+			if event.Type == "view" {
+				usage.Views++
+			} else {
+				usage.Tries++
+			}
+		case "search":
+			query := normalizeSearchQuery(event.Query)
+			if query == "" {
+				continue
+			}
+			if a.usage.searches == nil {
+				a.usage.searches = make(map[string]*SearchUsage)
+			}
+			usage, ok := a.usage.searches[query]
+			if !ok {
+				if len(a.usage.searches) >= maxSearches {
+					continue
+				}
+				usage = &SearchUsage{Query: query}
+				a.usage.searches[query] = usage
+			}
+			usage.Count++
+			if event.Results != nil && *event.Results == 0 {
+				usage.NoResults++
+			}
+		}
+	}
+}
+
+// normalizeSearchQuery lowercases the query, collapses its whitespace and
+// cuts it to maxSearchLength characters
+func normalizeSearchQuery(query string) string {
+	query = strings.ToLower(strings.Join(strings.Fields(query), " "))
+	if utf8.RuneCountInString(query) > maxSearchLength {
+		query = string([]rune(query)[:maxSearchLength])
+	}
+	return query
+}
+
+// csvCell quotes a user-entered value that spreadsheets would run as a formula
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// serveAnalytics handles GET /analytics, the report as JSON or with
+// ?format=csv as a CSV download, and POST /analytics/events from the UI
+func (a *APIDocs) serveAnalytics(w http.ResponseWriter, r *http.Request, path string) {
+	if a.config.Analytics == nil || !a.config.Analytics.Enabled {
+		http.NotFound(w, r)
+		return
+	}
+
+	if path == "/analytics/events" {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		// Opted-out browsers are not counted, whatever the UI sends
+		if r.Header.Get("DNT") == "1" || r.Header.Get("Sec-GPC") == "1" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		var batch struct {
+			Events []usageEvent `json:"events"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxUsageBody)).Decode(&batch); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid events: %v", err), http.StatusBadRequest)
+			return
+		}
+		if len(batch.Events) > maxUsageEvents {
+			http.Error(w, fmt.Sprintf("At most %d events per request", maxUsageEvents), http.StatusRequestEntityTooLarge)
+			return
+		}
+		a.recordUsage(batch.Events)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	report := a.Analytics()
+	w.Header().Set("Cache-Control", "no-store")
+
+	if r.URL.Query().Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="bytedocs-analytics.csv"`)
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"type", "method", "path", "query", "views", "tries", "searches", "no_results"})
+		for _, usage := range report.Endpoints {
+			writer.Write([]string{"endpoint", usage.Method, usage.Path, "", strconv.Itoa(usage.Views), strconv.Itoa(usage.Tries), "", ""})
+		}
+		for _, usage := range report.Searches {
+			writer.Write([]string{"search", "", "", csvCell(usage.Query), "", "", strconv.Itoa(usage.Count), strconv.Itoa(usage.NoResults)})
+		}
+		writer.Flush()
+		return
+	}
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode analytics: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(body, '\n'))
+}
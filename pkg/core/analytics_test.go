@@ -0,0 +1,84 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAnalytics_CountsDocumentedEndpointsAndSearches(t *testing.T) {
+	docs := New(&Config{Title: "Shop", Version: "1.0.0", DocsPath: "/docs", Analytics: &AnalyticsConfig{Enabled: true, MaxSearches: 2}})
+	docs.AddRoute("GET", "/orders", nil)
+	docs.AddRoute("POST", "/orders", nil)
+	docs.AddRoute("DELETE", "/orders/{id}", nil)
+	if err := docs.Warmup(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	send := func(body string, header http.Header) int {
+		req := httptest.NewRequest("POST", "/docs/analytics/events", strings.NewReader(body))
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		rec := httptest.NewRecorder()
+		docs.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	status := send(`{"events":[
+		{"type":"view","method":"GET","path":"/orders"},
+		{"type":"view","method":"get","path":"/orders"},
+		{"type":"try","method":"GET","path":"/orders"},
+		{"type":"try","method":"POST","path":"/orders"},
+		{"type":"view","method":"GET","path":"/not-documented"},
+		{"type":"search","query":"  Refund   Order ","results":0},
+		{"type":"search","query":"refund order","results":0},
+		{"type":"search","query":"orders","results":2},
+		{"type":"search","query":"one too many","results":0}
+	]}`, nil)
+	if status != http.StatusNoContent {
+		t.Fatalf("expected 204 for recorded events, got %d", status)
+	}
+	if status := send(`{"events":[{"type":"view","method":"DELETE","path":"/orders/{id}"}]}`, http.Header{"Dnt": {"1"}}); status != http.StatusNoContent {
+		t.Fatalf("expected Do Not Track requests to be accepted, got %d", status)
+	}
+	if status := send(`not json`, nil); status != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid events, got %d", status)
+	}
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/analytics", nil))
+	var report AnalyticsReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("expected a JSON report: %v\n%s", err, rec.Body.String())
+	}
+	if len(report.Endpoints) != 3 || report.Endpoints[0] != (EndpointUsage{Method: "GET", Path: "/orders", Views: 2, Tries: 1}) {
+		t.Fatalf("expected GET /orders to be used most, got %+v", report.Endpoints)
+	}
+	if last := report.Endpoints[2]; last.Method != "DELETE" || last.Views != 0 {
+		t.Fatalf("expected the DNT view of DELETE /orders/{id} to be ignored, got %+v", last)
+	}
+	if len(report.Searches) != 2 || len(report.NoResults) != 1 || report.NoResults[0] != (SearchUsage{Query: "refund order", Count: 2, NoResults: 2}) {
+		t.Fatalf("expected normalized and capped searches, got %+v / %+v", report.Searches, report.NoResults)
+	}
+
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/analytics?format=csv", nil))
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") || !strings.Contains(rec.Body.String(), "endpoint,GET,/orders,,2,1,,\n") || !strings.Contains(rec.Body.String(), "search,,,refund order,,,2,2\n") {
+		t.Fatalf("unexpected CSV export:\n%s", rec.Body.String())
+	}
+
+	docs.ResetAnalytics()
+	if report := docs.Analytics(); report.Endpoints[0].Views != 0 || len(report.Searches) != 0 {
+		t.Fatalf("expected ResetAnalytics to drop the counters, got %+v", report)
+	}
+
+	disabled := New(&Config{Title: "Shop", DocsPath: "/docs"})
+	rec = httptest.NewRecorder()
+	disabled.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/analytics", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected analytics to be disabled by default, got %d", rec.Code)
+	}
+}
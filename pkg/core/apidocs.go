@@ -1,14 +1,21 @@
 package core
 
 import (
+	"bytes"
+	"context"
 	_ "embed"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	pathpkg "path"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
-	"text/template"
+	"sync"
+	"sync/atomic"
+	"html/template"
+	"time"
 
 	_ "github.com/idnexacloud/bytedocs-go/pkg/llm"
 	"gopkg.in/yaml.v3"
@@ -18,11 +25,77 @@ import (
 var templateHTML string
 
 type APIDocs struct {
-	config        *Config
-	documentation *Documentation
+	config    *Config
+	llmClient LLMClient
+
+	// mu guards routes, schemas and overrides. The generated documentation is
+	// published atomically so readers never block on generation.
+	mu            sync.Mutex
 	routes        []RouteInfo
 	schemas       map[string]Schema
-	llmClient     LLMClient
+	overrides     map[string][]func(*Endpoint)
+	sections      map[string]sectionMeta
+	channels      map[string]Channel
+	groups        []groupResponse
+	shared        []SharedParameter
+	documentation atomic.Pointer[Documentation]
+	dirty         atomic.Bool
+	generatedAt   atomic.Int64 // Unix time of the last generation, sent as Last-Modified
+
+	// detector discovers framework routes; it runs once, on Warmup or the
+	// first docs request. ready is closed once that first build finished.
+	detector   func()
+	detectOnce sync.Once
+	ready      chan struct{}
+
+	// Refresh runs refreshHooks and the detector again. While detecting,
+	// AddRouteInfo stages routes, which then replace the detected ones.
+	refreshMu    sync.Mutex
+	refreshHooks []func()
+	detecting    bool
+	staged       []RouteInfo
+
+	// generateHooks edit every generated documentation before it is published
+	generateHooks []func(*Documentation)
+
+	// notifyBaseline is the endpoint snapshot notifications compare against;
+	// nil until the first build finished
+	notifyBaseline map[string]endpointSnapshot
+
+	// usage counts views, tries and searches of the docs UI when analytics are enabled
+	usage usageStore
+
+	// traffic queues the routes recorded by Middleware for review
+	traffic trafficStore
+
+	// routeTemplates are the OpenAPI paths Middleware matches requests
+	// against; nil after routes changed until Generate or a request rebuilds them
+	routeTemplates atomic.Pointer[[]routeTemplate]
+
+	// redaction holds the redaction rules built from the config
+	redaction redactors
+
+	// aiUsage counts the tokens of AI chat for /ai/usage and the daily budgets
+	aiUsage aiUsageStore
+
+	// prompt is the parsed AIFeatures.PromptTemplate
+	prompt promptTemplateState
+
+	// federated holds the routes of each federated service's last successful fetch
+	federated map[string][]RouteInfo
+
+	// closed is canceled by Close and stops the background refreshes
+	closed    context.Context
+	closeDocs context.CancelFunc
+
+	// health tracks the AI client and federated services for /healthz;
+	// llmErr is why the AI client could not be created
+	health healthState
+	llmErr error
+
+	authOnce    sync.Once
+	authHandler http.Handler
+	cookieScope string // set by Registry.Mount, unless AuthConfig.CookieScope is
 }
 
 func convertPathToOpenAPI(path string) string {
@@ -32,6 +105,15 @@ func convertPathToOpenAPI(path string) string {
 
 	parts := strings.Split(path, "/")
 	for i, part := range parts {
+		if part == "{$}" {
+			// net/http's end-of-path marker: "/users/{$}" matches "/users/" only
+			parts[i] = ""
+			continue
+		}
+		if name, ok := wildcardSegment(part); ok {
+			parts[i] = "{" + name + "}"
+			continue
+		}
 		if strings.HasPrefix(part, ":") {
 			param := strings.TrimPrefix(part, ":")
 			parts[i] = "{" + param + "}"
@@ -84,33 +166,61 @@ func New(config *Config) *APIDocs {
 	}
 
 	var llmClient LLMClient
+	var llmErr error
 	if config.AIConfig != nil && config.AIConfig.Enabled {
 		client, err := NewLLMClient(config.AIConfig)
 		if err == nil {
 			llmClient = client
 		}
+		llmErr = err
 	}
 
-	return &APIDocs{
+	docs := &APIDocs{
 		config:    config,
 		routes:    make([]RouteInfo, 0),
 		schemas:   make(map[string]Schema),
 		llmClient: llmClient,
-		documentation: &Documentation{
-			Info: APIInfo{
-				Title:       config.Title,
-				Version:     config.Version,
-				Description: config.Description,
-				BaseURL:     config.BaseURL,
-			},
-			Endpoints: make([]EndpointSection, 0),
-			Schemas:   make(map[string]Schema),
-		},
+		llmErr:    llmErr,
+		overrides: make(map[string][]func(*Endpoint)),
+		sections:  make(map[string]sectionMeta),
+		channels:  make(map[string]Channel),
+		ready:     make(chan struct{}),
+		usage:     usageStore{since: time.Now().UTC()},
+		aiUsage:   aiUsageStore{since: time.Now().UTC()},
 	}
+	docs.documentation.Store(&Documentation{
+		Info: APIInfo{
+			Title:       config.Title,
+			Version:     config.Version,
+			Description: config.Description,
+			BaseURL:     config.BaseURL,
+		},
+		Endpoints: make([]EndpointSection, 0),
+		Schemas:   make(map[string]Schema),
+	})
+	docs.generatedAt.Store(time.Now().Unix())
+	docs.closed, docs.closeDocs = context.WithCancel(context.Background())
+	return docs
+}
+
+// Close stops the background work of the docs, such as the periodic fetches
+// of federated services. The docs keep serving what they generated last.
+func (a *APIDocs) Close() error {
+	a.closeDocs()
+	return nil
 }
 
 func (a *APIDocs) AddRouteInfo(route RouteInfo) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.detecting {
+		route.detected = true
+		a.staged = append(a.staged, route)
+		return
+	}
 	a.routes = append(a.routes, route)
+	a.routeTemplates.Store(nil)
+	a.dirty.Store(true)
 }
 
 func (a *APIDocs) GetConfig() *Config {
@@ -128,23 +238,167 @@ func (a *APIDocs) AddRoute(method, path string, handler interface{}, options ...
 		option(&route)
 	}
 
-	a.routes = append(a.routes, route)
+	a.AddRouteInfo(route)
 }
 
 type RouteOption func(*RouteInfo)
 
+// OverrideEndpoint registers a function that can fix or enrich the generated
+// endpoint for method and path. Overrides run on every Generate, after
+// auto-detected metadata has been merged, in registration order.
+func (a *APIDocs) OverrideEndpoint(method, path string, fn func(*Endpoint)) {
+	if fn == nil {
+		return
+	}
+	key := overrideKey(method, path)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.overrides[key] = append(a.overrides[key], fn)
+	a.dirty.Store(true)
+}
+
+// groupResponse is a response every endpoint under a path prefix can return
+type groupResponse struct {
+	prefix   string
+	status   string
+	response Response
+}
+
+// AddGroupResponse documents a response that every endpoint under pathPrefix
+// can return without its handler saying so, e.g. 401 from auth middleware or
+// 500 from recover middleware. An empty prefix or "/" applies to all endpoints.
+// Responses detected on the endpoint itself take precedence.
+func (a *APIDocs) AddGroupResponse(pathPrefix string, status int, response Response) {
+	if response.Description == "" {
+		response.Description = http.StatusText(status)
+	}
+	if response.ContentType == "" {
+		response.ContentType = "application/json"
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.groups = append(a.groups, groupResponse{
+		prefix:   strings.TrimSuffix(convertPathToOpenAPI(pathPrefix), "/"),
+		status:   strconv.Itoa(status),
+		response: response,
+	})
+	a.dirty.Store(true)
+}
+
+// AddAuthResponses documents the 401 and 403 responses of auth middleware for pathPrefix
+func (a *APIDocs) AddAuthResponses(pathPrefix string) {
+	a.AddGroupResponse(pathPrefix, http.StatusUnauthorized, Response{})
+	a.AddGroupResponse(pathPrefix, http.StatusForbidden, Response{})
+}
+
+// AddRecoveryResponse documents the 500 response of panic-recovery middleware for pathPrefix
+func (a *APIDocs) AddRecoveryResponse(pathPrefix string) {
+	a.AddGroupResponse(pathPrefix, http.StatusInternalServerError, Response{})
+}
+
+func (a *APIDocs) applyGroupResponses(endpoint *Endpoint) {
+	copied := false
+	for _, group := range a.groups {
+		if group.prefix != "" && endpoint.Path != group.prefix && !strings.HasPrefix(endpoint.Path, group.prefix+"/") {
+			continue
+		}
+		if _, exists := endpoint.Responses[group.status]; exists {
+			continue
+		}
+		// The responses map may be shared with the registered route; copy before adding
+		if !copied {
+			responses := make(map[string]Response, len(endpoint.Responses)+1)
+			for status, response := range endpoint.Responses {
+				responses[status] = response
+			}
+			endpoint.Responses = responses
+			copied = true
+		}
+		endpoint.Responses[group.status] = group.response
+	}
+}
+
+func overrideKey(method, path string) string {
+	return strings.ToUpper(method) + " " + convertPathToOpenAPI(path)
+}
+
+func (a *APIDocs) applyOverrides(endpoint *Endpoint) {
+	for _, fn := range a.overrides[overrideKey(endpoint.Method, endpoint.Path)] {
+		fn(endpoint)
+	}
+}
+
+// Generate rebuilds the documentation from the registered routes. It is safe
+// to call concurrently; the result is published atomically.
 func (a *APIDocs) Generate() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Routes added while generating mark the docs dirty again
+	a.dirty.Store(false)
+
 	sections := make(map[string]*EndpointSection)
 
-	for _, route := range a.routes {
+	a.routeTemplates.Store(newRouteTemplates(a.currentRoutes()))
+	registered := mergeDuplicateRoutes(a.withFederatedRoutes(a.currentRoutes()))
+	methodsByPath := make(map[string]map[string]bool)
+	for _, route := range registered {
+		path := convertPathToOpenAPI(route.Path)
+		if methodsByPath[path] == nil {
+			methodsByPath[path] = make(map[string]bool)
+		}
+		methodsByPath[path][strings.ToUpper(route.Method)] = true
+	}
+
+	shared, sharedByKey, err := a.sharedParameters()
+	if err != nil {
+		return err
+	}
+	locale := exampleLocale(a.config.ExampleLocale)
+
+	routes := make([]RouteInfo, 0, len(registered))
+	endpoints := make([]*Endpoint, 0, len(registered))
+	var warnings []AnalysisWarning
+	for _, route := range registered {
+		if a.shouldSkipRoute(route, methodsByPath) {
+			continue
+		}
+
 		endpoint := a.processRoute(route)
-		sectionName := a.extractSection(endpoint.Path)
+		a.applyOperationID(route, endpoint)
+		a.applyGroupResponses(endpoint)
+		a.applyDefaultResponses(endpoint)
+		applyResponseDescriptions(endpoint, route.ResponseDocs)
+		a.applyPagination(route, endpoint)
+		applyAccessModes(endpoint)
+		if a.config.UnwrapEnvelopes {
+			unwrapEnvelopes(endpoint)
+		}
+		if a.config.FakeExamples {
+			applyFakeExamples(endpoint, locale)
+		}
+		a.applyCapturedExamples(endpoint)
+		applySharedParameters(endpoint, shared)
+		a.applyOverrides(endpoint)
+		a.applyRedaction(endpoint)
+		routes = append(routes, route)
+		endpoints = append(endpoints, endpoint)
+		for _, message := range route.Warnings {
+			warnings = append(warnings, AnalysisWarning{Method: endpoint.Method, Path: endpoint.Path, Message: message})
+		}
+	}
+	dedupeOperationIDs(endpoints)
+
+	for i, endpoint := range endpoints {
+		sectionName, displayName := a.groupEndpoint(routes[i], endpoint)
 
 		if sections[sectionName] == nil {
 			sections[sectionName] = &EndpointSection{
 				ID:          sectionName,
-				Name:        a.formatSectionName(sectionName),
-				Description: fmt.Sprintf("%s related endpoints", a.formatSectionName(sectionName)),
+				Name:        displayName,
+				Description: fmt.Sprintf("%s related endpoints", displayName),
 				Endpoints:   make([]Endpoint, 0),
 			}
 		}
@@ -152,14 +406,192 @@ func (a *APIDocs) Generate() error {
 		sections[sectionName].Endpoints = append(sections[sectionName].Endpoints, *endpoint)
 	}
 
-	a.documentation.Endpoints = make([]EndpointSection, 0, len(sections))
+	guides, err := loadGuides(a.config)
+	if err != nil {
+		return err
+	}
+
+	current := a.documentation.Load()
+	docsRedactor, _ := a.redactors()
+	documentation := &Documentation{
+		Info:      current.Info,
+		Schemas:   docsRedactor.redactSchemas(current.Schemas),
+		Endpoints: make([]EndpointSection, 0, len(sections)),
+		Guides:    guides,
+		Warnings:  warnings,
+
+		SharedParameters: sharedByKey,
+	}
 	for _, section := range sections {
-		a.documentation.Endpoints = append(a.documentation.Endpoints, *section)
+		a.applySectionMeta(section)
+		documentation.Endpoints = append(documentation.Endpoints, *section)
+	}
+	// Sections come from a map; sort them so every export is stable
+	a.sortSections(documentation.Endpoints)
+	if len(a.generateHooks) > 0 {
+		// Hooks may edit the schemas; the published documentation keeps its own map
+		documentation.Schemas = make(map[string]Schema, len(current.Schemas))
+		for name, schema := range current.Schemas {
+			documentation.Schemas[name] = schema
+		}
+		for _, hook := range a.generateHooks {
+			hook(documentation)
+		}
+	}
+	a.documentation.Store(documentation)
+	a.generatedAt.Store(time.Now().Unix())
+	if a.notifyBaseline != nil {
+		a.reportChanges(snapshotDocumentation(documentation))
 	}
 
 	return nil
 }
 
+// OnGenerate registers a function that edits every generated documentation
+// before it is served and exported, after routes were detected, e.g. to add
+// computed endpoints, strip internal fields or reorder sections. Hooks run in
+// registration order while the documentation is generated, so they must not
+// call methods of the APIDocs that add routes or generate.
+func (a *APIDocs) OnGenerate(fn func(*Documentation)) {
+	if fn == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.generateHooks = append(a.generateHooks, fn)
+	a.dirty.Store(true)
+}
+
+// SetRouteDetector registers the function that discovers routes from the
+// framework router. It runs once, on Warmup or the first docs request.
+func (a *APIDocs) SetRouteDetector(detect func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.detector = detect
+}
+
+// build runs route detection once and regenerates the documentation if needed
+func (a *APIDocs) build() error {
+	a.detectOnce.Do(func() {
+		a.mu.Lock()
+		detect := a.detector
+		a.mu.Unlock()
+
+		if detect != nil {
+			a.detectRoutes(detect)
+		}
+		a.startFederation()
+		a.ensureGenerated()
+		a.initNotifications()
+		close(a.ready)
+	})
+	return a.ensureGenerated()
+}
+
+// Warmup runs route detection and analysis in a background goroutine and
+// waits until the documentation is built or ctx is done. Call it after all
+// routes are registered; use `go docs.Warmup(ctx)` to not block startup.
+func (a *APIDocs) Warmup(ctx context.Context) error {
+	go a.build()
+
+	select {
+	case <-a.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Ready returns a channel that is closed once the documentation was first built
+func (a *APIDocs) Ready() <-chan struct{} {
+	return a.ready
+}
+
+// IsReady reports whether the documentation was built, e.g. for readiness probes
+func (a *APIDocs) IsReady() bool {
+	select {
+	case <-a.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+// ensureGenerated regenerates the documentation only when routes or overrides
+// changed since the last build, so steady-state reads never take the lock
+func (a *APIDocs) ensureGenerated() error {
+	if !a.dirty.Load() {
+		return nil
+	}
+	return a.Generate()
+}
+
+// DefaultExcludePaths hide detected framework internals, health checks and
+// static files when Config.ExcludePaths is nil; an empty, non-nil slice
+// documents them all. Routes added with AddRoute are always kept.
+var DefaultExcludePaths = []string{"_ignition", "debug", "health", "healthz", "static", "assets"}
+
+// shouldSkipRoute applies ExcludePaths, SkipMethods and SkipAutoOPTIONS so every
+// framework integration filters routes the same way
+func (a *APIDocs) shouldSkipRoute(route RouteInfo, methodsByPath map[string]map[string]bool) bool {
+	method := strings.ToUpper(route.Method)
+
+	for _, skip := range a.config.SkipMethods {
+		if strings.EqualFold(strings.TrimSpace(skip), method) {
+			return true
+		}
+	}
+
+	excludes := a.config.ExcludePaths
+	if excludes == nil && route.detected {
+		excludes = DefaultExcludePaths
+	}
+	for _, exclude := range excludes {
+		if excludesPath(strings.TrimSpace(exclude), route.Path) {
+			return true
+		}
+	}
+
+	if a.config.SkipAutoOPTIONS && (method == http.MethodOptions || method == http.MethodHead) {
+		for other := range methodsByPath[convertPathToOpenAPI(route.Path)] {
+			if other != http.MethodOptions && other != http.MethodHead {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// excludesPath reports whether an ExcludePaths entry matches routePath. An
+// entry without a slash, e.g. "health" or "debug*", matches any whole path
+// segment; one with a slash, e.g. "/internal/*/metrics", matches the leading
+// segments. Segments take path.Match globs, so "health" keeps /api/healthcare.
+func excludesPath(exclude, routePath string) bool {
+	if exclude == "" {
+		return false
+	}
+	segments := strings.Split(strings.Trim(routePath, "/"), "/")
+	if !strings.Contains(exclude, "/") {
+		for _, segment := range segments {
+			if matched, _ := pathpkg.Match(exclude, segment); matched {
+				return true
+			}
+		}
+		return false
+	}
+	patterns := strings.Split(strings.Trim(exclude, "/"), "/")
+	if len(patterns) > len(segments) {
+		return false
+	}
+	for i, pattern := range patterns {
+		if matched, _ := pathpkg.Match(pattern, segments[i]); !matched {
+			return false
+		}
+	}
+	return true
+}
+
 func (a *APIDocs) processRoute(route RouteInfo) *Endpoint {
 	displayPath := convertPathToOpenAPI(route.Path)
 	
@@ -174,12 +606,13 @@ func (a *APIDocs) processRoute(route RouteInfo) *Endpoint {
 	}
 
 	pathParams := a.extractParameters(route.Path, route.Handler)
-	allParams := a.mergeParameters(pathParams, route.Parameters)
+	allParams := markWildcardParameters(route.Path, a.mergeParameters(pathParams, route.Parameters))
 
 	requestBody := route.RequestBody
 	if requestBody == nil {
 		requestBody = a.extractRequestBody(route.Handler)
 	}
+	requestBody = withAccepts(requestBody, route.Accepts)
 
 	responses := route.Responses
 	if len(responses) == 0 {
@@ -187,16 +620,25 @@ func (a *APIDocs) processRoute(route RouteInfo) *Endpoint {
 	}
 
 	endpoint := &Endpoint{
-		ID:          a.generateID(route.Method, displayPath),
-		Method:      route.Method,
-		Path:        displayPath,
-		Summary:     summary,
-		Description: description,
-		Parameters:  allParams,
-		RequestBody: requestBody,
-		Responses:   responses,
-		Handler:     reflect.ValueOf(route.Handler),
+		ID:           a.generateID(route.Method, displayPath),
+		Method:       route.Method,
+		Path:         displayPath,
+		Summary:      summary,
+		Description:  description,
+		Parameters:   allParams,
+		RequestBody:  requestBody,
+		Responses:    responses,
+		Deprecated:   route.Deprecated,
+		ExternalDocs: route.ExternalDocs,
+		Presets:      route.Presets,
+		SLA:          route.SLA,
+		Extensions:   route.Extensions,
+		Handler:      reflect.ValueOf(route.Handler),
 	}
+	if route.Summary == "" {
+		endpoint.generatedSummary = summary
+	}
+	applyGraphQL(endpoint)
 
 	return endpoint
 }
@@ -237,21 +679,19 @@ func (a *APIDocs) analyzeHandlerParameters(handler interface{}) []Parameter {
 	return params
 }
 
+// mergeParameters combines path and provided parameters. Provided parameters
+// replace path parameters with the same name and location; order is kept stable.
 func (a *APIDocs) mergeParameters(pathParams, providedParams []Parameter) []Parameter {
-	paramMap := make(map[string]Parameter)
+	result := make([]Parameter, 0, len(pathParams)+len(providedParams))
+	indexByKey := make(map[string]int)
 
-	for _, param := range pathParams {
+	for _, param := range append(append([]Parameter{}, pathParams...), providedParams...) {
 		key := param.Name + ":" + param.In
-		paramMap[key] = param
-	}
-
-	for _, param := range providedParams {
-		key := param.Name + ":" + param.In
-		paramMap[key] = param
-	}
-
-	result := make([]Parameter, 0, len(paramMap))
-	for _, param := range paramMap {
+		if index, ok := indexByKey[key]; ok {
+			result[index] = param
+			continue
+		}
+		indexByKey[key] = len(result)
 		result = append(result, param)
 	}
 
@@ -330,26 +770,29 @@ func (a *APIDocs) inferAction(method, path string) string {
 	}
 }
 
+// GetDocumentation returns the most recently generated documentation.
+// The returned value must be treated as read-only.
 func (a *APIDocs) GetDocumentation() *Documentation {
-	return a.documentation
+	return a.documentation.Load()
 }
 
 func (a *APIDocs) GetOpenAPIJSON() (map[string]interface{}, error) {
-	if err := a.Generate(); err != nil {
+	if err := a.build(); err != nil {
 		return nil, err
 	}
+	documentation := a.GetDocumentation()
 
 	openAPI := map[string]interface{}{
 		"openapi": "3.0.3",
 		"info": map[string]interface{}{
-			"title":       a.documentation.Info.Title,
-			"version":     a.documentation.Info.Version,
-			"description": a.documentation.Info.Description,
+			"title":       documentation.Info.Title,
+			"version":     documentation.Info.Version,
+			"description": documentation.Info.Description,
 		},
 		"servers": []map[string]interface{}{},
 		"paths":   map[string]interface{}{},
 		"components": map[string]interface{}{
-			"schemas": a.documentation.Schemas,
+			"schemas": documentation.Schemas,
 		},
 	}
 
@@ -361,16 +804,32 @@ func (a *APIDocs) GetOpenAPIJSON() (map[string]interface{}, error) {
 	if len(a.config.BaseURLs) > 0 {
 		servers := make([]map[string]interface{}, 0)
 		for _, baseURL := range a.config.BaseURLs {
-			servers = append(servers, map[string]interface{}{
+			server := map[string]interface{}{
 				"url":         baseURL.URL,
 				"description": baseURL.Name,
-			})
+			}
+			if len(baseURL.Variables) > 0 {
+				server["variables"] = baseURL.Variables
+			}
+			servers = append(servers, server)
 		}
 		openAPI["servers"] = servers
 	}
 
+	// Curated sections become tags, so other tools show their descriptions and order
+	if a.hasSectionMeta() {
+		tags := make([]map[string]interface{}, 0, len(documentation.Endpoints))
+		for _, section := range documentation.Endpoints {
+			tags = append(tags, map[string]interface{}{
+				"name":        section.Name,
+				"description": section.Description,
+			})
+		}
+		openAPI["tags"] = tags
+	}
+
 	paths := make(map[string]interface{})
-	for _, section := range a.documentation.Endpoints {
+	for _, section := range documentation.Endpoints {
 		for _, endpoint := range section.Endpoints {
 			pathKey := convertPathToOpenAPI(endpoint.Path)
 			if paths[pathKey] == nil {
@@ -389,37 +848,53 @@ func (a *APIDocs) GetOpenAPIJSON() (map[string]interface{}, error) {
 				"responses":   map[string]interface{}{},
 			}
 
+			if endpoint.Deprecated {
+				operation["deprecated"] = true
+			}
+
+			if endpoint.ExternalDocs != nil {
+				operation["externalDocs"] = endpoint.ExternalDocs
+			}
+
+			if endpoint.SLA > 0 {
+				operation["x-sla"] = endpoint.SLA.String()
+			}
+			if endpoint.Pagination != "" {
+				operation["x-pagination"] = endpoint.Pagination
+			}
+			setExtensions(operation, endpoint.Extensions)
+
 			if len(endpoint.Parameters) > 0 {
 				params := make([]map[string]interface{}, 0)
 				for _, param := range endpoint.Parameters {
-					params = append(params, map[string]interface{}{
-						"name":        param.Name,
-						"in":          param.In,
-						"required":    param.Required,
-						"description": param.Description,
-						"schema": map[string]interface{}{
-							"type": normalizeOpenAPIType(param.Type),
-						},
-						"example": param.Example,
-					})
+					if key, ok := sharedParameterRef(param, documentation.SharedParameters); ok {
+						params = append(params, map[string]interface{}{"$ref": "#/components/parameters/" + key})
+						continue
+					}
+					params = append(params, openAPIParameter(param))
 				}
 				operation["parameters"] = params
 			}
 
 			if endpoint.RequestBody != nil {
-				contentType := endpoint.RequestBody.ContentType
-				if contentType == "" {
-					contentType = "application/json"
+				content := make(map[string]interface{})
+				for _, media := range endpoint.RequestBody.Contents() {
+					content[media.ContentType] = map[string]interface{}{
+						"schema":  media.Schema,
+						"example": media.Example,
+					}
 				}
-				operation["requestBody"] = map[string]interface{}{
+				if media, ok := content[endpoint.RequestBody.ContentType].(map[string]interface{}); ok {
+					setMediaExamples(media, endpoint.RequestBody.Examples)
+				}
+				requestBody := map[string]interface{}{
 					"required": endpoint.RequestBody.Required,
-					"content": map[string]interface{}{
-						contentType: map[string]interface{}{
-							"schema":  endpoint.RequestBody.Schema,
-							"example": endpoint.RequestBody.Example,
-						},
-					},
+					"content":  content,
 				}
+				if description := endpoint.RequestBody.description(); description != "" {
+					requestBody["description"] = description
+				}
+				operation["requestBody"] = requestBody
 			}
 
 			responses := make(map[string]interface{})
@@ -428,7 +903,7 @@ func (a *APIDocs) GetOpenAPIJSON() (map[string]interface{}, error) {
 				if respContentType == "" {
 					respContentType = "application/json"
 				}
-				responses[statusCode] = map[string]interface{}{
+				entry := map[string]interface{}{
 					"description": response.Description,
 					"content": map[string]interface{}{
 						respContentType: map[string]interface{}{
@@ -437,6 +912,27 @@ func (a *APIDocs) GetOpenAPIJSON() (map[string]interface{}, error) {
 						},
 					},
 				}
+				setMediaExamples(entry["content"].(map[string]interface{})[respContentType].(map[string]interface{}), response.Examples)
+				if response.Streaming {
+					entry["x-streaming"] = true
+				}
+				if len(response.Headers) > 0 {
+					headers := make(map[string]interface{}, len(response.Headers))
+					for name, header := range response.Headers {
+						headerEntry := map[string]interface{}{
+							"schema": map[string]interface{}{"type": normalizeOpenAPIType(header.Type)},
+						}
+						if header.Description != "" {
+							headerEntry["description"] = header.Description
+						}
+						if header.Example != nil {
+							headerEntry["example"] = header.Example
+						}
+						headers[name] = headerEntry
+					}
+					entry["headers"] = headers
+				}
+				responses[statusCode] = entry
 			}
 			operation["responses"] = responses
 
@@ -446,78 +942,222 @@ func (a *APIDocs) GetOpenAPIJSON() (map[string]interface{}, error) {
 	}
 
 	openAPI["paths"] = paths
+
+	if variants := unionComponents(documentation); len(variants) > 0 {
+		schemas := make(map[string]interface{}, len(documentation.Schemas)+len(variants))
+		for name, schema := range documentation.Schemas {
+			schemas[name] = schema
+		}
+		for name, variant := range variants {
+			if _, exists := schemas[name]; !exists {
+				schemas[name] = variant
+			}
+		}
+		openAPI["components"].(map[string]interface{})["schemas"] = schemas
+	}
+
+	if len(documentation.SharedParameters) > 0 {
+		parameters := make(map[string]interface{}, len(documentation.SharedParameters))
+		for key, param := range documentation.SharedParameters {
+			parameters[key] = openAPIParameter(param)
+		}
+		openAPI["components"].(map[string]interface{})["parameters"] = parameters
+	}
+	setExtensions(openAPI, a.config.Extensions)
+	if err := a.applySpecValidation(openAPI); err != nil {
+		return nil, err
+	}
 	return openAPI, nil
 }
 
-func (a *APIDocs) GetOpenAPIYAML() ([]byte, error) {
-	openAPIMap, err := a.GetOpenAPIJSON()
+// openAPIParameter converts a parameter to an OpenAPI 3 parameter object
+func openAPIParameter(param Parameter) map[string]interface{} {
+	parameter := map[string]interface{}{
+		"name":        param.Name,
+		"in":          param.In,
+		"required":    param.Required,
+		"description": param.Description,
+		"schema": map[string]interface{}{
+			"type": normalizeOpenAPIType(param.Type),
+		},
+		"example": param.Example,
+	}
+	if param.Wildcard {
+		parameter["x-wildcard"] = true
+	}
+	return parameter
+}
+
+// GetOpenAPIJSONForRequest returns the OpenAPI spec, deriving the servers from
+// the incoming request when no base URL is configured. A ?profile=<name>
+// query applies an export profile such as "aws", see RegisterExportProfile,
+// and ?summary=true returns the summarized spec without examples or deep
+// schema bodies.
+func (a *APIDocs) GetOpenAPIJSONForRequest(r *http.Request) (map[string]interface{}, error) {
+	openAPI, err := a.GetOpenAPIJSON()
 	if err != nil {
 		return nil, err
 	}
 
-	yamlBytes, err := yaml.Marshal(openAPIMap)
+	if a.config.BaseURL == "" && len(a.config.BaseURLs) == 0 && r != nil {
+		openAPI["servers"] = []map[string]interface{}{
+			{"url": a.requestServerURL(r)},
+		}
+	}
+
+	profile, err := requestedExportProfile(r)
 	if err != nil {
 		return nil, err
 	}
+	if profile != nil {
+		profile(openAPI)
+	}
 
-	return yamlBytes, nil
+	if wantsSummary(r) {
+		openAPI = summarizeOpenAPI(openAPI)
+	}
+
+	return openAPI, nil
 }
 
-func (a *APIDocs) GetAPIContext() (string, error) {
-	openAPIJSON, err := a.GetOpenAPIJSON()
-	if err != nil {
-		return "", err
+// requestServerURL reconstructs the public URL of the API from the request
+// and the external path prefix. The reverse proxy headers (Forwarded,
+// X-Forwarded-Proto/Host) are only honored from Config.TrustedProxies.
+func (a *APIDocs) requestServerURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	host := r.Host
+	prefix := a.externalPrefix(r)
+	if !fromTrustedProxy(r, a.config.TrustedProxies) {
+		return scheme + "://" + host + prefix
 	}
 
-	jsonBytes, err := json.MarshalIndent(openAPIJSON, "", "  ")
-	if err != nil {
-		return "", err
+	if forwarded := r.Header.Get("Forwarded"); forwarded != "" {
+		// Only the first (client-facing) proxy entry is relevant
+		first := strings.Split(forwarded, ",")[0]
+		for _, pair := range strings.Split(first, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				continue
+			}
+			value = strings.Trim(value, `"`)
+			switch strings.ToLower(key) {
+			case "proto":
+				scheme = value
+			case "host":
+				host = value
+			}
+		}
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = strings.TrimSpace(strings.Split(proto, ",")[0])
+	}
+	if forwardedHost := r.Header.Get("X-Forwarded-Host"); forwardedHost != "" {
+		host = strings.TrimSpace(strings.Split(forwardedHost, ",")[0])
 	}
 
-	context := fmt.Sprintf(`
-=== API SPECIFICATION FOR YOUR REFERENCE ===
+	return scheme + "://" + host + prefix
+}
 
-API Title: %s
-Version: %s
-Description: %s
-Base URLs: %v
+// externalPrefix returns the path prefix the app is reachable under publicly:
+// the prefix of a Registry mount, the X-Forwarded-Prefix header of a trusted
+// proxy, or Config.RootPrefix
+func (a *APIDocs) externalPrefix(r *http.Request) string {
+	if r != nil {
+		if prefix, ok := mountPrefix(r); ok {
+			return prefix
+		}
+		if prefix := r.Header.Get("X-Forwarded-Prefix"); prefix != "" && fromTrustedProxy(r, a.config.TrustedProxies) {
+			return normalizePathPrefix(strings.Split(prefix, ",")[0])
+		}
+	}
+	return normalizePathPrefix(a.config.RootPrefix)
+}
 
-=== COMPLETE OPENAPI JSON SPECIFICATION ===
-%s
+// normalizePathPrefix cleans a path prefix to the "/a/b" form, or "" for the root.
+// Cleaning also collapses "//host" so a prefix can never point at another origin.
+func normalizePathPrefix(prefix string) string {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return ""
+	}
+	prefix = pathpkg.Clean("/" + prefix)
+	if prefix == "/" {
+		return ""
+	}
+	return prefix
+}
 
-=== STRICT INSTRUCTIONS ===
-- ONLY answer programming or API-related questions about the OpenAPI JSON specification above.
-- DO NOT answer questions outside the context of this API or its OpenAPI spec.
-- DO NOT provide information unrelated to the API, its endpoints, or usage.
-- ONLY use the provided OpenAPI JSON as your source of truth.
-- Give code examples, endpoint usage, and parameter details strictly based on the OpenAPI spec.
-- Be precise about required/optional parameters and show real request/response JSON from the spec.
-- DO NOT speculate or invent endpoints, parameters, or behaviors not present in the OpenAPI JSON.
-`,
-		a.documentation.Info.Title,
-		a.documentation.Info.Version,
-		a.documentation.Info.Description,
-		a.config.BaseURLs,
-		string(jsonBytes))
+// docsSubPath returns the request path below DocsPath. The external prefix is
+// stripped first, for proxies that forward it instead of removing it.
+func (a *APIDocs) docsSubPath(r *http.Request) string {
+	requestPath := r.URL.Path
+	if prefix := a.externalPrefix(r); prefix != "" && strings.HasPrefix(requestPath, prefix+a.config.DocsPath) {
+		requestPath = strings.TrimPrefix(requestPath, prefix)
+	}
+	return strings.TrimPrefix(requestPath, a.config.DocsPath)
+}
 
-	return context, nil
+func (a *APIDocs) GetOpenAPIYAML() ([]byte, error) {
+	return a.GetOpenAPIYAMLForRequest(nil)
+}
+
+// GetOpenAPIYAMLForRequest is the YAML counterpart of GetOpenAPIJSONForRequest
+func (a *APIDocs) GetOpenAPIYAMLForRequest(r *http.Request) ([]byte, error) {
+	openAPIMap, err := a.GetOpenAPIJSONForRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	yamlBytes, err := yaml.Marshal(a.orderSpec(openAPIMap))
+	if err != nil {
+		return nil, err
+	}
+
+	return yamlBytes, nil
+}
+
+// GetAPIContext returns the whole API for AI chat prompts, see GetAPIContextFor
+func (a *APIDocs) GetAPIContext() (string, error) {
+	return a.GetAPIContextFor("")
 }
 
 func (a *APIDocs) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, a.config.DocsPath)
-	if strings.HasPrefix(path, "/openapi.json") || strings.HasPrefix(path, "/openapi.yaml") || strings.HasPrefix(path, "/openapi.yml") {
+	a.setSecurityHeaders(w)
+	// Resolved once, so the auth middleware redirects below the same prefix
+	r = withMountPrefix(r, a.externalPrefix(r))
+
+	path := a.docsSubPath(r)
+	// The OpenAPI spec is public for tooling; everything else, including the
+	// other exports, stays behind AuthConfig. Exact paths, since serveDocs
+	// answers unknown ones with the docs page.
+	if path == "/openapi.json" || path == "/openapi.yaml" || path == "/openapi.yml" {
 		a.serveDocs(w, r)
 		return
 	}
+	if strings.TrimPrefix(path, "/") == "refresh" {
+		a.serveRefresh(w, r)
+		return
+	}
+	if trimmed := strings.TrimPrefix(path, "/"); trimmed == "healthz" || trimmed == "readyz" {
+		a.serveHealth(w, r, trimmed == "readyz")
+		return
+	}
 
 	if a.config.AuthConfig != nil && a.config.AuthConfig.Enabled {
-		authMiddleware := AuthMiddleware(a.config.AuthConfig)
-
-		docsHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			a.serveDocs(w, r)
+		// Built once so sessions and failed attempts persist across requests
+		a.authOnce.Do(func() {
+			auth := a.config.AuthConfig
+			if a.cookieScope != "" && auth.CookieScope == "" {
+				scoped := *auth
+				scoped.CookieScope = a.cookieScope
+				auth = &scoped
+			}
+			a.authHandler = authMiddleware(auth, a.config.TrustedProxies)(http.HandlerFunc(a.serveDocs))
 		})
-
-		authMiddleware(docsHandler).ServeHTTP(w, r)
+		a.authHandler.ServeHTTP(w, r)
 		return
 	}
 
@@ -525,12 +1165,9 @@ func (a *APIDocs) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func (a *APIDocs) serveDocs(w http.ResponseWriter, r *http.Request) {
+	a.build()
 
-	if len(a.documentation.Endpoints) == 0 {
-		a.Generate()
-	}
-
-	path := strings.TrimPrefix(r.URL.Path, a.config.DocsPath)
+	path := a.docsSubPath(r)
 	if path == "" {
 		path = "/"
 	} else if !strings.HasPrefix(path, "/") {
@@ -543,13 +1180,47 @@ func (a *APIDocs) serveDocs(w http.ResponseWriter, r *http.Request) {
 	case path == "/api-data.json":
 		w.Header().Set("Content-Type", "application/json")
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		json.NewEncoder(w).Encode(a.documentation)
+		filter, err := parseDocumentationFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		documentation := filterDocumentation(a.GetDocumentation(), filter)
+		if wantsSummary(r) {
+			documentation = summarizeDocumentation(documentation)
+		}
+		body, err := json.Marshal(documentation)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to encode documentation: %v", err), http.StatusInternalServerError)
+			return
+		}
+		a.writeCacheable(w, r, append(body, '\n'))
 	case path == "/chat":
 		a.serveChat(w, r)
+	case path == "/ai/usage":
+		a.serveAIUsage(w, r)
 	case path == "/openapi.json":
 		a.serveOpenAPI(w, r)
 	case path == "/openapi.yaml" || path == "/openapi.yml":
 		a.serveOpenAPIYAML(w, r)
+	case path == "/swagger.json":
+		a.serveSwagger(w, r)
+	case path == "/asyncapi.json":
+		a.serveAsyncAPI(w, r)
+	case path == "/types.ts":
+		a.serveTypeScript(w, r)
+	case path == "/scripts/run":
+		a.serveScripts(w, r)
+	case path == "/lint":
+		a.serveLint(w, r)
+	case path == "/coverage":
+		a.serveCoverage(w, r)
+	case path == "/debug/analysis":
+		a.serveDebugAnalysis(w, r)
+	case path == "/analytics" || path == "/analytics/events":
+		a.serveAnalytics(w, r, path)
+	case path == "/traffic" || path == "/traffic/publish" || path == "/traffic/discard":
+		a.serveTraffic(w, r, path)
 	case strings.HasPrefix(path, "/assets/"):
 		a.serveAsset(w, r, path)
 	default:
@@ -558,8 +1229,22 @@ func (a *APIDocs) serveDocs(w http.ResponseWriter, r *http.Request) {
 }
 
 func (a *APIDocs) serveReactApp(w http.ResponseWriter, r *http.Request) {
-	docsJSON, _ := json.Marshal(a.documentation)
-	configJSON, _ := json.Marshal(a.config)
+	// The UI builds its URLs from docsPath, so serve it with the external prefix
+	prefix := a.externalPrefix(r)
+	config := *a.config
+	config.RootPrefix = prefix
+	config.DocsPath = prefix + a.config.DocsPath
+
+	docsJSON, err := scriptJSON(a.uiDocumentation())
+	if err != nil {
+		http.Error(w, "Failed to encode documentation: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	configJSON, err := scriptJSON(config.Public())
+	if err != nil {
+		http.Error(w, "Failed to encode config: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	// Use embedded template
 	tmpl, err := template.New("docs").Parse(templateHTML)
@@ -569,33 +1254,35 @@ func (a *APIDocs) serveReactApp(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := struct {
-		Title      string
-		DocsPath   string
-		DocsJSON   string
-		ConfigJSON string
-		Config     *Config
+		Title         string
+		DocsPath      string
+		DocsJSON      template.JS
+		ConfigJSON    template.JS
+		Config        *PublicConfig
+		StylesheetURL string
+		ScriptURL     string
 	}{
-		Title:      a.config.Title,
-		DocsPath:   a.config.DocsPath,
-		DocsJSON:   string(docsJSON),
-		ConfigJSON: string(configJSON),
-		Config:     a.config,
+		Title:         a.config.Title,
+		DocsPath:      config.DocsPath,
+		DocsJSON:      docsJSON,
+		ConfigJSON:    configJSON,
+		Config:        config.Public(),
+		StylesheetURL: assetURL(config.DocsPath, "docs.css"),
+		ScriptURL:     assetURL(config.DocsPath, "docs.js"),
 	}
 
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := tmpl.Execute(w, data); err != nil {
+	var page bytes.Buffer
+	if err := tmpl.Execute(&page, data); err != nil {
 		http.Error(w, "Template execution error: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-}
-
-func (a *APIDocs) serveAsset(w http.ResponseWriter, r *http.Request, path string) {
-	http.NotFound(w, r)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	a.writeCacheable(w, r, page.Bytes())
 }
 
 func (a *APIDocs) serveBasicTemplate(w http.ResponseWriter, r *http.Request) {
-	docsJSON, _ := json.Marshal(a.documentation)
-	configJSON, _ := json.Marshal(a.config)
+	docsJSON, _ := scriptJSON(a.uiDocumentation())
+	configJSON, _ := scriptJSON(a.config.Public())
 
 	html := fmt.Sprintf(`<!DOCTYPE html>
 <html lang="en">
@@ -650,8 +1337,9 @@ func (a *APIDocs) serveBasicTemplate(w http.ResponseWriter, r *http.Request) {
     </script>
 </body>
 </html>`,
-		a.config.Title, a.config.Title, a.config.Description, a.config.BaseURL,
-		string(docsJSON), string(configJSON))
+		template.HTMLEscapeString(a.config.Title), template.HTMLEscapeString(a.config.Title),
+		template.HTMLEscapeString(a.config.Description), template.HTMLEscapeString(a.config.BaseURL),
+		docsJSON, configJSON)
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Write([]byte(html))
@@ -662,6 +1350,13 @@ func extractPathParams(path string) []string {
 	parts := strings.Split(path, "/")
 
 	for _, part := range parts {
+		if part == "{$}" {
+			continue
+		}
+		if name, ok := wildcardSegment(part); ok {
+			params = append(params, name)
+			continue
+		}
 		if strings.HasPrefix(part, ":") {
 			params = append(params, strings.TrimPrefix(part, ":"))
 		}
@@ -724,19 +1419,56 @@ func (a *APIDocs) serveChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if chatRequest.Context == "" {
-		apiContext, err := a.GetAPIContext()
+	if chatRequest.Model != "" && !a.config.AIConfig.AllowsModel(chatRequest.Model) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{
+			Error:    fmt.Sprintf("Model %s is not allowed", chatRequest.Model),
+			Provider: a.llmClient.GetProvider(),
+		})
+		return
+	}
+
+	if tmpl, _ := a.promptTemplate(); tmpl != nil {
+		prompt, err := a.GetAPIContextFor(chatRequest.Message)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(ChatResponse{
+				Error:    err.Error(),
+				Provider: a.llmClient.GetProvider(),
+			})
+			return
+		}
+		chatRequest.SystemPrompt = prompt
+	} else if chatRequest.Context == "" {
+		apiContext, err := a.GetAPIContextFor(chatRequest.Message)
 		if err == nil {
 			chatRequest.Context = apiContext
 		}
 	}
 
+	reservation, err := a.reserveAIBudget(a.aiClientKey(r), chatRequest)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(ChatResponse{
+			Error:    err.Error(),
+			Provider: a.llmClient.GetProvider(),
+		})
+		return
+	}
+
 	chatResponse, err := a.llmClient.Chat(r.Context(), chatRequest)
+	a.recordAIUsage(reservation, chatRequest, chatResponse)
+	if r.Context().Err() == nil {
+		// A client that went away says nothing about the provider
+		a.health.recordAI(err)
+	}
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(chatResponse)
 		return
 	}
+	chatResponse.Sources = a.chatSources(chatResponse.Response)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(chatResponse)
@@ -756,13 +1488,23 @@ func (a *APIDocs) serveOpenAPI(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	openAPIJSON, err := a.GetOpenAPIJSON()
+	if _, err := requestedExportProfile(r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	openAPIJSON, err := a.GetOpenAPIJSONForRequest(r)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to generate OpenAPI JSON: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	json.NewEncoder(w).Encode(openAPIJSON)
+	body, err := json.Marshal(a.orderSpec(openAPIJSON))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode OpenAPI JSON: %v", err), http.StatusInternalServerError)
+		return
+	}
+	a.writeCacheable(w, r, append(body, '\n'))
 }
 
 func (a *APIDocs) serveOpenAPIYAML(w http.ResponseWriter, r *http.Request) {
@@ -779,13 +1521,16 @@ func (a *APIDocs) serveOpenAPIYAML(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	openAPIYAML, err := a.GetOpenAPIYAML()
+	if _, err := requestedExportProfile(r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	openAPIYAML, err := a.GetOpenAPIYAMLForRequest(r)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to generate OpenAPI YAML: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	if _, err := w.Write(openAPIYAML); err != nil {
-		http.Error(w, fmt.Sprintf("Failed to write OpenAPI YAML: %v", err), http.StatusInternalServerError)
-	}
+	a.writeCacheable(w, r, openAPIYAML)
 }
@@ -1,6 +1,16 @@
 package core
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
 
 func TestConvertPathToOpenAPI_GorillaMuxRegex(t *testing.T) {
 	in := "/api/v1/users/{id:[0-9]+}"
@@ -17,3 +27,446 @@ func TestConvertPathToOpenAPI_MixedFormats(t *testing.T) {
 		t.Fatalf("expected %s, got %s", expected, got)
 	}
 }
+
+func TestOverrideEndpoint_AppliedAfterDetection(t *testing.T) {
+	docs := New(nil)
+	docs.AddRouteInfo(RouteInfo{Method: "GET", Path: "/api/users/:id", Summary: "detected"})
+	docs.OverrideEndpoint("get", "/api/users/{id}", func(e *Endpoint) {
+		e.Summary = "Get a user"
+		e.Responses["404"] = Response{Description: "User not found"}
+	})
+
+	if err := docs.Generate(); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+
+	endpoint := docs.GetDocumentation().Endpoints[0].Endpoints[0]
+	if endpoint.Summary != "Get a user" {
+		t.Fatalf("expected overridden summary, got %q", endpoint.Summary)
+	}
+	if endpoint.Responses["404"].Description != "User not found" {
+		t.Fatalf("expected overridden 404 response, got %+v", endpoint.Responses["404"])
+	}
+}
+
+func TestRootPrefix_AppliedToServersAndUI(t *testing.T) {
+	docs := New(&Config{Title: "API", Version: "1.0.0", DocsPath: "/docs", RootPrefix: "/myapp/", TrustedProxies: []string{"192.0.2.1"}})
+	docs.AddRoute("GET", "/ping", nil)
+
+	req := httptest.NewRequest("GET", "http://internal:8080/docs/openapi.json", nil)
+	spec, err := docs.GetOpenAPIJSONForRequest(req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if servers := spec["servers"].([]map[string]interface{}); servers[0]["url"] != "http://internal:8080/myapp" {
+		t.Fatalf("expected root prefix in servers, got %v", servers)
+	}
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/", nil))
+	if !strings.Contains(rec.Body.String(), `"docsPath":"/myapp/docs"`) {
+		t.Fatalf("expected prefixed docs path in UI config")
+	}
+
+	// The trusted proxy's header wins and can't point the UI at another origin
+	req = httptest.NewRequest("GET", "/docs/", nil)
+	req.Header.Set("X-Forwarded-Prefix", "//evil.example.com")
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `"docsPath":"/evil.example.com/docs"`) {
+		t.Fatalf("expected forwarded prefix to be cleaned to a path")
+	}
+
+	// Proxies that keep the prefix in the forwarded path are served too
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/myapp/docs/openapi.json", nil))
+	if rec.Code != 200 || !strings.Contains(rec.Body.String(), `"openapi"`) {
+		t.Fatalf("expected spec under the prefixed path, got %d", rec.Code)
+	}
+}
+
+func TestGenerate_SkipsMethodsAndInternalRoutes(t *testing.T) {
+	docs := New(&Config{
+		Title:           "API",
+		DocsPath:        "/docs",
+		SkipMethods:     []string{"trace"},
+		SkipAutoOPTIONS: true,
+	})
+	// Without ExcludePaths the defaults hide detected health checks and static files
+	docs.SetRouteDetector(func() {
+		docs.AddRoute("GET", "/users", nil)
+		docs.AddRoute("HEAD", "/users", nil)
+		docs.AddRoute("OPTIONS", "/users", nil)
+		docs.AddRoute("OPTIONS", "/preflight-only", nil)
+		docs.AddRoute("TRACE", "/users", nil)
+		docs.AddRoute("GET", "/health", nil)
+		docs.AddRoute("GET", "/healthz", nil)
+		docs.AddRoute("GET", "/static/app.js", nil)
+		docs.AddRoute("GET", "/assets/logo.png", nil)
+	})
+
+	if err := docs.Warmup(context.Background()); err != nil {
+		t.Fatalf("warmup failed: %v", err)
+	}
+
+	var documented []string
+	for _, section := range docs.GetDocumentation().Endpoints {
+		for _, endpoint := range section.Endpoints {
+			documented = append(documented, endpoint.Method+" "+endpoint.Path)
+		}
+	}
+	sort.Strings(documented)
+
+	expected := []string{"GET /users", "OPTIONS /preflight-only"}
+	if strings.Join(documented, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected %v, got %v", expected, documented)
+	}
+}
+
+func TestAPIDocs_ConcurrentGenerationAndReads(t *testing.T) {
+	docs := New(nil)
+	docs.AddRoute("GET", "/users", nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(3)
+		go func(i int) {
+			defer wg.Done()
+			docs.AddRoute("GET", fmt.Sprintf("/items/%d", i), nil)
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := docs.GetOpenAPIJSON(); err != nil {
+				t.Errorf("generate failed: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for _, section := range docs.GetDocumentation().Endpoints {
+				_ = len(section.Endpoints)
+			}
+		}()
+	}
+	wg.Wait()
+
+	spec, _ := docs.GetOpenAPIJSON()
+	if paths := spec["paths"].(map[string]interface{}); len(paths) != 9 {
+		t.Fatalf("expected 9 paths after concurrent registration, got %d", len(paths))
+	}
+}
+
+func TestWarmup_RunsDetectorOnceAndSignalsReady(t *testing.T) {
+	docs := New(nil)
+
+	calls := 0
+	docs.SetRouteDetector(func() {
+		calls++
+		docs.AddRoute("GET", "/users", nil)
+	})
+	if docs.IsReady() {
+		t.Fatalf("docs should not be ready before warmup")
+	}
+
+	if err := docs.Warmup(context.Background()); err != nil {
+		t.Fatalf("warmup failed: %v", err)
+	}
+	if !docs.IsReady() {
+		t.Fatalf("docs should be ready after warmup")
+	}
+	if _, err := docs.GetOpenAPIJSON(); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected detector to run once, ran %d times", calls)
+	}
+	if len(docs.GetDocumentation().Endpoints) != 1 {
+		t.Fatalf("expected detected route to be documented")
+	}
+}
+
+func TestSpecKeyOrder_OpenAPIConventionalOrder(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", SpecKeyOrder: SpecKeyOrderOpenAPI})
+	docs.AddRoute("POST", "/users", nil, WithRequest(struct {
+		Type string `json:"type" example:"admin"`
+		Name string `json:"name"`
+	}{}))
+	docs.AddRoute("GET", "/users", nil)
+	docs.AddRoute("GET", "/accounts", nil)
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/openapi.json", nil))
+	body := rec.Body.String()
+	if !strings.HasPrefix(body, `{"openapi":"3.0.3","info":`) {
+		t.Fatalf("expected conventional top-level order, got %.80s", body)
+	}
+	if strings.Index(body, `"get":`) > strings.Index(body, `"post":`) {
+		t.Fatalf("expected get before post")
+	}
+	if strings.Index(body, `"/accounts"`) > strings.Index(body, `"/users"`) {
+		t.Fatalf("expected sorted paths")
+	}
+	// Property names are user names and stay sorted alphabetically
+	if strings.Index(body, `"name":{`) > strings.Index(body, `"type":{`) {
+		t.Fatalf("expected alphabetical property names")
+	}
+
+	yamlBody, err := docs.GetOpenAPIYAML()
+	if err != nil {
+		t.Fatalf("yaml failed: %v", err)
+	}
+	if !strings.HasPrefix(string(yamlBody), "openapi: 3.0.3\ninfo:") {
+		t.Fatalf("expected conventional yaml order, got %.80s", yamlBody)
+	}
+
+	sections := docs.GetDocumentation().Endpoints
+	if len(sections) != 2 || sections[0].ID > sections[1].ID {
+		t.Fatalf("expected sections sorted by id, got %+v", sections)
+	}
+}
+
+func TestGroupResponses_InjectedUnderPrefix(t *testing.T) {
+	docs := New(nil)
+	docs.AddRoute("GET", "/api/users/:id", nil, WithResponse(200, map[string]string{"id": "1"}))
+	docs.AddRoute("GET", "/api/login", nil, WithResponse(200, "ok"), WithResponse(401, map[string]string{"error": "bad credentials"}))
+	docs.AddRoute("GET", "/apix/health", nil, WithResponse(200, "ok"))
+	docs.AddRecoveryResponse("")
+	docs.AddAuthResponses("/api")
+
+	if err := docs.Generate(); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+
+	byPath := map[string]Endpoint{}
+	for _, section := range docs.GetDocumentation().Endpoints {
+		for _, endpoint := range section.Endpoints {
+			byPath[endpoint.Path] = endpoint
+		}
+	}
+
+	users := byPath["/api/users/{id}"].Responses
+	if users["401"].Description != "Unauthorized" || users["403"].Description != "Forbidden" || users["500"].Description == "" {
+		t.Fatalf("expected injected auth and recovery responses, got %+v", users)
+	}
+	if byPath["/api/login"].Responses["401"].Example == nil {
+		t.Fatalf("expected the explicit 401 to win over the group response")
+	}
+	health := byPath["/apix/health"].Responses
+	if _, ok := health["401"]; ok {
+		t.Fatalf("prefix must match whole path segments, got %+v", health)
+	}
+	if _, ok := health["500"]; !ok {
+		t.Fatalf("expected the global recovery response, got %+v", health)
+	}
+}
+
+func TestExternalDocs_MappedToOpenAPIAndEndpoint(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs"})
+	docs.AddRoute("POST", "/refunds", nil, WithExternalDocs("https://wiki.example.com/refunds", "Refund policy"))
+	docs.AddRoute("GET", "/refunds", nil)
+
+	spec, err := docs.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := json.Marshal(spec)
+	if !strings.Contains(string(body), `"externalDocs":{"url":"https://wiki.example.com/refunds","description":"Refund policy"}`) {
+		t.Fatalf("expected externalDocs on the operation, got %s", body)
+	}
+	if strings.Count(string(body), "externalDocs") != 1 {
+		t.Fatalf("expected externalDocs only where set, got %s", body)
+	}
+
+	endpoint := docs.GetDocumentation().Endpoints[0].Endpoints[0]
+	if endpoint.Method == "GET" {
+		endpoint = docs.GetDocumentation().Endpoints[0].Endpoints[1]
+	}
+	if endpoint.ExternalDocs == nil || endpoint.ExternalDocs.URL != "https://wiki.example.com/refunds" {
+		t.Fatalf("expected the UI data to carry externalDocs, got %+v", endpoint.ExternalDocs)
+	}
+}
+
+func TestAnalysisWarnings_CollectedIntoDocumentation(t *testing.T) {
+	docs := New(&Config{DocsPath: "/docs"})
+	docs.AddRouteInfo(RouteInfo{Method: "POST", Path: "/users/:id", Warnings: []string{"analysis of handlers failed: handlers/users.go:12:1: expected '}'"}})
+	docs.AddRoute("GET", "/users", nil)
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	warnings := docs.GetDocumentation().Warnings
+	if len(warnings) != 1 || warnings[0].Method != "POST" || warnings[0].Path != "/users/{id}" || !strings.Contains(warnings[0].Message, "expected '}'") {
+		t.Fatalf("expected the route warning with the documented path, got %+v", warnings)
+	}
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/api-data.json?method=GET", nil))
+	if !strings.Contains(rec.Body.String(), `"warnings":[{"method":"POST","path":"/users/{id}"`) {
+		t.Fatalf("expected filtered docs to keep the warnings, got %s", rec.Body.String())
+	}
+	if health := docs.Health(); health.Warnings != 1 {
+		t.Fatalf("expected /healthz to count the warnings, got %d", health.Warnings)
+	}
+}
+
+func TestOnGenerate_HooksEditDocumentation(t *testing.T) {
+	docs := New(&Config{DocsPath: "/docs"})
+	docs.AddRoute("GET", "/users", nil)
+	docs.AddRoute("GET", "/orders", nil)
+	runs := 0
+	docs.OnGenerate(func(d *Documentation) {
+		runs++
+		for i := range d.Endpoints {
+			for j := range d.Endpoints[i].Endpoints {
+				d.Endpoints[i].Endpoints[j].Description = ""
+			}
+		}
+		sort.SliceStable(d.Endpoints, func(i, j int) bool { return d.Endpoints[i].Name == "Users" })
+		d.Endpoints[0].Endpoints = append(d.Endpoints[0].Endpoints, Endpoint{
+			ID: "get-users-count", Method: "GET", Path: "/users/count", Summary: "Count users",
+			Responses: map[string]Response{"200": {Description: "Success"}},
+		})
+		d.Schemas["Count"] = Schema{Type: "object"}
+	})
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	documentation := docs.GetDocumentation()
+	if len(documentation.Endpoints) != 2 || documentation.Endpoints[0].Name != "Users" || len(documentation.Endpoints[0].Endpoints) != 2 {
+		t.Fatalf("expected the hook to reorder sections and add an endpoint, got %+v", documentation.Endpoints)
+	}
+	if documentation.Endpoints[1].Endpoints[0].Description != "" {
+		t.Fatalf("expected the hook to strip descriptions, got %q", documentation.Endpoints[1].Endpoints[0].Description)
+	}
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/openapi.json", nil))
+	if body := rec.Body.String(); !strings.Contains(body, `"/users/count"`) || !strings.Contains(body, `"Count":{`) {
+		t.Fatalf("expected the spec to include the hook's edits, got %s", body)
+	}
+
+	docs.AddRoute("DELETE", "/users/:id", nil)
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	if runs != 2 {
+		t.Fatalf("expected the hook to run on every generation, ran %d times", runs)
+	}
+}
+
+func TestResponseHeaders_DocumentedInSpecs(t *testing.T) {
+	docs := New(&Config{DocsPath: "/docs"})
+	docs.AddRouteInfo(RouteInfo{Method: "GET", Path: "/login", Responses: map[string]Response{
+		"303": {Description: "See Other", ContentType: "text/html", Schema: map[string]interface{}{"type": "string"},
+			Headers: map[string]ResponseHeader{"Location": {Description: "URL the client is redirected to", Type: "string", Example: "/dashboard"}}},
+	}})
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := docs.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(spec)
+	if !strings.Contains(string(raw), `"headers":{"Location":{"description":"URL the client is redirected to","example":"/dashboard","schema":{"type":"string"}}}`) {
+		t.Fatalf("expected the Location header in the OpenAPI response, got %s", raw)
+	}
+	swagger, err := docs.GetSwaggerJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := json.Marshal(swagger); !strings.Contains(string(raw), `"headers":{"Location":{"description":"URL the client is redirected to","type":"string"}}`) {
+		t.Fatalf("expected the Location header in the Swagger response, got %s", raw)
+	}
+}
+
+func TestStreamingResponses_MarkedInSpecs(t *testing.T) {
+	docs := New(&Config{DocsPath: "/docs"})
+	docs.AddRouteInfo(RouteInfo{Method: "GET", Path: "/reports/{id}", Responses: map[string]Response{
+		"200": {Description: "OK", ContentType: "application/pdf", Schema: map[string]interface{}{"type": "string", "format": "binary"}, Streaming: true},
+	}})
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := docs.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(spec)
+	if !strings.Contains(string(raw), `"application/pdf":{"example":null,"schema":{"format":"binary","type":"string"}}`) || !strings.Contains(string(raw), `"x-streaming":true`) {
+		t.Fatalf("expected a streamed binary PDF response, got %s", raw)
+	}
+	swagger, err := docs.GetSwaggerJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := json.Marshal(swagger); !strings.Contains(string(raw), `"x-streaming":true`) {
+		t.Fatalf("expected the Swagger response to be marked streaming, got %s", raw)
+	}
+}
+
+func TestServeHTTP_ExportsStayBehindAuth(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", AuthConfig: &AuthConfig{Enabled: true, Type: "basic", Username: "u", Password: "p"}})
+	docs.AddRoute("GET", "/users", nil)
+
+	serve := func(path string, login bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if login {
+			req.SetBasicAuth("u", "p")
+		}
+		rec := httptest.NewRecorder()
+		docs.ServeHTTP(rec, req)
+		return rec
+	}
+	for _, path := range []string{"/docs/asyncapi.json", "/docs/asyncapi.json.x", "/docs/swagger.json", "/docs/swagger.jsonx",
+		"/docs/types.ts", "/docs/types.tsx", "/docs/openapi.json.x"} {
+		if rec := serve(path, false); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected %s to require auth, got %d", path, rec.Code)
+		}
+	}
+	for _, path := range []string{"/docs/asyncapi.json", "/docs/swagger.json", "/docs/types.ts"} {
+		if rec := serve(path, true); rec.Code != http.StatusOK {
+			t.Fatalf("expected %s after login, got %d", path, rec.Code)
+		}
+	}
+	if rec := serve("/docs/openapi.json", false); rec.Code != http.StatusOK {
+		t.Fatalf("expected the OpenAPI spec public, got %d", rec.Code)
+	}
+}
+
+func TestGenerate_ExcludePathsMatchSegmentsAndGlobs(t *testing.T) {
+	docs := New(&Config{
+		Title:           "API",
+		DocsPath:        "/docs",
+		BaseURL:         "http://localhost:8080",
+		ExcludePaths:    []string{"health", "debug*", "/internal/*/metrics", "/static"},
+		SkipAutoOPTIONS: true,
+	})
+	for _, path := range []string{
+		"/health", "/api/health/live", "/api/healthcare", "/debug", "/debugger/vars",
+		"/internal/orders/metrics", "/internal/orders/metrics/raw", "/internal/orders",
+		"/static/app.js", "/api/static", "/assets/logo.png",
+	} {
+		docs.AddRoute("GET", path, nil)
+	}
+	docs.AddRoute("HEAD", "/assets/logo.png", nil)
+
+	if err := docs.Generate(); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+
+	var documented []string
+	for _, section := range docs.GetDocumentation().Endpoints {
+		for _, endpoint := range section.Endpoints {
+			documented = append(documented, endpoint.Method+" "+endpoint.Path)
+		}
+	}
+	sort.Strings(documented)
+
+	expected := []string{"GET /api/healthcare", "GET /api/static", "GET /assets/logo.png", "GET /internal/orders"}
+	if strings.Join(documented, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected %v, got %v", expected, documented)
+	}
+}
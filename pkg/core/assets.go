@@ -0,0 +1,136 @@
+package core
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"mime"
+	"net/http"
+	pathpkg "path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed assets
+var assetFiles embed.FS
+
+// immutableCacheControl lets browsers keep content-hashed assets for a year;
+// a changed asset gets a new name, so it is never served stale
+const immutableCacheControl = "public, max-age=31536000, immutable"
+
+// uiAsset is an embedded stylesheet or script of the docs UI
+type uiAsset struct {
+	name        string // content-hashed name, e.g. docs.3f2a9c1d04b7e6a8.js
+	contentType string
+	content     []byte
+	etag        string
+
+	once       sync.Once
+	compressed map[string][]byte // encoded bodies by content encoding
+}
+
+var (
+	assetsOnce     sync.Once
+	assetsByName   map[string]*uiAsset // hashed and logical names
+	hashedAssetURL map[string]string   // logical name -> hashed name
+)
+
+// loadAssets indexes the embedded assets by their logical and content-hashed names
+func loadAssets() {
+	assetsByName = make(map[string]*uiAsset)
+	hashedAssetURL = make(map[string]string)
+
+	entries, _ := fs.ReadDir(assetFiles, "assets")
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		content, err := assetFiles.ReadFile("assets/" + entry.Name())
+		if err != nil {
+			continue
+		}
+
+		sum := sha256.Sum256(content)
+		hash := hex.EncodeToString(sum[:8])
+		ext := pathpkg.Ext(entry.Name())
+		asset := &uiAsset{
+			name:        strings.TrimSuffix(entry.Name(), ext) + "." + hash + ext,
+			contentType: mime.TypeByExtension(ext),
+			content:     content,
+			etag:        `"` + hash + `"`,
+		}
+		if asset.contentType == "" {
+			asset.contentType = "application/octet-stream"
+		}
+
+		assetsByName[entry.Name()] = asset
+		assetsByName[asset.name] = asset
+		hashedAssetURL[entry.Name()] = asset.name
+	}
+}
+
+// assetURL returns the content-hashed URL of a UI asset below docsPath
+func assetURL(docsPath, name string) string {
+	assetsOnce.Do(loadAssets)
+	if hashed, ok := hashedAssetURL[name]; ok {
+		name = hashed
+	}
+	return docsPath + "/assets/" + name
+}
+
+// encoded returns the asset body for the content encoding, compressing it once
+// with the best compression level on first use
+func (asset *uiAsset) encoded(encoding string) []byte {
+	asset.once.Do(func() {
+		asset.compressed = make(map[string][]byte, 2)
+		for _, candidate := range []string{"br", "gzip"} {
+			if body, err := compressBody(asset.content, candidate, true); err == nil && len(body) < len(asset.content) {
+				asset.compressed[candidate] = body
+			}
+		}
+	})
+	return asset.compressed[encoding]
+}
+
+// serveAsset serves an embedded UI asset. Content-hashed names are cached as
+// immutable; logical names (docs.js) are revalidated on every load.
+func (a *APIDocs) serveAsset(w http.ResponseWriter, r *http.Request, path string) {
+	assetsOnce.Do(loadAssets)
+	name := strings.TrimPrefix(path, "/assets/")
+	asset, ok := assetsByName[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", asset.contentType)
+	header.Set("ETag", asset.etag)
+	header.Add("Vary", "Accept-Encoding")
+	if name == asset.name {
+		header.Set("Cache-Control", immutableCacheControl)
+	} else {
+		header.Set("Cache-Control", "no-cache")
+	}
+
+	if etagMatches(r.Header.Get("If-None-Match"), asset.etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	body := asset.content
+	if encoding := negotiateEncoding(r, "br", "gzip"); encoding != "" {
+		if compressed := asset.encoded(encoding); compressed != nil {
+			header.Set("Content-Encoding", encoding)
+			body = compressed
+		}
+	}
+
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(body)
+}
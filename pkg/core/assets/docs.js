@@ -0,0 +1,5097 @@
+        function escapeHtml(str) {
+            return str
+                .replace(/&/g, '&amp;')
+                .replace(/</g, '&lt;')
+                .replace(/>/g, '&gt;');
+        }
+        function syntaxHighlight(jsonString) {
+
+            const safe = escapeHtml(jsonString);
+
+            return safe.replace(/("(?:[^\\"]|\\.)*")\s*:|("(?:[^\\"]|\\.)*")|(\btrue\b|\bfalse\b|\bnull\b)|(-?\d+(?:\.\d+)?(?:[eE][+-]?\d+)?)|(\{)|(\})|(\[)|(\])|,/g, function(match, key, string, bool, number, openBrace, closeBrace, openBracket, closeBracket) {
+                if (key) {
+
+                    return key.replace(/^("(?:[^\\"]|\\.)*")(\s*)$/, '<span class="json-key">$1</span>$2') + '<span class="json-colon">:</span>';
+                }
+                if (string) {
+
+                    return '<span class="json-string">' + string + '</span>';
+                }
+                if (bool) {
+
+                    if (bool === 'null') {
+                        return '<span class="json-null">' + bool + '</span>';
+                    } else {
+                        return '<span class="json-boolean">' + bool + '</span>';
+                    }
+                }
+                if (number) {
+
+                    return '<span class="json-number">' + number + '</span>';
+                }
+                if (openBrace) {
+                    return '<span class="json-brace">{</span>';
+                }
+                if (closeBrace) {
+                    return '<span class="json-brace">}</span>';
+                }
+                if (openBracket) {
+                    return '<span class="json-bracket">[</span>';
+                }
+                if (closeBracket) {
+                    return '<span class="json-bracket">]</span>';
+                }
+                if (match === ',') {
+                    return '<span class="json-comma">,</span>';
+                }
+                return match;
+            });
+        }
+
+        function copyToClipboard(text, button) {
+            navigator.clipboard.writeText(text).then(() => {
+
+                const originalHTML = button.innerHTML;
+                const originalClasses = button.className;
+                button.className = button.className.replace('border-gray-300 dark:border-white', 'border-accent bg-accent-light dark:dark-accent-bg');
+                button.innerHTML = `
+                    <svg class="w-3 h-3" viewBox="0 0 24 24">
+                        <use href="#check-icon"></use>
+                    </svg>
+                    Copied!
+                `;
+
+                setTimeout(() => {
+                    button.className = originalClasses;
+                    button.innerHTML = originalHTML;
+                }, 2000);
+            }).catch(err => {
+                console.error('Failed to copy: ', err);
+
+                const textArea = document.createElement('textarea');
+                textArea.value = text;
+                document.body.appendChild(textArea);
+                textArea.select();
+                document.execCommand('copy');
+                document.body.removeChild(textArea);
+
+                const originalClasses = button.className;
+                button.className = button.className.replace('border-gray-300 dark:border-white', 'border-accent bg-accent-light dark:dark-accent-bg');
+                setTimeout(() => {
+                    button.className = originalClasses;
+                }, 2000);
+            });
+        }
+
+        function createJsonViewer(jsonString, title = 'JSON') {
+            const copyId = 'copy_' + Math.random().toString(36).substr(2, 9);
+            const beautifyId = 'beautify_' + Math.random().toString(36).substr(2, 9);
+
+            let parsedJson;
+            try {
+                parsedJson = JSON.parse(jsonString);
+            } catch (e) {
+                parsedJson = jsonString
+            }
+            const beautifiedJson = JSON.stringify(parsedJson, null, 2);
+            const compactJson = JSON.stringify(parsedJson);
+            return `
+                <div class="bg-gray-50 dark:bg-[#212121] border border-gray-200 dark:border-0 rounded-xl p-4 font-mono text-sm overflow-x-auto mb-4 relative" data-beautify-id="${beautifyId}">
+                    <div class="flex justify-between items-center mb-3 pb-2 border-b border-gray-200 dark:border-0">
+                        <span class="text-xs font-semibold text-gray-600 dark:text-gray-300 uppercase tracking-wide">${title}</span>
+                        <div class="flex gap-2">
+                            <button class="flex items-center gap-1 px-2 py-1 border border-gray-300 rounded-md text-xs text-gray-600 dark:text-gray-300 hover:bg-gray-100 dark:hover:bg-green-800 transition-colors duration-200 dark:border-0 dark:bg-black" data-copy-text="${beautifiedJson.replace(/"/g, '&quot;')}" data-copy-id="${copyId}">
+                                <svg class="w-3 h-3" viewBox="0 0 24 24">
+                                    <use href="#copy-icon"></use>
+                                </svg>
+                                Copy
+                            </button>
+                            <button class="flex items-center gap-1 px-2 py-1 border border-gray-300 dark:border-white rounded-md text-xs text-gray-600 dark:text-gray-300 hover:bg-gray-100 dark:hover:bg-green-800 transition-colors duration-200 dark:border-0 dark:bg-black" data-beautify-target="${beautifyId}" data-beautified="${beautifiedJson.replace(/"/g, '&quot;')}" data-compact="${compactJson.replace(/"/g, '&quot;')}" data-is-beautified="true">
+                                <svg class="w-3 h-3" viewBox="0 0 24 24">
+                                    <path d="M3 3h18v2H3V3zm0 4h18v2H3V7zm0 4h18v2H3v-2zm0 4h18v2H3v-2z" fill="currentColor"/>
+                                </svg>
+                                Compact
+                            </button>
+                        </div>
+                    </div>
+                    <pre class="m-0 p-0 bg-transparent border-0 text-wrap text-gray-900 dark:text-white"><code class="json-content bg-transparent p-0 text-inherit">${syntaxHighlight(beautifiedJson)}</code></pre>
+                </div>
+            `;
+        }
+
+        function transformApiData(backendData) {
+            const transformed = {};
+            if (backendData.endpoints) {
+                backendData.endpoints.forEach(section => {
+                    const sectionName = section.name.toLowerCase();
+                    transformed[sectionName] = section.endpoints.map(endpoint => ({
+                        id: endpoint.id,
+                        method: endpoint.method,
+                        path: endpoint.path,
+                        title: endpoint.summary,
+                        description: endpoint.description || 'No description available',
+                        parameters: endpoint.parameters || [],
+                        requestBody: endpoint.requestBody || null,
+                        responses: endpoint.responses || {},
+                        externalDocs: endpoint.externalDocs || null,
+                        presets: endpoint.presets || [],
+                        sla: endpoint.sla || 0,
+                        graphql: endpoint.graphql || false
+                    }));
+                });
+            }
+            return transformed;
+        }
+        const transformedApiData = transformApiData(apiData);
+
+        let currentEndpoint = null;
+        let requestContentType = null; // selected request body content type; null uses the primary one
+        let bodyVariant = null; // selected discriminator value of a union request body; null uses the first variant
+        let testMode = 'form'; // "form", "raw" or "graphql", see switchTestMode
+        const graphqlStates = {}; // query, variables and operation name typed per GraphQL endpoint
+        let filteredEndpoints = [];
+        let settings = {
+            darkMode: false,
+            compactMode: false,
+            keepCookies: false
+        };
+        let auth = {
+            type: 'none',
+            token: '',
+            username: '',
+            password: '',
+            apiKey: '',
+            keyName: 'X-API-Key'
+        };
+
+        const sidebar = document.getElementById('sidebar');
+        const searchInput = document.getElementById('searchInput');
+        const searchClear = document.getElementById('searchClear');
+        const searchResults = document.getElementById('searchResults');
+        const searchCount = document.getElementById('searchCount');
+        const endpointsContainer = document.getElementById('endpointsContainer');
+        const baseUrlSelect = document.getElementById('baseUrlSelect');
+        const currentMethod = document.getElementById('currentMethod');
+        const currentUrl = document.getElementById('currentUrl');
+        const endpointDescription = document.getElementById('endpointDescription');
+        const endpointExternalDocs = document.getElementById('endpointExternalDocs');
+        const endpointSLA = document.getElementById('endpointSLA');
+        const parametersContent = document.getElementById('parametersContent');
+        const bodyContent = document.getElementById('bodyContent');
+        const responsesContent = document.getElementById('responsesContent');
+        const testButton = document.getElementById('testButton');
+        const responseContainer = document.getElementById('responseContainer');
+        const responseStatus = document.getElementById('responseStatus');
+        const responseTime = document.getElementById('responseTime');
+        const responseBody = document.getElementById('responseBody');
+
+        const mobileMenuBtn = document.getElementById('mobileMenuBtn');
+        const sidebarOverlay = document.getElementById('sidebarOverlay');
+
+        const settingsModal = document.getElementById('settingsModal');
+        const authModal = document.getElementById('authModal');
+        const settingsBtnSidebar = document.getElementById('settingsBtnSidebar');
+        const authBtn = document.getElementById('authBtn');
+        const authBtnDesktop = document.getElementById('authBtnDesktop');
+        const baseUrlSelectDesktop = document.getElementById('baseUrlSelectDesktop');
+        const closeSettings = document.getElementById('closeSettings');
+        const closeAuth = document.getElementById('closeAuth');
+
+    const darkModeToggle = document.getElementById('darkModeToggle');
+    const compactModeToggle = document.getElementById('compactModeToggle');
+    const keepCookiesToggle = document.getElementById('keepCookiesToggle');
+
+        const authType = document.getElementById('authType');
+        const authInputs = document.getElementById('authInputs');
+        const saveAuth = document.getElementById('saveAuth');
+
+        function init() {
+
+            function populateBaseUrlSelects() {
+                const selects = [baseUrlSelect, baseUrlSelectDesktop];
+                selects.forEach(select => {
+                    if (!select) return;
+                    select.innerHTML = '';
+
+                    const currentOriginOption = document.createElement('option');
+                    currentOriginOption.value = currentOrigin;
+                    currentOriginOption.textContent = `Current - ${currentOrigin}`;
+                    currentOriginOption.selected = true;
+                    select.appendChild(currentOriginOption);
+
+                    if (config && config.baseUrls && config.baseUrls.length > 0) {
+
+                        config.baseUrls.forEach((baseUrlOption, index) => {
+                            const option = document.createElement('option');
+                            option.value = resolveServerUrl(baseUrlOption);
+                            option.textContent = `${baseUrlOption.name} - ${option.value}`;
+                            option.dataset.serverIndex = index;
+                            select.appendChild(option);
+                        });
+                    } else if (config && config.baseUrl) {
+
+                        const option = document.createElement('option');
+                        option.value = config.baseUrl;
+                        option.textContent = `Config - ${config.baseUrl}`;
+                        select.appendChild(option);
+                    } else {
+
+                        const defaultOptions = [
+                            { name: 'Production', url: 'https://api.example.com' },
+                            { name: 'Staging', url: 'https://staging-api.example.com' },
+                            { name: 'Development', url: 'https://dev-api.example.com' },
+                            { name: 'Local', url: 'http://localhost:8080' }
+                        ];
+                        defaultOptions.forEach((baseUrlOption) => {
+                            const option = document.createElement('option');
+                            option.value = baseUrlOption.url;
+                            option.textContent = `${baseUrlOption.name} - ${baseUrlOption.url}`;
+                            select.appendChild(option);
+                        });
+                    }
+                });
+            }
+            populateBaseUrlSelects();
+            renderServerVariables(baseUrlSelect);
+
+            filteredEndpoints = Object.values(transformedApiData).flat();
+            renderEndpoints();
+            setupEventListeners();
+            loadSettings();
+            loadAuthentication();
+            selectFirstEndpoint();
+            initThemeColor();
+
+            document.getElementById('chatAIToggle').addEventListener('click', toggleChatSidebar);
+            if (config && config.analytics) {
+                document.getElementById('analyticsBtn').classList.replace('hidden', 'flex');
+                document.addEventListener('visibilitychange', () => {
+                    if (document.visibilityState === 'hidden') flushUsage();
+                });
+            }
+            if (apiData.warnings && apiData.warnings.length > 0) {
+                document.getElementById('diagnosticsCount').textContent = `(${apiData.warnings.length})`;
+                document.getElementById('diagnosticsBtn').classList.replace('hidden', 'flex');
+            }
+
+            setupSidebarResize();
+            setupLeftSidebarResize();
+            document.getElementById('closeChatSidebar').addEventListener('click', toggleChatSidebar);
+            document.getElementById('sendChatMessage').addEventListener('click', sendChatMessage);
+
+            (function setupChatInput() {
+                const chatInput = document.getElementById('chatInput');
+                if (!chatInput) return;
+
+                const MIN_HEIGHT = 50;
+                const MAX_HEIGHT = 100;
+                let raf = null;
+                function setRounded(el, atMax) {
+                    el.classList.remove('rounded-full', 'rounded-2xl');
+                    el.classList.add(atMax ? 'rounded-2xl' : 'rounded-full');
+                }
+                function autoResize(el) {
+
+                    if (raf) cancelAnimationFrame(raf);
+                    raf = requestAnimationFrame(() => {
+                        el.style.height = 'auto';
+                        const scroll = el.scrollHeight;
+                        const containsNewline = /\n/.test(el.value);
+                        const shouldMax = containsNewline || scroll > MAX_HEIGHT;
+                        const newHeight = shouldMax ? MAX_HEIGHT : Math.max(scroll, MIN_HEIGHT);
+                        el.style.height = newHeight + 'px';
+                        setRounded(el, shouldMax);
+                    });
+                }
+
+                autoResize(chatInput, true);
+
+                chatInput.addEventListener('input', (e) => {
+                    autoResize(e.target);
+                });
+
+                chatInput.addEventListener('paste', (e) => {
+                    setTimeout(() => {
+
+                        chatInput.value = chatInput.value.replace(/[ \t]{3,}/g, ' ');
+
+                        autoResize(chatInput);
+                    }, 0);
+                });
+
+                chatInput.addEventListener('keydown', (e) => {
+                    if (e.key === 'Enter') {
+                        if (!e.shiftKey) {
+                            e.preventDefault();
+                            sendChatMessage();
+                        } else {
+
+                            setTimeout(() => autoResize(chatInput, true), 0);
+                        }
+                    }
+                });
+
+                const origToggle = toggleChatSidebar;
+            })();
+        }
+
+        const collapsedNavGroups = new Set(JSON.parse(localStorage.getItem('bytedocs-nav-collapsed') || '[]'));
+
+        function resourceSegments(path) {
+            const parts = path.split('/').filter(part => part && !part.startsWith(':') && !part.startsWith('*') && !part.includes('{'));
+            while (parts.length > 0 && (parts[0] === 'api' || /^v\d+(\.\d+)*$/.test(parts[0]))) {
+                parts.shift();
+            }
+            return parts;
+        }
+
+        // buildNavigationTree nests endpoints by their resource path, so
+        // /users/{id}/orders/{orderId}/items sits under Users → Orders → Items
+        function buildNavigationTree(endpoints) {
+            const root = { name: '', children: new Map(), endpoints: [], count: 0 };
+            endpoints.forEach(endpoint => {
+                let node = root;
+                node.count++;
+                resourceSegments(endpoint.path).forEach(segment => {
+                    if (!node.children.has(segment)) {
+                        node.children.set(segment, { name: segment, children: new Map(), endpoints: [], count: 0 });
+                    }
+                    node = node.children.get(segment);
+                    node.count++;
+                });
+                node.endpoints.push(endpoint);
+            });
+            return root;
+        }
+
+        // collapseNavigationNode merges chains of groups without own endpoints,
+        // e.g. "Admin / Reports" instead of Admin holding only Reports
+        function collapseNavigationNode(node) {
+            while (node.endpoints.length === 0 && node.children.size === 1) {
+                const child = node.children.values().next().value;
+                node = { ...child, name: node.name ? `${node.name} / ${child.name}` : child.name };
+            }
+            return node;
+        }
+
+        function formatNavigationName(name) {
+            return name.split(' / ').map(part => part.charAt(0).toUpperCase() + part.slice(1).replace(/[-_]/g, ' ')).join(' / ');
+        }
+
+        function createEndpointItem(endpoint, depth) {
+            const itemDiv = document.createElement('div');
+            itemDiv.className = 'px-6 py-3 cursor-pointer border-l-3 border-transparent hover:bg-gray-100 dark:hover:bg-[#171717] hover:border-accent transition-all duration-200';
+            if (depth > 0) itemDiv.style.paddingLeft = `${1.5 + depth * 0.75}rem`;
+            itemDiv.dataset.endpointId = endpoint.id;
+            itemDiv.innerHTML = `
+                <div class="flex items-center gap-3 mb-1">
+                    <div class="inline-block px-2 py-1 rounded text-xs font-semibold text-center min-w-16 method-${endpoint.method.toLowerCase()}">${endpoint.method}</div>
+                    <div class="font-mono text-sm text-gray-900 dark:text-white">${endpoint.path}</div>
+                </div>
+                <div class="text-xs text-gray-600 dark:text-gray-300 endpoint-description" style="display: ${settings.compactMode ? 'none' : 'block'}">${getEndpointDescription(endpoint)}</div>
+            `;
+            itemDiv.addEventListener('click', () => {
+                selectEndpoint(endpoint);
+                trackUsage({ type: 'view', method: endpoint.method, path: endpoint.path });
+            });
+            return itemDiv;
+        }
+
+        function renderNavigationNode(container, node, key, depth, expandAll) {
+            node.endpoints.forEach(endpoint => container.appendChild(createEndpointItem(endpoint, depth)));
+            node.children.forEach(child => {
+                child = collapseNavigationNode(child);
+                const groupKey = `${key}/${child.name}`;
+                const groupDiv = document.createElement('div');
+                groupDiv.className = 'nav-group';
+                groupDiv.dataset.navGroup = groupKey;
+                if (!expandAll && collapsedNavGroups.has(groupKey)) groupDiv.classList.add('collapsed');
+
+                const toggle = document.createElement('button');
+                toggle.type = 'button';
+                toggle.className = 'nav-group-toggle w-full flex items-center gap-2 py-2 pr-6 text-left text-sm font-medium text-gray-700 dark:text-gray-200 hover:bg-gray-100 dark:hover:bg-[#171717]';
+                toggle.style.paddingLeft = `${1.5 + depth * 0.75}rem`;
+                toggle.innerHTML = `
+                    <svg class="nav-group-chevron w-3 h-3 flex-shrink-0" viewBox="0 0 24 24"><path d="M8 5l8 7-8 7" stroke="currentColor" stroke-width="2.5" fill="none"/></svg>
+                    <span class="flex-1 truncate"></span>
+                    <span class="nav-group-count text-xs text-gray-500 dark:text-gray-400">${child.count}</span>
+                `;
+                toggle.querySelector('span').textContent = formatNavigationName(child.name);
+                toggle.setAttribute('aria-expanded', String(!groupDiv.classList.contains('collapsed')));
+                toggle.addEventListener('click', () => toggleNavGroup(groupDiv));
+                groupDiv.appendChild(toggle);
+
+                const childrenDiv = document.createElement('div');
+                childrenDiv.className = 'nav-group-children';
+                renderNavigationNode(childrenDiv, child, groupKey, depth + 1, expandAll);
+                groupDiv.appendChild(childrenDiv);
+                container.appendChild(groupDiv);
+            });
+        }
+
+        function toggleNavGroup(groupDiv, collapsed = !groupDiv.classList.contains('collapsed')) {
+            groupDiv.classList.toggle('collapsed', collapsed);
+            groupDiv.querySelector('.nav-group-toggle').setAttribute('aria-expanded', String(!collapsed));
+            if (collapsed) {
+                collapsedNavGroups.add(groupDiv.dataset.navGroup);
+            } else {
+                collapsedNavGroups.delete(groupDiv.dataset.navGroup);
+            }
+            localStorage.setItem('bytedocs-nav-collapsed', JSON.stringify([...collapsedNavGroups]));
+        }
+
+        function renderEndpoints(endpointsToRender = null) {
+
+            const endpointsToShow = endpointsToRender || filteredEndpoints || Object.values(transformedApiData).flat();
+            const flatNavigation = Boolean(config && config.uiConfig && config.uiConfig.flatNavigation);
+            const expandAll = searchInput.value.trim() !== '';
+            endpointsContainer.innerHTML = '';
+            renderGuides();
+            Object.keys(transformedApiData).forEach(category => {
+                const categoryEndpoints = transformedApiData[category].filter(endpoint => 
+                    endpointsToShow.includes(endpoint)
+                );
+                if (categoryEndpoints.length === 0) return;
+                const groupDiv = document.createElement('div');
+                groupDiv.className = 'mb-6';
+                const titleDiv = document.createElement('div');
+                titleDiv.className = 'px-6 pb-3 text-sm font-semibold text-gray-600 dark:text-gray-300 uppercase tracking-wider';
+                titleDiv.textContent = `${category.charAt(0).toUpperCase() + category.slice(1)} (${categoryEndpoints.length})`;
+                groupDiv.appendChild(titleDiv);
+                if (flatNavigation) {
+                    categoryEndpoints.forEach(endpoint => groupDiv.appendChild(createEndpointItem(endpoint, 0)));
+                } else {
+                    // The section itself is the first level; nest what lies below it
+                    renderNavigationNode(groupDiv, collapseNavigationNode(buildNavigationTree(categoryEndpoints)), category, 0, expandAll);
+                }
+                endpointsContainer.appendChild(groupDiv);
+            });
+        }
+
+        const guides = apiData.guides || [];
+        let currentGuide = null;
+
+        // renderGuides lists the markdown guides above the endpoints, filtered by the search
+        function renderGuides() {
+            const query = searchInput.value.trim().toLowerCase();
+            const matching = guides.filter(guide => !query ||
+                guide.title.toLowerCase().includes(query) || guide.content.toLowerCase().includes(query));
+            if (matching.length === 0) return;
+
+            const groupDiv = document.createElement('div');
+            groupDiv.className = 'mb-6';
+            const titleDiv = document.createElement('div');
+            titleDiv.className = 'px-6 pb-3 text-sm font-semibold text-gray-600 dark:text-gray-300 uppercase tracking-wider';
+            titleDiv.textContent = 'Guides';
+            groupDiv.appendChild(titleDiv);
+
+            matching.forEach(guide => {
+                const itemDiv = document.createElement('div');
+                itemDiv.className = 'px-6 py-2 cursor-pointer border-l-3 border-transparent text-sm text-gray-900 dark:text-white hover:bg-gray-100 dark:hover:bg-[#171717] hover:border-accent transition-all duration-200';
+                itemDiv.dataset.guideSlug = guide.slug;
+                itemDiv.textContent = guide.title;
+                if (currentGuide && currentGuide.slug === guide.slug) itemDiv.classList.add('guide-active');
+                itemDiv.addEventListener('click', () => selectGuide(guide));
+                groupDiv.appendChild(itemDiv);
+            });
+            endpointsContainer.appendChild(groupDiv);
+        }
+
+        function selectGuide(guide) {
+            if (currentMode !== 'docs') switchMode('docs');
+            saveFormState();
+            currentGuide = guide;
+
+            document.querySelectorAll('[data-endpoint-id]').forEach(item => item.classList.remove('endpoint-active'));
+            document.querySelectorAll('[data-guide-slug]').forEach(item => {
+                item.classList.toggle('guide-active', item.dataset.guideSlug === guide.slug);
+            });
+
+            document.getElementById('guideBody').innerHTML = renderMarkdown(guide.content);
+            document.getElementById('docsContent').style.display = 'none';
+            document.getElementById('guideContent').style.display = 'block';
+            history.replaceState(null, '', `#guide-${encodeURIComponent(guide.slug)}`);
+            closeMobileSidebar();
+        }
+
+        // closeGuide returns from a guide to the endpoint view
+        function closeGuide() {
+            if (!currentGuide) return;
+            currentGuide = null;
+            document.querySelectorAll('[data-guide-slug]').forEach(item => item.classList.remove('guide-active'));
+            document.getElementById('guideContent').style.display = 'none';
+            if (currentMode === 'docs') document.getElementById('docsContent').style.display = 'block';
+            if (location.hash.startsWith('#guide-')) history.replaceState(null, '', location.pathname + location.search);
+        }
+
+        // openLinkedGuide opens the guide of a "#guide-<slug>" link
+        function openLinkedGuide() {
+            if (!location.hash.startsWith('#guide-')) return;
+            const guide = guides.find(guide => `#guide-${encodeURIComponent(guide.slug)}` === location.hash);
+            if (guide) selectGuide(guide);
+        }
+
+        const endpointFormStates = {};
+
+        function saveFormState() {
+            if (!currentEndpoint) return;
+            const state = {};
+
+            const testParametersInputs = document.getElementById('testParametersInputs');
+            if (testParametersInputs) {
+                const inputs = testParametersInputs.querySelectorAll('input');
+                inputs.forEach(input => {
+                    if (input.name && input.value) {
+                        state[input.name] = input.value;
+                    }
+                });
+            }
+
+            if (monacoEditor) {
+                const value = monacoEditor.getValue();
+                if (value) {
+                    state['body'] = value;
+                }
+            }
+            if (requestContentType) {
+                state['contentType'] = requestContentType;
+            }
+            if (bodyVariant) {
+                state['variant'] = bodyVariant;
+            }
+
+            const responseContainer = document.getElementById('responseContainer');
+            if (responseContainer && !responseContainer.classList.contains('hidden')) {
+                const responseStatus = document.getElementById('responseStatus');
+                const responseTime = document.getElementById('responseTime');
+                const responseBody = document.getElementById('responseBody');
+                state['response'] = {
+                    status: responseStatus?.textContent || '',
+                    statusClass: responseStatus?.className || '',
+                    time: responseTime?.textContent || '',
+                    timeClass: responseTime?.className || '',
+                    body: responseBody?.innerHTML || '',
+                    visible: true
+                };
+            }
+            endpointFormStates[currentEndpoint.id] = state;
+        }
+
+        function restoreFormState() {
+            if (!currentEndpoint || !endpointFormStates[currentEndpoint.id]) return;
+            const state = endpointFormStates[currentEndpoint.id];
+
+            const testParametersInputs = document.getElementById('testParametersInputs');
+            if (testParametersInputs) {
+                const inputs = testParametersInputs.querySelectorAll('input');
+                inputs.forEach(input => {
+                    if (input.name && state[input.name]) {
+                        input.value = state[input.name];
+                    }
+                });
+            }
+
+            if (state['contentType'] && state['contentType'] !== requestContentType) {
+                requestContentType = state['contentType'];
+                renderRequestBody();
+                syncRequestContentType();
+            }
+            if (state['variant'] && state['variant'] !== bodyVariant) {
+                bodyVariant = state['variant'];
+                renderRequestBody();
+            }
+            if (monacoEditor && state['body']) {
+                monacoEditor.setValue(state['body']);
+            }
+
+            if (state['response']) {
+                const responseContainer = document.getElementById('responseContainer');
+                const responseStatus = document.getElementById('responseStatus');
+                const responseTime = document.getElementById('responseTime');
+                const responseBody = document.getElementById('responseBody');
+                if (state.response.visible && responseContainer) {
+                    responseContainer.classList.remove('hidden');
+                    if (responseStatus) {
+                        responseStatus.textContent = state.response.status;
+                        responseStatus.className = state.response.statusClass;
+                    }
+                    if (responseTime) {
+                        responseTime.textContent = state.response.time;
+                        if (state.response.timeClass) {
+                            responseTime.className = state.response.timeClass;
+                        }
+                    }
+                    if (responseBody) {
+                        responseBody.innerHTML = state.response.body;
+                    }
+                }
+            }
+        }
+
+        function selectEndpoint(endpoint) {
+
+            saveFormState();
+            closeGuide();
+            currentEndpoint = endpoint;
+            requestContentType = null;
+            bodyVariant = null;
+
+            document.querySelectorAll('[data-endpoint-id]').forEach(item => {
+                item.classList.remove('endpoint-active');
+            });
+            const activeItem = document.querySelector(`[data-endpoint-id="${endpoint.id}"]`);
+            if (activeItem) {
+                activeItem.classList.add('endpoint-active');
+                for (let group = activeItem.closest('.nav-group.collapsed'); group; group = group.parentElement.closest('.nav-group.collapsed')) {
+                    toggleNavGroup(group, false);
+                }
+            }
+
+            currentMethod.textContent = endpoint.method;
+            currentMethod.className = `endpoint-method px-2 rounded-md text-sm method-${endpoint.method.toLowerCase()}`;
+
+            const selectedOption = baseUrlSelect.options[baseUrlSelect.selectedIndex];
+            const selectedText = selectedOption ? selectedOption.textContent : 'Current';
+            const baseUrlName = selectedText.split(' - ')[0];
+            currentUrl.innerHTML = `
+                <span class="base-url-badge">${baseUrlName}</span>
+                <span class="endpoint-path">${endpoint.path}</span>
+            `;
+
+            const bodyTab = document.getElementById('bodyTab');
+            const hasBody = ['POST', 'PUT', 'PATCH'].includes(endpoint.method.toUpperCase());
+            bodyTab.style.display = hasBody ? 'block' : 'none';
+
+            updateContent();
+
+            const responseContainer = document.getElementById('responseContainer');
+            responseContainer.classList.add('hidden');
+        }
+
+        function updateContent() {
+            if (!currentEndpoint) return;
+
+            const description = getEndpointDescription(currentEndpoint);
+            endpointDescription.textContent = description;
+
+            // Only http(s) links, the URL comes from handler comments
+            const externalDocs = currentEndpoint.externalDocs;
+            if (externalDocs && /^https?:\/\//i.test(externalDocs.url)) {
+                endpointExternalDocs.href = externalDocs.url;
+                endpointExternalDocs.textContent = `${externalDocs.description || 'Read more'} →`;
+                endpointExternalDocs.classList.remove('hidden');
+            } else {
+                endpointExternalDocs.removeAttribute('href');
+                endpointExternalDocs.classList.add('hidden');
+            }
+
+            const budget = slaMs(currentEndpoint);
+            endpointSLA.textContent = budget ? `⏱ ≤ ${formatMs(budget)}` : '';
+            endpointSLA.classList.toggle('hidden', !budget);
+
+            if (currentEndpoint.parameters && currentEndpoint.parameters.length > 0) {
+                parametersContent.innerHTML = `
+                    <div class="mobile-scroll-table">
+                        <table class="w-full border-collapse mb-4 bg-white dark:bg-black border border-gray-200 dark:border-0 rounded-lg overflow-hidden">
+                        <thead>
+                            <tr class="bg-gray-50 dark:bg-[#2c2d2d]">
+                                <th class="px-3 py-2 text-left border-b border-gray-200 dark:border-0 font-semibold text-gray-900 dark:text-white">Name</th>
+                                <th class="px-3 py-2 text-left border-b border-gray-200 dark:border-0 font-semibold text-gray-900 dark:text-white">Location</th>
+                                <th class="px-3 py-2 text-left border-b border-gray-200 dark:border-0 font-semibold text-gray-900 dark:text-white">Type</th>
+                                <th class="px-3 py-2 text-left border-b border-gray-200 dark:border-0 font-semibold text-gray-900 dark:text-white">Required</th>
+                                <th class="px-3 py-2 text-left border-b border-gray-200 dark:border-0 font-semibold text-gray-900 dark:text-white">Description</th>
+                            </tr>
+                        </thead>
+                        <tbody>
+                            ${currentEndpoint.parameters.map(param => `
+                                <tr class="border-b border-gray-200 dark:border-0">
+                                    <td class="px-3 py-2"><code class="bg-gray-100 dark:bg-green-800 dark:text-white px-2 py-1 rounded text-sm">${param.name}</code></td>
+                                    <td class="px-3 py-2"><span class="px-2 py-1 rounded text-xs font-medium ${param.in === 'path' ? 'bg-blue-100 text-blue-800 dark:bg-blue-800 dark:text-blue-100' : 'bg-purple-100 text-purple-800 dark:bg-purple-800 dark:text-purple-100'}">${param.in}</span></td>
+                                    <td class="px-3 py-2"><span class="bg-gray-100 dark:bg-green-800 dark:text-white px-2 py-1 rounded text-xs font-mono">${param.type}</span></td>
+                                    <td class="px-3 py-2">${param.required ? '<span class="text-red-600 dark:text-red-400 font-semibold">Yes</span>' : 'No'}</td>
+                                    <td class="px-3 py-2 text-gray-600 dark:text-gray-300">${param.description || 'No description available'}</td>
+                                </tr>
+                            `).join('')}
+                        </tbody>
+                        </table>
+                    </div>
+                `;
+            } else {
+                parametersContent.innerHTML = '<p>No parameters required.</p>';
+            }
+
+            renderRequestBody();
+
+            const responses = getEndpointResponses(currentEndpoint);
+            if (responses && Object.keys(responses).length > 0) {
+                responsesContent.innerHTML = Object.entries(responses).map(([status, response]) => {
+                    const exampleHtml = response.example !== undefined && response.example !== null
+                        ? createJsonViewer(JSON.stringify(response.example, null, 2), `Response ${status}`)
+                        : '';
+                    const capturedHtml = namedExamplesHtml(response.examples, `Response ${status}`);
+                    const streamingHtml = response.streaming
+                        ? `<span class="inline-block px-2 py-1 rounded text-xs font-semibold ml-2 bg-yellow-100 text-yellow-800 dark:bg-yellow-800 dark:text-yellow-100" title="The body is a file or stream sent as it is read">Streaming</span>`
+                        : '';
+                    const contentTypeHtml = response.streaming && response.contentType
+                        ? `<p class="mb-3 text-sm text-gray-600 dark:text-gray-300"><code class="bg-gray-100 dark:bg-green-800 dark:text-white px-2 py-1 rounded text-xs">${escapeHtml(response.contentType)}</code> binary body</p>`
+                        : '';
+                    const envelope = response.schema && response.schema['x-envelope'];
+                    const envelopeHtml = envelope
+                        ? `<p class="mb-3 text-sm text-gray-600 dark:text-gray-300">Payload in <code class="bg-gray-100 dark:bg-green-800 dark:text-white px-2 py-1 rounded text-xs">${escapeHtml(envelope)}</code>${response.schema['x-envelope-type'] ? `: <span class="font-mono font-semibold text-gray-900 dark:text-white">${escapeHtml(response.schema['x-envelope-type'])}</span>` : ''}</p>`
+                        : '';
+                    const headers = Object.entries(response.headers || {});
+                    const headersHtml = headers.length > 0 ? `
+                        <ul class="mb-3 text-sm text-gray-600 dark:text-gray-300">
+                            ${headers.map(([name, header]) => `<li><code class="bg-gray-100 dark:bg-green-800 dark:text-white px-2 py-1 rounded text-xs">${escapeHtml(name)}</code> ${escapeHtml(header.description || '')}${header.example !== undefined ? ` <span class="font-mono">${escapeHtml(String(header.example))}</span>` : ''}</li>`).join('')}
+                        </ul>` : '';
+                    return `
+                        <div class="mb-6 p-4 border border-gray-200 dark:border-[#1b1b1b] rounded-2xl bg-white dark:bg-[#171717]">
+                            <h4 class="mb-3"><span class="inline-block px-2 py-1 rounded text-xs font-semibold mr-2 ${status.startsWith('2') ? 'bg-green-100 text-green-800 dark:bg-green-800 dark:text-green-100' : status.startsWith('3') ? 'bg-blue-100 text-blue-800 dark:bg-blue-800 dark:text-blue-100' : 'bg-red-100 text-red-800 dark:bg-red-800 dark:text-red-100'}">${status}</span><span class="text-gray-900 dark:text-white">${response.description}</span>${streamingHtml}</h4>
+                            ${contentTypeHtml}
+                            ${envelopeHtml}
+                            ${headersHtml}
+                            ${exampleHtml}
+                            ${capturedHtml}
+                        </div>`;
+                }).join('');
+            } else {
+                responsesContent.innerHTML = '<p>No response examples available.</p>';
+            }
+
+            updateTestForm();
+        }
+
+        // namedExamplesHtml shows further examples of a body, such as those captured from traffic
+        function namedExamplesHtml(examples, title) {
+            return (examples || []).map(example =>
+                createJsonViewer(JSON.stringify(example.value, null, 2), `${title} · ${escapeHtml(example.summary || example.name)}`)
+            ).join('');
+        }
+
+        function renderRequestBody() {
+            const bodyContent = document.getElementById('bodyContent');
+            if (!['POST', 'PUT', 'PATCH'].includes(currentEndpoint.method.toUpperCase())) {
+                bodyContent.innerHTML = '<p>No request body required for this method.</p>';
+                return;
+            }
+
+            const contents = requestBodyContents(currentEndpoint);
+            const content = currentBodyContent(currentEndpoint);
+            const selector = contents.length > 1 ? `
+                <label class="flex items-center gap-2 mb-4 text-sm text-gray-600 dark:text-gray-300">Content type
+                    <select id="bodyContentType" class="px-2 py-1 border border-gray-300 dark:border-[#212121] rounded-md bg-white dark:bg-black text-gray-900 dark:text-white text-sm">
+                        ${contents.map(option => `<option value="${escapeHtml(option.contentType)}" ${option === content ? 'selected' : ''}>${escapeHtml(option.contentType)}</option>`).join('')}
+                    </select>
+                </label>` : '';
+            const union = content ? bodyUnion(content.schema) : null;
+            const variantSelector = union ? `
+                <label class="flex items-center gap-2 mb-4 text-sm text-gray-600 dark:text-gray-300">${escapeHtml(union.property || 'Type')}
+                    <select id="bodyVariant" class="px-2 py-1 border border-gray-300 dark:border-[#212121] rounded-md bg-white dark:bg-black text-gray-900 dark:text-white text-sm">
+                        ${union.variants.map(variant => `<option value="${escapeHtml(variant.value)}" ${variant.value === union.selected ? 'selected' : ''}>${escapeHtml(variant.title ? `${variant.value} (${variant.title})` : variant.value)}</option>`).join('')}
+                    </select>
+                </label>` : '';
+
+            const descriptionText = currentEndpoint.requestBody?.description || content?.schema?.description;
+            const description = descriptionText ? `<p class="text-sm text-gray-600 dark:text-gray-300 mb-4">${escapeHtml(descriptionText)}</p>` : '';
+            const example = content ? content.example : null;
+            if (example) {
+                const formatted = formatBodyExample(example, content.contentType);
+                bodyContent.innerHTML = `
+                    ${selector}
+                    ${variantSelector}
+                    ${description}
+                    ${isXmlContentType(content.contentType)
+                        ? `<pre class="p-4 bg-gray-100 dark:bg-[#212121] border border-gray-200 dark:border-[#2c2d2d] rounded-lg font-mono text-sm overflow-x-auto">${escapeHtml(formatted)}</pre>`
+                        : createJsonViewer(formatted, 'Request Body')}
+                    ${content.contentType === currentEndpoint.requestBody?.contentType ? namedExamplesHtml(currentEndpoint.requestBody.examples, 'Request Body') : ''}
+                    <p class="text-muted" style="margin-top: 8px; font-size: 14px;"></p>
+                `;
+            } else {
+                bodyContent.innerHTML = `${selector}${variantSelector}${description}<p>No request body example available.</p>`;
+            }
+            document.getElementById('bodyContentType')?.addEventListener('change', (e) => selectRequestContentType(e.target.value));
+            document.getElementById('bodyVariant')?.addEventListener('change', (e) => selectBodyVariant(e.target.value));
+        }
+
+        // selectBodyVariant switches the documented and try-it body to another variant of a union
+        function selectBodyVariant(value) {
+            bodyVariant = value;
+            renderRequestBody();
+
+            const content = currentBodyContent(currentEndpoint);
+            if (monacoEditor && content && content.example) {
+                monacoEditor.setValue(formatBodyExample(content.example, content.contentType));
+            }
+            saveFormState();
+        }
+
+        // selectRequestContentType switches the documented and try-it body to another content type
+        function selectRequestContentType(contentType) {
+            requestContentType = contentType;
+            renderRequestBody();
+            syncRequestContentType();
+
+            const content = currentBodyContent(currentEndpoint);
+            if (monacoEditor) {
+                monacoEditor.setValue(content && content.example
+                    ? formatBodyExample(content.example, content.contentType)
+                    : (isJsonContentType(contentType) ? '{\n  \n}' : ''));
+            }
+            saveFormState();
+        }
+
+        // syncRequestContentType shows the try-it content type selector and sets the editor language
+        function syncRequestContentType() {
+            const testContentType = document.getElementById('testContentType');
+            const contents = requestBodyContents(currentEndpoint);
+            const content = currentBodyContent(currentEndpoint);
+            if (testContentType) {
+                testContentType.innerHTML = contents.map(option => `<option value="${escapeHtml(option.contentType)}">${escapeHtml(option.contentType)}</option>`).join('');
+                testContentType.classList.toggle('hidden', contents.length < 2);
+                if (content) testContentType.value = content.contentType;
+            }
+            if (monacoEditor && window.monaco) {
+                monaco.editor.setModelLanguage(monacoEditor.getModel(), content && isXmlContentType(content.contentType) ? 'xml' : 'json');
+            }
+        }
+
+        function updateTestForm() {
+            if (!currentEndpoint) return;
+            const testParametersForm = document.getElementById('testParametersForm');
+            const testParametersInputs = document.getElementById('testParametersInputs');
+            const testBodyForm = document.getElementById('testBodyForm');
+            const testBodyInput = document.getElementById('testBodyInput');
+
+            if (currentEndpoint.parameters && currentEndpoint.parameters.length > 0) {
+                testParametersForm.classList.remove('hidden');
+
+                const pathParams = currentEndpoint.parameters.filter(p => p.in === 'path');
+                const queryParams = currentEndpoint.parameters.filter(p => p.in === 'query');
+                const otherParams = currentEndpoint.parameters.filter(p => p.in !== 'path' && p.in !== 'query');
+                let html = '';
+
+                if (pathParams.length > 0) {
+                    html += `
+                        <div class="mb-4">
+                            <h5 class="text-sm font-semibold text-gray-900 dark:text-white mb-2 flex items-center">
+                                <span class="px-2 py-1 rounded text-xs font-medium bg-blue-100 text-blue-800 dark:bg-blue-800 dark:text-blue-100 mr-2">path</span>
+                                Path Parameters
+                            </h5>
+                            <div class="space-y-3">
+                                ${pathParams.map(param => {
+                                    const isRequired = param.required ? 'required' : '';
+                                    const placeholder = param.description || `Enter ${param.name}`;
+                                    return `
+                                        <div class="flex flex-col">
+                                            <label class="text-sm font-medium text-[#2c2d2d] dark:text-gray-300 mb-1">
+                                                ${param.name} 
+                                                <span class="text-xs text-gray-500 dark:text-gray-400">(${param.type})</span>
+                                                ${param.required ? '<span class="text-red-500">*</span>' : ''}
+                                            </label>
+                                            <input 
+                                                type="text" 
+                                                name="param_${param.name}" 
+                                                class="px-3 py-2 border border-gray-300 dark:border-[#212121] rounded-md bg-white dark:bg-black text-gray-900 dark:text-white text-sm" 
+                                                placeholder="${placeholder}"
+                                                ${isRequired}
+                                            >
+                                            ${param.description ? `<span class="text-xs text-gray-500 dark:text-gray-400 mt-1">${param.description}</span>` : ''}
+                                        </div>
+                                    `;
+                                }).join('')}
+                            </div>
+                        </div>
+                    `;
+                }
+
+                if (queryParams.length > 0) {
+                    html += `
+                        <div class="mb-4">
+                            <h5 class="text-sm font-semibold text-gray-900 dark:text-white mb-2 flex items-center">
+                                <span class="px-2 py-1 rounded text-xs font-medium bg-purple-100 text-purple-800 dark:bg-purple-800 dark:text-purple-100 mr-2">query</span>
+                                Query Parameters
+                            </h5>
+                            <div class="space-y-3">
+                                ${queryParams.map(param => {
+                                    const isRequired = param.required ? 'required' : '';
+                                    const placeholder = param.description || `Enter ${param.name}`;
+                                    return `
+                                        <div class="flex flex-col">
+                                            <label class="text-sm font-medium text-[#2c2d2d] dark:text-gray-300 mb-1">
+                                                ${param.name} 
+                                                <span class="text-xs text-gray-500 dark:text-gray-400">(${param.type})</span>
+                                                ${param.required ? '<span class="text-red-500">*</span>' : ''}
+                                            </label>
+                                            <input 
+                                                type="text" 
+                                                name="param_${param.name}" 
+                                                class="px-3 py-2 border border-gray-300 dark:border-[#212121] rounded-md bg-white dark:bg-black text-gray-900 dark:text-white text-sm" 
+                                                placeholder="${placeholder}"
+                                                ${isRequired}
+                                            >
+                                            ${param.description ? `<span class="text-xs text-gray-500 dark:text-gray-400 mt-1">${param.description}</span>` : ''}
+                                        </div>
+                                    `;
+                                }).join('')}
+                            </div>
+                        </div>
+                    `;
+                }
+
+                if (otherParams.length > 0) {
+                    html += `
+                        <div class="mb-4">
+                            <h5 class="text-sm font-semibold text-gray-900 dark:text-white mb-2">Other Parameters</h5>
+                            <div class="space-y-3">
+                                ${otherParams.map(param => {
+                                    const isRequired = param.required ? 'required' : '';
+                                    const placeholder = param.description || `Enter ${param.name}`;
+                                    return `
+                                        <div class="flex flex-col">
+                                            <label class="text-sm font-medium text-[#2c2d2d] dark:text-gray-300 mb-1">
+                                                ${param.name} 
+                                                <span class="px-2 py-1 rounded text-xs font-medium bg-gray-100 text-gray-800 dark:bg-green-800 dark:text-gray-100 mr-1">${param.in}</span>
+                                                <span class="text-xs text-gray-500 dark:text-gray-400">(${param.type})</span>
+                                                ${param.required ? '<span class="text-red-500">*</span>' : ''}
+                                            </label>
+                                            <input 
+                                                type="text" 
+                                                name="param_${param.name}" 
+                                                class="px-3 py-2 border border-gray-300 dark:border-[#212121] rounded-md bg-white dark:bg-black text-gray-900 dark:text-white text-sm" 
+                                                placeholder="${placeholder}"
+                                                ${isRequired}
+                                            >
+                                            ${param.description ? `<span class="text-xs text-gray-500 dark:text-gray-400 mt-1">${param.description}</span>` : ''}
+                                        </div>
+                                    `;
+                                }).join('')}
+                            </div>
+                        </div>
+                    `;
+                }
+                testParametersInputs.innerHTML = html;
+            } else {
+                testParametersForm.classList.add('hidden');
+            }
+
+            const hasBody = ['POST', 'PUT', 'PATCH'].includes(currentEndpoint.method.toUpperCase());
+            if (hasBody) {
+                testBodyForm.classList.remove('hidden');
+
+                syncRequestContentType();
+                if (!endpointFormStates[currentEndpoint.id] || !endpointFormStates[currentEndpoint.id]['body']) {
+
+                    const exampleBody = getRequestBodyExample(currentEndpoint);
+                    const defaultValue = exampleBody ? formatBodyExample(exampleBody, currentBodyContent(currentEndpoint).contentType) : '{\n  \n}';
+                    if (monacoEditor) {
+                        monacoEditor.setValue(defaultValue);
+                    }
+                }
+            } else {
+                testBodyForm.classList.add('hidden');
+            }
+
+            const graphqlModeButton = document.getElementById('graphqlModeButton');
+            graphqlModeButton.classList.toggle('hidden', !currentEndpoint.graphql);
+            if (currentEndpoint.graphql) {
+                loadGraphQLState();
+                switchTestMode('graphql');
+            } else if (testMode === 'graphql') {
+                switchTestMode('form');
+            }
+
+            renderTestPresets();
+            setTimeout(restoreFormState, 0);
+        }
+
+        // loadGraphQLState fills the query editor with what was typed for the
+        // endpoint before, else with its documented request example
+        function loadGraphQLState() {
+            const example = getRequestBodyExample(currentEndpoint) || {};
+            const variables = example.variables && Object.keys(example.variables).length ? JSON.stringify(example.variables, null, 2) : '';
+            const state = graphqlStates[currentEndpoint.id] || {
+                query: typeof example.query === 'string' ? example.query : 'query {\n  __typename\n}',
+                variables,
+                operationName: example.operationName || ''
+            };
+            document.getElementById('graphqlQuery').value = state.query;
+            document.getElementById('graphqlVariables').value = state.variables;
+            document.getElementById('graphqlOperationName').value = state.operationName;
+        }
+
+        function saveGraphQLState() {
+            if (!currentEndpoint || !currentEndpoint.graphql) return;
+            graphqlStates[currentEndpoint.id] = {
+                query: document.getElementById('graphqlQuery').value,
+                variables: document.getElementById('graphqlVariables').value,
+                operationName: document.getElementById('graphqlOperationName').value
+            };
+        }
+
+        // sendGraphQLRequest sends the query as a GraphQL POST body, or as query
+        // string parameters to GET endpoints
+        async function sendGraphQLRequest() {
+            if (!currentEndpoint) return;
+            trackUsage({ type: 'try', method: currentEndpoint.method, path: currentEndpoint.path });
+            const button = document.getElementById('graphqlSendButton');
+            button.disabled = true;
+            button.textContent = 'Sending...';
+            const startTime = Date.now();
+            try {
+                const payload = { query: document.getElementById('graphqlQuery').value };
+                const variablesText = document.getElementById('graphqlVariables').value.trim();
+                if (variablesText) {
+                    try {
+                        payload.variables = JSON.parse(variablesText);
+                    } catch (e) {
+                        throw new Error('Invalid JSON in variables');
+                    }
+                }
+                const operationName = document.getElementById('graphqlOperationName').value.trim();
+                if (operationName) payload.operationName = operationName;
+
+                let url = `${baseUrlSelect.value || currentOrigin}${currentEndpoint.path}`;
+                const requestOptions = {
+                    method: currentEndpoint.method.toUpperCase() === 'GET' ? 'GET' : 'POST',
+                    credentials: requestCredentials(),
+                    headers: {
+                        ...getAuthHeaders(),
+                        'Accept': 'application/graphql-response+json, application/json'
+                    }
+                };
+                if (requestOptions.method === 'GET') {
+                    const query = new URLSearchParams({ query: payload.query });
+                    if (payload.variables) query.set('variables', JSON.stringify(payload.variables));
+                    if (payload.operationName) query.set('operationName', payload.operationName);
+                    url += (url.includes('?') ? '&' : '?') + query.toString();
+                } else {
+                    requestOptions.headers['Content-Type'] = 'application/json';
+                    requestOptions.body = JSON.stringify(payload);
+                    const csrfToken = document.querySelector('meta[name="csrf-token"]');
+                    if (csrfToken) {
+                        requestOptions.headers['X-CSRF-TOKEN'] = csrfToken.getAttribute('content');
+                    }
+                }
+
+                const response = await fetch(url, requestOptions);
+                await showTestResponse(response, Date.now() - startTime);
+            } catch (error) {
+                showTestError(error, Date.now() - startTime);
+            } finally {
+                button.disabled = false;
+                button.textContent = 'Send Request';
+            }
+        }
+
+        // renderTestPresets offers "Fill example" and the endpoint's named presets above the try-it form
+        function renderTestPresets() {
+            const testPresets = document.getElementById('testPresets');
+            const testPresetStatus = document.getElementById('testPresetStatus');
+            testPresets.innerHTML = '';
+            testPresetStatus.textContent = '';
+
+            const hasExample = (currentEndpoint.parameters || []).some(param => param.example !== undefined && param.example !== null) ||
+                getRequestBodyExample(currentEndpoint) !== null;
+            const presets = [];
+            if (hasExample) presets.push({ name: 'Fill example', description: 'Documented parameter examples and request body' });
+            presets.push(...(currentEndpoint.presets || []));
+
+            testPresets.classList.toggle('hidden', presets.length === 0);
+            presets.forEach(preset => {
+                const button = document.createElement('button');
+                button.type = 'button';
+                button.className = 'px-3 py-1 border border-gray-300 dark:border-[#212121] rounded-md bg-white dark:bg-black text-gray-900 dark:text-white text-sm hover:border-accent hover:text-accent transition-colors duration-200';
+                button.textContent = preset.name;
+                if (preset.description) button.title = preset.description;
+                button.addEventListener('click', () => applyTestPreset(preset));
+                testPresets.appendChild(button);
+            });
+        }
+
+        // applyTestPreset fills the try-it form; values the preset leaves out fall back to the documented examples
+        function applyTestPreset(preset) {
+            const values = preset.params || {};
+            (currentEndpoint.parameters || []).forEach(param => {
+                const input = document.querySelector(`#testParametersInputs [name="param_${CSS.escape(param.name)}"]`);
+                if (!input) return;
+                const example = param.example !== undefined && param.example !== null ? String(param.example) : '';
+                input.value = values[param.name] !== undefined ? values[param.name] : example;
+            });
+
+            if (monacoEditor && ['POST', 'PUT', 'PATCH'].includes(currentEndpoint.method.toUpperCase())) {
+                const body = preset.body !== undefined ? preset.body : getRequestBodyExample(currentEndpoint);
+                const contentType = currentBodyContent(currentEndpoint)?.contentType;
+                monacoEditor.setValue(body !== null && body !== undefined ? formatBodyExample(body, contentType) : '{\n  \n}');
+            }
+
+            const baseUrlName = (baseUrlSelect.options[baseUrlSelect.selectedIndex]?.textContent || 'Current').split(' - ')[0];
+            document.getElementById('testPresetStatus').textContent = `Filled "${preset.name}" · ${baseUrlName} · ${describeAuth(auth)}`;
+            saveFormState();
+        }
+
+        function performSearch() {
+            const query = searchInput.value.toLowerCase().trim();
+            if (query === '') {
+                filteredEndpoints = Object.values(transformedApiData).flat();
+                searchResults.classList.add('hidden');
+                searchClear.classList.add('hidden');
+            } else {
+                filteredEndpoints = Object.values(transformedApiData).flat().filter(endpoint => {
+                    const searchTargets = [
+                        endpoint.title || '',
+                        endpoint.description || '',
+                        getEndpointDescription(endpoint),
+                        endpoint.path || '',
+                        endpoint.id || '',
+                        endpoint.method || '',
+                        endpoint.summary || '',
+
+                        ...(endpoint.parameters || []).map(param => 
+                            `${param.name} ${param.type} ${param.description || ''}`
+                        )
+                    ];
+                    return searchTargets.some(target => 
+                        target.toLowerCase().includes(query)
+                    );
+                });
+                searchResults.classList.remove('hidden');
+                searchClear.classList.remove('hidden');
+                searchCount.textContent = filteredEndpoints.length;
+            }
+            trackSearch(query, query === '' ? 0 : filteredEndpoints.length);
+            renderEndpoints();
+        }
+
+        function clearSearch() {
+            searchInput.value = '';
+            performSearch();
+        }
+
+        function selectFirstEndpoint() {
+            if (filteredEndpoints && filteredEndpoints.length > 0) {
+                selectEndpoint(filteredEndpoints[0]);
+            } else {
+                const firstCategory = Object.keys(transformedApiData)[0];
+                if (firstCategory && transformedApiData[firstCategory][0]) {
+                    selectEndpoint(transformedApiData[firstCategory][0]);
+                }
+            }
+        }
+
+        function switchTab(tabName) {
+
+            document.querySelectorAll('[data-tab]').forEach(tab => {
+                tab.className = 'px-6 py-3 cursor-pointer border-b-2 border-transparent text-gray-600 dark:text-gray-300 hover:text-accent hover:border-accent transition-all duration-200';
+
+                tab.style.borderBottomColor = '';
+                tab.style.color = '';
+            });
+
+            document.querySelectorAll('[id="overview"], [id="parameters"], [id="body"], [id="responses"], [id="test"]').forEach(content => {
+                content.classList.add('hidden');
+                content.classList.remove('block');
+            });
+
+            const activeTab = document.querySelector(`[data-tab="${tabName}"]`);
+            activeTab.className = 'px-6 py-3 cursor-pointer border-b-2 font-medium transition-all duration-200';
+            activeTab.style.borderBottomColor = 'var(--accent-color)';
+            activeTab.style.color = 'var(--accent-color)';
+
+            const activeContent = document.getElementById(tabName);
+            activeContent.classList.remove('hidden');
+            activeContent.classList.add('block');
+        }
+
+        async function testEndpoint() {
+            if (!currentEndpoint) return;
+            trackUsage({ type: 'try', method: currentEndpoint.method, path: currentEndpoint.path });
+            testButton.disabled = true;
+            testButton.textContent = 'Sending...';
+            const startTime = Date.now();
+            try {
+
+                const parameters = {};
+                const paramInputs = document.querySelectorAll('[name^="param_"]');
+                paramInputs.forEach(input => {
+                    const paramName = input.name.replace('param_', '');
+                    if (input.value.trim()) {
+                        parameters[paramName] = input.value.trim();
+                    }
+                });
+
+                let baseUrl = baseUrlSelect.value || currentOrigin;
+                let url = `${baseUrl}${currentEndpoint.path}`;
+
+                Object.entries(parameters).forEach(([key, value]) => {
+                    url = url.replace(`{${key}}`, value);
+                });
+
+                const paramLocations = Object.fromEntries((currentEndpoint.parameters || []).map(param => [param.name, param.in]));
+                const headerParams = {};
+                const queryParams = new URLSearchParams();
+                Object.entries(parameters).forEach(([key, value]) => {
+                    if (currentEndpoint.path.includes(`{${key}}`)) return;
+                    if (paramLocations[key] === 'header') {
+                        headerParams[key] = value;
+                    } else if (paramLocations[key] === 'query' || (currentEndpoint.method.toUpperCase() === 'GET' && paramLocations[key] !== 'cookie')) {
+                        queryParams.append(key, value);
+                    }
+                });
+                if (queryParams.toString()) {
+                    url += '?' + queryParams.toString();
+                }
+
+                const contentType = currentBodyContent(currentEndpoint)?.contentType || 'application/json';
+                const requestOptions = {
+                    method: currentEndpoint.method,
+                    credentials: requestCredentials(),
+                    headers: {
+                        ...getAuthHeaders(),
+                        ...headerParams,
+                        'Content-Type': contentType,
+                        'Accept': 'application/json'
+                    }
+                };
+
+                if (!['GET', 'HEAD', 'OPTIONS'].includes(currentEndpoint.method.toUpperCase())) {
+                    const csrfToken = document.querySelector('meta[name="csrf-token"]');
+                    if (csrfToken) {
+                        requestOptions.headers['X-CSRF-TOKEN'] = csrfToken.getAttribute('content');
+                    }
+                }
+
+                if (['POST', 'PUT', 'PATCH'].includes(currentEndpoint.method.toUpperCase())) {
+                    if (monacoEditor) {
+                        const bodyValue = monacoEditor.getValue().trim();
+                        if (bodyValue && !isJsonContentType(contentType)) {
+                            requestOptions.body = bodyValue;
+                        } else if (bodyValue) {
+                            try {
+
+                                JSON.parse(bodyValue);
+                                requestOptions.body = bodyValue;
+                            } catch (e) {
+                                throw new Error('Invalid JSON in request body');
+                            }
+                        }
+                    }
+                }
+
+                const response = await fetch(url, requestOptions);
+                await showTestResponse(response, Date.now() - startTime);
+
+                saveFormState();
+            } catch (error) {
+                showTestError(error, Date.now() - startTime);
+
+                saveFormState();
+            } finally {
+                testButton.disabled = false;
+                testButton.textContent = 'Send Request';
+            }
+        }
+
+        async function showTestResponse(response, duration) {
+            responseContainer.classList.remove('hidden');
+            responseStatus.textContent = response.status;
+            responseStatus.className = `response-status dark:text-white status-${response.status}`;
+            showResponseTime(duration);
+            const textResponse = await response.text();
+            try {
+                const responseData = JSON.parse(textResponse);
+                responseBody.innerHTML = createJsonViewer(JSON.stringify(responseData, null, 2), 'Response');
+            } catch (e) {
+                responseBody.innerHTML = `<pre class="p-4 bg-gray-100 dark:bg-[#212121] border border-gray-200 dark:border-[#2c2d2d] rounded-lg font-mono text-sm">${escapeHtml(textResponse) || 'Empty response'}</pre>`;
+            }
+        }
+
+        function showTestError(error, duration) {
+            responseContainer.classList.remove('hidden');
+            responseStatus.textContent = '500';
+            responseStatus.className = 'response-status dark:text-white status-500';
+            showResponseTime(duration);
+            responseBody.innerHTML = createJsonViewer(JSON.stringify({ error: 'Request failed: ' + error.message }, null, 2), 'Error Response');
+        }
+
+        // switchTestMode toggles the try-it tab between the generated form, the
+        // raw request editor and, for GraphQL endpoints, the query editor
+        function switchTestMode(mode) {
+            testMode = mode;
+            document.getElementById('formRequestPanel').classList.toggle('hidden', mode !== 'form');
+            document.getElementById('rawRequestPanel').classList.toggle('hidden', mode !== 'raw');
+            document.getElementById('graphqlRequestPanel').classList.toggle('hidden', mode !== 'graphql');
+            document.querySelectorAll('.test-mode-btn').forEach(button => {
+                const active = button.dataset.testMode === mode;
+                button.classList.toggle('border-accent', active);
+                button.classList.toggle('text-accent', active);
+                button.classList.toggle('border-gray-300', !active);
+                button.classList.toggle('dark:border-[#212121]', !active);
+                button.classList.toggle('text-gray-600', !active);
+                button.classList.toggle('dark:text-gray-300', !active);
+            });
+            if (mode === 'raw') {
+                if (!document.getElementById('rawUrl').value && currentEndpoint) {
+                    document.getElementById('rawMethod').value = currentEndpoint.method.toUpperCase();
+                    document.getElementById('rawUrl').value = (baseUrlSelect.value || currentOrigin) + currentEndpoint.path;
+                }
+                renderRawScenarioOptions();
+            }
+        }
+
+        // tokenizeShell splits a command line the way a POSIX shell would, for
+        // the quoting styles curl commands copied from browsers and terminals use
+        function tokenizeShell(command) {
+            const tokens = [];
+            let current = null;
+            const input = command.replace(/\\\r?\n/g, ' ');
+            for (let i = 0; i < input.length; i++) {
+                const char = input[i];
+                if (/\s/.test(char)) {
+                    if (current !== null) tokens.push(current);
+                    current = null;
+                } else if (char === "'") {
+                    const end = input.indexOf("'", i + 1);
+                    if (end === -1) throw new Error('Unterminated single quote');
+                    current = (current || '') + input.slice(i + 1, end);
+                    i = end;
+                } else if (char === '$' && input[i + 1] === "'") {
+                    let value = '';
+                    for (i += 2; i < input.length && input[i] !== "'"; i++) {
+                        if (input[i] === '\\' && i + 1 < input.length) {
+                            const escaped = input[++i];
+                            value += { n: '\n', t: '\t', r: '\r' }[escaped] ?? escaped;
+                        } else {
+                            value += input[i];
+                        }
+                    }
+                    if (i >= input.length) throw new Error('Unterminated quote');
+                    current = (current || '') + value;
+                } else if (char === '"') {
+                    let value = '';
+                    for (i++; i < input.length && input[i] !== '"'; i++) {
+                        if (input[i] === '\\' && '"\\$`'.includes(input[i + 1])) i++;
+                        value += input[i];
+                    }
+                    if (i >= input.length) throw new Error('Unterminated double quote');
+                    current = (current || '') + value;
+                } else if (char === '\\' && i + 1 < input.length) {
+                    current = (current || '') + input[++i];
+                } else {
+                    current = (current || '') + char;
+                }
+            }
+            if (current !== null) tokens.push(current);
+            return tokens;
+        }
+
+        // parseCurl turns a curl command into the method, URL, headers and body it would send
+        function parseCurl(command) {
+            const tokens = tokenizeShell(command.trim());
+            if (tokens[0] !== 'curl') throw new Error('The command must start with curl');
+
+            const valueFlags = {
+                '-X': 'method', '--request': 'method',
+                '-H': 'header', '--header': 'header',
+                '-d': 'data', '--data': 'data', '--data-raw': 'data', '--data-binary': 'data', '--data-ascii': 'data', '--data-urlencode': 'data',
+                '--json': 'json',
+                '-u': 'user', '--user': 'user',
+                '-b': 'cookie', '--cookie': 'cookie',
+                '-A': 'agent', '--user-agent': 'agent',
+                '-e': 'referer', '--referer': 'referer',
+                '--url': 'url'
+            };
+            const ignoredValueFlags = ['-o', '--output', '-m', '--max-time', '--connect-timeout', '-w', '--write-out', '--retry', '-x', '--proxy', '--cacert', '--cert', '--key'];
+
+            let method = null;
+            let url = null;
+            let get = false;
+            const headers = [];
+            const data = [];
+            for (let i = 1; i < tokens.length; i++) {
+                let token = tokens[i];
+                let value;
+                const long = token.match(/^(--[a-z-]+)=(.*)$/s);
+                if (long) {
+                    token = long[1];
+                    value = long[2];
+                } else if (/^-[XHdubAe].+/s.test(token)) {
+                    value = token.slice(2);
+                    token = token.slice(0, 2);
+                }
+
+                const kind = valueFlags[token];
+                if (kind) {
+                    if (value === undefined) {
+                        if (i + 1 >= tokens.length) throw new Error(`${token} needs a value`);
+                        value = tokens[++i];
+                    }
+                    switch (kind) {
+                        case 'method': method = value.toUpperCase(); break;
+                        case 'header': {
+                            const separator = value.indexOf(':');
+                            if (separator > 0) headers.push([value.slice(0, separator).trim(), value.slice(separator + 1).trim()]);
+                            break;
+                        }
+                        case 'json':
+                            headers.push(['Content-Type', 'application/json'], ['Accept', 'application/json']);
+                            data.push(value);
+                            break;
+                        case 'data': data.push(value); break;
+                        case 'user': headers.push(['Authorization', 'Basic ' + btoa(value)]); break;
+                        case 'cookie': headers.push(['Cookie', value]); break;
+                        case 'agent': headers.push(['User-Agent', value]); break;
+                        case 'referer': headers.push(['Referer', value]); break;
+                        case 'url': url = value; break;
+                    }
+                } else if (token === '-G' || token === '--get') {
+                    get = true;
+                } else if (token === '-I' || token === '--head') {
+                    method = 'HEAD';
+                } else if (ignoredValueFlags.includes(token)) {
+                    if (value === undefined) i++;
+                } else if (!token.startsWith('-') && url === null) {
+                    url = token;
+                }
+            }
+            if (!url) throw new Error('No URL found in the curl command');
+
+            let body = data.join('&');
+            if (get && body) {
+                url += (url.includes('?') ? '&' : '?') + body;
+                body = '';
+            }
+            if (body && !headers.some(([name]) => name.toLowerCase() === 'content-type')) {
+                headers.push(['Content-Type', 'application/x-www-form-urlencoded']);
+            }
+            if (!method) method = body ? 'POST' : 'GET';
+            return { method, url, headers, body };
+        }
+
+        // matchEndpoint finds the documented endpoint a URL calls, with the values of its path parameters
+        function matchEndpoint(method, url) {
+            let pathname;
+            try {
+                pathname = new URL(url, baseUrlSelect.value || currentOrigin).pathname;
+            } catch (e) {
+                return null;
+            }
+            const prefixes = [''];
+            Array.from(baseUrlSelect.options).forEach(option => {
+                try {
+                    const prefix = new URL(option.value || currentOrigin).pathname.replace(/\/$/, '');
+                    if (prefix && pathname.startsWith(prefix)) prefixes.push(prefix);
+                } catch (e) { }
+            });
+
+            for (const endpoint of Object.values(transformedApiData).flat()) {
+                if (endpoint.method.toUpperCase() !== method) continue;
+                const names = [];
+                const pattern = endpoint.path.split('/').map(segment => {
+                    const param = segment.match(/^\{(.+)\}$/);
+                    if (!param) return segment.replace(/[.*+?^$()|[\]\\]/g, '\\$&');
+                    names.push(param[1]);
+                    return '([^/]+)';
+                }).join('/');
+                const regex = new RegExp(`^${pattern}/?$`);
+                for (const prefix of prefixes) {
+                    const match = pathname.slice(prefix.length).match(regex);
+                    if (match) {
+                        const params = {};
+                        names.forEach((name, index) => params[name] = decodeURIComponent(match[index + 1]));
+                        return { endpoint, params };
+                    }
+                }
+            }
+            return null;
+        }
+
+        function importCurl() {
+            const status = document.getElementById('curlImportStatus');
+            let request;
+            try {
+                request = parseCurl(document.getElementById('curlInput').value);
+            } catch (error) {
+                status.textContent = error.message;
+                status.className = 'text-xs text-red-600 dark:text-red-400';
+                return;
+            }
+
+            const match = matchEndpoint(request.method, request.url);
+            if (match && match.endpoint !== currentEndpoint) {
+                selectEndpoint(match.endpoint);
+                switchTab('test');
+                switchTestMode('raw');
+            }
+            document.getElementById('rawMethod').value = request.method;
+            document.getElementById('rawUrl').value = request.url;
+            document.getElementById('rawHeaders').value = request.headers.map(([name, value]) => `${name}: ${value}`).join('\n');
+            let body = request.body;
+            try {
+                body = JSON.stringify(JSON.parse(body), null, 2);
+            } catch (e) { }
+            document.getElementById('rawBody').value = body;
+
+            status.textContent = match ? `Matched ${match.endpoint.method} ${match.endpoint.path}` : 'No documented endpoint matches this URL';
+            status.className = 'text-xs text-gray-500 dark:text-gray-400';
+        }
+
+        // rawRequest reads the raw editor; relative URLs are resolved against the selected base URL
+        function rawRequest() {
+            const method = document.getElementById('rawMethod').value;
+            let url = document.getElementById('rawUrl').value.trim();
+            if (!url) throw new Error('Enter a URL');
+            if (!/^https?:\/\//i.test(url)) {
+                url = (baseUrlSelect.value || currentOrigin).replace(/\/$/, '') + (url.startsWith('/') ? url : '/' + url);
+            }
+            const headers = {};
+            document.getElementById('rawHeaders').value.split('\n').forEach(line => {
+                const separator = line.indexOf(':');
+                if (separator > 0) headers[line.slice(0, separator).trim()] = line.slice(separator + 1).trim();
+            });
+            const body = ['GET', 'HEAD'].includes(method) ? '' : document.getElementById('rawBody').value;
+            return { method, url, headers, body };
+        }
+
+        async function sendRawRequest() {
+            const button = document.getElementById('rawSendButton');
+            button.disabled = true;
+            button.textContent = 'Sending...';
+            const startTime = Date.now();
+            try {
+                const request = rawRequest();
+                const match = matchEndpoint(request.method, request.url);
+                if (match) trackUsage({ type: 'try', method: match.endpoint.method, path: match.endpoint.path });
+                const response = await fetch(request.url, {
+                    method: request.method,
+                    credentials: requestCredentials(),
+                    headers: request.headers,
+                    body: request.body || undefined
+                });
+                await showTestResponse(response, Date.now() - startTime);
+            } catch (error) {
+                showTestError(error, Date.now() - startTime);
+            } finally {
+                button.disabled = false;
+                button.textContent = 'Send Request';
+            }
+        }
+
+        function renderRawScenarioOptions() {
+            const select = document.getElementById('rawScenario');
+            select.innerHTML = `<option value="new">New scenario</option>` +
+                scenarios.map((scenario, index) => `<option value="${index}">${escapeHtml(scenario.name)}</option>`).join('');
+        }
+
+        // saveRawRequestToScenario appends the raw request to a saved scenario; a
+        // request matching a documented endpoint keeps its path template and parameters
+        function saveRawRequestToScenario() {
+            let request;
+            try {
+                request = rawRequest();
+            } catch (error) {
+                showNotification(error.message, 'error');
+                return;
+            }
+
+            const match = matchEndpoint(request.method, request.url);
+            let body = request.body || null;
+            try {
+                body = body ? JSON.parse(body) : null;
+            } catch (e) { }
+
+            const choice = document.getElementById('rawScenario').value;
+            let scenario = scenarios[Number(choice)];
+            if (choice === 'new' || !scenario) {
+                scenario = {
+                    id: Date.now(),
+                    name: `Imported request ${new Date().toLocaleString()}`,
+                    description: '',
+                    executionMode: 'waterfall',
+                    requests: [],
+                    variables: {},
+                    authentication: { type: 'none' },
+                    created: new Date().toISOString(),
+                    modified: new Date().toISOString()
+                };
+                scenarios.push(scenario);
+            }
+            scenario.requests.push({
+                id: match ? match.endpoint.id : `raw-${Date.now()}`,
+                method: request.method,
+                path: match ? match.endpoint.path + new URL(request.url).search : request.url,
+                title: match ? match.endpoint.title : `${request.method} ${request.url}`,
+                config: {
+                    enabled: true,
+                    timeout: 30000,
+                    retries: 0,
+                    parameters: match ? match.params : {},
+                    headers: request.headers,
+                    body: body,
+                    useExampleBody: false
+                }
+            });
+            scenario.modified = new Date().toISOString();
+            saveScenarios();
+            renderScenariosGrid();
+            renderRawScenarioOptions();
+            document.getElementById('rawScenario').value = String(scenarios.indexOf(scenario));
+            showNotification(`Saved to scenario "${escapeHtml(scenario.name)}"`, 'success');
+        }
+
+        // serverVariableValues keeps the chosen value of each base URL variable by name
+        const serverVariableValues = {};
+
+        function resolveServerUrl(baseUrlOption) {
+            const variables = baseUrlOption.variables || {};
+            return baseUrlOption.url.replace(/\{([^{}]+)\}/g, (placeholder, name) => {
+                if (!variables[name]) return placeholder;
+                return serverVariableValues[name] ?? variables[name].default;
+            });
+        }
+
+        // renderServerVariables shows a picker per variable of the selected base
+        // URL; choosing a value rewrites the option values, which requests use as-is
+        function renderServerVariables(selectElement) {
+            const selected = selectElement?.options[selectElement.selectedIndex];
+            const server = selected && selected.dataset.serverIndex !== undefined
+                ? config.baseUrls[Number(selected.dataset.serverIndex)] : null;
+            const names = Object.keys(server?.variables || {});
+
+            ['serverVariables', 'serverVariablesDesktop'].forEach(id => {
+                const container = document.getElementById(id);
+                if (!container) return;
+                container.innerHTML = '';
+                container.classList.toggle('hidden', names.length === 0);
+                container.classList.toggle('flex', names.length > 0);
+
+                names.forEach(name => {
+                    const variable = server.variables[name];
+                    const control = document.createElement(variable.enum && variable.enum.length ? 'select' : 'input');
+                    if (control.tagName === 'SELECT') {
+                        variable.enum.forEach(value => {
+                            const option = document.createElement('option');
+                            option.value = value;
+                            option.textContent = `${name}: ${value}`;
+                            control.appendChild(option);
+                        });
+                    } else {
+                        control.type = 'text';
+                        control.placeholder = name;
+                    }
+                    control.value = serverVariableValues[name] ?? variable.default;
+                    control.title = variable.description || name;
+                    control.dataset.serverVariable = name;
+                    control.className = 'px-2 py-2 border border-gray-300 dark:border-[#212121] rounded-md bg-white dark:bg-black text-gray-900 dark:text-white text-sm';
+                    control.addEventListener('change', () => {
+                        serverVariableValues[name] = control.value;
+                        document.querySelectorAll(`[data-server-variable="${CSS.escape(name)}"]`).forEach(other => {
+                            other.value = control.value;
+                        });
+                        [baseUrlSelect, baseUrlSelectDesktop].forEach(select => {
+                            if (!select) return;
+                            Array.from(select.options).forEach(option => {
+                                if (option.dataset.serverIndex === undefined) return;
+                                const baseUrlOption = config.baseUrls[Number(option.dataset.serverIndex)];
+                                option.value = resolveServerUrl(baseUrlOption);
+                                option.textContent = `${baseUrlOption.name} - ${option.value}`;
+                            });
+                        });
+                    });
+                    container.appendChild(control);
+                });
+            });
+        }
+
+        // slaMs returns the endpoint's latency budget in milliseconds, 0 when it has none
+        function slaMs(endpoint) {
+            return endpoint && endpoint.sla ? endpoint.sla / 1e6 : 0;
+        }
+
+        function formatMs(ms) {
+            return ms >= 1000 ? `${+(ms / 1000).toFixed(2)}s` : `${+ms.toFixed(1)}ms`;
+        }
+
+        // latencyClass colors a measured duration against the budget
+        function latencyClass(duration, budget) {
+            if (!budget) return 'text-gray-500 dark:text-gray-400';
+            return duration <= budget ? 'text-green-600 dark:text-green-400' : 'text-red-600 dark:text-red-400';
+        }
+
+        function showResponseTime(duration) {
+            const budget = slaMs(currentEndpoint);
+            responseTime.textContent = budget ? `${duration}ms / ${formatMs(budget)} budget` : `${duration}ms`;
+            responseTime.className = `text-xs ${latencyClass(duration, budget)}`;
+            responseTime.title = budget ? (duration <= budget ? 'Within the expected response time' : 'Slower than the expected response time') : '';
+        }
+
+        function describeAuth(auth) {
+            switch (auth.type) {
+                case 'bearer':
+                    return auth.token ? 'Bearer Token (configured)' : 'Bearer Token (not configured)';
+                case 'basic':
+                    return (auth.username && auth.password) ? 'Basic Auth (configured)' : 'Basic Auth (not configured)';
+                case 'apikey':
+                    return auth.apiKey ? `API Key - ${auth.keyName || 'X-API-Key'} (configured)` : 'API Key (not configured)';
+                default:
+                    return 'No Authentication';
+            }
+        }
+
+        function getAuthHeaders() {
+            const headers = {};
+            switch (auth.type) {
+                case 'bearer':
+                    if (auth.token) {
+                        headers.Authorization = `Bearer ${auth.token}`;
+                    }
+                    break;
+                case 'basic':
+                    if (auth.username && auth.password) {
+                        headers.Authorization = `Basic ${btoa(auth.username + ':' + auth.password)}`;
+                    }
+                    break;
+                case 'apikey':
+                    if (auth.apiKey) {
+                        headers[auth.keyName] = auth.apiKey;
+                    }
+                    break;
+            }
+            return headers;
+        }
+
+        function updateThemeColor(color) {
+
+            let themeColorMeta = document.querySelector('meta[name="theme-color"]:not([media])');
+            if (!themeColorMeta) {
+                themeColorMeta = document.createElement('meta');
+                themeColorMeta.name = 'theme-color';
+                document.head.appendChild(themeColorMeta);
+            }
+            themeColorMeta.content = color;
+
+            const lightThemeMeta = document.querySelector('meta[name="theme-color"][media*="light"]');
+            const darkThemeMeta = document.querySelector('meta[name="theme-color"][media*="dark"]');
+            if (lightThemeMeta) lightThemeMeta.content = '#f9fafb';
+            if (darkThemeMeta) darkThemeMeta.content = '#0a0a0a';
+        }
+
+        function toggleSetting(settingName) {
+            settings[settingName] = !settings[settingName];
+            applySetting(settingName);
+            saveSettings();
+        }
+        function applySetting(settingName) {
+            const toggle = document.getElementById(`${settingName}Toggle`);
+            if (!toggle) return;
+            const toggleSlider = toggle.querySelector('div');
+            if (settings[settingName]) {
+                toggle.classList.add('toggle-active');
+                toggle.classList.remove('bg-gray-200', 'dark:bg-gray-600');
+                toggle.classList.add('bg-accent');
+                toggleSlider.classList.add('translate-x-5');
+                toggleSlider.classList.remove('translate-x-0');
+            } else {
+                toggle.classList.remove('toggle-active');
+                toggle.classList.add('bg-gray-200', 'dark:bg-gray-600');
+                toggle.classList.remove('bg-accent');
+                toggleSlider.classList.remove('translate-x-5');
+                toggleSlider.classList.add('translate-x-0');
+            }
+            switch (settingName) {
+                case 'darkMode':
+                    if (settings.darkMode) {
+                        document.documentElement.classList.add('dark');
+
+                        updateThemeColor('#0a0a0a');
+                    } else {
+                        document.documentElement.classList.remove('dark');
+
+                        updateThemeColor('#f9fafb');
+                    }
+                    break;
+                case 'compactMode':
+                    if (settings.compactMode) {
+                        sidebar.classList.add('w-70');
+                        sidebar.classList.remove('w-80');
+                    } else {
+                        sidebar.classList.remove('w-70');
+                        sidebar.classList.add('w-80');
+                    }
+
+                    renderEndpoints();
+                    break;
+                case 'showDescriptions':
+
+                    break;
+            }
+        }
+        // requestCredentials lets the browser send and keep the cookies of the
+        // target API across try-it requests, like a login followed by a fetch
+        function requestCredentials() {
+            return settings.keepCookies ? 'include' : 'same-origin';
+        }
+
+        function saveSettings() {
+            localStorage.setItem('apiDocsSettings', JSON.stringify(settings));
+        }
+        function loadSettings() {
+            const saved = localStorage.getItem('apiDocsSettings');
+            if (saved) {
+                settings = { ...settings, ...JSON.parse(saved) };
+            } else {
+
+                if (window.matchMedia && window.matchMedia('(prefers-color-scheme: dark)').matches) {
+                    settings.darkMode = true;
+                }
+            }
+            Object.keys(settings).forEach(settingName => {
+                applySetting(settingName);
+            });
+
+            const isDark = document.documentElement.classList.contains('dark');
+            updateThemeColor(isDark ? '#0a0a0a' : '#f9fafb');
+        }
+
+        function updateAuthInputs() {
+            const type = authType.value;
+            let html = '';
+            switch (type) {
+                case 'bearer':
+                    html = '<input type="text" class="w-full px-3 py-2 border border-gray-300 dark:border-[#383838] rounded-md bg-white dark:bg-[#212121] text-gray-900 dark:text-white mb-2" id="authToken" placeholder="Bearer token" value="' + auth.token + '">';
+                    break;
+                case 'basic':
+                    html = `
+                        <input type="text" class="w-full px-3 py-2 border border-gray-300 dark:border-[#383838] rounded-md bg-white dark:bg-[#212121] text-gray-900 dark:text-white mb-2" id="authUsername" placeholder="Username" value="${auth.username}">
+                        <input type="password" class="w-full px-3 py-2 border border-gray-300 dark:border-[#383838] rounded-md bg-white dark:bg-[#212121] text-gray-900 dark:text-white mb-2" id="authPassword" placeholder="Password" value="${auth.password}">
+                    `;
+                    break;
+                case 'apikey':
+                    html = `
+                        <input type="text" class="w-full px-3 py-2 border border-gray-300 dark:border-[#383838] rounded-md bg-white dark:bg-[#212121] text-gray-900 dark:text-white mb-2" id="authKeyName" placeholder="Header name (e.g., X-API-Key)" value="${auth.keyName}">
+                        <input type="text" class="w-full px-3 py-2 border border-gray-300 dark:border-[#383838] rounded-md bg-white dark:bg-[#212121] text-gray-900 dark:text-white mb-2" id="authApiKey" placeholder="API key value" value="${auth.apiKey}">
+                    `;
+                    break;
+            }
+            authInputs.innerHTML = html;
+        }
+        function saveAuthentication() {
+            auth.type = authType.value;
+            switch (auth.type) {
+                case 'bearer':
+                    auth.token = document.getElementById('authToken')?.value || '';
+                    break;
+                case 'basic':
+                    auth.username = document.getElementById('authUsername')?.value || '';
+                    auth.password = document.getElementById('authPassword')?.value || '';
+                    break;
+                case 'apikey':
+                    auth.keyName = document.getElementById('authKeyName')?.value || 'X-API-Key';
+                    auth.apiKey = document.getElementById('authApiKey')?.value || '';
+                    break;
+            }
+            localStorage.setItem('apiDocsAuth', JSON.stringify(auth));
+            authModal.classList.add('hidden');
+            authModal.classList.remove('flex');
+        }
+        function loadAuthentication() {
+            const saved = localStorage.getItem('apiDocsAuth');
+            if (saved) {
+                auth = { ...auth, ...JSON.parse(saved) };
+            }
+            authType.value = auth.type;
+            updateAuthInputs();
+        }
+
+        function setupEventListeners() {
+
+            document.addEventListener('click', (e) => {
+                if (e.target.closest('[data-copy-text]')) {
+                    const button = e.target.closest('[data-copy-text]');
+                    const textToCopy = button.getAttribute('data-copy-text');
+                    if (textToCopy) {
+                        copyToClipboard(textToCopy, button);
+                    }
+                }
+            });
+
+            document.addEventListener('click', (e) => {
+                if (e.target.closest('[data-beautify-target]')) {
+                    const button = e.target.closest('[data-beautify-target]');
+                    const targetId = button.getAttribute('data-beautify-target');
+                    const isBeautified = button.getAttribute('data-is-beautified') === 'true';
+                    const beautifiedJson = button.getAttribute('data-beautified');
+                    const compactJson = button.getAttribute('data-compact');
+                    const codeBlock = document.querySelector(`[data-beautify-id="${targetId}"]`);
+                    const codeContent = codeBlock.querySelector('.json-content');
+                    const copyButton = codeBlock.querySelector('.copy-button');
+                    if (isBeautified) {
+
+                        codeContent.innerHTML = syntaxHighlight(compactJson);
+                        button.innerHTML = `
+                            <svg class="copy-icon" viewBox="0 0 24 24" style="width: 14px; height: 14px;">
+                                <path d="M3 7h18v2H3V7zm0 4h18v2H3v-2z" fill="currentColor"/>
+                                <path d="M3 15h12v2H3v-2z" fill="currentColor"/>
+                            </svg>
+                            Beautify
+                        `;
+                        button.setAttribute('data-is-beautified', 'false');
+                        copyButton.setAttribute('data-copy-text', compactJson);
+                    } else {
+
+                        codeContent.innerHTML = syntaxHighlight(beautifiedJson);
+                        button.innerHTML = `
+                            <svg class="copy-icon" viewBox="0 0 24 24" style="width: 14px; height: 14px;">
+                                <path d="M3 3h18v2H3V3zm0 4h18v2H3V7zm0 4h18v2H3v-2zm0 4h18v2H3v-2z" fill="currentColor"/>
+                            </svg>
+                            Compact
+                        `;
+                        button.setAttribute('data-is-beautified', 'true');
+                        copyButton.setAttribute('data-copy-text', beautifiedJson);
+                    }
+                }
+            });
+
+            document.addEventListener('keydown', (e) => {
+                if (e.key === 'Escape' || e.key === 'Esc') {
+
+                    if (settingsModal && !settingsModal.classList.contains('hidden')) {
+                        settingsModal.classList.add('hidden');
+                        settingsModal.classList.remove('flex');
+                    }
+
+                    if (authModal && !authModal.classList.contains('hidden')) {
+                        authModal.classList.add('hidden');
+                        authModal.classList.remove('flex');
+                    }
+
+                    const chatSidebar = document.getElementById('chatSidebar');
+                    if (chatSidebar && !chatSidebar.classList.contains('hidden')) {
+                        chatSidebar.classList.add('hidden');
+                    }
+                }
+            });
+
+            searchInput.addEventListener('input', performSearch);
+            searchClear.addEventListener('click', clearSearch);
+
+            function handleBaseUrlChange(selectElement) {
+                renderServerVariables(selectElement);
+                if (currentEndpoint) {
+
+                    const selectedOption = selectElement.options[selectElement.selectedIndex];
+                    const selectedText = selectedOption ? selectedOption.textContent : 'Current';
+                    const baseUrlName = selectedText.split(' - ')[0];
+                    currentUrl.innerHTML = `
+                        <span class="base-url-badge">${baseUrlName}</span>
+                        <span class="endpoint-path">${currentEndpoint.path}</span>
+                    `;
+
+                    const selects = [baseUrlSelect, baseUrlSelectDesktop];
+                    selects.forEach(select => {
+                        if (select && select !== selectElement) {
+                            select.value = selectElement.value;
+                        }
+                    });
+                }
+            }
+
+            baseUrlSelect.addEventListener('change', (e) => handleBaseUrlChange(e.target));
+            if (baseUrlSelectDesktop) {
+                baseUrlSelectDesktop.addEventListener('change', (e) => handleBaseUrlChange(e.target));
+            }
+
+            document.querySelectorAll('.tab').forEach(tab => {
+                tab.addEventListener('click', () => {
+                    switchTab(tab.dataset.tab);
+                });
+            });
+
+            testButton.addEventListener('click', testEndpoint);
+            document.querySelectorAll('.test-mode-btn').forEach(button => {
+                button.addEventListener('click', () => switchTestMode(button.dataset.testMode));
+            });
+            document.getElementById('importCurl').addEventListener('click', importCurl);
+            document.getElementById('rawSendButton').addEventListener('click', sendRawRequest);
+            document.getElementById('graphqlSendButton').addEventListener('click', sendGraphQLRequest);
+            ['graphqlQuery', 'graphqlVariables', 'graphqlOperationName'].forEach(id => {
+                document.getElementById(id).addEventListener('input', saveGraphQLState);
+            });
+            document.getElementById('rawSaveScenario').addEventListener('click', saveRawRequestToScenario);
+
+            function openSettings() {
+                settingsModal.classList.remove('hidden');
+                settingsModal.classList.add('flex');
+            }
+            if (settingsBtnSidebar) {
+                settingsBtnSidebar.addEventListener('click', openSettings);
+            }
+
+            async function exportOpenApiYaml() {
+                const makeFilename = () => {
+                    const timestamp = new Date().toISOString().split('T')[0];
+                    const rawTitle = apiData?.info?.title || 'api';
+                    const slug = rawTitle.toLowerCase().replace(/[^a-z0-9]+/g, '-').replace(/^-+|-+$/g, '') || 'api';
+                    return `openapi-${slug}-${timestamp}.yaml`;
+                };
+
+                try {
+                    const docsPath = (config && config.docsPath) ? config.docsPath : '/docs';
+                    const normalizedDocsPath = docsPath.endsWith('/') ? docsPath.slice(0, -1) : docsPath;
+                    const openapiUrl = `${normalizedDocsPath}/openapi.yaml`;
+
+                    const filename = makeFilename();
+
+                    const response = await fetch(openapiUrl, {
+                        headers: { 'Accept': 'application/yaml' }
+                    });
+
+                    if (!response.ok) {
+                        throw new Error(`Unexpected status ${response.status}`);
+                    }
+
+                    const yamlBlob = await response.blob();
+                    const link = document.createElement('a');
+                    link.href = URL.createObjectURL(yamlBlob);
+                    link.download = filename;
+                    link.click();
+                    setTimeout(() => URL.revokeObjectURL(link.href), 0);
+                    showNotification(`OpenAPI YAML exported as ${filename}`, 'success', 3000);
+                } catch (error) {
+                    showNotification('Failed to export OpenAPI YAML', 'error', 3000);
+                    console.error('Export error:', error);
+                }
+            }
+            const exportJsonBtn = document.getElementById('exportJsonBtn');
+            const exportJsonBtnMobile = document.getElementById('exportJsonBtnMobile');
+            if (exportJsonBtn) {
+                exportJsonBtn.addEventListener('click', exportOpenApiYaml);
+            }
+            if (exportJsonBtnMobile) {
+                exportJsonBtnMobile.addEventListener('click', exportOpenApiYaml);
+            }
+            closeSettings.addEventListener('click', () => {
+                settingsModal.classList.add('hidden');
+                settingsModal.classList.remove('flex');
+            });
+
+            darkModeToggle.addEventListener('click', () => toggleSetting('darkMode'));
+            compactModeToggle.addEventListener('click', () => toggleSetting('compactMode'));
+            if (keepCookiesToggle) keepCookiesToggle.addEventListener('click', () => toggleSetting('keepCookies'));
+
+            document.querySelectorAll('.theme-color-btn').forEach(btn => {
+                btn.addEventListener('click', (e) => {
+                    const selectedTheme = e.target.getAttribute('data-theme');
+                    changeThemeColor(selectedTheme);
+                });
+            });
+
+            function openAuth() {
+                loadAuthentication();
+                authModal.classList.remove('hidden');
+                authModal.classList.add('flex');
+            }
+            authBtn.addEventListener('click', openAuth);
+            if (authBtnDesktop) {
+                authBtnDesktop.addEventListener('click', openAuth);
+            }
+            closeAuth.addEventListener('click', () => {
+                authModal.classList.add('hidden');
+                authModal.classList.remove('flex');
+            });
+
+            authType.addEventListener('change', updateAuthInputs);
+            saveAuth.addEventListener('click', saveAuthentication);
+
+            mobileMenuBtn.addEventListener('click', toggleMobileSidebar);
+            document.getElementById('mobileMenuBtnScenario').addEventListener('click', toggleMobileSidebar);
+            document.getElementById('mobileMenuBtnGuide').addEventListener('click', toggleMobileSidebar);
+            sidebarOverlay.addEventListener('click', closeMobileSidebar);
+
+            document.addEventListener('click', (e) => {
+                if (e.target.closest('[data-endpoint-id]') && window.innerWidth <= 768) {
+                    closeMobileSidebar();
+                }
+            });
+
+            window.addEventListener('resize', () => {
+                if (window.innerWidth > 768) {
+                    closeMobileSidebar();
+                }
+                
+                // Re-render scenario requests to adjust mobile/desktop layout
+                if (currentScenario && currentScenario.requests) {
+                    renderScenarioRequests(currentScenario.requests);
+                }
+            });
+
+            [settingsModal, authModal].forEach(modal => {
+                modal.addEventListener('click', (e) => {
+                    if (e.target === modal) {
+                        modal.classList.add('hidden');
+                        modal.classList.remove('flex');
+                    }
+                });
+            });
+
+            let formSaveTimeout;
+            document.addEventListener('input', (e) => {
+                const target = e.target;
+
+                if (target.matches('[name^="param_"]')) {
+                    clearTimeout(formSaveTimeout);
+                    formSaveTimeout = setTimeout(saveFormState, 300)
+                }
+            });
+        }
+
+        function hexToRgb(hex) {
+            const result = /^#?([a-f\d]{2})([a-f\d]{2})([a-f\d]{2})$/i.exec(hex);
+            return result ? {
+                r: parseInt(result[1], 16),
+                g: parseInt(result[2], 16),
+                b: parseInt(result[3], 16)
+            } : null;
+        }
+
+        function changeThemeColor(themeName) {
+            if (!themes[themeName]) return;
+            const newTheme = themes[themeName];
+            currentTheme = themeName;
+
+            localStorage.setItem('theme-color', themeName);
+
+            tailwind.config.theme.extend.colors.accent = newTheme.accent;
+            tailwind.config.theme.extend.colors['accent-hover'] = newTheme.accentHover;
+            tailwind.config.theme.extend.colors['accent-light'] = newTheme.accentLight;
+
+            document.documentElement.style.setProperty('--accent-color', newTheme.accent);
+            document.documentElement.style.setProperty('--accent-hover-color', newTheme.accentHover);
+            document.documentElement.style.setProperty('--accent-light-color', newTheme.accentLight);
+
+            const accentRgb = hexToRgb(newTheme.accent);
+            document.documentElement.style.setProperty('--accent-dark-bg', `rgba(${accentRgb.r}, ${accentRgb.g}, ${accentRgb.b}, 0.2)`);
+
+            document.querySelectorAll('.theme-color-btn').forEach(btn => {
+                const theme = btn.getAttribute('data-theme');
+                if (theme === themeName) {
+                    btn.style.border = '3px solid ' + newTheme.accent;
+                    btn.style.boxShadow = '0 0 0 2px rgba(255,255,255,0.8)';
+                } else {
+                    btn.style.border = '2px solid';
+                    btn.style.boxShadow = 'none';
+                }
+            });
+        }
+
+        function initThemeColor() {
+            let savedTheme = localStorage.getItem('theme-color') || 'green';
+            
+            // Validate theme exists, fallback to green if invalid
+            if (!themes[savedTheme]) {
+                savedTheme = 'green';
+                localStorage.setItem('theme-color', savedTheme);
+            }
+
+            const currentColors = themes[savedTheme];
+            document.documentElement.style.setProperty('--accent-color', currentColors.accent);
+            document.documentElement.style.setProperty('--accent-hover-color', currentColors.accentHover);
+            document.documentElement.style.setProperty('--accent-light-color', currentColors.accentLight);
+
+            const accentRgb = hexToRgb(currentColors.accent);
+            document.documentElement.style.setProperty('--accent-dark-bg', `rgba(${accentRgb.r}, ${accentRgb.g}, ${accentRgb.b}, 0.2)`);
+
+            document.querySelectorAll('.theme-color-btn').forEach(btn => {
+                const theme = btn.getAttribute('data-theme');
+                if (theme === savedTheme) {
+                    btn.style.border = '3px solid ' + themes[savedTheme].accent;
+                    btn.style.boxShadow = '0 0 0 2px rgba(255,255,255,0.8)';
+                } else {
+                    btn.style.border = '2px solid';
+                    btn.style.boxShadow = 'none';
+                }
+            });
+        }
+
+        function getEndpointDescription(endpoint) {
+            return endpoint.description || endpoint.summary || 'No description available';
+        }
+
+        function getRequestBodyExample(endpoint) {
+            return currentBodyContent(endpoint)?.example || null;
+        }
+
+        // requestBodyContents lists the primary request body content type followed by its alternatives
+        function requestBodyContents(endpoint) {
+            const body = endpoint && endpoint.requestBody;
+            if (!body) return [];
+            return [
+                { contentType: body.contentType || 'application/json', schema: body.schema, example: body.example },
+                ...(body.alternatives || [])
+            ];
+        }
+
+        function currentBodyContent(endpoint) {
+            const contents = requestBodyContents(endpoint);
+            const content = contents.find(content => content.contentType === requestContentType) || contents[0] || null;
+            return content ? withBodyVariant(content) : null;
+        }
+
+        // bodyUnion finds a discriminated union (oneOf with a discriminator) that is
+        // the body schema or one of its properties, with its variants and the selected one
+        function bodyUnion(schema) {
+            if (!schema) return null;
+            const candidates = [['', schema], ...Object.entries(schema.properties || {})];
+            for (const [property, candidate] of candidates) {
+                const discriminator = candidate?.discriminator?.propertyName;
+                if (!discriminator || !Array.isArray(candidate.oneOf)) continue;
+                const variants = candidate.oneOf.map(variant => ({
+                    value: String(variant.properties?.[discriminator]?.enum?.[0] ?? variant.title ?? ''),
+                    title: variant.title || '',
+                    example: variant.example
+                })).filter(variant => variant.value);
+                if (variants.length === 0) continue;
+                const selected = variants.some(variant => variant.value === bodyVariant) ? bodyVariant : variants[0].value;
+                return { property, variants, selected };
+            }
+            return null;
+        }
+
+        // withBodyVariant uses the example of the selected union variant for the body
+        function withBodyVariant(content) {
+            const union = bodyUnion(content.schema);
+            const variant = union && union.variants.find(option => option.value === union.selected);
+            if (!variant || variant.example === undefined || !bodyVariant) return content;
+            const example = union.property
+                ? { ...(content.example || {}), [union.property]: variant.example }
+                : variant.example;
+            return { ...content, example };
+        }
+
+        function isJsonContentType(contentType) {
+            return !contentType || /[/+]json\b/.test(contentType);
+        }
+
+        function isXmlContentType(contentType) {
+            return /[/+]xml\b/.test(contentType || '');
+        }
+
+        // formatBodyExample renders an example for the content type: XML is derived
+        // from the JSON example, other types show the JSON example
+        function formatBodyExample(example, contentType) {
+            return isXmlContentType(contentType) ? toXml(example, 'request') : JSON.stringify(example, null, 2);
+        }
+
+        function toXml(value, name, indent = '') {
+            if (Array.isArray(value)) {
+                return value.map(item => toXml(item, name, indent)).join('\n');
+            }
+            if (value !== null && typeof value === 'object') {
+                const children = Object.entries(value).map(([key, child]) => toXml(child, key, indent + '  ')).join('\n');
+                return `${indent}<${name}>\n${children}\n${indent}</${name}>`;
+            }
+            const text = String(value ?? '').replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;');
+            return `${indent}<${name}>${text}</${name}>`;
+        }
+
+        function getEndpointResponses(endpoint) {
+            return endpoint.responses || {};
+        }
+
+        function toggleMobileSidebar() {
+            sidebar.classList.toggle('mobile-open');
+            sidebarOverlay.classList.toggle('active');
+        }
+        function closeMobileSidebar() {
+            sidebar.classList.remove('mobile-open');
+            sidebarOverlay.classList.remove('active');
+        }
+
+        let isChatOpen = false;
+        let isTyping = false;
+        function setupSidebarResize() {
+            const resizeHandle = document.getElementById('resizeHandle');
+            const chatSidebar = document.getElementById('chatSidebar');
+            let isResizing = false;
+            if (resizeHandle) {
+                resizeHandle.addEventListener('mousedown', (e) => {
+                    isResizing = true;
+                    document.body.style.cursor = 'col-resize';
+                    document.body.style.userSelect = 'none';
+                    const startX = e.clientX;
+                    const startWidth = parseInt(window.getComputedStyle(chatSidebar).width, 10);
+                    function handleMouseMove(e) {
+                        if (!isResizing) return;
+                        const deltaX = startX - e.clientX;
+                        const newWidth = startWidth + deltaX;
+
+                        const minWidth = 280;
+                        const maxWidth = 600;
+                        const constrainedWidth = Math.min(Math.max(newWidth, minWidth), maxWidth);
+                        chatSidebar.style.width = constrainedWidth + 'px';
+                    }
+                    function handleMouseUp() {
+                        isResizing = false;
+                        document.body.style.cursor = '';
+                        document.body.style.userSelect = '';
+                        document.removeEventListener('mousemove', handleMouseMove);
+                        document.removeEventListener('mouseup', handleMouseUp);
+                    }
+                    document.addEventListener('mousemove', handleMouseMove);
+                    document.addEventListener('mouseup', handleMouseUp);
+                    e.preventDefault();
+                });
+            }
+        }
+        function setupLeftSidebarResize() {
+            const leftResizeHandle = document.getElementById('leftResizeHandle');
+            const leftSidebar = document.getElementById('sidebar');
+            let isResizing = false;
+            leftResizeHandle.addEventListener('mousedown', (e) => {
+                isResizing = true;
+                document.body.style.cursor = 'col-resize';
+                document.body.style.userSelect = 'none';
+                const startX = e.clientX;
+                const startWidth = parseInt(window.getComputedStyle(leftSidebar).width, 10);
+                function handleMouseMove(e) {
+                    if (!isResizing) return;
+                    const deltaX = e.clientX - startX;
+                    const newWidth = startWidth + deltaX;
+
+                    const minWidth = 280;
+                    const maxWidth = 500;
+                    const constrainedWidth = Math.min(Math.max(newWidth, minWidth), maxWidth);
+                    leftSidebar.style.width = constrainedWidth + 'px';
+                }
+                function handleMouseUp() {
+                    isResizing = false;
+                    document.body.style.cursor = '';
+                    document.body.style.userSelect = '';
+                    document.removeEventListener('mousemove', handleMouseMove);
+                    document.removeEventListener('mouseup', handleMouseUp);
+                }
+                document.addEventListener('mousemove', handleMouseMove);
+                document.addEventListener('mouseup', handleMouseUp);
+                e.preventDefault();
+            });
+        }
+        function toggleChatSidebar() {
+            const chatSidebar = document.getElementById('chatSidebar');
+            const chatToggleBtn = document.getElementById('chatAIToggle');
+            isChatOpen = !isChatOpen;
+            if (isChatOpen) {
+                chatSidebar.classList.remove('hidden');
+                chatToggleBtn.classList.add('bg-accent-hover');
+                chatToggleBtn.classList.remove('bg-accent');
+
+                setTimeout(() => {
+                    document.getElementById('chatInput').focus();
+                }, 100);
+            } else {
+                chatSidebar.classList.add('hidden');
+                chatToggleBtn.classList.remove('bg-accent-hover');
+                chatToggleBtn.classList.add('bg-accent');
+            }
+        }
+        function escapeHtml(text) {
+            const div = document.createElement('div');
+            div.textContent = text;
+            return div.innerHTML;
+        }
+        function safeScrollToBottom(container) {
+
+            container.scrollTop = container.scrollHeight - 8;
+        }
+        function addChatMessage(message, sender = 'user') {
+            const chatMessages = document.getElementById('chatMessages');
+            const messageDiv = document.createElement('div');
+            messageDiv.className = 'flex items-start gap-3 chat-message';
+            if (sender === 'user') {
+                messageDiv.innerHTML = `
+                    <div class="chat-bubble user bg-accent rounded-lg p-3 text-sm ml-auto">
+                        ${escapeHtml(message)}
+                    </div>
+                `;
+                messageDiv.classList.add('flex-row-reverse');
+            } else {
+
+                const looksLikeMDTable =
+                    /\n\|.+\|\n\|?[:\- ]+\|/s.test(message) ||
+                    /(^|\n)\s*\|.*\|/m.test(message)
+                if (looksLikeMDTable) {
+
+                    const finalHtml = renderMarkdown(message, { breaks: true });
+                    messageDiv.innerHTML = `
+                    <div class="w-6 h-6 bg-accent rounded-full flex items-center justify-center flex-shrink-0">
+                        <svg class="w-3 h-3 text-white" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+                        <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M13 10V3L4 14h7v7l9-11h-7z"></path>
+                        </svg>
+                    </div>
+                    <div class="chat-bubble bg-white dark:bg-[#171717] rounded-lg p-3 text-sm text-gray-900 dark:text-white max-w-full flex-1 prose prose-sm max-w-none chat-md">
+                        ${finalHtml}
+                    </div>
+                    `;
+                } else {
+
+                    let formattedMessage = escapeHtml(message);
+
+                    formattedMessage = formattedMessage.replace(/^### (.*$)/gm, '<h3 class="font-bold text-lg mt-4 mb-2">$1</h3>');
+                    formattedMessage = formattedMessage.replace(/^## (.*$)/gm, '<h2 class="font-bold text-xl mt-4 mb-2">$1</h2>');
+                    formattedMessage = formattedMessage.replace(/^# (.*$)/gm, '<h1 class="font-bold text-2xl mt-4 mb-2">$1</h1>');
+
+                    formattedMessage = formattedMessage.replace(/```(\w+)?\n([\s\S]*?)```/g, '<pre class="bg-[#202020] text-gray-100 p-3 rounded-md my-2 overflow-x-auto"><code class="text-sm">$2</code></pre>');
+
+                    formattedMessage = formattedMessage.replace(/`([^`]+)`/g, '<code class="bg-gray-200 dark:bg-black px-1 py-0.5 rounded text-xs font-mono">$1</code>');
+
+                    formattedMessage = formattedMessage.replace(/\*\*(.*?)\*\*/g, '<strong class="font-bold">$1</strong>');
+                    formattedMessage = formattedMessage.replace(/\*(.*?)\*/g, '<em class="italic">$1</em>');
+
+                    formattedMessage = formattedMessage.replace(/^- (.*$)/gm, '<li class="ml-4 list-disc list-inside">$1</li>');
+                    formattedMessage = formattedMessage.replace(/^(\d+)\. (.*$)/gm, '<li class="ml-4 list-decimal list-inside">$2</li>');
+
+                    formattedMessage = formattedMessage.replace(/\n\n/g, '</p><p class="mt-2">');
+                    formattedMessage = formattedMessage.replace(/\n/g, '<br>');
+                    if (!formattedMessage.startsWith('<h') && !formattedMessage.startsWith('<pre>')) {
+                        formattedMessage = '<p>' + formattedMessage + '</p>';
+                    }
+                    messageDiv.innerHTML = `
+                    <div class="w-6 h-6 bg-accent rounded-full flex items-center justify-center flex-shrink-0">
+                        <svg class="w-3 h-3 text-white" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+                        <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M13 10V3L4 14h7v7l9-11h-7z"></path>
+                        </svg>
+                    </div>
+                    <div class="chat-bubble bg-white dark:bg-[#171717] rounded-lg p-3 text-sm text-gray-900 dark:text-white max-w-full flex-1 prose prose-sm max-w-none">
+                        ${formattedMessage}
+                    </div>
+                    `;
+                }
+            }
+            chatMessages.appendChild(messageDiv);
+
+            setTimeout(() => safeScrollToBottom(chatMessages), 20);
+        }
+        // addChatSources shows the operations an answer refers to as chips that open them
+        function addChatSources(sources) {
+            if (!Array.isArray(sources) || sources.length === 0) return;
+            const endpoints = Object.values(transformedApiData).flat();
+            const sourcesDiv = document.createElement('div');
+            sourcesDiv.className = 'flex flex-wrap items-center gap-2 pl-9 chat-sources';
+            sourcesDiv.innerHTML = '<span class="text-xs text-gray-500 dark:text-gray-400">Sources</span>';
+            sources.forEach(source => {
+                const endpoint = endpoints.find(candidate => candidate.id === source.operationId)
+                    || endpoints.find(candidate => candidate.method.toUpperCase() === String(source.method).toUpperCase() && candidate.path === source.path);
+                if (!endpoint) return;
+                const chip = document.createElement('button');
+                chip.type = 'button';
+                chip.className = 'inline-flex items-center gap-1 px-2 py-1 rounded-full border border-gray-200 dark:border-gray-700 text-xs font-mono text-gray-900 dark:text-white hover:border-accent';
+                chip.title = endpoint.summary || '';
+                chip.innerHTML = `<span class="px-1 rounded font-semibold method-${escapeHtml(endpoint.method.toLowerCase())}">${escapeHtml(endpoint.method)}</span>${escapeHtml(endpoint.path)}`;
+                chip.addEventListener('click', () => {
+                    selectEndpoint(endpoint);
+                    trackUsage({ type: 'view', method: endpoint.method, path: endpoint.path });
+                });
+                sourcesDiv.appendChild(chip);
+            });
+            if (sourcesDiv.querySelector('button')) {
+                const chatMessages = document.getElementById('chatMessages');
+                chatMessages.appendChild(sourcesDiv);
+                setTimeout(() => safeScrollToBottom(chatMessages), 20);
+            }
+        }
+        // renderMarkdown turns markdown into sanitized HTML styled like the rest of the UI
+        function renderMarkdown(markdown, options = {}) {
+            const rawHtml = marked.parse(markdown, { gfm: true, breaks: Boolean(options.breaks) });
+
+            const temp = document.createElement('div');
+            temp.innerHTML = DOMPurify.sanitize(rawHtml);
+            temp.querySelectorAll('table').forEach((tbl) => {
+                const wrap = document.createElement('div');
+                wrap.className = 'mobile-scroll-table';
+                tbl.parentNode.insertBefore(wrap, tbl);
+                wrap.appendChild(tbl);
+            });
+            temp.querySelectorAll('h1').forEach(el => el.classList.add('font-bold', 'text-2xl', 'mt-4', 'mb-2'));
+            temp.querySelectorAll('h2').forEach(el => el.classList.add('font-bold', 'text-xl', 'mt-4', 'mb-2'));
+            temp.querySelectorAll('h3').forEach(el => el.classList.add('font-bold', 'text-lg', 'mt-4', 'mb-2'));
+            temp.querySelectorAll('pre').forEach(el => el.classList.add('bg-[#202020]', 'text-gray-100', 'p-3', 'rounded-md', 'my-2', 'overflow-x-auto'));
+            temp.querySelectorAll('code:not(pre code)').forEach(el => el.classList.add('bg-gray-200', 'dark:bg-black', 'px-1', 'py-0.5', 'rounded', 'text-xs', 'font-mono'));
+            return temp.innerHTML;
+        }
+
+        function showTypingIndicator() {
+            const chatMessages = document.getElementById('chatMessages');
+            const typingDiv = document.createElement('div');
+            typingDiv.className = 'flex items-start gap-3';
+            typingDiv.id = 'typingIndicator';
+            typingDiv.innerHTML = `
+                <div class="w-6 h-6 bg-accent rounded-full flex items-center justify-center flex-shrink-0">
+                    <svg class="w-3 h-3 text-white" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+                        <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M13 10V3L4 14h7v7l9-11h-7z"></path>
+                    </svg>
+                </div>
+                <div class="bg-white dark:bg-[#171717] rounded-lg p-3">
+                    <div class="chat-typing">
+                        <div class="chat-typing-dot"></div>
+                        <div class="chat-typing-dot"></div>
+                        <div class="chat-typing-dot"></div>
+                    </div>
+                </div>
+            `;
+            chatMessages.appendChild(typingDiv);
+            chatMessages.scrollTop = chatMessages.scrollHeight;
+        }
+        function hideTypingIndicator() {
+            const typingIndicator = document.getElementById('typingIndicator');
+            if (typingIndicator) {
+                typingIndicator.remove();
+            }
+        }
+        async function sendAIRequest(userMessage) {
+            if (isTyping) return;
+            isTyping = true;
+            showTypingIndicator();
+            try {
+
+                const chatRequest = {
+                    message: userMessage
+                    // Backend will auto-provide complete API context via getAPIContext()
+                    // No need to send context or endpoint from frontend
+                };
+
+                // Debug: Log the chat request payload
+                console.log('🔍 Frontend Chat Request Payload:', {
+                    message: chatRequest.message,
+                    note: 'Backend will auto-provide complete API context'
+                });
+
+                const csrfToken = document.querySelector('meta[name="csrf-token"]')?.getAttribute('content');
+                const response = await fetch(`${window.location.origin}${config.docsPath || '/docs'}/chat`, {
+                    method: 'POST',
+                    headers: {
+                        'Content-Type': 'application/json',
+                        'X-CSRF-TOKEN': csrfToken
+                    },
+                    body: JSON.stringify(chatRequest)
+                });
+                const data = await response.json();
+                hideTypingIndicator();
+                if (data.error) {
+
+                    addChatMessage(`Sorry, I encountered an error: ${data.error}`, 'ai');
+                } else {
+
+                    addChatMessage(data.response || 'Sorry, I couldn\'t generate a response.', 'ai');
+                    addChatSources(data.sources);
+                }
+            } catch (error) {
+                console.error('Chat error:', error);
+                hideTypingIndicator();
+
+                addChatMessage('Sorry, I\'m having trouble connecting to the AI service right now. Please try again later.', 'ai');
+            }
+            isTyping = false;
+        }
+        function sendChatMessage() {
+            const chatInput = document.getElementById('chatInput');
+            const message = chatInput.value.trim();
+            if (!message || isTyping) return;
+
+            addChatMessage(message, 'user');
+
+            chatInput.value = '';
+
+            try { chatInput.style.height = '50px'; chatInput.style.overflow = 'hidden'; chatInput.classList.remove('rounded-2xl'); chatInput.classList.add('rounded-full'); } catch (e) {}
+
+            try { chatInput.focus(); } catch (e) {}
+
+            sendAIRequest(message);
+        }
+
+        let monacoEditor = null;
+        let configBodyEditor = null;
+
+        function initMonacoEditor() {
+            require.config({ paths: { vs: 'https://cdn.jsdelivr.net/npm/monaco-editor@0.44.0/min/vs' } });
+            require(['vs/editor/editor.main'], function () {
+                const container = document.getElementById('testBodyInput');
+                if (container) {
+                    monacoEditor = monaco.editor.create(container, {
+                        value: '{\n  \n}',
+                        language: 'json',
+                        theme: document.documentElement.classList.contains('dark') ? 'vs-dark' : 'vs',
+                        automaticLayout: true,
+                        minimap: { enabled: false },
+                        scrollBeyondLastLine: false,
+                        wordWrap: 'on',
+                        lineNumbers: 'on',
+                        glyphMargin: false,
+                        folding: true,
+                        lineDecorationsWidth: 0,
+                        lineNumbersMinChars: 3,
+                        fontSize: 14,
+                        fontFamily: 'ui-monospace, SFMono-Regular, "SF Mono", Consolas, "Liberation Mono", Menlo, monospace'
+                    });
+
+                    let monacoSaveTimeout;
+                    monacoEditor.onDidChangeModelContent(() => {
+                        clearTimeout(monacoSaveTimeout);
+                        monacoSaveTimeout = setTimeout(saveFormState, 300)
+                    });
+
+                    const observer = new MutationObserver(function(mutations) {
+                        mutations.forEach(function(mutation) {
+                            if (mutation.type === 'attributes' && mutation.attributeName === 'class') {
+                                const isDark = document.documentElement.classList.contains('dark');
+                                monaco.editor.setTheme(isDark ? 'vs-dark' : 'vs');
+                            }
+                        });
+                    });
+                    observer.observe(document.documentElement, { attributes: true });
+                    if (currentEndpoint) syncRequestContentType();
+                }
+            });
+            document.getElementById('testContentType')?.addEventListener('change', (e) => selectRequestContentType(e.target.value));
+        }
+
+        function initConfigBodyEditor() {
+            if (!window.monaco || configBodyEditor) return;
+            const container = document.getElementById('configBodyEditor');
+            if (container) {
+                configBodyEditor = monaco.editor.create(container, {
+                    value: '{\n  \n}',
+                    language: 'json',
+                    theme: document.documentElement.classList.contains('dark') ? 'vs-dark' : 'vs',
+                    automaticLayout: true,
+                    minimap: { enabled: false },
+                    scrollBeyondLastLine: false,
+                    wordWrap: 'on',
+                    lineNumbers: 'on',
+                    glyphMargin: false,
+                    folding: true,
+                    lineDecorationsWidth: 0,
+                    lineNumbersMinChars: 3,
+                    fontSize: 14,
+                    fontFamily: 'ui-monospace, SFMono-Regular, "SF Mono", Consolas, "Liberation Mono", Menlo, monospace'
+                });
+
+                const observer = new MutationObserver(function(mutations) {
+                    mutations.forEach(function(mutation) {
+                        if (mutation.type === 'attributes' && mutation.attributeName === 'class') {
+                            if (configBodyEditor) {
+                                const isDark = document.documentElement.classList.contains('dark');
+                                monaco.editor.setTheme(isDark ? 'vs-dark' : 'vs');
+                            }
+                        }
+                    });
+                });
+                observer.observe(document.documentElement, { attributes: true });
+            }
+        }
+
+        let currentMode = 'docs'
+        function switchMode(mode) {
+            currentMode = mode;
+
+            document.querySelectorAll('.mode-toggle-btn').forEach(btn => {
+                btn.classList.remove('active');
+            });
+            document.querySelector(`[data-mode="${mode}"]`).classList.add('active');
+            
+            // Close mobile sidebar when switching modes
+            closeMobileSidebar();
+
+            const docsContent = document.getElementById('docsContent');
+            const scenarioContent = document.getElementById('scenarioContent');
+            closeGuide();
+            if (mode === 'docs') {
+                docsContent.style.display = 'block';
+                scenarioContent.style.display = 'none';
+
+                document.getElementById('endpointsContainer').style.display = 'block';
+                document.getElementById('searchInput').style.display = 'block';
+                document.getElementById('searchInput').parentElement.style.display = 'block';
+            } else if (mode === 'scenario') {
+                docsContent.style.display = 'none';
+                scenarioContent.style.display = 'block';
+
+                document.getElementById('endpointsContainer').style.display = 'block';
+                document.getElementById('searchInput').style.display = 'block';
+                document.getElementById('searchInput').parentElement.style.display = 'block';
+
+                document.getElementById('searchInput').placeholder = 'Search endpoints to add to scenario...';
+            }
+
+            localStorage.setItem('bytedocs-mode', mode);
+        }
+        function initModeToggle() {
+
+            document.getElementById('docsMode').addEventListener('click', () => switchMode('docs'));
+            document.getElementById('scenarioMode').addEventListener('click', () => switchMode('scenario'));
+
+            const savedMode = localStorage.getItem('bytedocs-mode');
+            if (savedMode && ['docs', 'scenario'].includes(savedMode)) {
+                switchMode(savedMode);
+            } else {
+                switchMode('docs')
+            }
+        }
+
+        function switchScenarioTab(tab) {
+
+            const informationTab = document.getElementById('informationTab');
+            const endpointsTab = document.getElementById('endpointsTab');
+            const informationContent = document.getElementById('informationTabContent');
+            const endpointsContent = document.getElementById('endpointsTabContent');
+
+            informationTab.classList.remove('border-accent', 'text-accent', 'bg-accent/5');
+            informationTab.classList.add('border-transparent', 'text-gray-500', 'dark:text-gray-400', 'hover:text-gray-700', 'dark:hover:text-gray-300', 'hover:border-gray-300');
+            endpointsTab.classList.remove('border-accent', 'text-accent', 'bg-accent/5');
+            endpointsTab.classList.add('border-transparent', 'text-gray-500', 'dark:text-gray-400', 'hover:text-gray-700', 'dark:hover:text-gray-300', 'hover:border-gray-300');
+
+            informationContent.classList.add('hidden');
+            endpointsContent.classList.add('hidden');
+
+            if (tab === 'information') {
+                informationTab.classList.remove('border-transparent', 'text-gray-500', 'dark:text-gray-400', 'hover:text-gray-700', 'dark:hover:text-gray-300', 'hover:border-gray-300');
+                informationTab.classList.add('border-accent', 'text-accent', 'bg-accent/5');
+                informationContent.classList.remove('hidden');
+            } else if (tab === 'endpoints') {
+                endpointsTab.classList.remove('border-transparent', 'text-gray-500', 'dark:text-gray-400', 'hover:text-gray-700', 'dark:hover:text-gray-300', 'hover:border-gray-300');
+                endpointsTab.classList.add('border-accent', 'text-accent', 'bg-accent/5');
+                endpointsContent.classList.remove('hidden');
+            }
+        }
+
+        function switchMobileScenarioTab(tab) {
+            const mobileInfoTab = document.getElementById('mobileInfoTab');
+            const mobileEndpointsTab = document.getElementById('mobileEndpointsTab');
+            const mobileSequenceTab = document.getElementById('mobileSequenceTab');
+            const mobileInformationContent = document.getElementById('mobileInformationContent');
+            const mobileEndpointsContent = document.getElementById('mobileEndpointsContent');
+            const mobileSequenceContent = document.getElementById('mobileSequenceContent');
+
+            // Reset all tabs
+            [mobileInfoTab, mobileEndpointsTab, mobileSequenceTab].forEach(tabEl => {
+                tabEl.classList.remove('border-accent', 'text-accent', 'bg-accent/5');
+                tabEl.classList.add('border-transparent', 'text-gray-500', 'dark:text-gray-400');
+            });
+
+            // Hide all content
+            [mobileInformationContent, mobileEndpointsContent, mobileSequenceContent].forEach(content => {
+                content.classList.add('hidden');
+            });
+
+            // Show selected tab and content
+            if (tab === 'information') {
+                mobileInfoTab.classList.remove('border-transparent', 'text-gray-500', 'dark:text-gray-400');
+                mobileInfoTab.classList.add('border-accent', 'text-accent', 'bg-accent/5');
+                mobileInformationContent.classList.remove('hidden');
+            } else if (tab === 'endpoints') {
+                mobileEndpointsTab.classList.remove('border-transparent', 'text-gray-500', 'dark:text-gray-400');
+                mobileEndpointsTab.classList.add('border-accent', 'text-accent', 'bg-accent/5');
+                mobileEndpointsContent.classList.remove('hidden');
+                // Sync endpoints content for mobile
+                syncEndpointsToMobile();
+            } else if (tab === 'sequence') {
+                mobileSequenceTab.classList.remove('border-transparent', 'text-gray-500', 'dark:text-gray-400');
+                mobileSequenceTab.classList.add('border-accent', 'text-accent', 'bg-accent/5');
+                mobileSequenceContent.classList.remove('hidden');
+                // Sync sequence content for mobile
+                syncSequenceToMobile();
+            }
+        }
+
+        function syncEndpointsToMobile() {
+            // No need to manually sync anymore, populateAvailableEndpoints handles both
+            populateAvailableEndpoints(document.getElementById('endpointSearch')?.value || '');
+        }
+
+        function syncSequenceToMobile() {
+            // No need to manually sync anymore, renderScenarioRequests handles both
+            if (currentScenario && currentScenario.requests) {
+                renderScenarioRequests(currentScenario.requests);
+            }
+        }
+
+        function syncMobileToDesktop() {
+            // Sync form data from mobile to desktop
+            const mobileScenarioName = document.getElementById('mobileScenarioName');
+            const desktopScenarioName = document.getElementById('scenarioName');
+            if (mobileScenarioName && desktopScenarioName) {
+                desktopScenarioName.value = mobileScenarioName.value;
+            }
+
+            const mobileScenarioDescription = document.getElementById('mobileScenarioDescription');
+            const desktopScenarioDescription = document.getElementById('scenarioDescription');
+            if (mobileScenarioDescription && desktopScenarioDescription) {
+                desktopScenarioDescription.value = mobileScenarioDescription.value;
+            }
+
+            ['PreRequestScript', 'PostResponseScript'].forEach(field => {
+                const mobileScript = document.getElementById('mobileScenario' + field);
+                const desktopScript = document.getElementById('scenario' + field);
+                if (mobileScript && desktopScript) {
+                    desktopScript.value = mobileScript.value;
+                }
+            });
+
+            // Sync execution mode
+            const mobileExecutionMode = document.querySelector('input[name="mobileExecutionMode"]:checked');
+            const desktopExecutionMode = document.querySelector('input[name="executionMode"][value="' + (mobileExecutionMode?.value || 'waterfall') + '"]');
+            if (desktopExecutionMode) {
+                desktopExecutionMode.checked = true;
+            }
+
+            // Sync auth type
+            const mobileAuthType = document.getElementById('mobileScenarioAuthType');
+            const desktopAuthType = document.getElementById('scenarioAuthType');
+            if (mobileAuthType && desktopAuthType) {
+                desktopAuthType.value = mobileAuthType.value;
+            }
+        }
+
+        let scenarios = [];
+        let currentScenario = null;
+        let isEditingScenario = false;
+
+        function loadScenarios() {
+            const savedScenarios = localStorage.getItem('bytedocs-scenarios');
+            if (savedScenarios) {
+                scenarios = JSON.parse(savedScenarios);
+            }
+            renderScenariosGrid();
+        }
+
+        function saveScenarios() {
+            localStorage.setItem('bytedocs-scenarios', JSON.stringify(scenarios));
+        }
+
+        function renderScenariosGrid() {
+
+            filteredScenarios = [...scenarios];
+
+            const searchInput = document.getElementById('scenarioSearchInput');
+            if (searchInput) searchInput.value = '';
+            const grid = document.getElementById('scenariosGrid');
+            const addCard = document.getElementById('addScenarioCard');
+
+            const existingCards = grid.querySelectorAll('.scenario-card');
+            existingCards.forEach(card => card.remove());
+
+            hideNoResultsMessage();
+
+            scenarios.forEach((scenario, index) => {
+                const card = createScenarioCard(scenario, index);
+                grid.insertBefore(card, addCard);
+            });
+        }
+
+        const scenarioIcons = [
+            `<svg class="w-5 h-5 text-white" fill="currentColor" viewBox="0 0 20 20"><path d="M9 12l2 2 4-4m6 2a9 9 0 11-18 0 9 9 0 0118 0z"/></svg>`,
+            `<svg class="w-5 h-5 text-white" fill="currentColor" viewBox="0 0 20 20"><path fill-rule="evenodd" d="M11.49 3.17c-.38-1.56-2.6-1.56-2.98 0a1.532 1.532 0 01-2.286.948c-1.372-.836-2.942.734-2.106 2.106.54.886.061 2.042-.947 2.287-1.561.379-1.561 2.6 0 2.978a1.532 1.532 0 01.947 2.287c-.836 1.372.734 2.942 2.106 2.106a1.532 1.532 0 012.287.947c.379 1.561 2.6 1.561 2.978 0a1.533 1.533 0 012.287-.947c1.372.836 2.942-.734 2.106-2.106a1.533 1.533 0 01.947-2.287c1.561-.379 1.561-2.6 0-2.978a1.532 1.532 0 01-.947-2.287c.836-1.372-.734-2.942-2.106-2.106a1.532 1.532 0 01-2.287-.947zM10 13a3 3 0 100-6 3 3 0 000 6z" clip-rule="evenodd"/></svg>`,
+            `<svg class="w-5 h-5 text-white" fill="currentColor" viewBox="0 0 20 20"><path d="M13 6a3 3 0 11-6 0 3 3 0 016 0zM18 8a2 2 0 11-4 0 2 2 0 014 0zM14 15a4 4 0 00-8 0v3h8v-3z"/></svg>`,
+            `<svg class="w-5 h-5 text-white" fill="currentColor" viewBox="0 0 20 20"><path fill-rule="evenodd" d="M3 4a1 1 0 011-1h4a1 1 0 010 2H6.414l2.293 2.293a1 1 0 01-1.414 1.414L5 6.414V8a1 1 0 01-2 0V4zm9 1a1 1 0 010-2h4a1 1 0 011 1v4a1 1 0 01-2 0V6.414l-2.293 2.293a1 1 0 11-1.414-1.414L13.586 5H12zm-9 7a1 1 0 012 0v1.586l2.293-2.293a1 1 0 111.414 1.414L6.414 15H8a1 1 0 010 2H4a1 1 0 01-1-1v-4zm13-1a1 1 0 011 1v4a1 1 0 01-1 1h-4a1 1 0 010-2h1.586l-2.293-2.293a1 1 0 111.414-1.414L15.586 13H14a1 1 0 01-1-1z" clip-rule="evenodd"/></svg>`,
+            `<svg class="w-5 h-5 text-white" fill="currentColor" viewBox="0 0 20 20"><path fill-rule="evenodd" d="M6 2a2 2 0 00-2 2v12a2 2 0 002 2h8a2 2 0 002-2V4a2 2 0 00-2-2H6zm1 2a1 1 0 000 2h6a1 1 0 100-2H7zm6 7a1 1 0 011 1v3a1 1 0 11-2 0v-3a1 1 0 011-1zm-3 3a1 1 0 011 1v1a1 1 0 11-2 0v-1a1 1 0 011-1zm-4 1a1 1 0 011 1v.01a1 1 0 11-2 0V16a1 1 0 011-1z" clip-rule="evenodd"/></svg>`,
+            `<svg class="w-5 h-5 text-white" fill="currentColor" viewBox="0 0 20 20"><path d="M3 4a1 1 0 011-1h12a1 1 0 011 1v2a1 1 0 01-1 1H4a1 1 0 01-1-1V4zM3 10a1 1 0 011-1h6a1 1 0 011 1v6a1 1 0 01-1 1H4a1 1 0 01-1-1v-6zM14 9a1 1 0 00-1 1v6a1 1 0 001 1h2a1 1 0 001-1v-6a1 1 0 00-1-1h-2z"/></svg>`,
+            `<svg class="w-5 h-5 text-white" fill="currentColor" viewBox="0 0 20 20"><path fill-rule="evenodd" d="M10 18a8 8 0 100-16 8 8 0 000 16zm3.707-8.293l-3-3a1 1 0 00-1.414 0l-3 3a1 1 0 001.414 1.414L9 9.414V13a1 1 0 102 0V9.414l1.293 1.293a1 1 0 001.414-1.414z" clip-rule="evenodd"/></svg>`,
+            `<svg class="w-5 h-5 text-white" fill="currentColor" viewBox="0 0 20 20"><path d="M2 10a8 8 0 018-8v8h8a8 8 0 11-16 0z"/><path d="M12 2.252A8.014 8.014 0 0117.748 8H12V2.252z"/></svg>`,
+            `<svg class="w-5 h-5 text-white" fill="currentColor" viewBox="0 0 20 20"><path fill-rule="evenodd" d="M12.395 2.553a1 1 0 00-1.45-.385c-.345.23-.614.558-.822.88-.214.33-.403.713-.57 1.116-.334.804-.614 1.768-.84 2.734a31.365 31.365 0 00-.613 3.58 2.64 2.64 0 01-.945-1.067c-.328-.68-.398-1.534-.398-2.654A1 1 0 005.05 6.05 6.981 6.981 0 003 11a7 7 0 1011.95-4.95c-.592-.591-.98-.985-1.348-1.467-.363-.476-.724-1.063-1.207-2.03zM12.12 15.12A3 3 0 017 13s.879.5 2.5.5c0-1 .5-4 1.25-4.5.5 1 .786 1.293 1.371 1.879A2.99 2.99 0 0113 13a2.99 2.99 0 01-.879 2.121z" clip-rule="evenodd"/></svg>`,
+            `<svg class="w-5 h-5 text-white" fill="currentColor" viewBox="0 0 20 20"><path fill-rule="evenodd" d="M11.3 1.046A1 1 0 0112 2v5h4a1 1 0 01.82 1.573l-7 10A1 1 0 018 18v-5H4a1 1 0 01-.82-1.573l7-10a1 1 0 011.12-.38z" clip-rule="evenodd"/></svg>`,
+        ];
+
+        const scenarioGradients = [
+            'from-pink-500 to-rose-500',
+            'from-purple-500 to-indigo-500', 
+            'from-blue-500 to-cyan-500',
+            'from-green-500 to-emerald-500',
+            'from-yellow-500 to-orange-500',
+            'from-red-500 to-pink-500',
+            'from-indigo-500 to-purple-500',
+            'from-cyan-500 to-blue-500',
+            'from-emerald-500 to-teal-500',
+            'from-orange-500 to-red-500',
+            'from-violet-500 to-purple-500',
+            'from-teal-500 to-green-500',
+            'from-amber-500 to-yellow-500',
+            'from-rose-500 to-pink-500'
+        ];
+
+        function getScenarioVisuals(scenarioName) {
+
+            let hash = 0;
+            for (let i = 0; i < scenarioName.length; i++) {
+                const char = scenarioName.charCodeAt(i);
+                hash = ((hash << 5) - hash) + char;
+                hash = hash & hash
+            }
+            const iconIndex = Math.abs(hash) % scenarioIcons.length;
+            const gradientIndex = Math.abs(hash >> 8) % scenarioGradients.length;
+            return {
+                icon: scenarioIcons[iconIndex],
+                gradient: scenarioGradients[gradientIndex]
+            };
+        }
+
+        function createScenarioCard(scenario, index) {
+            const card = document.createElement('div');
+            card.className = 'scenario-card bg-white dark:bg-[#171717] border border-gray-200 dark:border-[#2c2d2d] rounded-lg p-4 sm:p-6 hover:shadow-lg transition-all duration-200 cursor-pointer';
+
+            card.addEventListener('click', (e) => {
+
+                if (e.target.closest('button')) return;
+                showScenarioDetails(index);
+            });
+            const executionMode = scenario.executionMode || 'waterfall';
+            const isParallel = executionMode === 'parallel';
+
+            const visuals = getScenarioVisuals(scenario.name);
+
+            const modeConfig = isParallel ? {
+                badgeColor: 'bg-orange-500',
+                badgeText: 'PARALLEL'
+            } : {
+                badgeColor: 'bg-blue-500',
+                badgeText: 'WATERFALL'
+            };
+            card.innerHTML = `
+                <div class="flex items-start justify-between mb-4">
+                    <div class="flex items-center gap-2 sm:gap-3 flex-1 min-w-0">
+                        <div class="w-8 h-8 sm:w-10 sm:h-10 bg-gradient-to-br ${visuals.gradient} rounded-lg flex items-center justify-center flex-shrink-0">
+                            ${visuals.icon}
+                        </div>
+                        <div class="min-w-0 flex-1">
+                            <div class="flex flex-col sm:flex-row sm:items-center sm:gap-2 mb-1">
+                                <h3 class="font-semibold text-gray-900 dark:text-white text-sm sm:text-base truncate">${scenario.name}</h3>
+                                <span class="text-xs px-2 py-0.5 ${modeConfig.badgeColor} text-white rounded-full font-medium self-start sm:self-auto">${modeConfig.badgeText}</span>
+                            </div>
+                            <p class="text-xs sm:text-sm text-gray-500 dark:text-gray-400">${scenario.requests.length} request${scenario.requests.length !== 1 ? 's' : ''}</p>
+                        </div>
+                    </div>
+                    <div class="flex items-center gap-1 sm:gap-2 flex-shrink-0">
+                        <button class="text-gray-400 hover:text-gray-600 dark:hover:text-gray-300 transition-colors duration-200" title="Export JSON" onclick="exportScenario(${index})">
+                            <svg class="w-4 h-4" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+                                <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M12 10v6m0 0l-3-3m3 3l3-3m2 8H7a2 2 0 01-2-2V5a2 2 0 012-2h5.586a1 1 0 01.707.293l5.414 5.414a1 1 0 01.293.707V19a2 2 0 01-2 2z"/>
+                            </svg>
+                        </button>
+                        <button class="text-gray-400 hover:text-gray-600 dark:hover:text-gray-300 transition-colors duration-200" title="Edit" onclick="editScenario(${index})">
+                            <svg class="w-4 h-4" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+                                <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M11 5H6a2 2 0 00-2 2v11a2 2 0 002 2h11a2 2 0 002-2v-5m-1.414-9.414a2 2 0 112.828 2.828L11.828 15H9v-2.828l8.586-8.586z"/>
+                            </svg>
+                        </button>
+                        <button class="text-gray-400 hover:text-red-500 transition-colors duration-200" title="Delete" onclick="deleteScenario(${index})">
+                            <svg class="w-4 h-4" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+                                <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M19 7l-.867 12.142A2 2 0 0116.138 21H7.862a2 2 0 01-1.995-1.858L5 7m5 4v6m4-6v6m1-10V4a1 1 0 00-1-1h-4a1 1 0 00-1 1v3M4 7h16"/>
+                            </svg>
+                        </button>
+                    </div>
+                </div>
+                <div class="space-y-2 mb-4">
+                    ${scenario.requests.slice(0, 3).map(req => `
+                        <div class="flex items-center gap-2 text-xs sm:text-sm text-gray-600 dark:text-gray-400">
+                            <span class="w-2 h-2 ${getMethodColor(req.method)} rounded-full flex-shrink-0"></span>
+                            <span class="method-${req.method.toLowerCase()} flex-shrink-0">${req.method}</span>
+                            <span class="truncate">${req.path}</span>
+                        </div>
+                    `).join('')}
+                    ${scenario.requests.length > 3 ? `<p class="text-xs text-gray-500 dark:text-gray-400">+${scenario.requests.length - 3} more requests</p>` : ''}
+                </div>
+                <div class="flex flex-col sm:flex-row sm:items-center sm:justify-between gap-2">
+                    <span class="text-xs text-gray-500 dark:text-gray-400 truncate">${scenario.description || 'No description'}</span>
+                    <button class="bg-accent hover:bg-accent-hover text-white px-3 py-1.5 rounded text-xs font-medium transition-colors duration-200 flex-shrink-0" onclick="runScenario(${index})">
+                        Run Scenario
+                    </button>
+                </div>
+            `;
+            return card;
+        }
+
+        function getMethodColor(method) {
+            switch(method.toUpperCase()) {
+                case 'GET': return 'bg-blue-500';
+                case 'POST': return 'bg-green-500';
+                case 'PUT': return 'bg-yellow-500';
+                case 'PATCH': return 'bg-purple-500';
+                case 'DELETE': return 'bg-red-500';
+                default: return 'bg-gray-500';
+            }
+        }
+
+        function resetToCleanCreateState() {
+            isEditingScenario = false;
+            currentScenario = { name: '', description: '', requests: [], variables: {}, authentication: { type: 'none' } };
+
+            document.getElementById('scenarioName').value = '';
+            document.getElementById('scenarioDescription').value = '';
+            document.getElementById('mobileScenarioName').value = '';
+            document.getElementById('mobileScenarioDescription').value = '';
+            setScenarioScriptInputs({});
+            
+            const endpointSearch = document.getElementById('endpointSearch');
+            if (endpointSearch) endpointSearch.value = '';
+            const mobileEndpointSearch = document.getElementById('mobileEndpointSearch');
+            if (mobileEndpointSearch) mobileEndpointSearch.value = '';
+
+            const waterfallMode = document.getElementById('waterfallMode');
+            if (waterfallMode) waterfallMode.checked = true;
+            const mobileWaterfallMode = document.querySelector('input[name="mobileExecutionMode"][value="waterfall"]');
+            if (mobileWaterfallMode) mobileWaterfallMode.checked = true;
+
+            const container = document.getElementById('scenarioRequests');
+            if (container) {
+                const existingRequests = container.querySelectorAll('.scenario-request-item');
+                existingRequests.forEach(item => item.remove());
+            }
+
+            const emptyMessage = document.getElementById('emptyScenarioMessage');
+            if (emptyMessage) emptyMessage.style.display = 'block';
+
+            updateLeftScenarioButton();
+        }
+
+        function openScenarioModal(scenario = null) {
+            const modal = document.getElementById('scenarioModal');
+            const title = document.getElementById('scenarioModalTitle');
+            if (scenario) {
+
+                isEditingScenario = true;
+                currentScenario = { ...scenario }
+                title.textContent = 'Edit Scenario';
+                document.getElementById('scenarioName').value = scenario.name;
+                document.getElementById('scenarioDescription').value = scenario.description || '';
+                
+                // Also populate mobile form
+                document.getElementById('mobileScenarioName').value = scenario.name;
+                document.getElementById('mobileScenarioDescription').value = scenario.description || '';
+                setScenarioScriptInputs(scenario);
+
+                const executionMode = scenario.executionMode || 'waterfall';
+                const waterfallMode = document.getElementById('waterfallMode');
+                const parallelMode = document.getElementById('parallelMode');
+                const mobileWaterfallMode = document.querySelector('input[name="mobileExecutionMode"][value="waterfall"]');
+                const mobileParallelMode = document.querySelector('input[name="mobileExecutionMode"][value="parallel"]');
+                
+                if (executionMode === 'parallel') {
+                    if (parallelMode) parallelMode.checked = true;
+                    if (mobileParallelMode) mobileParallelMode.checked = true;
+                } else {
+                    if (waterfallMode) waterfallMode.checked = true;
+                    if (mobileWaterfallMode) mobileWaterfallMode.checked = true;
+                }
+                renderScenarioRequests(scenario.requests || []);
+            } else {
+
+                title.textContent = isEditingScenario ? 'Edit Scenario' : 'Create New Scenario';
+
+                document.getElementById('scenarioName').value = currentScenario.name || '';
+                document.getElementById('scenarioDescription').value = currentScenario.description || '';
+                setScenarioScriptInputs(currentScenario);
+
+                const executionMode = currentScenario.executionMode || 'waterfall';
+                const waterfallMode = document.getElementById('waterfallMode');
+                const parallelMode = document.getElementById('parallelMode');
+                if (executionMode === 'parallel') {
+                    if (parallelMode) parallelMode.checked = true;
+                } else {
+                    if (waterfallMode) waterfallMode.checked = true;
+                }
+
+                renderScenarioRequests(currentScenario.requests || []);
+            }
+
+            loadScenarioAuthentication();
+
+            updateLeftScenarioButton();
+
+            switchScenarioTab('information');
+            switchMobileScenarioTab('information');
+
+            populateAvailableEndpoints();
+            modal.classList.remove('hidden');
+            modal.classList.add('flex');
+        }
+
+        function populateAvailableEndpoints(searchQuery = '') {
+            const container = document.getElementById('availableEndpoints');
+            const mobileContainer = document.getElementById('mobileAvailableEndpoints');
+            container.innerHTML = '';
+            if (mobileContainer) mobileContainer.innerHTML = '';
+            const query = searchQuery.toLowerCase();
+
+            Object.keys(transformedApiData).forEach(category => {
+                const categoryEndpoints = transformedApiData[category].filter(endpoint => {
+                    if (!query) return true;
+                    return (
+                        endpoint.path.toLowerCase().includes(query) ||
+                        endpoint.method.toLowerCase().includes(query) ||
+                        (endpoint.title && endpoint.title.toLowerCase().includes(query)) ||
+                        category.toLowerCase().includes(query)
+                    );
+                });
+                if (categoryEndpoints.length === 0) return;
+                const categoryDiv = document.createElement('div');
+                categoryDiv.className = 'mb-2';
+                categoryDiv.innerHTML = `<h4 class="text-xs font-medium text-gray-600 dark:text-gray-400 mb-1">${category.toUpperCase()}</h4>`;
+                categoryEndpoints.forEach(endpoint => {
+
+                    const isSelected = currentScenario && currentScenario.requests && 
+                        currentScenario.requests.some(req => 
+                            req.method === endpoint.method && req.path === endpoint.path
+                        );
+                    const endpointBtn = document.createElement('button');
+
+                    if (isSelected) {
+                        endpointBtn.className = 'w-full text-left p-2 text-xs border border-accent bg-accent/10 rounded mb-1 opacity-60 cursor-not-allowed transition-colors duration-200';
+                        endpointBtn.disabled = true;
+                    } else {
+                        endpointBtn.className = 'w-full text-left p-2 text-xs border border-gray-200 dark:border-[#2c2d2d] rounded mb-1 hover:bg-gray-50 dark:hover:bg-[#2c2d2d] transition-colors duration-200';
+                    }
+
+                    const pathText = query ? highlightMatch(endpoint.path, query) : endpoint.path;
+                    endpointBtn.innerHTML = `
+                        <div class="flex items-center gap-2">
+                            <span class="method-${endpoint.method.toLowerCase()} text-xs px-1 py-0.5 rounded">${endpoint.method}</span>
+                            <span class="text-gray-900 dark:text-white truncate">${pathText}</span>
+                            ${isSelected ? '<span class="ml-auto text-xs text-accent font-medium">✓</span>' : ''}
+                        </div>
+                    `;
+                    if (!isSelected) {
+                        endpointBtn.onclick = () => addEndpointToScenario(endpoint);
+                    }
+                    categoryDiv.appendChild(endpointBtn);
+                    
+                    // Also create mobile version
+                    if (mobileContainer) {
+                        const mobileEndpointBtn = endpointBtn.cloneNode(true);
+                        if (!isSelected) {
+                            mobileEndpointBtn.onclick = () => addEndpointToScenario(endpoint);
+                        }
+                        
+                        // Create or get mobile category div
+                        let mobileCategoryDiv = mobileContainer.querySelector(`[data-category="${category}"]`);
+                        if (!mobileCategoryDiv) {
+                            mobileCategoryDiv = document.createElement('div');
+                            mobileCategoryDiv.className = 'mb-2';
+                            mobileCategoryDiv.setAttribute('data-category', category);
+                            mobileCategoryDiv.innerHTML = `<h4 class="text-xs font-medium text-gray-600 dark:text-gray-400 mb-1">${category.toUpperCase()}</h4>`;
+                            mobileContainer.appendChild(mobileCategoryDiv);
+                        }
+                        mobileCategoryDiv.appendChild(mobileEndpointBtn);
+                    }
+                });
+                container.appendChild(categoryDiv);
+            });
+
+            if (query && container.children.length === 0) {
+                container.innerHTML = '<p class="text-xs text-gray-500 dark:text-gray-400 text-center py-4">No endpoints found</p>';
+                if (mobileContainer) {
+                    mobileContainer.innerHTML = '<p class="text-xs text-gray-500 dark:text-gray-400 text-center py-4">No endpoints found</p>';
+                }
+            }
+        }
+
+        function highlightMatch(text, query) {
+            if (!query) return text;
+            const regex = new RegExp(`(${query})`, 'gi');
+            return text.replace(regex, '<mark class="bg-yellow-200 dark:bg-yellow-800">$1</mark>');
+        }
+
+        function addEndpointToScenario(endpoint) {
+            if (!currentScenario.requests) {
+                currentScenario.requests = [];
+            }
+
+            const exists = currentScenario.requests.some(req => 
+                req.method === endpoint.method && req.path === endpoint.path
+            );
+            if (!exists) {
+
+                let exampleBody = null;
+                if (['POST', 'PUT', 'PATCH'].includes(endpoint.method)) {
+                    if (endpoint.requestBody && endpoint.requestBody.example) {
+                        exampleBody = endpoint.requestBody.example;
+                    } else if (endpoint.requestBody && endpoint.requestBody.content && endpoint.requestBody.content['application/json'] && endpoint.requestBody.content['application/json'].example) {
+                        exampleBody = endpoint.requestBody.content['application/json'].example;
+                    }
+                }
+                currentScenario.requests.push({
+                    id: endpoint.id,
+                    method: endpoint.method,
+                    path: endpoint.path,
+                    title: endpoint.title,
+                    originalEndpoint: endpoint,
+                    config: {
+                        enabled: true,
+                        timeout: 30000,
+                        retries: 0,
+                        parameters: {},
+                        headers: {},
+                        body: exampleBody,
+                        useExampleBody: exampleBody ? true : false
+                    }
+                });
+                renderScenarioRequests(currentScenario.requests);
+
+                const searchQuery = document.getElementById('endpointSearch') ? document.getElementById('endpointSearch').value : '';
+                populateAvailableEndpoints(searchQuery);
+            } else {
+                showNotification('Endpoint already exists in scenario', 'error', 3000);
+            }
+        }
+
+        function closeScenarioModal() {
+            const modal = document.getElementById('scenarioModal');
+            modal.classList.add('hidden');
+            modal.classList.remove('flex');
+
+        }
+
+        function resetScenarioForm() {
+            if (confirm('Are you sure you want to reset the entire form? All unsaved changes will be lost.')) {
+                currentScenario = { name: '', description: '', requests: [], variables: {} };
+                isEditingScenario = false;
+
+                document.getElementById('scenarioName').value = '';
+                document.getElementById('scenarioDescription').value = '';
+                document.getElementById('endpointSearch').value = '';
+                setScenarioScriptInputs({});
+
+                renderScenarioRequests([]);
+
+                populateAvailableEndpoints();
+
+                document.getElementById('scenarioModalTitle').textContent = 'Create New Scenario';
+
+                updateLeftScenarioButton();
+                showNotification('Form has been reset', 'info');
+            }
+        }
+
+        function deleteCurrentScenario() {
+            if (!isEditingScenario || !currentScenario.id) {
+                showNotification('No scenario to delete', 'error');
+                return;
+            }
+            
+            if (confirm(`Are you sure you want to delete the scenario "${currentScenario.name}"? This action cannot be undone.`)) {
+
+                const index = scenarios.findIndex(s => s.id === currentScenario.id);
+                if (index !== -1) {
+                    scenarios.splice(index, 1);
+                    saveScenarios();
+                    renderScenariosGrid();
+                    closeScenarioModal();
+                    showNotification('Scenario deleted successfully', 'success');
+                } else {
+                    showNotification('Scenario not found', 'error');
+                }
+            }
+        }
+
+        function updateLeftScenarioButton() {
+            const buttons = document.querySelectorAll('.scenario-left-btn');
+            buttons.forEach(button => {
+                if (isEditingScenario) {
+                    button.textContent = 'Delete';
+                    button.onclick = deleteCurrentScenario;
+                } else {
+                    button.textContent = 'Reset Form';
+                    button.onclick = resetScenarioForm;
+                }
+            });
+        }
+
+        function renderScenarioRequests(requests) {
+            const container = document.getElementById('scenarioRequests');
+            const emptyMessage = document.getElementById('emptyScenarioMessage');
+            const mobileContainer = document.getElementById('mobileScenarioRequests');
+            const mobileEmptyMessage = document.getElementById('mobileEmptyScenarioMessage');
+
+            // Clear desktop container
+            const existingRequests = container.querySelectorAll('.scenario-request-item');
+            existingRequests.forEach(item => item.remove());
+            
+            // Clear mobile container
+            if (mobileContainer) {
+                const mobileExistingRequests = mobileContainer.querySelectorAll('.scenario-request-item');
+                mobileExistingRequests.forEach(item => item.remove());
+            }
+            
+            if (requests.length === 0) {
+                emptyMessage.style.display = 'block';
+                if (mobileEmptyMessage) mobileEmptyMessage.style.display = 'block';
+                return;
+            }
+            
+            emptyMessage.style.display = 'none';
+            if (mobileEmptyMessage) mobileEmptyMessage.style.display = 'none';
+
+            requests.forEach((request, index) => {
+                const item = createScenarioRequestItem(request, index);
+                container.appendChild(item);
+                
+                // For mobile container, we need to create items optimized for mobile
+                if (mobileContainer) {
+                    const mobileItem = createMobileScenarioRequestItem(request, index);
+                    mobileContainer.appendChild(mobileItem);
+                }
+            });
+        }
+
+        function createScenarioRequestItem(request, index) {
+            const item = document.createElement('div');
+            // Desktop version - original layout
+            item.className = 'scenario-request-item bg-gray-50 dark:bg-[#2c2d2d] border border-gray-200 dark:border-[#171717] rounded-lg p-3 flex items-center justify-between';
+            item.innerHTML = `
+                <div class="flex items-center gap-3">
+                    <div class="flex items-center gap-2">
+                        <span class="text-lg text-gray-400 select-none">${index + 1}.</span>
+                        <span class="method-${request.method.toLowerCase()} text-xs px-2 py-1 rounded">${request.method}</span>
+                    </div>
+                    <span class="text-sm text-gray-900 dark:text-white">${request.path}</span>
+                </div>
+                <div class="flex items-center gap-2">
+                    <button class="text-gray-400 hover:text-gray-600 transition-colors duration-200" title="Configure" onclick="configureRequest(${index})">
+                        <svg class="w-4 h-4" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+                            <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M10.325 4.317c.426-1.756 2.924-1.756 3.35 0a1.724 1.724 0 002.573 1.066c1.543-.94 3.31.826 2.37 2.37a1.724 1.724 0 001.065 2.572c1.756.426 1.756 2.924 0 3.35a1.724 1.724 0 00-1.066 2.573c.94 1.543-.826 3.31-2.37 2.37a1.724 1.724 0 00-2.572 1.065c-.426 1.756-2.924 1.756-3.35 0a1.724 1.724 0 00-2.573-1.066c-1.543.94-3.31-.826-2.37-2.37a1.724 1.724 0 00-1.065-2.572c-1.756-.426-1.756-2.924 0-3.35a1.724 1.724 0 001.066-2.573c-.94-1.543.826-3.31 2.37-2.37.996.608 2.296.07 2.572-1.065z"/>
+                            <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M15 12a3 3 0 11-6 0 3 3 0 016 0z"/>
+                        </svg>
+                    </button>
+                    <button class="text-gray-400 hover:text-red-500 transition-colors duration-200" title="Remove" onclick="removeRequestFromScenario(${index})">
+                        <svg class="w-4 h-4" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+                            <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M19 7l-.867 12.142A2 2 0 0116.138 21H7.862a2 2 0 01-1.995-1.858L5 7m5 4v6m4-6v6m1-10V4a1 1 0 00-1-1h-4a1 1 0 00-1 1v3M4 7h16"/>
+                        </svg>
+                    </button>
+                </div>
+            `;
+            return item;
+        }
+
+        function createMobileScenarioRequestItem(request, index) {
+            const item = document.createElement('div');
+            // Mobile version - more compact
+            item.className = 'scenario-request-item bg-gray-50 dark:bg-[#2c2d2d] border border-gray-200 dark:border-[#171717] rounded-lg p-3';
+            item.innerHTML = `
+                <div class="flex items-center justify-between mb-2">
+                    <div class="flex items-center gap-2 flex-1 min-w-0">
+                        <span class="text-sm text-gray-400 select-none flex-shrink-0">${index + 1}.</span>
+                        <span class="method-${request.method.toLowerCase()} text-xs px-1.5 py-0.5 rounded flex-shrink-0">${request.method}</span>
+                    </div>
+                    <div class="flex items-center gap-1 flex-shrink-0">
+                        <button class="text-gray-400 hover:text-gray-600 transition-colors duration-200 p-1" title="Configure" onclick="configureRequest(${index})">
+                            <svg class="w-4 h-4" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+                                <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M10.325 4.317c.426-1.756 2.924-1.756 3.35 0a1.724 1.724 0 002.573 1.066c1.543-.94 3.31.826 2.37 2.37a1.724 1.724 0 001.065 2.572c1.756.426 1.756 2.924 0 3.35a1.724 1.724 0 00-1.066 2.573c.94 1.543-.826 3.31-2.37 2.37a1.724 1.724 0 00-2.572 1.065c-.426 1.756-2.924 1.756-3.35 0a1.724 1.724 0 00-2.573-1.066c-1.543.94-3.31-.826-2.37-2.37a1.724 1.724 0 00-1.065-2.572c-1.756-.426-1.756-2.924 0-3.35a1.724 1.724 0 001.066-2.573c-.94-1.543.826-3.31 2.37-2.37.996.608 2.296.07 2.572-1.065z"/>
+                                <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M15 12a3 3 0 11-6 0 3 3 0 016 0z"/>
+                            </svg>
+                        </button>
+                        <button class="text-gray-400 hover:text-red-500 transition-colors duration-200 p-1" title="Remove" onclick="removeRequestFromScenario(${index})">
+                            <svg class="w-4 h-4" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+                                <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M19 7l-.867 12.142A2 2 0 0116.138 21H7.862a2 2 0 01-1.995-1.858L5 7m5 4v6m4-6v6m1-10V4a1 1 0 00-1-1h-4a1 1 0 00-1 1v3M4 7h16"/>
+                            </svg>
+                        </button>
+                    </div>
+                </div>
+                <div class="text-sm text-gray-900 dark:text-white truncate">${request.path}</div>
+            `;
+            return item;
+        }
+
+        function initScenarioManagement() {
+
+            loadScenarios();
+
+            document.getElementById('createScenarioBtn').addEventListener('click', () => {
+
+                resetToCleanCreateState();
+                openScenarioModal();
+            });
+            document.getElementById('addScenarioCard').addEventListener('click', () => {
+
+                resetToCleanCreateState();
+                openScenarioModal();
+            });
+            document.getElementById('closeScenarioModal').addEventListener('click', closeScenarioModal);
+            // Handle both mobile and desktop cancel buttons
+            document.querySelectorAll('.scenario-cancel-btn').forEach(btn => {
+                btn.addEventListener('click', closeScenarioModal);
+            });
+            
+            // Handle both mobile and desktop save buttons
+            document.querySelectorAll('.scenario-save-btn').forEach(btn => {
+                btn.addEventListener('click', saveCurrentScenario);
+            });
+
+            document.getElementById('scenarioAuthType').addEventListener('change', updateScenarioAuthInputs);
+
+            document.getElementById('closeConfigModal').addEventListener('click', closeEndpointConfigModal);
+            document.getElementById('cancelConfig').addEventListener('click', closeEndpointConfigModal);
+            document.getElementById('saveConfig').addEventListener('click', saveEndpointConfiguration);
+            document.getElementById('addHeader').addEventListener('click', () => addHeaderRow());
+            document.getElementById('formatBody').addEventListener('click', formatRequestBody);
+            document.getElementById('loadExampleBody').addEventListener('click', loadExampleRequestBody);
+
+            let searchTimeout;
+            document.addEventListener('input', (e) => {
+                if (e.target.id === 'endpointSearch' || e.target.id === 'mobileEndpointSearch') {
+                    clearTimeout(searchTimeout);
+                    searchTimeout = setTimeout(() => {
+                        populateAvailableEndpoints(e.target.value);
+                        // Also populate mobile endpoints if searching from mobile
+                        if (e.target.id === 'mobileEndpointSearch') {
+                            syncEndpointsToMobile();
+                        }
+                    }, 300);
+                }
+
+                if (e.target.id === 'scenarioName' || e.target.id === 'scenarioDescription' || e.target.id === 'mobileScenarioName' || e.target.id === 'mobileScenarioDescription') {
+                    if (currentScenario) {
+                        if (e.target.id === 'scenarioName' || e.target.id === 'mobileScenarioName') {
+                            currentScenario.name = e.target.value;
+                            // Sync between mobile and desktop
+                            if (e.target.id === 'mobileScenarioName') {
+                                document.getElementById('scenarioName').value = e.target.value;
+                            } else {
+                                document.getElementById('mobileScenarioName').value = e.target.value;
+                            }
+                        } else if (e.target.id === 'scenarioDescription' || e.target.id === 'mobileScenarioDescription') {
+                            currentScenario.description = e.target.value;
+                            // Sync between mobile and desktop
+                            if (e.target.id === 'mobileScenarioDescription') {
+                                document.getElementById('scenarioDescription').value = e.target.value;
+                            } else {
+                                document.getElementById('mobileScenarioDescription').value = e.target.value;
+                            }
+                        }
+                    }
+                }
+            });
+
+            const scenarioModal = document.getElementById('scenarioModal');
+            scenarioModal.addEventListener('click', (e) => {
+                if (e.target === scenarioModal) {
+                    closeScenarioModal();
+                }
+            });
+
+            const scenarioDetailsModal = document.getElementById('scenarioDetailsModal');
+            scenarioDetailsModal.addEventListener('click', (e) => {
+                if (e.target === scenarioDetailsModal) {
+                    closeScenarioDetails();
+                }
+            });
+
+            if (!localStorage.getItem('bytedocs-scenarios-info-shown')) {
+                setTimeout(() => {
+                    showNotification('Scenarios are saved to your browser\'s local storage', 'info', 5000);
+                    localStorage.setItem('bytedocs-scenarios-info-shown', 'true');
+                }, 2000);
+            }
+        }
+
+        function showNotification(message, type = 'info', duration = 3000) {
+            const notification = document.createElement('div');
+            const bgColor = type === 'error' ? 'bg-red-500' : type === 'success' ? 'bg-green-500' : 'bg-blue-500';
+            notification.className = `fixed top-4 right-4 ${bgColor} text-white px-4 py-2 rounded-lg shadow-lg z-50 max-w-sm`;
+            notification.innerHTML = `
+                <div class="flex items-center gap-2">
+                    <span class="text-sm">${message}</span>
+                    <button class="ml-2 text-white hover:text-gray-200" onclick="this.parentElement.parentElement.remove()">
+                        <svg class="w-4 h-4" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+                            <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M6 18L18 6M6 6l12 12"/>
+                        </svg>
+                    </button>
+                </div>
+            `;
+            document.body.appendChild(notification);
+            setTimeout(() => {
+                if (notification.parentNode) {
+                    notification.remove();
+                }
+            }, duration);
+        }
+
+        // setScenarioScriptInputs fills the script fields of the scenario modal
+        function setScenarioScriptInputs(scenario) {
+            ['scenario', 'mobileScenario'].forEach(prefix => {
+                document.getElementById(prefix + 'PreRequestScript').value = scenario.preRequestScript || '';
+                document.getElementById(prefix + 'PostResponseScript').value = scenario.postResponseScript || '';
+            });
+        }
+
+        function saveCurrentScenario() {
+            console.log('Save scenario button clicked')
+            
+            // Check if we're on mobile and sync data first
+            const isMobile = window.innerWidth < 1024;
+            if (isMobile) {
+                syncMobileToDesktop();
+            }
+            
+            const name = document.getElementById('scenarioName').value.trim();
+            const description = document.getElementById('scenarioDescription').value.trim();
+            const executionMode = document.querySelector('input[name="executionMode"]:checked').value;
+
+            saveScenarioAuthentication();
+            console.log('Scenario name:', name)
+            console.log('Current scenario:', currentScenario)
+            console.log('Execution mode:', executionMode)
+            if (!name) {
+                showNotification('Please enter a scenario name', 'error');
+                return;
+            }
+            if (!currentScenario.requests || currentScenario.requests.length === 0) {
+                showNotification('Please add at least one request to the scenario', 'error');
+                return;
+            }
+            const scenario = {
+                id: isEditingScenario ? currentScenario.id : Date.now(),
+                name: name,
+                description: description,
+                executionMode: executionMode,
+                preRequestScript: document.getElementById('scenarioPreRequestScript').value,
+                postResponseScript: document.getElementById('scenarioPostResponseScript').value,
+                requests: currentScenario.requests || [],
+                variables: currentScenario.variables || {},
+                authentication: currentScenario.authentication || { type: 'none' },
+                created: isEditingScenario ? currentScenario.created : new Date().toISOString(),
+                modified: new Date().toISOString()
+            };
+            console.log('Saving scenario:', scenario)
+            if (isEditingScenario) {
+                const index = scenarios.findIndex(s => s.id === currentScenario.id);
+                if (index !== -1) {
+                    scenarios[index] = scenario;
+                }
+            } else {
+                scenarios.push(scenario);
+            }
+            saveScenarios();
+            renderScenariosGrid();
+            closeScenarioModal();
+
+            const action = isEditingScenario ? 'updated' : 'created';
+            showNotification(`Scenario "${name}" ${action} successfully`, 'success', 3000);
+        }
+
+        function editScenario(index) {
+            openScenarioModal(scenarios[index]);
+        }
+
+        function deleteScenario(index) {
+            const scenario = scenarios[index];
+            if (confirm(`Are you sure you want to delete "${scenario.name}"?`)) {
+                scenarios.splice(index, 1);
+                saveScenarios();
+                renderScenariosGrid();
+                showNotification(`Scenario "${scenario.name}" deleted`, 'info', 3000);
+            }
+        }
+
+        function exportScenario(index) {
+            const scenario = scenarios[index];
+            if (!scenario) return;
+            const exportData = {
+                name: scenario.name,
+                description: scenario.description,
+                executionMode: scenario.executionMode || 'waterfall',
+                preRequestScript: scenario.preRequestScript || '',
+                postResponseScript: scenario.postResponseScript || '',
+                requests: scenario.requests || [],
+                variables: scenario.variables || {},
+                exported: new Date().toISOString(),
+                version: '1.0'
+            };
+            const jsonString = JSON.stringify(exportData, null, 2);
+            const blob = new Blob([jsonString], { type: 'application/json' });
+            const url = URL.createObjectURL(blob);
+            const link = document.createElement('a');
+            link.href = url;
+            link.download = `${scenario.name.replace(/[^a-z0-9]/gi, '_').toLowerCase()}_scenario.json`;
+            document.body.appendChild(link);
+            link.click();
+            document.body.removeChild(link);
+            URL.revokeObjectURL(url);
+            showNotification(`Scenario "${scenario.name}" exported successfully`, 'success');
+        }
+
+        function exportAllScenarios() {
+            if (scenarios.length === 0) {
+                showNotification('No scenarios to export', 'info');
+                return;
+            }
+            const exportData = {
+                scenarios: scenarios.map(scenario => ({
+                    name: scenario.name,
+                    description: scenario.description,
+                    executionMode: scenario.executionMode || 'waterfall',
+                    preRequestScript: scenario.preRequestScript || '',
+                    postResponseScript: scenario.postResponseScript || '',
+                    requests: scenario.requests || [],
+                    variables: scenario.variables || {}
+                })),
+                exported: new Date().toISOString(),
+                version: '1.0',
+                type: 'scenarios_collection'
+            };
+            const jsonString = JSON.stringify(exportData, null, 2);
+            const blob = new Blob([jsonString], { type: 'application/json' });
+            const url = URL.createObjectURL(blob);
+            const link = document.createElement('a');
+            link.href = url;
+            const timestamp = new Date().toISOString().split('T')[0];
+            link.download = `bytedocs_scenarios_${timestamp}.json`;
+            document.body.appendChild(link);
+            link.click();
+            document.body.removeChild(link);
+            URL.revokeObjectURL(url);
+            showNotification(`${scenarios.length} scenarios exported successfully`, 'success');
+        }
+
+        function openImportModal() {
+            const modal = document.getElementById('importModal');
+            modal.classList.remove('hidden');
+            modal.classList.add('flex');
+
+            setupImportDragAndDrop();
+        }
+
+        function closeImportModal() {
+            const modal = document.getElementById('importModal');
+            modal.classList.add('hidden');
+            modal.classList.remove('flex');
+
+            document.getElementById('importProgress').classList.add('hidden');
+            document.getElementById('importProgressList').innerHTML = '';
+            document.getElementById('importModalFileInput').value = '';
+        }
+
+        function setupImportDragAndDrop() {
+            const dropZone = document.getElementById('dropZone');
+            const fileInput = document.getElementById('importModalFileInput');
+
+            dropZone.addEventListener('click', () => {
+                fileInput.click();
+            });
+
+            fileInput.addEventListener('change', (e) => {
+                handleImportFiles(e.target.files);
+            });
+
+            dropZone.addEventListener('dragover', (e) => {
+                e.preventDefault();
+                dropZone.classList.add('border-accent', 'bg-accent', 'bg-opacity-5');
+            });
+            dropZone.addEventListener('dragleave', (e) => {
+                e.preventDefault();
+                dropZone.classList.remove('border-accent', 'bg-accent', 'bg-opacity-5');
+            });
+            dropZone.addEventListener('drop', (e) => {
+                e.preventDefault();
+                dropZone.classList.remove('border-accent', 'bg-accent', 'bg-opacity-5');
+                handleImportFiles(e.dataTransfer.files);
+            });
+        }
+
+        function handleImportFiles(files) {
+            if (files.length === 0) return;
+
+            const progressDiv = document.getElementById('importProgress');
+            const progressList = document.getElementById('importProgressList');
+            progressDiv.classList.remove('hidden');
+            progressList.innerHTML = '';
+            let importedCount = 0;
+            let totalFiles = files.length;
+            Array.from(files).forEach((file, index) => {
+
+                if (!file.name.endsWith('.json')) {
+                    addProgressItem(file.name, 'error', 'Invalid file type - only JSON files are allowed');
+                    return;
+                }
+
+                const reader = new FileReader();
+                reader.onload = function(e) {
+                    try {
+                        const importData = JSON.parse(e.target.result);
+
+                        if (importData.type === 'scenarios_collection' && importData.scenarios) {
+
+                            let collectionImported = 0;
+                            importData.scenarios.forEach(scenarioData => {
+                                if (processScenarioImport(scenarioData)) {
+                                    collectionImported++;
+                                }
+                            });
+                            addProgressItem(file.name, 'success', `Imported ${collectionImported} scenarios from collection`);
+                            importedCount += collectionImported;
+                        } else {
+
+                            if (processScenarioImport(importData)) {
+                                addProgressItem(file.name, 'success', 'Scenario imported successfully');
+                                importedCount++;
+                            } else {
+                                addProgressItem(file.name, 'error', 'Invalid scenario format');
+                            }
+                        }
+                    } catch (error) {
+                        addProgressItem(file.name, 'error', 'Invalid JSON format');
+                    }
+
+                    if (index === totalFiles - 1) {
+                        setTimeout(() => {
+                            if (importedCount > 0) {
+
+                                localStorage.setItem('bytedocs-scenarios', JSON.stringify(scenarios));
+
+                                renderScenariosGrid();
+                                showNotification(`${importedCount} scenario(s) imported successfully`, 'success');
+
+                                setTimeout(closeImportModal, 2000);
+                            }
+                        }, 500);
+                    }
+                };
+                reader.readAsText(file);
+            });
+        }
+
+        function processScenarioImport(importData) {
+
+            if (!importData.name || !importData.requests || !Array.isArray(importData.requests)) {
+                return false;
+            }
+
+            const existingIndex = scenarios.findIndex(s => s.name === importData.name);
+
+            const newScenario = {
+                name: importData.name,
+                description: importData.description || '',
+                executionMode: importData.executionMode || 'waterfall',
+                preRequestScript: importData.preRequestScript || '',
+                postResponseScript: importData.postResponseScript || '',
+                requests: importData.requests.map(req => ({
+                    ...req,
+                    config: req.config || {
+                        enabled: true,
+                        timeout: 30000,
+                        retries: 0,
+                        parameters: {},
+                        headers: {},
+                        body: null,
+                        useExampleBody: false
+                    }
+                })),
+                variables: importData.variables || {}
+            };
+
+            if (existingIndex !== -1) {
+                scenarios[existingIndex] = newScenario;
+            } else {
+                scenarios.push(newScenario);
+            }
+            return true;
+        }
+
+        function addProgressItem(fileName, status, message) {
+            const progressList = document.getElementById('importProgressList');
+            const item = document.createElement('div');
+            item.className = 'flex items-center gap-2';
+            const statusIcon = status === 'success' 
+                ? '<svg class="w-4 h-4 text-green-500" fill="currentColor" viewBox="0 0 20 20"><path fill-rule="evenodd" d="M16.707 5.293a1 1 0 010 1.414l-8 8a1 1 0 01-1.414 0l-4-4a1 1 0 011.414-1.414L8 12.586l7.293-7.293a1 1 0 011.414 0z" clip-rule="evenodd"/></svg>'
+                : '<svg class="w-4 h-4 text-red-500" fill="currentColor" viewBox="0 0 20 20"><path fill-rule="evenodd" d="M4.293 4.293a1 1 0 011.414 0L10 8.586l4.293-4.293a1 1 0 111.414 1.414L11.414 10l4.293 4.293a1 1 0 01-1.414 1.414L10 11.414l-4.293 4.293a1 1 0 01-1.414-1.414L8.586 10 4.293 5.707a1 1 0 010-1.414z" clip-rule="evenodd"/></svg>';
+            item.innerHTML = `
+                ${statusIcon}
+                <span class="font-medium text-gray-900 dark:text-white">${fileName}</span>
+                <span class="text-gray-500 dark:text-gray-400">- ${message}</span>
+            `;
+            progressList.appendChild(item);
+        }
+
+        let currentDetailScenarioIndex = null;
+        function showScenarioDetails(index) {
+            const scenario = scenarios[index];
+            if (!scenario) return;
+            currentDetailScenarioIndex = index;
+            const modal = document.getElementById('scenarioDetailsModal');
+
+            const visuals = getScenarioVisuals(scenario.name);
+            const executionMode = scenario.executionMode || 'waterfall';
+            const isParallel = executionMode === 'parallel';
+
+            document.getElementById('detailsScenarioName').textContent = scenario.name;
+            document.getElementById('detailsDescription').textContent = scenario.description || 'No description provided';
+
+            const iconDiv = document.getElementById('detailsIcon');
+            iconDiv.className = `w-10 h-10 bg-gradient-to-br ${visuals.gradient} rounded-lg flex items-center justify-center`;
+            iconDiv.innerHTML = visuals.icon;
+
+            const modeSpan = document.getElementById('detailsExecutionMode');
+            const modeConfig = isParallel ? {
+                badgeColor: 'bg-orange-500',
+                badgeText: 'PARALLEL'
+            } : {
+                badgeColor: 'bg-blue-500', 
+                badgeText: 'WATERFALL'
+            };
+            modeSpan.className = `text-xs px-2 py-0.5 ${modeConfig.badgeColor} text-white rounded-full font-medium`;
+            modeSpan.textContent = modeConfig.badgeText;
+
+            const requestCount = scenario.requests.length;
+            document.getElementById('detailsRequestCount').textContent = `${requestCount} request${requestCount !== 1 ? 's' : ''}`;
+            document.getElementById('detailsExecMode').textContent = isParallel ? 'Parallel' : 'Waterfall';
+            document.getElementById('detailsReqCount').textContent = requestCount.toString();
+
+            document.getElementById('detailsAuthType').textContent = describeAuth(scenario.authentication || { type: 'none' });
+
+            const requestsList = document.getElementById('detailsRequestsList');
+            requestsList.innerHTML = '';
+            if (scenario.requests.length === 0) {
+                requestsList.innerHTML = `
+                    <div class="text-center py-8 text-gray-500 dark:text-gray-400">
+                        <svg class="w-12 h-12 mx-auto mb-2" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+                            <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M20 13V6a2 2 0 00-2-2H6a2 2 0 00-2 2v7m16 0v5a2 2 0 01-2 2H6a2 2 0 01-2-2v-5m16 0h-2M4 13h2m13-8V4a1 1 0 00-1-1h-4a1 1 0 00-1 1v1M7 8h10l-5 5-5-5z"/>
+                        </svg>
+                        <p>No requests in this scenario</p>
+                    </div>
+                `;
+            } else {
+                scenario.requests.forEach((request, reqIndex) => {
+                    const requestDiv = document.createElement('div');
+                    requestDiv.className = 'bg-gray-50 dark:bg-[#2c2d2d] rounded-lg p-4 border border-gray-200 dark:border-[#3c3d3d]';
+                    const methodColor = getMethodColor(request.method);
+                    const configCount = Object.keys(request.config.parameters || {}).length + Object.keys(request.config.headers || {}).length;
+                    const hasConfig = configCount > 0 || request.config.body;
+
+                    let configDetails = '';
+
+                    const timeout = request.config.timeout || 30000;
+                    const retries = request.config.retries || 0;
+                    const enabled = request.config.enabled !== false;
+                    
+                    configDetails += `
+                        <div class="mt-3 pt-3 border-t border-gray-200 dark:border-[#3c3d3d]">
+                            <h5 class="text-xs font-medium text-gray-700 dark:text-gray-300 mb-2">Configuration</h5>
+                            <div class="grid grid-cols-2 gap-2 text-xs">
+                                <div><span class="text-gray-500 dark:text-gray-400">Status:</span> <span class="${enabled ? 'text-green-600 dark:text-green-400' : 'text-red-600 dark:text-red-400'}">${enabled ? 'Enabled' : 'Disabled'}</span></div>
+                                <div><span class="text-gray-500 dark:text-gray-400">Timeout:</span> <span class="text-gray-700 dark:text-gray-300">${timeout}ms</span></div>
+                                <div><span class="text-gray-500 dark:text-gray-400">Retries:</span> <span class="text-gray-700 dark:text-gray-300">${retries}</span></div>
+                            </div>
+                        </div>
+                    `;
+
+                    if (Object.keys(request.config.parameters || {}).length > 0) {
+                        configDetails += `
+                            <div class="mt-3 pt-3 border-t border-gray-200 dark:border-[#3c3d3d]">
+                                <h5 class="text-xs font-medium text-gray-700 dark:text-gray-300 mb-2">Parameters (${Object.keys(request.config.parameters).length})</h5>
+                                <div class="space-y-1">
+                                    ${Object.entries(request.config.parameters).map(([name, value]) => `
+                                        <div class="flex justify-between text-xs">
+                                            <span class="text-gray-600 dark:text-gray-400 font-mono">${name}:</span>
+                                            <span class="text-gray-700 dark:text-gray-300 break-all ml-2">${value}</span>
+                                        </div>
+                                    `).join('')}
+                                </div>
+                            </div>
+                        `;
+                    }
+
+                    if (Object.keys(request.config.headers || {}).length > 0) {
+                        configDetails += `
+                            <div class="mt-3 pt-3 border-t border-gray-200 dark:border-[#3c3d3d]">
+                                <h5 class="text-xs font-medium text-gray-700 dark:text-gray-300 mb-2">Custom Headers (${Object.keys(request.config.headers).length})</h5>
+                                <div class="space-y-1">
+                                    ${Object.entries(request.config.headers).map(([name, value]) => `
+                                        <div class="flex justify-between text-xs">
+                                            <span class="text-gray-600 dark:text-gray-400 font-mono">${name}:</span>
+                                            <span class="text-gray-700 dark:text-gray-300 break-all ml-2">${value}</span>
+                                        </div>
+                                    `).join('')}
+                                </div>
+                            </div>
+                        `;
+                    }
+
+                    if (request.config.body) {
+                        const bodyPreview = JSON.stringify(request.config.body, null, 2);
+                        const isLongBody = bodyPreview.length > 200;
+                        const displayBody = isLongBody ? bodyPreview.substring(0, 200) + '...' : bodyPreview;
+                        
+                        configDetails += `
+                            <div class="mt-3 pt-3 border-t border-gray-200 dark:border-[#3c3d3d]">
+                                <h5 class="text-xs font-medium text-gray-700 dark:text-gray-300 mb-2">Request Body</h5>
+                                <div class="bg-gray-100 dark:bg-[#1c1c1c] rounded p-2 text-xs">
+                                    <pre class="text-gray-700 dark:text-gray-300 font-mono whitespace-pre-wrap">${displayBody}</pre>
+                                    ${isLongBody ? '<div class="text-gray-500 dark:text-gray-400 mt-1">... (truncated)</div>' : ''}
+                                </div>
+                            </div>
+                        `;
+                    }
+                    
+                    requestDiv.innerHTML = `
+                        <div class="flex items-center justify-between cursor-pointer hover:bg-gray-100 dark:hover:bg-[#3c3d3d] rounded p-2 -m-2 transition-colors duration-200" onclick="toggleRequestDetails(${reqIndex})">
+                            <div class="flex items-center gap-3 min-w-0 flex-1">
+                                <span class="w-2 h-2 ${methodColor} rounded-full flex-shrink-0"></span>
+                                <span class="font-mono text-sm font-medium method-${request.method.toLowerCase()} flex-shrink-0">${request.method}</span>
+                                <span class="text-sm text-gray-600 dark:text-gray-400 truncate" title="${request.path}">${request.path}</span>
+                            </div>
+                            <div class="flex items-center gap-2 flex-shrink-0">
+                                ${hasConfig ? '<span class="text-xs bg-blue-100 dark:bg-blue-900 text-blue-800 dark:text-blue-200 px-2 py-1 rounded-full">Configured</span>' : ''}
+                                <span class="text-xs text-gray-500 dark:text-gray-400">#${reqIndex + 1}</span>
+                                <svg class="w-4 h-4 text-gray-400 transform transition-transform duration-200 collapse-icon" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+                                    <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M19 9l-7 7-7-7"/>
+                                </svg>
+                            </div>
+                        </div>
+                        <div class="request-details hidden">
+                            ${configDetails}
+                        </div>
+                    `;
+                    requestsList.appendChild(requestDiv);
+                });
+            }
+
+            modal.classList.remove('hidden');
+            modal.classList.add('flex');
+        }
+        function closeScenarioDetails() {
+            const modal = document.getElementById('scenarioDetailsModal');
+            modal.classList.add('hidden');
+            modal.classList.remove('flex');
+            currentDetailScenarioIndex = null;
+        }
+        
+        function toggleRequestDetails(requestIndex) {
+            const requestDivs = document.querySelectorAll('#detailsRequestsList .request-details');
+            const collapseIcons = document.querySelectorAll('#detailsRequestsList .collapse-icon');
+            
+            if (requestDivs[requestIndex] && collapseIcons[requestIndex]) {
+                const details = requestDivs[requestIndex];
+                const icon = collapseIcons[requestIndex];
+                
+                if (details.classList.contains('hidden')) {
+
+                    details.classList.remove('hidden');
+                    icon.style.transform = 'rotate(180deg)';
+                } else {
+
+                    details.classList.add('hidden');
+                    icon.style.transform = 'rotate(0deg)';
+                }
+            }
+        }
+        function editScenarioFromDetails() {
+            if (currentDetailScenarioIndex !== null) {
+                const scenarioIndex = currentDetailScenarioIndex
+                const scenario = scenarios[scenarioIndex];
+                
+                if (scenario) {
+                    closeScenarioDetails();
+
+                    setTimeout(() => {
+                        editScenario(scenarioIndex);
+                    }, 100);
+                } else {
+                    showNotification('Scenario not found', 'error');
+                }
+            } else {
+                showNotification('No scenario selected', 'error');
+            }
+        }
+        function exportScenarioFromDetails() {
+            if (currentDetailScenarioIndex !== null) {
+                exportScenario(currentDetailScenarioIndex);
+            }
+        }
+        function runScenarioFromDetails() {
+            if (currentDetailScenarioIndex !== null) {
+                closeScenarioDetails();
+                runScenario(currentDetailScenarioIndex);
+            }
+        }
+
+        function updateScenarioAuthInputs() {
+            const type = document.getElementById('scenarioAuthType').value;
+            const container = document.getElementById('scenarioAuthInputs');
+            let html = '';
+            const authData = currentScenario.authentication || {};
+            switch (type) {
+                case 'bearer':
+                    html = '<input type="text" class="w-full px-2 py-1 text-xs border border-gray-300 dark:border-[#171717] rounded bg-white dark:bg-black text-gray-900 dark:text-white focus:outline-none focus:ring-1 focus:ring-accent" id="scenarioAuthToken" placeholder="Bearer token" value="' + (authData.token || '') + '">';
+                    break;
+                case 'basic':
+                    html = `
+                        <input type="text" class="w-full px-2 py-1 text-xs border border-gray-300 dark:border-[#171717] rounded bg-white dark:bg-black text-gray-900 dark:text-white focus:outline-none focus:ring-1 focus:ring-accent mb-1" id="scenarioAuthUsername" placeholder="Username" value="${authData.username || ''}">
+                        <input type="password" class="w-full px-2 py-1 text-xs border border-gray-300 dark:border-[#171717] rounded bg-white dark:bg-black text-gray-900 dark:text-white focus:outline-none focus:ring-1 focus:ring-accent" id="scenarioAuthPassword" placeholder="Password" value="${authData.password || ''}">
+                    `;
+                    break;
+                case 'apikey':
+                    html = `
+                        <input type="text" class="w-full px-2 py-1 text-xs border border-gray-300 dark:border-[#171717] rounded bg-white dark:bg-black text-gray-900 dark:text-white focus:outline-none focus:ring-1 focus:ring-accent mb-1" id="scenarioAuthKeyName" placeholder="Header name (e.g., X-API-Key)" value="${authData.keyName || 'X-API-Key'}">
+                        <input type="text" class="w-full px-2 py-1 text-xs border border-gray-300 dark:border-[#171717] rounded bg-white dark:bg-black text-gray-900 dark:text-white focus:outline-none focus:ring-1 focus:ring-accent" id="scenarioAuthApiKey" placeholder="API key value" value="${authData.apiKey || ''}">
+                    `;
+                    break;
+            }
+            container.innerHTML = html;
+        }
+        function saveScenarioAuthentication() {
+            const authType = document.getElementById('scenarioAuthType').value;
+            if (!currentScenario.authentication) {
+                currentScenario.authentication = {};
+            }
+            currentScenario.authentication.type = authType;
+            switch (authType) {
+                case 'bearer':
+                    currentScenario.authentication.token = document.getElementById('scenarioAuthToken')?.value || '';
+                    break;
+                case 'basic':
+                    currentScenario.authentication.username = document.getElementById('scenarioAuthUsername')?.value || '';
+                    currentScenario.authentication.password = document.getElementById('scenarioAuthPassword')?.value || '';
+                    break;
+                case 'apikey':
+                    currentScenario.authentication.keyName = document.getElementById('scenarioAuthKeyName')?.value || 'X-API-Key';
+                    currentScenario.authentication.apiKey = document.getElementById('scenarioAuthApiKey')?.value || '';
+                    break;
+                case 'none':
+                default:
+                    currentScenario.authentication = { type: 'none' };
+                    break;
+            }
+        }
+        function loadScenarioAuthentication() {
+            const authData = currentScenario.authentication || { type: 'none' };
+            document.getElementById('scenarioAuthType').value = authData.type;
+            updateScenarioAuthInputs();
+        }
+        function getScenarioAuthHeaders(scenarioAuth = null) {
+            const auth = scenarioAuth || (currentScenario && currentScenario.authentication) || { type: 'none' };
+            const headers = {};
+            switch (auth.type) {
+                case 'bearer':
+                    if (auth.token) {
+                        headers['Authorization'] = `Bearer ${auth.token}`;
+                    }
+                    break;
+                case 'basic':
+                    if (auth.username && auth.password) {
+                        const credentials = btoa(`${auth.username}:${auth.password}`);
+                        headers['Authorization'] = `Basic ${credentials}`;
+                    }
+                    break;
+                case 'apikey':
+                    if (auth.keyName && auth.apiKey) {
+                        headers[auth.keyName] = auth.apiKey;
+                    }
+                    break;
+            }
+            return headers;
+        }
+
+        let filteredScenarios = [];
+        function searchScenarios(query) {
+            if (!query.trim()) {
+
+                filteredScenarios = [...scenarios];
+            } else {
+
+                const searchTerm = query.toLowerCase();
+                filteredScenarios = scenarios.filter(scenario => 
+                    scenario.name.toLowerCase().includes(searchTerm) || 
+                    (scenario.description && scenario.description.toLowerCase().includes(searchTerm))
+                );
+            }
+
+            renderFilteredScenariosGrid();
+        }
+        function renderFilteredScenariosGrid() {
+            const grid = document.getElementById('scenariosGrid');
+            const addCard = document.getElementById('addScenarioCard');
+
+            const existingCards = grid.querySelectorAll('.scenario-card');
+            existingCards.forEach(card => card.remove());
+
+            filteredScenarios.forEach((scenario) => {
+                const originalIndex = scenarios.findIndex(s => s === scenario);
+                const card = createScenarioCard(scenario, originalIndex);
+                grid.insertBefore(card, addCard);
+            });
+
+            if (filteredScenarios.length === 0 && document.getElementById('scenarioSearchInput').value.trim()) {
+                showNoResultsMessage();
+            } else {
+                hideNoResultsMessage();
+            }
+        }
+        function showNoResultsMessage() {
+            const grid = document.getElementById('scenariosGrid');
+            const addCard = document.getElementById('addScenarioCard');
+
+            const existingMessage = document.getElementById('noResultsMessage');
+            if (existingMessage) existingMessage.remove();
+
+            const noResultsDiv = document.createElement('div');
+            noResultsDiv.id = 'noResultsMessage';
+            noResultsDiv.className = 'col-span-full text-center py-12';
+            noResultsDiv.innerHTML = `
+                <div class="text-gray-400 dark:text-gray-500">
+                    <svg class="w-12 h-12 mx-auto mb-4" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+                        <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M21 21l-6-6m2-5a7 7 0 11-14 0 7 7 0 0114 0z"/>
+                    </svg>
+                    <h3 class="text-lg font-medium mb-2">No scenarios found</h3>
+                    <p class="text-sm">Try adjusting your search terms or create a new scenario</p>
+                </div>
+            `;
+            if (addCard) {
+                addCard.classList.add('hidden');
+                grid.insertBefore(noResultsDiv, addCard);
+            } else {
+                grid.appendChild(noResultsDiv);
+            }
+        }
+        function hideNoResultsMessage() {
+            const existingMessage = document.getElementById('noResultsMessage');
+            if (existingMessage) existingMessage.remove();
+            const addCard = document.getElementById('addScenarioCard');
+            if (addCard) {
+                addCard.classList.remove('hidden');
+            }
+        }
+
+        function removeRequestFromScenario(index) {
+            if (currentScenario && currentScenario.requests) {
+                currentScenario.requests.splice(index, 1);
+                renderScenarioRequests(currentScenario.requests);
+
+                const searchQuery = document.getElementById('endpointSearch') ? document.getElementById('endpointSearch').value : '';
+                populateAvailableEndpoints(searchQuery);
+            }
+        }
+
+        function configureRequest(index) {
+            const request = currentScenario.requests[index];
+            if (!request) return;
+
+            window.currentConfigIndex = index;
+            window.currentConfigRequest = request;
+
+            openEndpointConfigModal(request);
+        }
+
+        function openEndpointConfigModal(request) {
+            const modal = document.getElementById('endpointConfigModal');
+            const title = document.getElementById('configModalTitle');
+            const subtitle = document.getElementById('configModalSubtitle');
+            title.textContent = `Configure ${request.method} ${request.path}`;
+            subtitle.textContent = `Customize request parameters, headers, and body for this endpoint`;
+
+            document.getElementById('configEnabled').checked = request.config.enabled;
+            document.getElementById('configTimeout').value = request.config.timeout || 30000;
+            document.getElementById('configRetries').value = request.config.retries || 0;
+
+            populateConfigParameters(request);
+
+            populateConfigHeaders(request.config.headers || {});
+            document.getElementById('configPreRequestScript').value = request.config.preRequestScript || '';
+            document.getElementById('configPostResponseScript').value = request.config.postResponseScript || '';
+
+            const bodySection = document.getElementById('configBodySection');
+            if (['POST', 'PUT', 'PATCH'].includes(request.method)) {
+                bodySection.classList.remove('hidden');
+                document.getElementById('useExampleBody').checked = request.config.useExampleBody;
+
+                if (!configBodyEditor && window.monaco) {
+                    initConfigBodyEditor();
+                }
+
+                if (configBodyEditor) {
+                    const bodyContent = request.config.body ? JSON.stringify(request.config.body, null, 2) : '{\n  \n}';
+                    configBodyEditor.setValue(bodyContent);
+                } else {
+
+                    setTimeout(() => {
+                        if (!configBodyEditor) initConfigBodyEditor();
+                        if (configBodyEditor) {
+                            const bodyContent = request.config.body ? JSON.stringify(request.config.body, null, 2) : '{\n  \n}';
+                            configBodyEditor.setValue(bodyContent);
+                        }
+                    }, 100);
+                }
+            } else {
+                bodySection.classList.add('hidden');
+            }
+            modal.classList.remove('hidden');
+            modal.classList.add('flex');
+        }
+
+        function closeEndpointConfigModal() {
+            const modal = document.getElementById('endpointConfigModal');
+            modal.classList.add('hidden');
+            modal.classList.remove('flex');
+        }
+
+        function populateConfigParameters(request) {
+            const container = document.getElementById('configParameters');
+            container.innerHTML = '';
+
+            const endpoint = request.originalEndpoint;
+            if (!endpoint || !endpoint.parameters) return;
+            endpoint.parameters.forEach((param, index) => {
+                const paramDiv = document.createElement('div');
+                paramDiv.className = 'space-y-2 p-3 border border-gray-200 dark:border-[#2c2d2d] rounded-md';
+                const currentValue = request.config.parameters[param.name] || param.default || '';
+                const isRequired = param.required ? 'Required' : 'Optional';
+                paramDiv.innerHTML = `
+                    <div class="flex items-center justify-between">
+                        <label class="block text-sm font-medium text-gray-700 dark:text-gray-300">${param.name}</label>
+                        <span class="text-xs text-gray-500 dark:text-gray-400">${isRequired}</span>
+                    </div>
+                    <input type="text" 
+                           value="${currentValue}" 
+                           placeholder="${param.description || param.name}"
+                           data-param-name="${param.name}"
+                           class="w-full px-2 py-1 text-sm border border-gray-300 dark:border-[#2c2d2d] rounded bg-white dark:bg-black text-gray-900 dark:text-white focus:outline-none focus:ring-1 focus:ring-accent">
+                    <p class="text-xs text-gray-500 dark:text-gray-400">${param.description || 'No description'}</p>
+                `;
+                container.appendChild(paramDiv);
+            });
+            if (endpoint.parameters.length === 0) {
+                container.innerHTML = '<p class="text-sm text-gray-500 dark:text-gray-400">No parameters available for this endpoint</p>';
+            }
+        }
+
+        function populateConfigHeaders(headers) {
+            const container = document.getElementById('configHeaders');
+
+            const existingHeaders = container.querySelectorAll('.header-row');
+            existingHeaders.forEach(header => header.remove());
+
+            Object.entries(headers).forEach(([name, value]) => {
+                addHeaderRow(name, value);
+            });
+        }
+
+        function addHeaderRow(name = '', value = '') {
+            const container = document.getElementById('configHeaders');
+            const headerDiv = document.createElement('div');
+            headerDiv.className = 'flex gap-2 header-row';
+            headerDiv.innerHTML = `
+                <input type="text" placeholder="Header name" value="${name}" 
+                       class="flex-1 px-2 py-1 text-sm border border-gray-300 dark:border-[#2c2d2d] rounded bg-white dark:bg-black text-gray-900 dark:text-white focus:outline-none focus:ring-1 focus:ring-accent">
+                <input type="text" placeholder="Header value" value="${value}"
+                       class="flex-1 px-2 py-1 text-sm border border-gray-300 dark:border-[#2c2d2d] rounded bg-white dark:bg-black text-gray-900 dark:text-white focus:outline-none focus:ring-1 focus:ring-accent">
+                <button class="text-red-500 hover:text-red-700 px-2" onclick="this.parentElement.remove()">×</button>
+            `;
+
+            container.insertBefore(headerDiv, container.lastElementChild);
+        }
+
+        function saveEndpointConfiguration() {
+            const index = window.currentConfigIndex;
+            if (index === undefined || !currentScenario.requests[index]) return;
+            const request = currentScenario.requests[index];
+
+            request.config.enabled = document.getElementById('configEnabled').checked;
+            request.config.timeout = parseInt(document.getElementById('configTimeout').value) || 30000;
+            request.config.retries = parseInt(document.getElementById('configRetries').value) || 0;
+
+            const paramInputs = document.querySelectorAll('#configParameters input[data-param-name]');
+            request.config.parameters = {};
+            paramInputs.forEach(input => {
+                const paramName = input.getAttribute('data-param-name');
+                if (input.value.trim()) {
+                    request.config.parameters[paramName] = input.value.trim();
+                }
+            });
+
+            const headerRows = document.querySelectorAll('#configHeaders .header-row');
+            request.config.headers = {};
+            headerRows.forEach(row => {
+                const nameInput = row.querySelector('input:first-child');
+                const valueInput = row.querySelector('input:nth-child(2)');
+                if (nameInput && valueInput && nameInput.value.trim() && valueInput.value.trim()) {
+                    request.config.headers[nameInput.value.trim()] = valueInput.value.trim();
+                }
+            });
+            request.config.preRequestScript = document.getElementById('configPreRequestScript').value;
+            request.config.postResponseScript = document.getElementById('configPostResponseScript').value;
+
+            if (['POST', 'PUT', 'PATCH'].includes(request.method)) {
+                request.config.useExampleBody = document.getElementById('useExampleBody').checked;
+
+                let bodyText = '';
+                if (configBodyEditor) {
+                    bodyText = configBodyEditor.getValue().trim();
+                }
+                if (bodyText) {
+                    try {
+                        request.config.body = JSON.parse(bodyText);
+                    } catch (e) {
+                        showNotification('Invalid JSON in request body', 'error');
+                        return;
+                    }
+                } else {
+                    request.config.body = null;
+                }
+            }
+
+            renderScenarioRequests(currentScenario.requests);
+
+            saveScenarios();
+            
+            closeEndpointConfigModal();
+            showNotification('Configuration saved successfully', 'success');
+        }
+
+        function formatRequestBody() {
+            if (!configBodyEditor) return;
+            const bodyText = configBodyEditor.getValue().trim();
+            if (!bodyText) return;
+            try {
+                const parsed = JSON.parse(bodyText);
+                configBodyEditor.setValue(JSON.stringify(parsed, null, 2));
+                showNotification('JSON formatted successfully', 'success');
+            } catch (e) {
+                showNotification('Invalid JSON format', 'error');
+            }
+        }
+
+        function loadExampleRequestBody() {
+            const request = window.currentConfigRequest;
+            if (!request || !request.originalEndpoint) return;
+            const endpoint = request.originalEndpoint;
+            let exampleBody = null;
+            if (endpoint.requestBody && endpoint.requestBody.example) {
+                exampleBody = endpoint.requestBody.example;
+            } else if (endpoint.requestBody && endpoint.requestBody.content && endpoint.requestBody.content['application/json'] && endpoint.requestBody.content['application/json'].example) {
+                exampleBody = endpoint.requestBody.content['application/json'].example;
+            }
+            if (exampleBody) {
+                if (configBodyEditor) {
+                    configBodyEditor.setValue(JSON.stringify(exampleBody, null, 2));
+                }
+                document.getElementById('useExampleBody').checked = true;
+                showNotification('Example body loaded successfully', 'success');
+            } else {
+                showNotification('No example body available for this endpoint', 'info');
+            }
+        }
+
+        async function runScenario(index) {
+            const scenario = scenarios[index];
+            if (!scenario || !scenario.requests || scenario.requests.length === 0) {
+                showNotification('No requests found in this scenario', 'error');
+                return;
+            }
+
+            const enabledRequests = scenario.requests.filter(req => req.config.enabled !== false);
+            if (enabledRequests.length === 0) {
+                showNotification('No enabled requests found in this scenario', 'error');
+                return;
+            }
+            const executionMode = scenario.executionMode || 'waterfall';
+            const scripting = {
+                preRequestScript: scenario.preRequestScript || '',
+                postResponseScript: scenario.postResponseScript || '',
+                variables: { ...(scenario.variables || {}) }
+            };
+            showNotification(`Starting scenario: ${scenario.name} (${executionMode} mode)`, 'info');
+            await runCollection(`Running Scenario: ${scenario.name}`, scenario.name, enabledRequests, executionMode, scenario.authentication, scripting);
+            showNotification(`Scenario "${scenario.name}" completed`, 'success');
+        }
+
+        // runCollection executes a batch of requests in a modal with a progress bar,
+        // and offers the results as a JUnit XML or JSON report once they finished;
+        // scripting holds the scenario scripts and the variables they share
+        async function runCollection(title, name, enabledRequests, executionMode, authentication, scripting = null) {
+            const modal = document.createElement('div');
+            modal.className = 'fixed inset-0 bg-black bg-opacity-50 flex items-center justify-center z-50 p-2 sm:p-4';
+            modal.innerHTML = `
+                <div class="bg-white dark:bg-[#171717] rounded-lg shadow-xl max-w-6xl w-full max-h-full sm:max-h-[90vh] overflow-hidden flex flex-col">
+                    <div class="p-3 sm:p-6 border-b border-gray-200 dark:border-[#2c2d2d] flex flex-col sm:flex-row sm:items-center justify-between gap-3">
+                        <div class="min-w-0 flex-1">
+                            <h2 class="text-lg sm:text-xl font-semibold text-gray-900 dark:text-white truncate">${escapeHtml(title)}</h2>
+                            <p class="text-xs sm:text-sm text-gray-500 dark:text-gray-400 mt-1">${enabledRequests.length} requests • ${executionMode} execution</p>
+                        </div>
+                        <button class="text-gray-400 hover:text-gray-600 dark:hover:text-gray-300 transition-colors duration-200 flex-shrink-0" onclick="this.closest('.fixed').remove()">
+                            <svg class="w-5 h-5 sm:w-6 sm:h-6" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+                                <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M6 18L18 6M6 6l12 12"/>
+                            </svg>
+                        </button>
+                    </div>
+                    <div class="flex-shrink-0 px-3 sm:px-6 pt-3 sm:pt-4">
+                        <div class="h-2 bg-gray-200 dark:bg-[#2c2d2d] rounded-full overflow-hidden">
+                            <div class="run-progress-bar h-full bg-accent transition-all duration-200" style="width: 0%"></div>
+                        </div>
+                        <p class="run-progress-text text-xs text-gray-500 dark:text-gray-400 mt-2">0 / ${enabledRequests.length} completed</p>
+                    </div>
+                    <div class="flex-1 p-3 sm:p-6 overflow-y-auto min-h-0">
+                        <div id="scenarioResults" class="space-y-4">
+                            
+                        </div>
+                    </div>
+                    <div class="flex-shrink-0 p-3 sm:p-6 border-t border-gray-200 dark:border-[#2c2d2d] flex flex-col sm:flex-row justify-end gap-2">
+                        <button class="run-report-btn px-4 py-2 bg-gray-100 hover:bg-gray-200 dark:bg-[#2c2d2d] dark:hover:bg-[#3c3d3d] text-gray-700 dark:text-gray-300 rounded-md w-full sm:w-auto disabled:opacity-50" data-format="junit" disabled>Download JUnit XML</button>
+                        <button class="run-report-btn px-4 py-2 bg-gray-100 hover:bg-gray-200 dark:bg-[#2c2d2d] dark:hover:bg-[#3c3d3d] text-gray-700 dark:text-gray-300 rounded-md w-full sm:w-auto disabled:opacity-50" data-format="json" disabled>Download JSON</button>
+                        <button class="px-4 py-2 bg-gray-500 hover:bg-gray-600 text-white rounded-md w-full sm:w-auto" onclick="this.closest('.fixed').remove()">Close</button>
+                    </div>
+                </div>
+            `;
+            document.body.appendChild(modal);
+
+            modal.addEventListener('click', (e) => {
+                if (e.target === modal) {
+                    modal.remove();
+                }
+            });
+            const resultsContainer = modal.querySelector('#scenarioResults');
+
+            const run = { name, executionMode, startedAt: new Date().toISOString(), results: [] };
+            const onResult = (result) => {
+                run.results.push(result);
+                const done = run.results.length;
+                const failed = run.results.filter(r => !r.passed).length;
+                modal.querySelector('.run-progress-bar').style.width = `${Math.round(done / enabledRequests.length * 100)}%`;
+                modal.querySelector('.run-progress-text').textContent = `${done} / ${enabledRequests.length} completed · ${done - failed} passed · ${failed} failed`;
+            };
+
+            if (executionMode === 'parallel') {
+                await executeRequestsInParallel(enabledRequests, resultsContainer, authentication, onResult, scripting);
+            } else {
+                await executeRequestsSequentially(enabledRequests, resultsContainer, authentication, onResult, scripting);
+            }
+            run.finishedAt = new Date().toISOString();
+
+            modal.querySelectorAll('.run-report-btn').forEach(button => {
+                button.disabled = false;
+                button.addEventListener('click', () => downloadRunReport(run, button.dataset.format));
+            });
+            return run;
+        }
+
+        async function executeRequestsInParallel(requests, resultsContainer, scenarioAuth = null, onResult = () => {}, scripting = null) {
+
+            const resultItems = [];
+            requests.forEach((request, i) => {
+                const resultItem = createResultItem(request, i);
+                resultsContainer.appendChild(resultItem);
+                resultItems.push(resultItem);
+            });
+
+            const promises = requests.map((request, i) => executeRequest(request, i, resultItems[i], scenarioAuth, scripting).then(onResult));
+            await Promise.allSettled(promises);
+        }
+
+        async function executeRequestsSequentially(requests, resultsContainer, scenarioAuth = null, onResult = () => {}, scripting = null) {
+            for (let i = 0; i < requests.length; i++) {
+                const request = requests[i];
+
+                const resultItem = createResultItem(request, i);
+                resultsContainer.appendChild(resultItem);
+
+                onResult(await executeRequest(request, i, resultItem, scenarioAuth, scripting));
+
+                if (i < requests.length - 1) {
+                    await new Promise(resolve => setTimeout(resolve, 1000));
+                }
+            }
+        }
+
+        // runReportJSON describes a finished run with a summary and one entry per request
+        function runReportJSON(run) {
+            const failed = run.results.filter(result => !result.passed).length;
+            return JSON.stringify({
+                name: run.name,
+                executionMode: run.executionMode,
+                startedAt: run.startedAt,
+                finishedAt: run.finishedAt,
+                summary: { total: run.results.length, passed: run.results.length - failed, failed: failed },
+                results: run.results
+            }, null, 2);
+        }
+
+        // runReportJUnit renders a finished run as a JUnit XML test suite, so CI
+        // dashboards can show it; network and script errors are errors, failed
+        // assertions and, without assertions, non-2xx responses failures
+        function runReportJUnit(run) {
+            const xml = value => String(value ?? '').replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;').replace(/"/g, '&quot;');
+            const failures = run.results.filter(result => !result.passed && !result.error).length;
+            const errors = run.results.filter(result => result.error).length;
+            const seconds = ms => (ms / 1000).toFixed(3);
+            const total = run.results.reduce((sum, result) => sum + (result.duration || 0), 0);
+
+            const cases = run.results.map(result => {
+                let body = '';
+                if (result.error) {
+                    body = `\n      <error message="${xml(result.error)}" type="RequestError"/>\n    `;
+                } else if (!result.passed && result.tests && result.tests.length > 0) {
+                    const failed = result.tests.filter(test => !test.passed);
+                    body = `\n      <failure message="${xml(`${failed.length} of ${result.tests.length} assertions failed`)}" type="Assertion">${xml(failed.map(test => test.message || test.name).join('\n'))}</failure>\n    `;
+                } else if (!result.passed) {
+                    body = `\n      <failure message="${xml(`HTTP ${result.status}`)}" type="HTTPStatus">${xml(result.response)}</failure>\n    `;
+                }
+                const output = `\n      <system-out>${xml(`${result.method} ${result.url}`)}</system-out>\n    `;
+                return `    <testcase classname="${xml(run.name)}" name="${xml(`${result.method} ${result.path}`)}" time="${seconds(result.duration || 0)}">${body || output}</testcase>`;
+            }).join('\n');
+
+            return `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="${xml(run.name)}" tests="${run.results.length}" failures="${failures}" errors="${errors}" time="${seconds(total)}">
+  <testsuite name="${xml(run.name)}" tests="${run.results.length}" failures="${failures}" errors="${errors}" time="${seconds(total)}" timestamp="${xml(run.startedAt)}">
+${cases}
+  </testsuite>
+</testsuites>
+`;
+        }
+
+        function downloadRunReport(run, format) {
+            const junit = format === 'junit';
+            const blob = new Blob([junit ? runReportJUnit(run) : runReportJSON(run)], { type: junit ? 'application/xml' : 'application/json' });
+            const url = URL.createObjectURL(blob);
+            const link = document.createElement('a');
+            link.href = url;
+            const slug = run.name.toLowerCase().replace(/[^a-z0-9]+/g, '_').replace(/^_|_$/g, '') || 'run';
+            link.download = `bytedocs_${slug}_${run.startedAt.split('T')[0]}.${junit ? 'xml' : 'json'}`;
+            document.body.appendChild(link);
+            link.click();
+            document.body.removeChild(link);
+            URL.revokeObjectURL(url);
+        }
+
+        function createResultItem(request, index) {
+            const resultItem = document.createElement('div');
+            resultItem.className = 'border border-gray-200 dark:border-[#2c2d2d] rounded-lg p-3 sm:p-4';
+            resultItem.innerHTML = `
+                <div class="flex items-center gap-3 mb-3">
+                    <span class="text-lg text-gray-400 flex-shrink-0">${index + 1}.</span>
+                    <span class="method-${request.method.toLowerCase()} text-xs px-2 py-1 rounded flex-shrink-0">${request.method}</span>
+                    <span class="text-sm text-gray-900 dark:text-white truncate min-w-0 flex-1" title="${request.path}">${request.path}</span>
+                    <div class="flex-shrink-0 status-container">
+                        <div class="w-4 h-4 border-2 border-accent border-t-transparent rounded-full animate-spin"></div>
+                    </div>
+                </div>
+                <div class="result-content hidden">
+                    
+                </div>
+            `;
+            return resultItem;
+        }
+
+        // runRequestScripts runs the scenario's and the request's script of a
+        // stage on the docs server, which uses the engine of the Go scenario
+        // runner; the variables they set are added to scripting.variables
+        async function runRequestScripts(scripting, stage, requestScript, payload) {
+            const scenarioScript = stage === 'pre-request' ? scripting.preRequestScript : scripting.postResponseScript;
+            if (!scenarioScript.trim() && !(requestScript || '').trim()) return null;
+            const response = await fetch(`${window.location.origin}${config.docsPath || '/docs'}/scripts/run`, {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json', 'Accept': 'application/json' },
+                body: JSON.stringify({
+                    stage,
+                    scenario_script: scenarioScript,
+                    request_script: requestScript || '',
+                    variables: scripting.variables,
+                    ...payload
+                })
+            });
+            if (!response.ok) throw new Error(`${stage} script: ${(await response.text()).trim() || `HTTP ${response.status}`}`);
+            const outcome = await response.json();
+            Object.assign(scripting.variables, outcome.assigned);
+            return outcome;
+        }
+
+        // replaceScenarioVariables replaces {{name}} placeholders with scenario variables
+        function replaceScenarioVariables(text, variables) {
+            return Object.entries(variables).reduce((result, [name, value]) => result.split(`{{${name}}}`).join(value), text);
+        }
+
+        async function executeRequest(request, index, resultItem, scenarioAuth = null, scripting = null) {
+            const result = { name: request.title || `${request.method} ${request.path}`, method: request.method, path: request.path, url: request.path };
+            try {
+
+                const baseUrlElement = document.getElementById('baseUrlSelect') || document.querySelector('select[name="base_url"]');
+                const baseUrlValue = baseUrlElement ? baseUrlElement.value : currentOrigin;
+                let fullUrl = request.path;
+                if (!request.path.startsWith('http')) {
+                    fullUrl = baseUrlValue.replace(/\/$/, '') + (request.path.startsWith('/') ? request.path : '/' + request.path);
+                }
+
+                if (request.config.parameters) {
+                    Object.entries(request.config.parameters).forEach(([name, value]) => {
+                        fullUrl = fullUrl.replace(`{${name}}`, encodeURIComponent(value));
+                    });
+                }
+                result.url = fullUrl;
+
+                const options = {
+                    method: request.method,
+                    headers: {
+                        'Content-Type': 'application/json',
+                        'Accept': 'application/json'
+                    }
+                };
+
+                const authToUse = scenarioAuth || window.auth;
+                if (authToUse && authToUse.type !== 'none') {
+                    const authHeaders = getScenarioAuthHeaders(authToUse);
+                    Object.assign(options.headers, authHeaders);
+                }
+
+                if (request.config.headers) {
+                    Object.assign(options.headers, request.config.headers);
+                }
+
+                if (['POST', 'PUT', 'PATCH'].includes(request.method) && request.config.body) {
+                    options.body = typeof request.config.body === 'string' ? request.config.body : JSON.stringify(request.config.body);
+                }
+
+                // Pre-request scripts may set variables and headers; an error
+                // stops the request from being sent
+                let tests = [];
+                if (scripting) {
+                    const pre = await runRequestScripts(scripting, 'pre-request', request.config.preRequestScript, {
+                        request: { method: request.method, url: fullUrl, body: options.body || '' },
+                        headers: options.headers
+                    });
+                    if (pre) {
+                        if (pre.error) throw new Error(pre.error);
+                        options.headers = pre.headers || {};
+                        tests = pre.tests;
+                    }
+                    fullUrl = replaceScenarioVariables(fullUrl, scripting.variables);
+                    if (options.body) options.body = replaceScenarioVariables(options.body, scripting.variables);
+                    result.url = fullUrl;
+                }
+
+                const controller = new AbortController();
+                const timeoutId = setTimeout(() => controller.abort(), request.config.timeout || 30000);
+                options.signal = controller.signal;
+                options.credentials = requestCredentials();
+                const startTime = Date.now();
+                const response = await fetch(fullUrl, options);
+                clearTimeout(timeoutId);
+                const endTime = Date.now();
+                const responseTime = endTime - startTime;
+                let responseData;
+                try {
+                    const contentType = response.headers.get('content-type');
+                    if (contentType && contentType.includes('application/json')) {
+                        responseData = await response.json();
+                    } else {
+                        responseData = await response.text();
+                    }
+                } catch (parseError) {
+                    responseData = await response.text();
+                }
+
+                const budget = slaMs(Object.values(transformedApiData).flat().find(ep => ep.id === request.id));
+                const responseText = typeof responseData === 'string' ? responseData : JSON.stringify(responseData, null, 2);
+
+                // Post-response scripts extract variables and assert; their
+                // assertions decide whether the request passed
+                let scriptError = '';
+                if (scripting) {
+                    const responseHeaders = {};
+                    response.headers.forEach((value, name) => { responseHeaders[name] = [value]; });
+                    const post = await runRequestScripts(scripting, 'post-response', request.config.postResponseScript, {
+                        response: { status_code: response.status, headers: responseHeaders, body: responseText, duration_ms: responseTime }
+                    }).catch(error => ({ tests: [], error: error.message }));
+                    if (post) {
+                        tests = tests.concat(post.tests);
+                        scriptError = post.error || '';
+                    }
+                }
+                const failedTests = tests.filter(test => !test.passed).length;
+                const passed = !scriptError && (tests.length > 0 ? failedTests === 0 : response.ok);
+                if (tests.length > 0) result.tests = tests;
+                if (scriptError) result.error = scriptError;
+
+                const statusClass = passed ? 'text-green-600' : 'text-red-600';
+                const statusText = passed ? 'Success' : 'Error';
+                const testsHtml = tests.length === 0 && !scriptError ? '' : `
+                    <div class="mt-2 space-y-1">
+                        ${tests.map(test => `<div class="text-xs ${test.passed ? 'text-green-600' : 'text-red-600'}">${test.passed ? '✓' : '✗'} ${escapeHtml(test.name)}</div>`).join('')}
+                        ${scriptError ? `<div class="text-xs text-red-600">${escapeHtml(scriptError)}</div>` : ''}
+                    </div>
+                `;
+                Object.assign(result, {
+                    status: response.status,
+                    passed: passed,
+                    duration: responseTime,
+                    budgetMs: budget || undefined,
+                    withinBudget: budget ? responseTime <= budget : undefined,
+                    response: responseText.length > 2000 ? responseText.slice(0, 2000) + '…' : responseText
+                });
+                const timeText = budget ? `${responseTime}ms / ${formatMs(budget)}` : `${responseTime}ms`;
+                const statusContainer = resultItem.querySelector('.status-container');
+                statusContainer.innerHTML = `
+                    <div class="text-right">
+                        <div class="${statusClass} text-sm font-medium">${response.status} ${statusText}</div>
+                        <div class="text-xs ${latencyClass(responseTime, budget)}">${timeText}</div>
+                    </div>
+                `;
+                const resultContent = resultItem.querySelector('.result-content');
+                resultContent.innerHTML = `
+                    <div class="mt-3 p-3 bg-gray-50 dark:bg-[#2c2d2d] rounded">
+                        <h4 class="text-sm font-medium text-gray-700 dark:text-gray-300 mb-2">Response (${response.status})</h4>
+                        <div class="text-xs text-gray-500 dark:text-gray-400 mb-2 break-all" title="${fullUrl}">${fullUrl}</div>
+                        <pre class="text-xs text-gray-600 dark:text-gray-400 whitespace-pre-wrap max-h-32 overflow-y-auto overflow-x-auto">${responseText}</pre>
+                        ${testsHtml}
+                    </div>
+                `;
+                resultContent.classList.remove('hidden');
+            } catch (error) {
+                console.error('Request failed:', error);
+                Object.assign(result, { passed: false, error: error.message });
+
+                const statusContainer = resultItem.querySelector('.status-container');
+                statusContainer.innerHTML = `
+                    <div class="text-right">
+                        <div class="text-red-600 text-sm font-medium">Failed</div>
+                    </div>
+                `;
+                const resultContent = resultItem.querySelector('.result-content');
+                resultContent.innerHTML = `
+                    <div class="mt-3 p-3 bg-red-50 dark:bg-red-900/20 rounded">
+                        <h4 class="text-sm font-medium text-red-700 dark:text-red-300 mb-2">Error</h4>
+                        <pre class="text-xs text-red-600 dark:text-red-400 whitespace-pre-wrap break-all">${error.message}</pre>
+                    </div>
+                `;
+                resultContent.classList.remove('hidden');
+            }
+            return result;
+        }
+
+        // collectionRunnerSelection holds the ids of the endpoints picked in the runner modal
+        const collectionRunnerSelection = new Set();
+
+        function openCollectionRunner() {
+            const modal = document.getElementById('collectionRunnerModal');
+            document.getElementById('collectionRunnerSearch').value = '';
+            renderCollectionRunnerEndpoints('');
+            modal.classList.remove('hidden');
+            modal.classList.add('flex');
+        }
+
+        function closeCollectionRunner() {
+            const modal = document.getElementById('collectionRunnerModal');
+            modal.classList.add('hidden');
+            modal.classList.remove('flex');
+        }
+
+        function collectionRunnerMatches(endpoint, query) {
+            const target = `${endpoint.method} ${endpoint.path} ${endpoint.title || ''}`.toLowerCase();
+            return target.includes(query.toLowerCase().trim());
+        }
+
+        function renderCollectionRunnerEndpoints(query) {
+            const container = document.getElementById('collectionRunnerEndpoints');
+            container.innerHTML = Object.entries(transformedApiData).map(([section, endpoints]) => {
+                const visible = endpoints.filter(endpoint => collectionRunnerMatches(endpoint, query));
+                if (visible.length === 0) return '';
+                return `
+                    <div class="mb-4">
+                        <h3 class="text-sm font-semibold text-gray-900 dark:text-white mb-2">${escapeHtml(section)}</h3>
+                        <div class="space-y-1">
+                            ${visible.map(endpoint => `
+                                <label class="flex items-center gap-3 p-2 rounded hover:bg-gray-50 dark:hover:bg-[#2c2d2d] cursor-pointer">
+                                    <input type="checkbox" value="${escapeHtml(endpoint.id)}" ${collectionRunnerSelection.has(endpoint.id) ? 'checked' : ''}
+                                        onchange="toggleCollectionEndpoint(this.value, this.checked)">
+                                    <span class="method-${endpoint.method.toLowerCase()} text-xs px-2 py-1 rounded">${endpoint.method}</span>
+                                    <span class="text-sm text-gray-900 dark:text-white truncate">${escapeHtml(endpoint.path)}</span>
+                                    <span class="text-xs text-gray-500 dark:text-gray-400 truncate">${escapeHtml(endpoint.title || '')}</span>
+                                </label>
+                            `).join('')}
+                        </div>
+                    </div>
+                `;
+            }).join('') || '<p class="text-sm text-gray-500 dark:text-gray-400">No endpoints match the filter.</p>';
+            updateCollectionRunnerCount();
+        }
+
+        function toggleCollectionEndpoint(id, selected) {
+            if (selected) {
+                collectionRunnerSelection.add(id);
+            } else {
+                collectionRunnerSelection.delete(id);
+            }
+            updateCollectionRunnerCount();
+        }
+
+        // selectAllCollectionEndpoints toggles every endpoint shown by the current filter
+        function selectAllCollectionEndpoints(selected) {
+            const query = document.getElementById('collectionRunnerSearch').value;
+            Object.values(transformedApiData).flat().forEach(endpoint => {
+                if (collectionRunnerMatches(endpoint, query)) toggleCollectionEndpoint(endpoint.id, selected);
+            });
+            renderCollectionRunnerEndpoints(query);
+        }
+
+        function updateCollectionRunnerCount() {
+            document.getElementById('collectionRunnerCount').textContent = `${collectionRunnerSelection.size} selected`;
+        }
+
+        // runSelectedEndpoints runs the picked endpoints with their documented
+        // path parameter and request body examples
+        async function runSelectedEndpoints() {
+            const endpoints = Object.values(transformedApiData).flat().filter(endpoint => collectionRunnerSelection.has(endpoint.id));
+            if (endpoints.length === 0) {
+                showNotification('Select at least one endpoint to run', 'error');
+                return;
+            }
+            const requests = endpoints.map(endpoint => {
+                const parameters = {};
+                (endpoint.parameters || []).forEach(param => {
+                    if (param.in === 'path' && param.example !== undefined && param.example !== null) {
+                        parameters[param.name] = String(param.example);
+                    }
+                });
+                const body = ['POST', 'PUT', 'PATCH'].includes(endpoint.method.toUpperCase()) ? (endpoint.requestBody?.example ?? null) : null;
+                return {
+                    id: endpoint.id,
+                    method: endpoint.method.toUpperCase(),
+                    path: endpoint.path,
+                    title: endpoint.title,
+                    config: { enabled: true, timeout: 30000, retries: 0, parameters, headers: {}, body }
+                };
+            });
+            const executionMode = document.querySelector('input[name="collectionExecutionMode"]:checked').value;
+            closeCollectionRunner();
+            const run = await runCollection(`Running ${requests.length} endpoints`, 'Endpoint run', requests, executionMode, null);
+            const failed = run.results.filter(result => !result.passed).length;
+            showNotification(`Endpoint run completed: ${run.results.length - failed} passed, ${failed} failed`, failed ? 'error' : 'success');
+        }
+
+        // Usage analytics send counters only; browsers asking not to be tracked send nothing
+        const analyticsEnabled = Boolean(config && config.analytics) && navigator.doNotTrack !== '1' && !navigator.globalPrivacyControl;
+        const analyticsQueue = [];
+        let analyticsTimer = null;
+        let searchAnalyticsTimer = null;
+
+        function analyticsUrl(suffix = '') {
+            return `${window.location.origin}${config.docsPath || '/docs'}/analytics${suffix}`;
+        }
+
+        // trackUsage queues a "view", "try" or "search" event; events are sent in batches
+        function trackUsage(event) {
+            if (!analyticsEnabled) return;
+            analyticsQueue.push(event);
+            clearTimeout(analyticsTimer);
+            if (analyticsQueue.length >= 50) {
+                flushUsage();
+            } else {
+                analyticsTimer = setTimeout(flushUsage, 5000);
+            }
+        }
+
+        function trackSearch(query, results) {
+            clearTimeout(searchAnalyticsTimer);
+            if (!analyticsEnabled || query.length < 2) return;
+            // Only the query the user stopped typing at is counted
+            searchAnalyticsTimer = setTimeout(() => trackUsage({ type: 'search', query, results }), 1500);
+        }
+
+        function flushUsage() {
+            clearTimeout(analyticsTimer);
+            if (analyticsQueue.length === 0) return;
+            const body = JSON.stringify({ events: analyticsQueue.splice(0) });
+            if (navigator.sendBeacon && navigator.sendBeacon(analyticsUrl('/events'), new Blob([body], { type: 'application/json' }))) return;
+            fetch(analyticsUrl('/events'), {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body,
+                keepalive: true
+            }).catch(() => {});
+        }
+
+        async function openAnalytics() {
+            const modal = document.getElementById('analyticsModal');
+            const content = document.getElementById('analyticsContent');
+            content.innerHTML = '<p class="text-sm text-gray-500 dark:text-gray-400">Loading...</p>';
+            modal.classList.remove('hidden');
+            modal.classList.add('flex');
+            flushUsage();
+
+            try {
+                const response = await fetch(analyticsUrl(), { headers: { 'Accept': 'application/json' } });
+                if (!response.ok) throw new Error(`HTTP ${response.status}`);
+                renderAnalytics(await response.json());
+            } catch (error) {
+                content.innerHTML = `<p class="text-sm text-red-600">Failed to load analytics: ${escapeHtml(error.message)}</p>`;
+            }
+        }
+
+        function closeAnalytics() {
+            const modal = document.getElementById('analyticsModal');
+            modal.classList.add('hidden');
+            modal.classList.remove('flex');
+        }
+
+        function renderAnalytics(report) {
+            const endpointRow = usage => `
+                <tr class="border-t border-gray-100 dark:border-[#2c2d2d]">
+                    <td class="py-1 pr-3"><span class="inline-block px-2 rounded text-xs font-semibold method-${escapeHtml(usage.method.toLowerCase())}">${escapeHtml(usage.method)}</span></td>
+                    <td class="py-1 pr-3 font-mono text-xs break-all">${escapeHtml(usage.path)}</td>
+                    <td class="py-1 pr-3 text-right">${usage.views}</td>
+                    <td class="py-1 text-right">${usage.tries}</td>
+                </tr>`;
+            const searchRow = (usage, count) => `
+                <tr class="border-t border-gray-100 dark:border-[#2c2d2d]">
+                    <td class="py-1 pr-3 font-mono text-xs break-all">${escapeHtml(usage.query)}</td>
+                    <td class="py-1 text-right">${count}</td>
+                </tr>`;
+            const table = (headers, rows, empty) => rows.length === 0
+                ? `<p class="text-sm text-gray-500 dark:text-gray-400">${empty}</p>`
+                : `<table class="w-full text-sm text-gray-700 dark:text-gray-300">
+                    <thead><tr class="text-xs text-gray-500 dark:text-gray-400">${headers.map(header => `<th class="pb-1 ${['Views', 'Tries', 'Searches'].includes(header) ? 'text-right' : 'text-left'}">${header}</th>`).join('')}</tr></thead>
+                    <tbody>${rows.join('')}</tbody>
+                </table>`;
+            const section = (title, body) => `
+                <div>
+                    <h3 class="text-sm font-semibold text-gray-900 dark:text-white mb-2">${title}</h3>
+                    ${body}
+                </div>`;
+
+            const used = report.endpoints.filter(usage => usage.views + usage.tries > 0);
+            const unused = report.endpoints.filter(usage => usage.views + usage.tries === 0);
+            document.getElementById('analyticsSince').textContent = `Counted since ${new Date(report.since).toLocaleString()}`;
+            document.getElementById('analyticsContent').innerHTML = [
+                section('Most used endpoints', table(['Method', 'Path', 'Views', 'Tries'], used.slice(0, 20).map(endpointRow), 'No endpoint was viewed or tried yet.')),
+                section('Searches without results', table(['Query', 'Searches'], report.noResults.slice(0, 20).map(usage => searchRow(usage, usage.noResults)), 'Every search found an endpoint.')),
+                section('Top searches', table(['Query', 'Searches'], report.searches.slice(0, 20).map(usage => searchRow(usage, usage.count)), 'Nothing was searched yet.')),
+                section(`Never viewed or tried (${unused.length})`, table(['Method', 'Path', 'Views', 'Tries'], unused.map(endpointRow), 'Every endpoint was used.'))
+            ].join('');
+        }
+
+        function openDiagnostics() {
+            const modal = document.getElementById('diagnosticsModal');
+            const warnings = apiData.warnings || [];
+            document.getElementById('diagnosticsContent').innerHTML = `
+                <ul class="space-y-3">
+                    ${warnings.map((warning, index) => `
+                        <li class="border border-gray-200 dark:border-[#2c2d2d] rounded-md p-3">
+                            <button class="flex items-center gap-2 text-left hover:underline" onclick="showDiagnosticEndpoint(${index})">
+                                <span class="inline-block px-2 rounded text-xs font-semibold method-${escapeHtml(warning.method.toLowerCase())}">${escapeHtml(warning.method)}</span>
+                                <span class="font-mono text-xs break-all text-gray-900 dark:text-white">${escapeHtml(warning.path)}</span>
+                            </button>
+                            <p class="text-sm text-gray-600 dark:text-gray-300 mt-2">${escapeHtml(warning.message)}</p>
+                        </li>`).join('')}
+                </ul>`;
+            modal.classList.remove('hidden');
+            modal.classList.add('flex');
+        }
+
+        function closeDiagnostics() {
+            const modal = document.getElementById('diagnosticsModal');
+            modal.classList.add('hidden');
+            modal.classList.remove('flex');
+        }
+
+        function showDiagnosticEndpoint(index) {
+            const warning = (apiData.warnings || [])[index];
+            if (!warning) return;
+            const endpoint = Object.values(transformedApiData).flat()
+                .find(candidate => candidate.method.toUpperCase() === warning.method.toUpperCase() && candidate.path === warning.path);
+            if (!endpoint) return;
+            closeDiagnostics();
+            selectEndpoint(endpoint);
+        }
+
+        function initDragAndDrop() {
+            let draggedEndpoint = null;
+
+            function addDragToEndpoints() {
+                const endpointItems = document.querySelectorAll('.endpoint-item');
+                endpointItems.forEach(item => {
+                    item.draggable = true;
+                    item.addEventListener('dragstart', (e) => {
+                        if (currentMode !== 'scenario') return;
+                        const endpointId = item.getAttribute('data-endpoint-id');
+                        const endpoint = findEndpointById(endpointId);
+                        if (endpoint) {
+                            draggedEndpoint = {
+                                id: endpointId,
+                                method: endpoint.method,
+                                path: endpoint.path,
+                                title: endpoint.title,
+                                parameters: endpoint.parameters || [],
+                                requestBody: endpoint.requestBody || null
+                            };
+                            e.dataTransfer.effectAllowed = 'copy';
+                            item.classList.add('opacity-50');
+                        }
+                    });
+                    item.addEventListener('dragend', (e) => {
+                        item.classList.remove('opacity-50');
+                        draggedEndpoint = null;
+                    });
+                });
+            }
+
+            function findEndpointById(endpointId) {
+                for (const sectionName in transformedApiData) {
+                    const endpoint = transformedApiData[sectionName].find(ep => ep.id === endpointId);
+                    if (endpoint) return endpoint;
+                }
+                return null;
+            }
+
+            const scenarioRequests = document.getElementById('scenarioRequests');
+            scenarioRequests.addEventListener('dragover', (e) => {
+                if (draggedEndpoint) {
+                    e.preventDefault();
+                    e.dataTransfer.dropEffect = 'copy';
+                    scenarioRequests.classList.add('border-accent', 'bg-accent-light', 'bg-opacity-10');
+                }
+            });
+            scenarioRequests.addEventListener('dragleave', (e) => {
+
+                if (!scenarioRequests.contains(e.relatedTarget)) {
+                    scenarioRequests.classList.remove('border-accent', 'bg-accent-light', 'bg-opacity-10');
+                }
+            });
+            scenarioRequests.addEventListener('drop', (e) => {
+                e.preventDefault();
+                scenarioRequests.classList.remove('border-accent', 'bg-accent-light', 'bg-opacity-10');
+                if (draggedEndpoint && currentScenario) {
+
+                    const exists = currentScenario.requests.some(req => 
+                        req.method === draggedEndpoint.method && req.path === draggedEndpoint.path
+                    );
+                    if (!exists) {
+                        if (!currentScenario.requests) {
+                            currentScenario.requests = [];
+                        }
+                        currentScenario.requests.push({
+                            id: draggedEndpoint.id,
+                            method: draggedEndpoint.method,
+                            path: draggedEndpoint.path,
+                            title: draggedEndpoint.title,
+                            parameters: draggedEndpoint.parameters,
+                            requestBody: draggedEndpoint.requestBody,
+                            config: {
+                                enabled: true,
+                                timeout: 30000,
+                                variables: {}
+                            }
+                        });
+                        renderScenarioRequests(currentScenario.requests);
+                    } else {
+
+                        showNotification('Endpoint already exists in scenario', 'error', 3000);
+                    }
+                }
+                draggedEndpoint = null;
+            });
+
+            const originalRenderEndpoints = renderEndpoints;
+            window.renderEndpoints = function(endpointsToRender = null) {
+                originalRenderEndpoints(endpointsToRender);
+                setTimeout(addDragToEndpoints, 100)
+            };
+
+            setTimeout(addDragToEndpoints, 1000)
+        }
+
+        document.addEventListener('DOMContentLoaded', function() {
+            init();
+            initMonacoEditor();
+            initModeToggle();
+            initScenarioManagement();
+            openLinkedGuide();
+
+        });
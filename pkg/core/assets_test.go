@@ -0,0 +1,70 @@
+package core
+
+import (
+	"io"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestUIAssets_HashedCompressedAndImmutable(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs"})
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs", nil))
+	match := regexp.MustCompile(`src="(/docs/assets/docs\.[0-9a-f]+\.js)"`).FindStringSubmatch(rec.Body.String())
+	if match == nil {
+		t.Fatalf("expected the page to reference a content-hashed script")
+	}
+	scriptURL := match[1]
+
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", scriptURL, nil))
+	if rec.Code != 200 || rec.Header().Get("Cache-Control") != immutableCacheControl || rec.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("expected immutable uncompressed script, got %d %v", rec.Code, rec.Header())
+	}
+	plain := rec.Body.String()
+	etag := rec.Header().Get("ETag")
+
+	req := httptest.NewRequest("GET", scriptURL, nil)
+	req.Header.Set("Accept-Encoding", "gzip, br")
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Encoding") != "br" {
+		t.Fatalf("expected brotli encoding, got %v", rec.Header())
+	}
+	decoded, _ := io.ReadAll(brotli.NewReader(rec.Body))
+	if string(decoded) != plain {
+		t.Fatalf("brotli body differs from plain body")
+	}
+
+	req = httptest.NewRequest("GET", scriptURL, nil)
+	req.Header.Set("Accept-Encoding", "br;q=0, gzip")
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip encoding when brotli is refused, got %v", rec.Header())
+	}
+
+	req = httptest.NewRequest("GET", scriptURL, nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, req)
+	if rec.Code != 304 {
+		t.Fatalf("expected 304 for matching ETag, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/assets/docs.css", nil))
+	if rec.Code != 200 || rec.Header().Get("Cache-Control") != "no-cache" {
+		t.Fatalf("expected revalidated logical asset, got %d %v", rec.Code, rec.Header())
+	}
+
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/assets/missing.js", nil))
+	if rec.Code != 404 {
+		t.Fatalf("expected 404 for unknown asset, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,182 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// asyncAPIVersion is the AsyncAPI version of /asyncapi.json
+const asyncAPIVersion = "2.6.0"
+
+// Channel documents an event channel, e.g. a Kafka topic or NATS subject.
+// Following AsyncAPI 2.x, Subscribe describes the messages your application
+// sends (clients subscribe to them) and Publish those it receives.
+type Channel struct {
+	Description string
+	Parameters  map[string]Parameter   // placeholders of the channel name, e.g. "userId" in "users.{userId}.events"
+	Subscribe   *ChannelOperation      // messages the application publishes
+	Publish     *ChannelOperation      // messages the application consumes
+	Bindings    map[string]interface{} // protocol bindings, e.g. {"kafka": {"partitions": 3}}
+}
+
+// ChannelOperation describes the messages of one direction of a channel
+type ChannelOperation struct {
+	OperationID string
+	Summary     string
+	Description string
+	MessageName string      // defaults to the channel name
+	ContentType string      // defaults to "application/json"
+	Message     interface{} // example value of the payload; its schema is derived like WithRequest
+}
+
+// EventServer is a message broker the channels live on
+type EventServer struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`      // e.g. "kafka.internal:9092"
+	Protocol    string `json:"protocol"` // e.g. "kafka", "nats", "amqp", "mqtt"
+	Description string `json:"description,omitempty"`
+}
+
+// AddChannel documents an event channel for the AsyncAPI export at
+// /asyncapi.json. Adding a channel with the same name replaces it.
+func (a *APIDocs) AddChannel(name string, channel Channel) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.channels[name] = channel
+	a.generatedAt.Store(time.Now().Unix())
+}
+
+// GetAsyncAPIJSON returns an AsyncAPI 2.x document of the registered channels
+func (a *APIDocs) GetAsyncAPIJSON() map[string]interface{} {
+	a.mu.Lock()
+	channels := make(map[string]Channel, len(a.channels))
+	for name, channel := range a.channels {
+		channels[name] = channel
+	}
+	a.mu.Unlock()
+
+	asyncAPI := map[string]interface{}{
+		"asyncapi": asyncAPIVersion,
+		"info": map[string]interface{}{
+			"title":       a.config.Title,
+			"version":     a.config.Version,
+			"description": a.config.Description,
+		},
+		"defaultContentType": "application/json",
+		"channels":           map[string]interface{}{},
+	}
+
+	if len(a.config.EventServers) > 0 {
+		servers := make(map[string]interface{}, len(a.config.EventServers))
+		for i, server := range a.config.EventServers {
+			name := server.Name
+			if name == "" {
+				name = fmt.Sprintf("server%d", i+1)
+			}
+			entry := map[string]interface{}{"url": server.URL, "protocol": server.Protocol}
+			if server.Description != "" {
+				entry["description"] = server.Description
+			}
+			servers[name] = entry
+		}
+		asyncAPI["servers"] = servers
+	}
+
+	names := make([]string, 0, len(channels))
+	for name := range channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := asyncAPI["channels"].(map[string]interface{})
+	for _, name := range names {
+		channel := channels[name]
+		item := map[string]interface{}{}
+		if channel.Description != "" {
+			item["description"] = channel.Description
+		}
+		if len(channel.Parameters) > 0 {
+			parameters := make(map[string]interface{}, len(channel.Parameters))
+			for paramName, param := range channel.Parameters {
+				parameters[paramName] = map[string]interface{}{
+					"description": param.Description,
+					"schema":      map[string]interface{}{"type": normalizeOpenAPIType(param.Type)},
+				}
+			}
+			item["parameters"] = parameters
+		}
+		if channel.Subscribe != nil {
+			item["subscribe"] = asyncAPIOperation(name, channel.Subscribe)
+		}
+		if channel.Publish != nil {
+			item["publish"] = asyncAPIOperation(name, channel.Publish)
+		}
+		if len(channel.Bindings) > 0 {
+			item["bindings"] = channel.Bindings
+		}
+		items[name] = item
+	}
+
+	return asyncAPI
+}
+
+// asyncAPIOperation builds the operation object of one channel direction
+func asyncAPIOperation(channel string, operation *ChannelOperation) map[string]interface{} {
+	messageName := operation.MessageName
+	if messageName == "" {
+		messageName = channel
+	}
+	contentType := operation.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	message := map[string]interface{}{
+		"name":        messageName,
+		"contentType": contentType,
+	}
+	if operation.Message != nil {
+		schema, example := SchemaFromValue(operation.Message)
+		message["payload"] = schema
+		if example != nil {
+			message["examples"] = []map[string]interface{}{{"payload": example}}
+		}
+	}
+
+	result := map[string]interface{}{"message": message}
+	if operation.OperationID != "" {
+		result["operationId"] = operation.OperationID
+	}
+	if operation.Summary != "" {
+		result["summary"] = operation.Summary
+	}
+	if operation.Description != "" {
+		result["description"] = operation.Description
+	}
+	return result
+}
+
+// serveAsyncAPI serves the AsyncAPI document like /openapi.json
+func (a *APIDocs) serveAsyncAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := json.Marshal(a.GetAsyncAPIJSON())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode AsyncAPI JSON: %v", err), http.StatusInternalServerError)
+		return
+	}
+	a.writeCacheable(w, r, append(body, '\n'))
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAsyncAPI_ExportsRegisteredChannels(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs",
+		EventServers: []EventServer{{Name: "production", URL: "kafka.internal:9092", Protocol: "kafka"}},
+		AuthConfig:   &AuthConfig{Enabled: true, Type: "api_key", APIKey: "secret", APIKeyHeader: "X-API-Key"},
+	})
+	type OrderCreated struct {
+		OrderID string  `json:"orderId" example:"ord_1"`
+		Total   float64 `json:"total"`
+	}
+	docs.AddChannel("orders.created", Channel{
+		Description: "Emitted after checkout",
+		Subscribe:   &ChannelOperation{OperationID: "onOrderCreated", Message: OrderCreated{}},
+		Bindings:    map[string]interface{}{"kafka": map[string]interface{}{"partitions": 3}},
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/docs/asyncapi.json", nil)
+	req.Header.Set("X-API-Key", "secret")
+	docs.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected the AsyncAPI document, got %d", rec.Code)
+	}
+	var spec struct {
+		AsyncAPI string                            `json:"asyncapi"`
+		Servers  map[string]map[string]interface{} `json:"servers"`
+		Channels map[string]struct {
+			Subscribe struct {
+				OperationID string `json:"operationId"`
+				Message     struct {
+					Payload  map[string]interface{}   `json:"payload"`
+					Examples []map[string]interface{} `json:"examples"`
+				} `json:"message"`
+			} `json:"subscribe"`
+			Bindings map[string]interface{} `json:"bindings"`
+		} `json:"channels"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatal(err)
+	}
+	channel, ok := spec.Channels["orders.created"]
+	if spec.AsyncAPI != "2.6.0" || spec.Servers["production"]["protocol"] != "kafka" || !ok {
+		t.Fatalf("expected an AsyncAPI 2.x document with servers and the channel, got %s", rec.Body.String())
+	}
+	properties, _ := channel.Subscribe.Message.Payload["properties"].(map[string]interface{})
+	if channel.Subscribe.OperationID != "onOrderCreated" || properties["orderId"] == nil || len(channel.Subscribe.Message.Examples) != 1 || channel.Bindings["kafka"] == nil {
+		t.Fatalf("expected the message schema, example and bindings, got %s", rec.Body.String())
+	}
+}
@@ -1,24 +1,44 @@
 package core
 
 import (
+	"context"
+	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// AuthMiddleware protects the docs with the configured auth type. Failed
+// attempts of every type count towards the IP ban, so create the middleware
+// once and reuse it for all requests. Bans are keyed on the peer address;
+// forwarded client IPs are ignored, see Config.TrustedProxies.
 func AuthMiddleware(config *AuthConfig) func(http.Handler) http.Handler {
+	return authMiddleware(config, nil)
+}
+
+// authMiddleware is AuthMiddleware keying bans on the client IP behind trustedProxies
+func authMiddleware(config *AuthConfig, trustedProxies []string) func(http.Handler) http.Handler {
+	if config == nil || !config.Enabled {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	guard := newLoginGuard(config)
+	var sessionAuth *SessionAuthMiddleware
+	var sessionErr error
+	if config.Type == "session" {
+		sessionAuth, sessionErr = newSessionAuthMiddleware(config, guard, trustedProxies)
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if config == nil || !config.Enabled {
-				next.ServeHTTP(w, r)
-				return
-			}
-
 			if config.Type == "session" {
-				sessionAuth, err := NewSessionAuthMiddleware(config)
-				if err != nil {
+				if sessionErr != nil {
 					http.Error(w, "Failed to initialize session auth", http.StatusInternalServerError)
 					return
 				}
@@ -26,16 +46,63 @@ func AuthMiddleware(config *AuthConfig) func(http.Handler) http.Handler {
 				return
 			}
 
+			ip := clientIPBehind(r, trustedProxies)
+			if expiry, banned := guard.banExpiry(ip); banned {
+				handleBanned(w, expiry)
+				return
+			}
+
 			if err := authenticateRequest(r, config); err != nil {
+				// Requests without credentials are the browser's first try, not a guess
+				if !errors.Is(err, errMissingCredentials) {
+					if banned, _ := guard.recordFailure(ip); banned {
+						expiry, _ := guard.banExpiry(ip)
+						handleBanned(w, expiry)
+						return
+					}
+				}
 				handleAuthError(w, r, config, err)
 				return
 			}
 
-			next.ServeHTTP(w, r)
+			guard.recordSuccess(ip)
+			next.ServeHTTP(w, withAuthIdentity(r, verifiedIdentity(r, config)))
 		})
 	}
 }
 
+// authIdentityKey carries who the docs auth let a request in as
+type authIdentityKey struct{}
+
+// withAuthIdentity returns r carrying the identity the docs auth verified
+func withAuthIdentity(r *http.Request, identity string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), authIdentityKey{}, identity))
+}
+
+// authIdentity returns the identity set by withAuthIdentity
+func authIdentity(r *http.Request) (string, bool) {
+	identity, ok := r.Context().Value(authIdentityKey{}).(string)
+	return identity, ok && identity != ""
+}
+
+// verifiedIdentity names who passed authenticateRequest: the basic auth user,
+// or the configured key, which all of its holders share
+func verifiedIdentity(r *http.Request, config *AuthConfig) string {
+	if username, _, ok := r.BasicAuth(); ok && config.Type == "basic" {
+		return "user:" + username
+	}
+	return "key:" + config.Type
+}
+
+// sessionIdentity names a signed-in session without keeping its ID around
+func sessionIdentity(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return "session:" + hex.EncodeToString(sum[:8])
+}
+
+// errMissingCredentials marks requests that sent no credentials at all
+var errMissingCredentials = errors.New("missing credentials")
+
 func authenticateRequest(r *http.Request, config *AuthConfig) error {
 	switch config.Type {
 	case "basic":
@@ -52,7 +119,7 @@ func authenticateRequest(r *http.Request, config *AuthConfig) error {
 func authenticateBasic(r *http.Request, config *AuthConfig) error {
 	auth := r.Header.Get("Authorization")
 	if auth == "" {
-		return fmt.Errorf("missing Authorization header")
+		return fmt.Errorf("%w: Authorization header", errMissingCredentials)
 	}
 
 	if !strings.HasPrefix(auth, "Basic ") {
@@ -87,7 +154,7 @@ func authenticateAPIKey(r *http.Request, config *AuthConfig) error {
 
 	apiKey := r.Header.Get(headerName)
 	if apiKey == "" {
-		return fmt.Errorf("missing %s header", headerName)
+		return fmt.Errorf("%w: %s header", errMissingCredentials, headerName)
 	}
 
 	if subtle.ConstantTimeCompare([]byte(apiKey), []byte(config.APIKey)) != 1 {
@@ -100,7 +167,7 @@ func authenticateAPIKey(r *http.Request, config *AuthConfig) error {
 func authenticateBearer(r *http.Request, config *AuthConfig) error {
 	auth := r.Header.Get("Authorization")
 	if auth == "" {
-		return fmt.Errorf("missing Authorization header")
+		return fmt.Errorf("%w: Authorization header", errMissingCredentials)
 	}
 
 	if !strings.HasPrefix(auth, "Bearer ") {
@@ -166,6 +233,18 @@ func handleAuthError(w http.ResponseWriter, r *http.Request, config *AuthConfig,
 	)))
 }
 
+// handleBanned rejects a client banned after too many failed attempts
+func handleBanned(w http.ResponseWriter, expiry time.Time) {
+	retryAfter := int(time.Until(expiry).Seconds()) + 1
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.WriteHeader(http.StatusForbidden)
+	w.Write([]byte(`{"error": "Too many failed attempts", "message": "This IP address is temporarily banned"}`))
+}
+
 func GinAuthMiddleware(config *AuthConfig) func(c interface{}) {
 	return func(c interface{}) {
 	}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuth_BansRepeatedFailuresForBasicAndAPIKey(t *testing.T) {
+	for _, auth := range []*AuthConfig{
+		{Enabled: true, Type: "basic", Username: "admin", Password: "secret", IPBanEnabled: true, IPBanMaxAttempts: 3, IPBanDuration: 5, AdminWhitelistIPs: []string{"10.0.0.0/8"}},
+		{Enabled: true, Type: "api_key", APIKey: "secret", IPBanEnabled: true, IPBanMaxAttempts: 3, IPBanDuration: 5, AdminWhitelistIPs: []string{"10.0.0.0/8"}},
+	} {
+		docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", AuthConfig: auth})
+		request := func(remoteAddr, credential string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest("GET", "/docs/api-data.json", nil)
+			req.RemoteAddr = remoteAddr
+			if credential != "" && auth.Type == "basic" {
+				req.SetBasicAuth("admin", credential)
+			} else if credential != "" {
+				req.Header.Set("X-API-Key", credential)
+			}
+			rec := httptest.NewRecorder()
+			docs.ServeHTTP(rec, req)
+			return rec
+		}
+
+		for i := 0; i < 5; i++ {
+			if rec := request("203.0.113.7:4000", ""); rec.Code != 401 {
+				t.Fatalf("%s: requests without credentials must not ban, got %d", auth.Type, rec.Code)
+			}
+		}
+		for i := 0; i < 2; i++ {
+			if rec := request("203.0.113.7:4000", "wrong"); rec.Code != 401 {
+				t.Fatalf("%s: expected 401 for attempt %d, got %d", auth.Type, i+1, rec.Code)
+			}
+		}
+		if rec := request("203.0.113.7:4000", "wrong"); rec.Code != 403 || rec.Header().Get("Retry-After") == "" {
+			t.Fatalf("%s: expected a ban at the limit, got %d %v", auth.Type, rec.Code, rec.Header())
+		}
+		if rec := request("203.0.113.7:4000", "secret"); rec.Code != 403 {
+			t.Fatalf("%s: banned IP must stay banned even with valid credentials, got %d", auth.Type, rec.Code)
+		}
+		if rec := request("198.51.100.2:4000", "secret"); rec.Code != 200 {
+			t.Fatalf("%s: other clients must not be affected, got %d", auth.Type, rec.Code)
+		}
+
+		for i := 0; i < 6; i++ {
+			request("10.1.2.3:4000", "wrong")
+		}
+		if rec := request("10.1.2.3:4000", "secret"); rec.Code != 200 {
+			t.Fatalf("%s: whitelisted range must never be banned, got %d", auth.Type, rec.Code)
+		}
+	}
+}
+
+func TestAuth_BansIgnoreForwardedHeadersFromUntrustedPeers(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", TrustedProxies: []string{"192.0.2.10"}, AuthConfig: &AuthConfig{
+		Enabled: true, Type: "basic", Username: "admin", Password: "secret",
+		IPBanEnabled: true, IPBanMaxAttempts: 3, IPBanDuration: 5, AdminWhitelistIPs: []string{"127.0.0.1"},
+	}})
+	request := func(remoteAddr, forwarded, password string) int {
+		req := httptest.NewRequest("GET", "/docs/api-data.json", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwarded)
+		req.Header.Set("X-Real-IP", forwarded)
+		req.SetBasicAuth("admin", password)
+		rec := httptest.NewRecorder()
+		docs.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// A new spoofed address per attempt, including a whitelisted one, still counts against the peer
+	for i, forwarded := range []string{"198.51.100.1", "127.0.0.1"} {
+		if code := request("203.0.113.7:4000", forwarded, "wrong"); code != 401 {
+			t.Fatalf("expected 401 for attempt %d, got %d", i+1, code)
+		}
+	}
+	if code := request("203.0.113.7:4000", "198.51.100.3", "wrong"); code != 403 {
+		t.Fatalf("expected the peer banned despite spoofed headers, got %d", code)
+	}
+	if code := request("203.0.113.7:4000", "127.0.0.1", "secret"); code != 403 {
+		t.Fatalf("a spoofed whitelisted address must not lift the ban, got %d", code)
+	}
+
+	// Behind the trusted proxy the forwarded client is banned, not the proxy
+	for i := 0; i < 3; i++ {
+		request("192.0.2.10:4000", "198.51.100.9", "wrong")
+	}
+	if code := request("192.0.2.10:4000", "198.51.100.9", "secret"); code != 403 {
+		t.Fatalf("expected the forwarded client banned, got %d", code)
+	}
+	if code := request("192.0.2.10:4000", "198.51.100.10", "secret"); code != 200 {
+		t.Fatalf("other clients behind the proxy must not be affected, got %d", code)
+	}
+}
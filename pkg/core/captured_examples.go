@@ -0,0 +1,136 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+const defaultCapturedExamples = 3
+
+// capturedExamples are the distinct redacted bodies sampled for a documented route
+type capturedExamples struct {
+	request   []interface{}
+	responses map[string][]interface{} // by status code
+}
+
+// captureExamples keeps the sampled bodies of a documented route as further
+// examples, skipping duplicates and bodies beyond Config.Traffic.MaxExamples.
+// The docs are regenerated when an example was added.
+func (a *APIDocs) captureExamples(key string, request interface{}, status string, response interface{}) {
+	maxExamples := a.config.Traffic.MaxExamples
+	if maxExamples <= 0 {
+		maxExamples = defaultCapturedExamples
+	}
+
+	a.traffic.mu.Lock()
+	defer a.traffic.mu.Unlock()
+	if a.traffic.examples == nil {
+		a.traffic.examples = make(map[string]*capturedExamples)
+	}
+	captured, ok := a.traffic.examples[key]
+	if !ok {
+		captured = &capturedExamples{responses: make(map[string][]interface{})}
+		a.traffic.examples[key] = captured
+	}
+
+	added := false
+	if request != nil {
+		captured.request, ok = appendExample(captured.request, request, maxExamples)
+		added = added || ok
+	}
+	if response != nil {
+		captured.responses[status], ok = appendExample(captured.responses[status], response, maxExamples)
+		added = added || ok
+	}
+	if added {
+		a.dirty.Store(true)
+	}
+}
+
+// appendExample adds value unless an equal example is kept or the list is full
+func appendExample(examples []interface{}, value interface{}, maxExamples int) ([]interface{}, bool) {
+	if len(examples) >= maxExamples {
+		return examples, false
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return examples, false
+	}
+	for _, example := range examples {
+		if existing, _ := json.Marshal(example); string(existing) == string(encoded) {
+			return examples, false
+		}
+	}
+	return append(examples, value), true
+}
+
+// applyCapturedExamples attaches the examples captured from traffic to the
+// request body and the documented responses of endpoint
+func (a *APIDocs) applyCapturedExamples(endpoint *Endpoint) {
+	a.traffic.mu.Lock()
+	defer a.traffic.mu.Unlock()
+	captured, ok := a.traffic.examples[endpoint.Method+" "+endpoint.Path]
+	if !ok {
+		return
+	}
+
+	if endpoint.RequestBody != nil && len(captured.request) > 0 {
+		body := *endpoint.RequestBody
+		body.Examples = append(append([]Example{}, body.Examples...), namedCapturedExamples(captured.request)...)
+		endpoint.RequestBody = &body
+	}
+
+	statuses := make([]string, 0, len(captured.responses))
+	for status := range captured.responses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	var responses map[string]Response
+	for _, status := range statuses {
+		response, ok := endpoint.Responses[status]
+		if !ok {
+			continue
+		}
+		if responses == nil {
+			responses = make(map[string]Response, len(endpoint.Responses))
+			for code, existing := range endpoint.Responses {
+				responses[code] = existing
+			}
+		}
+		response.Examples = append(append([]Example{}, response.Examples...), namedCapturedExamples(captured.responses[status])...)
+		responses[status] = response
+	}
+	if responses != nil {
+		endpoint.Responses = responses
+	}
+}
+
+func namedCapturedExamples(values []interface{}) []Example {
+	examples := make([]Example, 0, len(values))
+	for i, value := range values {
+		examples = append(examples, Example{Name: fmt.Sprintf("captured-%d", i+1), Summary: "Captured from traffic", Value: value})
+	}
+	return examples
+}
+
+// setMediaExamples exports further examples as the named examples of an
+// OpenAPI media type, with its single example as "default"
+func setMediaExamples(media map[string]interface{}, examples []Example) {
+	if len(examples) == 0 {
+		return
+	}
+	named := make(map[string]interface{}, len(examples)+1)
+	if example := media["example"]; example != nil {
+		named["default"] = map[string]interface{}{"value": example}
+	}
+	for _, example := range examples {
+		entry := map[string]interface{}{"value": example.Value}
+		if example.Summary != "" {
+			entry["summary"] = example.Summary
+		}
+		named[example.Name] = entry
+	}
+	delete(media, "example")
+	media["examples"] = named
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCapturedExamples_AttachedToDocumentedEndpoints(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", Traffic: &TrafficConfig{CaptureExamples: true, MaxExamples: 2}})
+	docs.AddRouteInfo(RouteInfo{Method: "POST", Path: "/users/:id/notes",
+		RequestBody: &RequestBody{ContentType: "application/json", Schema: map[string]interface{}{"type": "object"}, Example: map[string]interface{}{"text": "string"}},
+		Responses:   map[string]Response{"200": {Description: "OK", Example: map[string]interface{}{"id": 0}}},
+	})
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	app := docs.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "missing") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":%q,"sessionToken":"abc"}`, strings.Split(r.URL.Path, "/")[2])
+	}))
+	for _, id := range []string{"1", "1", "2", "3"} {
+		request := httptest.NewRequest("POST", "/users/"+id+"/notes", strings.NewReader(`{"text":"hello","password":"secret"}`))
+		request.Header.Set("Content-Type", "application/json")
+		app.ServeHTTP(httptest.NewRecorder(), request)
+	}
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/missing", nil))
+
+	if routes := docs.ObservedRoutes(); len(routes) != 0 {
+		t.Fatalf("expected no observed routes without Enabled, got %+v", routes)
+	}
+	spec, err := docs.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	operation := spec["paths"].(map[string]interface{})["/users/{id}/notes"].(map[string]interface{})["post"].(map[string]interface{})
+	requestMedia := operation["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	requestExamples, _ := requestMedia["examples"].(map[string]interface{})
+	if len(requestExamples) != 2 || requestMedia["example"] != nil {
+		t.Fatalf("expected the documented example and one deduplicated capture, got %+v", requestMedia)
+	}
+	captured := requestExamples["captured-1"].(map[string]interface{})["value"].(map[string]interface{})
+	if captured["text"] != "hello" || captured["password"] != "[REDACTED]" {
+		t.Fatalf("expected a redacted captured request, got %+v", captured)
+	}
+
+	responseMedia := operation["responses"].(map[string]interface{})["200"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	responseExamples, _ := responseMedia["examples"].(map[string]interface{})
+	if len(responseExamples) != 3 || responseExamples["default"] == nil || responseExamples["captured-3"] != nil {
+		t.Fatalf("expected two captured responses next to the default, got %+v", responseExamples)
+	}
+	if value := responseExamples["captured-2"].(map[string]interface{})["value"].(map[string]interface{}); value["id"] != "2" || value["sessionToken"] != "[REDACTED]" {
+		t.Fatalf("expected the second distinct response, got %+v", value)
+	}
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// pathMention finds paths in an answer, with the method written before them
+// as in "GET /users/{id}" or curl's "-X POST https://api.example.com/users"
+var pathMention = regexp.MustCompile("(?i)(?:\\b(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS)\\s+[\"'`]?)?(?:https?://[^\\s/\"'`]+)?(/[A-Za-z0-9_\\-{}:.~%/]*)")
+
+// chatSources returns the documented operations an answer refers to, by
+// method and path or by operationId, in the order they are first mentioned.
+// A path mentioned without a method refers to all its operations unless it
+// is also mentioned with one.
+func (a *APIDocs) chatSources(answer string) []ChatSource {
+	if answer == "" {
+		return nil
+	}
+	documentation := a.GetDocumentation()
+	endpoints := make([]Endpoint, 0)
+	for _, section := range documentation.Endpoints {
+		endpoints = append(endpoints, section.Endpoints...)
+	}
+	prefixes := a.basePathPrefixes()
+
+	first := make(map[int]int) // endpoint index to the position of its first mention
+	mention := func(i, position int) {
+		if existing, ok := first[i]; !ok || position < existing {
+			first[i] = position
+		}
+	}
+
+	qualified := make(map[string]bool) // paths mentioned with a method
+	type bareMention struct {
+		position  int
+		endpoints []int
+	}
+	var bare []bareMention
+	for _, match := range pathMention.FindAllStringSubmatchIndex(answer, -1) {
+		start := match[4]
+		if start > 0 && match[2] < 0 && match[0] == start {
+			// "and/or", "application/json": a path starts a word
+			if previous := rune(answer[start-1]); unicode.IsLetter(previous) || unicode.IsDigit(previous) {
+				continue
+			}
+		}
+		mentioned := strings.TrimRight(answer[start:match[5]], ".,:;")
+		if mentioned == "/" || mentioned == "" {
+			continue
+		}
+		method := ""
+		if match[2] >= 0 {
+			method = strings.ToUpper(answer[match[2]:match[3]])
+		}
+
+		var matched []int
+		for i, endpoint := range endpoints {
+			if (method == "" || strings.EqualFold(endpoint.Method, method)) && matchesDocumentedPath(endpoint.Path, mentioned, prefixes) {
+				matched = append(matched, i)
+			}
+		}
+		if method == "" {
+			bare = append(bare, bareMention{position: match[0], endpoints: matched})
+			continue
+		}
+		for _, i := range matched {
+			qualified[endpoints[i].Path] = true
+			mention(i, match[0])
+		}
+	}
+	for _, candidate := range bare {
+		for _, i := range candidate.endpoints {
+			if !qualified[endpoints[i].Path] {
+				mention(i, candidate.position)
+			}
+		}
+	}
+
+	for i, endpoint := range endpoints {
+		if position := operationIDMention(answer, endpoint.ID); position >= 0 {
+			mention(i, position)
+		}
+	}
+
+	if len(first) == 0 {
+		return nil
+	}
+	indexes := make([]int, 0, len(first))
+	for i := range first {
+		indexes = append(indexes, i)
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		if first[indexes[i]] != first[indexes[j]] {
+			return first[indexes[i]] < first[indexes[j]]
+		}
+		return indexes[i] < indexes[j]
+	})
+	sources := make([]ChatSource, 0, len(indexes))
+	for _, i := range indexes {
+		endpoint := endpoints[i]
+		sources = append(sources, ChatSource{OperationID: endpoint.ID, Method: endpoint.Method, Path: endpoint.Path, Summary: endpoint.Summary})
+	}
+	return sources
+}
+
+// basePathPrefixes returns the paths of the configured base URLs, which
+// answers may write in front of documented paths
+func (a *APIDocs) basePathPrefixes() []string {
+	urls := []string{a.config.BaseURL}
+	for _, option := range a.config.BaseURLs {
+		urls = append(urls, option.URL)
+	}
+	var prefixes []string
+	for _, raw := range urls {
+		if parsed, err := url.Parse(raw); err == nil {
+			if prefix := strings.TrimRight(parsed.Path, "/"); prefix != "" {
+				prefixes = append(prefixes, prefix)
+			}
+		}
+	}
+	return prefixes
+}
+
+// matchesDocumentedPath reports whether a mentioned path is a documented path
+// template, literally or with values for its parameters, optionally behind
+// a base URL path
+func matchesDocumentedPath(template, mentioned string, prefixes []string) bool {
+	if matchPathSegments(template, mentioned) {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if rest := strings.TrimPrefix(mentioned, prefix); rest != mentioned && strings.HasPrefix(rest, "/") && matchPathSegments(template, rest) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPathSegments(template, mentioned string) bool {
+	templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+	mentionedSegments := strings.Split(strings.Trim(mentioned, "/"), "/")
+	if len(templateSegments) != len(mentionedSegments) {
+		return false
+	}
+	for i, segment := range templateSegments {
+		if strings.HasPrefix(segment, "{") || strings.HasPrefix(segment, ":") {
+			if mentionedSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != mentionedSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// operationIDMention returns where an operationId is mentioned as a whole
+// word, or -1. IDs that are plain lowercase words are not looked for, they
+// are likely to appear in any answer.
+func operationIDMention(answer, id string) int {
+	if len(id) < 4 || strings.IndexFunc(id, func(r rune) bool { return !unicode.IsLower(r) }) < 0 {
+		return -1
+	}
+	isWordRune := func(r byte) bool {
+		return r == '_' || r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+	}
+	for offset := 0; offset < len(answer); {
+		index := strings.Index(answer[offset:], id)
+		if index < 0 {
+			return -1
+		}
+		start, end := offset+index, offset+index+len(id)
+		if (start == 0 || !isWordRune(answer[start-1])) && (end == len(answer) || !isWordRune(answer[end])) {
+			return start
+		}
+		offset = end
+	}
+	return -1
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestChatSources_LinkReferencedOperations(t *testing.T) {
+	answer := "Create the user with `POST /users` and read it back:\n\n" +
+		"```bash\ncurl -X DELETE \"https://api.example.com/v1/users/42\" -H 'Content-Type: application/json'\n```\n\n" +
+		"Orders are listed by listOrders and/or GET /orders?page=2. There is no /payments endpoint; /users is paginated."
+	RegisterLLMClientFactory("fake-cited", func(config *AIConfig) (LLMClient, error) {
+		return &fakeChatClient{provider: "fake-cited", model: "m-1", answer: answer}, nil
+	})
+	docs := New(&Config{Title: "Test", DocsPath: "/docs", BaseURL: "https://api.example.com/v1",
+		AIConfig: &AIConfig{Enabled: true, Provider: "fake-cited", Features: AIFeatures{ChatEnabled: true}},
+		OperationID: func(route RouteInfo, endpoint *Endpoint) string {
+			if endpoint.Path == "/orders" {
+				return "listOrders"
+			}
+			return ""
+		},
+	})
+	docs.AddRoute("GET", "/users", nil, WithSummary("List users"))
+	docs.AddRoute("POST", "/users", nil, WithSummary("Create user"))
+	docs.AddRoute("GET", "/users/{id}", nil)
+	docs.AddRoute("DELETE", "/users/{id}", nil)
+	docs.AddRoute("GET", "/orders", nil)
+	docs.AddRoute("GET", "/payments/{id}", nil)
+
+	recorder := httptest.NewRecorder()
+	docs.ServeHTTP(recorder, httptest.NewRequest("POST", "/docs/chat", strings.NewReader(`{"message":"How do I manage users?"}`)))
+	var response ChatResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	var cited []string
+	for _, source := range response.Sources {
+		cited = append(cited, source.Method+" "+source.Path)
+	}
+	if strings.Join(cited, ", ") != "POST /users, DELETE /users/{id}, GET /orders" {
+		t.Fatalf("unexpected sources: %v", cited)
+	}
+	if response.Sources[0].Summary != "Create user" || response.Sources[2].OperationID != "listOrders" {
+		t.Fatalf("expected operationIds and summaries, got %+v", response.Sources)
+	}
+}
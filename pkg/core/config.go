@@ -1,10 +1,13 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/idnexacloud/bytedocs-go/pkg/ai"
@@ -28,10 +31,36 @@ func LoadConfigFromEnv(envFile ...string) (*Config, error) {
 		Description: getEnvOrDefault("BYTEDOCS_DESCRIPTION", "Auto-generated API documentation"),
 		BaseURL:     getEnvOrDefault("BYTEDOCS_BASE_URL", "http://localhost:8080"),
 		DocsPath:    getEnvOrDefault("BYTEDOCS_DOCS_PATH", "/docs"),
+		RootPrefix:  os.Getenv("BYTEDOCS_ROOT_PREFIX"),
+		SeparatePort: os.Getenv("BYTEDOCS_SEPARATE_PORT"),
 		AutoDetect:  getEnvBool("BYTEDOCS_AUTO_DETECT", true),
-		ExcludePaths: getEnvSlice("BYTEDOCS_EXCLUDE_PATHS", []string{"_ignition", "debug", "health"}),
+		ExcludePaths: getEnvSlice("BYTEDOCS_EXCLUDE_PATHS", DefaultExcludePaths),
+		SkipMethods:  getEnvSlice("BYTEDOCS_SKIP_METHODS", nil),
+		SkipAutoOPTIONS: getEnvBool("BYTEDOCS_SKIP_AUTO_OPTIONS", false),
+		SpecKeyOrder:    getEnvOrDefault("BYTEDOCS_SPEC_KEY_ORDER", ""),
+		SpecValidation:  os.Getenv("BYTEDOCS_SPEC_VALIDATION"),
+		GroupingStrategy: os.Getenv("BYTEDOCS_GROUPING_STRATEGY"),
+		AnalysisCacheDir: os.Getenv("BYTEDOCS_ANALYSIS_CACHE_DIR"),
+		AnalysisWorkers:  getEnvInt("BYTEDOCS_ANALYSIS_WORKERS", 0),
+		Debug:            getEnvBool("BYTEDOCS_DEBUG", false),
+		HealthDetails:    getEnvBool("BYTEDOCS_HEALTH_DETAILS", false),
+		ErrorResponseType: os.Getenv("BYTEDOCS_ERROR_RESPONSE_TYPE"),
+		ErrorHelpers:      getEnvSlice("BYTEDOCS_ERROR_HELPERS", nil),
+		ResponseEnvelopes: getEnvSlice("BYTEDOCS_RESPONSE_ENVELOPES", nil),
+		UnwrapEnvelopes:   getEnvBool("BYTEDOCS_UNWRAP_ENVELOPES", false),
+		FakeExamples:      getEnvBool("BYTEDOCS_FAKE_EXAMPLES", false),
+		ExampleLocale:     os.Getenv("BYTEDOCS_EXAMPLE_LOCALE"),
+		AllowRawMarkup:    getEnvBool("BYTEDOCS_ALLOW_RAW_MARKUP", false),
+		GuidesDir:         os.Getenv("BYTEDOCS_GUIDES_DIR"),
 	}
 
+	// An explicitly empty BYTEDOCS_BASE_URL derives servers from each request
+	if value, ok := os.LookupEnv("BYTEDOCS_BASE_URL"); ok && value == "" {
+		config.BaseURL = ""
+		config.ServersFromRequest = true
+	}
+	config.TrustedProxies = getEnvSlice("BYTEDOCS_TRUSTED_PROXIES", nil)
+
 	// Load multiple base URLs if provided
 	if prodURL := os.Getenv("BYTEDOCS_PRODUCTION_URL"); prodURL != "" {
 		config.BaseURLs = append(config.BaseURLs, BaseURLOption{
@@ -52,6 +81,92 @@ func LoadConfigFromEnv(envFile ...string) (*Config, error) {
 		})
 	}
 
+	// Load security header overrides
+	if hasSecurityHeadersConfig() {
+		config.SecurityHeaders = &SecurityHeadersConfig{
+			Disabled:              !getEnvBool("BYTEDOCS_SECURITY_HEADERS", true),
+			ContentSecurityPolicy: os.Getenv("BYTEDOCS_CSP"),
+			ScriptSources:         getEnvSlice("BYTEDOCS_CSP_SCRIPT_SOURCES", nil),
+			ConnectSources:        getEnvSlice("BYTEDOCS_CSP_CONNECT_SOURCES", nil),
+			FrameAncestors:        getEnvSlice("BYTEDOCS_CSP_FRAME_ANCESTORS", nil),
+		}
+	}
+
+	// Load the pagination convention of list endpoints
+	if style := os.Getenv("BYTEDOCS_PAGINATION_STYLE"); style != "" {
+		config.Pagination = &Pagination{
+			Style:       style,
+			DefaultSize: getEnvInt("BYTEDOCS_PAGINATION_DEFAULT_SIZE", 0),
+			MaxSize:     getEnvInt("BYTEDOCS_PAGINATION_MAX_SIZE", 0),
+			ItemsField:  os.Getenv("BYTEDOCS_PAGINATION_ITEMS_FIELD"),
+		}
+	}
+
+	// Load the credentials of the refresh endpoint
+	if token, secret := os.Getenv("BYTEDOCS_REFRESH_TOKEN"), os.Getenv("BYTEDOCS_REFRESH_WEBHOOK_SECRET"); token != "" || secret != "" {
+		config.Refresh = &RefreshConfig{
+			Token:         token,
+			WebhookSecret: secret,
+		}
+	}
+
+	// Load the spec change notifications
+	if slack, teams := os.Getenv("BYTEDOCS_NOTIFY_SLACK_WEBHOOK"), os.Getenv("BYTEDOCS_NOTIFY_TEAMS_WEBHOOK"); slack != "" || teams != "" {
+		config.Notifications = &NotificationConfig{
+			SlackWebhookURL: slack,
+			TeamsWebhookURL: teams,
+			BreakingOnly:    getEnvBool("BYTEDOCS_NOTIFY_BREAKING_ONLY", false),
+			StateFile:       os.Getenv("BYTEDOCS_NOTIFY_STATE_FILE"),
+			DocsURL:         os.Getenv("BYTEDOCS_NOTIFY_DOCS_URL"),
+		}
+	}
+
+	// Load the usage analytics
+	if getEnvBool("BYTEDOCS_ANALYTICS_ENABLED", false) {
+		config.Analytics = &AnalyticsConfig{
+			Enabled:     true,
+			MaxSearches: getEnvInt("BYTEDOCS_ANALYTICS_MAX_SEARCHES", defaultMaxSearches),
+		}
+	}
+
+	// Load the traffic recording of Middleware
+	if getEnvBool("BYTEDOCS_TRAFFIC_ENABLED", false) || getEnvBool("BYTEDOCS_TRAFFIC_CAPTURE_EXAMPLES", false) {
+		config.Traffic = &TrafficConfig{
+			Enabled:       getEnvBool("BYTEDOCS_TRAFFIC_ENABLED", false),
+			SampleRate:    getEnvFloat("BYTEDOCS_TRAFFIC_SAMPLE_RATE", 0),
+			MaxBodyBytes:  int64(getEnvInt("BYTEDOCS_TRAFFIC_MAX_BODY_BYTES", defaultTrafficBodyBytes)),
+			RedactFields:  getEnvSlice("BYTEDOCS_TRAFFIC_REDACT_FIELDS", nil),
+			PathTemplates: getEnvSlice("BYTEDOCS_TRAFFIC_PATH_TEMPLATES", nil),
+			AutoPublish:   getEnvBool("BYTEDOCS_TRAFFIC_AUTO_PUBLISH", false),
+
+			CaptureExamples: getEnvBool("BYTEDOCS_TRAFFIC_CAPTURE_EXAMPLES", false),
+			MaxExamples:     getEnvInt("BYTEDOCS_TRAFFIC_MAX_EXAMPLES", defaultCapturedExamples),
+		}
+	}
+
+	// Load the redaction rules
+	if getEnvBool("BYTEDOCS_REDACTION_ENABLED", false) {
+		config.Redaction = &RedactionConfig{
+			Enabled:     true,
+			Fields:      getEnvSlice("BYTEDOCS_REDACTION_FIELDS", nil),
+			Patterns:    getEnvSlice("BYTEDOCS_REDACTION_PATTERNS", nil),
+			Replacement: os.Getenv("BYTEDOCS_REDACTION_REPLACEMENT"),
+		}
+	}
+
+	// Load the federated services, "Name:URL" pairs
+	if services := getEnvMap("BYTEDOCS_FEDERATION_SERVICES"); len(services) > 0 {
+		config.Federation = &FederationConfig{
+			Interval: time.Duration(getEnvInt("BYTEDOCS_FEDERATION_INTERVAL", 300)) * time.Second,
+		}
+		for name, url := range services {
+			config.Federation.Services = append(config.Federation.Services, FederatedService{Name: name, URL: url})
+		}
+		sort.Slice(config.Federation.Services, func(i, j int) bool {
+			return config.Federation.Services[i].Name < config.Federation.Services[j].Name
+		})
+	}
+
 	// Load authentication config
 	if getEnvBool("BYTEDOCS_AUTH_ENABLED", false) {
 		config.AuthConfig = &AuthConfig{
@@ -69,6 +184,12 @@ func LoadConfigFromEnv(envFile ...string) (*Config, error) {
 			IPBanMaxAttempts:     getEnvInt("BYTEDOCS_AUTH_IP_BAN_MAX_ATTEMPTS", 5),
 			IPBanDuration:        getEnvInt("BYTEDOCS_AUTH_IP_BAN_DURATION", 60),
 			AdminWhitelistIPs:    getEnvSlice("BYTEDOCS_AUTH_ADMIN_WHITELIST_IPS", []string{"127.0.0.1"}),
+			CookieSameSite:       getEnvOrDefault("BYTEDOCS_AUTH_COOKIE_SAMESITE", ""),
+			CookieSecure:         getEnvBool("BYTEDOCS_AUTH_COOKIE_SECURE", false),
+			LogoutEnabled:        getEnvBool("BYTEDOCS_AUTH_LOGOUT_ENABLED", false),
+			TOTPEnabled:          getEnvBool("BYTEDOCS_AUTH_TOTP_ENABLED", false),
+			TOTPIssuer:           getEnvOrDefault("BYTEDOCS_AUTH_TOTP_ISSUER", ""),
+			TOTPUsers:            getEnvMap("BYTEDOCS_AUTH_TOTP_USERS"),
 		}
 	}
 
@@ -83,6 +204,16 @@ func LoadConfigFromEnv(envFile ...string) (*Config, error) {
 			Favicon:     getEnvOrDefault("BYTEDOCS_UI_FAVICON", ""),
 			Title:       getEnvOrDefault("BYTEDOCS_UI_TITLE", ""),
 			Subtitle:    getEnvOrDefault("BYTEDOCS_UI_SUBTITLE", ""),
+			FlatNavigation: getEnvBool("BYTEDOCS_UI_FLAT_NAVIGATION", false),
+
+			MaxConcurrentRequests: getEnvInt("BYTEDOCS_UI_MAX_CONCURRENT_REQUESTS", 0),
+			MaxQueuedRequests:     getEnvInt("BYTEDOCS_UI_MAX_QUEUED_REQUESTS", 0),
+			QueueTimeout:          getEnvInt("BYTEDOCS_UI_QUEUE_TIMEOUT", 0),
+			MaxRequestBytes:       int64(getEnvInt("BYTEDOCS_UI_MAX_REQUEST_BYTES", 0)),
+			MaxResponseBytes:      int64(getEnvInt("BYTEDOCS_UI_MAX_RESPONSE_BYTES", 0)),
+			MaxDownloadBytes:      int64(getEnvInt("BYTEDOCS_UI_MAX_DOWNLOAD_BYTES", 0)),
+			MaxKeptBytes:          int64(getEnvInt("BYTEDOCS_UI_MAX_KEPT_BYTES", 0)),
+			TestClient:            testClientConfigFromEnv(),
 		}
 	}
 
@@ -99,6 +230,13 @@ func LoadConfigFromEnv(envFile ...string) (*Config, error) {
 				MaxTokens:            getEnvInt("BYTEDOCS_AI_MAX_TOKENS", 1000),
 				MaxCompletionTokens:  getEnvInt("BYTEDOCS_AI_MAX_COMPLETION_TOKENS", 1000),
 				Temperature:          getEnvFloat("BYTEDOCS_AI_TEMPERATURE", 0.7),
+				ContextBudget:        getEnvInt("BYTEDOCS_AI_CONTEXT_BUDGET", 0),
+				ContextEndpoints:     getEnvInt("BYTEDOCS_AI_CONTEXT_ENDPOINTS", 0),
+				AllowedModels:        getEnvSlice("BYTEDOCS_AI_ALLOWED_MODELS", nil),
+				DailyTokens:          getEnvInt("BYTEDOCS_AI_DAILY_TOKENS", 0),
+				DailyTokensPerClient: getEnvInt("BYTEDOCS_AI_DAILY_TOKENS_PER_CLIENT", 0),
+				PromptTemplate:       getEnvOrDefault("BYTEDOCS_AI_PROMPT_TEMPLATE", ""),
+				PromptTemplateFile:   getEnvOrDefault("BYTEDOCS_AI_PROMPT_TEMPLATE_FILE", ""),
 			},
 			Settings: map[string]interface{}{
 				"app_name": getEnvOrDefault("APP_NAME", "ByteDocs API"),
@@ -106,6 +244,21 @@ func LoadConfigFromEnv(envFile ...string) (*Config, error) {
 				"base_url": getEnvOrDefault("BYTEDOCS_AI_BASE_URL", ""),
 			},
 		}
+		// "gemini:gemini-2.5-flash,openrouter:openai/gpt-4o"; keys come from the
+		// providers' environment variables, e.g. GEMINI_API_KEY
+		for _, entry := range getEnvSlice("BYTEDOCS_AI_FALLBACKS", nil) {
+			provider, model, _ := strings.Cut(strings.TrimSpace(entry), ":")
+			if provider != "" {
+				config.AIConfig.Fallbacks = append(config.AIConfig.Fallbacks, ai.AIFallback{Provider: provider, Model: strings.TrimSpace(model)})
+			}
+		}
+	}
+
+	// Resolve secret references such as BYTEDOCS_AI_API_KEY="file:///run/secrets/openai_key"
+	ctx, cancel := context.WithTimeout(context.Background(), secretResolveTimeout)
+	defer cancel()
+	if err := ResolveSecrets(ctx, config); err != nil {
+		return nil, err
 	}
 
 	return config, nil
@@ -130,6 +283,63 @@ func ValidateConfig(config *Config) error {
 	if !strings.HasPrefix(config.DocsPath, "/") {
 		return fmt.Errorf("docs path must start with /")
 	}
+	if config.RootPrefix != "" && !strings.HasPrefix(config.RootPrefix, "/") {
+		return fmt.Errorf("root prefix must start with /")
+	}
+	if !validGroupingStrategy(config.GroupingStrategy) {
+		return fmt.Errorf("unknown grouping strategy %q, use %q, %q, %q, %q or %q", config.GroupingStrategy,
+			GroupByLastSegment, GroupByPrefix, GroupByResource, GroupByTag, GroupByFile)
+	}
+	for _, option := range config.BaseURLs {
+		if err := validateServerVariables(option); err != nil {
+			return err
+		}
+	}
+	if config.GuidesDir != "" {
+		if info, err := os.Stat(config.GuidesDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("guides dir %q is not a directory", config.GuidesDir)
+		}
+	}
+	if config.Pagination != nil {
+		if style := config.Pagination.Style; style == PaginationNone || !validPaginationStyle(style) {
+			return fmt.Errorf("unknown pagination style %q, use %q, %q or %q", style,
+				PaginationOffset, PaginationCursor, PaginationPage)
+		}
+	}
+	if err := validateDefaultResponses(config.DefaultResponses); err != nil {
+		return err
+	}
+	if _, err := ParseResponseEnvelopes(config.ResponseEnvelopes); err != nil {
+		return err
+	}
+	if config.Redaction != nil {
+		if _, err := newRedactor(config.Redaction, nil); err != nil {
+			return err
+		}
+	}
+	if config.UIConfig != nil && config.UIConfig.TestClient != nil {
+		if err := config.UIConfig.TestClient.validate(); err != nil {
+			return fmt.Errorf("invalid test client config: %w", err)
+		}
+	}
+	// Validate base URLs
+	if config.BaseURL == "" && len(config.BaseURLs) == 0 && !config.ServersFromRequest {
+		return fmt.Errorf("at least one base URL must be provided, or set ServersFromRequest")
+	}
+	if _, err := parseTrustedProxies(config.TrustedProxies); err != nil {
+		return err
+	}
+	if config.Traffic != nil && (config.Traffic.SampleRate < 0 || config.Traffic.SampleRate > 1) {
+		return fmt.Errorf("traffic sample rate %v must be between 0 and 1", config.Traffic.SampleRate)
+	}
+	if mode := config.SpecValidation; mode != "" && mode != SpecValidationStrict && mode != SpecValidationAnnotate {
+		return fmt.Errorf("unknown spec validation mode %q, use %q or %q", mode, SpecValidationStrict, SpecValidationAnnotate)
+	}
+	if config.ExampleLocale != "" {
+		if _, ok := lookupExampleLocale(config.ExampleLocale); !ok {
+			return fmt.Errorf("unknown example locale %q, register it with RegisterExampleLocale", config.ExampleLocale)
+		}
+	}
 
 	// Validate auth config
 	if config.AuthConfig != nil && config.AuthConfig.Enabled {
@@ -145,11 +355,6 @@ func ValidateConfig(config *Config) error {
 		}
 	}
 
-	// Validate base URLs
-	if config.BaseURL == "" && len(config.BaseURLs) == 0 {
-		return fmt.Errorf("at least one base URL must be provided")
-	}
-
 	return nil
 }
 
@@ -188,6 +393,22 @@ func validateAuthConfig(auth *AuthConfig) error {
 		if len(auth.AdminWhitelistIPs) == 0 {
 			auth.AdminWhitelistIPs = []string{"127.0.0.1"}
 		}
+		if auth.TOTPEnabled && len(auth.TOTPUsers) == 0 {
+			return fmt.Errorf("TOTP requires at least one user in TOTPUsers")
+		}
+		for username, secret := range auth.TOTPUsers {
+			if secret == "" {
+				continue
+			}
+			if _, err := decodeTOTPSecret(secret); err != nil {
+				return fmt.Errorf("invalid TOTP secret for user %s", username)
+			}
+		}
+		switch strings.ToLower(auth.CookieSameSite) {
+		case "", "lax", "strict", "none":
+		default:
+			return fmt.Errorf("invalid cookie SameSite mode: %s (supported: lax, strict, none)", auth.CookieSameSite)
+		}
 	default:
 		return fmt.Errorf("unsupported auth type: %s (supported: basic, api_key, bearer, session)", auth.Type)
 	}
@@ -216,6 +437,18 @@ func validateAIConfig(ai *ai.AIConfig) error {
 	if !isSupported {
 		return fmt.Errorf("unsupported AI provider: %s (supported: %s)", ai.Provider, strings.Join(supportedProviders, ", "))
 	}
+	for i, fallback := range ai.Fallbacks {
+		isSupported = false
+		for _, provider := range supportedProviders {
+			if fallback.Provider == provider {
+				isSupported = true
+				break
+			}
+		}
+		if !isSupported {
+			return fmt.Errorf("unsupported AI provider of fallback %d: %s (supported: %s)", i+1, fallback.Provider, strings.Join(supportedProviders, ", "))
+		}
+	}
 
 	if ai.Features.MaxTokens < 1 {
 		return fmt.Errorf("max tokens must be greater than 0")
@@ -223,6 +456,9 @@ func validateAIConfig(ai *ai.AIConfig) error {
 	if ai.Features.Temperature < 0 || ai.Features.Temperature > 2 {
 		return fmt.Errorf("temperature must be between 0 and 2")
 	}
+	if _, err := parsePromptTemplate(ai.Features); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -269,6 +505,22 @@ func getEnvSlice(key string, defaultValue []string) []string {
 	return defaultValue
 }
 
+// getEnvMap parses "key:value,key2:value2"; a key without a value maps to ""
+func getEnvMap(key string) map[string]string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	result := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		name, entry, _ := strings.Cut(strings.TrimSpace(pair), ":")
+		if name != "" {
+			result[name] = strings.TrimSpace(entry)
+		}
+	}
+	return result
+}
+
 func hasUIConfig() bool {
 	uiKeys := []string{
 		"BYTEDOCS_UI_THEME",
@@ -279,6 +531,19 @@ func hasUIConfig() bool {
 		"BYTEDOCS_UI_FAVICON",
 		"BYTEDOCS_UI_TITLE",
 		"BYTEDOCS_UI_SUBTITLE",
+		"BYTEDOCS_UI_MAX_CONCURRENT_REQUESTS",
+		"BYTEDOCS_UI_MAX_QUEUED_REQUESTS",
+		"BYTEDOCS_UI_QUEUE_TIMEOUT",
+		"BYTEDOCS_UI_MAX_REQUEST_BYTES",
+		"BYTEDOCS_UI_MAX_RESPONSE_BYTES",
+		"BYTEDOCS_UI_MAX_DOWNLOAD_BYTES",
+		"BYTEDOCS_UI_FLAT_NAVIGATION",
+		"BYTEDOCS_UI_TEST_CA_FILE",
+		"BYTEDOCS_UI_TEST_INSECURE_SKIP_VERIFY",
+		"BYTEDOCS_UI_TEST_CLIENT_CERT_FILE",
+		"BYTEDOCS_UI_TEST_CLIENT_KEY_FILE",
+		"BYTEDOCS_UI_TEST_PROXY",
+		"BYTEDOCS_UI_TEST_REDIRECTS",
 	}
 
 	for _, key := range uiKeys {
@@ -287,4 +552,21 @@ func hasUIConfig() bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+
+func hasSecurityHeadersConfig() bool {
+	keys := []string{
+		"BYTEDOCS_SECURITY_HEADERS",
+		"BYTEDOCS_CSP",
+		"BYTEDOCS_CSP_SCRIPT_SOURCES",
+		"BYTEDOCS_CSP_CONNECT_SOURCES",
+		"BYTEDOCS_CSP_FRAME_ANCESTORS",
+	}
+
+	for _, key := range keys {
+		if os.Getenv(key) != "" {
+			return true
+		}
+	}
+	return false
+}
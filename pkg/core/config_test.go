@@ -0,0 +1,39 @@
+package core
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidateConfig_BaseURLsAndTrustedProxies(t *testing.T) {
+	config := &Config{Title: "API", Version: "1.0.0", DocsPath: "/docs"}
+	if err := ValidateConfig(config); err == nil || !strings.Contains(err.Error(), "base URL") {
+		t.Fatalf("expected a missing base URL rejected, got %v", err)
+	}
+	config.ServersFromRequest = true
+	if err := ValidateConfig(config); err != nil {
+		t.Fatalf("expected servers from requests accepted, got %v", err)
+	}
+
+	config.TrustedProxies = []string{"10.0.0.0/8", "127.0.0.1", "::1"}
+	if err := ValidateConfig(config); err != nil {
+		t.Fatalf("expected trusted proxies accepted, got %v", err)
+	}
+	config.TrustedProxies = []string{"proxy.internal"}
+	if err := ValidateConfig(config); err == nil || !strings.Contains(err.Error(), "trusted proxy") {
+		t.Fatalf("expected a hostname rejected as trusted proxy, got %v", err)
+	}
+
+	// The logout redirect ignores the prefix header of untrusted peers
+	t.Chdir("../..") // auth templates are loaded relative to the module root
+	docs := New(&Config{Title: "API", Version: "1.0.0", DocsPath: "/docs", BaseURL: "http://localhost",
+		AuthConfig: &AuthConfig{Enabled: true, Type: "session", Password: "secret", SessionExpire: 60, LogoutEnabled: true}})
+	req := httptest.NewRequest("GET", "/docs/logout", nil)
+	req.Header.Set("X-Forwarded-Prefix", "//evil.example.com")
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, req)
+	if location := rec.Header().Get("Location"); location != "/docs" {
+		t.Fatalf("expected logout back to /docs, got %d %q", rec.Code, location)
+	}
+}
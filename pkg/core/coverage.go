@@ -0,0 +1,200 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// CoverageReport measures how much of the API is documented beyond what
+// ByteDocs infers on its own
+type CoverageReport struct {
+	Score             float64        `json:"score"`             // percent of all checked items that are documented
+	Routes            CoverageMetric `json:"routes"`            // routes with a written summary
+	RequestExamples   CoverageMetric `json:"requestExamples"`   // request bodies with an example
+	ResponseSchemas   CoverageMetric `json:"responseSchemas"`   // response bodies with more than a bare object schema
+	FieldDescriptions CoverageMetric `json:"fieldDescriptions"` // struct fields with a description
+}
+
+// CoverageMetric counts documented items of one kind and lists the others
+type CoverageMetric struct {
+	Total      int      `json:"total"`
+	Documented int      `json:"documented"`
+	Percent    float64  `json:"percent"`
+	Missing    []string `json:"missing"`
+}
+
+// check counts an item, remembering label when it is not documented
+func (m *CoverageMetric) check(documented bool, label string) {
+	m.Total++
+	if documented {
+		m.Documented++
+		return
+	}
+	m.Missing = append(m.Missing, label)
+}
+
+// finish computes the percentage and sorts the missing items
+func (m *CoverageMetric) finish() {
+	m.Percent = coveragePercent(m.Documented, m.Total)
+	if m.Missing == nil {
+		m.Missing = make([]string, 0)
+	}
+	sort.Strings(m.Missing)
+}
+
+// coveragePercent is documented/total as a percentage, 100 when there is nothing to document
+func coveragePercent(documented, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(int(float64(documented)/float64(total)*1000+0.5)) / 10
+}
+
+// Coverage reports the documentation coverage of the generated documentation:
+// routes whose summary was inferred, request bodies without an example,
+// responses documented as bare objects and struct fields without a description
+func (a *APIDocs) Coverage() (*CoverageReport, error) {
+	if err := a.build(); err != nil {
+		return nil, err
+	}
+	documentation := a.GetDocumentation()
+
+	report := &CoverageReport{}
+	for _, section := range documentation.Endpoints {
+		for _, endpoint := range section.Endpoints {
+			operation := endpoint.Method + " " + endpoint.Path
+			summary := strings.TrimSpace(endpoint.Summary)
+			report.Routes.check(summary != "" && endpoint.Summary != endpoint.generatedSummary, operation)
+
+			if endpoint.RequestBody != nil {
+				report.RequestExamples.check(endpoint.RequestBody.Example != nil, operation)
+				checkFieldDescriptions(&report.FieldDescriptions, normalizeSchema(endpoint.RequestBody.Schema), operation+" request", "")
+			}
+
+			statuses := make([]string, 0, len(endpoint.Responses))
+			for status := range endpoint.Responses {
+				statuses = append(statuses, status)
+			}
+			sort.Strings(statuses)
+			for _, status := range statuses {
+				schema := normalizeSchema(endpoint.Responses[status].Schema)
+				if schema == nil {
+					continue
+				}
+				label := operation + " " + status
+				report.ResponseSchemas.check(!isBareObjectSchema(schema), label)
+				checkFieldDescriptions(&report.FieldDescriptions, schema, label+" response", "")
+			}
+		}
+	}
+
+	names := make([]string, 0, len(documentation.Schemas))
+	for name := range documentation.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for field, property := range documentation.Schemas[name].Properties {
+			report.FieldDescriptions.check(strings.TrimSpace(property.Description) != "", name+"."+field)
+		}
+	}
+
+	metrics := []*CoverageMetric{&report.Routes, &report.RequestExamples, &report.ResponseSchemas, &report.FieldDescriptions}
+	documented, total := 0, 0
+	for _, metric := range metrics {
+		metric.finish()
+		documented += metric.Documented
+		total += metric.Total
+	}
+	report.Score = coveragePercent(documented, total)
+	return report, nil
+}
+
+// normalizeSchema turns a schema built from typed Go values into plain JSON maps
+func normalizeSchema(schema interface{}) map[string]interface{} {
+	if schema == nil {
+		return nil
+	}
+	if schemaMap, ok := schema.(map[string]interface{}); ok {
+		return schemaMap
+	}
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return nil
+	}
+	return normalized
+}
+
+// isBareObjectSchema reports whether schema is an object that says nothing about its content
+func isBareObjectSchema(schema map[string]interface{}) bool {
+	if schema["$ref"] != nil || schema["items"] != nil {
+		return false
+	}
+	// map[string]string says what it holds, map[string]interface{} does not
+	if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok && !isBareObjectSchema(additional) {
+		return false
+	}
+	if schemaType, _ := schema["type"].(string); schemaType != "object" && schemaType != "" {
+		return false
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	return len(properties) == 0
+}
+
+// checkFieldDescriptions counts the properties of schema and its nested
+// objects and arrays; $refs are counted once, as component schemas
+func checkFieldDescriptions(metric *CoverageMetric, schema map[string]interface{}, location, prefix string) {
+	if schema == nil || schema["$ref"] != nil {
+		return
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		checkFieldDescriptions(metric, items, location, prefix)
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property, _ := properties[name].(map[string]interface{})
+		if property == nil {
+			continue
+		}
+		field := prefix + name
+		if property["$ref"] == nil {
+			description, _ := property["description"].(string)
+			metric.check(strings.TrimSpace(description) != "", location+": "+field)
+		}
+		checkFieldDescriptions(metric, property, location, field+".")
+	}
+}
+
+// serveCoverage reports the documentation coverage as JSON
+func (a *APIDocs) serveCoverage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report, err := a.Coverage()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to compute coverage: %v", err), http.StatusInternalServerError)
+		return
+	}
+	body, err := json.Marshal(report)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode coverage report: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	a.writeCacheable(w, r, append(body, '\n'))
+}
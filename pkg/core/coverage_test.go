@@ -0,0 +1,46 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCoverage_ReportsUndocumentedRoutesAndFields(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs"})
+	docs.AddRoute("POST", "/users", nil,
+		WithSummary("Create a user"),
+		WithRequest(struct {
+			Name  string `json:"name" description:"Full name" example:"Ada"`
+			Email string `json:"email"`
+		}{}),
+		WithResponse(http.StatusCreated, map[string]interface{}{}))
+	docs.AddRoute("GET", "/users", nil)
+
+	report, err := docs.Coverage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Routes.Documented != 1 || strings.Join(report.Routes.Missing, ",") != "GET /users" {
+		t.Fatalf("expected the inferred summary as missing, got %+v", report.Routes)
+	}
+	if report.RequestExamples.Percent != 100 {
+		t.Fatalf("expected the request example to count, got %+v", report.RequestExamples)
+	}
+	if strings.Join(report.ResponseSchemas.Missing, ",") != "POST /users 201" {
+		t.Fatalf("expected the bare object response as missing, got %+v", report.ResponseSchemas)
+	}
+	if strings.Join(report.FieldDescriptions.Missing, ",") != "POST /users request: email" {
+		t.Fatalf("expected the undescribed field as missing, got %+v", report.FieldDescriptions)
+	}
+	if report.Score <= 0 || report.Score >= 100 {
+		t.Fatalf("expected a partial score, got %v", report.Score)
+	}
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/coverage", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"fieldDescriptions":{`) {
+		t.Fatalf("expected the coverage report, got %d %s", rec.Code, rec.Body.String())
+	}
+}
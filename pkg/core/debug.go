@@ -0,0 +1,126 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// AnalysisTrace is how the parser documented one route, recorded when
+// Config.Debug is set and served at <docs path>/debug/analysis
+type AnalysisTrace struct {
+	Method   string   `json:"method"`
+	Path     string   `json:"path"`
+	Handler  string   `json:"handler,omitempty"` // runtime name of the handler function
+	Source   string   `json:"source,omitempty"`  // file:line of the handler, relative to the working directory
+	Steps    []string `json:"steps"`             // matched binding and response calls, resolved types and fallbacks
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// AnalysisTraces returns the analysis trace of every registered route.
+// Routes analyzed before debugging was enabled, e.g. from the analyzer
+// cache, and routes added with AddRoute have no steps.
+func (a *APIDocs) AnalysisTraces() []AnalysisTrace {
+	a.mu.Lock()
+	routes := append([]RouteInfo{}, a.currentRoutes()...)
+	a.mu.Unlock()
+
+	traces := make([]AnalysisTrace, 0, len(routes))
+	for _, route := range routes {
+		trace := AnalysisTrace{
+			Method:   strings.ToUpper(route.Method),
+			Path:     convertPathToOpenAPI(route.Path),
+			Steps:    route.Trace,
+			Warnings: route.Warnings,
+		}
+		if trace.Steps == nil {
+			trace.Steps = []string{}
+		}
+		trace.Handler, trace.Source = handlerLocation(route.Handler)
+		traces = append(traces, trace)
+	}
+	return traces
+}
+
+// handlerLocation returns the runtime name and source position of a handler func
+func handlerLocation(handler interface{}) (name, source string) {
+	value := reflect.ValueOf(handler)
+	if !value.IsValid() || value.Kind() != reflect.Func || value.IsNil() {
+		return "", ""
+	}
+	fn := runtime.FuncForPC(value.Pointer())
+	if fn == nil {
+		return "", ""
+	}
+	file, line := fn.FileLine(fn.Entry())
+	if file == "" {
+		return fn.Name(), ""
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+	return fn.Name(), fmt.Sprintf("%s:%d", file, line)
+}
+
+// serveDebugAnalysis handles /debug/analysis: the traces as JSON or, with
+// ?format=text, as a readable dump. ?path= keeps routes whose path contains it.
+func (a *APIDocs) serveDebugAnalysis(w http.ResponseWriter, r *http.Request) {
+	if !a.config.Debug {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	traces := a.AnalysisTraces()
+	if filter := r.URL.Query().Get("path"); filter != "" {
+		kept := traces[:0]
+		for _, trace := range traces {
+			if strings.Contains(trace.Path, filter) {
+				kept = append(kept, trace)
+			}
+		}
+		traces = kept
+	}
+	w.Header().Set("Cache-Control", "no-store")
+
+	if r.URL.Query().Get("format") == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, trace := range traces {
+			fmt.Fprintf(w, "%s %s", trace.Method, trace.Path)
+			if trace.Handler != "" {
+				fmt.Fprintf(w, "  %s", trace.Handler)
+			}
+			if trace.Source != "" {
+				fmt.Fprintf(w, "  (%s)", trace.Source)
+			}
+			fmt.Fprintln(w)
+			for _, step := range trace.Steps {
+				fmt.Fprintf(w, "    - %s\n", step)
+			}
+			for _, warning := range trace.Warnings {
+				fmt.Fprintf(w, "    ! %s\n", warning)
+			}
+			fmt.Fprintln(w)
+		}
+		return
+	}
+
+	body, err := json.MarshalIndent(traces, "", "  ")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode traces: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(body, '\n'))
+}
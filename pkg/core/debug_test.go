@@ -0,0 +1,39 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugAnalysis_ServesRouteTraces(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	docs := New(&Config{DocsPath: "/docs", Debug: true})
+	docs.AddRouteInfo(RouteInfo{Method: "post", Path: "/users/:id", Handler: handler, Trace: []string{"c.ShouldBindJSON: request body CreateUserRequest as application/json"}})
+	docs.AddRouteInfo(RouteInfo{Method: "GET", Path: "/health", Warnings: []string{"handler is an anonymous function in main"}})
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/debug/analysis?path=/users", nil))
+	var traces []AnalysisTrace
+	if err := json.Unmarshal(rec.Body.Bytes(), &traces); err != nil {
+		t.Fatal(err)
+	}
+	if len(traces) != 1 || traces[0].Method != "POST" || traces[0].Path != "/users/{id}" || len(traces[0].Steps) != 1 ||
+		!strings.Contains(traces[0].Handler, "TestDebugAnalysis_ServesRouteTraces") || !strings.HasPrefix(traces[0].Source, "debug_test.go:") {
+		t.Fatalf("expected the filtered trace with its handler, got %+v", traces)
+	}
+
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/debug/analysis?format=text", nil))
+	if body := rec.Body.String(); !strings.Contains(body, "    - c.ShouldBindJSON: request body") || !strings.Contains(body, "GET /health\n    ! handler is an anonymous function") {
+		t.Fatalf("expected a readable dump, got\n%s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	New(&Config{DocsPath: "/docs"}).ServeHTTP(rec, httptest.NewRequest("GET", "/docs/debug/analysis", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected traces to be hidden unless debugging, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// validateDefaultResponses rejects Config.DefaultResponses keyed by something
+// other than an HTTP status code
+func validateDefaultResponses(responses map[int]Response) error {
+	for status := range responses {
+		if status < 100 || status > 599 {
+			return fmt.Errorf("default response status %d is not an HTTP status code", status)
+		}
+	}
+	return nil
+}
+
+// applyDefaultResponses adds the Config.DefaultResponses the endpoint does not
+// document itself, from its handler, a route option or AddGroupResponse
+func (a *APIDocs) applyDefaultResponses(endpoint *Endpoint) {
+	if len(a.config.DefaultResponses) == 0 {
+		return
+	}
+	statuses := make([]int, 0, len(a.config.DefaultResponses))
+	for status := range a.config.DefaultResponses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+
+	copied := false
+	for _, status := range statuses {
+		code := strconv.Itoa(status)
+		if _, exists := endpoint.Responses[code]; exists {
+			continue
+		}
+		// The responses map may be shared with the registered route; copy before adding
+		if !copied {
+			responses := make(map[string]Response, len(endpoint.Responses)+len(statuses))
+			for existing, response := range endpoint.Responses {
+				responses[existing] = response
+			}
+			endpoint.Responses = responses
+			copied = true
+		}
+		response := a.config.DefaultResponses[status]
+		if response.Description == "" {
+			response.Description = http.StatusText(status)
+		}
+		if response.ContentType == "" && response.Schema != nil {
+			response.ContentType = "application/json"
+		}
+		endpoint.Responses[code] = response
+	}
+}
@@ -0,0 +1,48 @@
+package core
+
+import "testing"
+
+func TestDefaultResponses_MergedUnlessDocumented(t *testing.T) {
+	type testError struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	errorSchema, errorExample := SchemaFromValue(testError{})
+	docs := New(&Config{DocsPath: "/docs", DefaultResponses: map[int]Response{
+		401: {Schema: errorSchema, Example: errorExample},
+		429: {Description: "Rate limit exceeded", Schema: errorSchema, Example: errorExample},
+		500: {Schema: errorSchema, Example: errorExample},
+	}})
+	docs.AddRouteInfo(RouteInfo{Method: "GET", Path: "/users", Responses: map[string]Response{
+		"200": {Description: "OK"},
+		"500": {Description: "Database unavailable"},
+	}})
+	docs.AddRouteInfo(RouteInfo{Method: "GET", Path: "/health", Responses: map[string]Response{"200": {Description: "OK"}}})
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	endpoints := map[string]Endpoint{}
+	for _, section := range docs.GetDocumentation().Endpoints {
+		for _, endpoint := range section.Endpoints {
+			endpoints[endpoint.Path] = endpoint
+		}
+	}
+	users := endpoints["/users"].Responses
+	if users["401"].Description != "Unauthorized" || users["401"].Schema == nil || users["401"].ContentType != "application/json" {
+		t.Fatalf("expected the default 401 with the error schema, got %+v", users["401"])
+	}
+	if users["429"].Description != "Rate limit exceeded" {
+		t.Fatalf("expected the configured description, got %+v", users["429"])
+	}
+	if users["500"].Description != "Database unavailable" || users["500"].Schema != nil {
+		t.Fatalf("expected the documented 500 to take precedence, got %+v", users["500"])
+	}
+	if len(endpoints["/health"].Responses) != 4 {
+		t.Fatalf("expected every endpoint to get the defaults, got %+v", endpoints["/health"].Responses)
+	}
+
+	if err := ValidateConfig(&Config{Title: "API", Version: "1.0.0", DocsPath: "/docs", DefaultResponses: map[int]Response{42: {}}}); err == nil {
+		t.Fatal("expected a default response without an HTTP status to be rejected")
+	}
+}
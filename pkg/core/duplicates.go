@@ -0,0 +1,206 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// mergeDuplicateRoutes merges routes registered more than once for the same
+// method and path, e.g. by two router wrappers or a wrapper and AddRoute.
+// The most detailed registration is documented, with the fields it lacks
+// filled from the others; fields the registrations disagree on are reported
+// as a warning of the merged route.
+func mergeDuplicateRoutes(routes []RouteInfo) []RouteInfo {
+	indexByKey := make(map[string]int, len(routes))
+	groups := make([][]RouteInfo, 0, len(routes))
+	for _, route := range routes {
+		key := overrideKey(route.Method, route.Path)
+		if index, ok := indexByKey[key]; ok {
+			groups[index] = append(groups[index], route)
+			continue
+		}
+		indexByKey[key] = len(groups)
+		groups = append(groups, []RouteInfo{route})
+	}
+	if len(groups) == len(routes) {
+		return routes
+	}
+
+	merged := make([]RouteInfo, 0, len(groups))
+	for _, group := range groups {
+		if len(group) == 1 {
+			merged = append(merged, group[0])
+			continue
+		}
+		merged = append(merged, mergeRouteGroup(group))
+	}
+	return merged
+}
+
+// mergeRouteGroup merges the registrations of one method and path
+func mergeRouteGroup(group []RouteInfo) RouteInfo {
+	richest := 0
+	for i, route := range group {
+		if routeDetail(route) > routeDetail(group[richest]) {
+			richest = i
+		}
+	}
+
+	route := group[richest]
+	// The slices and maps may be shared with the registered route; copy before filling
+	route.Parameters = append([]Parameter{}, route.Parameters...)
+	if route.Responses != nil {
+		responses := make(map[string]Response, len(route.Responses))
+		for status, response := range route.Responses {
+			responses[status] = response
+		}
+		route.Responses = responses
+	}
+
+	conflicts := make(map[string]bool)
+	for i, other := range group {
+		if i == richest {
+			continue
+		}
+		for _, conflict := range routeConflicts(route, other) {
+			conflicts[conflict] = true
+		}
+		fillRoute(&route, other)
+	}
+
+	if len(conflicts) > 0 {
+		fields := make([]string, 0, len(conflicts))
+		for field := range conflicts {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		route.Warnings = append(append([]string{}, route.Warnings...), fmt.Sprintf(
+			"registered %d times with conflicting %s; documented from the most detailed registration",
+			len(group), strings.Join(fields, ", ")))
+	}
+	return route
+}
+
+// routeDetail scores how much a registration documents
+func routeDetail(route RouteInfo) int {
+	detail := len(route.Parameters) + len(route.Responses) + len(route.Presets) + len(route.Accepts) + len(route.ResponseDocs) + len(route.Extensions)
+	for _, set := range []bool{
+		route.Summary != "", route.Description != "", route.RequestBody != nil, route.Section != "",
+		route.ExternalDocs != nil, route.SLA != 0, route.Pagination != "", route.Deprecated,
+	} {
+		if set {
+			detail++
+		}
+	}
+	// Routes the parser could not analyze document less than they seem to
+	return detail - len(route.Warnings)
+}
+
+// fillRoute copies the fields route lacks from other
+func fillRoute(route *RouteInfo, other RouteInfo) {
+	if route.Handler == nil {
+		route.Handler = other.Handler
+	}
+	if len(route.Middlewares) == 0 {
+		route.Middlewares = other.Middlewares
+	}
+	if route.Summary == "" {
+		route.Summary = other.Summary
+	}
+	if route.Description == "" {
+		route.Description = other.Description
+	}
+	if route.RequestBody == nil {
+		route.RequestBody = other.RequestBody
+	}
+	if route.Section == "" {
+		route.Section = other.Section
+	}
+	if route.ExternalDocs == nil {
+		route.ExternalDocs = other.ExternalDocs
+	}
+	if len(route.Presets) == 0 {
+		route.Presets = other.Presets
+	}
+	if route.SLA == 0 {
+		route.SLA = other.SLA
+	}
+	if route.Pagination == "" {
+		route.Pagination = other.Pagination
+	}
+	if len(route.Accepts) == 0 {
+		route.Accepts = other.Accepts
+	}
+	if len(route.ResponseDocs) == 0 {
+		route.ResponseDocs = other.ResponseDocs
+	}
+	if len(route.Extensions) == 0 {
+		route.Extensions = other.Extensions
+	}
+	route.Deprecated = route.Deprecated || other.Deprecated
+
+	documented := make(map[string]bool, len(route.Parameters))
+	for _, param := range route.Parameters {
+		documented[param.Name+":"+param.In] = true
+	}
+	for _, param := range other.Parameters {
+		if !documented[param.Name+":"+param.In] {
+			route.Parameters = append(route.Parameters, param)
+			documented[param.Name+":"+param.In] = true
+		}
+	}
+
+	for status, response := range other.Responses {
+		if route.Responses == nil {
+			route.Responses = make(map[string]Response, len(other.Responses))
+		}
+		if _, ok := route.Responses[status]; !ok {
+			route.Responses[status] = response
+		}
+	}
+}
+
+// routeConflicts names the fields both registrations set to different values
+func routeConflicts(route, other RouteInfo) []string {
+	var conflicts []string
+	if route.Handler != nil && other.Handler != nil && handlerIdentity(route.Handler) != handlerIdentity(other.Handler) {
+		conflicts = append(conflicts, "handlers")
+	}
+	if route.Summary != "" && other.Summary != "" && route.Summary != other.Summary {
+		conflicts = append(conflicts, "summaries")
+	}
+	if route.Description != "" && other.Description != "" && route.Description != other.Description {
+		conflicts = append(conflicts, "descriptions")
+	}
+	if route.RequestBody != nil && other.RequestBody != nil &&
+		(route.RequestBody.ContentType != other.RequestBody.ContentType || !reflect.DeepEqual(route.RequestBody.Schema, other.RequestBody.Schema)) {
+		conflicts = append(conflicts, "request bodies")
+	}
+
+	params := make(map[string]Parameter, len(route.Parameters))
+	for _, param := range route.Parameters {
+		params[param.Name+":"+param.In] = param
+	}
+	for _, param := range other.Parameters {
+		if existing, ok := params[param.Name+":"+param.In]; ok && (existing.Type != param.Type || existing.Required != param.Required) {
+			conflicts = append(conflicts, fmt.Sprintf("%s parameter %q", param.In, param.Name))
+		}
+	}
+
+	for status, response := range other.Responses {
+		if existing, ok := route.Responses[status]; ok && existing.Schema != nil && response.Schema != nil && !reflect.DeepEqual(existing.Schema, response.Schema) {
+			conflicts = append(conflicts, status+" responses")
+		}
+	}
+	return conflicts
+}
+
+// handlerIdentity tells handlers apart: functions by name, others by type
+func handlerIdentity(handler interface{}) string {
+	if name, _ := handlerLocation(handler); name != "" {
+		return name
+	}
+	return fmt.Sprintf("%T", handler)
+}
@@ -0,0 +1,38 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDuplicateRoutes_MergedWithConflictsReported(t *testing.T) {
+	docs := New(&Config{DocsPath: "/docs"})
+	docs.AddRouteInfo(RouteInfo{Method: "GET", Path: "/users/:id", Summary: "Get user",
+		Parameters: []Parameter{{Name: "id", In: "path", Type: "integer", Required: true}},
+		Responses:  map[string]Response{"200": {Description: "Success", Schema: map[string]interface{}{"type": "object"}}}})
+	docs.AddRoute("GET", "/users/{id}", nil, WithDescription("Returns a user"), WithParam("fields", "query", "string", false, "Fields to include"))
+	docs.AddRoute("POST", "/users", func() {}, WithSummary("Create"))
+	docs.AddRoute("POST", "/users", func() {}, WithSummary("Create user"))
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	var endpoints []Endpoint
+	for _, section := range docs.GetDocumentation().Endpoints {
+		endpoints = append(endpoints, section.Endpoints...)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("expected duplicates to be merged into 2 endpoints, got %+v", endpoints)
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.Method == "GET" && (endpoint.Summary != "Get user" || endpoint.Description != "Returns a user" || len(endpoint.Parameters) != 2) {
+			t.Fatalf("expected the registrations' metadata to be merged, got %+v", endpoint)
+		}
+	}
+
+	warnings := docs.GetDocumentation().Warnings
+	if len(warnings) != 1 || warnings[0].Method != "POST" || warnings[0].Path != "/users" ||
+		!strings.Contains(warnings[0].Message, "registered 2 times with conflicting handlers, summaries") {
+		t.Fatalf("expected only the conflicting POST /users to be reported, got %+v", warnings)
+	}
+}
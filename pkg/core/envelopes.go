@@ -0,0 +1,75 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Schema keys marking a response envelope and the type of its payload
+const (
+	envelopeKey     = "x-envelope"
+	envelopeTypeKey = "x-envelope-type"
+)
+
+// ParseResponseEnvelopes parses "APIResponse.Data" entries into a map from
+// envelope struct to the Go name of its payload field. A package qualifier,
+// as in "api.Response.Data", is ignored.
+func ParseResponseEnvelopes(values []string) (map[string]string, error) {
+	envelopes := make(map[string]string, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		index := strings.LastIndex(value, ".")
+		if index <= 0 || index == len(value)-1 {
+			return nil, fmt.Errorf("invalid response envelope %q, use \"Type.Field\"", value)
+		}
+		envelopes[helperName(value[:index])] = value[index+1:]
+	}
+	return envelopes, nil
+}
+
+// MarkEnvelope flags schema as an envelope whose payload is the property with
+// the given JSON name, and records the payload type when known, e.g. "User"
+func MarkEnvelope(schema map[string]interface{}, property, payloadType string) {
+	if schema == nil || property == "" {
+		return
+	}
+	schema[envelopeKey] = property
+	if payloadType != "" {
+		schema[envelopeTypeKey] = payloadType
+	}
+}
+
+// unwrapEnvelopes documents the payload of enveloped responses in place of
+// the envelope, for Config.UnwrapEnvelopes
+func unwrapEnvelopes(endpoint *Endpoint) {
+	var responses map[string]Response
+	for status, response := range endpoint.Responses {
+		schema, ok := response.Schema.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		property, _ := schema[envelopeKey].(string)
+		properties, _ := schema["properties"].(map[string]interface{})
+		payload, ok := properties[property]
+		if property == "" || !ok {
+			continue
+		}
+		if responses == nil {
+			responses = make(map[string]Response, len(endpoint.Responses))
+			for code, existing := range endpoint.Responses {
+				responses[code] = existing
+			}
+		}
+		response.Schema = payload
+		if example, ok := response.Example.(map[string]interface{}); ok {
+			response.Example = example[property]
+		}
+		responses[status] = response
+	}
+	if responses != nil {
+		endpoint.Responses = responses
+	}
+}
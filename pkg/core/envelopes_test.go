@@ -0,0 +1,49 @@
+package core
+
+import "testing"
+
+func TestResponseEnvelopes_Unwrap(t *testing.T) {
+	if _, err := ParseResponseEnvelopes([]string{"APIResponse"}); err == nil {
+		t.Fatal("expected an envelope without a payload field to be rejected")
+	}
+	envelopes, err := ParseResponseEnvelopes([]string{"api.APIResponse.Data"})
+	if err != nil || envelopes["APIResponse"] != "Data" {
+		t.Fatalf("expected the envelope without its package qualifier, got %+v, %v", envelopes, err)
+	}
+
+	envelope := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"success": map[string]interface{}{"type": "boolean"},
+			"data":    map[string]interface{}{"type": "object", "properties": map[string]interface{}{"id": map[string]interface{}{"type": "integer"}}},
+		},
+	}
+	MarkEnvelope(envelope, "data", "User")
+	route := RouteInfo{Method: "GET", Path: "/users/{id}", Responses: map[string]Response{
+		"200": {Description: "OK", ContentType: "application/json", Schema: envelope, Example: map[string]interface{}{"success": true, "data": map[string]interface{}{"id": 1}}},
+	}}
+
+	docs := New(&Config{DocsPath: "/docs"})
+	docs.AddRouteInfo(route)
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	kept := docs.GetDocumentation().Endpoints[0].Endpoints[0].Responses["200"]
+	if schema, _ := kept.Schema.(map[string]interface{}); schema["x-envelope"] != "data" || schema["x-envelope-type"] != "User" {
+		t.Fatalf("expected the envelope documented as is, got %+v", kept.Schema)
+	}
+
+	docs = New(&Config{DocsPath: "/docs", UnwrapEnvelopes: true})
+	docs.AddRouteInfo(route)
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	unwrapped := docs.GetDocumentation().Endpoints[0].Endpoints[0].Responses["200"]
+	schema, _ := unwrapped.Schema.(map[string]interface{})
+	if properties, _ := schema["properties"].(map[string]interface{}); properties["id"] == nil {
+		t.Fatalf("expected the payload schema, got %+v", unwrapped.Schema)
+	}
+	if example, _ := unwrapped.Example.(map[string]interface{}); example["id"] != 1 {
+		t.Fatalf("expected the payload example, got %+v", unwrapped.Example)
+	}
+}
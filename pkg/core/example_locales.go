@@ -0,0 +1,252 @@
+package core
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ExampleLocale is the market data FakeExamples draws from: names, places,
+// phone numbers, prices and dates as the API's users write them
+type ExampleLocale struct {
+	FirstNames      []string
+	LastNames       []string
+	FamilyNameFirst bool // full names as "<last> <first>", e.g. in Japan
+
+	Cities        []string
+	Streets       []string
+	AddressFormat string // "{number}" and "{street}" are replaced, e.g. "{street} {number}"
+	PostalFormat  string // '#' becomes a digit and '?' an uppercase letter, e.g. "#####"
+	Country       string
+	CountryCode   string // ISO 3166-1 alpha-2, e.g. "DE"
+	PhoneFormat   string // '#' becomes a digit, e.g. "+49 30 ########"
+
+	Currency           string  // ISO 4217 code, e.g. "EUR"
+	CurrencyDecimals   int     // digits after the decimal separator, 0 for IDR and JPY
+	PriceFormat        string  // formatted price strings; "{amount}" is replaced, e.g. "{amount} €"
+	PriceMax           float64 // upper bound of example prices
+	DecimalSeparator   string
+	ThousandsSeparator string
+
+	DateFormat string // Go layout of date fields without a schema format, e.g. "02.01.2006"
+}
+
+// DefaultExampleLocale is used when Config.ExampleLocale is empty or unknown
+const DefaultExampleLocale = "en-US"
+
+// usExampleLocale fills the fields a registered locale leaves empty
+var usExampleLocale = ExampleLocale{
+	FirstNames:         []string{"Alice", "Brandon", "Chloe", "David", "Emily", "Frank", "Grace", "Henry", "Isabella", "Jack", "Kayla", "Logan", "Madison", "Nathan", "Olivia", "Ryan"},
+	LastNames:          []string{"Anderson", "Brown", "Clark", "Davis", "Evans", "Garcia", "Harris", "Johnson", "Lee", "Miller", "Nguyen", "Parker", "Robinson", "Smith", "Taylor", "Walker"},
+	Cities:             []string{"Austin", "Boston", "Chicago", "Denver", "Miami", "Portland", "San Diego", "Seattle"},
+	Streets:            []string{"Maple Street", "Oak Avenue", "Harbor Road", "Elm Drive", "Park Lane", "Washington Boulevard"},
+	AddressFormat:      "{number} {street}",
+	PostalFormat:       "#####",
+	Country:            "United States",
+	CountryCode:        "US",
+	PhoneFormat:        "+1-555-01##",
+	Currency:           "USD",
+	CurrencyDecimals:   2,
+	PriceFormat:        "${amount}",
+	PriceMax:           500,
+	DecimalSeparator:   ".",
+	ThousandsSeparator: ",",
+	DateFormat:         "01/02/2006",
+}
+
+var (
+	exampleLocales = map[string]*ExampleLocale{
+		"en-US": &usExampleLocale,
+		"en-GB": {
+			FirstNames:         []string{"Amelia", "Ben", "Charlotte", "Daniel", "Eleanor", "Freddie", "Georgia", "Harry", "Isla", "James", "Lily", "Oliver", "Poppy", "Thomas"},
+			LastNames:          []string{"Baker", "Cooper", "Davies", "Evans", "Hughes", "Jones", "Patel", "Roberts", "Taylor", "Thomas", "Williams", "Wilson", "Wright"},
+			Cities:             []string{"Birmingham", "Bristol", "Edinburgh", "Leeds", "London", "Manchester", "Cardiff"},
+			Streets:            []string{"High Street", "Station Road", "Church Lane", "Victoria Road", "Mill Lane", "Kings Road"},
+			AddressFormat:      "{number} {street}",
+			PostalFormat:       "EC1A #??",
+			Country:            "United Kingdom",
+			CountryCode:        "GB",
+			PhoneFormat:        "+44 20 7946 0###",
+			Currency:           "GBP",
+			CurrencyDecimals:   2,
+			PriceFormat:        "£{amount}",
+			PriceMax:           500,
+			DecimalSeparator:   ".",
+			ThousandsSeparator: ",",
+			DateFormat:         "02/01/2006",
+		},
+		"de-DE": {
+			FirstNames:         []string{"Anna", "Ben", "Clara", "Elias", "Emma", "Felix", "Hannah", "Jonas", "Lea", "Lukas", "Mia", "Paul", "Sophie", "Tim"},
+			LastNames:          []string{"Becker", "Fischer", "Hoffmann", "Koch", "Meyer", "Müller", "Richter", "Schäfer", "Schmidt", "Schneider", "Wagner", "Weber"},
+			Cities:             []string{"Berlin", "Dresden", "Frankfurt am Main", "Hamburg", "Köln", "Leipzig", "München", "Stuttgart"},
+			Streets:            []string{"Hauptstraße", "Bahnhofstraße", "Gartenstraße", "Schillerstraße", "Lindenweg", "Goethestraße"},
+			AddressFormat:      "{street} {number}",
+			PostalFormat:       "#####",
+			Country:            "Deutschland",
+			CountryCode:        "DE",
+			PhoneFormat:        "+49 30 ########",
+			Currency:           "EUR",
+			CurrencyDecimals:   2,
+			PriceFormat:        "{amount} €",
+			PriceMax:           500,
+			DecimalSeparator:   ",",
+			ThousandsSeparator: ".",
+			DateFormat:         "02.01.2006",
+		},
+		"fr-FR": {
+			FirstNames:         []string{"Camille", "Chloé", "Gabriel", "Hugo", "Inès", "Jules", "Léa", "Louis", "Manon", "Nathan", "Sarah", "Théo"},
+			LastNames:          []string{"Bernard", "Dubois", "Durand", "Lefebvre", "Leroy", "Martin", "Moreau", "Petit", "Richard", "Robert", "Simon", "Thomas"},
+			Cities:             []string{"Bordeaux", "Lille", "Lyon", "Marseille", "Nantes", "Nice", "Paris", "Toulouse"},
+			Streets:            []string{"rue de la Paix", "avenue Victor Hugo", "rue de la République", "boulevard Voltaire", "rue Pasteur", "place de la Gare"},
+			AddressFormat:      "{number} {street}",
+			PostalFormat:       "75###",
+			Country:            "France",
+			CountryCode:        "FR",
+			PhoneFormat:        "+33 1 ## ## ## ##",
+			Currency:           "EUR",
+			CurrencyDecimals:   2,
+			PriceFormat:        "{amount} €",
+			PriceMax:           500,
+			DecimalSeparator:   ",",
+			ThousandsSeparator: " ",
+			DateFormat:         "02/01/2006",
+		},
+		"id-ID": {
+			FirstNames:         []string{"Adi", "Budi", "Citra", "Dewi", "Eko", "Fitri", "Gilang", "Hendra", "Indah", "Joko", "Kartika", "Lestari", "Putri", "Rizky", "Sari", "Wahyu"},
+			LastNames:          []string{"Hidayat", "Kusuma", "Lubis", "Nasution", "Pratama", "Purnomo", "Santoso", "Saputra", "Setiawan", "Siregar", "Sulistyo", "Wijaya"},
+			Cities:             []string{"Bandung", "Denpasar", "Jakarta", "Makassar", "Medan", "Semarang", "Surabaya", "Yogyakarta"},
+			Streets:            []string{"Jl. Sudirman", "Jl. Thamrin", "Jl. Gatot Subroto", "Jl. Diponegoro", "Jl. Merdeka", "Jl. Ahmad Yani"},
+			AddressFormat:      "{street} No. {number}",
+			PostalFormat:       "1####",
+			Country:            "Indonesia",
+			CountryCode:        "ID",
+			PhoneFormat:        "+62 812-####-####",
+			Currency:           "IDR",
+			CurrencyDecimals:   0,
+			PriceFormat:        "Rp{amount}",
+			PriceMax:           5000000,
+			DecimalSeparator:   ",",
+			ThousandsSeparator: ".",
+			DateFormat:         "02/01/2006",
+		},
+		"ja-JP": {
+			FirstNames:         []string{"Haruto", "Hina", "Kaito", "Mei", "Ren", "Sakura", "Sota", "Yui", "Yuto", "Aoi"},
+			LastNames:          []string{"Sato", "Suzuki", "Takahashi", "Tanaka", "Watanabe", "Ito", "Yamamoto", "Nakamura", "Kobayashi", "Kato"},
+			FamilyNameFirst:    true,
+			Cities:             []string{"Fukuoka", "Kobe", "Kyoto", "Nagoya", "Osaka", "Sapporo", "Tokyo", "Yokohama"},
+			Streets:            []string{"Ginza", "Shibuya", "Umeda", "Sakae", "Tenjin", "Shinjuku"},
+			AddressFormat:      "{street} {number}-chōme",
+			PostalFormat:       "###-####",
+			Country:            "Japan",
+			CountryCode:        "JP",
+			PhoneFormat:        "+81 3-####-####",
+			Currency:           "JPY",
+			CurrencyDecimals:   0,
+			PriceFormat:        "¥{amount}",
+			PriceMax:           50000,
+			DecimalSeparator:   ".",
+			ThousandsSeparator: ",",
+			DateFormat:         "2006/01/02",
+		},
+		"pt-BR": {
+			FirstNames:         []string{"Ana", "Bruno", "Camila", "Diego", "Fernanda", "Gabriel", "Juliana", "Lucas", "Mariana", "Pedro", "Rafael", "Beatriz"},
+			LastNames:          []string{"Almeida", "Alves", "Carvalho", "Costa", "Ferreira", "Gomes", "Lima", "Oliveira", "Pereira", "Ribeiro", "Santos", "Silva"},
+			Cities:             []string{"Belo Horizonte", "Brasília", "Curitiba", "Fortaleza", "Porto Alegre", "Recife", "Rio de Janeiro", "São Paulo"},
+			Streets:            []string{"Rua das Flores", "Avenida Paulista", "Rua XV de Novembro", "Avenida Atlântica", "Rua da Consolação", "Rua Augusta"},
+			AddressFormat:      "{street}, {number}",
+			PostalFormat:       "#####-###",
+			Country:            "Brasil",
+			CountryCode:        "BR",
+			PhoneFormat:        "+55 11 9####-####",
+			Currency:           "BRL",
+			CurrencyDecimals:   2,
+			PriceFormat:        "R$ {amount}",
+			PriceMax:           2500,
+			DecimalSeparator:   ",",
+			ThousandsSeparator: ".",
+			DateFormat:         "02/01/2006",
+		},
+	}
+	exampleLocalesMutex sync.RWMutex
+)
+
+// RegisterExampleLocale makes Config.ExampleLocale accept tag, e.g. "nl-NL",
+// or replaces a built-in locale. A nil locale removes the tag.
+func RegisterExampleLocale(tag string, locale *ExampleLocale) {
+	exampleLocalesMutex.Lock()
+	defer exampleLocalesMutex.Unlock()
+	if locale == nil {
+		delete(exampleLocales, tag)
+		return
+	}
+	exampleLocales[tag] = locale
+}
+
+// lookupExampleLocale returns the locale registered for tag; the match is
+// case-insensitive, "de_DE" matches "de-DE" and a bare language such as
+// "de" matches the first tag of that language
+func lookupExampleLocale(tag string) (*ExampleLocale, bool) {
+	exampleLocalesMutex.RLock()
+	defer exampleLocalesMutex.RUnlock()
+
+	tag = strings.ReplaceAll(tag, "_", "-")
+	tags := make([]string, 0, len(exampleLocales))
+	for registered := range exampleLocales {
+		tags = append(tags, registered)
+	}
+	sort.Strings(tags)
+	for _, registered := range tags {
+		if strings.EqualFold(registered, tag) {
+			return exampleLocales[registered], true
+		}
+	}
+	for _, registered := range tags {
+		if language, _, _ := strings.Cut(registered, "-"); !strings.Contains(tag, "-") && strings.EqualFold(language, tag) {
+			return exampleLocales[registered], true
+		}
+	}
+	return nil, false
+}
+
+// exampleLocale returns the locale for tag, or the default one, with the
+// groups of fields it leaves empty taken from the US locale
+func exampleLocale(tag string) ExampleLocale {
+	found, ok := lookupExampleLocale(tag)
+	if !ok {
+		found, ok = lookupExampleLocale(DefaultExampleLocale)
+	}
+	if !ok {
+		return usExampleLocale
+	}
+
+	locale := *found
+	if len(locale.FirstNames) == 0 || len(locale.LastNames) == 0 {
+		locale.FirstNames, locale.LastNames, locale.FamilyNameFirst = usExampleLocale.FirstNames, usExampleLocale.LastNames, false
+	}
+	if len(locale.Cities) == 0 {
+		locale.Cities = usExampleLocale.Cities
+	}
+	if len(locale.Streets) == 0 || locale.AddressFormat == "" {
+		locale.Streets, locale.AddressFormat = usExampleLocale.Streets, usExampleLocale.AddressFormat
+	}
+	if locale.PostalFormat == "" {
+		locale.PostalFormat = usExampleLocale.PostalFormat
+	}
+	if locale.Country == "" || locale.CountryCode == "" {
+		locale.Country, locale.CountryCode = usExampleLocale.Country, usExampleLocale.CountryCode
+	}
+	if locale.PhoneFormat == "" {
+		locale.PhoneFormat = usExampleLocale.PhoneFormat
+	}
+	if locale.Currency == "" || locale.PriceFormat == "" || locale.PriceMax <= 0 {
+		locale.Currency, locale.CurrencyDecimals, locale.PriceFormat, locale.PriceMax = usExampleLocale.Currency, usExampleLocale.CurrencyDecimals, usExampleLocale.PriceFormat, usExampleLocale.PriceMax
+		locale.DecimalSeparator, locale.ThousandsSeparator = usExampleLocale.DecimalSeparator, usExampleLocale.ThousandsSeparator
+	}
+	if locale.DecimalSeparator == "" {
+		locale.DecimalSeparator = "."
+	}
+	if locale.DateFormat == "" {
+		locale.DateFormat = usExampleLocale.DateFormat
+	}
+	return locale
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestExampleLocale_MarketSpecificFakeExamples(t *testing.T) {
+	type customer struct {
+		Name           string  `json:"name"`
+		Phone          string  `json:"phone"`
+		Currency       string  `json:"currency"`
+		Balance        float64 `json:"balance"`
+		FormattedTotal string  `json:"formattedTotal"`
+		JoinDate       string  `json:"joinDate"`
+	}
+	example := func(locale string) map[string]interface{} {
+		docs := New(&Config{DocsPath: "/docs", FakeExamples: true, ExampleLocale: locale})
+		docs.AddRoute("POST", "/customers", nil, WithRequest(customer{}))
+		if err := docs.Generate(); err != nil {
+			t.Fatal(err)
+		}
+		return docs.GetDocumentation().Endpoints[0].Endpoints[0].RequestBody.Example.(map[string]interface{})
+	}
+
+	indonesian := example("id_ID")
+	if phone, _ := indonesian["phone"].(string); !strings.HasPrefix(phone, "+62 812-") || indonesian["currency"] != "IDR" {
+		t.Fatalf("expected Indonesian phone and currency, got %+v", indonesian)
+	}
+	if balance, _ := indonesian["balance"].(float64); balance != math.Trunc(balance) || balance < 100 {
+		t.Fatalf("expected a rupiah balance without minor units, got %+v", indonesian)
+	}
+	if total, _ := indonesian["formattedTotal"].(string); !regexp.MustCompile(`^Rp\d{1,3}(\.\d{3})*$`).MatchString(total) {
+		t.Fatalf("expected a rupiah price, got %q", total)
+	}
+	if date, _ := indonesian["joinDate"].(string); !regexp.MustCompile(`^\d{2}/\d{2}/2024$`).MatchString(date) {
+		t.Fatalf("expected a dd/mm/yyyy date, got %q", date)
+	}
+
+	german := example("de")
+	if total, _ := german["formattedTotal"].(string); !strings.HasSuffix(total, " €") || !strings.Contains(total, ",") {
+		t.Fatalf("expected a euro price with a decimal comma, got %q", total)
+	}
+
+	RegisterExampleLocale("nl-NL", &ExampleLocale{FirstNames: []string{"Daan"}, LastNames: []string{"de Vries"}, PhoneFormat: "+31 20 ### ####"})
+	defer RegisterExampleLocale("nl-NL", nil)
+	dutch := example("nl-NL")
+	if dutch["name"] != "Daan de Vries" || !strings.HasPrefix(dutch["phone"].(string), "+31 20 ") || dutch["currency"] != "USD" {
+		t.Fatalf("expected the registered locale with defaults for the fields it leaves empty, got %+v", dutch)
+	}
+	if err := ValidateConfig(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", ExampleLocale: "xx-XX"}); err == nil {
+		t.Fatal("expected an unknown example locale to be rejected")
+	}
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Extensions are OpenAPI vendor extensions by name, e.g. "x-rate-limit",
+// emitted as they are on operations, schemas or the root document
+type Extensions map[string]interface{}
+
+// ExtensionName returns name with the "x-" prefix vendor extensions require
+func ExtensionName(name string) string {
+	name = strings.TrimSpace(name)
+	if strings.HasPrefix(strings.ToLower(name), "x-") {
+		return name
+	}
+	return "x-" + name
+}
+
+// ParseExtensionValue reads an extension value written in a comment or tag:
+// JSON such as 100, true or {"rate": 10} is decoded, other text is a string,
+// and an empty value means true, as for a bare "@x-internal"
+func ParseExtensionValue(value string) interface{} {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return true
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(value), &decoded); err == nil {
+		return decoded
+	}
+	return value
+}
+
+// FieldExtensions applies the extensions:"x-order=1,x-internal,!x-omitempty"
+// struct tag of a field to its schema: a name alone sets true, a name with
+// a leading ! sets false; tag looks up a struct tag of the field
+func FieldExtensions(schema map[string]interface{}, tag func(key string) string) {
+	if schema == nil {
+		return
+	}
+	for _, entry := range strings.Split(tag("extensions"), ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if name == "" {
+			continue
+		}
+		if negated, ok := strings.CutPrefix(name, "!"); ok && !found {
+			schema[ExtensionName(negated)] = false
+			continue
+		}
+		schema[ExtensionName(name)] = ParseExtensionValue(value)
+	}
+}
+
+// setExtensions copies extensions into an OpenAPI object, overriding the
+// extensions ByteDocs sets itself, such as x-sla
+func setExtensions(object map[string]interface{}, extensions Extensions) {
+	for name, value := range extensions {
+		object[ExtensionName(name)] = value
+	}
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExtensions_EmittedOnOperationsSchemasAndRoot(t *testing.T) {
+	type testOrder struct {
+		ID string `json:"id" extensions:"x-order=1,internal"`
+	}
+	docs := New(&Config{DocsPath: "/docs", Extensions: Extensions{"x-logo": map[string]interface{}{"url": "/logo.png"}}})
+	docs.AddRoute("GET", "/orders", nil,
+		WithResponse(200, testOrder{}),
+		WithExtension("rate-limit", 100),
+		WithExtension("x-sla", "fast"),
+	)
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := docs.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(spec)
+	for _, expected := range []string{`"x-logo":{"url":"/logo.png"}`, `"x-rate-limit":100`, `"x-order":1`, `"x-internal":true`} {
+		if !strings.Contains(string(raw), expected) {
+			t.Fatalf("expected %s in the OpenAPI spec, got %s", expected, raw)
+		}
+	}
+	swagger, err := docs.GetSwaggerJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ = json.Marshal(swagger)
+	if !strings.Contains(string(raw), `"x-logo":{"url":"/logo.png"}`) || !strings.Contains(string(raw), `"x-rate-limit":100`) {
+		t.Fatalf("expected the extensions in the Swagger spec, got %s", raw)
+	}
+	if endpoint := docs.GetDocumentation().Endpoints[0].Endpoints[0]; endpoint.Extensions["x-sla"] != "fast" {
+		t.Fatalf("expected the extension on the endpoint, got %+v", endpoint.Extensions)
+	}
+	if value := ParseExtensionValue("payments team"); value != "payments team" {
+		t.Fatalf("expected non-JSON values as strings, got %v", value)
+	}
+}
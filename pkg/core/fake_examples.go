@@ -0,0 +1,301 @@
+package core
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Placeholder examples the analyzers and SchemaFromValue fall back to
+const (
+	placeholderString   = "string"
+	placeholderDateTime = "2024-01-01T00:00:00Z"
+	placeholderUUID     = "123e4567-e89b-12d3-a456-426614174000"
+)
+
+var (
+	fakeCompanies = []string{"Acme Corp", "Globex", "Initech", "Northwind Traders", "Umbrella Labs", "Wayne Enterprises"}
+	fakeWords     = []string{"alpha", "bravo", "cobalt", "delta", "ember", "falcon", "garnet", "harbor", "indigo", "juniper"}
+	fakeTitles    = []string{"Quarterly report", "Welcome aboard", "Release notes", "Team offsite", "Product launch", "Weekly digest"}
+	// fakePersonFields are the prefixes of fields naming a person, e.g. customerName
+	fakePersonFields = map[string]bool{"full": true, "display": true, "customer": true, "user": true, "contact": true, "author": true, "owner": true, "recipient": true, "sender": true}
+	fakeSentences    = []string{
+		"A short description of the item.",
+		"Ships within two business days.",
+		"Updated after the last review.",
+		"Available in all regions.",
+	}
+)
+
+// applyFakeExamples replaces the placeholder examples of the request body and
+// responses, such as "string" and 0, with realistic values derived from the
+// field names and formats, drawn from locale. Values are seeded by method,
+// path and field, so they are stable between runs.
+func applyFakeExamples(endpoint *Endpoint, locale ExampleLocale) {
+	seed := endpoint.Method + " " + endpoint.Path
+	if endpoint.RequestBody != nil {
+		body := *endpoint.RequestBody
+		body.Example = fakeExample(body.Schema, body.Example, "", seed+" request", locale)
+		if len(body.Alternatives) > 0 {
+			body.Alternatives = append([]BodyContent{}, body.Alternatives...)
+			for i, alternative := range body.Alternatives {
+				body.Alternatives[i].Example = fakeExample(alternative.Schema, alternative.Example, "", seed+" request "+alternative.ContentType, locale)
+			}
+		}
+		endpoint.RequestBody = &body
+	}
+	if len(endpoint.Responses) > 0 {
+		responses := make(map[string]Response, len(endpoint.Responses))
+		for status, response := range endpoint.Responses {
+			response.Example = fakeExample(response.Schema, response.Example, "", seed+" "+status, locale)
+			responses[status] = response
+		}
+		endpoint.Responses = responses
+	}
+}
+
+// fakeExample returns a copy of example with its placeholder values replaced;
+// name is the field holding the value and seed its location
+func fakeExample(schema, example interface{}, name, seed string, locale ExampleLocale) interface{} {
+	schemaMap, _ := schema.(map[string]interface{})
+	switch value := example.(type) {
+	case map[string]interface{}:
+		properties, _ := schemaMap["properties"].(map[string]interface{})
+		faked := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			faked[key] = fakeExample(properties[key], item, key, seed+"."+key, locale)
+		}
+		return faked
+	case []interface{}:
+		faked := make([]interface{}, len(value))
+		for i, item := range value {
+			faked[i] = fakeExample(schemaMap["items"], item, name, fmt.Sprintf("%s[%d]", seed, i), locale)
+		}
+		return faked
+	}
+	if !isPlaceholderExample(example) {
+		return example
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(seed))
+	rng := rand.New(rand.NewSource(int64(hash.Sum64())))
+	format, _ := schemaMap["format"].(string)
+	words := fieldWords(name)
+	if _, ok := example.(string); ok {
+		return fakeString(rng, words, format, locale)
+	}
+	return fakeNumber(rng, words, example, locale)
+}
+
+// fieldWords splits a field name such as "createdAt" or "user_id" into
+// lowercase words
+func fieldWords(name string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			if len(word) > 0 {
+				words, word = append(words, string(word)), nil
+			}
+			continue
+		case unicode.IsUpper(r) && len(word) > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			words, word = append(words, string(word)), nil
+		}
+		word = append(word, unicode.ToLower(r))
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
+
+// isPlaceholderExample reports whether an example is a type-based default
+// rather than a value from a struct literal or an example tag
+func isPlaceholderExample(example interface{}) bool {
+	switch value := example.(type) {
+	case string:
+		return value == placeholderString || value == "" || value == placeholderDateTime || value == placeholderUUID
+	case nil, bool:
+		return false
+	}
+	number := reflect.ValueOf(example)
+	switch number.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return number.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return number.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return number.Float() == 0
+	}
+	return false
+}
+
+// fakeString returns a string for the words of the field name and the format
+func fakeString(rng *rand.Rand, words []string, format string, locale ExampleLocale) string {
+	name, suffix := strings.Join(words, ""), lastWord(words)
+	first, last := pick(rng, locale.FirstNames), pick(rng, locale.LastNames)
+	fullName := first + " " + last
+	if locale.FamilyNameFirst {
+		fullName = last + " " + first
+	}
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(rng.Intn(365*24*60)) * time.Minute)
+	switch {
+	case format == "uuid" || suffix == "uuid" || suffix == "guid" || suffix == "id":
+		return fmt.Sprintf("%08x-%04x-4%03x-%04x-%012x", rng.Uint32(), rng.Intn(1<<16), rng.Intn(1<<12), 0x8000|rng.Intn(1<<14), rng.Int63n(1<<48))
+	case format == "date-time" || suffix == "at" || suffix == "time" || suffix == "timestamp":
+		return date.Format(time.RFC3339)
+	case format == "date":
+		return date.Format("2006-01-02")
+	case suffix == "date" || suffix == "birthday" || name == "dob":
+		return date.Format(locale.DateFormat)
+	case format == "email" || strings.Contains(name, "email"):
+		return strings.ToLower(first + "." + last + "@example.com")
+	case format == "ipv4" || suffix == "ip" || strings.HasSuffix(name, "ipaddress"):
+		return fmt.Sprintf("192.0.2.%d", 1+rng.Intn(254))
+	case format == "uri" || format == "url" || suffix == "url" || suffix == "uri" || suffix == "website" || suffix == "link":
+		if strings.Contains(name, "avatar") || strings.Contains(name, "image") || strings.Contains(name, "photo") {
+			return fmt.Sprintf("https://example.com/images/%s.png", pick(rng, fakeWords))
+		}
+		return fmt.Sprintf("https://example.com/%s", pick(rng, fakeWords))
+	case name == "username" || name == "login" || name == "handle":
+		return strings.ToLower(first + "." + last)
+	case name == "firstname" || name == "givenname":
+		return first
+	case name == "lastname" || name == "surname" || name == "familyname":
+		return last
+	case name == "name" || name == "author" || name == "owner" || (suffix == "name" && fakePersonFields[words[0]]):
+		return fullName
+	case strings.Contains(name, "phone") || strings.Contains(name, "mobile"):
+		return fillPattern(rng, locale.PhoneFormat)
+	case strings.Contains(name, "street") || name == "address" || name == "addressline1":
+		return strings.NewReplacer("{number}", fmt.Sprint(1+rng.Intn(199)), "{street}", pick(rng, locale.Streets)).Replace(locale.AddressFormat)
+	case name == "city":
+		return pick(rng, locale.Cities)
+	case name == "country":
+		return locale.Country
+	case name == "countrycode":
+		return locale.CountryCode
+	case strings.Contains(name, "zip") || strings.Contains(name, "postal"):
+		return fillPattern(rng, locale.PostalFormat)
+	case strings.Contains(name, "company") || strings.Contains(name, "organization"):
+		return pick(rng, fakeCompanies)
+	case suffix == "currency" || name == "currencycode":
+		return locale.Currency
+	case strings.Contains(name, "price") || strings.Contains(name, "amount") || strings.Contains(name, "total"):
+		if words[0] == "formatted" || words[0] == "display" {
+			return strings.ReplaceAll(locale.PriceFormat, "{amount}", formatAmount(fakePrice(rng, locale), locale))
+		}
+		// Machine-readable amounts keep a plain decimal point
+		return strconv.FormatFloat(fakePrice(rng, locale), 'f', locale.CurrencyDecimals, 64)
+	case name == "title" || name == "subject":
+		return pick(rng, fakeTitles)
+	case strings.Contains(name, "description") || name == "bio" || name == "comment" || name == "note" || name == "message":
+		return pick(rng, fakeSentences)
+	case strings.Contains(name, "password") || strings.Contains(name, "secret"):
+		return fmt.Sprintf("S3cure-%s-%d", pick(rng, fakeWords), 100+rng.Intn(900))
+	case suffix == "token":
+		return fmt.Sprintf("tok_%016x", rng.Uint64())
+	}
+	return pick(rng, fakeWords)
+}
+
+// fakeNumber returns a number for the words of the field name, of the type of placeholder
+func fakeNumber(rng *rand.Rand, words []string, placeholder interface{}, locale ExampleLocale) interface{} {
+	name, suffix := strings.Join(words, ""), lastWord(words)
+	var value float64
+	switch {
+	case strings.Contains(name, "price") || strings.Contains(name, "amount") || strings.Contains(name, "total") ||
+		strings.Contains(name, "cost") || strings.Contains(name, "balance"):
+		value = fakePrice(rng, locale)
+	case name == "lat" || name == "latitude":
+		value = math.Round((rng.Float64()*180-90)*1e4) / 1e4
+	case name == "lng" || name == "lon" || name == "longitude":
+		value = math.Round((rng.Float64()*360-180)*1e4) / 1e4
+	case name == "age":
+		value = float64(18 + rng.Intn(60))
+	case name == "year":
+		value = float64(1990 + rng.Intn(35))
+	case strings.Contains(name, "rating") || strings.Contains(name, "score"):
+		value = math.Round((1+rng.Float64()*4)*10) / 10
+	case strings.Contains(name, "percent"):
+		value = float64(rng.Intn(101))
+	case strings.Contains(name, "count") || strings.Contains(name, "quantity") || suffix == "qty":
+		value = float64(1 + rng.Intn(20))
+	case suffix == "id":
+		value = float64(1 + rng.Intn(10000))
+	default:
+		value = float64(1 + rng.Intn(100))
+	}
+
+	switch placeholder.(type) {
+	case float32:
+		return float32(value)
+	case float64:
+		return value
+	}
+	// Integer placeholders keep integer values
+	return int64(math.Round(value))
+}
+
+// fakePrice returns a price up to locale.PriceMax, rounded to the currency's decimals
+func fakePrice(rng *rand.Rand, locale ExampleLocale) float64 {
+	unit := math.Pow(10, float64(locale.CurrencyDecimals))
+	if locale.CurrencyDecimals == 0 {
+		// Currencies without minor units are priced in round hundreds
+		unit = 0.01
+	}
+	return math.Max(1, math.Round(rng.Float64()*locale.PriceMax*unit)) / unit
+}
+
+// formatAmount writes a price with the locale's separators, e.g. "1.234,50"
+func formatAmount(amount float64, locale ExampleLocale) string {
+	formatted := strconv.FormatFloat(amount, 'f', locale.CurrencyDecimals, 64)
+	whole, fraction, _ := strings.Cut(formatted, ".")
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteString(locale.ThousandsSeparator)
+		}
+		grouped.WriteRune(digit)
+	}
+	if fraction != "" {
+		grouped.WriteString(locale.DecimalSeparator + fraction)
+	}
+	return grouped.String()
+}
+
+// fillPattern replaces '#' with random digits and '?' with random uppercase letters
+func fillPattern(rng *rand.Rand, pattern string) string {
+	var filled strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '#':
+			filled.WriteByte(byte('0' + rng.Intn(10)))
+		case '?':
+			filled.WriteByte(byte('A' + rng.Intn(26)))
+		default:
+			filled.WriteRune(r)
+		}
+	}
+	return filled.String()
+}
+
+func lastWord(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	return words[len(words)-1]
+}
+
+func pick(rng *rand.Rand, values []string) string {
+	return values[rng.Intn(len(values))]
+}
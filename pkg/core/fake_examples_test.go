@@ -0,0 +1,47 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFakeExamples_RealisticAndStable(t *testing.T) {
+	type createOrder struct {
+		CustomerEmail string    `json:"customer_email"`
+		CustomerName  string    `json:"customerName"`
+		Quantity      int       `json:"quantity"`
+		Price         float64   `json:"price"`
+		DeliverAt     time.Time `json:"deliverAt"`
+		Note          string    `json:"note" example:"Leave at the door"`
+	}
+	example := func(fake bool) map[string]interface{} {
+		docs := New(&Config{DocsPath: "/docs", FakeExamples: fake})
+		docs.AddRoute("POST", "/orders", nil, WithRequest(createOrder{}))
+		if err := docs.Generate(); err != nil {
+			t.Fatal(err)
+		}
+		return docs.GetDocumentation().Endpoints[0].Endpoints[0].RequestBody.Example.(map[string]interface{})
+	}
+
+	if plain := example(false); plain["customer_email"] != "string" || plain["quantity"] != int64(0) {
+		t.Fatalf("expected placeholders without FakeExamples, got %+v", plain)
+	}
+	faked := example(true)
+	if email, _ := faked["customer_email"].(string); !strings.HasSuffix(email, "@example.com") {
+		t.Fatalf("expected a fake email, got %+v", faked)
+	}
+	if name, _ := faked["customerName"].(string); !strings.Contains(name, " ") {
+		t.Fatalf("expected a fake full name, got %+v", faked)
+	}
+	if faked["quantity"] == int64(0) || faked["price"] == 0.0 || faked["deliverAt"] == "2024-01-01T00:00:00Z" {
+		t.Fatalf("expected placeholders to be replaced, got %+v", faked)
+	}
+	if faked["note"] != "Leave at the door" {
+		t.Fatalf("expected the example tag to be kept, got %+v", faked)
+	}
+	if again := example(true); fmt.Sprint(again) != fmt.Sprint(faked) {
+		t.Fatalf("expected stable fake examples, got %+v and %+v", faked, again)
+	}
+}
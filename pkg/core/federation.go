@@ -0,0 +1,476 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultFederationInterval = 5 * time.Minute
+	federationTimeout         = 10 * time.Second
+	maxFederatedSpec          = 10 << 20
+)
+
+// federatedMethods are the operations of an OpenAPI path item
+var federatedMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// FederationConfig merges the OpenAPI documents of sibling services into
+// these docs, one section per service, so one portal presents the whole
+// platform. The documents are fetched on the first build, on Refresh and
+// then every Interval until APIDocs.Close; a service that cannot be fetched keeps the endpoints
+// of its last successful fetch.
+type FederationConfig struct {
+	Services []FederatedService
+	Interval time.Duration                   // between fetches; 0 uses 5 minutes, negative only fetches on build and Refresh
+	Client   *http.Client                    // nil uses a client with a 10 second timeout
+	OnError  func(service string, err error) // called when a service cannot be fetched or parsed
+}
+
+// FederatedService is a sibling service whose OpenAPI 3 document is merged
+type FederatedService struct {
+	Name        string            // section name, e.g. "Orders"
+	URL         string            // of its JSON or YAML spec, e.g. "http://orders:8080/docs/openapi.json"
+	Prefix      string            // added to its paths, e.g. the route "/orders" of a gateway in front of it
+	Description string            // section description; empty uses the spec's info.description
+	Headers     map[string]string // sent with the fetch, e.g. Authorization; values may be secret references
+}
+
+// federatedSpec is the outcome of fetching one service
+type federatedSpec struct {
+	routes      []RouteInfo
+	description string
+	err         error
+}
+
+// SyncFederation fetches the documents of the federated services now and
+// regenerates the docs. It returns the errors of the services that failed.
+func (a *APIDocs) SyncFederation(ctx context.Context) error {
+	a.build()
+	fetchErr := a.fetchFederation(ctx)
+	if err := a.Generate(); err != nil {
+		return err
+	}
+	return fetchErr
+}
+
+// startFederation fetches the federated documents for the first build and
+// keeps them current in the background until Close
+func (a *APIDocs) startFederation() {
+	settings := a.config.Federation
+	if settings == nil || len(settings.Services) == 0 {
+		return
+	}
+	a.fetchFederation(context.Background())
+
+	interval := settings.Interval
+	if interval == 0 {
+		interval = defaultFederationInterval
+	}
+	if interval < 0 || a.closed.Err() != nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				a.SyncFederation(a.closed)
+			case <-a.closed.Done():
+				return
+			}
+		}
+	}()
+}
+
+// fetchFederation fetches every service in parallel and stores the routes of
+// the ones that succeeded
+func (a *APIDocs) fetchFederation(ctx context.Context) error {
+	settings := a.config.Federation
+	if settings == nil || len(settings.Services) == 0 {
+		return nil
+	}
+	client := settings.Client
+	if client == nil {
+		client = &http.Client{Timeout: federationTimeout}
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		// The first build waits for the fetch, so it must end
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, federationTimeout)
+		defer cancel()
+	}
+
+	results := make([]federatedSpec, len(settings.Services))
+	var wg sync.WaitGroup
+	for i, service := range settings.Services {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			spec, err := fetchFederatedSpec(ctx, client, service)
+			if err != nil {
+				results[i].err = err
+				return
+			}
+			results[i] = federatedRoutes(service, spec)
+		}()
+	}
+	wg.Wait()
+
+	var errs []error
+	a.mu.Lock()
+	for i, service := range settings.Services {
+		result := results[i]
+		a.health.recordFederation(service.Name, len(result.routes), result.err)
+		if result.err != nil {
+			errs = append(errs, fmt.Errorf("federated service %q: %w", service.Name, result.err))
+			continue
+		}
+		if a.federated == nil {
+			a.federated = make(map[string][]RouteInfo)
+		}
+		a.federated[service.Name] = result.routes
+
+		// SetSection metadata of the application wins
+		id := federatedSectionID(service.Name)
+		if meta, ok := a.sections[id]; !ok || meta.federated {
+			description := service.Description
+			if description == "" {
+				description = result.description
+			}
+			a.sections[id] = sectionMeta{name: service.Name, description: description, federated: true}
+		}
+	}
+	a.dirty.Store(true)
+	a.mu.Unlock()
+
+	if settings.OnError != nil {
+		for i, service := range settings.Services {
+			if results[i].err != nil {
+				settings.OnError(service.Name, results[i].err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// withFederatedRoutes appends the federated routes, in service order, to a
+// copy of routes. Callers hold a.mu.
+func (a *APIDocs) withFederatedRoutes(routes []RouteInfo) []RouteInfo {
+	if len(a.federated) == 0 {
+		return routes
+	}
+	merged := append([]RouteInfo{}, routes...)
+	for _, service := range a.config.Federation.Services {
+		merged = append(merged, a.federated[service.Name]...)
+	}
+	return merged
+}
+
+// fetchFederatedSpec downloads and decodes an OpenAPI 3 document
+func fetchFederatedSpec(ctx context.Context, client *http.Client, service FederatedService) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, service.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json, application/yaml")
+	for name, value := range service.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned %s", service.URL, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFederatedSpec+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxFederatedSpec {
+		return nil, fmt.Errorf("spec is larger than %d bytes", maxFederatedSpec)
+	}
+
+	var spec map[string]interface{}
+	if err := json.Unmarshal(body, &spec); err != nil {
+		if yamlErr := yaml.Unmarshal(body, &spec); yamlErr != nil {
+			return nil, fmt.Errorf("spec is neither JSON nor YAML: %v", err)
+		}
+		stringKeys(spec)
+	}
+	if version, _ := spec["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("not an OpenAPI 3 document")
+	}
+	return spec, nil
+}
+
+// stringKeys converts the maps YAML decodes with numeric keys, such as
+// response codes, to string keys in place
+func stringKeys(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, entry := range value {
+			value[key] = stringKeys(entry)
+		}
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(value))
+		for key, entry := range value {
+			converted[fmt.Sprint(key)] = stringKeys(entry)
+		}
+		return converted
+	case []interface{}:
+		for i, entry := range value {
+			value[i] = stringKeys(entry)
+		}
+	}
+	return value
+}
+
+// federatedRoutes turns the operations of a spec into routes of the
+// service's section. $refs are inlined, since the service's components are
+// not part of the merged document.
+func federatedRoutes(service FederatedService, spec map[string]interface{}) federatedSpec {
+	result := federatedSpec{}
+	if info, ok := spec["info"].(map[string]interface{}); ok {
+		result.description, _ = info["description"].(string)
+	}
+	prefix := normalizePathPrefix(service.Prefix)
+	section := federatedSectionID(service.Name)
+
+	paths, _ := spec["paths"].(map[string]interface{})
+	names := make([]string, 0, len(paths))
+	for path := range paths {
+		names = append(names, path)
+	}
+	sort.Strings(names)
+
+	for _, path := range names {
+		item, _ := resolveSpecRefs(spec, paths[path], nil).(map[string]interface{})
+		if item == nil {
+			continue
+		}
+		shared := federatedParameters(item["parameters"])
+		for _, method := range federatedMethods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			route := RouteInfo{
+				Method:     strings.ToUpper(method),
+				Path:       prefix + path,
+				Parameters: append(append([]Parameter{}, shared...), federatedParameters(operation["parameters"])...),
+				Responses:  federatedResponses(operation["responses"]),
+				Section:    section,
+			}
+			route.Summary, _ = operation["summary"].(string)
+			route.Description, _ = operation["description"].(string)
+			route.Deprecated, _ = operation["deprecated"].(bool)
+			route.RequestBody = federatedRequestBody(operation["requestBody"])
+			if docs, ok := operation["externalDocs"].(map[string]interface{}); ok {
+				if url, _ := docs["url"].(string); url != "" {
+					description, _ := docs["description"].(string)
+					route.ExternalDocs = &ExternalDocs{URL: url, Description: description}
+				}
+			}
+			result.routes = append(result.routes, route)
+		}
+	}
+	return result
+}
+
+// federatedSectionID is the section ID of a service, e.g. "orders" for "Orders"
+func federatedSectionID(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), "-"))
+}
+
+// federatedParameters converts OpenAPI parameter objects
+func federatedParameters(value interface{}) []Parameter {
+	list, _ := value.([]interface{})
+	params := make([]Parameter, 0, len(list))
+	for _, entry := range list {
+		definition, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		param := Parameter{Type: "string"}
+		param.Name, _ = definition["name"].(string)
+		param.In, _ = definition["in"].(string)
+		param.Required, _ = definition["required"].(bool)
+		param.Description, _ = definition["description"].(string)
+		param.Example = definition["example"]
+		if schema, ok := definition["schema"].(map[string]interface{}); ok {
+			param.Type = federatedParameterType(schema["type"])
+			if param.Example == nil {
+				param.Example = schema["example"]
+			}
+		}
+		if param.Name == "" || param.In == "" {
+			continue
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// federatedParameterType maps a JSON schema type to the type names of Parameter
+func federatedParameterType(value interface{}) string {
+	switch value {
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array", "object":
+		return value.(string)
+	}
+	return "string"
+}
+
+// federatedRequestBody converts an OpenAPI request body; JSON content comes
+// first, the other content types become alternatives
+func federatedRequestBody(value interface{}) *RequestBody {
+	definition, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	contents := federatedContents(definition["content"])
+	if len(contents) == 0 {
+		return nil
+	}
+	body := &RequestBody{
+		ContentType:  contents[0].ContentType,
+		Schema:       contents[0].Schema,
+		Example:      contents[0].Example,
+		Alternatives: contents[1:],
+	}
+	body.Required, _ = definition["required"].(bool)
+	if len(body.Alternatives) == 0 {
+		body.Alternatives = nil
+	}
+	return body
+}
+
+// federatedResponses converts OpenAPI responses, keeping the preferred
+// content type of each
+func federatedResponses(value interface{}) map[string]Response {
+	definitions, _ := value.(map[string]interface{})
+	responses := make(map[string]Response, len(definitions))
+	for status, entry := range definitions {
+		definition, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		response := Response{}
+		response.Description, _ = definition["description"].(string)
+		if contents := federatedContents(definition["content"]); len(contents) > 0 {
+			response.ContentType = contents[0].ContentType
+			response.Schema = contents[0].Schema
+			response.Example = contents[0].Example
+		}
+		responses[status] = response
+	}
+	return responses
+}
+
+// federatedContents converts an OpenAPI content map, JSON types first
+func federatedContents(value interface{}) []BodyContent {
+	content, _ := value.(map[string]interface{})
+	types := make([]string, 0, len(content))
+	for contentType := range content {
+		types = append(types, contentType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		left, right := strings.Contains(types[i], "json"), strings.Contains(types[j], "json")
+		if left != right {
+			return left
+		}
+		return types[i] < types[j]
+	})
+
+	contents := make([]BodyContent, 0, len(types))
+	for _, contentType := range types {
+		media, _ := content[contentType].(map[string]interface{})
+		entry := BodyContent{ContentType: contentType, Schema: media["schema"], Example: media["example"]}
+		if examples, ok := media["examples"].(map[string]interface{}); entry.Example == nil && ok {
+			names := make([]string, 0, len(examples))
+			for name := range examples {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				if example, ok := examples[name].(map[string]interface{}); ok && example["value"] != nil {
+					entry.Example = example["value"]
+					break
+				}
+			}
+		}
+		contents = append(contents, entry)
+	}
+	return contents
+}
+
+// resolveSpecRefs returns a copy of value with the local $refs of spec
+// replaced by their targets. seen holds the refs being resolved, so
+// recursive schemas end in a plain object instead of looping.
+func resolveSpecRefs(spec map[string]interface{}, value interface{}, seen map[string]bool) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := value["$ref"].(string); ok && strings.HasPrefix(ref, "#/") {
+			if seen[ref] {
+				return map[string]interface{}{"type": "object", "description": "Recursive " + ref[strings.LastIndex(ref, "/")+1:]}
+			}
+			target, ok := specPointer(spec, ref)
+			if !ok {
+				return map[string]interface{}{}
+			}
+			if seen == nil {
+				seen = make(map[string]bool)
+			}
+			seen[ref] = true
+			defer delete(seen, ref)
+			return resolveSpecRefs(spec, target, seen)
+		}
+		resolved := make(map[string]interface{}, len(value))
+		for key, entry := range value {
+			resolved[key] = resolveSpecRefs(spec, entry, seen)
+		}
+		return resolved
+	case []interface{}:
+		resolved := make([]interface{}, len(value))
+		for i, entry := range value {
+			resolved[i] = resolveSpecRefs(spec, entry, seen)
+		}
+		return resolved
+	}
+	return value
+}
+
+// specPointer looks up a "#/a/b" JSON pointer in spec
+func specPointer(spec map[string]interface{}, ref string) (interface{}, bool) {
+	var current interface{} = spec
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[token]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
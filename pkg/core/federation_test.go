@@ -0,0 +1,186 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFederation_MergesRemoteSpecsIntoServiceSections(t *testing.T) {
+	var ordersStatus atomic.Int32
+	ordersStatus.Store(http.StatusOK)
+	ordersSpec := `{
+		"openapi": "3.0.3",
+		"info": {"title": "Orders", "version": "1.0.0", "description": "Order management"},
+		"paths": {
+			"/orders/{id}": {
+				"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+				"get": {
+					"summary": "Get order",
+					"parameters": [{"$ref": "#/components/parameters/Expand"}],
+					"responses": {"200": {"description": "The order", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Order"}}}}}
+				},
+				"delete": {"summary": "Cancel order", "deprecated": true, "responses": {"204": {"description": "Cancelled"}}}
+			}
+		},
+		"components": {
+			"parameters": {"Expand": {"name": "expand", "in": "query", "schema": {"type": "boolean"}}},
+			"schemas": {"Order": {"type": "object", "properties": {"id": {"type": "integer"}, "parent": {"$ref": "#/components/schemas/Order"}}}}
+		}
+	}`
+	billingSpec := `openapi: 3.0.3
+info:
+  title: Billing
+  version: 2.0.0
+paths:
+  /invoices:
+    post:
+      summary: Create invoice
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {type: object, properties: {amount: {type: number}}}
+            examples:
+              small: {value: {amount: 10}}
+      responses:
+        201:
+          description: Created
+`
+	services := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orders/openapi.json":
+			if status := int(ordersStatus.Load()); status != http.StatusOK {
+				w.WriteHeader(status)
+				return
+			}
+			if r.Header.Get("Authorization") != "Bearer internal" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			io.WriteString(w, ordersSpec)
+		case "/billing/openapi.yaml":
+			io.WriteString(w, billingSpec)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer services.Close()
+
+	var failed []string
+	docs := New(&Config{Title: "Platform", Version: "1.0.0", DocsPath: "/docs", Federation: &FederationConfig{
+		Interval: -1,
+		Services: []FederatedService{
+			{Name: "Orders", URL: services.URL + "/orders/openapi.json", Prefix: "/orders-api", Headers: map[string]string{"Authorization": "Bearer internal"}},
+			{Name: "Billing", URL: services.URL + "/billing/openapi.yaml", Description: "Invoices and payments"},
+			{Name: "Search", URL: services.URL + "/search/openapi.json"},
+		},
+		OnError: func(service string, err error) { failed = append(failed, service) },
+	}})
+	docs.AddRoute("GET", "/health", nil)
+	if err := docs.Warmup(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 1 || failed[0] != "Search" {
+		t.Fatalf("expected the missing search spec to be reported, got %v", failed)
+	}
+
+	sections := map[string]EndpointSection{}
+	for _, section := range docs.GetDocumentation().Endpoints {
+		sections[section.ID] = section
+	}
+	orders, billing := sections["orders"], sections["billing"]
+	if orders.Name != "Orders" || orders.Description != "Order management" || len(orders.Endpoints) != 2 {
+		t.Fatalf("expected an Orders section with two endpoints, got %+v", orders)
+	}
+	byMethod := map[string]Endpoint{}
+	for _, endpoint := range orders.Endpoints {
+		byMethod[endpoint.Method] = endpoint
+	}
+	if !byMethod["DELETE"].Deprecated {
+		t.Fatal("expected the cancel endpoint to stay deprecated")
+	}
+	get := byMethod["GET"]
+	if get.Path != "/orders-api/orders/{id}" || get.Summary != "Get order" || len(get.Parameters) != 2 || get.Parameters[0].Type != "int" || get.Parameters[1].Name != "expand" {
+		t.Fatalf("expected the prefixed order endpoint with its parameters, got %+v", get)
+	}
+	schema, _ := json.Marshal(get.Responses["200"].Schema)
+	if strings.Contains(string(schema), "$ref") || !strings.Contains(string(schema), `"description":"Recursive Order"`) {
+		t.Fatalf("expected the response schema to be inlined, got %s", schema)
+	}
+	if billing.Description != "Invoices and payments" || len(billing.Endpoints) != 1 || billing.Endpoints[0].Responses["201"].Description != "Created" ||
+		!billing.Endpoints[0].RequestBody.Required || fmt.Sprint(billing.Endpoints[0].RequestBody.Example) != "map[amount:10]" {
+		t.Fatalf("expected the YAML billing spec to be merged, got %+v", billing)
+	}
+	if len(sections) != 3 {
+		t.Fatalf("expected the local endpoints next to the services, got %d sections", len(sections))
+	}
+
+	// A failing service keeps its last endpoints
+	ordersStatus.Store(http.StatusBadGateway)
+	if err := docs.SyncFederation(context.Background()); err == nil || !strings.Contains(err.Error(), `federated service "Orders": GET `) {
+		t.Fatalf("expected the failed fetch to be returned, got %v", err)
+	}
+	spec, err := docs.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paths := spec["paths"].(map[string]interface{}); paths["/orders-api/orders/{id}"] == nil || paths["/invoices"] == nil || paths["/health"] == nil {
+		t.Fatalf("expected the merged spec to keep every service, got %v", paths)
+	}
+}
+
+func TestFederation_PeriodicFetchesStopOnCloseAndUnmount(t *testing.T) {
+	var fetches atomic.Int32
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		io.WriteString(w, `{"openapi":"3.0.3","info":{"title":"Orders","version":"1.0.0"},"paths":{}}`)
+	}))
+	defer service.Close()
+
+	newDocs := func() *APIDocs {
+		docs := New(&Config{Title: "Platform", Version: "1.0.0", DocsPath: "/docs", Federation: &FederationConfig{
+			Interval: 5 * time.Millisecond,
+			Services: []FederatedService{{Name: "Orders", URL: service.URL}},
+		}})
+		if err := docs.Warmup(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		return docs
+	}
+	waitForFetches := func(n int32) {
+		for deadline := time.Now().Add(2 * time.Second); fetches.Load() < n; time.Sleep(time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d fetches, got %d", n, fetches.Load())
+			}
+		}
+	}
+	assertStopped := func(how string) {
+		time.Sleep(20 * time.Millisecond) // a fetch in flight while closing may still finish
+		stoppedAt := fetches.Load()
+		time.Sleep(50 * time.Millisecond)
+		if fetches.Load() != stoppedAt {
+			t.Fatalf("expected the periodic fetches to stop after %s, got %d more", how, fetches.Load()-stoppedAt)
+		}
+	}
+
+	docs := newDocs()
+	waitForFetches(fetches.Load() + 2)
+	docs.Close()
+	assertStopped("Close")
+
+	registry := NewRegistry()
+	if err := registry.Mount(Tenant{Name: "platform", Docs: newDocs()}); err != nil {
+		t.Fatal(err)
+	}
+	waitForFetches(fetches.Load() + 2)
+	registry.Unmount("platform")
+	assertStopped("Unmount")
+}
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// documentationFilter selects a slice of the documentation for api-data.json.
+// Empty fields match everything.
+type documentationFilter struct {
+	Tags       []string
+	Methods    []string
+	Deprecated *bool
+	Search     string
+	Sort       string
+}
+
+// parseDocumentationFilter reads ?tag=&method=&deprecated=&search=&sort= from
+// the request. tag and method accept comma-separated or repeated values.
+func parseDocumentationFilter(r *http.Request) (documentationFilter, error) {
+	var filter documentationFilter
+	if r == nil {
+		return filter, nil
+	}
+	query := r.URL.Query()
+
+	filter.Tags = splitQueryValues(query["tag"])
+	filter.Methods = splitQueryValues(query["method"])
+	filter.Search = strings.ToLower(strings.TrimSpace(query.Get("search")))
+
+	if value := strings.TrimSpace(query.Get("deprecated")); value != "" {
+		deprecated, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid deprecated value %q", value)
+		}
+		filter.Deprecated = &deprecated
+	}
+
+	switch sortBy := strings.ToLower(strings.TrimSpace(query.Get("sort"))); sortBy {
+	case "", "path", "method", "summary":
+		filter.Sort = sortBy
+	default:
+		return filter, fmt.Errorf("invalid sort value %q, expected path, method or summary", sortBy)
+	}
+
+	return filter, nil
+}
+
+func splitQueryValues(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
+
+// isEmpty reports whether the filter would return the documentation unchanged
+func (f documentationFilter) isEmpty() bool {
+	return len(f.Tags) == 0 && len(f.Methods) == 0 && f.Deprecated == nil && f.Search == "" && f.Sort == ""
+}
+
+// filterDocumentation returns a copy of doc with only the matching endpoints.
+// Sections left without endpoints are dropped; schemas are kept as-is.
+func filterDocumentation(doc *Documentation, filter documentationFilter) *Documentation {
+	if doc == nil || filter.isEmpty() {
+		return doc
+	}
+
+	filtered := &Documentation{
+		Info:      doc.Info,
+		Endpoints: make([]EndpointSection, 0, len(doc.Endpoints)),
+		Schemas:   doc.Schemas,
+		Guides:    doc.Guides,
+		Warnings:  doc.Warnings,
+	}
+
+	for _, section := range doc.Endpoints {
+		sectionCopy := section
+		sectionCopy.Endpoints = make([]Endpoint, 0, len(section.Endpoints))
+		for _, endpoint := range section.Endpoints {
+			if filter.matches(section, endpoint) {
+				sectionCopy.Endpoints = append(sectionCopy.Endpoints, endpoint)
+			}
+		}
+		if len(sectionCopy.Endpoints) == 0 {
+			continue
+		}
+		sortEndpoints(sectionCopy.Endpoints, filter.Sort)
+		filtered.Endpoints = append(filtered.Endpoints, sectionCopy)
+	}
+
+	return filtered
+}
+
+func (f documentationFilter) matches(section EndpointSection, endpoint Endpoint) bool {
+	if len(f.Tags) > 0 && !matchesAnyTag(f.Tags, section, endpoint) {
+		return false
+	}
+
+	if len(f.Methods) > 0 {
+		found := false
+		for _, method := range f.Methods {
+			if strings.EqualFold(method, endpoint.Method) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.Deprecated != nil && *f.Deprecated != endpoint.Deprecated {
+		return false
+	}
+
+	if f.Search != "" {
+		haystack := strings.ToLower(strings.Join([]string{
+			endpoint.ID, endpoint.Path, endpoint.Summary, endpoint.Description,
+		}, "\n"))
+		if !strings.Contains(haystack, f.Search) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesAnyTag compares tags case-insensitively against the section name and
+// id as well as the endpoint's own tags
+func matchesAnyTag(tags []string, section EndpointSection, endpoint Endpoint) bool {
+	for _, tag := range tags {
+		if strings.EqualFold(tag, section.Name) || strings.EqualFold(tag, section.ID) {
+			return true
+		}
+		for _, endpointTag := range endpoint.Tags {
+			if strings.EqualFold(tag, endpointTag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sortEndpoints orders endpoints by path, method or summary; ties keep their order
+func sortEndpoints(endpoints []Endpoint, sortBy string) {
+	var key func(Endpoint) string
+	switch sortBy {
+	case "path":
+		key = func(e Endpoint) string { return e.Path + " " + e.Method }
+	case "method":
+		key = func(e Endpoint) string { return e.Method + " " + e.Path }
+	case "summary":
+		key = func(e Endpoint) string { return strings.ToLower(e.Summary) }
+	default:
+		return
+	}
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		return key(endpoints[i]) < key(endpoints[j])
+	})
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"sort"
+	"testing"
+)
+
+func TestAPIData_FiltersBySearchMethodAndDeprecated(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs"})
+	docs.AddRoute("GET", "/users", nil, WithSummary("List users by email"))
+	docs.AddRoute("POST", "/users", nil, WithSummary("Create user"))
+	docs.AddRoute("GET", "/users/legacy", nil, WithSummary("Legacy email lookup"), WithDeprecated())
+	docs.AddRoute("GET", "/orders", nil, WithSummary("List orders"))
+
+	fetch := func(query string) *Documentation {
+		rec := httptest.NewRecorder()
+		docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/api-data.json?"+query, nil))
+		if rec.Code != 200 {
+			t.Fatalf("%s: unexpected status %d: %s", query, rec.Code, rec.Body.String())
+		}
+		var doc Documentation
+		if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+			t.Fatalf("%s: invalid json: %v", query, err)
+		}
+		return &doc
+	}
+	paths := func(doc *Documentation) []string {
+		var result []string
+		for _, section := range doc.Endpoints {
+			for _, endpoint := range section.Endpoints {
+				result = append(result, endpoint.Method+" "+endpoint.Path)
+			}
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	got := paths(fetch("method=get&deprecated=false&search=EMAIL"))
+	if len(got) != 1 || got[0] != "GET /users" {
+		t.Fatalf("unexpected filter result: %v", got)
+	}
+
+	got = paths(fetch("deprecated=true"))
+	if len(got) != 1 || got[0] != "GET /users/legacy" {
+		t.Fatalf("unexpected deprecated result: %v", got)
+	}
+
+	if got = paths(fetch("")); len(got) != 4 {
+		t.Fatalf("expected unfiltered docs, got %v", got)
+	}
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/api-data.json?deprecated=maybe", nil))
+	if rec.Code != 400 {
+		t.Fatalf("expected 400 for invalid filter, got %d", rec.Code)
+	}
+}
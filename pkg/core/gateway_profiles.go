@@ -0,0 +1,202 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ExportProfile adapts an OpenAPI spec for import into an API gateway by
+// adding its extensions. The spec is freshly built for every export; profiles
+// may change its root and operation maps but must not modify schemas in place.
+type ExportProfile func(spec map[string]interface{})
+
+var (
+	exportProfiles = map[string]ExportProfile{
+		"aws":    awsExportProfile,
+		"kong":   kongExportProfile,
+		"apigee": apigeeExportProfile,
+	}
+	exportProfilesMutex sync.RWMutex
+)
+
+// RegisterExportProfile makes ?profile=<name> on the OpenAPI export and
+// GetOpenAPIJSONForProfile apply profile. The built-in profiles are "aws",
+// "kong" and "apigee"; registering one of their names replaces it, and a nil
+// profile removes it.
+func RegisterExportProfile(name string, profile ExportProfile) {
+	exportProfilesMutex.Lock()
+	defer exportProfilesMutex.Unlock()
+	name = strings.ToLower(strings.TrimSpace(name))
+	if profile == nil {
+		delete(exportProfiles, name)
+		return
+	}
+	exportProfiles[name] = profile
+}
+
+func lookupExportProfile(name string) (ExportProfile, error) {
+	exportProfilesMutex.RLock()
+	defer exportProfilesMutex.RUnlock()
+	profile, ok := exportProfiles[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		names := make([]string, 0, len(exportProfiles))
+		for known := range exportProfiles {
+			names = append(names, known)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown export profile %q, use one of %s", name, strings.Join(names, ", "))
+	}
+	return profile, nil
+}
+
+// GetOpenAPIJSONForProfile returns the OpenAPI spec adapted by the named
+// export profile, e.g. "aws", for import into that gateway
+func (a *APIDocs) GetOpenAPIJSONForProfile(name string) (map[string]interface{}, error) {
+	profile, err := lookupExportProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	openAPI, err := a.GetOpenAPIJSON()
+	if err != nil {
+		return nil, err
+	}
+	profile(openAPI)
+	return openAPI, nil
+}
+
+// requestedExportProfile returns the profile of a ?profile= query, or nil
+func requestedExportProfile(r *http.Request) (ExportProfile, error) {
+	if r == nil {
+		return nil, nil
+	}
+	name := r.URL.Query().Get("profile")
+	if name == "" {
+		return nil, nil
+	}
+	return lookupExportProfile(name)
+}
+
+// eachOperation calls fn for every operation of spec with its path and method
+func eachOperation(spec map[string]interface{}, fn func(path, method string, operation map[string]interface{})) {
+	paths, _ := spec["paths"].(map[string]interface{})
+	for path, item := range paths {
+		pathItem, _ := item.(map[string]interface{})
+		for method, value := range pathItem {
+			if operation, ok := value.(map[string]interface{}); ok {
+				fn(path, method, operation)
+			}
+		}
+	}
+}
+
+// operationParameters returns the parameters of an operation, resolving
+// $refs to shared parameters
+func operationParameters(spec, operation map[string]interface{}) []map[string]interface{} {
+	params, _ := operation["parameters"].([]map[string]interface{})
+	components, _ := spec["components"].(map[string]interface{})
+	shared, _ := components["parameters"].(map[string]interface{})
+	resolved := make([]map[string]interface{}, 0, len(params))
+	for _, param := range params {
+		if ref, ok := param["$ref"].(string); ok {
+			param, _ = shared[strings.TrimPrefix(ref, "#/components/parameters/")].(map[string]interface{})
+		}
+		if param != nil {
+			resolved = append(resolved, param)
+		}
+	}
+	return resolved
+}
+
+// firstServerURL returns the URL of the first server with an absolute URL
+func firstServerURL(spec map[string]interface{}) string {
+	servers, _ := spec["servers"].([]map[string]interface{})
+	for _, server := range servers {
+		if url, _ := server["url"].(string); strings.Contains(url, "://") {
+			return strings.TrimSuffix(url, "/")
+		}
+	}
+	return ""
+}
+
+// awsExportProfile adds HTTP proxy integration stubs and request validation
+// for importing the spec into Amazon API Gateway. Without an absolute server
+// URL, integrations point to the backendUrl stage variable.
+func awsExportProfile(spec map[string]interface{}) {
+	backend := firstServerURL(spec)
+	if backend == "" {
+		backend = "${stageVariables.backendUrl}"
+	}
+
+	spec["x-amazon-apigateway-request-validators"] = map[string]interface{}{
+		"all":         map[string]interface{}{"validateRequestBody": true, "validateRequestParameters": true},
+		"params-only": map[string]interface{}{"validateRequestBody": false, "validateRequestParameters": true},
+	}
+	spec["x-amazon-apigateway-request-validator"] = "all"
+
+	locations := map[string]string{"path": "path", "query": "querystring", "header": "header"}
+	eachOperation(spec, func(path, method string, operation map[string]interface{}) {
+		requestParameters := make(map[string]interface{})
+		for _, param := range operationParameters(spec, operation) {
+			name, _ := param["name"].(string)
+			in, _ := param["in"].(string)
+			if location, ok := locations[in]; ok && name != "" {
+				requestParameters["integration.request."+location+"."+name] = "method.request." + location + "." + name
+			}
+		}
+		integration := map[string]interface{}{
+			"type":                "http_proxy",
+			"httpMethod":          strings.ToUpper(method),
+			"uri":                 backend + path,
+			"passthroughBehavior": "when_no_match",
+		}
+		if len(requestParameters) > 0 {
+			integration["requestParameters"] = requestParameters
+		}
+		operation["x-amazon-apigateway-integration"] = integration
+	})
+}
+
+// kongExportProfile names the service and enables request validation against
+// the documented schemas for decK's openapi2kong conversion
+func kongExportProfile(spec map[string]interface{}) {
+	info, _ := spec["info"].(map[string]interface{})
+	title, _ := info["title"].(string)
+	if name := gatewayName(title); name != "" {
+		spec["x-kong-name"] = strings.ToLower(name)
+	}
+	eachOperation(spec, func(path, method string, operation map[string]interface{}) {
+		if operation["requestBody"] == nil && len(operationParameters(spec, operation)) == 0 {
+			return
+		}
+		operation["x-kong-plugin-request-validator"] = map[string]interface{}{
+			"enabled": true,
+			"config":  map[string]interface{}{"verbose_response": true},
+		}
+	})
+}
+
+// apigeeExportProfile makes operationIds valid Apigee flow names, since
+// Apigee turns each operation into a conditional flow named after it
+func apigeeExportProfile(spec map[string]interface{}) {
+	eachOperation(spec, func(path, method string, operation map[string]interface{}) {
+		if id, ok := operation["operationId"].(string); ok {
+			operation["operationId"] = gatewayName(id)
+		}
+	})
+}
+
+var gatewayNameInvalid = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// gatewayName turns text into a name gateways accept, e.g. "get--users-{id}"
+// into "get-users-id" and "Orders API" into "Orders-API"
+func gatewayName(text string) string {
+	name := gatewayNameInvalid.ReplaceAllString(text, "-")
+	for strings.Contains(name, "--") {
+		name = strings.ReplaceAll(name, "--", "-")
+	}
+	return strings.Trim(name, "-")
+}
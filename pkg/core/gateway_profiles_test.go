@@ -0,0 +1,68 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExportProfiles_GatewayExtensions(t *testing.T) {
+	docs := New(&Config{Title: "Orders API", DocsPath: "/docs", BaseURL: "https://api.example.com"})
+	docs.AddRouteInfo(RouteInfo{Method: "GET", Path: "/orders/:id", Parameters: []Parameter{
+		{Name: "expand", In: "query", Type: "string"},
+	}})
+	docs.AddRouteInfo(RouteInfo{Method: "GET", Path: "/health"})
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	aws, err := docs.GetOpenAPIJSONForProfile("aws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aws["x-amazon-apigateway-request-validator"] != "all" {
+		t.Fatalf("expected request validation for AWS, got %+v", aws)
+	}
+	operation := aws["paths"].(map[string]interface{})["/orders/{id}"].(map[string]interface{})["get"].(map[string]interface{})
+	integration, _ := operation["x-amazon-apigateway-integration"].(map[string]interface{})
+	parameters, _ := integration["requestParameters"].(map[string]interface{})
+	if integration["uri"] != "https://api.example.com/orders/{id}" || integration["httpMethod"] != "GET" ||
+		parameters["integration.request.path.id"] != "method.request.path.id" || parameters["integration.request.querystring.expand"] != "method.request.querystring.expand" {
+		t.Fatalf("expected an HTTP proxy integration stub, got %+v", integration)
+	}
+
+	kong, err := docs.GetOpenAPIJSONForProfile("Kong")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := kong["paths"].(map[string]interface{})
+	if kong["x-kong-name"] != "orders-api" || paths["/orders/{id}"].(map[string]interface{})["get"].(map[string]interface{})["x-kong-plugin-request-validator"] == nil {
+		t.Fatalf("expected Kong service name and request validator, got %+v", kong)
+	}
+	if paths["/health"].(map[string]interface{})["get"].(map[string]interface{})["x-kong-plugin-request-validator"] != nil {
+		t.Fatal("expected no validator on an operation without input")
+	}
+
+	apigee, err := docs.GetOpenAPIJSONForProfile("apigee")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := apigee["paths"].(map[string]interface{})["/orders/{id}"].(map[string]interface{})["get"].(map[string]interface{})["operationId"]; id != "get-orders-id" {
+		t.Fatalf("expected a flow-safe operationId, got %v", id)
+	}
+
+	if _, err := docs.GetOpenAPIJSONForProfile("azure"); err == nil {
+		t.Fatal("expected an unknown profile to be rejected")
+	}
+	recorder := httptest.NewRecorder()
+	docs.ServeHTTP(recorder, httptest.NewRequest("GET", "/docs/openapi.json?profile=azure", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown profile, got %d", recorder.Code)
+	}
+	recorder = httptest.NewRecorder()
+	docs.ServeHTTP(recorder, httptest.NewRequest("GET", "/docs/openapi.json?profile=aws", nil))
+	if !strings.Contains(recorder.Body.String(), "x-amazon-apigateway-integration") {
+		t.Fatalf("expected the AWS profile applied to the export, got %s", recorder.Body.String())
+	}
+}
@@ -0,0 +1,195 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maxGoldenDiffLines caps the diff printed when the spec does not match
+const maxGoldenDiffLines = 200
+
+// TestingT is the subset of *testing.T used by AssertMatchesGolden, so this
+// package does not depend on the testing package
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// AssertMatchesGolden compares the generated OpenAPI spec with the golden file
+// at path and fails t with a line diff when they differ. Use it in your own
+// test suite to make API contract changes show up in code review.
+//
+// Run the tests with -update (define it with flag.Bool("update", false, ...) in
+// your test package) or BYTEDOCS_UPDATE_GOLDEN=1 to rewrite the golden file.
+func (a *APIDocs) AssertMatchesGolden(t TestingT, path string) {
+	t.Helper()
+
+	spec, err := a.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatalf("bytedocs: failed to generate OpenAPI spec: %v", err)
+		return
+	}
+	actual, err := json.MarshalIndent(a.orderSpec(spec), "", "  ")
+	if err != nil {
+		t.Fatalf("bytedocs: failed to encode OpenAPI spec: %v", err)
+		return
+	}
+	actual = append(actual, '\n')
+
+	if shouldUpdateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("bytedocs: failed to create golden directory: %v", err)
+			return
+		}
+		if err := os.WriteFile(path, actual, 0644); err != nil {
+			t.Fatalf("bytedocs: failed to update golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("bytedocs: failed to read golden file %s: %v (run with -update to create it)", path, err)
+		return
+	}
+
+	// Normalize the golden file so hand edits and formatting don't matter
+	var decoded interface{}
+	if err := json.Unmarshal(expected, &decoded); err != nil {
+		t.Fatalf("bytedocs: golden file %s is not valid JSON: %v", path, err)
+		return
+	}
+	if expected, err = json.MarshalIndent(a.orderSpec(decoded), "", "  "); err != nil {
+		t.Fatalf("bytedocs: failed to encode golden file %s: %v", path, err)
+		return
+	}
+	expected = append(expected, '\n')
+
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("bytedocs: OpenAPI spec does not match %s (run with -update to accept the change):\n%s",
+			path, goldenDiff(string(expected), string(actual)))
+	}
+}
+
+// shouldUpdateGolden reports whether golden files should be rewritten, based on
+// an -update flag defined by the host test package or BYTEDOCS_UPDATE_GOLDEN
+func shouldUpdateGolden() bool {
+	if update, err := strconv.ParseBool(os.Getenv("BYTEDOCS_UPDATE_GOLDEN")); err == nil && update {
+		return true
+	}
+	if f := flag.Lookup("update"); f != nil {
+		update, err := strconv.ParseBool(f.Value.String())
+		return err == nil && update
+	}
+	return false
+}
+
+// goldenDiff renders a line diff between expected (-) and actual (+) with a few
+// lines of context around each change
+func goldenDiff(expected, actual string) string {
+	const context = 3
+
+	a := strings.Split(strings.TrimSuffix(expected, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(actual, "\n"), "\n")
+
+	// Trim the common prefix and suffix before computing the LCS table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	type diffLine struct {
+		op   byte
+		text string
+	}
+	lines := make([]diffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		lines = append(lines, diffLine{' ', line})
+	}
+
+	if len(midA)*len(midB) > 4_000_000 {
+		// Too large for an LCS table: show the changed block as a whole
+		for _, line := range midA {
+			lines = append(lines, diffLine{'-', line})
+		}
+		for _, line := range midB {
+			lines = append(lines, diffLine{'+', line})
+		}
+	} else {
+		lcs := make([][]int, len(midA)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(midB)+1)
+		}
+		for i := len(midA) - 1; i >= 0; i-- {
+			for j := len(midB) - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(midA) || j < len(midB) {
+			switch {
+			case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+				lines = append(lines, diffLine{' ', midA[i]})
+				i++
+				j++
+			case j < len(midB) && (i == len(midA) || lcs[i][j+1] >= lcs[i+1][j]):
+				lines = append(lines, diffLine{'+', midB[j]})
+				j++
+			default:
+				lines = append(lines, diffLine{'-', midA[i]})
+				i++
+			}
+		}
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, diffLine{' ', line})
+	}
+
+	// Only print changed lines and their context
+	var out strings.Builder
+	written := 0
+	lastPrinted := -1
+	for index, line := range lines {
+		near := false
+		for k := index - context; k <= index+context; k++ {
+			if k >= 0 && k < len(lines) && lines[k].op != ' ' {
+				near = true
+				break
+			}
+		}
+		if !near {
+			continue
+		}
+		if written >= maxGoldenDiffLines {
+			fmt.Fprintf(&out, "... diff truncated\n")
+			break
+		}
+		if lastPrinted >= 0 && index > lastPrinted+1 {
+			out.WriteString("...\n")
+		}
+		fmt.Fprintf(&out, "%c %s\n", line.op, line.text)
+		lastPrinted = index
+		written++
+	}
+
+	return out.String()
+}
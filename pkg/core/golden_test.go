@@ -0,0 +1,49 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type recordingT struct {
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Fatalf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertMatchesGolden_UpdatesAndReportsDiff(t *testing.T) {
+	golden := t.TempDir() + "/testdata/openapi.golden.json"
+
+	docs := New(&Config{Title: "Golden", Version: "1.0.0", DocsPath: "/docs"})
+	docs.AddRoute("GET", "/users/:id", nil, WithParam("expand", "query", "string", false, "Expand relations"))
+
+	t.Setenv("BYTEDOCS_UPDATE_GOLDEN", "1")
+	rec := &recordingT{}
+	docs.AssertMatchesGolden(rec, golden)
+	if len(rec.errors) > 0 {
+		t.Fatalf("update failed: %v", rec.errors)
+	}
+
+	t.Setenv("BYTEDOCS_UPDATE_GOLDEN", "")
+	rec = &recordingT{}
+	docs.AssertMatchesGolden(rec, golden)
+	if len(rec.errors) > 0 {
+		t.Fatalf("expected golden to match, got %v", rec.errors)
+	}
+
+	docs.AddRoute("DELETE", "/users/:id", nil)
+	rec = &recordingT{}
+	docs.AssertMatchesGolden(rec, golden)
+	if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], `+       "delete": {`) {
+		t.Fatalf("expected a diff with the added operation, got %v", rec.errors)
+	}
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"net/http"
+	"strings"
+)
+
+// GraphQLExtension marks an operation as a GraphQL endpoint, set by
+// WithGraphQL or a "@x-graphql" comment line
+const GraphQLExtension = "x-graphql"
+
+// GraphQLRequest is the JSON body of a GraphQL request
+type GraphQLRequest struct {
+	Query         string                 `json:"query" example:"query { __typename }"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"`
+}
+
+// isGraphQLRoute reports whether a route serves GraphQL: marked with
+// x-graphql, or a GET or POST route whose last path segment is "graphql".
+// "@x-graphql false" opts a route out.
+func isGraphQLRoute(method, path string, extensions Extensions) bool {
+	if value, ok := extensions[GraphQLExtension]; ok {
+		flag, isBool := value.(bool)
+		return !isBool || flag
+	}
+	if method != http.MethodGet && method != http.MethodPost {
+		return false
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	return strings.EqualFold(segments[len(segments)-1], "graphql")
+}
+
+// applyGraphQL marks a GraphQL endpoint and documents the GraphQL request
+// body of a POST that documents none
+func applyGraphQL(endpoint *Endpoint) {
+	if !isGraphQLRoute(endpoint.Method, endpoint.Path, endpoint.Extensions) {
+		return
+	}
+	endpoint.GraphQL = true
+
+	extensions := make(Extensions, len(endpoint.Extensions)+1)
+	for name, value := range endpoint.Extensions {
+		extensions[name] = value
+	}
+	extensions[GraphQLExtension] = true
+	endpoint.Extensions = extensions
+
+	if endpoint.RequestBody == nil && endpoint.Method == http.MethodPost {
+		schema, example := SchemaFromValue(GraphQLRequest{})
+		endpoint.RequestBody = &RequestBody{
+			ContentType: "application/json",
+			Schema:      schema,
+			Example:     example,
+			Required:    true,
+			Description: "GraphQL query with its variables",
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package core
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGraphQL_DetectedMarkedAndDocumented(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs"})
+	docs.AddRoute("POST", "/api/graphql", nil)
+	docs.AddRoute("POST", "/gql", nil, WithGraphQL())
+	docs.AddRoute("POST", "/legacy/graphql", nil, WithExtension("x-graphql", false))
+	docs.AddRoute("POST", "/users", nil)
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	graphql := make(map[string]*Endpoint)
+	for _, section := range docs.GetDocumentation().Endpoints {
+		for i := range section.Endpoints {
+			graphql[section.Endpoints[i].Path] = &section.Endpoints[i]
+		}
+	}
+	for path, want := range map[string]bool{"/api/graphql": true, "/gql": true, "/legacy/graphql": false, "/users": false} {
+		if graphql[path] == nil || graphql[path].GraphQL != want {
+			t.Fatalf("expected %s to be GraphQL %v, got %+v", path, want, graphql[path])
+		}
+	}
+	body := graphql["/api/graphql"].RequestBody
+	if body == nil || body.ContentType != "application/json" {
+		t.Fatalf("expected a documented GraphQL request body, got %+v", body)
+	}
+	if example, ok := body.Example.(map[string]interface{}); !ok || example["query"] != "query { __typename }" {
+		t.Fatalf("expected a GraphQL query example, got %#v", body.Example)
+	}
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/openapi.json", nil))
+	if spec := rec.Body.String(); strings.Count(spec, `"x-graphql":true`) != 2 || !strings.Contains(spec, `"x-graphql":false`) {
+		t.Fatalf("expected x-graphql on the GraphQL operations, got %s", spec)
+	}
+}
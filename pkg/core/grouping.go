@@ -0,0 +1,134 @@
+package core
+
+import (
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// Values for Config.GroupingStrategy
+const (
+	GroupByLastSegment = "last"     // last path segment that is not a parameter (default)
+	GroupByPrefix      = "prefix"   // first segment after "api" and the version, e.g. "users" for /api/v1/users/{id}/orders
+	GroupByResource    = "resource" // full resource path, e.g. "users/orders" for /api/v1/users/{id}/orders
+	GroupByTag         = "tag"      // first endpoint tag, falling back to the prefix
+	GroupByFile        = "file"     // source file of the handler, falling back to the prefix
+)
+
+// GroupingFunc returns the section of an endpoint. An empty result falls back
+// to Config.GroupingStrategy.
+type GroupingFunc func(route RouteInfo, endpoint *Endpoint) string
+
+// versionSegment matches API version segments such as "v1" or "v2.1"
+var versionSegment = regexp.MustCompile(`^v\d+(\.\d+)*$`)
+
+// handlerFileSuffixes are stripped from handler file names, so that
+// user_handler.go and users_controller.go group as "user" and "users"
+var handlerFileSuffixes = []string{"_handlers", "_handler", "_controllers", "_controller", "_routes", "_api"}
+
+// validGroupingStrategy reports whether strategy is one of the GroupBy values or empty
+func validGroupingStrategy(strategy string) bool {
+	switch strategy {
+	case "", GroupByLastSegment, GroupByPrefix, GroupByResource, GroupByTag, GroupByFile:
+		return true
+	}
+	return false
+}
+
+// groupEndpoint returns the section ID and display name of an endpoint
+func (a *APIDocs) groupEndpoint(route RouteInfo, endpoint *Endpoint) (string, string) {
+	if section := strings.TrimSpace(route.Section); section != "" {
+		return section, a.formatSectionName(section)
+	}
+	if a.config != nil && a.config.GroupingFunc != nil {
+		if section := strings.TrimSpace(a.config.GroupingFunc(route, endpoint)); section != "" {
+			return section, a.formatSectionName(section)
+		}
+	}
+
+	strategy := ""
+	if a.config != nil {
+		strategy = a.config.GroupingStrategy
+	}
+
+	switch strategy {
+	case GroupByPrefix:
+		return a.prefixSection(endpoint.Path)
+	case GroupByResource:
+		segments := resourceSegments(endpoint.Path)
+		if len(segments) == 0 {
+			return "default", a.formatSectionName("default")
+		}
+		names := make([]string, len(segments))
+		for i, segment := range segments {
+			names[i] = a.formatSectionName(segment)
+		}
+		return strings.Join(segments, "/"), strings.Join(names, " / ")
+	case GroupByTag:
+		if len(endpoint.Tags) > 0 && strings.TrimSpace(endpoint.Tags[0]) != "" {
+			tag := strings.TrimSpace(endpoint.Tags[0])
+			return tag, a.formatSectionName(tag)
+		}
+		return a.prefixSection(endpoint.Path)
+	case GroupByFile:
+		if file := handlerFileSection(route.Handler); file != "" {
+			return file, a.formatSectionName(strings.ReplaceAll(file, "_", " "))
+		}
+		return a.prefixSection(endpoint.Path)
+	}
+
+	section := a.extractSection(endpoint.Path)
+	return section, a.formatSectionName(section)
+}
+
+// prefixSection groups by the first resource segment of path
+func (a *APIDocs) prefixSection(path string) (string, string) {
+	section := "default"
+	if segments := resourceSegments(path); len(segments) > 0 {
+		section = segments[0]
+	}
+	return section, a.formatSectionName(section)
+}
+
+// resourceSegments returns the static segments of path after a leading "api"
+// and version segment, e.g. ["users", "orders"] for /api/v1/users/{id}/orders
+func resourceSegments(path string) []string {
+	var segments []string
+	for i, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		if part == "" || strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") || strings.Contains(part, "{") {
+			continue
+		}
+		if len(segments) == 0 && i < 2 && (part == "api" || versionSegment.MatchString(part)) {
+			continue
+		}
+		segments = append(segments, part)
+	}
+	return segments
+}
+
+// handlerFileSection returns the source file name of handler without its
+// extension and handler suffix, or "" when it is not a function
+func handlerFileSection(handler interface{}) string {
+	value := reflect.ValueOf(handler)
+	if !value.IsValid() || value.Kind() != reflect.Func || value.IsNil() {
+		return ""
+	}
+	fn := runtime.FuncForPC(value.Pointer())
+	if fn == nil {
+		return ""
+	}
+	file, _ := fn.FileLine(fn.Entry())
+	if file == "" {
+		return ""
+	}
+
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	for _, suffix := range handlerFileSuffixes {
+		if trimmed := strings.TrimSuffix(name, suffix); trimmed != name && trimmed != "" {
+			return trimmed
+		}
+	}
+	return name
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGrouping_Strategies(t *testing.T) {
+	sectionsOf := func(config *Config) map[string][]string {
+		config.Title, config.Version, config.DocsPath = "Test", "1.0.0", "/docs"
+		docs := New(config)
+		docs.AddRoute("GET", "/api/v1/users/{id}/orders", func(http.ResponseWriter, *http.Request) {})
+		docs.AddRoute("GET", "/api/v1/users", nil)
+		docs.AddRoute("GET", "/api/v1/videos", nil)
+		if err := docs.Generate(); err != nil {
+			t.Fatal(err)
+		}
+		sections := make(map[string][]string)
+		for _, section := range docs.GetDocumentation().Endpoints {
+			for _, endpoint := range section.Endpoints {
+				sections[section.ID+"|"+section.Name] = append(sections[section.ID+"|"+section.Name], endpoint.Path)
+			}
+		}
+		return sections
+	}
+
+	if sections := sectionsOf(&Config{}); len(sections["orders|Orders"]) != 1 {
+		t.Fatalf("expected the last segment by default, got %v", sections)
+	}
+	if sections := sectionsOf(&Config{GroupingStrategy: GroupByPrefix}); len(sections["users|Users"]) != 2 || len(sections["videos|Videos"]) != 1 {
+		t.Fatalf("expected nested resources under their prefix, got %v", sections)
+	}
+	if sections := sectionsOf(&Config{GroupingStrategy: GroupByResource}); len(sections["users/orders|Users / Orders"]) != 1 || len(sections["users|Users"]) != 1 {
+		t.Fatalf("expected full resource paths, got %v", sections)
+	}
+	if sections := sectionsOf(&Config{GroupingStrategy: GroupByFile}); len(sections["grouping_test|Grouping Test"]) != 1 || len(sections["users|Users"]) != 1 {
+		t.Fatalf("expected the handler file with a prefix fallback, got %v", sections)
+	}
+
+	custom := sectionsOf(&Config{GroupingFunc: func(route RouteInfo, endpoint *Endpoint) string {
+		if strings.Contains(route.Path, "videos") {
+			return "media"
+		}
+		return ""
+	}})
+	if len(custom["media|Media"]) != 1 || len(custom["orders|Orders"]) != 1 {
+		t.Fatalf("expected the custom func with a strategy fallback, got %v", custom)
+	}
+
+	if err := ValidateConfig(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", GroupingStrategy: "folder"}); err == nil {
+		t.Fatalf("expected unknown strategies to be rejected")
+	}
+}
+
+func TestNavigation_NestedByDefaultFlatOnRequest(t *testing.T) {
+	t.Setenv("BYTEDOCS_UI_FLAT_NAVIGATION", "true")
+	config, err := LoadConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.UIConfig == nil || !config.UIConfig.FlatNavigation {
+		t.Fatalf("expected BYTEDOCS_UI_FLAT_NAVIGATION to enable flat navigation")
+	}
+
+	docs := New(config)
+	docs.AddRoute("GET", "/api/v1/users/{id}/orders/{orderId}/items", nil)
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", config.DocsPath, nil))
+	if !strings.Contains(rec.Body.String(), `"flatNavigation":true`) {
+		t.Fatalf("expected the flag in the page config")
+	}
+
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", assetURL(config.DocsPath, "docs.js"), nil))
+	if script := rec.Body.String(); !strings.Contains(script, "function buildNavigationTree") || !strings.Contains(script, "config.uiConfig.flatNavigation") {
+		t.Fatalf("expected the nested navigation in the UI script")
+	}
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Guide is a markdown page listed above the endpoints, e.g. an
+// authentication guide, pagination conventions or a changelog
+type Guide struct {
+	Slug    string `json:"slug"` // derived from Title when empty
+	Title   string `json:"title"`
+	Content string `json:"content"` // markdown
+}
+
+var (
+	guideOrderPrefix = regexp.MustCompile(`^\d+[-_.]`)
+	guideSlugInvalid = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// loadGuides returns Config.Guides followed by the *.md files of
+// Config.GuidesDir in file name order
+func loadGuides(config *Config) ([]Guide, error) {
+	guides := make([]Guide, 0, len(config.Guides))
+	for _, guide := range config.Guides {
+		if guide.Slug == "" {
+			guide.Slug = guideSlug(guide.Title)
+		}
+		guides = append(guides, guide)
+	}
+
+	if config.GuidesDir != "" {
+		files, err := filepath.Glob(filepath.Join(config.GuidesDir, "*.md"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list guides: %w", err)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read guide %s: %w", file, err)
+			}
+			guides = append(guides, guideFromMarkdown(filepath.Base(file), string(content)))
+		}
+	}
+
+	seen := make(map[string]bool, len(guides))
+	for _, guide := range guides {
+		if guide.Slug == "" {
+			return nil, fmt.Errorf("guide %q needs a slug or a title", guide.Title)
+		}
+		if seen[guide.Slug] {
+			return nil, fmt.Errorf("duplicate guide slug %q", guide.Slug)
+		}
+		seen[guide.Slug] = true
+	}
+	return guides, nil
+}
+
+// guideFromMarkdown builds a guide from a file: "01-getting-started.md" gets
+// the slug "getting-started", its title is the first "# " heading
+func guideFromMarkdown(fileName, content string) Guide {
+	name := guideOrderPrefix.ReplaceAllString(strings.TrimSuffix(fileName, filepath.Ext(fileName)), "")
+	guide := Guide{Slug: guideSlug(name), Content: content}
+
+	for _, line := range strings.Split(content, "\n") {
+		if title, found := strings.CutPrefix(strings.TrimSpace(line), "# "); found {
+			guide.Title = strings.TrimSpace(title)
+			break
+		}
+	}
+	if guide.Title == "" {
+		title := strings.Join(strings.Fields(strings.NewReplacer("-", " ", "_", " ").Replace(name)), " ")
+		if title != "" {
+			title = strings.ToUpper(title[:1]) + title[1:]
+		}
+		guide.Title = title
+	}
+	return guide
+}
+
+// guideSlug lowercases text and joins its words with dashes
+func guideSlug(text string) string {
+	return strings.Trim(guideSlugInvalid.ReplaceAllString(strings.ToLower(text), "-"), "-")
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGuides_FromConfigAndDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "02-pagination.md"), []byte("# Pagination Conventions\n\nUse `?page=`."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "01-authentication.md"), []byte("Send a bearer token."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", BaseURL: "http://localhost:8080", GuidesDir: dir,
+		Guides: []Guide{{Title: "Getting Started", Content: "# Getting Started"}}}
+	if err := ValidateConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	docs := New(config)
+	docs.AddRoute("GET", "/users", nil)
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/api-data.json", nil))
+	var documentation Documentation
+	if err := json.Unmarshal(rec.Body.Bytes(), &documentation); err != nil {
+		t.Fatal(err)
+	}
+	got := make([]string, 0, len(documentation.Guides))
+	for _, guide := range documentation.Guides {
+		got = append(got, guide.Slug+"="+guide.Title)
+	}
+	want := "getting-started=Getting Started,authentication=Authentication,pagination=Pagination Conventions"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("expected config guides first, then files in name order, got %v", got)
+	}
+
+	config.Guides = append(config.Guides, Guide{Slug: "pagination", Title: "Again"})
+	if err := docs.Generate(); err == nil || !strings.Contains(err.Error(), "duplicate guide slug") {
+		t.Fatalf("expected duplicate slugs to fail, got %v", err)
+	}
+	if err := ValidateConfig(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", GuidesDir: filepath.Join(dir, "missing")}); err == nil {
+		t.Fatal("expected a missing guides dir to be rejected")
+	}
+}
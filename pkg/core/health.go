@@ -0,0 +1,200 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Health statuses of HealthReport and its parts
+const (
+	HealthOK       = "ok"
+	HealthPending  = "pending"  // the first analysis is still running
+	HealthDegraded = "degraded" // the docs are served, but the AI client or a federated service fails
+	HealthError    = "error"
+	HealthUnknown  = "unknown" // the AI client was not used yet
+)
+
+// HealthReport is the state of the docs subsystem, served at <docs path>/healthz
+type HealthReport struct {
+	Status      string             `json:"status"`   // HealthOK, HealthPending or HealthDegraded
+	Analysis    string             `json:"analysis"` // HealthPending or "complete"
+	Endpoints   int                `json:"endpoints"`
+	Sections    int                `json:"sections"`
+	Schemas     int                `json:"schemas"`
+	Warnings    int                `json:"warnings"` // endpoints whose handlers could not be analyzed, see Documentation.Warnings
+	GeneratedAt time.Time          `json:"generatedAt"`
+	AI          *AIHealth          `json:"ai,omitempty"`         // nil when AI is disabled; served only with Config.HealthDetails
+	Federation  []FederationHealth `json:"federation,omitempty"` // one entry per federated service; served only with Config.HealthDetails
+}
+
+// AIHealth is the state of the AI client. Checking it would cost tokens, so
+// the status is the outcome of the last chat request.
+type AIHealth struct {
+	Provider string     `json:"provider"`
+	Model    string     `json:"model,omitempty"`
+	Status   string     `json:"status"` // HealthOK, HealthError or HealthUnknown
+	Error    string     `json:"error,omitempty"`
+	LastUsed *time.Time `json:"lastUsed,omitempty"`
+}
+
+// FederationHealth is the state of the last fetch of a federated service
+type FederationHealth struct {
+	Name      string     `json:"name"`
+	Status    string     `json:"status"` // HealthOK, HealthError or HealthPending before the first fetch
+	Error     string     `json:"error,omitempty"`
+	Endpoints int        `json:"endpoints"` // served from the last successful fetch
+	FetchedAt *time.Time `json:"fetchedAt,omitempty"`
+}
+
+// callStatus is the outcome of the last call to a dependency
+type callStatus struct {
+	at        time.Time
+	err       error
+	endpoints int // endpoints a federated service returned
+}
+
+// healthState records the outcome of the last AI chat request and of the
+// last fetch of each federated service. It has its own lock, so health checks
+// answer while a generation holds the docs lock.
+type healthState struct {
+	mu         sync.Mutex
+	ai         callStatus
+	federation map[string]callStatus
+}
+
+// recordAI stores the outcome of an AI call
+func (h *healthState) recordAI(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ai = callStatus{at: time.Now().UTC(), err: err}
+}
+
+// recordFederation stores the outcome of fetching a federated service
+func (h *healthState) recordFederation(service string, endpoints int, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.federation == nil {
+		h.federation = make(map[string]callStatus)
+	}
+	if err != nil {
+		// The routes of the last successful fetch are still served
+		endpoints = h.federation[service].endpoints
+	}
+	h.federation[service] = callStatus{at: time.Now().UTC(), err: err, endpoints: endpoints}
+}
+
+// Health reports whether the first analysis finished, what was generated and
+// the state of the AI client and the federated services. It does not start
+// the analysis.
+func (a *APIDocs) Health() *HealthReport {
+	report := &HealthReport{
+		Status:      HealthOK,
+		Analysis:    "complete",
+		GeneratedAt: time.Unix(a.generatedAt.Load(), 0).UTC(),
+	}
+	if !a.IsReady() {
+		report.Status = HealthPending
+		report.Analysis = HealthPending
+	}
+
+	documentation := a.GetDocumentation()
+	report.Sections = len(documentation.Endpoints)
+	report.Schemas = len(documentation.Schemas)
+	report.Warnings = len(documentation.Warnings)
+	for _, section := range documentation.Endpoints {
+		report.Endpoints += len(section.Endpoints)
+	}
+
+	degraded := false
+	if ai := a.config.AIConfig; ai != nil && ai.Enabled {
+		report.AI = &AIHealth{Provider: ai.Provider, Model: ai.Features.Model, Status: HealthUnknown}
+		switch {
+		case a.llmClient == nil:
+			report.AI.Status = HealthError
+			report.AI.Error = "client not configured"
+			if a.llmErr != nil {
+				report.AI.Error = a.llmErr.Error()
+			}
+		default:
+			report.AI.Model = a.llmClient.GetModel()
+			a.health.mu.Lock()
+			last := a.health.ai
+			a.health.mu.Unlock()
+			if !last.at.IsZero() {
+				report.AI.Status = HealthOK
+				report.AI.LastUsed = &last.at
+				if last.err != nil {
+					report.AI.Status = HealthError
+					report.AI.Error = last.err.Error()
+				}
+			}
+		}
+		degraded = report.AI.Status == HealthError
+	}
+
+	if federation := a.config.Federation; federation != nil {
+		a.health.mu.Lock()
+		for _, service := range federation.Services {
+			health := FederationHealth{Name: service.Name, Status: HealthPending}
+			if fetch, ok := a.health.federation[service.Name]; ok {
+				at := fetch.at
+				health.FetchedAt = &at
+				health.Status = HealthOK
+				health.Endpoints = fetch.endpoints
+				if fetch.err != nil {
+					health.Status = HealthError
+					health.Error = fetch.err.Error()
+					degraded = true
+				}
+			}
+			report.Federation = append(report.Federation, health)
+		}
+		a.health.mu.Unlock()
+	}
+
+	if degraded && report.Status == HealthOK {
+		report.Status = HealthDegraded
+	}
+	return report
+}
+
+// serveHealth handles /healthz, always 200 while the process serves, and
+// /readyz, 503 until the first analysis finished. Orchestrators cannot sign
+// in, so both bypass the docs login. /readyz starts the analysis, since it
+// otherwise waits for the first docs request.
+func (a *APIDocs) serveHealth(w http.ResponseWriter, r *http.Request, ready bool) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := http.StatusOK
+	var body interface{}
+	if ready {
+		go a.build()
+		readiness := map[string]string{"status": HealthOK}
+		if !a.IsReady() {
+			readiness["status"] = HealthPending
+			status = http.StatusServiceUnavailable
+		}
+		body = readiness
+	} else {
+		// Anyone can reach /healthz; provider names, error messages and
+		// service URLs are only reported when HealthDetails opts in
+		report := a.Health()
+		if !a.config.HealthDetails {
+			report.AI, report.Federation = nil, nil
+		}
+		body = report
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if r.Method == http.MethodGet {
+		json.NewEncoder(w).Encode(body)
+	}
+}
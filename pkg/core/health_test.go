@@ -0,0 +1,71 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHealth_ReportsAnalysisEndpointsAndAIClient(t *testing.T) {
+	docs := New(&Config{
+		DocsPath:   "/docs",
+		AuthConfig: &AuthConfig{Enabled: true, Type: "basic", Username: "admin", Password: "hunter2-password"},
+		AIConfig:   &AIConfig{Enabled: true, Provider: "no-such-provider"},
+	})
+	docs.AddRoute("GET", "/users", nil)
+	docs.AddRoute("POST", "/users", nil)
+
+	// Orchestrators probe without docs credentials, before the first analysis
+	health := docs.Health()
+	if health.Status != HealthPending || health.Analysis != HealthPending || health.Endpoints != 0 {
+		t.Fatalf("expected a pending analysis before the first build, got %+v", health)
+	}
+
+	if err := docs.Warmup(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	readiness := httptest.NewRecorder()
+	docs.ServeHTTP(readiness, httptest.NewRequest("GET", "/docs/readyz", nil))
+	if readiness.Code != http.StatusOK {
+		t.Fatalf("expected /readyz to pass after warmup without login, got %d", readiness.Code)
+	}
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/healthz", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("expected an uncached /healthz without login, got %d %v", rec.Code, rec.Header())
+	}
+	var report HealthReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Analysis != "complete" || report.Endpoints != 2 || report.Sections != 1 || report.GeneratedAt.IsZero() {
+		t.Fatalf("expected the generated endpoints to be counted, got %+v", report)
+	}
+	if report.Status != HealthDegraded || report.AI != nil || strings.Contains(rec.Body.String(), "no-such-provider") {
+		t.Fatalf("expected the degraded status without AI details for anonymous probes, got %s", rec.Body.String())
+	}
+	if health := docs.Health(); health.AI == nil || health.AI.Status != HealthError || !strings.Contains(health.AI.Error, "no-such-provider") {
+		t.Fatalf("expected the missing AI client in the full report, got %+v", health.AI)
+	}
+
+	docs.config.HealthDetails = true
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/healthz", nil))
+	report = HealthReport{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.AI == nil || report.AI.Status != HealthError || !strings.Contains(report.AI.Error, "no-such-provider") {
+		t.Fatalf("expected HealthDetails to serve the AI client, got %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("POST", "/docs/healthz", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected /healthz to be read-only, got %d", rec.Code)
+	}
+}
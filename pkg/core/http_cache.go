@@ -0,0 +1,140 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+// minCompressSize is the smallest body worth compressing
+const minCompressSize = 1024
+
+// writeCacheable writes a spec or docs payload with a weak ETag and
+// Last-Modified header, answering 304 when the client already has it and
+// gzip-compressing the body when the client accepts it. The Content-Type
+// header must be set by the caller.
+func (a *APIDocs) writeCacheable(w http.ResponseWriter, r *http.Request, body []byte) {
+	sum := sha256.Sum256(body)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	lastModified := time.Unix(a.generatedAt.Load(), 0).UTC()
+
+	header := w.Header()
+	header.Set("ETag", etag)
+	header.Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	header.Set("Cache-Control", "no-cache")
+	header.Add("Vary", "Accept-Encoding")
+
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if encoding := negotiateEncoding(r, "gzip"); encoding != "" && len(body) >= minCompressSize {
+		if compressed, err := compressBody(body, encoding, false); err == nil {
+			header.Set("Content-Encoding", encoding)
+			body = compressed
+		}
+	}
+
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	w.Write(body)
+}
+
+// notModified evaluates If-None-Match, falling back to If-Modified-Since only
+// when no ETag condition was sent
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r == nil {
+		return false
+	}
+
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		return etagMatches(match, etag)
+	}
+
+	if since := r.Header.Get("If-Modified-Since"); since != "" {
+		if t, err := http.ParseTime(since); err == nil {
+			return !lastModified.After(t)
+		}
+	}
+
+	return false
+}
+
+// etagMatches reports whether an If-None-Match header matches etag, using
+// the weak comparison that GET and HEAD requests allow
+func etagMatches(match, etag string) bool {
+	for _, candidate := range strings.Split(match, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiateEncoding picks the first of the supported content encodings with
+// the highest weight in Accept-Encoding, or "" for identity
+func negotiateEncoding(r *http.Request, supported ...string) string {
+	if r == nil {
+		return ""
+	}
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		weights[strings.ToLower(strings.TrimSpace(coding))] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, coding := range supported {
+		if q := weights[coding]; q > bestQ {
+			best, bestQ = coding, q
+		}
+	}
+	return best
+}
+
+// compressBody encodes body as "br" or "gzip". Static content uses the best
+// compression since it is encoded once; dynamic responses favor speed.
+func compressBody(body []byte, encoding string, static bool) ([]byte, error) {
+	var compressed bytes.Buffer
+	var writer io.WriteCloser
+	switch encoding {
+	case "br":
+		level := 4
+		if static {
+			level = brotli.BestCompression
+		}
+		writer = brotli.NewWriterLevel(&compressed, level)
+	case "gzip":
+		level := gzip.BestSpeed
+		if static {
+			level = gzip.BestCompression
+		}
+		writer, _ = gzip.NewWriterLevel(&compressed, level)
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return compressed.Bytes(), nil
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSpecEndpoints_ETagAndGzip(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs"})
+	for i := 0; i < 20; i++ {
+		docs.AddRoute("GET", fmt.Sprintf("/items/%d", i), nil)
+	}
+
+	for _, path := range []string{"/docs/openapi.json", "/docs/openapi.yaml", "/docs/api-data.json"} {
+		rec := httptest.NewRecorder()
+		docs.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		etag := rec.Header().Get("ETag")
+		if rec.Code != 200 || etag == "" || rec.Header().Get("Last-Modified") == "" {
+			t.Fatalf("%s: expected 200 with validators, got %d %v", path, rec.Code, rec.Header())
+		}
+		plain := rec.Body.String()
+
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("If-None-Match", etag)
+		rec = httptest.NewRecorder()
+		docs.ServeHTTP(rec, req)
+		if rec.Code != 304 || rec.Body.Len() != 0 {
+			t.Fatalf("%s: expected 304 for matching ETag, got %d", path, rec.Code)
+		}
+
+		req = httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept-Encoding", "br, gzip")
+		rec = httptest.NewRecorder()
+		docs.ServeHTTP(rec, req)
+		if rec.Header().Get("Content-Encoding") != "gzip" {
+			t.Fatalf("%s: expected gzip encoding, got %v", path, rec.Header())
+		}
+		reader, err := gzip.NewReader(rec.Body)
+		if err != nil {
+			t.Fatalf("%s: invalid gzip body: %v", path, err)
+		}
+		decoded, _ := io.ReadAll(reader)
+		if string(decoded) != plain {
+			t.Fatalf("%s: gzip body differs from plain body", path)
+		}
+	}
+}
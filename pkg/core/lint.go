@@ -0,0 +1,258 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Lint severities, from most to least severe
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+	LintInfo    = "info"
+)
+
+// Built-in lint rules
+const (
+	LintRuleMissingSummary       = "operation-summary"
+	LintRuleMissingDescription   = "operation-description"
+	LintRuleMissing4xx           = "operation-4xx-response"
+	LintRuleParameterDescription = "parameter-description"
+	LintRuleUntypedParameter     = "parameter-type"
+	LintRuleParameterNaming      = "parameter-naming"
+	LintRulePathNaming           = "path-kebab-case"
+	LintRuleDuplicateOperationID = "operation-id-unique"
+)
+
+// LintFinding is one rule violation of a spec
+type LintFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Method   string `json:"method,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Location string `json:"location"` // JSON pointer into the spec, e.g. "/paths/~1users/get/parameters/0"
+	Message  string `json:"message"`
+}
+
+// LintReport lists the findings of LintSpec. Passed is false when any finding
+// is an error, so CI can gate on it.
+type LintReport struct {
+	Passed   bool          `json:"passed"`
+	Errors   int           `json:"errors"`
+	Warnings int           `json:"warnings"`
+	Infos    int           `json:"infos"`
+	Findings []LintFinding `json:"findings"`
+}
+
+var lintSeverityRank = map[string]int{LintError: 0, LintWarning: 1, LintInfo: 2}
+
+var (
+	kebabSegment  = regexp.MustCompile(`^[a-z0-9]+([-.][a-z0-9]+)*$`)
+	parameterName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+)
+
+// lintMethods are the operation keys of an OpenAPI path item, in report order
+var lintMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// LintSpec checks an OpenAPI document against the built-in rule set: missing
+// summaries and descriptions, untyped or undescribed parameters, operations
+// without 4xx responses, path and parameter naming, and duplicate operationIds.
+// Findings are ordered by path and method.
+func LintSpec(spec map[string]interface{}) (*LintReport, error) {
+	normalized, err := normalizeSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &LintReport{Findings: make([]LintFinding, 0)}
+	add := func(finding LintFinding) {
+		report.Findings = append(report.Findings, finding)
+	}
+
+	paths, _ := normalized["paths"].(map[string]interface{})
+	pathKeys := make([]string, 0, len(paths))
+	for path := range paths {
+		pathKeys = append(pathKeys, path)
+	}
+	sort.Strings(pathKeys)
+
+	components, _ := normalized["components"].(map[string]interface{})
+	shared, _ := components["parameters"].(map[string]interface{})
+
+	operationIDs := make(map[string]string)
+	for _, path := range pathKeys {
+		pathItem, _ := paths[path].(map[string]interface{})
+		pathPointer := "/paths/" + jsonPointerEscape(path)
+
+		for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+			if segment == "" || strings.HasPrefix(segment, "{") || kebabSegment.MatchString(segment) {
+				continue
+			}
+			add(LintFinding{Rule: LintRulePathNaming, Severity: LintWarning, Path: path, Location: pathPointer,
+				Message: fmt.Sprintf("path segment %q should be lowercase kebab-case", segment)})
+			break
+		}
+
+		for _, method := range lintMethods {
+			operation, ok := pathItem[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			lintOperation(operation, strings.ToUpper(method), path, pathPointer+"/"+method, shared, operationIDs, add)
+		}
+	}
+
+	for _, finding := range report.Findings {
+		switch finding.Severity {
+		case LintError:
+			report.Errors++
+		case LintWarning:
+			report.Warnings++
+		default:
+			report.Infos++
+		}
+	}
+	report.Passed = report.Errors == 0
+	return report, nil
+}
+
+// lintOperation applies the operation and parameter rules to one operation
+func lintOperation(operation map[string]interface{}, method, path, pointer string, shared map[string]interface{}, operationIDs map[string]string, add func(LintFinding)) {
+	finding := func(rule, severity, location, message string) {
+		add(LintFinding{Rule: rule, Severity: severity, Method: method, Path: path, Location: location, Message: message})
+	}
+
+	if summary, _ := operation["summary"].(string); strings.TrimSpace(summary) == "" {
+		finding(LintRuleMissingSummary, LintWarning, pointer, "operation has no summary")
+	}
+	if description, _ := operation["description"].(string); strings.TrimSpace(description) == "" {
+		finding(LintRuleMissingDescription, LintInfo, pointer, "operation has no description")
+	}
+
+	if id, _ := operation["operationId"].(string); id != "" {
+		if first, exists := operationIDs[id]; exists {
+			finding(LintRuleDuplicateOperationID, LintError, pointer+"/operationId",
+				fmt.Sprintf("operationId %q is already used by %s", id, first))
+		} else {
+			operationIDs[id] = method + " " + path
+		}
+	}
+
+	responses, _ := operation["responses"].(map[string]interface{})
+	has4xx := false
+	for status := range responses {
+		if strings.HasPrefix(status, "4") || strings.EqualFold(status, "default") {
+			has4xx = true
+			break
+		}
+	}
+	if !has4xx {
+		finding(LintRuleMissing4xx, LintWarning, pointer+"/responses", "operation documents no 4xx response")
+	}
+
+	parameters, _ := operation["parameters"].([]interface{})
+	for i, value := range parameters {
+		parameter, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// Shared parameters are checked where they are used
+		if ref, _ := parameter["$ref"].(string); ref != "" {
+			if parameter, ok = shared[strings.TrimPrefix(ref, "#/components/parameters/")].(map[string]interface{}); !ok {
+				continue
+			}
+		}
+		location := fmt.Sprintf("%s/parameters/%d", pointer, i)
+		name, _ := parameter["name"].(string)
+		in, _ := parameter["in"].(string)
+
+		if description, _ := parameter["description"].(string); strings.TrimSpace(description) == "" {
+			finding(LintRuleParameterDescription, LintWarning, location, fmt.Sprintf("%s parameter %q has no description", in, name))
+		}
+		schema, _ := parameter["schema"].(map[string]interface{})
+		if schemaType, _ := schema["type"].(string); schemaType == "" && schema["$ref"] == nil {
+			finding(LintRuleUntypedParameter, LintError, location, fmt.Sprintf("%s parameter %q has no type", in, name))
+		}
+		if in != "header" && in != "cookie" && !parameterName.MatchString(name) {
+			finding(LintRuleParameterNaming, LintWarning, location,
+				fmt.Sprintf("%s parameter %q should be camelCase or snake_case", in, name))
+		}
+	}
+}
+
+// normalizeSpec turns a spec built from typed Go values into plain JSON maps
+func normalizeSpec(spec map[string]interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode spec: %w", err)
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return nil, fmt.Errorf("failed to decode spec: %w", err)
+	}
+	return normalized, nil
+}
+
+// jsonPointerEscape escapes a key for use in a JSON pointer (RFC 6901)
+func jsonPointerEscape(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// filterLintReport keeps findings at least as severe as minSeverity
+func filterLintReport(report *LintReport, minSeverity string) *LintReport {
+	limit, ok := lintSeverityRank[minSeverity]
+	if !ok {
+		return report
+	}
+	filtered := *report
+	filtered.Findings = make([]LintFinding, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		if lintSeverityRank[finding.Severity] <= limit {
+			filtered.Findings = append(filtered.Findings, finding)
+		}
+	}
+	return &filtered
+}
+
+// Lint runs LintSpec against the generated OpenAPI document
+func (a *APIDocs) Lint() (*LintReport, error) {
+	spec, err := a.GetOpenAPIJSON()
+	if err != nil {
+		return nil, err
+	}
+	return LintSpec(spec)
+}
+
+// serveLint reports the lint findings of the generated spec. ?severity=warning
+// hides info findings and ?severity=error keeps errors only; the counts and
+// passed always cover every finding.
+func (a *APIDocs) serveLint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	severity := r.URL.Query().Get("severity")
+	if _, ok := lintSeverityRank[severity]; severity != "" && !ok {
+		http.Error(w, fmt.Sprintf("invalid severity %q, use error, warning or info", severity), http.StatusBadRequest)
+		return
+	}
+
+	report, err := a.Lint()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to lint OpenAPI spec: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	body, err := json.Marshal(filterLintReport(report, severity))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode lint report: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	a.writeCacheable(w, r, append(body, '\n'))
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLint_ReportsFindingsForGeneratedSpec(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs"})
+	docs.AddRoute("GET", "/userAccounts/{id}", nil,
+		WithSummary("Get an account"),
+		WithDescription("Returns one account"),
+		WithParam("page-size", "query", "int", false, ""),
+		WithResponse(http.StatusOK, struct{}{}))
+
+	report, err := docs.Lint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := make(map[string]bool)
+	for _, finding := range report.Findings {
+		rules[finding.Rule] = true
+	}
+	for _, rule := range []string{LintRulePathNaming, LintRuleMissing4xx, LintRuleParameterDescription, LintRuleParameterNaming} {
+		if !rules[rule] {
+			t.Fatalf("expected a %s finding, got %+v", rule, report.Findings)
+		}
+	}
+	if rules[LintRuleMissingSummary] || !report.Passed {
+		t.Fatalf("expected no summary finding and no errors, got %+v", report)
+	}
+
+	// An external spec with untyped parameters and duplicate operationIds fails
+	external, err := LintSpec(map[string]interface{}{"paths": map[string]interface{}{
+		"/a": map[string]interface{}{"get": map[string]interface{}{"operationId": "op", "parameters": []interface{}{
+			map[string]interface{}{"name": "q", "in": "query", "description": "Query"},
+		}}},
+		"/b": map[string]interface{}{"get": map[string]interface{}{"operationId": "op"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if external.Passed || external.Errors != 2 {
+		t.Fatalf("expected the untyped param and duplicate operationId as errors, got %+v", external)
+	}
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/lint?severity=error", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"findings":[]`) || !strings.Contains(rec.Body.String(), `"passed":true`) {
+		t.Fatalf("expected an error-only lint report, got %d %s", rec.Code, rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/lint?severity=fatal", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected unknown severities to be rejected, got %d", rec.Code)
+	}
+}
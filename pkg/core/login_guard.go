@@ -0,0 +1,156 @@
+package core
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultIPBanMaxAttempts = 5
+	defaultIPBanDuration    = 60 // minutes
+	loginGuardCleanupPeriod = 10 * time.Minute
+)
+
+// loginGuard tracks failed authentication attempts per client IP and bans
+// clients that reach IPBanMaxAttempts. One guard is shared by every auth type
+// of a middleware, so session, basic, api_key and bearer auth ban alike.
+type loginGuard struct {
+	enabled     bool
+	maxAttempts int
+	banDuration time.Duration
+	whitelist   []string // IPs or CIDR ranges that are never banned
+
+	attempts    map[string]failedAttempts
+	bans        map[string]time.Time // IP -> ban expiry
+	lastCleanup time.Time
+	now         func() time.Time
+	mutex       sync.Mutex
+}
+
+// failedAttempts counts failures since the first one; the count starts over
+// once the ban duration passed without reaching the limit
+type failedAttempts struct {
+	count int
+	first time.Time
+}
+
+// newLoginGuard reads the IP ban settings of config, using 5 attempts and a
+// 60 minute ban when they are not set
+func newLoginGuard(config *AuthConfig) *loginGuard {
+	guard := &loginGuard{
+		enabled:     config.IPBanEnabled,
+		maxAttempts: config.IPBanMaxAttempts,
+		banDuration: time.Duration(config.IPBanDuration) * time.Minute,
+		whitelist:   config.AdminWhitelistIPs,
+		attempts:    make(map[string]failedAttempts),
+		bans:        make(map[string]time.Time),
+		now:         time.Now,
+	}
+	if guard.maxAttempts <= 0 {
+		guard.maxAttempts = defaultIPBanMaxAttempts
+	}
+	if guard.banDuration <= 0 {
+		guard.banDuration = defaultIPBanDuration * time.Minute
+	}
+	guard.lastCleanup = guard.now()
+	return guard
+}
+
+// isWhitelisted reports whether ip matches an address or CIDR range of the whitelist
+func (g *loginGuard) isWhitelisted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	for _, entry := range g.whitelist {
+		entry = strings.TrimSpace(entry)
+		if entry == ip {
+			return true
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil && parsed != nil && network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// banExpiry returns when the ban of ip ends, or false when it is not banned
+func (g *loginGuard) banExpiry(ip string) (time.Time, bool) {
+	if !g.enabled || g.isWhitelisted(ip) {
+		return time.Time{}, false
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.cleanupLocked()
+
+	expiry, banned := g.bans[ip]
+	if banned && !g.now().Before(expiry) {
+		delete(g.bans, ip)
+		return time.Time{}, false
+	}
+	return expiry, banned
+}
+
+// isBanned reports whether ip is currently banned
+func (g *loginGuard) isBanned(ip string) bool {
+	_, banned := g.banExpiry(ip)
+	return banned
+}
+
+// recordFailure counts a failed attempt of ip. It returns whether ip is now
+// banned and how many attempts it has left otherwise.
+func (g *loginGuard) recordFailure(ip string) (banned bool, remaining int) {
+	if !g.enabled {
+		return false, g.maxAttempts
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	now := g.now()
+	attempts := g.attempts[ip]
+	if attempts.count == 0 || now.Sub(attempts.first) > g.banDuration {
+		attempts = failedAttempts{first: now}
+	}
+	attempts.count++
+
+	if attempts.count < g.maxAttempts {
+		g.attempts[ip] = attempts
+		return false, g.maxAttempts - attempts.count
+	}
+
+	// Whitelisted clients start over instead of being banned
+	delete(g.attempts, ip)
+	if g.isWhitelisted(ip) {
+		return false, g.maxAttempts
+	}
+	g.bans[ip] = now.Add(g.banDuration)
+	return true, 0
+}
+
+// recordSuccess forgets the failed attempts of ip
+func (g *loginGuard) recordSuccess(ip string) {
+	g.mutex.Lock()
+	delete(g.attempts, ip)
+	g.mutex.Unlock()
+}
+
+// cleanupLocked drops expired bans and stale attempts every cleanup period
+func (g *loginGuard) cleanupLocked() {
+	now := g.now()
+	if now.Sub(g.lastCleanup) < loginGuardCleanupPeriod {
+		return
+	}
+	g.lastCleanup = now
+
+	for ip, expiry := range g.bans {
+		if !now.Before(expiry) {
+			delete(g.bans, ip)
+		}
+	}
+	for ip, attempts := range g.attempts {
+		if now.Sub(attempts.first) > g.banDuration {
+			delete(g.attempts, ip)
+		}
+	}
+}
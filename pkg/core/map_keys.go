@@ -0,0 +1,61 @@
+package core
+
+import "strings"
+
+// Patterns of the keys of integer-keyed maps, which JSON encodes as decimal strings
+const (
+	intKeyPattern  = "^-?[0-9]+$"
+	uintKeyPattern = "^[0-9]+$"
+)
+
+// MapKeyHints annotates a map schema with the Go type of its keys, for key
+// types other than string, and the pattern integer keys follow. keyType is the
+// key type as written, such as "UserID" or "int64", and keyKind its underlying
+// kind when known. It returns an example key that fits the hints.
+func MapKeyHints(schema map[string]interface{}, keyType, keyKind string) string {
+	if schema == nil {
+		return "key"
+	}
+	if keyType != "" && keyType != "string" {
+		schema["x-additionalPropertiesName"] = keyType
+	}
+
+	pattern := ""
+	switch keyKind {
+	case "int", "int8", "int16", "int32", "int64":
+		pattern = intKeyPattern
+	case "uint", "uint8", "uint16", "uint32", "uint64", "uintptr":
+		pattern = uintKeyPattern
+	}
+	if pattern == "" {
+		return "key"
+	}
+	setKeyPattern(schema, pattern)
+	return "1"
+}
+
+// MapKeyTags applies the keydescription:"..." and keypattern:"..." struct
+// tags of a map field to its schema; tag looks up a struct tag of the field
+func MapKeyTags(schema map[string]interface{}, tag func(key string) string) {
+	if schema == nil || schema["additionalProperties"] == nil {
+		return
+	}
+	if description := strings.TrimSpace(tag("keydescription")); description != "" {
+		schema["x-key-description"] = description
+	}
+	if pattern := strings.TrimSpace(tag("keypattern")); pattern != "" {
+		// A declared pattern replaces the one derived from the key type
+		delete(schema, "x-patternProperties")
+		setKeyPattern(schema, pattern)
+	}
+}
+
+// setKeyPattern documents that the keys of a map follow pattern, as an
+// x-patternProperties hint since OpenAPI 3.0 schemas lack patternProperties
+func setKeyPattern(schema map[string]interface{}, pattern string) {
+	value := schema["additionalProperties"]
+	if value == nil {
+		value = map[string]interface{}{}
+	}
+	schema["x-patternProperties"] = map[string]interface{}{pattern: value}
+}
@@ -0,0 +1,38 @@
+package core
+
+import "testing"
+
+func TestMapKeys_TypedKeysAndPatterns(t *testing.T) {
+	type userID string
+	type profile struct {
+		Name string `json:"name"`
+	}
+	type directory struct {
+		Profiles  map[userID]profile `json:"profiles" keydescription:"ID of the user"`
+		Shards    map[uint16]string  `json:"shards"`
+		Countries map[string]int     `json:"countries" keypattern:"^[A-Z]{2}$"`
+	}
+	schema, example := SchemaFromValue(directory{})
+	properties := schema.(map[string]interface{})["properties"].(map[string]interface{})
+
+	profiles := properties["profiles"].(map[string]interface{})
+	if profiles["x-additionalPropertiesName"] != "userID" || profiles["x-key-description"] != "ID of the user" {
+		t.Fatalf("expected the key type and description, got %+v", profiles)
+	}
+	if value := profiles["additionalProperties"].(map[string]interface{}); value["properties"] == nil {
+		t.Fatalf("expected the value schema as additionalProperties, got %+v", value)
+	}
+
+	shards := properties["shards"].(map[string]interface{})
+	if _, ok := shards["x-patternProperties"].(map[string]interface{})["^[0-9]+$"]; !ok {
+		t.Fatalf("expected integer keys to document their pattern, got %+v", shards)
+	}
+	if shardExample := example.(map[string]interface{})["shards"].(map[string]interface{}); shardExample["1"] == nil {
+		t.Fatalf("expected an integer example key, got %+v", shardExample)
+	}
+
+	countries := properties["countries"].(map[string]interface{})
+	if _, ok := countries["x-patternProperties"].(map[string]interface{})["^[A-Z]{2}$"]; !ok || countries["x-additionalPropertiesName"] != nil {
+		t.Fatalf("expected the keypattern tag as a pattern hint, got %+v", countries)
+	}
+}
@@ -0,0 +1,355 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// notifyTimeout bounds the webhook calls of one notification
+const notifyTimeout = 10 * time.Second
+
+// NotificationConfig posts a summary of endpoint changes to chat webhooks
+// whenever regenerating the documentation adds, removes or breaks endpoints,
+// e.g. after Refresh or routes registered at runtime
+type NotificationConfig struct {
+	SlackWebhookURL string      // Slack incoming webhook URL
+	TeamsWebhookURL string      // Microsoft Teams incoming webhook URL
+	Notifiers       []Notifier  // Further destinations, after Slack and Teams
+	BreakingOnly    bool        // Notify only about removed endpoints and breaking changes
+	StateFile       string      // Endpoint snapshot kept across restarts, so changes between deploys are reported
+	DocsURL         string      // Link added to messages, e.g. "https://api.example.com/docs"
+	OnError         func(error) // Called when a notifier fails; errors are dropped without it
+}
+
+// Notifier delivers the changes found when the documentation was regenerated
+type Notifier interface {
+	Notify(ctx context.Context, changes SpecChanges) error
+}
+
+// NotifierFunc adapts a function to Notifier
+type NotifierFunc func(ctx context.Context, changes SpecChanges) error
+
+// Notify calls f(ctx, changes)
+func (f NotifierFunc) Notify(ctx context.Context, changes SpecChanges) error {
+	return f(ctx, changes)
+}
+
+// SpecChanges are the endpoint differences between two generations of the documentation
+type SpecChanges struct {
+	Title    string
+	Version  string
+	DocsURL  string
+	Added    []EndpointChange
+	Removed  []EndpointChange // breaking: clients calling them fail
+	Breaking []EndpointChange // kept endpoints that old clients can no longer call as before
+}
+
+// EndpointChange is one added, removed or changed endpoint
+type EndpointChange struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Detail string `json:"detail,omitempty"` // summary of an added endpoint, reason of a breaking change
+}
+
+// Summary counts the changes, e.g. "2 endpoints added, 1 removed, 1 breaking change"
+func (c SpecChanges) Summary() string {
+	var parts []string
+	if n := len(c.Added); n == 1 {
+		parts = append(parts, "1 endpoint added")
+	} else if n > 1 {
+		parts = append(parts, fmt.Sprintf("%d endpoints added", n))
+	}
+	if n := len(c.Removed); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d removed", n))
+	}
+	if n := len(c.Breaking); n == 1 {
+		parts = append(parts, "1 breaking change")
+	} else if n > 1 {
+		parts = append(parts, fmt.Sprintf("%d breaking changes", n))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (c SpecChanges) empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Breaking) == 0
+}
+
+// markdown renders the changes as a headline and bulleted sections; bold is
+// the chat's bold marker and newline its line break
+func (c SpecChanges) markdown(bold, newline string) string {
+	title := strings.TrimSpace(c.Title + " " + c.Version)
+	lines := []string{fmt.Sprintf("%s%s%s: %s", bold, title, bold, c.Summary())}
+	for _, section := range []struct {
+		name    string
+		changes []EndpointChange
+	}{{"Added", c.Added}, {"Removed", c.Removed}, {"Breaking", c.Breaking}} {
+		if len(section.changes) == 0 {
+			continue
+		}
+		lines = append(lines, bold+section.name+bold)
+		for _, change := range section.changes {
+			line := fmt.Sprintf("• `%s %s`", change.Method, change.Path)
+			if change.Detail != "" {
+				line += " " + change.Detail
+			}
+			lines = append(lines, line)
+		}
+	}
+	if c.DocsURL != "" {
+		lines = append(lines, c.DocsURL)
+	}
+	return strings.Join(lines, newline)
+}
+
+// SlackNotifier posts changes to a Slack incoming webhook
+type SlackNotifier struct {
+	WebhookURL string
+	Client     *http.Client // defaults to http.DefaultClient
+}
+
+// Notify posts the changes as a mrkdwn message
+func (s *SlackNotifier) Notify(ctx context.Context, changes SpecChanges) error {
+	return postWebhook(ctx, s.Client, s.WebhookURL, map[string]interface{}{
+		"text": changes.markdown("*", "\n"),
+	})
+}
+
+// TeamsNotifier posts changes to a Microsoft Teams incoming webhook
+type TeamsNotifier struct {
+	WebhookURL string
+	Client     *http.Client // defaults to http.DefaultClient
+}
+
+// Notify posts the changes as a message card
+func (t *TeamsNotifier) Notify(ctx context.Context, changes SpecChanges) error {
+	return postWebhook(ctx, t.Client, t.WebhookURL, map[string]interface{}{
+		"@type":    "MessageCard",
+		"@context": "https://schema.org/extensions",
+		"summary":  changes.Summary(),
+		"text":     changes.markdown("**", "\n\n"),
+	})
+}
+
+// postWebhook sends payload as JSON and fails on a non-2xx response
+func postWebhook(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// endpointSnapshot is what notifications compare of an endpoint
+type endpointSnapshot struct {
+	Summary      string              `json:"summary,omitempty"`
+	Parameters   []parameterSnapshot `json:"parameters,omitempty"`
+	BodyRequired bool                `json:"bodyRequired,omitempty"`
+}
+
+type parameterSnapshot struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Type     string `json:"type,omitempty"`
+	Required bool   `json:"required,omitempty"`
+}
+
+// snapshotDocumentation keys the endpoints of the documentation by "METHOD path"
+func snapshotDocumentation(documentation *Documentation) map[string]endpointSnapshot {
+	snapshot := make(map[string]endpointSnapshot)
+	for _, section := range documentation.Endpoints {
+		for _, endpoint := range section.Endpoints {
+			entry := endpointSnapshot{Summary: endpoint.Summary}
+			for _, param := range endpoint.Parameters {
+				entry.Parameters = append(entry.Parameters, parameterSnapshot{
+					Name:     param.Name,
+					In:       param.In,
+					Type:     normalizeOpenAPIType(param.Type),
+					Required: param.Required || param.In == "path",
+				})
+			}
+			entry.BodyRequired = endpoint.RequestBody != nil && endpoint.RequestBody.Required
+			snapshot[strings.ToUpper(endpoint.Method)+" "+endpoint.Path] = entry
+		}
+	}
+	return snapshot
+}
+
+// diffSnapshots lists added and removed endpoints, and the changes of kept
+// endpoints that break existing clients: a new required parameter, an
+// optional parameter becoming required, a parameter changing its type or a
+// request body becoming required
+func diffSnapshots(previous, current map[string]endpointSnapshot) SpecChanges {
+	var changes SpecChanges
+	for _, key := range sortedSnapshotKeys(current) {
+		method, path, _ := strings.Cut(key, " ")
+		before, existed := previous[key]
+		after := current[key]
+		if !existed {
+			changes.Added = append(changes.Added, EndpointChange{Method: method, Path: path, Detail: after.Summary})
+			continue
+		}
+
+		known := make(map[string]parameterSnapshot, len(before.Parameters))
+		for _, param := range before.Parameters {
+			known[param.Name+":"+param.In] = param
+		}
+		for _, param := range after.Parameters {
+			old, ok := known[param.Name+":"+param.In]
+			var reason string
+			switch {
+			case !ok && param.Required:
+				reason = fmt.Sprintf("new required %s parameter %q", param.In, param.Name)
+			case ok && param.Required && !old.Required:
+				reason = fmt.Sprintf("%s parameter %q is now required", param.In, param.Name)
+			case ok && old.Type != "" && param.Type != "" && old.Type != param.Type:
+				reason = fmt.Sprintf("%s parameter %q changed from %s to %s", param.In, param.Name, old.Type, param.Type)
+			}
+			if reason != "" {
+				changes.Breaking = append(changes.Breaking, EndpointChange{Method: method, Path: path, Detail: reason})
+			}
+		}
+		if after.BodyRequired && !before.BodyRequired {
+			changes.Breaking = append(changes.Breaking, EndpointChange{Method: method, Path: path, Detail: "request body is now required"})
+		}
+	}
+	for _, key := range sortedSnapshotKeys(previous) {
+		if _, ok := current[key]; !ok {
+			method, path, _ := strings.Cut(key, " ")
+			changes.Removed = append(changes.Removed, EndpointChange{Method: method, Path: path})
+		}
+	}
+	return changes
+}
+
+func sortedSnapshotKeys(snapshot map[string]endpointSnapshot) []string {
+	keys := make([]string, 0, len(snapshot))
+	for key := range snapshot {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// initNotifications takes the first complete documentation as the baseline of
+// later notifications. With a state file, changes since the snapshot of the
+// previous run are reported right away.
+func (a *APIDocs) initNotifications() {
+	if a.config.Notifications == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	current := snapshotDocumentation(a.documentation.Load())
+	if previous, ok := a.loadNotificationState(); ok {
+		a.notifyBaseline = previous
+		a.reportChanges(current)
+		return
+	}
+	a.notifyBaseline = current
+	a.saveNotificationState(current)
+}
+
+// reportChanges compares current with the baseline and notifies about the
+// differences in the background. Callers hold a.mu.
+func (a *APIDocs) reportChanges(current map[string]endpointSnapshot) {
+	settings := a.config.Notifications
+	changes := diffSnapshots(a.notifyBaseline, current)
+	if changes.empty() {
+		return
+	}
+	a.notifyBaseline = current
+	a.saveNotificationState(current)
+	if settings.BreakingOnly && len(changes.Removed) == 0 && len(changes.Breaking) == 0 {
+		return
+	}
+
+	changes.Title = a.config.Title
+	changes.Version = a.config.Version
+	changes.DocsURL = settings.DocsURL
+	go a.sendNotifications(settings, changes)
+}
+
+func (a *APIDocs) sendNotifications(settings *NotificationConfig, changes SpecChanges) {
+	var notifiers []Notifier
+	if settings.SlackWebhookURL != "" {
+		notifiers = append(notifiers, &SlackNotifier{WebhookURL: settings.SlackWebhookURL})
+	}
+	if settings.TeamsWebhookURL != "" {
+		notifiers = append(notifiers, &TeamsNotifier{WebhookURL: settings.TeamsWebhookURL})
+	}
+	notifiers = append(notifiers, settings.Notifiers...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	for _, notifier := range notifiers {
+		if err := notifier.Notify(ctx, changes); err != nil {
+			a.notificationError(fmt.Errorf("failed to send spec change notification: %w", err))
+		}
+	}
+}
+
+func (a *APIDocs) loadNotificationState() (map[string]endpointSnapshot, bool) {
+	path := a.config.Notifications.StateFile
+	if path == "" {
+		return nil, false
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			a.notificationError(fmt.Errorf("failed to read notification state: %w", err))
+		}
+		return nil, false
+	}
+	var snapshot map[string]endpointSnapshot
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		a.notificationError(fmt.Errorf("invalid notification state %s: %w", path, err))
+		return nil, false
+	}
+	return snapshot, true
+}
+
+func (a *APIDocs) saveNotificationState(snapshot map[string]endpointSnapshot) {
+	path := a.config.Notifications.StateFile
+	if path == "" {
+		return
+	}
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err == nil {
+		err = os.WriteFile(path, content, 0644)
+	}
+	if err != nil {
+		a.notificationError(fmt.Errorf("failed to write notification state: %w", err))
+	}
+}
+
+func (a *APIDocs) notificationError(err error) {
+	if onError := a.config.Notifications.OnError; onError != nil {
+		onError(err)
+	}
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotifications_ReportAddedRemovedAndBreakingEndpoints(t *testing.T) {
+	posts := make(chan string, 4)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		posts <- r.URL.Path + " " + string(body)
+	}))
+	defer webhook.Close()
+
+	received := make(chan SpecChanges, 4)
+	stateFile := filepath.Join(t.TempDir(), "endpoints.json")
+	config := &Config{
+		Title:    "Orders API",
+		Version:  "1.2.0",
+		DocsPath: "/docs",
+		Notifications: &NotificationConfig{
+			SlackWebhookURL: webhook.URL + "/slack",
+			TeamsWebhookURL: webhook.URL + "/teams",
+			StateFile:       stateFile,
+			DocsURL:         "https://api.example.com/docs",
+			Notifiers: []Notifier{NotifierFunc(func(ctx context.Context, changes SpecChanges) error {
+				received <- changes
+				return nil
+			})},
+		},
+	}
+	docs := New(config)
+	docs.AddRoute("GET", "/orders", nil, WithParam("status", "query", "string", false, ""))
+	docs.AddRoute("DELETE", "/orders/{id}", nil)
+	if err := docs.Warmup(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(stateFile); err != nil {
+		t.Fatalf("expected the first build to write the state file: %v", err)
+	}
+
+	docs.OverrideEndpoint("GET", "/orders", func(e *Endpoint) {
+		e.Parameters[0].Required = true
+	})
+	docs.AddRoute("POST", "/orders", nil, WithSummary("Create order"))
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case changes := <-received:
+		if len(changes.Added) != 1 || changes.Added[0].Path != "/orders" || changes.Added[0].Detail != "Create order" {
+			t.Fatalf("expected POST /orders to be added, got %+v", changes.Added)
+		}
+		if len(changes.Breaking) != 1 || !strings.Contains(changes.Breaking[0].Detail, `query parameter "status" is now required`) {
+			t.Fatalf("expected the required status parameter to be breaking, got %+v", changes.Breaking)
+		}
+		if changes.Summary() != "1 endpoint added, 1 breaking change" {
+			t.Fatalf("unexpected summary %q", changes.Summary())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a notification")
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case post := <-posts:
+			if strings.HasPrefix(post, "/slack ") && !strings.Contains(post, "*Orders API 1.2.0*: 1 endpoint added") ||
+				strings.HasPrefix(post, "/teams ") && !strings.Contains(post, `"@type":"MessageCard"`) ||
+				!strings.Contains(post, "https://api.example.com/docs") {
+				t.Fatalf("unexpected webhook payload %s", post)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected Slack and Teams webhooks to be called")
+		}
+	}
+
+	// A new process compares its first build with the saved state
+	restarted := New(&Config{Title: "Orders API", Version: "1.3.0", DocsPath: "/docs", Notifications: &NotificationConfig{
+		StateFile:    stateFile,
+		BreakingOnly: true,
+		Notifiers: []Notifier{NotifierFunc(func(ctx context.Context, changes SpecChanges) error {
+			received <- changes
+			return nil
+		})},
+	}})
+	restarted.AddRoute("GET", "/orders", nil, WithParam("status", "query", "string", true, ""))
+	restarted.AddRoute("POST", "/orders", nil)
+	if err := restarted.Warmup(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case changes := <-received:
+		if len(changes.Removed) != 1 || changes.Removed[0].Method != "DELETE" || len(changes.Added) != 0 {
+			t.Fatalf("expected DELETE /orders/{id} to be reported as removed, got %+v", changes)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a notification about the removed endpoint")
+	}
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OperationIDFunc returns the operationId of an endpoint, e.g. "listUserOrders".
+// An empty result keeps the generated ID.
+type OperationIDFunc func(route RouteInfo, endpoint *Endpoint) string
+
+// applyOperationID replaces the generated ID with Config.OperationID's.
+// Overrides run afterwards and may still change it.
+func (a *APIDocs) applyOperationID(route RouteInfo, endpoint *Endpoint) {
+	if a.config == nil || a.config.OperationID == nil {
+		return
+	}
+	if id := strings.TrimSpace(a.config.OperationID(route, endpoint)); id != "" {
+		endpoint.ID = id
+	}
+}
+
+// dedupeOperationIDs makes endpoint IDs unique. Endpoints sharing an ID are
+// ordered by method and path; the first keeps it and the others get "-2",
+// "-3", ... so the suffixes do not depend on route registration order.
+func dedupeOperationIDs(endpoints []*Endpoint) {
+	byID := make(map[string][]*Endpoint)
+	used := make(map[string]bool)
+	for _, endpoint := range endpoints {
+		byID[endpoint.ID] = append(byID[endpoint.ID], endpoint)
+		used[endpoint.ID] = true
+	}
+
+	collisions := make([]string, 0)
+	for id, group := range byID {
+		if len(group) > 1 {
+			collisions = append(collisions, id)
+		}
+	}
+	sort.Strings(collisions)
+
+	for _, id := range collisions {
+		group := byID[id]
+		sort.SliceStable(group, func(i, j int) bool {
+			if group[i].Method != group[j].Method {
+				return group[i].Method < group[j].Method
+			}
+			return group[i].Path < group[j].Path
+		})
+
+		suffix := 2
+		for _, endpoint := range group[1:] {
+			candidate := fmt.Sprintf("%s-%d", id, suffix)
+			for used[candidate] {
+				suffix++
+				candidate = fmt.Sprintf("%s-%d", id, suffix)
+			}
+			used[candidate] = true
+			endpoint.ID = candidate
+			suffix++
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package core
+
+import "testing"
+
+func TestOperationID_CustomAndDeduplicated(t *testing.T) {
+	idsOf := func(config *Config, paths ...string) map[string]string {
+		config.Title, config.Version, config.DocsPath = "Test", "1.0.0", "/docs"
+		docs := New(config)
+		for _, path := range paths {
+			docs.AddRoute("GET", path, nil)
+		}
+		if err := docs.Generate(); err != nil {
+			t.Fatal(err)
+		}
+		ids := make(map[string]string)
+		for _, section := range docs.GetDocumentation().Endpoints {
+			for _, endpoint := range section.Endpoints {
+				ids[endpoint.Path] = endpoint.ID
+			}
+		}
+		return ids
+	}
+
+	// "/a-b" and "/a/b" collide once slashes are replaced; "/a-b-2" takes the first suffix
+	forward := idsOf(&Config{}, "/a-b", "/a/b", "/a-b-2")
+	backward := idsOf(&Config{}, "/a-b-2", "/a/b", "/a-b")
+	if forward["/a-b"] != "get--a-b" || forward["/a/b"] != "get--a-b-3" || forward["/a-b-2"] != "get--a-b-2" {
+		t.Fatalf("expected unique suffixed IDs, got %v", forward)
+	}
+	for path, id := range forward {
+		if backward[path] != id {
+			t.Fatalf("expected IDs independent of registration order, got %v and %v", forward, backward)
+		}
+	}
+
+	custom := idsOf(&Config{OperationID: func(route RouteInfo, endpoint *Endpoint) string {
+		if route.Path == "/users" {
+			return "listUsers"
+		}
+		return ""
+	}}, "/users", "/accounts")
+	if custom["/users"] != "listUsers" || custom["/accounts"] != "get--accounts" {
+		t.Fatalf("expected the custom ID with a generated fallback, got %v", custom)
+	}
+}
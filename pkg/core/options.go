@@ -0,0 +1,184 @@
+package core
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// WithSummary sets the endpoint summary
+func WithSummary(summary string) RouteOption {
+	return func(route *RouteInfo) {
+		route.Summary = summary
+	}
+}
+
+// WithDescription sets the endpoint description
+func WithDescription(description string) RouteOption {
+	return func(route *RouteInfo) {
+		route.Description = description
+	}
+}
+
+// WithDeprecated marks the endpoint as deprecated
+func WithDeprecated() RouteOption {
+	return func(route *RouteInfo) {
+		route.Deprecated = true
+	}
+}
+
+// WithSection puts the endpoint into the section with the given ID
+func WithSection(id string) RouteOption {
+	return func(route *RouteInfo) {
+		route.Section = id
+	}
+}
+
+// WithExternalDocs links the endpoint to a guide, shown as a "Read more" link
+func WithExternalDocs(url, description string) RouteOption {
+	return func(route *RouteInfo) {
+		route.ExternalDocs = &ExternalDocs{URL: url, Description: description}
+	}
+}
+
+// WithPreset adds a named set of values that fills the try-it form in one click
+func WithPreset(preset Preset) RouteOption {
+	return func(route *RouteInfo) {
+		route.Presets = append(route.Presets, preset)
+	}
+}
+
+// WithSLA sets the endpoint's expected response time, shown as a latency
+// badge and compared against the measured duration of try-it requests
+func WithSLA(budget time.Duration) RouteOption {
+	return func(route *RouteInfo) {
+		route.SLA = budget
+	}
+}
+
+// WithGraphQL marks the endpoint as a GraphQL endpoint, which the tester opens
+// in GraphQL mode; routes ending in /graphql are detected without it
+func WithGraphQL() RouteOption {
+	return WithExtension(GraphQLExtension, true)
+}
+
+// WithPagination documents the endpoint as paginated in the given style
+// (PaginationOffset, PaginationCursor or PaginationPage), or opts it out of
+// Config.Pagination with PaginationNone
+func WithPagination(style string) RouteOption {
+	return func(route *RouteInfo) {
+		route.Pagination = style
+	}
+}
+
+// WithParam adds a single parameter to the endpoint
+func WithParam(name, in, paramType string, required bool, description string) RouteOption {
+	return func(route *RouteInfo) {
+		route.Parameters = append(route.Parameters, Parameter{
+			Name:        name,
+			In:          in,
+			Type:        paramType,
+			Required:    required,
+			Description: description,
+		})
+	}
+}
+
+// WithParameters adds several parameters to the endpoint
+func WithParameters(params []Parameter) RouteOption {
+	return func(route *RouteInfo) {
+		route.Parameters = append(route.Parameters, params...)
+	}
+}
+
+// WithRequest documents a JSON request body using the type of value.
+// Struct tags (json, binding, validate, example) are honored.
+func WithRequest(value interface{}) RouteOption {
+	return WithRequestContentType("application/json", value)
+}
+
+// WithRequestContentType documents a request body with an explicit content type
+func WithRequestContentType(contentType string, value interface{}) RouteOption {
+	return func(route *RouteInfo) {
+		schema, example := SchemaFromValue(value)
+		route.RequestBody = &RequestBody{
+			ContentType: contentType,
+			Schema:      schema,
+			Example:     example,
+			Required:    true,
+		}
+	}
+}
+
+// WithRequestAlternative documents a further content type of the request
+// body with its own schema, e.g. an XML variant next to WithRequest. Without
+// a request body it becomes the primary one.
+func WithRequestAlternative(contentType string, value interface{}) RouteOption {
+	return func(route *RouteInfo) {
+		schema, example := SchemaFromValue(value)
+		if route.RequestBody == nil {
+			route.RequestBody = &RequestBody{ContentType: contentType, Schema: schema, Example: example, Required: true}
+			return
+		}
+		alternatives := make([]BodyContent, 0, len(route.RequestBody.Alternatives)+1)
+		for _, content := range route.RequestBody.Alternatives {
+			if content.ContentType != contentType {
+				alternatives = append(alternatives, content)
+			}
+		}
+		route.RequestBody.Alternatives = append(alternatives, BodyContent{ContentType: contentType, Schema: schema, Example: example})
+	}
+}
+
+// WithAccepts documents further content types the request body accepts with
+// the same schema, e.g. WithAccepts("application/xml")
+func WithAccepts(contentTypes ...string) RouteOption {
+	return func(route *RouteInfo) {
+		route.Accepts = append(route.Accepts, contentTypes...)
+	}
+}
+
+// WithExtension attaches a vendor extension to the operation, e.g.
+// WithExtension("x-rate-limit", 100); the "x-" prefix is added when missing
+func WithExtension(name string, value interface{}) RouteOption {
+	return func(route *RouteInfo) {
+		if route.Extensions == nil {
+			route.Extensions = make(Extensions)
+		}
+		route.Extensions[ExtensionName(name)] = value
+	}
+}
+
+// WithResponseDescription replaces the generated description of the response
+// for status, e.g. WithResponseDescription(404, "User not found with the given ID")
+func WithResponseDescription(status int, description string) RouteOption {
+	return func(route *RouteInfo) {
+		if route.ResponseDocs == nil {
+			route.ResponseDocs = make(map[string]string)
+		}
+		route.ResponseDocs[strconv.Itoa(status)] = description
+	}
+}
+
+// WithResponse documents a JSON response for status using the type of value.
+// Pass nil for responses without a body.
+func WithResponse(status int, value interface{}) RouteOption {
+	return func(route *RouteInfo) {
+		if route.Responses == nil {
+			route.Responses = make(map[string]Response)
+		}
+
+		response := Response{
+			Description: http.StatusText(status),
+		}
+		if response.Description == "" {
+			response.Description = "Response"
+		}
+		if value != nil {
+			response.Schema, response.Example = SchemaFromValue(value)
+			response.ContentType = "application/json"
+		}
+
+		route.Responses[strconv.Itoa(status)] = response
+	}
+}
@@ -0,0 +1,105 @@
+package core
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouteOptions_BuildSchemasFromValues(t *testing.T) {
+	type user struct {
+		ID    int    `json:"id" example:"42"`
+		Email string `json:"email" binding:"required"`
+		Notes string `json:"notes,omitempty"`
+	}
+
+	docs := New(nil)
+	docs.AddRoute("POST", "/users", nil,
+		WithSummary("Create user"),
+		WithRequest(user{}),
+		WithResponse(201, &user{ID: 7}),
+	)
+	if err := docs.Generate(); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+
+	endpoint := docs.GetDocumentation().Endpoints[0].Endpoints[0]
+	if endpoint.Summary != "Create user" {
+		t.Fatalf("expected summary, got %q", endpoint.Summary)
+	}
+
+	schema := endpoint.RequestBody.Schema.(map[string]interface{})
+	required := schema["required"].([]string)
+	if len(required) != 1 || required[0] != "email" {
+		t.Fatalf("expected email to be required, got %v", required)
+	}
+	if example := endpoint.RequestBody.Example.(map[string]interface{}); example["id"] != int64(42) {
+		t.Fatalf("expected tag example for id, got %#v", example["id"])
+	}
+
+	created, ok := endpoint.Responses["201"]
+	if !ok || created.Description != "Created" {
+		t.Fatalf("expected 201 Created response, got %+v", endpoint.Responses)
+	}
+	if example := created.Example.(map[string]interface{}); example["id"] != int64(7) {
+		t.Fatalf("expected value example for id, got %#v", example["id"])
+	}
+}
+
+func TestPresets_FromRouteOptionsAndOverrides(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs"})
+	docs.AddRoute("POST", "/users", nil,
+		WithRequest(struct {
+			Name string `json:"name" example:"Ada"`
+		}{}),
+		WithPreset(Preset{Name: "create admin user", Params: map[string]string{"X-Tenant": "acme"}, Body: map[string]interface{}{"name": "Root", "role": "admin"}}))
+	docs.OverrideEndpoint("POST", "/users", func(e *Endpoint) {
+		e.Presets = append(e.Presets, Preset{Name: "guest", Body: map[string]interface{}{"name": "Guest"}})
+	})
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	endpoint := docs.GetDocumentation().Endpoints[0].Endpoints[0]
+	if len(endpoint.Presets) != 2 || endpoint.Presets[0].Name != "create admin user" || endpoint.Presets[1].Name != "guest" {
+		t.Fatalf("expected the option preset followed by the override preset, got %+v", endpoint.Presets)
+	}
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/api-data.json", nil))
+	if !strings.Contains(rec.Body.String(), `"presets":[{"name":"create admin user","params":{"X-Tenant":"acme"}`) {
+		t.Fatalf("expected presets in the UI data, got %s", rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/api-data.json?summary=true", nil))
+	if strings.Contains(rec.Body.String(), "presets") {
+		t.Fatalf("expected summary mode to drop presets like other examples, got %s", rec.Body.String())
+	}
+}
+
+func TestSLA_MappedToEndpointAndOpenAPI(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs"})
+	docs.AddRoute("GET", "/orders/{id}", nil, WithSLA(200*time.Millisecond))
+	docs.AddRoute("GET", "/reports", nil)
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	endpoints := docs.GetDocumentation().Endpoints[0].Endpoints
+	for _, endpoint := range endpoints {
+		want := time.Duration(0)
+		if endpoint.Path == "/orders/{id}" {
+			want = 200 * time.Millisecond
+		}
+		if endpoint.SLA != want {
+			t.Fatalf("expected %s to have SLA %v, got %v", endpoint.Path, want, endpoint.SLA)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/openapi.json", nil))
+	if spec := rec.Body.String(); !strings.Contains(spec, `"x-sla":"200ms"`) || strings.Count(spec, `"x-sla"`) != 1 {
+		t.Fatalf("expected x-sla only on the budgeted operation, got %s", spec)
+	}
+}
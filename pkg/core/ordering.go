@@ -0,0 +1,166 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Values for Config.SpecKeyOrder
+const (
+	SpecKeyOrderAlphabetical = "alphabetical"
+	SpecKeyOrderOpenAPI      = "openapi"
+)
+
+// openAPIKeyRank is the conventional position of well-known OpenAPI keys.
+// Unknown keys follow in alphabetical order.
+var openAPIKeyRank = rankKeys(
+	"openapi", "info", "servers", "tags", "paths", "components",
+	"title", "version", "url", "name", "in",
+	"get", "put", "post", "delete", "options", "head", "patch", "trace",
+	"$ref", "summary", "description", "externalDocs", "operationId", "deprecated", "required",
+	"type", "format", "nullable", "enum", "parameters", "requestBody",
+	"responses", "content", "schema", "items", "properties", "example", "examples",
+)
+
+// openAPINameMaps are keys whose object values are keyed by user-chosen names
+// (paths, status codes, schema and property names); those are always sorted
+var openAPINameMaps = map[string]bool{
+	"paths": true, "properties": true, "responses": true, "schemas": true,
+	"content": true, "headers": true, "securitySchemes": true, "variables": true,
+}
+
+// openAPIDataKeys hold free-form example data that keeps alphabetical order
+var openAPIDataKeys = map[string]bool{
+	"example": true, "examples": true, "default": true, "enum": true,
+}
+
+func rankKeys(keys ...string) map[string]int {
+	ranks := make(map[string]int, len(keys))
+	for i, key := range keys {
+		ranks[key] = i
+	}
+	return ranks
+}
+
+// orderedObject is a JSON/YAML object that marshals its keys in a fixed order
+type orderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		encodedValue, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(encodedValue)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (o orderedObject) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, key := range o.keys {
+		valueNode := &yaml.Node{}
+		if err := valueNode.Encode(o.values[key]); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, valueNode)
+	}
+	return node, nil
+}
+
+// orderSpec returns the spec in the key order selected by Config.SpecKeyOrder.
+// The default keeps the value as-is; encoders already sort map keys, so the
+// output is deterministic either way.
+func (a *APIDocs) orderSpec(spec interface{}) interface{} {
+	if a.config == nil || a.config.SpecKeyOrder != SpecKeyOrderOpenAPI {
+		return spec
+	}
+
+	// Normalize typed values (Schema, Parameter, ...) into plain maps first
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return spec
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var normalized interface{}
+	if err := decoder.Decode(&normalized); err != nil {
+		return spec
+	}
+
+	return orderOpenAPIValue(normalized, false, false)
+}
+
+// orderOpenAPIValue converts maps into orderedObjects. names means the keys are
+// user-chosen names, data means the value is free-form example data.
+func orderOpenAPIValue(value interface{}, names, data bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.SliceStable(keys, func(i, j int) bool {
+			if !names && !data {
+				rankI, okI := openAPIKeyRank[keys[i]]
+				rankJ, okJ := openAPIKeyRank[keys[j]]
+				if okI != okJ {
+					return okI
+				}
+				if okI && rankI != rankJ {
+					return rankI < rankJ
+				}
+			}
+			return keys[i] < keys[j]
+		})
+
+		values := make(map[string]interface{}, len(v))
+		for _, key := range keys {
+			switch {
+			case data:
+				values[key] = orderOpenAPIValue(v[key], false, true)
+			case names:
+				// Values of a name map are regular OpenAPI objects again
+				values[key] = orderOpenAPIValue(v[key], false, false)
+			default:
+				values[key] = orderOpenAPIValue(v[key], openAPINameMaps[key], openAPIDataKeys[key])
+			}
+		}
+		return orderedObject{keys: keys, values: values}
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = orderOpenAPIValue(item, false, data)
+		}
+		return items
+	case json.Number:
+		// Keep integers exact and let YAML emit plain numbers, not strings
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	default:
+		return v
+	}
+}
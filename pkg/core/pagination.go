@@ -0,0 +1,248 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Pagination styles for Config.Pagination, WithPagination and @Paginate
+const (
+	PaginationOffset = "offset" // ?limit=&offset=, envelope with total, limit and offset
+	PaginationCursor = "cursor" // ?cursor=&limit=, envelope with next_cursor and has_more
+	PaginationPage   = "page"   // ?page=&size=, envelope with page, size, total and total_pages
+	PaginationNone   = "none"   // opts an endpoint out of Config.Pagination
+)
+
+const defaultPageSize = 20
+
+// Pagination is the paging convention of list endpoints. Its query
+// parameters and response envelope fields are added to every paginated
+// endpoint; parameters and fields the endpoint documents itself are kept.
+type Pagination struct {
+	Style       string // PaginationOffset, PaginationCursor or PaginationPage
+	DefaultSize int    // documented default page size; 0 uses 20
+	MaxSize     int    // documented maximum page size; 0 leaves it out
+	ItemsField  string // envelope field holding the items; default "data"
+}
+
+// paginationField is a query parameter or an envelope field of a style
+type paginationField struct {
+	name        string
+	kind        string // "int", "string" or "boolean"
+	description string
+	example     interface{}
+}
+
+// validPaginationStyle reports whether style is a known style or "none"
+func validPaginationStyle(style string) bool {
+	switch style {
+	case PaginationOffset, PaginationCursor, PaginationPage, PaginationNone:
+		return true
+	}
+	return false
+}
+
+// isListEndpoint reports whether the endpoint returns a collection: a GET
+// whose path does not end in a path parameter, e.g. "/users" but not "/users/{id}"
+func isListEndpoint(method, path string) bool {
+	if !strings.EqualFold(method, "GET") {
+		return false
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	return !strings.HasPrefix(segments[len(segments)-1], "{")
+}
+
+// applyPagination adds the pagination parameters and envelope of the route's
+// style, or of Config.Pagination for list endpoints without one
+func (a *APIDocs) applyPagination(route RouteInfo, endpoint *Endpoint) {
+	settings := Pagination{}
+	if a.config.Pagination != nil {
+		settings = *a.config.Pagination
+	}
+
+	switch {
+	case route.Pagination == PaginationNone:
+		return
+	case route.Pagination != "":
+		settings.Style = route.Pagination
+	case settings.Style == "" || !isListEndpoint(endpoint.Method, endpoint.Path):
+		return
+	}
+	if settings.DefaultSize <= 0 {
+		settings.DefaultSize = defaultPageSize
+	}
+	if settings.ItemsField == "" {
+		settings.ItemsField = "data"
+	}
+
+	params, fields := paginationFields(settings)
+	if params == nil {
+		return
+	}
+	endpoint.Pagination = settings.Style
+
+	documented := make(map[string]bool, len(endpoint.Parameters))
+	for _, param := range endpoint.Parameters {
+		documented[param.Name+":"+param.In] = true
+	}
+	parameters := append([]Parameter{}, endpoint.Parameters...)
+	for _, field := range params {
+		if documented[field.name+":query"] {
+			continue
+		}
+		parameters = append(parameters, Parameter{
+			Name:        field.name,
+			In:          "query",
+			Type:        field.kind,
+			Description: field.description,
+			Example:     field.example,
+		})
+	}
+	endpoint.Parameters = parameters
+
+	status := successStatus(endpoint.Responses)
+	if status == "" {
+		return
+	}
+	// The responses map may be shared with the registered route; copy before replacing
+	responses := make(map[string]Response, len(endpoint.Responses))
+	for code, response := range endpoint.Responses {
+		responses[code] = response
+	}
+	responses[status] = paginationEnvelope(responses[status], settings.ItemsField, fields)
+	endpoint.Responses = responses
+}
+
+// paginationFields returns the query parameters and envelope fields of a style
+func paginationFields(settings Pagination) ([]paginationField, []paginationField) {
+	sizeDescription := fmt.Sprintf("default %d", settings.DefaultSize)
+	if settings.MaxSize > 0 {
+		sizeDescription += fmt.Sprintf(", max %d", settings.MaxSize)
+	}
+
+	switch settings.Style {
+	case PaginationOffset:
+		return []paginationField{
+			{"limit", "int", "Maximum number of items to return (" + sizeDescription + ")", settings.DefaultSize},
+			{"offset", "int", "Number of items to skip (default 0)", 0},
+		}, []paginationField{
+			{"total", "int", "Total number of items", 100},
+			{"limit", "int", "Page size used", settings.DefaultSize},
+			{"offset", "int", "Number of items skipped", 0},
+		}
+	case PaginationCursor:
+		return []paginationField{
+			{"cursor", "string", "Cursor from next_cursor of the previous page; omit for the first page", ""},
+			{"limit", "int", "Maximum number of items to return (" + sizeDescription + ")", settings.DefaultSize},
+		}, []paginationField{
+			{"next_cursor", "string", "Cursor of the next page, empty on the last page", "eyJpZCI6MjB9"},
+			{"has_more", "boolean", "Whether more items follow", true},
+		}
+	case PaginationPage:
+		return []paginationField{
+			{"page", "int", "Page number, starting at 1", 1},
+			{"size", "int", "Items per page (" + sizeDescription + ")", settings.DefaultSize},
+		}, []paginationField{
+			{"page", "int", "Current page number", 1},
+			{"size", "int", "Items per page", settings.DefaultSize},
+			{"total", "int", "Total number of items", 100},
+			{"total_pages", "int", "Total number of pages", 5},
+		}
+	}
+	return nil, nil
+}
+
+// successStatus returns the lowest documented 2xx status, or "" without one
+func successStatus(responses map[string]Response) string {
+	statuses := make([]string, 0, len(responses))
+	for status := range responses {
+		if strings.HasPrefix(status, "2") {
+			statuses = append(statuses, status)
+		}
+	}
+	if len(statuses) == 0 {
+		return ""
+	}
+	sort.Strings(statuses)
+	return statuses[0]
+}
+
+// paginationEnvelope wraps an array response into the envelope, or adds the
+// missing envelope fields to an object response
+func paginationEnvelope(response Response, itemsField string, fields []paginationField) Response {
+	schema, _ := response.Schema.(map[string]interface{})
+	_, exampleIsObject := response.Example.(map[string]interface{})
+	wrap := schema["type"] == "array" || (schema == nil && !exampleIsObject)
+	if !wrap && schema != nil && schema["type"] != "object" {
+		return response
+	}
+
+	properties := map[string]interface{}{}
+	required := []string{}
+	example := map[string]interface{}{}
+	if wrap {
+		items := schema
+		if items == nil {
+			items = map[string]interface{}{"type": "array", "items": map[string]interface{}{}}
+		}
+		properties[itemsField] = items
+		required = append(required, itemsField)
+		if response.Example != nil {
+			example[itemsField] = response.Example
+		} else {
+			example[itemsField] = []interface{}{}
+		}
+	} else {
+		if documented, ok := schema["properties"].(map[string]interface{}); ok {
+			for name, property := range documented {
+				properties[name] = property
+			}
+		}
+		switch documented := schema["required"].(type) {
+		case []string:
+			required = append(required, documented...)
+		case []interface{}:
+			for _, name := range documented {
+				if name, ok := name.(string); ok {
+					required = append(required, name)
+				}
+			}
+		}
+		if documented, ok := response.Example.(map[string]interface{}); ok {
+			for key, value := range documented {
+				example[key] = value
+			}
+		}
+	}
+
+	for _, field := range fields {
+		if _, ok := properties[field.name]; !ok {
+			properties[field.name] = map[string]interface{}{
+				"type":        normalizeOpenAPIType(field.kind),
+				"description": field.description,
+			}
+			required = append(required, field.name)
+		}
+		if _, ok := example[field.name]; !ok {
+			example[field.name] = field.example
+		}
+	}
+
+	envelope := map[string]interface{}{}
+	if !wrap {
+		for key, value := range schema {
+			envelope[key] = value
+		}
+	}
+	envelope["type"] = "object"
+	envelope["properties"] = properties
+	envelope["required"] = required
+
+	response.Schema = envelope
+	response.Example = example
+	if response.ContentType == "" {
+		response.ContentType = "application/json"
+	}
+	return response
+}
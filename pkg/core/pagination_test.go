@@ -0,0 +1,69 @@
+package core
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPagination_GlobalStyleAndPerEndpointOverrides(t *testing.T) {
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs",
+		Pagination: &Pagination{Style: PaginationOffset, DefaultSize: 50, MaxSize: 200}})
+	docs.AddRoute("GET", "/users", nil,
+		WithParam("limit", "query", "int", false, "Custom limit"),
+		WithResponse(200, []user{{ID: 1, Name: "Ada"}}))
+	docs.AddRoute("GET", "/users/{id}", nil, WithResponse(200, user{}))
+	docs.AddRoute("GET", "/events", nil, WithPagination(PaginationCursor), WithResponse(200, struct {
+		Items []user `json:"items"`
+	}{}))
+	docs.AddRoute("GET", "/health", nil, WithPagination(PaginationNone))
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	endpoints := map[string]Endpoint{}
+	for _, section := range docs.GetDocumentation().Endpoints {
+		for _, endpoint := range section.Endpoints {
+			endpoints[endpoint.Path] = endpoint
+		}
+	}
+
+	users := endpoints["/users"]
+	if users.Pagination != PaginationOffset || len(users.Parameters) != 2 ||
+		users.Parameters[0].Description != "Custom limit" || users.Parameters[1].Name != "offset" {
+		t.Fatalf("expected the documented limit to be kept and offset added, got %q %+v", users.Pagination, users.Parameters)
+	}
+	schema := users.Responses["200"].Schema.(map[string]interface{})
+	properties := schema["properties"].(map[string]interface{})
+	for _, field := range []string{"data", "total", "limit", "offset"} {
+		if properties[field] == nil {
+			t.Fatalf("expected envelope field %q, got %+v", field, properties)
+		}
+	}
+	example := users.Responses["200"].Example.(map[string]interface{})
+	if items, ok := example["data"].([]interface{}); !ok || len(items) != 1 || example["limit"] != 50 {
+		t.Fatalf("expected the list example wrapped in the envelope, got %+v", example)
+	}
+
+	if endpoints["/users/{id}"].Pagination != "" || endpoints["/health"].Pagination != "" || len(endpoints["/health"].Parameters) != 0 {
+		t.Fatal("expected item endpoints and opted-out endpoints to stay unpaginated")
+	}
+	events := endpoints["/events"]
+	properties = events.Responses["200"].Schema.(map[string]interface{})["properties"].(map[string]interface{})
+	if events.Pagination != PaginationCursor || properties["items"] == nil || properties["next_cursor"] == nil || properties["data"] != nil {
+		t.Fatalf("expected cursor fields added to the documented object envelope, got %+v", properties)
+	}
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/openapi.json", nil))
+	if !strings.Contains(rec.Body.String(), `"x-pagination":"cursor"`) || !strings.Contains(rec.Body.String(), "default 50, max 200") {
+		t.Fatalf("expected pagination in the OpenAPI spec, got %s", rec.Body.String())
+	}
+	if err := ValidateConfig(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", Pagination: &Pagination{Style: "infinite"}}); err == nil {
+		t.Fatal("expected an unknown pagination style to be rejected")
+	}
+}
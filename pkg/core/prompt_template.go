@@ -0,0 +1,89 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// DefaultPromptTemplate is a starting point for AIFeatures.PromptTemplate:
+// instructions close to the built-in ones followed by the API
+const DefaultPromptTemplate = `You are the API documentation assistant of {{.Title}}. Only answer questions about this API, using the OpenAPI specification below as the single source of truth.
+
+Rules:
+- Never invent endpoints, parameters or responses that are not in the specification. If an endpoint does not exist, say so.
+- Be precise about required and optional parameters and show request and response JSON from the specification.
+- Write the method and path of every endpoint you refer to, e.g. GET /users/{id}, so it can be linked.
+- Be concise and keep code and curl examples minimal and runnable.
+- Answer in the language of the question.
+
+API: {{.Title}} {{.Version}}
+{{if .Description}}Description: {{.Description}}
+{{end}}{{if .BaseURLs}}Base URLs: {{join .BaseURLs ", "}}
+{{end}}
+OpenAPI specification{{if .Trimmed}} of the endpoints relevant to the question{{end}}:
+{{.Spec}}
+{{if .OtherEndpoints}}
+Other endpoints, not detailed above; say so instead of guessing their parameters:
+{{join .OtherEndpoints "\n"}}
+{{end}}`
+
+// PromptData are the variables of AIFeatures.PromptTemplate
+type PromptData struct {
+	Title          string
+	Version        string
+	Description    string
+	BaseURLs       []string
+	Question       string
+	Spec           string   // OpenAPI JSON, trimmed to the endpoints relevant to Question when over AIFeatures.ContextBudget
+	Trimmed        bool     // Spec is trimmed
+	OtherEndpoints []string // "METHOD path - summary" of the endpoints left out of a trimmed Spec
+}
+
+// promptTemplateFuncs are available in prompt templates besides the built-in ones
+var promptTemplateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// promptTemplateState is the parsed prompt template, loaded once
+type promptTemplateState struct {
+	once     sync.Once
+	template *template.Template
+	err      error
+}
+
+// promptTemplate returns the parsed AIFeatures.PromptTemplate or
+// PromptTemplateFile, nil when neither is configured
+func (a *APIDocs) promptTemplate() (*template.Template, error) {
+	a.prompt.once.Do(func() {
+		if a.config.AIConfig != nil {
+			a.prompt.template, a.prompt.err = parsePromptTemplate(a.config.AIConfig.Features)
+		}
+	})
+	return a.prompt.template, a.prompt.err
+}
+
+// parsePromptTemplate parses the template of features; the text wins over
+// the file
+func parsePromptTemplate(features AIFeatures) (*template.Template, error) {
+	text := features.PromptTemplate
+	if text == "" && features.PromptTemplateFile != "" {
+		content, err := os.ReadFile(features.PromptTemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read AI prompt template: %w", err)
+		}
+		text = string(content)
+	}
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("prompt").Funcs(promptTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AI prompt template: %w", err)
+	}
+	return tmpl, nil
+}
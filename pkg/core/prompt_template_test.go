@@ -0,0 +1,56 @@
+package core
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPromptTemplate_ReplacesSystemPrompt(t *testing.T) {
+	client := &fakeChatClient{provider: "fake-templated", model: "m-1"}
+	RegisterLLMClientFactory("fake-templated", func(config *AIConfig) (LLMClient, error) { return client, nil })
+	docs := New(&Config{Title: "Shop", Version: "2.1.0", DocsPath: "/docs", BaseURL: "https://api.example.com",
+		AIConfig: &AIConfig{Enabled: true, Provider: "fake-templated", Features: AIFeatures{ChatEnabled: true,
+			PromptTemplate: `Du bist der Assistent von {{.Title}} {{.Version}} ({{join .BaseURLs ", "}}). Frage: {{.Question}}{{if .Trimmed}} (gekürzt){{end}}
+{{.Spec}}`}}})
+	docs.AddRoute("GET", "/users", nil, WithSummary("List users"))
+
+	recorder := httptest.NewRecorder()
+	docs.ServeHTTP(recorder, httptest.NewRequest("POST", "/docs/chat", strings.NewReader(`{"message":"Wie liste ich Benutzer?","context":"ignored"}`)))
+	if len(client.requests) != 1 {
+		t.Fatalf("expected a chat call, got %s", recorder.Body.String())
+	}
+	prompt := client.requests[0].SystemPrompt
+	if !strings.HasPrefix(prompt, "Du bist der Assistent von Shop 2.1.0 (https://api.example.com). Frage: Wie liste ich Benutzer?\n") || !strings.Contains(prompt, `"/users"`) {
+		t.Fatalf("unexpected system prompt: %s", prompt)
+	}
+	if strings.Contains(prompt, "STRICT INSTRUCTIONS") {
+		t.Fatal("expected the built-in instructions replaced")
+	}
+
+	docs = New(&Config{Title: "Shop", Version: "2.1.0", DocsPath: "/docs", AIConfig: &AIConfig{Features: AIFeatures{PromptTemplate: DefaultPromptTemplate}}})
+	docs.AddRoute("GET", "/users", nil)
+	context, err := docs.GetAPIContextFor("")
+	if err != nil || !strings.HasPrefix(context, "You are the API documentation assistant of Shop.") || !strings.Contains(context, `"/users"`) {
+		t.Fatalf("expected the default template rendered, got %q, %v", context, err)
+	}
+
+	file := filepath.Join(t.TempDir(), "prompt.tmpl")
+	if err := os.WriteFile(file, []byte("{{upper .Title}}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	docs = New(&Config{Title: "Shop", DocsPath: "/docs", AIConfig: &AIConfig{Features: AIFeatures{PromptTemplateFile: file}}})
+	if context, err := docs.GetAPIContextFor("hi"); err != nil || context != "SHOP" {
+		t.Fatalf("expected the template file rendered, got %q, %v", context, err)
+	}
+
+	for _, features := range []AIFeatures{{PromptTemplate: "{{.Title"}, {PromptTemplateFile: filepath.Join(t.TempDir(), "missing.tmpl")}} {
+		features.MaxTokens = 149
+		config := &Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", BaseURL: "http://localhost:8080", AIConfig: &AIConfig{Enabled: true, Provider: "openai", APIKey: "sk-test", Features: features}}
+		if err := ValidateConfig(config); err == nil || !strings.Contains(err.Error(), "prompt template") {
+			t.Fatalf("expected the prompt template rejected, got %v", err)
+		}
+	}
+}
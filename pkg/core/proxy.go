@@ -0,0 +1,109 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// mountPrefixKey carries the path prefix a Registry serves a tenant under. The
+// tenant trusts it like the X-Forwarded-Prefix of a trusted proxy.
+type mountPrefixKey struct{}
+
+// withMountPrefix returns r carrying the external path prefix of its docs
+func withMountPrefix(r *http.Request, prefix string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), mountPrefixKey{}, prefix))
+}
+
+// mountPrefix returns the prefix set by withMountPrefix
+func mountPrefix(r *http.Request) (string, bool) {
+	prefix, ok := r.Context().Value(mountPrefixKey{}).(string)
+	return prefix, ok
+}
+
+// parseTrustedProxies parses IPs and CIDRs such as "10.0.0.0/8" or "127.0.0.1"
+func parseTrustedProxies(entries []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q, use an IP or CIDR", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q, use an IP or CIDR", entry)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// containsIP reports whether ip, a textual address, is in one of networks
+func containsIP(networks []*net.IPNet, ip string) bool {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP is the address of the peer that sent r
+func remoteIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
+
+// fromTrustedProxy reports whether r was sent by one of trustedProxies, so
+// its Forwarded and X-Forwarded-* headers can be believed
+func fromTrustedProxy(r *http.Request, trustedProxies []string) bool {
+	if r == nil || len(trustedProxies) == 0 {
+		return false
+	}
+	networks, err := parseTrustedProxies(trustedProxies)
+	return err == nil && containsIP(networks, remoteIP(r))
+}
+
+// clientIPBehind returns the IP of the client that sent r: the peer address,
+// or behind trusted proxies the last X-Forwarded-For address that is not one
+// of them. Headers from other peers are ignored, as anyone can set them.
+func clientIPBehind(r *http.Request, trustedProxies []string) string {
+	ip := remoteIP(r)
+	networks, err := parseTrustedProxies(trustedProxies)
+	if err != nil || !containsIP(networks, ip) {
+		return ip
+	}
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+		if !containsIP(networks, hop) {
+			break
+		}
+	}
+	return ip
+}
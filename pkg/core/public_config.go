@@ -0,0 +1,72 @@
+package core
+
+// PublicConfig is the part of Config the docs UI needs. It is the only config
+// embedded into docs pages, so credentials such as the auth password, API
+// keys and AI provider keys never reach the browser.
+type PublicConfig struct {
+	Title       string          `json:"title"`
+	Version     string          `json:"version"`
+	Description string          `json:"description"`
+	BaseURL     string          `json:"baseUrl"`
+	BaseURLs    []BaseURLOption `json:"baseUrls"`
+	DocsPath    string          `json:"docsPath"`
+	RootPrefix  string          `json:"rootPrefix,omitempty"`
+	UIConfig    *UIConfig       `json:"uiConfig,omitempty"`
+	AuthConfig  *PublicAuthInfo `json:"authConfig,omitempty"`
+	AIConfig    *PublicAIInfo   `json:"aiConfig,omitempty"`
+	Analytics   bool            `json:"analytics,omitempty"` // the UI reports views, tries and searches
+}
+
+// PublicAuthInfo tells the UI how the docs are protected, without credentials
+type PublicAuthInfo struct {
+	Enabled      bool   `json:"enabled"`
+	Type         string `json:"type"`
+	APIKeyHeader string `json:"apiKeyHeader,omitempty"`
+}
+
+// PublicAIInfo tells the UI which AI features are available, without provider
+// keys or settings
+type PublicAIInfo struct {
+	Enabled     bool   `json:"enabled"`
+	Provider    string `json:"provider"`
+	ChatEnabled bool   `json:"chatEnabled"`
+	Model       string `json:"model,omitempty"`
+}
+
+// Public returns the browser-safe projection of the config
+func (c *Config) Public() *PublicConfig {
+	if c == nil {
+		return nil
+	}
+
+	public := &PublicConfig{
+		Title:       c.Title,
+		Version:     c.Version,
+		Description: c.Description,
+		BaseURL:     c.BaseURL,
+		BaseURLs:    c.BaseURLs,
+		DocsPath:    c.DocsPath,
+		RootPrefix:  c.RootPrefix,
+		Analytics:   c.Analytics != nil && c.Analytics.Enabled,
+	}
+	if c.UIConfig != nil {
+		uiConfig := *c.UIConfig
+		uiConfig.TestClient = nil
+		public.UIConfig = &uiConfig
+	}
+	if c.AuthConfig != nil {
+		public.AuthConfig = &PublicAuthInfo{Enabled: c.AuthConfig.Enabled, Type: c.AuthConfig.Type}
+		if c.AuthConfig.Type == "api_key" {
+			public.AuthConfig.APIKeyHeader = c.AuthConfig.APIKeyHeader
+		}
+	}
+	if c.AIConfig != nil {
+		public.AIConfig = &PublicAIInfo{
+			Enabled:     c.AIConfig.Enabled,
+			Provider:    c.AIConfig.Provider,
+			ChatEnabled: c.AIConfig.Features.ChatEnabled,
+			Model:       c.AIConfig.Features.Model,
+		}
+	}
+	return public
+}
@@ -0,0 +1,357 @@
+package core
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const redactedValue = "[REDACTED]"
+
+// defaultRedactedNames are the built-in field name patterns; names match when
+// they contain one of these, ignoring case, "-" and "_"
+var defaultRedactedNames = []string{"password", "passwd", "secret", "token", "apikey", "authorization", "cookie", "session", "creditcard", "cardnumber", "cvv", "ssn"}
+
+// Built-in value patterns: emails, card numbers, JWTs and bearer tokens
+var (
+	emailValue  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@([A-Za-z0-9-]+\.)+[A-Za-z]{2,}`)
+	cardValue   = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	jwtValue    = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	bearerValue = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]+=*`)
+)
+
+// reservedEmailDomains are documentation domains (RFC 2606), kept in examples
+var reservedEmailDomains = []string{"example.com", "example.org", "example.net", ".example", ".test", ".invalid", ".localhost"}
+
+// RedactFunc is a custom redaction rule. field is the JSON property or
+// parameter name the value belongs to, "" when there is none; returning true
+// replaces value with the result.
+type RedactFunc func(field string, value interface{}) (interface{}, bool)
+
+// RedactionConfig removes personal data and secrets from every documented
+// example, from captured traffic and from the AI context. Values of fields
+// named like passwords, tokens, secrets, cookies or card numbers are replaced
+// entirely; emails, card numbers, JWTs and bearer tokens are replaced wherever
+// they appear in a string. Emails at documentation domains such as
+// example.com are kept.
+type RedactionConfig struct {
+	Enabled     bool
+	Fields      []string   // field name patterns on top of the built-in ones, e.g. "iban" or "*_phone"; case-insensitive, ignoring "-" and "_"
+	Patterns    []string   // regular expressions of values on top of the built-in ones, e.g. `\+\d{10,14}`
+	Func        RedactFunc // custom rule, run after the others
+	Replacement string     // "[REDACTED]" by default
+}
+
+// redactor applies redaction rules to examples and text
+type redactor struct {
+	names       []string
+	patterns    []*regexp.Regexp
+	fn          RedactFunc
+	replacement string
+}
+
+// redactors are built once from the config, on first use
+type redactors struct {
+	once    sync.Once
+	docs    *redactor // nil when Config.Redaction is disabled
+	traffic *redactor
+}
+
+// newRedactor combines the built-in rules, config and further field name
+// patterns; config may be nil
+func newRedactor(config *RedactionConfig, fields []string) (*redactor, error) {
+	r := &redactor{replacement: redactedValue, patterns: []*regexp.Regexp{emailValue, cardValue, jwtValue, bearerValue}}
+	names := append(append([]string{}, defaultRedactedNames...), fields...)
+	if config != nil {
+		names = append(names, config.Fields...)
+		for _, pattern := range config.Patterns {
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+			}
+			r.patterns = append(r.patterns, compiled)
+		}
+		r.fn = config.Func
+		if config.Replacement != "" {
+			r.replacement = config.Replacement
+		}
+	}
+	for _, name := range names {
+		if normalized := normalizeRedactedName(name); normalized != "" {
+			r.names = append(r.names, normalized)
+		}
+	}
+	return r, nil
+}
+
+// redactors returns the redactor of the documentation, nil unless
+// Config.Redaction is enabled, and the one of captured traffic, which always
+// applies the built-in rules and Config.Traffic.RedactFields
+func (a *APIDocs) redactors() (*redactor, *redactor) {
+	a.redaction.once.Do(func() {
+		config := a.config.Redaction
+		if config != nil && !config.Enabled {
+			config = nil
+		}
+		if config != nil {
+			a.redaction.docs, _ = newRedactor(config, nil)
+		}
+		var fields []string
+		if a.config.Traffic != nil {
+			fields = a.config.Traffic.RedactFields
+		}
+		a.redaction.traffic, _ = newRedactor(config, fields)
+		if a.redaction.traffic == nil {
+			// Invalid patterns are reported by ValidateConfig; keep the built-in rules
+			a.redaction.traffic, _ = newRedactor(nil, fields)
+		}
+	})
+	return a.redaction.docs, a.redaction.traffic
+}
+
+func normalizeRedactedName(name string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// redactsName reports whether the values of a field are redacted entirely
+func (r *redactor) redactsName(field string) bool {
+	if field == "" {
+		return false
+	}
+	normalized := normalizeRedactedName(field)
+	for _, name := range r.names {
+		if strings.Contains(name, "*") {
+			if matched, _ := path.Match(name, normalized); matched {
+				return true
+			}
+		} else if strings.Contains(normalized, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// text replaces the values matching the patterns within s
+func (r *redactor) text(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllStringFunc(s, func(match string) string {
+			switch pattern {
+			case emailValue:
+				if isReservedEmail(match) {
+					return match
+				}
+			case cardValue:
+				if !luhnValid(match) {
+					return match
+				}
+			}
+			return r.replacement
+		})
+	}
+	return s
+}
+
+// value returns a redacted copy of an example belonging to field
+func (r *redactor) value(field string, value interface{}) interface{} {
+	if r == nil || value == nil {
+		return value
+	}
+	if r.redactsName(field) {
+		return r.replacement
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			redacted[key] = r.value(key, item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = r.value(field, item)
+		}
+		return redacted
+	case string:
+		value = r.text(v)
+	}
+	if r.fn != nil {
+		if replaced, ok := r.fn(field, value); ok {
+			return replaced
+		}
+	}
+	return value
+}
+
+// schema returns a copy of a JSON schema with its examples and defaults redacted
+func (r *redactor) schema(field string, schema interface{}) interface{} {
+	switch value := schema.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			switch key {
+			case "example", "default":
+				item = r.value(field, item)
+			case "properties":
+				if properties, ok := item.(map[string]interface{}); ok {
+					redacted := make(map[string]interface{}, len(properties))
+					for name, property := range properties {
+						redacted[name] = r.schema(name, property)
+					}
+					item = redacted
+				}
+			case "enum":
+			default:
+				item = r.schema(field, item)
+			}
+			copied[key] = item
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(value))
+		for i, item := range value {
+			copied[i] = r.schema(field, item)
+		}
+		return copied
+	}
+	return schema
+}
+
+// examples redacts named examples
+func (r *redactor) examples(examples []Example) []Example {
+	if len(examples) == 0 {
+		return examples
+	}
+	redacted := make([]Example, len(examples))
+	for i, example := range examples {
+		example.Value = r.value("", example.Value)
+		redacted[i] = example
+	}
+	return redacted
+}
+
+// applyRedaction redacts the examples, presets and schema examples of
+// endpoint when Config.Redaction is enabled
+func (a *APIDocs) applyRedaction(endpoint *Endpoint) {
+	r, _ := a.redactors()
+	if r == nil {
+		return
+	}
+
+	if len(endpoint.Parameters) > 0 {
+		params := make([]Parameter, len(endpoint.Parameters))
+		for i, param := range endpoint.Parameters {
+			param.Example = r.value(param.Name, param.Example)
+			params[i] = param
+		}
+		endpoint.Parameters = params
+	}
+
+	if endpoint.RequestBody != nil {
+		body := *endpoint.RequestBody
+		body.Example = r.value("", body.Example)
+		body.Schema = r.schema("", body.Schema)
+		body.Examples = r.examples(body.Examples)
+		if len(body.Alternatives) > 0 {
+			body.Alternatives = make([]BodyContent, len(endpoint.RequestBody.Alternatives))
+			for i, content := range endpoint.RequestBody.Alternatives {
+				content.Example = r.value("", content.Example)
+				content.Schema = r.schema("", content.Schema)
+				body.Alternatives[i] = content
+			}
+		}
+		endpoint.RequestBody = &body
+	}
+
+	if len(endpoint.Responses) > 0 {
+		responses := make(map[string]Response, len(endpoint.Responses))
+		for status, response := range endpoint.Responses {
+			response.Example = r.value("", response.Example)
+			response.Schema = r.schema("", response.Schema)
+			response.Examples = r.examples(response.Examples)
+			if len(response.Headers) > 0 {
+				headers := make(map[string]ResponseHeader, len(response.Headers))
+				for name, header := range response.Headers {
+					header.Example = r.value(name, header.Example)
+					headers[name] = header
+				}
+				response.Headers = headers
+			}
+			responses[status] = response
+		}
+		endpoint.Responses = responses
+	}
+
+	if len(endpoint.Presets) > 0 {
+		presets := make([]Preset, len(endpoint.Presets))
+		for i, preset := range endpoint.Presets {
+			if len(preset.Params) > 0 {
+				params := make(map[string]string, len(preset.Params))
+				for name, value := range preset.Params {
+					params[name] = fmt.Sprint(r.value(name, value))
+				}
+				preset.Params = params
+			}
+			preset.Body = r.value("", preset.Body)
+			presets[i] = preset
+		}
+		endpoint.Presets = presets
+	}
+}
+
+// redactSchemas redacts the examples of component schemas
+func (r *redactor) redactSchemas(schemas map[string]Schema) map[string]Schema {
+	if r == nil || len(schemas) == 0 {
+		return schemas
+	}
+	redacted := make(map[string]Schema, len(schemas))
+	for name, schema := range schemas {
+		schema.Example = r.value("", schema.Example)
+		if len(schema.Properties) > 0 {
+			properties := make(map[string]Property, len(schema.Properties))
+			for propertyName, property := range schema.Properties {
+				property.Example = r.value(propertyName, property.Example)
+				properties[propertyName] = property
+			}
+			schema.Properties = properties
+		}
+		redacted[name] = schema
+	}
+	return redacted
+}
+
+func isReservedEmail(email string) bool {
+	domain := strings.ToLower(email[strings.LastIndex(email, "@")+1:])
+	for _, reserved := range reservedEmailDomains {
+		if domain == strings.TrimPrefix(reserved, ".") || strings.HasSuffix(domain, "."+strings.TrimPrefix(reserved, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// luhnValid reports whether the digits of a number pass the Luhn check of
+// card numbers
+func luhnValid(number string) bool {
+	sum, double := 0, false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		digit := int(c - '0')
+		if double {
+			if digit *= 2; digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
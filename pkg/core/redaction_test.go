@@ -0,0 +1,90 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedaction_ExamplesTrafficAndAIContext(t *testing.T) {
+	type signup struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+		IBAN     string `json:"iban"`
+		Note     string `json:"note"`
+	}
+	type session struct {
+		Token   string `json:"token"`
+		Contact string `json:"contact"`
+		Phone   string `json:"phone"`
+		OrderID int64  `json:"orderId"`
+	}
+	docs := New(&Config{
+		Title:       "Test",
+		Version:     "1.0.0",
+		Description: "Questions go to ops@corp.io",
+		DocsPath:    "/docs",
+		Redaction: &RedactionConfig{
+			Enabled:  true,
+			Fields:   []string{"iban"},
+			Patterns: []string{`EMP-\d+`},
+			Func: func(field string, value interface{}) (interface{}, bool) {
+				return "***", field == "phone"
+			},
+		},
+		Traffic: &TrafficConfig{CaptureExamples: true},
+	})
+	docs.AddRoute("POST", "/signup", nil,
+		WithRequest(signup{Email: "ada@corp.io", Password: "hunter2", IBAN: "DE89370400440532013000", Note: "card 4111 1111 1111 1111, employee EMP-42"}),
+		WithResponse(200, session{Token: "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.sig", Contact: "help@example.com", Phone: "+49 30 1234", OrderID: 1234567890123}),
+		WithPreset(Preset{Name: "admin", Params: map[string]string{"apiKey": "k-123"}, Body: map[string]interface{}{"email": "root@corp.io"}}),
+	)
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(docs.GetDocumentation())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{"ada@corp.io", "hunter2", "DE89370400440532013000", "4111 1111 1111 1111", "EMP-42", "eyJhbGciOiJIUzI1NiJ9", "+49 30 1234", "k-123", "root@corp.io"} {
+		if strings.Contains(string(data), leaked) {
+			t.Fatalf("expected %q redacted, got %s", leaked, data)
+		}
+	}
+	for _, kept := range []string{"help@example.com", "1234567890123", `"phone":"***"`, "card [REDACTED], employee [REDACTED]"} {
+		if !strings.Contains(string(data), kept) {
+			t.Fatalf("expected %q in the documentation, got %s", kept, data)
+		}
+	}
+
+	context, err := docs.GetAPIContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(context, "ops@corp.io") || strings.Contains(context, "ada@corp.io") {
+		t.Fatal("expected the AI context redacted")
+	}
+
+	app := docs.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"token":"abc","contact":"grace@corp.io","iban":"GB33BUKB20201555555555"}`)
+	}))
+	request := httptest.NewRequest("POST", "/signup", strings.NewReader(`{"email":"grace@corp.io"}`))
+	request.Header.Set("Content-Type", "application/json")
+	app.ServeHTTP(httptest.NewRecorder(), request)
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = json.Marshal(docs.GetDocumentation())
+	if strings.Contains(string(data), "grace@corp.io") || strings.Contains(string(data), "GB33BUKB20201555555555") || !strings.Contains(string(data), "captured-1") {
+		t.Fatalf("expected captured traffic redacted, got %s", data)
+	}
+
+	if err := ValidateConfig(&Config{Title: "Test", DocsPath: "/docs", Redaction: &RedactionConfig{Enabled: true, Patterns: []string{"("}}}); err == nil {
+		t.Fatal("expected an invalid redaction pattern to be rejected")
+	}
+}
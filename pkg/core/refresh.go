@@ -0,0 +1,184 @@
+package core
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RefreshConfig enables POST <docs path>/refresh, which clears the analysis
+// caches and regenerates the documentation, e.g. from a CI/CD pipeline after
+// a deploy. The endpoint stays disabled until a token or webhook secret is set.
+type RefreshConfig struct {
+	Token         string // Sent as "Authorization: Bearer <token>" by scripts and pipelines
+	WebhookSecret string // Secret of a GitHub (X-Hub-Signature-256) or GitLab (X-Gitlab-Token) webhook
+}
+
+// maxRefreshBody bounds the webhook payload read to verify its signature
+const maxRefreshBody = 1 << 20
+
+// refreshResult is the response of a successful refresh
+type refreshResult struct {
+	Status      string `json:"status"`
+	Endpoints   int    `json:"endpoints"`
+	GeneratedAt string `json:"generatedAt"`
+}
+
+// OnRefresh registers a function that runs at the start of every Refresh,
+// before routes are detected again, e.g. to drop analysis caches
+func (a *APIDocs) OnRefresh(fn func()) {
+	if fn == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.refreshHooks = append(a.refreshHooks, fn)
+}
+
+// Refresh runs the OnRefresh hooks, detects the framework routes again,
+// fetches the federated specs and regenerates the documentation. Routes added with AddRoute are kept; the
+// previously detected routes are replaced once detection finished, so docs
+// requests during a refresh keep seeing the previous documentation.
+func (a *APIDocs) Refresh() error {
+	// The first detection must have run, or it would add the routes again later
+	a.build()
+
+	a.refreshMu.Lock()
+	defer a.refreshMu.Unlock()
+
+	a.mu.Lock()
+	hooks := append([]func(){}, a.refreshHooks...)
+	detect := a.detector
+	a.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook()
+	}
+	if detect != nil {
+		a.detectRoutes(detect)
+	}
+	// Failed services keep their previous endpoints; OnError reports them
+	a.fetchFederation(context.Background())
+	a.dirty.Store(true)
+	return a.Generate()
+}
+
+// detectRoutes runs detect with AddRouteInfo staging its routes, then
+// replaces the routes of the previous detection with them
+func (a *APIDocs) detectRoutes(detect func()) {
+	a.mu.Lock()
+	a.detecting = true
+	a.staged = nil
+	a.mu.Unlock()
+
+	defer func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.routes = a.currentRoutes()
+		a.detecting = false
+		a.staged = nil
+		a.routeTemplates.Store(nil)
+		a.dirty.Store(true)
+	}()
+
+	detect()
+}
+
+// currentRoutes returns the registered routes. During a detection the staged
+// routes stand in for the detected ones, since detectors generate the docs
+// themselves before they return. Callers hold a.mu.
+func (a *APIDocs) currentRoutes() []RouteInfo {
+	if !a.detecting {
+		return a.routes
+	}
+	routes := make([]RouteInfo, 0, len(a.routes)+len(a.staged))
+	for _, route := range a.routes {
+		if !route.detected {
+			routes = append(routes, route)
+		}
+	}
+	return append(routes, a.staged...)
+}
+
+// serveRefresh handles POST /refresh. It authenticates with its own token or
+// webhook secret instead of the docs login, since pipelines cannot sign in.
+func (a *APIDocs) serveRefresh(w http.ResponseWriter, r *http.Request) {
+	settings := a.config.Refresh
+	if settings == nil || (settings.Token == "" && settings.WebhookSecret == "") {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRefreshBody))
+	if err != nil {
+		http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !settings.authorized(r, body) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := a.Refresh(); err != nil {
+		http.Error(w, "Failed to refresh documentation: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	endpoints := 0
+	for _, section := range a.GetDocumentation().Endpoints {
+		endpoints += len(section.Endpoints)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(refreshResult{
+		Status:      "refreshed",
+		Endpoints:   endpoints,
+		GeneratedAt: time.Unix(a.generatedAt.Load(), 0).UTC().Format(time.RFC3339),
+	})
+}
+
+// authorized accepts the bearer token, a GitHub signature of the body or the
+// GitLab webhook token
+func (c *RefreshConfig) authorized(r *http.Request, body []byte) bool {
+	if c.Token != "" {
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && subtle.ConstantTimeCompare([]byte(token), []byte(c.Token)) == 1 {
+			return true
+		}
+	}
+	if c.WebhookSecret == "" {
+		return false
+	}
+	if signature := r.Header.Get("X-Hub-Signature-256"); signature != "" {
+		return validWebhookSignature(c.WebhookSecret, body, signature)
+	}
+	if token := r.Header.Get("X-Gitlab-Token"); token != "" {
+		return subtle.ConstantTimeCompare([]byte(token), []byte(c.WebhookSecret)) == 1
+	}
+	return false
+}
+
+// validWebhookSignature checks a GitHub "sha256=<hex HMAC of the body>" signature
+func validWebhookSignature(secret string, body []byte, signature string) bool {
+	digest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	expected, err := hex.DecodeString(digest)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRefresh_RedetectsRoutesBehindTokenOrWebhookSignature(t *testing.T) {
+	docs := New(&Config{
+		Title:      "Test",
+		Version:    "1.0.0",
+		DocsPath:   "/docs",
+		AuthConfig: &AuthConfig{Enabled: true, Type: "basic", Username: "admin", Password: "secret"},
+		Refresh:    &RefreshConfig{Token: "deploy-token", WebhookSecret: "hook-secret"},
+	})
+	detected := []string{"/users"}
+	hooks := 0
+	docs.SetRouteDetector(func() {
+		for _, path := range detected {
+			docs.AddRoute("GET", path, nil)
+		}
+		docs.Generate()
+	})
+	docs.OnRefresh(func() { hooks++ })
+	docs.AddRoute("GET", "/manual", nil)
+
+	paths := func() []string {
+		var paths []string
+		for _, section := range docs.GetDocumentation().Endpoints {
+			for _, endpoint := range section.Endpoints {
+				paths = append(paths, endpoint.Path)
+			}
+		}
+		sort.Strings(paths)
+		return paths
+	}
+	refresh := func(header, value, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/docs/refresh", strings.NewReader(body))
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		docs.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if err := docs.Warmup(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := paths(); fmt.Sprint(got) != "[/manual /users]" {
+		t.Fatalf("expected the manual and detected routes, got %v", got)
+	}
+
+	detected = []string{"/users", "/orders"}
+	if rec := refresh("", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without credentials, got %d", rec.Code)
+	}
+	if rec := refresh("Authorization", "Bearer wrong", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a wrong token, got %d", rec.Code)
+	}
+	rec := refresh("Authorization", "Bearer deploy-token", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"endpoints":3`) {
+		t.Fatalf("expected a refresh with 3 endpoints, got %d %s", rec.Code, rec.Body.String())
+	}
+	if got := paths(); fmt.Sprint(got) != "[/manual /orders /users]" || hooks != 1 {
+		t.Fatalf("expected the detected routes to be replaced once, got %v after %d hooks", got, hooks)
+	}
+
+	payload := `{"ref":"refs/heads/main"}`
+	mac := hmac.New(sha256.New, []byte("hook-secret"))
+	mac.Write([]byte(payload))
+	detected = []string{"/orders"}
+	if rec := refresh("X-Hub-Signature-256", "sha256=00", payload); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a bad GitHub signature, got %d", rec.Code)
+	}
+	if rec := refresh("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)), payload); rec.Code != http.StatusOK {
+		t.Fatalf("expected a signed GitHub webhook to refresh, got %d", rec.Code)
+	}
+	if rec := refresh("X-Gitlab-Token", "hook-secret", payload); rec.Code != http.StatusOK {
+		t.Fatalf("expected a GitLab webhook to refresh, got %d", rec.Code)
+	}
+	if got := paths(); fmt.Sprint(got) != "[/manual /orders]" {
+		t.Fatalf("expected removed routes to disappear, got %v", got)
+	}
+
+	get := httptest.NewRecorder()
+	docs.ServeHTTP(get, httptest.NewRequest("GET", "/docs/refresh", nil))
+	if get.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected GET to be rejected, got %d", get.Code)
+	}
+
+	disabled := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs"})
+	rec = httptest.NewRecorder()
+	disabled.ServeHTTP(rec, httptest.NewRequest("POST", "/docs/refresh", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected the endpoint to be disabled without credentials, got %d", rec.Code)
+	}
+}
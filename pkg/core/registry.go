@@ -0,0 +1,233 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Tenant is the documentation of one API served by a Registry
+type Tenant struct {
+	Name   string   // unique name, e.g. the service name
+	Host   string   // request host, e.g. "docs.orders.example.com"; empty serves the tenant on every host
+	Prefix string   // path prefix, e.g. "/orders" serves the docs at /orders/docs; empty mounts them at the root
+	Docs   *APIDocs // built with New or a parser Setup*Docs function
+}
+
+// Registry serves the documentation of several APIs from one process, e.g. a
+// central docs gateway. Every tenant keeps its own Config, so auth, AI,
+// refresh and analytics settings stay separate per API. Requests go to the
+// tenant with the matching host and the longest matching prefix; the root of
+// a host without a tenant lists the docs available there.
+type Registry struct {
+	// TrustedProxies are the IPs or CIDRs of reverse proxies in front of the
+	// registry whose X-Forwarded-Prefix is honored
+	TrustedProxies []string
+
+	mu      sync.RWMutex
+	tenants []Tenant
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Mount adds a tenant. Mount tenants before serving requests: session auth
+// cookies of a tenant are scoped by its name, unless AuthConfig.CookieScope
+// is set, so logging in to one API does not end the session of another.
+func (g *Registry) Mount(tenant Tenant) error {
+	if tenant.Name == "" {
+		return fmt.Errorf("tenant needs a name")
+	}
+	if tenant.Docs == nil {
+		return fmt.Errorf("tenant %q has no docs", tenant.Name)
+	}
+	tenant.Host = strings.ToLower(hostname(tenant.Host))
+	tenant.Prefix = normalizePathPrefix(tenant.Prefix)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, mounted := range g.tenants {
+		switch {
+		case mounted.Name == tenant.Name:
+			return fmt.Errorf("tenant %q is already mounted", tenant.Name)
+		case mounted.Docs == tenant.Docs:
+			return fmt.Errorf("docs of tenant %q are already mounted as %q", tenant.Name, mounted.Name)
+		case mounted.Host == tenant.Host && mounted.docsMount() == tenant.docsMount():
+			return fmt.Errorf("tenant %q already serves %s%s", mounted.Name, mounted.Host, mounted.docsMount())
+		}
+	}
+
+	// Applied when the docs build their auth, leaving the caller's Config and
+	// AuthConfig, which tenants may share, untouched
+	tenant.Docs.cookieScope = cookieScope(tenant.Name)
+	g.tenants = append(g.tenants, tenant)
+	return nil
+}
+
+// Unmount removes a tenant and closes its docs, reporting whether it was mounted
+func (g *Registry) Unmount(name string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i, tenant := range g.tenants {
+		if tenant.Name == name {
+			g.tenants = append(g.tenants[:i:i], g.tenants[i+1:]...)
+			tenant.Docs.Close()
+			return true
+		}
+	}
+	return false
+}
+
+// Docs returns the documentation of a tenant by name
+func (g *Registry) Docs(name string) (*APIDocs, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	for _, tenant := range g.tenants {
+		if tenant.Name == name {
+			return tenant.Docs, true
+		}
+	}
+	return nil, false
+}
+
+// Tenants returns the mounted tenants sorted by name
+func (g *Registry) Tenants() []Tenant {
+	g.mu.RLock()
+	tenants := append([]Tenant{}, g.tenants...)
+	g.mu.RUnlock()
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].Name < tenants[j].Name })
+	return tenants
+}
+
+// Warmup builds the documentation of every tenant, see APIDocs.Warmup
+func (g *Registry) Warmup(ctx context.Context) error {
+	for _, tenant := range g.Tenants() {
+		if err := tenant.Docs.Warmup(ctx); err != nil {
+			return fmt.Errorf("tenant %q: %w", tenant.Name, err)
+		}
+	}
+	return nil
+}
+
+// ServeHTTP serves the docs of the tenant matching the request. The tenant
+// sees the request below its prefix, and uses the prefix as its external
+// prefix, so its UI, spec servers and redirects keep the public URLs.
+func (g *Registry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// A trusted proxy in front of the gateway may forward its own prefix
+	outer := ""
+	if forwarded := r.Header.Get("X-Forwarded-Prefix"); forwarded != "" && fromTrustedProxy(r, g.TrustedProxies) {
+		outer = normalizePathPrefix(strings.Split(forwarded, ",")[0])
+	}
+	path := r.URL.Path
+	if outer != "" && (path == outer || strings.HasPrefix(path, outer+"/")) {
+		path = strings.TrimPrefix(path, outer)
+	}
+
+	tenant, ok := g.match(strings.ToLower(hostname(r.Host)), path)
+	if !ok {
+		if path == "" || path == "/" {
+			g.serveIndex(w, r, outer)
+			return
+		}
+		http.NotFound(w, r)
+		return
+	}
+
+	routed := withMountPrefix(r, outer+tenant.Prefix)
+	routed.URL = new(url.URL)
+	*routed.URL = *r.URL
+	routed.URL.Path = strings.TrimPrefix(path, tenant.Prefix)
+	routed.URL.RawPath = ""
+	tenant.Docs.ServeHTTP(w, routed)
+}
+
+// docsMount is the path the tenant's docs are served under, e.g. "/orders/docs"
+func (t Tenant) docsMount() string {
+	return t.Prefix + strings.TrimSuffix(t.Docs.config.DocsPath, "/")
+}
+
+// match returns the tenant of host whose docs path is the longest prefix of
+// path; tenants of the host win over tenants served on every host
+func (g *Registry) match(host, path string) (Tenant, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var best Tenant
+	found := false
+	for _, tenant := range g.tenants {
+		if tenant.Host != "" && tenant.Host != host {
+			continue
+		}
+		mount := tenant.docsMount()
+		if mount != "" && path != mount && !strings.HasPrefix(path, mount+"/") {
+			continue
+		}
+		if found {
+			if longest := len(best.docsMount()); len(mount) < longest || len(mount) == longest && tenant.Host == "" {
+				continue
+			}
+		}
+		best, found = tenant, true
+	}
+	return best, found
+}
+
+// serveIndex lists the docs served on the request's host
+func (g *Registry) serveIndex(w http.ResponseWriter, r *http.Request, outer string) {
+	host := strings.ToLower(hostname(r.Host))
+	var links strings.Builder
+	for _, tenant := range g.Tenants() {
+		if tenant.Host != "" && tenant.Host != host {
+			continue
+		}
+		config := tenant.Docs.config
+		title := config.Title
+		if title == "" {
+			title = tenant.Name
+		}
+		href := outer + tenant.docsMount() + "/"
+		fmt.Fprintf(&links, "        <li><a href=\"%s\">%s</a> <small>%s</small></li>\n",
+			html.EscapeString(href), html.EscapeString(title), html.EscapeString(config.Version))
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <title>API Documentation</title>
+    <style>body { font-family: Inter, system-ui, sans-serif; max-width: 720px; margin: 40px auto; color: #333; } li { margin: 8px 0; } small { color: #6b7280; }</style>
+</head>
+<body>
+    <h1>API Documentation</h1>
+    <ul>
+%s    </ul>
+</body>
+</html>`, links.String())
+}
+
+// hostname strips the port of a host
+func hostname(host string) string {
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		return name
+	}
+	return host
+}
+
+// cookieScope turns a tenant name into a cookie name suffix
+func cookieScope(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_RoutesTenantsByPrefixAndHost(t *testing.T) {
+	t.Chdir("../..") // auth templates are loaded relative to the module root
+	shared := &AuthConfig{Enabled: true, Type: "session", Password: "secret", SessionExpire: 60, LogoutEnabled: true}
+	orders := New(&Config{Title: "Orders API", Version: "2.0.0", DocsPath: "/docs", AuthConfig: shared})
+	orders.AddRoute("GET", "/orders", nil)
+	billing := New(&Config{Title: "Billing API", Version: "1.0.0", DocsPath: "/docs", AuthConfig: shared})
+	catalog := New(&Config{Title: "Catalog API", Version: "1.0.0", DocsPath: "/docs"})
+	partner := New(&Config{Title: "Partner API", Version: "1.0.0", DocsPath: "/docs"})
+
+	registry := NewRegistry()
+	for _, tenant := range []Tenant{
+		{Name: "orders", Prefix: "/orders", Docs: orders},
+		{Name: "billing", Prefix: "billing/", Docs: billing},
+		{Name: "catalog", Prefix: "/catalog", Docs: catalog},
+		{Name: "partner", Host: "Partners.Example.com:443", Docs: partner},
+	} {
+		if err := registry.Mount(tenant); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := registry.Mount(Tenant{Name: "orders-v2", Prefix: "/orders", Docs: New(&Config{DocsPath: "/docs"})}); err == nil || !strings.Contains(err.Error(), `"orders" already serves /orders/docs`) {
+		t.Fatalf("expected a clash with the orders tenant, got %v", err)
+	}
+	if err := registry.Mount(Tenant{Name: "orders", Docs: New(nil)}); err == nil {
+		t.Fatal("expected duplicate tenant names to be rejected")
+	}
+	if err := registry.Warmup(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(host, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", target, nil)
+		req.Host = host
+		rec := httptest.NewRecorder()
+		registry.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("docs.example.com", "/catalog/docs/openapi.json")
+	if rec.Code != 200 || !strings.Contains(rec.Body.String(), `"title":"Catalog API"`) || !strings.Contains(rec.Body.String(), `"url":"http://docs.example.com/catalog"`) {
+		t.Fatalf("expected the catalog spec with the prefix in its server URL, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := serve("docs.example.com", "/catalog/docs/"); !strings.Contains(rec.Body.String(), `"docsPath":"/catalog/docs"`) {
+		t.Fatalf("expected the catalog UI to build its URLs from the prefix")
+	}
+	if rec := serve("docs.example.com", "/catalog/other"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected paths outside the docs to be unknown, got %d", rec.Code)
+	}
+
+	// Tenants sharing an AuthConfig get separate session cookies
+	hasCookie := func(rec *httptest.ResponseRecorder, name string) bool {
+		for _, cookie := range rec.Result().Cookies() {
+			if cookie.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+	ordersLogin := serve("docs.example.com", "/orders/docs/")
+	billingLogin := serve("docs.example.com", "/billing/docs/")
+	if !hasCookie(ordersLogin, csrfCookieName+"_orders") || !hasCookie(billingLogin, csrfCookieName+"_billing") || shared.CookieScope != "" {
+		t.Fatalf("expected cookies scoped per tenant, got %v and %v", ordersLogin.Result().Cookies(), billingLogin.Result().Cookies())
+	}
+	if orders.GetConfig().AuthConfig != shared || billing.GetConfig().AuthConfig != shared {
+		t.Fatal("expected Mount to leave the tenants' configs untouched")
+	}
+	if rec := serve("docs.example.com", "/orders/docs/logout"); rec.Header().Get("Location") != "/orders/docs" {
+		t.Fatalf("expected logout to redirect below the prefix, got %q", rec.Header().Get("Location"))
+	}
+
+	if rec := serve("partners.example.com", "/docs/api-data.json"); !strings.Contains(rec.Body.String(), `"title":"Partner API"`) {
+		t.Fatalf("expected the partner docs on their host, got %d", rec.Code)
+	}
+	if rec := serve("docs.example.com", "/docs/api-data.json"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected the partner docs only on their host, got %d", rec.Code)
+	}
+
+	index := serve("docs.example.com", "/").Body.String()
+	if !strings.Contains(index, `<a href="/billing/docs/">Billing API</a>`) || !strings.Contains(index, `<a href="/orders/docs/">Orders API</a>`) || strings.Contains(index, "Partner API") {
+		t.Fatalf("expected the index to list the docs of the host:\n%s", index)
+	}
+
+	if !registry.Unmount("catalog") || registry.Unmount("catalog") {
+		t.Fatal("expected catalog to be unmounted once")
+	}
+	if rec := serve("docs.example.com", "/catalog/docs/openapi.json"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected unmounted docs to be gone, got %d", rec.Code)
+	}
+}
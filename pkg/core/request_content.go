@@ -0,0 +1,54 @@
+package core
+
+// Contents returns the primary content type of the body followed by its alternatives
+func (b *RequestBody) Contents() []BodyContent {
+	if b == nil {
+		return nil
+	}
+	contentType := b.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	contents := []BodyContent{{ContentType: contentType, Schema: b.Schema, Example: b.Example}}
+	return append(contents, b.Alternatives...)
+}
+
+// description returns the body's description, or else the description of
+// its schema, e.g. the doc comment of the request struct
+func (b *RequestBody) description() string {
+	if b.Description != "" {
+		return b.Description
+	}
+	if schema, ok := b.Schema.(map[string]interface{}); ok {
+		description, _ := schema["description"].(string)
+		return description
+	}
+	return ""
+}
+
+// hasContentType reports whether the body already documents contentType
+func (b *RequestBody) hasContentType(contentType string) bool {
+	for _, content := range b.Contents() {
+		if content.ContentType == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// withAccepts returns a copy of body that also accepts the given content
+// types with the primary schema
+func withAccepts(body *RequestBody, accepts []string) *RequestBody {
+	if body == nil || len(accepts) == 0 {
+		return body
+	}
+	// The body may be shared with the registered route; copy before adding
+	extended := *body
+	extended.Alternatives = append([]BodyContent{}, body.Alternatives...)
+	for _, contentType := range accepts {
+		if contentType != "" && !extended.hasContentType(contentType) {
+			extended.Alternatives = append(extended.Alternatives, BodyContent{ContentType: contentType, Schema: body.Schema, Example: body.Example})
+		}
+	}
+	return &extended
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestContentTypes_AlternativesAndAccepts(t *testing.T) {
+	type createUser struct {
+		Name string `json:"name"`
+	}
+	type createUserXML struct {
+		FullName string `json:"full_name"`
+	}
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs"})
+	docs.AddRoute("POST", "/users", nil,
+		WithRequest(createUser{}),
+		WithRequestAlternative("application/xml", createUserXML{}),
+		WithAccepts("application/x-yaml", "application/json"))
+	docs.AddRoute("POST", "/events", nil, WithAccepts("application/xml"))
+
+	spec, err := docs.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(spec)
+	var openAPI struct {
+		Paths map[string]map[string]struct {
+			RequestBody struct {
+				Content map[string]struct {
+					Schema struct{ Properties map[string]interface{} }
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(raw, &openAPI); err != nil {
+		t.Fatal(err)
+	}
+	content := openAPI.Paths["/users"]["post"].RequestBody.Content
+	if len(content) != 3 || content["application/xml"].Schema.Properties["full_name"] == nil ||
+		content["application/x-yaml"].Schema.Properties["name"] == nil {
+		t.Fatalf("expected JSON, XML with its own schema and YAML with the JSON schema, got %+v", content)
+	}
+	if len(openAPI.Paths["/events"]["post"].RequestBody.Content) != 0 {
+		t.Fatal("expected WithAccepts without a request body to add nothing")
+	}
+
+	swagger, err := docs.GetSwaggerJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	operation := swagger["paths"].(map[string]interface{})["/users"].(map[string]interface{})["post"].(map[string]interface{})
+	if consumes := operation["consumes"].([]string); len(consumes) != 3 || consumes[0] != "application/json" {
+		t.Fatalf("expected Swagger to consume every content type, got %v", consumes)
+	}
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/api-data.json", nil))
+	if !strings.Contains(rec.Body.String(), `"alternatives":[{"contentType":"application/xml"`) {
+		t.Fatalf("expected alternatives in the UI data, got %s", rec.Body.String())
+	}
+}
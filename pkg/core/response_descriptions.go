@@ -0,0 +1,22 @@
+package core
+
+// applyResponseDescriptions replaces the generated descriptions of the
+// responses, such as "Not Found", with the ones from @Response or
+// WithResponseDescription, documenting statuses the handler analysis missed
+// as responses without a body
+func applyResponseDescriptions(endpoint *Endpoint, descriptions map[string]string) {
+	if len(descriptions) == 0 {
+		return
+	}
+	// The responses map may be shared with the registered route; copy before changing
+	responses := make(map[string]Response, len(endpoint.Responses)+len(descriptions))
+	for status, response := range endpoint.Responses {
+		responses[status] = response
+	}
+	for status, description := range descriptions {
+		response := responses[status]
+		response.Description = description
+		responses[status] = response
+	}
+	endpoint.Responses = responses
+}
@@ -0,0 +1,37 @@
+package core
+
+import "testing"
+
+func TestResponseDescriptions_ReplaceStatusText(t *testing.T) {
+	docs := New(&Config{DocsPath: "/docs"})
+	docs.AddRouteInfo(RouteInfo{
+		Method: "GET",
+		Path:   "/users/{id}",
+		Responses: map[string]Response{
+			"200": {Description: "OK"},
+			"404": {Description: "Not Found", ContentType: "application/json", Schema: map[string]interface{}{"type": "object"}},
+		},
+		ResponseDocs: map[string]string{"404": "User not found with the given ID"},
+	})
+	docs.AddRoute("DELETE", "/users/{id}", nil, WithResponseDescription(409, "User still owns projects"))
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	endpoints := map[string]*Endpoint{}
+	for _, section := range docs.GetDocumentation().Endpoints {
+		for i := range section.Endpoints {
+			endpoints[section.Endpoints[i].Method] = &section.Endpoints[i]
+		}
+	}
+	notFound := endpoints["GET"].Responses["404"]
+	if notFound.Description != "User not found with the given ID" || notFound.Schema == nil {
+		t.Fatalf("expected the described 404 to keep its schema, got %+v", notFound)
+	}
+	if endpoints["GET"].Responses["200"].Description != "OK" {
+		t.Fatalf("expected undescribed responses to keep their description, got %+v", endpoints["GET"].Responses)
+	}
+	if conflict, ok := endpoints["DELETE"].Responses["409"]; !ok || conflict.Description != "User still owns projects" {
+		t.Fatalf("expected a response for the described status, got %+v", endpoints["DELETE"].Responses)
+	}
+}
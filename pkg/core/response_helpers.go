@@ -0,0 +1,68 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ResponseHelper is an application function that writes a response, such as
+// respondJSON(w, status, data)
+type ResponseHelper struct {
+	StatusArg   int    // index of the status code argument; -1 when it always responds 200
+	DataArg     int    // index of the payload argument; -1 when it writes no body
+	ContentType string // media type of the payload; empty means application/json
+}
+
+var (
+	responseHelpers      = make(map[string]ResponseHelper)
+	responseHelpersMutex sync.RWMutex
+)
+
+// RegisterResponseHelper documents calls to the named function, such as
+// respondJSON(w, status, data) with statusArg 1 and dataArg 2, as responses
+// with the status code and payload of those arguments. Calls are matched by
+// function or method name; a package qualifier in name is ignored. Register
+// helpers before the docs are first generated.
+func RegisterResponseHelper(name string, statusArg, dataArg int) {
+	responseHelpersMutex.Lock()
+	defer responseHelpersMutex.Unlock()
+	responseHelpers[helperName(name)] = ResponseHelper{StatusArg: statusArg, DataArg: dataArg}
+}
+
+// UnregisterResponseHelper removes a helper registered with RegisterResponseHelper
+func UnregisterResponseHelper(name string) {
+	responseHelpersMutex.Lock()
+	defer responseHelpersMutex.Unlock()
+	delete(responseHelpers, helperName(name))
+}
+
+// LookupResponseHelper returns the registered helper with the given function name
+func LookupResponseHelper(name string) (ResponseHelper, bool) {
+	responseHelpersMutex.RLock()
+	defer responseHelpersMutex.RUnlock()
+	helper, ok := responseHelpers[helperName(name)]
+	return helper, ok
+}
+
+// ResponseHelpersFingerprint identifies the registered helpers, so persisted
+// analyses are redone when they change
+func ResponseHelpersFingerprint() string {
+	responseHelpersMutex.RLock()
+	defer responseHelpersMutex.RUnlock()
+	entries := make([]string, 0, len(responseHelpers))
+	for name, helper := range responseHelpers {
+		entries = append(entries, fmt.Sprintf("%s:%d:%d:%s", name, helper.StatusArg, helper.DataArg, helper.ContentType))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+func helperName(name string) string {
+	name = strings.TrimSpace(name)
+	if index := strings.LastIndex(name, "."); index >= 0 {
+		return name[index+1:]
+	}
+	return name
+}
@@ -0,0 +1,23 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResponseHelpers_Registry(t *testing.T) {
+	RegisterResponseHelper("httputil.RespondJSON", 1, 2)
+	defer UnregisterResponseHelper("RespondJSON")
+
+	helper, ok := LookupResponseHelper("RespondJSON")
+	if !ok || helper.StatusArg != 1 || helper.DataArg != 2 {
+		t.Fatalf("expected the helper without its package qualifier, got %+v", helper)
+	}
+	if fingerprint := ResponseHelpersFingerprint(); !strings.Contains(fingerprint, "RespondJSON:1:2") {
+		t.Fatalf("expected the helper in the fingerprint, got %q", fingerprint)
+	}
+	UnregisterResponseHelper("RespondJSON")
+	if _, ok := LookupResponseHelper("RespondJSON"); ok {
+		t.Fatal("expected the helper to be removed")
+	}
+}
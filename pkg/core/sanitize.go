@@ -0,0 +1,250 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// allowedMarkupTags are the formatting elements kept in descriptions rendered by the UI
+var allowedMarkupTags = map[string]bool{
+	"a": true, "abbr": true, "b": true, "blockquote": true, "br": true, "code": true,
+	"del": true, "em": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true,
+	"h6": true, "hr": true, "i": true, "kbd": true, "li": true, "ol": true, "p": true,
+	"pre": true, "s": true, "small": true, "span": true, "strong": true, "sub": true,
+	"sup": true, "table": true, "tbody": true, "td": true, "th": true, "thead": true,
+	"tr": true, "u": true, "ul": true,
+}
+
+// droppedMarkupTags are removed together with their content
+var droppedMarkupTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "template": true, "textarea": true, "title": true, "svg": true,
+	"math": true, "xmp": true, "noembed": true, "noframes": true,
+}
+
+// markupTextEscaper escapes text for element content and quoted attributes
+var markupTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&#34;")
+
+// scriptJSON encodes v for an inline <script> block. encoding/json escapes
+// <, >, & and U+2028/U+2029, so values cannot close the script element or
+// break the JavaScript source.
+func scriptJSON(v interface{}) (template.JS, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(true)
+	if err := encoder.Encode(v); err != nil {
+		return "", err
+	}
+	return template.JS(bytes.TrimSpace(buf.Bytes())), nil
+}
+
+// sanitizeMarkup keeps basic formatting markup of a description and escapes
+// everything else. Scripts, event handler attributes and javascript: links are removed.
+func sanitizeMarkup(markup string) string {
+	if !strings.ContainsAny(markup, `<>&"`) {
+		return markup
+	}
+
+	var out strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(markup))
+	skipping := ""
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			return out.String()
+		case html.TextToken:
+			if skipping == "" {
+				out.WriteString(markupTextEscaper.Replace(string(tokenizer.Text())))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if skipping != "" {
+				continue
+			}
+			if droppedMarkupTags[token.Data] {
+				if tokenType == html.StartTagToken {
+					skipping = token.Data
+				}
+				continue
+			}
+			if allowedMarkupTags[token.Data] {
+				writeMarkupTag(&out, token)
+			}
+		case html.EndTagToken:
+			token := tokenizer.Token()
+			if skipping != "" {
+				if token.Data == skipping {
+					skipping = ""
+				}
+				continue
+			}
+			if allowedMarkupTags[token.Data] {
+				out.WriteString("</" + token.Data + ">")
+			}
+		}
+	}
+}
+
+// writeMarkupTag writes an allowed start tag with its safe attributes only
+func writeMarkupTag(out *strings.Builder, token html.Token) {
+	out.WriteString("<" + token.Data)
+	for _, attr := range token.Attr {
+		switch {
+		case attr.Key == "title":
+		case attr.Key == "href" && token.Data == "a" && isSafeLink(attr.Val):
+		default:
+			continue
+		}
+		out.WriteString(" " + attr.Key + `="` + markupTextEscaper.Replace(attr.Val) + `"`)
+	}
+	if token.Type == html.SelfClosingTagToken {
+		out.WriteString(" /")
+	}
+	out.WriteString(">")
+}
+
+// isSafeLink allows relative, http(s) and mailto links
+func isSafeLink(link string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// uiDocumentation returns the documentation embedded into the docs page, with
+// comment-sourced text sanitized unless raw markup is allowed
+func (a *APIDocs) uiDocumentation() *Documentation {
+	doc := a.GetDocumentation()
+	if a.config.AllowRawMarkup {
+		return doc
+	}
+	return sanitizeDocumentation(doc)
+}
+
+// sanitizeDocumentation copies doc with every descriptive text field sanitized
+func sanitizeDocumentation(doc *Documentation) *Documentation {
+	sanitized := &Documentation{
+		Info:      doc.Info,
+		Endpoints: make([]EndpointSection, 0, len(doc.Endpoints)),
+		Guides:    doc.Guides, // markdown, sanitized after rendering in the UI
+		Warnings:  doc.Warnings,
+	}
+	sanitized.Info.Title = sanitizeMarkup(doc.Info.Title)
+	sanitized.Info.Description = sanitizeMarkup(doc.Info.Description)
+
+	if len(doc.Schemas) > 0 {
+		sanitized.Schemas = make(map[string]Schema, len(doc.Schemas))
+		for name, schema := range doc.Schemas {
+			if len(schema.Properties) > 0 {
+				properties := make(map[string]Property, len(schema.Properties))
+				for propertyName, property := range schema.Properties {
+					property.Description = sanitizeMarkup(property.Description)
+					properties[propertyName] = property
+				}
+				schema.Properties = properties
+			}
+			sanitized.Schemas[name] = schema
+		}
+	}
+
+	for _, section := range doc.Endpoints {
+		sectionCopy := section
+		sectionCopy.Name = sanitizeMarkup(section.Name)
+		sectionCopy.Description = sanitizeMarkup(section.Description)
+		sectionCopy.Endpoints = make([]Endpoint, 0, len(section.Endpoints))
+		for _, endpoint := range section.Endpoints {
+			sectionCopy.Endpoints = append(sectionCopy.Endpoints, sanitizeEndpoint(endpoint))
+		}
+		sanitized.Endpoints = append(sanitized.Endpoints, sectionCopy)
+	}
+
+	return sanitized
+}
+
+func sanitizeEndpoint(endpoint Endpoint) Endpoint {
+	endpoint.Summary = sanitizeMarkup(endpoint.Summary)
+	endpoint.Description = sanitizeMarkup(endpoint.Description)
+
+	if len(endpoint.Parameters) > 0 {
+		params := make([]Parameter, len(endpoint.Parameters))
+		for i, param := range endpoint.Parameters {
+			param.Description = sanitizeMarkup(param.Description)
+			params[i] = param
+		}
+		endpoint.Parameters = params
+	}
+
+	if endpoint.RequestBody != nil {
+		body := *endpoint.RequestBody
+		body.Schema = sanitizeSchemaDescriptions(body.Schema)
+		if len(body.Alternatives) > 0 {
+			body.Alternatives = make([]BodyContent, len(endpoint.RequestBody.Alternatives))
+			for i, content := range endpoint.RequestBody.Alternatives {
+				content.Schema = sanitizeSchemaDescriptions(content.Schema)
+				body.Alternatives[i] = content
+			}
+		}
+		endpoint.RequestBody = &body
+	}
+
+	if len(endpoint.Responses) > 0 {
+		responses := make(map[string]Response, len(endpoint.Responses))
+		for status, response := range endpoint.Responses {
+			response.Description = sanitizeMarkup(response.Description)
+			response.Schema = sanitizeSchemaDescriptions(response.Schema)
+			responses[status] = response
+		}
+		endpoint.Responses = responses
+	}
+
+	return endpoint
+}
+
+// sanitizeSchemaDescriptions copies a JSON schema with its descriptions sanitized
+func sanitizeSchemaDescriptions(schema interface{}) interface{} {
+	switch value := schema.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			switch key {
+			case "description":
+				if text, ok := item.(string); ok {
+					item = sanitizeMarkup(text)
+				}
+			case "properties":
+				// keys are property names, each value is a schema
+				if properties, ok := item.(map[string]interface{}); ok {
+					sanitizedProperties := make(map[string]interface{}, len(properties))
+					for name, property := range properties {
+						sanitizedProperties[name] = sanitizeSchemaDescriptions(property)
+					}
+					item = sanitizedProperties
+				}
+			case "example", "examples", "default", "enum":
+				// values are shown as escaped JSON, not markup
+			default:
+				item = sanitizeSchemaDescriptions(item)
+			}
+			copied[key] = item
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(value))
+		for i, item := range value {
+			copied[i] = sanitizeSchemaDescriptions(item)
+		}
+		return copied
+	}
+	return schema
+}
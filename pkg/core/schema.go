@@ -0,0 +1,233 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+)
+
+// SchemaFromValue builds an OpenAPI-style schema and an example from a Go value
+// using reflection. Non-zero field values are used as examples; otherwise the
+// `example` struct tag or a type-based default is used.
+func SchemaFromValue(value interface{}) (interface{}, interface{}) {
+	if value == nil {
+		return nil, nil
+	}
+	return schemaFromReflect(reflect.ValueOf(value), make(map[reflect.Type]bool))
+}
+
+func schemaFromReflect(v reflect.Value, visited map[reflect.Type]bool) (map[string]interface{}, interface{}) {
+	t := v.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		if v.IsValid() && !v.IsNil() {
+			v = v.Elem()
+		} else {
+			v = reflect.Zero(t)
+		}
+	}
+
+	if t == timeType {
+		example := "2024-01-01T00:00:00Z"
+		if tm, ok := v.Interface().(time.Time); ok && !tm.IsZero() {
+			example = tm.Format(time.RFC3339)
+		}
+		return map[string]interface{}{"type": "string", "format": "date-time"}, example
+	}
+
+	// encoding/json copies a RawMessage as is and sends other byte slices as
+	// base64 strings
+	if t == rawMessageType {
+		var example interface{} = map[string]interface{}{}
+		if raw, ok := v.Interface().(json.RawMessage); ok && len(raw) > 0 {
+			var decoded interface{}
+			if json.Unmarshal(raw, &decoded) == nil {
+				example = decoded
+			}
+		}
+		return map[string]interface{}{"type": "object"}, example
+	}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		example := "Ynl0ZXM="
+		if v.Len() > 0 {
+			example = base64.StdEncoding.EncodeToString(v.Bytes())
+		}
+		return map[string]interface{}{"type": "string", "format": "byte"}, example
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		if s := v.String(); s != "" {
+			return map[string]interface{}{"type": "string"}, s
+		}
+		return map[string]interface{}{"type": "string"}, "string"
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return integerSchema(t), v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return integerSchema(t), v.Uint()
+	case reflect.Float32, reflect.Float64:
+		schema := map[string]interface{}{"type": "number", "format": "double"}
+		if t.Kind() == reflect.Float32 {
+			schema["format"] = "float"
+		}
+		return schema, v.Float()
+	case reflect.Slice, reflect.Array:
+		itemSchema, itemExample := schemaFromReflect(reflect.Zero(t.Elem()), visited)
+		if v.Len() > 0 {
+			_, itemExample = schemaFromReflect(v.Index(0), visited)
+		}
+		example := []interface{}{}
+		if itemExample != nil {
+			example = append(example, itemExample)
+		}
+		return map[string]interface{}{"type": "array", "items": itemSchema}, example
+	case reflect.Map:
+		valueSchema, valueExample := schemaFromReflect(reflect.Zero(t.Elem()), visited)
+		schema := map[string]interface{}{"type": "object", "additionalProperties": valueSchema}
+		key := MapKeyHints(schema, t.Key().Name(), t.Key().Kind().String())
+		example := map[string]interface{}{}
+		if valueExample != nil {
+			example[key] = valueExample
+		}
+		return schema, example
+	case reflect.Struct:
+		if visited[t] {
+			return map[string]interface{}{"type": "object"}, map[string]interface{}{}
+		}
+		visited[t] = true
+		defer delete(visited, t)
+		return structSchemaFromReflect(v, visited)
+	case reflect.Interface:
+		if registered, ok := lookupUnion(t); ok {
+			return unionSchemaFromReflect(registered, visited)
+		}
+		return map[string]interface{}{"type": "object"}, map[string]interface{}{}
+	default:
+		return map[string]interface{}{"type": "object"}, map[string]interface{}{}
+	}
+}
+
+func integerSchema(t reflect.Type) map[string]interface{} {
+	schema := map[string]interface{}{"type": "integer"}
+	switch t.Kind() {
+	case reflect.Int32, reflect.Uint32:
+		schema["format"] = "int32"
+	case reflect.Int64, reflect.Uint64:
+		schema["format"] = "int64"
+	}
+	return schema
+}
+
+func structSchemaFromReflect(v reflect.Value, visited map[reflect.Type]bool) (map[string]interface{}, interface{}) {
+	t := v.Type()
+	properties := make(map[string]interface{})
+	example := make(map[string]interface{})
+	required := make([]string, 0)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := v.Field(i)
+
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embeddedSchema, embeddedExample := schemaFromReflect(fieldValue, visited)
+			if props, ok := embeddedSchema["properties"].(map[string]interface{}); ok {
+				for key, val := range props {
+					properties[key] = val
+				}
+			}
+			if req, ok := embeddedSchema["required"].([]string); ok {
+				required = append(required, req...)
+			}
+			if exMap, ok := embeddedExample.(map[string]interface{}); ok {
+				for key, val := range exMap {
+					example[key] = val
+				}
+			}
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		jsonTag := field.Tag.Get("json")
+		name := strings.Split(jsonTag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name // encoding/json keeps untagged names as declared
+		}
+
+		schema, fieldExample := schemaFromReflect(fieldValue, visited)
+		description, readOnly, writeOnly := FieldAccessMode(field.Tag.Get, field.Tag.Get("description"))
+		if description != "" {
+			schema["description"] = description
+		}
+		SetAccessMode(schema, readOnly, writeOnly)
+		MapKeyTags(schema, field.Tag.Get)
+		FieldExtensions(schema, field.Tag.Get)
+		if tagExample := field.Tag.Get("example"); tagExample != "" && fieldValue.IsZero() {
+			fieldExample = exampleFromTag(tagExample, schema)
+		}
+
+		properties[name] = schema
+		example[name] = fieldExample
+
+		if isRequiredTag(jsonTag, field.Tag.Get("binding"), field.Tag.Get("validate")) {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema, example
+}
+
+func exampleFromTag(raw string, schema map[string]interface{}) interface{} {
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var val interface{}
+		if err := json.Unmarshal([]byte(trimmed), &val); err == nil {
+			return val
+		}
+	}
+
+	switch schema["type"] {
+	case "integer":
+		if num, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+			return num
+		}
+	case "number":
+		if num, err := strconv.ParseFloat(trimmed, 64); err == nil {
+			return num
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(trimmed); err == nil {
+			return b
+		}
+	}
+	return raw
+}
+
+func isRequiredTag(jsonTag, bindingTag, validateTag string) bool {
+	if strings.Contains(jsonTag, "omitempty") || strings.Contains(bindingTag, "omitempty") {
+		return false
+	}
+	return strings.Contains(bindingTag, "required") || strings.Contains(validateTag, "required")
+}
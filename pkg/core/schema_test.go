@@ -0,0 +1,88 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWithRequest_UntaggedFieldsKeepTheirJSONNames(t *testing.T) {
+	type user struct {
+		Name   string
+		Email  string          `json:"email"`
+		Avatar []byte          `json:"avatar"`
+		Meta   json.RawMessage `json:"meta"`
+	}
+
+	docs := New(nil)
+	docs.AddRoute("POST", "/users", nil, WithRequest(user{Name: "Ada", Avatar: []byte("hi"), Meta: json.RawMessage(`{"plan":"pro"}`)}))
+	spec, err := docs.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+
+	media := spec["paths"].(map[string]interface{})["/users"].(map[string]interface{})["post"].(map[string]interface{})["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	properties := media["schema"].(map[string]interface{})["properties"].(map[string]interface{})
+	if _, ok := properties["Name"]; !ok || properties["name"] != nil || properties["email"] == nil {
+		t.Fatalf("expected the property names encoding/json sends, got %v", properties)
+	}
+	if avatar := properties["avatar"].(map[string]interface{}); avatar["type"] != "string" || avatar["format"] != "byte" {
+		t.Fatalf("expected []byte documented as a base64 string, got %v", avatar)
+	}
+	if meta := properties["meta"].(map[string]interface{}); meta["type"] != "object" || meta["items"] != nil {
+		t.Fatalf("expected json.RawMessage documented as a free-form object, got %v", meta)
+	}
+	example := media["example"].(map[string]interface{})
+	if example["Name"] != "Ada" {
+		t.Fatalf("expected the example keyed like the JSON body, got %v", example)
+	}
+	if example["avatar"] != "aGk=" || example["meta"].(map[string]interface{})["plan"] != "pro" {
+		t.Fatalf("expected the examples encoding/json sends, got %v", example)
+	}
+}
+
+func TestSchemaDescriptions_DescribeRequestBodies(t *testing.T) {
+	schema := map[string]interface{}{"type": "object", "description": "CreateOrder is the payload to place an order",
+		"properties": map[string]interface{}{"sku": map[string]interface{}{"type": "string"}}}
+	docs := New(&Config{DocsPath: "/docs"})
+	docs.AddRouteInfo(RouteInfo{Method: "POST", Path: "/orders", RequestBody: &RequestBody{ContentType: "application/json", Schema: schema}})
+	docs.AddRouteInfo(RouteInfo{Method: "PUT", Path: "/orders/:id", RequestBody: &RequestBody{ContentType: "application/json", Schema: schema, Description: "Replaces the order"}})
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := docs.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(spec)
+	var openAPI struct {
+		Paths map[string]map[string]struct {
+			RequestBody struct{ Description string } `json:"requestBody"`
+		}
+	}
+	if err := json.Unmarshal(raw, &openAPI); err != nil {
+		t.Fatal(err)
+	}
+	if got := openAPI.Paths["/orders"]["post"].RequestBody.Description; got != "CreateOrder is the payload to place an order" {
+		t.Fatalf("expected the schema description on the request body, got %q", got)
+	}
+	if got := openAPI.Paths["/orders/{id}"]["put"].RequestBody.Description; got != "Replaces the order" {
+		t.Fatalf("expected an explicit description to win, got %q", got)
+	}
+
+	swagger, err := docs.GetSwaggerJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := json.Marshal(swagger); !strings.Contains(string(raw), `"description":"CreateOrder is the payload to place an order","in":"body"`) {
+		t.Fatalf("expected the Swagger body parameter to be described, got %s", raw)
+	}
+	ts, err := docs.GetTypeScript()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(ts, "/** CreateOrder is the payload to place an order */\nexport interface") {
+		t.Fatalf("expected the TypeScript declaration to carry the description, got %s", ts)
+	}
+}
@@ -0,0 +1,952 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Scenario scripts are a few statements, one per line, run before a request
+// and after its response:
+//
+//	set ts = unix()
+//	header X-Signature = hmacSHA256(secret, method + "\n" + path + "\n" + ts + "\n" + body)
+//	assert status == 201, "user created"
+//	set userId = json("data.id")
+//
+// They have no loops and no access to files, the network or the environment,
+// so a script always ends and can only change the request, the scenario
+// variables and the test results.
+const (
+	maxScriptLength  = 64 << 10
+	maxScriptValue   = 1 << 20
+	maxScriptRunBody = 4 << 20
+)
+
+// script is a parsed scenario script
+type script struct {
+	statements []scriptStatement
+}
+
+type scriptStatement struct {
+	line    int
+	kind    string // "set", "header" or "assert"
+	target  string // variable or header name
+	expr    scriptNode
+	message scriptNode // of an assert, may be nil
+	source  string
+}
+
+// ScriptEnv is what a running script sees and changes
+type ScriptEnv struct {
+	Variables map[string]string // read and set by the script
+	Assigned  map[string]string // receives the variables the script sets, may be nil
+
+	// Before the request; body, url and path have the variables replaced
+	// when they are read
+	Request *ScriptRequest
+	Replace func(string) string // nil replaces {{name}} with Variables
+
+	// After the response
+	Response     *ScriptResponse
+	responseJSON interface{}
+	jsonParsed   bool
+
+	Headers map[string]string // headers set by the script
+	Tests   []ScriptTest
+}
+
+// replace fills in the variables of a request field; like the replace
+// function, it checks the size before building a value over maxScriptValue
+func (env *ScriptEnv) replace(text string) (string, error) {
+	if env.Replace != nil {
+		text = env.Replace(text)
+	} else {
+		for name, value := range env.Variables {
+			placeholder := "{{" + name + "}}"
+			if size := int64(len(text)) + int64(strings.Count(text, placeholder))*int64(len(value)-len(placeholder)); size > maxScriptValue {
+				return "", fmt.Errorf("value is longer than %d bytes", maxScriptValue)
+			}
+			text = strings.ReplaceAll(text, placeholder, value)
+		}
+	}
+	if len(text) > maxScriptValue {
+		return "", fmt.Errorf("value is longer than %d bytes", maxScriptValue)
+	}
+	return text, nil
+}
+
+// ScriptRequest is the request a pre-request script runs before
+type ScriptRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body"`
+}
+
+// ScriptResponse is the response a post-response script runs after
+type ScriptResponse struct {
+	StatusCode int                 `json:"status_code"`
+	Headers    map[string][]string `json:"headers"`
+	Body       string              `json:"body"`
+	Duration   int64               `json:"duration_ms"`
+}
+
+// ScriptTest is the outcome of an assert statement
+type ScriptTest struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// parseScript parses a script, reporting the first syntax error with its line
+func parseScript(source string) (*script, error) {
+	if len(source) > maxScriptLength {
+		return nil, fmt.Errorf("script is longer than %d bytes", maxScriptLength)
+	}
+	parsed := &script{}
+	for i, line := range strings.Split(source, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		statement, err := parseScriptStatement(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		statement.line = i + 1
+		parsed.statements = append(parsed.statements, statement)
+	}
+	return parsed, nil
+}
+
+func parseScriptStatement(line string) (scriptStatement, error) {
+	keyword, rest, _ := strings.Cut(line, " ")
+	statement := scriptStatement{kind: keyword, source: line}
+	switch keyword {
+	case "set", "header":
+		target, expression, ok := strings.Cut(rest, "=")
+		statement.target = strings.TrimSpace(target)
+		if !ok || statement.target == "" || strings.ContainsAny(statement.target, " \t\"'(){}") {
+			return statement, fmt.Errorf("expected %s <name> = <expression>", keyword)
+		}
+		parser, err := newScriptParser(expression)
+		if err != nil {
+			return statement, err
+		}
+		if statement.expr, err = parser.parseExpression(); err != nil {
+			return statement, err
+		}
+		if !parser.done() {
+			return statement, fmt.Errorf("unexpected %q", parser.peek().text)
+		}
+	case "assert":
+		parser, err := newScriptParser(rest)
+		if err != nil {
+			return statement, err
+		}
+		if statement.expr, err = parser.parseExpression(); err != nil {
+			return statement, err
+		}
+		statement.source = strings.TrimSpace(rest[:parser.peek().offset])
+		if parser.accept(",") {
+			if statement.message, err = parser.parseExpression(); err != nil {
+				return statement, err
+			}
+		}
+		if !parser.done() {
+			return statement, fmt.Errorf("unexpected %q", parser.peek().text)
+		}
+	default:
+		return statement, fmt.Errorf("unknown statement %q, use set, header or assert", keyword)
+	}
+	return statement, nil
+}
+
+// run executes the statements in order and stops at the first runtime
+// error; failed assertions are recorded in env.Tests and do not stop it
+func (s *script) run(env *ScriptEnv) error {
+	if env.Variables == nil {
+		env.Variables = map[string]string{}
+	}
+	if env.Headers == nil {
+		env.Headers = map[string]string{}
+	}
+	for _, statement := range s.statements {
+		value, err := statement.expr.eval(env)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", statement.line, err)
+		}
+		switch statement.kind {
+		case "set":
+			env.Variables[statement.target] = scriptString(value)
+			if env.Assigned != nil {
+				env.Assigned[statement.target] = scriptString(value)
+			}
+		case "header":
+			if env.Request == nil {
+				return fmt.Errorf("line %d: headers can only be set before the request", statement.line)
+			}
+			env.Headers[statement.target] = scriptString(value)
+		case "assert":
+			test := ScriptTest{Name: statement.source, Passed: scriptTruthy(value)}
+			if statement.message != nil {
+				message, err := statement.message.eval(env)
+				if err != nil {
+					return fmt.Errorf("line %d: %w", statement.line, err)
+				}
+				test.Name = scriptString(message)
+			}
+			if !test.Passed {
+				test.Message = "assertion failed: " + statement.source
+			}
+			env.Tests = append(env.Tests, test)
+		}
+	}
+	return nil
+}
+
+// RunScenarioScripts runs the scenario's script and then the request's own;
+// stage names them in errors, e.g. "pre-request"
+func RunScenarioScripts(env *ScriptEnv, stage, scenarioScript, requestScript string) error {
+	for i, source := range []string{scenarioScript, requestScript} {
+		if strings.TrimSpace(source) == "" {
+			continue
+		}
+		name := stage + " script"
+		if i == 0 {
+			name = "scenario " + name
+		}
+		parsed, err := parseScript(source)
+		if err == nil {
+			err = parsed.run(env)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ValidateScript reports the first syntax error of a script with its line,
+// so it can be shown when a scenario is saved rather than when it runs
+func ValidateScript(source string) error {
+	_, err := parseScript(source)
+	return err
+}
+
+// scriptRun is a request to <docs>/scripts/run, sent by the scenario runner
+// of the docs UI before and after each request it makes
+type scriptRun struct {
+	Stage          string            `json:"stage"` // "pre-request" or "post-response"
+	ScenarioScript string            `json:"scenario_script"`
+	RequestScript  string            `json:"request_script"`
+	Variables      map[string]string `json:"variables"`
+	Request        *ScriptRequest    `json:"request,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Response       *ScriptResponse   `json:"response,omitempty"`
+}
+
+// serveScripts runs the scripts of a scenario request. Scripts have no access
+// to the network, files or environment, so running them for any visitor of
+// the docs is as safe as running them in the browser.
+func (a *APIDocs) serveScripts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var run scriptRun
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxScriptRunBody)).Decode(&run); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid script run: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if run.Variables == nil {
+		run.Variables = map[string]string{}
+	}
+	if run.Headers == nil {
+		run.Headers = map[string]string{}
+	}
+	env := &ScriptEnv{Variables: run.Variables, Assigned: map[string]string{}, Headers: run.Headers}
+	switch {
+	case run.Stage == "pre-request" && run.Request != nil:
+		env.Request = run.Request
+	case run.Stage == "post-response" && run.Response != nil:
+		env.Response = run.Response
+	default:
+		http.Error(w, "Expected a pre-request stage with a request or a post-response stage with a response", http.StatusBadRequest)
+		return
+	}
+
+	result := struct {
+		Variables map[string]string `json:"variables"`
+		Assigned  map[string]string `json:"assigned"`
+		Headers   map[string]string `json:"headers,omitempty"`
+		Tests     []ScriptTest      `json:"tests"`
+		Error     string            `json:"error,omitempty"`
+	}{Variables: env.Variables, Assigned: env.Assigned, Tests: []ScriptTest{}}
+	if err := RunScenarioScripts(env, run.Stage, run.ScenarioScript, run.RequestScript); err != nil {
+		result.Error = err.Error()
+	}
+	result.Tests = append(result.Tests, env.Tests...)
+	if env.Request != nil {
+		result.Headers = env.Headers
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode script results: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(body)
+}
+
+// Expressions
+
+type scriptNode interface {
+	eval(env *ScriptEnv) (interface{}, error)
+}
+
+type scriptLiteral struct{ value interface{} }
+
+type scriptIdent struct{ name string }
+
+type scriptUnary struct {
+	op      string
+	operand scriptNode
+}
+
+type scriptBinary struct {
+	op          string
+	left, right scriptNode
+}
+
+type scriptCall struct {
+	name string
+	args []scriptNode
+}
+
+func (n scriptLiteral) eval(*ScriptEnv) (interface{}, error) { return n.value, nil }
+
+func (n scriptIdent) eval(env *ScriptEnv) (interface{}, error) {
+	switch n.name {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if env.Request != nil {
+		switch n.name {
+		case "method":
+			return env.Request.Method, nil
+		case "url":
+			return env.replace(env.Request.URL)
+		case "path":
+			target, err := env.replace(env.Request.URL)
+			if err != nil {
+				return nil, err
+			}
+			if parsed, err := url.Parse(target); err == nil {
+				if parsed.RawQuery != "" {
+					return parsed.EscapedPath() + "?" + parsed.RawQuery, nil
+				}
+				return parsed.EscapedPath(), nil
+			}
+			return target, nil
+		case "body":
+			return env.replace(env.Request.Body)
+		}
+	}
+	if env.Response != nil {
+		switch n.name {
+		case "status":
+			return float64(env.Response.StatusCode), nil
+		case "body":
+			return env.Response.Body, nil
+		case "duration":
+			return float64(env.Response.Duration), nil
+		}
+	}
+	if value, ok := env.Variables[n.name]; ok {
+		return value, nil
+	}
+	return nil, fmt.Errorf("unknown variable %s", n.name)
+}
+
+func (n scriptUnary) eval(env *ScriptEnv) (interface{}, error) {
+	value, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		return !scriptTruthy(value), nil
+	}
+	number, err := scriptNumber(value)
+	if err != nil {
+		return nil, err
+	}
+	return -number, nil
+}
+
+func (n scriptBinary) eval(env *ScriptEnv) (interface{}, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	// && and || only evaluate the right side when it decides the result
+	switch n.op {
+	case "&&":
+		if !scriptTruthy(left) {
+			return false, nil
+		}
+		right, err := n.right.eval(env)
+		return scriptTruthy(right), err
+	case "||":
+		if scriptTruthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(env)
+		return scriptTruthy(right), err
+	}
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==", "!=":
+		equal := scriptCompare(left, right) == 0
+		return equal == (n.op == "=="), nil
+	case "<":
+		return scriptCompare(left, right) < 0, nil
+	case "<=":
+		return scriptCompare(left, right) <= 0, nil
+	case ">":
+		return scriptCompare(left, right) > 0, nil
+	case ">=":
+		return scriptCompare(left, right) >= 0, nil
+	case "+":
+		_, leftIsNumber := left.(float64)
+		_, rightIsNumber := right.(float64)
+		if leftIsNumber && rightIsNumber {
+			return left.(float64) + right.(float64), nil
+		}
+		return scriptLimit(scriptString(left) + scriptString(right))
+	}
+
+	a, err := scriptNumber(left)
+	if err != nil {
+		return nil, err
+	}
+	b, err := scriptNumber(right)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/", "%":
+		// % works on the integer parts, so 0.5 is a zero divisor too
+		if b == 0 || (n.op == "%" && int64(b) == 0) {
+			return nil, fmt.Errorf("division by zero")
+		}
+		if n.op == "%" {
+			return float64(int64(a) % int64(b)), nil
+		}
+		return a / b, nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", n.op)
+}
+
+func (n scriptCall) eval(env *ScriptEnv) (interface{}, error) {
+	function, ok := scriptFunctions[n.name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s", n.name)
+	}
+	if function.args >= 0 && len(n.args) != function.args {
+		return nil, fmt.Errorf("%s expects %d arguments, got %d", n.name, function.args, len(n.args))
+	}
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+	value, err := function.call(env, args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.name, err)
+	}
+	if text, ok := value.(string); ok {
+		return scriptLimit(text)
+	}
+	return value, nil
+}
+
+// Functions
+
+type scriptFunction struct {
+	args int // -1 for any number
+	call func(env *ScriptEnv, args []interface{}) (interface{}, error)
+}
+
+func stringFunction(transform func(string) string) scriptFunction {
+	return scriptFunction{args: 1, call: func(_ *ScriptEnv, args []interface{}) (interface{}, error) {
+		return transform(scriptString(args[0])), nil
+	}}
+}
+
+func hmacFunction(newHash func() hash.Hash, encode func([]byte) string) scriptFunction {
+	return scriptFunction{args: 2, call: func(_ *ScriptEnv, args []interface{}) (interface{}, error) {
+		mac := hmac.New(newHash, []byte(scriptString(args[0])))
+		mac.Write([]byte(scriptString(args[1])))
+		return encode(mac.Sum(nil)), nil
+	}}
+}
+
+func hashFunction(sum func([]byte) []byte) scriptFunction {
+	return stringFunction(func(s string) string { return hex.EncodeToString(sum([]byte(s))) })
+}
+
+var scriptFunctions map[string]scriptFunction
+
+func init() {
+	scriptFunctions = map[string]scriptFunction{
+		// time
+		"now": {args: 0, call: func(*ScriptEnv, []interface{}) (interface{}, error) {
+			return time.Now().UTC().Format(time.RFC3339), nil
+		}},
+		"unix": {args: 0, call: func(*ScriptEnv, []interface{}) (interface{}, error) {
+			return float64(time.Now().Unix()), nil
+		}},
+		"unixMilli": {args: 0, call: func(*ScriptEnv, []interface{}) (interface{}, error) {
+			return float64(time.Now().UnixMilli()), nil
+		}},
+
+		// random values
+		"uuid": {args: 0, call: func(*ScriptEnv, []interface{}) (interface{}, error) {
+			buf := make([]byte, 16)
+			if _, err := rand.Read(buf); err != nil {
+				return nil, err
+			}
+			buf[6] = buf[6]&0x0f | 0x40
+			buf[8] = buf[8]&0x3f | 0x80
+			return fmt.Sprintf("%x-%x-%x-%x-%x", buf[0:4], buf[4:6], buf[6:8], buf[8:10], buf[10:]), nil
+		}},
+		"randomHex": {args: 1, call: func(_ *ScriptEnv, args []interface{}) (interface{}, error) {
+			n, err := scriptNumber(args[0])
+			if err != nil || n < 1 || n > 256 {
+				return nil, fmt.Errorf("expects a byte count from 1 to 256")
+			}
+			buf := make([]byte, int(n))
+			if _, err := rand.Read(buf); err != nil {
+				return nil, err
+			}
+			return hex.EncodeToString(buf), nil
+		}},
+
+		// hashes and signatures, hex encoded unless the name says otherwise
+		"md5":              hashFunction(func(b []byte) []byte { sum := md5.Sum(b); return sum[:] }),
+		"sha1":             hashFunction(func(b []byte) []byte { sum := sha1.Sum(b); return sum[:] }),
+		"sha256":           hashFunction(func(b []byte) []byte { sum := sha256.Sum256(b); return sum[:] }),
+		"sha512":           hashFunction(func(b []byte) []byte { sum := sha512.Sum512(b); return sum[:] }),
+		"hmacSHA1":         hmacFunction(sha1.New, hex.EncodeToString),
+		"hmacSHA256":       hmacFunction(sha256.New, hex.EncodeToString),
+		"hmacSHA512":       hmacFunction(sha512.New, hex.EncodeToString),
+		"hmacSHA256Base64": hmacFunction(sha256.New, base64.StdEncoding.EncodeToString),
+		"hmacSHA512Base64": hmacFunction(sha512.New, base64.StdEncoding.EncodeToString),
+
+		// encodings and strings
+		"base64":    stringFunction(func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }),
+		"base64url": stringFunction(func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }),
+		"base64Decode": {args: 1, call: func(_ *ScriptEnv, args []interface{}) (interface{}, error) {
+			decoded, err := base64.StdEncoding.DecodeString(scriptString(args[0]))
+			if err != nil {
+				return nil, err
+			}
+			return string(decoded), nil
+		}},
+		"urlEncode": stringFunction(url.QueryEscape),
+		"upper":     stringFunction(strings.ToUpper),
+		"lower":     stringFunction(strings.ToLower),
+		"trim":      stringFunction(strings.TrimSpace),
+		"string":    stringFunction(func(s string) string { return s }),
+		"number": {args: 1, call: func(_ *ScriptEnv, args []interface{}) (interface{}, error) {
+			return scriptNumber(args[0])
+		}},
+		"len": {args: 1, call: func(_ *ScriptEnv, args []interface{}) (interface{}, error) {
+			return float64(len([]rune(scriptString(args[0])))), nil
+		}},
+		"contains": {args: 2, call: func(_ *ScriptEnv, args []interface{}) (interface{}, error) {
+			return strings.Contains(scriptString(args[0]), scriptString(args[1])), nil
+		}},
+		"startsWith": {args: 2, call: func(_ *ScriptEnv, args []interface{}) (interface{}, error) {
+			return strings.HasPrefix(scriptString(args[0]), scriptString(args[1])), nil
+		}},
+		"replace": {args: 3, call: func(_ *ScriptEnv, args []interface{}) (interface{}, error) {
+			text, old, replacement := scriptString(args[0]), scriptString(args[1]), scriptString(args[2])
+			if old == "" {
+				return nil, fmt.Errorf("the text to replace must not be empty")
+			}
+			// checked before replacing, the result may be far larger than
+			// its inputs
+			if size := int64(len(text)) + int64(strings.Count(text, old))*int64(len(replacement)-len(old)); size > maxScriptValue {
+				return nil, fmt.Errorf("value is longer than %d bytes", maxScriptValue)
+			}
+			return strings.ReplaceAll(text, old, replacement), nil
+		}},
+
+		// request and response
+		"header": {args: 1, call: func(env *ScriptEnv, args []interface{}) (interface{}, error) {
+			name := scriptString(args[0])
+			if env.Response != nil {
+				for key, values := range env.Response.Headers {
+					if strings.EqualFold(key, name) && len(values) > 0 {
+						return values[0], nil
+					}
+				}
+				return nil, nil
+			}
+			for key, value := range env.Headers {
+				if strings.EqualFold(key, name) {
+					return value, nil
+				}
+			}
+			return nil, nil
+		}},
+		"json": {args: 1, call: func(env *ScriptEnv, args []interface{}) (interface{}, error) {
+			if env.Response == nil {
+				return nil, fmt.Errorf("only available after the response")
+			}
+			if !env.jsonParsed {
+				env.jsonParsed = true
+				json.Unmarshal([]byte(env.Response.Body), &env.responseJSON)
+			}
+			return scriptJSONPath(env.responseJSON, scriptString(args[0])), nil
+		}},
+	}
+}
+
+// scriptJSONPath looks up a dotted path like "data.items[0].id" or
+// "data.items.0.id"; objects and arrays are returned as JSON text
+func scriptJSONPath(value interface{}, path string) interface{} {
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	for _, key := range strings.Split(strings.Trim(path, "."), ".") {
+		if key == "" || key == "$" {
+			continue
+		}
+		switch current := value.(type) {
+		case map[string]interface{}:
+			value = current[key]
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(current) {
+				return nil
+			}
+			value = current[index]
+		default:
+			return nil
+		}
+	}
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		encoded, _ := json.Marshal(value)
+		return string(encoded)
+	}
+	return value
+}
+
+// Values are strings, float64 numbers, bools or nil
+
+func scriptString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(value)
+}
+
+func scriptNumber(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	number, err := strconv.ParseFloat(strings.TrimSpace(scriptString(value)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", scriptString(value))
+	}
+	return number, nil
+}
+
+func scriptTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != "" && v != "false"
+	}
+	return true
+}
+
+// scriptCompare compares numerically when a number meets a numeric value,
+// else as text
+func scriptCompare(left, right interface{}) int {
+	_, leftIsNumber := left.(float64)
+	_, rightIsNumber := right.(float64)
+	if leftIsNumber || rightIsNumber {
+		a, errA := scriptNumber(left)
+		b, errB := scriptNumber(right)
+		if errA == nil && errB == nil {
+			switch {
+			case a < b:
+				return -1
+			case a > b:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(scriptString(left), scriptString(right))
+}
+
+func scriptLimit(text string) (interface{}, error) {
+	if len(text) > maxScriptValue {
+		return nil, fmt.Errorf("value is longer than %d bytes", maxScriptValue)
+	}
+	return text, nil
+}
+
+// Parsing
+
+type scriptToken struct {
+	kind   string // "ident", "number", "string", "op" or "eof"
+	text   string
+	str    string // unquoted value of a string
+	offset int    // in the expression source
+}
+
+type scriptParser struct {
+	tokens []scriptToken
+	pos    int
+}
+
+var scriptOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "!", "(", ")", ","}
+
+func newScriptParser(source string) (*scriptParser, error) {
+	parser := &scriptParser{}
+	for i := 0; i < len(source); {
+		c := rune(source[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '`' || c == '\'':
+			end := i + 1
+			for end < len(source) && rune(source[end]) != c {
+				if source[end] == '\\' && c != '`' {
+					end++
+				}
+				end++
+			}
+			if end >= len(source) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			literal := source[i : end+1]
+			value := literal[1 : len(literal)-1]
+			if c == '"' {
+				unquoted, err := strconv.Unquote(literal)
+				if err != nil {
+					return nil, fmt.Errorf("invalid string %s", literal)
+				}
+				value = unquoted
+			} else if c == '\'' {
+				value = strings.NewReplacer(`\'`, `'`, `\\`, `\`).Replace(value)
+			}
+			parser.tokens = append(parser.tokens, scriptToken{kind: "string", text: literal, str: value, offset: i})
+			i = end + 1
+		case unicode.IsDigit(c):
+			end := i
+			for end < len(source) && (unicode.IsDigit(rune(source[end])) || source[end] == '.') {
+				end++
+			}
+			parser.tokens = append(parser.tokens, scriptToken{kind: "number", text: source[i:end], offset: i})
+			i = end
+		case unicode.IsLetter(c) || c == '_':
+			end := i
+			for end < len(source) && (unicode.IsLetter(rune(source[end])) || unicode.IsDigit(rune(source[end])) || source[end] == '_') {
+				end++
+			}
+			parser.tokens = append(parser.tokens, scriptToken{kind: "ident", text: source[i:end], offset: i})
+			i = end
+		default:
+			matched := false
+			for _, op := range scriptOperators {
+				if strings.HasPrefix(source[i:], op) {
+					parser.tokens = append(parser.tokens, scriptToken{kind: "op", text: op, offset: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q", c)
+			}
+		}
+	}
+	parser.tokens = append(parser.tokens, scriptToken{kind: "eof", offset: len(source)})
+	return parser, nil
+}
+
+func (p *scriptParser) peek() scriptToken { return p.tokens[p.pos] }
+
+func (p *scriptParser) done() bool { return p.peek().kind == "eof" }
+
+func (p *scriptParser) accept(op string) bool {
+	if token := p.peek(); token.kind == "op" && token.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// scriptPrecedence lists the binary operators from loosest to tightest
+var scriptPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *scriptParser) parseExpression() (scriptNode, error) {
+	return p.parseBinary(0)
+}
+
+func (p *scriptParser) parseBinary(level int) (scriptNode, error) {
+	if level == len(scriptPrecedence) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		matched := ""
+		for _, op := range scriptPrecedence[level] {
+			if p.accept(op) {
+				matched = op
+				break
+			}
+		}
+		if matched == "" {
+			return left, nil
+		}
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = scriptBinary{op: matched, left: left, right: right}
+	}
+}
+
+func (p *scriptParser) parseUnary() (scriptNode, error) {
+	for _, op := range []string{"!", "-"} {
+		if p.accept(op) {
+			operand, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			return scriptUnary{op: op, operand: operand}, nil
+		}
+	}
+	return p.parsePrimary()
+}
+
+func (p *scriptParser) parsePrimary() (scriptNode, error) {
+	token := p.peek()
+	switch token.kind {
+	case "string":
+		p.pos++
+		return scriptLiteral{value: token.str}, nil
+	case "number":
+		p.pos++
+		number, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", token.text)
+		}
+		return scriptLiteral{value: number}, nil
+	case "ident":
+		p.pos++
+		if !p.accept("(") {
+			return scriptIdent{name: token.text}, nil
+		}
+		if _, ok := scriptFunctions[token.text]; !ok {
+			return nil, fmt.Errorf("unknown function %s", token.text)
+		}
+		call := scriptCall{name: token.text}
+		if p.accept(")") {
+			return call, nil
+		}
+		for {
+			arg, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if p.accept(")") {
+				return call, nil
+			}
+			if !p.accept(",") {
+				return nil, fmt.Errorf("expected , or ) in the arguments of %s", token.text)
+			}
+		}
+	case "op":
+		if p.accept("(") {
+			inner, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			if !p.accept(")") {
+				return nil, fmt.Errorf("expected )")
+			}
+			return inner, nil
+		}
+	case "eof":
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q", token.text)
+}
@@ -0,0 +1,269 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// runTestScript parses and runs source against a response, failing the test
+// on syntax and runtime errors
+func runTestScript(t *testing.T, source string, response *ScriptResponse) *ScriptEnv {
+	t.Helper()
+	parsed, err := parseScript(source)
+	if err != nil {
+		t.Fatalf("parse %q: %v", source, err)
+	}
+	env := &ScriptEnv{Variables: map[string]string{"secret": "key"}, Response: response}
+	if err := parsed.run(env); err != nil {
+		t.Fatalf("run %q: %v", source, err)
+	}
+	return env
+}
+
+func TestParseScript_ReportsSyntaxErrors(t *testing.T) {
+	for source, want := range map[string]string{
+		"set = 1":                "line 1: expected set <name> = <expression>",
+		"# note\n\nassert (1":    "line 3: expected )",
+		"foo x":                  `unknown statement "foo"`,
+		"assert nope(1)":         "unknown function nope",
+		"set a = 'x":             "unterminated string",
+		"header X = 1 2":         `unexpected "2"`,
+		"assert 1 ==":            "unexpected end of expression",
+		"set a = upper(1, 2":     "expected , or ) in the arguments of upper",
+		"assert 1 @ 2":           "unexpected character '@'",
+		"set a = \"bad\\q\"":     "invalid string",
+		"assert len(\"a\") > 0,": "unexpected end of expression",
+	} {
+		_, err := parseScript(source)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("parse %q: expected %q, got %v", source, want, err)
+		}
+	}
+
+	parsed, err := parseScript("// comment\nset a = 1\n\n# comment\nassert a == 1, \"a is one\"")
+	if err != nil || len(parsed.statements) != 2 || parsed.statements[1].line != 5 {
+		t.Fatalf("expected two statements with their line numbers, got %+v %v", parsed, err)
+	}
+}
+
+func TestScript_EvaluatesExpressions(t *testing.T) {
+	response := &ScriptResponse{
+		StatusCode: 201,
+		Duration:   12,
+		Headers:    map[string][]string{"Content-Type": {"application/json"}},
+		Body:       `{"data":{"id":42,"name":"Ada","items":[{"sku":"a-1"}],"tags":["x"]}}`,
+	}
+	for _, expression := range []string{
+		`status == 201`,
+		`status >= 200 && status < 300`,
+		`!(status == 404) || missing`,
+		`duration == 12`,
+		`1 + 2 * 3 == 7`,
+		`(1 + 2) * 3 == 9`,
+		`-1 + 2 * 3 % 4 == 1`,
+		`10 / 4 == 2.5`,
+		`"a" + 1 + 2 == "a12"`,
+		`"10" == 10`,
+		`"b" > "a"`,
+		`true && !false`,
+		`json("data.id") == 42`,
+		`json("data.items[0].sku") == 'a-1'`,
+		`json("data.items.0.sku") == "a-1"`,
+		`json("data.tags") == "[\"x\"]"`,
+		`json("data.missing") == ""`,
+		`json("data.items[5]") == ""`,
+		`header("content-type") == "application/json"`,
+		`header("X-Missing") == ""`,
+		`upper(json("data.name")) == "ADA" && lower("ADA") == "ada"`,
+		`trim("  a ") == "a" && len("héllo") == 5`,
+		`contains(body, "Ada") && startsWith(body, "{")`,
+		`replace("a-b-c", "-", "+") == "a+b+c"`,
+		`number("2.5") + 1 == 3.5 && string(1.5) == "1.5"`,
+		`base64("hello") == "aGVsbG8=" && base64url("??>") == "Pz8-"`,
+		`base64Decode("aGVsbG8=") == "hello"`,
+		`urlEncode("a b&c") == "a+b%26c"`,
+		"`raw \\n` == \"raw \\\\n\"",
+		`len(uuid()) == 36 && uuid() != uuid()`,
+		`len(randomHex(8)) == 16`,
+		`unix() > 1700000000 && unixMilli() > unix() && contains(now(), "T")`,
+		`secret == "key"`,
+	} {
+		env := runTestScript(t, "assert "+expression, response)
+		if len(env.Tests) != 1 || !env.Tests[0].Passed {
+			t.Errorf("expected %s to hold, got %+v", expression, env.Tests)
+		}
+	}
+
+	env := runTestScript(t, "set id = json(\"data.id\")\nassert status == 200, \"is \" + status\nassert id == 42", response)
+	if env.Variables["id"] != "42" {
+		t.Fatalf("expected the extracted variable, got %v", env.Variables)
+	}
+	if len(env.Tests) != 2 || env.Tests[0].Passed || env.Tests[0].Name != "is 201" || env.Tests[0].Message != "assertion failed: status == 200" || !env.Tests[1].Passed {
+		t.Fatalf("expected a failed assertion that does not stop the script, got %+v", env.Tests)
+	}
+
+	for source, want := range map[string]string{
+		"assert nothing":          "line 1: unknown variable nothing",
+		"set a = 1 / 0":           "division by zero",
+		"set a = \"x\" * 2":       `"x" is not a number`,
+		"set a = randomHex(0)":    "expects a byte count from 1 to 256",
+		"set a = upper()":         "upper expects 1 arguments, got 0",
+		"set a = base64Decode(1)": "base64Decode:",
+		"header X-A = 1":          "headers can only be set before the request",
+	} {
+		parsed, err := parseScript(source)
+		if err != nil {
+			t.Fatalf("parse %q: %v", source, err)
+		}
+		err = parsed.run(&ScriptEnv{Response: response})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("run %q: expected %q, got %v", source, want, err)
+		}
+	}
+}
+
+func TestScript_HashesAndHMACs(t *testing.T) {
+	const fox = "The quick brown fox jumps over the lazy dog"
+	env := runTestScript(t, strings.Join([]string{
+		`set md5 = md5("abc")`,
+		`set sha1 = sha1("abc")`,
+		`set sha256 = sha256("abc")`,
+		`set sha512 = sha512("abc")`,
+		`set hmac1 = hmacSHA1(secret, "` + fox + `")`,
+		`set hmac256 = hmacSHA256(secret, "` + fox + `")`,
+		`set hmac512 = hmacSHA512(secret, "` + fox + `")`,
+		`set hmac256b64 = hmacSHA256Base64(secret, "` + fox + `")`,
+		`set hmac512b64 = hmacSHA512Base64(secret, "` + fox + `")`,
+	}, "\n"), &ScriptResponse{})
+
+	for name, want := range map[string]string{
+		"md5":        "900150983cd24fb0d6963f7d28e17f72",
+		"sha1":       "a9993e364706816aba3e25717850c26c9cd0d89d",
+		"sha256":     "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		"sha512":     "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+		"hmac1":      "de7c9b85b8b78aa6bc8a7a36f70a90701c9db4d9",
+		"hmac256":    "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		"hmac512":    "b42af09057bac1e2d41708e48a902e09b5ff7f12ab428a4fe86653c73dd248fb82f948a549f7b791a5b41915ee4d1ec3935357e4e2317250d0372afa2ebeeb3a",
+		"hmac256b64": "97yD9DBThCSxMpjmqm+xQ+9NWaFJRhdZl0edvC0aPNg=",
+		"hmac512b64": "tCrwkFe6weLUFwjkipAuCbX/fxKrQopP6GZTxz3SSPuC+UilSfe3kaW0GRXuTR7Dk1NX5OIxclDQNyr6Lr7rOg==",
+	} {
+		if got := env.Variables[name]; got != want {
+			t.Errorf("%s: expected %s, got %s", name, want, got)
+		}
+	}
+}
+
+func TestScript_Limits(t *testing.T) {
+	if _, err := parseScript(strings.Repeat("# padding\n", maxScriptLength/10+1)); err == nil {
+		t.Fatal("expected an oversized script rejected")
+	}
+
+	// doubling a value stops at maxScriptValue
+	doubling := "set a = \"" + strings.Repeat("x", 1024) + "\"\n" + strings.Repeat("set a = a + a\n", 11)
+	parsed, err := parseScript(doubling)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := &ScriptEnv{}
+	if err := parsed.run(env); err == nil || !strings.Contains(err.Error(), "line 12: value is longer than") {
+		t.Fatalf("expected the doubling stopped at the value limit, got %v", err)
+	}
+	if len(env.Variables["a"]) != maxScriptValue {
+		t.Fatalf("expected the last value under the limit kept, got %d bytes", len(env.Variables["a"]))
+	}
+
+	// replace must not build a huge string before the limit is checked
+	for source, want := range map[string]string{
+		`set b = replace(a, "", a)`:     "must not be empty",
+		`set b = replace(a, "x", "yy")`: "value is longer than",
+	} {
+		parsed, err := parseScript(source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := parsed.run(env); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("run %q: expected %q, got %v", source, want, err)
+		}
+	}
+	parsed, _ = parseScript(`set b = replace(a, "xx", "y")`)
+	if err := parsed.run(env); err != nil || len(env.Variables["b"]) != maxScriptValue/2 {
+		t.Fatalf("expected a shrinking replace allowed, got %d bytes, %v", len(env.Variables["b"]), err)
+	}
+}
+
+func TestScripts_RunForTheDocsScenarioRunner(t *testing.T) {
+	docs := New(&Config{Title: "Shop", Version: "1.0.0", DocsPath: "/docs"})
+	run := func(method, body string) (int, map[string]interface{}) {
+		rec := httptest.NewRecorder()
+		docs.ServeHTTP(rec, httptest.NewRequest(method, "/docs/scripts/run", strings.NewReader(body)))
+		var result map[string]interface{}
+		json.Unmarshal(rec.Body.Bytes(), &result)
+		return rec.Code, result
+	}
+
+	status, pre := run("POST", `{"stage":"pre-request","variables":{"secret":"key"},
+		"scenario_script":"set ts = \"1700000000\"\nheader X-Timestamp = ts",
+		"request_script":"header X-Signature = hmacSHA256(secret, method + \" \" + path + \" \" + body)",
+		"request":{"method":"POST","url":"http://api.test/users?at={{ts}}","body":"{\"at\":\"{{ts}}\"}"},
+		"headers":{"Accept":"application/json"}}`)
+	mac := hmac.New(sha256.New, []byte("key"))
+	mac.Write([]byte(`POST /users?at=1700000000 {"at":"1700000000"}`))
+	headers, _ := pre["headers"].(map[string]interface{})
+	if status != http.StatusOK || pre["error"] != nil || headers["Accept"] != "application/json" || headers["X-Timestamp"] != "1700000000" ||
+		headers["X-Signature"] != hex.EncodeToString(mac.Sum(nil)) {
+		t.Fatalf("expected the request signed with the variables replaced, got %d %v", status, pre)
+	}
+	if assigned, _ := pre["assigned"].(map[string]interface{}); len(assigned) != 1 || assigned["ts"] != "1700000000" {
+		t.Fatalf("expected only the variables the scripts set as assigned, got %v", pre["assigned"])
+	}
+
+	status, post := run("POST", `{"stage":"post-response","variables":{},
+		"request_script":"assert status == 201, \"created\"\nset userId = json(\"data.id\")\nassert header(\"content-type\") == \"text/plain\"",
+		"response":{"status_code":201,"headers":{"Content-Type":["application/json"]},"body":"{\"data\":{\"id\":42}}","duration_ms":5}}`)
+	tests, _ := post["tests"].([]interface{})
+	if status != http.StatusOK || post["error"] != nil || len(tests) != 2 || post["headers"] != nil {
+		t.Fatalf("expected two assertions and no headers after the response, got %d %v", status, post)
+	}
+	if first, second := tests[0].(map[string]interface{}), tests[1].(map[string]interface{}); first["name"] != "created" || first["passed"] != true || second["passed"] != false {
+		t.Fatalf("expected the first assertion passed and the second failed, got %v", tests)
+	}
+	if variables, _ := post["variables"].(map[string]interface{}); variables["userId"] != "42" {
+		t.Fatalf("expected the extracted variable, got %v", post["variables"])
+	}
+
+	for script, want := range map[string]string{
+		"set a = 1 / 0":   "pre-request script: line 1: division by zero",
+		"set a = 5 % 0.5": "pre-request script: line 1: division by zero",
+	} {
+		body, _ := json.Marshal(map[string]interface{}{"stage": "pre-request", "request": map[string]string{"method": "GET", "url": "/"}, "request_script": script})
+		if status, failed := run("POST", string(body)); status != http.StatusOK || failed["error"] != want {
+			t.Fatalf("%s: expected the script error reported, got %d %v", script, status, failed)
+		}
+	}
+
+	// {{name}} replacement stops before building a value over the limit
+	body, _ := json.Marshal(map[string]interface{}{
+		"stage":          "pre-request",
+		"variables":      map[string]string{"a": strings.Repeat("x", 200<<10)},
+		"request":        map[string]string{"method": "POST", "url": "/", "body": strings.Repeat("{{a}}", 2000)},
+		"request_script": "set size = len(body)",
+	})
+	if status, limited := run("POST", string(body)); status != http.StatusOK || !strings.Contains(fmt.Sprint(limited["error"]), "value is longer than") {
+		t.Fatalf("expected the replacement rejected at the value limit, got %d %v", status, limited["error"])
+	}
+	for method, body := range map[string]string{
+		"GET":  "",
+		"POST": `{"stage":"post-response"}`,
+	} {
+		if status, _ := run(method, body); status == http.StatusOK {
+			t.Errorf("%s %s: expected the run rejected", method, body)
+		}
+	}
+}
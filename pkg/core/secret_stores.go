@@ -0,0 +1,241 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// VaultSecretResolver reads secrets from HashiCorp Vault's KV engine. A
+// reference is "<mount path>#<key>", e.g. "vault://secret/data/bytedocs#ai_api_key".
+type VaultSecretResolver struct {
+	Address   string       // e.g. "https://vault.internal:8200"
+	Token     string       // Vault token with read access to the path
+	Namespace string       // Vault Enterprise namespace, optional
+	Client    *http.Client // defaults to http.DefaultClient
+}
+
+// ResolveSecret reads the secret at the path and returns its key. KV v2
+// ("secret/data/...") and KV v1 responses are both supported.
+func (v *VaultSecretResolver) ResolveSecret(ctx context.Context, ref string) (string, error) {
+	path, key := splitSecretRef(ref)
+	if v.Address == "" {
+		return "", fmt.Errorf("vault address is not configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(v.Address, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", v.Token)
+	if v.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+
+	body, err := doSecretRequest(v.Client, req)
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("vault: invalid response: %w", err)
+	}
+	data := response.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, hasMetadata := data["metadata"]; hasMetadata {
+			data = nested
+		}
+	}
+	return secretField(data, key)
+}
+
+// resolveVaultSecretFromEnv resolves vault:// references with VAULT_ADDR,
+// VAULT_TOKEN and VAULT_NAMESPACE
+func resolveVaultSecretFromEnv(ctx context.Context, ref string) (string, error) {
+	resolver := &VaultSecretResolver{
+		Address:   os.Getenv("VAULT_ADDR"),
+		Token:     os.Getenv("VAULT_TOKEN"),
+		Namespace: os.Getenv("VAULT_NAMESPACE"),
+	}
+	return resolver.ResolveSecret(ctx, ref)
+}
+
+// AWSSecretsManagerResolver reads secrets from AWS Secrets Manager. A
+// reference is "<secret id>" or "<secret id>#<json key>", e.g.
+// "awssm://prod/bytedocs#ai_api_key".
+type AWSSecretsManagerResolver struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string       // for temporary credentials, optional
+	Endpoint        string       // defaults to https://secretsmanager.<region>.amazonaws.com
+	Client          *http.Client // defaults to http.DefaultClient
+}
+
+// ResolveSecret calls GetSecretValue and returns the secret string, or one
+// key of it when the secret holds a JSON object
+func (a *AWSSecretsManagerResolver) ResolveSecret(ctx context.Context, ref string) (string, error) {
+	secretID, key := splitSecretRef(ref)
+	if a.Region == "" || a.AccessKeyID == "" || a.SecretAccessKey == "" {
+		return "", fmt.Errorf("aws region and credentials are not configured")
+	}
+
+	endpoint := a.Endpoint
+	if endpoint == "" {
+		endpoint = "https://secretsmanager." + a.Region + ".amazonaws.com"
+	}
+	payload, _ := json.Marshal(map[string]string{"SecretId": secretID})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	a.sign(req, payload, time.Now().UTC())
+
+	body, err := doSecretRequest(a.Client, req)
+	if err != nil {
+		return "", fmt.Errorf("aws secrets manager: %w", err)
+	}
+
+	var response struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("aws secrets manager: invalid response: %w", err)
+	}
+	if key == "" {
+		return response.SecretString, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(response.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("aws secrets manager: secret %s is not a JSON object", secretID)
+	}
+	return secretField(fields, key)
+}
+
+// sign adds an AWS Signature Version 4 Authorization header for the
+// secretsmanager service
+func (a *AWSSecretsManagerResolver) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if a.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", a.SessionToken)
+	}
+
+	signedHeaders := []string{"content-type", "host", "x-amz-date"}
+	if a.SessionToken != "" {
+		signedHeaders = append(signedHeaders, "x-amz-security-token")
+	}
+	signedHeaders = append(signedHeaders, "x-amz-target")
+
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaders {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	payloadHash := sha256.Sum256(payload)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + a.Region + "/secretsmanager/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+a.SecretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, a.Region)
+	signingKey = hmacSHA256(signingKey, "secretsmanager")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+a.AccessKeyID+"/"+scope+
+		", SignedHeaders="+strings.Join(signedHeaders, ";")+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// resolveAWSSecretFromEnv resolves awssm:// references with the standard
+// AWS_REGION, AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN variables
+func resolveAWSSecretFromEnv(ctx context.Context, ref string) (string, error) {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	resolver := &AWSSecretsManagerResolver{
+		Region:          region,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	return resolver.ResolveSecret(ctx, ref)
+}
+
+// doSecretRequest performs a secret store request and returns the body of a 2xx response
+func doSecretRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return body, nil
+}
+
+// secretField returns a string field of a secret object. Without a key, a
+// secret with a single field returns that field.
+func secretField(fields map[string]interface{}, key string) (string, error) {
+	if key == "" {
+		if len(fields) != 1 {
+			return "", fmt.Errorf("secret has %d fields, add #<key> to the reference", len(fields))
+		}
+		for name := range fields {
+			key = name
+		}
+	}
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("secret has no field %q", key)
+	}
+	if text, ok := value.(string); ok {
+		return text, nil
+	}
+	return fmt.Sprint(value), nil
+}
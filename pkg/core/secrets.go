@@ -0,0 +1,182 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SecretResolver resolves a secret reference, the part after "<scheme>://" of
+// a config value such as "file:///run/secrets/ai_key"
+type SecretResolver interface {
+	ResolveSecret(ctx context.Context, ref string) (string, error)
+}
+
+// SecretResolverFunc adapts a function to SecretResolver
+type SecretResolverFunc func(ctx context.Context, ref string) (string, error)
+
+// ResolveSecret calls f(ctx, ref)
+func (f SecretResolverFunc) ResolveSecret(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+// secretResolveTimeout bounds the lookups of LoadConfigFromEnv
+const secretResolveTimeout = 10 * time.Second
+
+var (
+	secretResolvers = map[string]SecretResolver{
+		"file":  SecretResolverFunc(resolveFileSecret),
+		"env":   SecretResolverFunc(resolveEnvSecret),
+		"vault": SecretResolverFunc(resolveVaultSecretFromEnv),
+		"awssm": SecretResolverFunc(resolveAWSSecretFromEnv),
+	}
+	secretResolversMutex sync.RWMutex
+)
+
+// RegisterSecretResolver makes config values starting with "<scheme>://"
+// resolve through resolver. It replaces the built-in file, env, vault and
+// awssm resolvers when registered under the same scheme.
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	secretResolversMutex.Lock()
+	defer secretResolversMutex.Unlock()
+	if resolver == nil {
+		delete(secretResolvers, scheme)
+		return
+	}
+	secretResolvers[scheme] = resolver
+}
+
+// ResolveSecrets replaces secret references in the auth password, API key and
+// TOTP secrets, the AI provider key, the AI settings, the refresh token and
+// webhook secret, the notification webhook URLs and the headers of federated
+// services with the secrets they point to.
+// LoadConfigFromEnv calls it; configs built in code call it before New.
+func ResolveSecrets(ctx context.Context, config *Config) error {
+	if config == nil {
+		return nil
+	}
+
+	if auth := config.AuthConfig; auth != nil {
+		if err := resolveSecretField(ctx, "auth password", &auth.Password); err != nil {
+			return err
+		}
+		if err := resolveSecretField(ctx, "auth API key", &auth.APIKey); err != nil {
+			return err
+		}
+		for username, secret := range auth.TOTPUsers {
+			if err := resolveSecretField(ctx, "TOTP secret of "+username, &secret); err != nil {
+				return err
+			}
+			auth.TOTPUsers[username] = secret
+		}
+	}
+
+	if aiConfig := config.AIConfig; aiConfig != nil {
+		if err := resolveSecretField(ctx, "AI API key", &aiConfig.APIKey); err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(aiConfig.Settings))
+		for key := range aiConfig.Settings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value, ok := aiConfig.Settings[key].(string)
+			if !ok {
+				continue
+			}
+			if err := resolveSecretField(ctx, "AI setting "+key, &value); err != nil {
+				return err
+			}
+			aiConfig.Settings[key] = value
+		}
+		for i := range aiConfig.Fallbacks {
+			fallback := &aiConfig.Fallbacks[i]
+			if err := resolveSecretField(ctx, "AI API key of fallback "+fallback.Provider, &fallback.APIKey); err != nil {
+				return err
+			}
+		}
+	}
+
+	if refresh := config.Refresh; refresh != nil {
+		if err := resolveSecretField(ctx, "refresh token", &refresh.Token); err != nil {
+			return err
+		}
+		if err := resolveSecretField(ctx, "refresh webhook secret", &refresh.WebhookSecret); err != nil {
+			return err
+		}
+	}
+
+	if notifications := config.Notifications; notifications != nil {
+		if err := resolveSecretField(ctx, "Slack webhook URL", &notifications.SlackWebhookURL); err != nil {
+			return err
+		}
+		if err := resolveSecretField(ctx, "Teams webhook URL", &notifications.TeamsWebhookURL); err != nil {
+			return err
+		}
+	}
+
+	if federation := config.Federation; federation != nil {
+		for _, service := range federation.Services {
+			for name, value := range service.Headers {
+				if err := resolveSecretField(ctx, service.Name+" header "+name, &value); err != nil {
+					return err
+				}
+				service.Headers[name] = value
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveSecretField resolves *value in place when it is a reference of a registered scheme
+func resolveSecretField(ctx context.Context, name string, value *string) error {
+	scheme, ref, found := strings.Cut(*value, "://")
+	if !found {
+		return nil
+	}
+
+	secretResolversMutex.RLock()
+	resolver, ok := secretResolvers[scheme]
+	secretResolversMutex.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	secret, err := resolver.ResolveSecret(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s from %s://: %w", name, scheme, err)
+	}
+	*value = secret
+	return nil
+}
+
+// resolveFileSecret reads a mounted secret such as /run/secrets/ai_key,
+// without its trailing newline
+func resolveFileSecret(_ context.Context, path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// resolveEnvSecret reads another environment variable
+func resolveEnvSecret(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+// splitSecretRef splits "path#key" into the secret path and the optional JSON key
+func splitSecretRef(ref string) (path string, key string) {
+	path, key, _ = strings.Cut(ref, "#")
+	return path, key
+}
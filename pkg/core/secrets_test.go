@@ -0,0 +1,68 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestResolveSecrets_FileVaultAWSAndCustom(t *testing.T) {
+	secretFile := t.TempDir() + "/docs_password"
+	if err := os.WriteFile(secretFile, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/bytedocs" || r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"data":{"api_key":"from-vault"},"metadata":{"version":3}}}`)
+	}))
+	defer vault.Close()
+	t.Setenv("VAULT_ADDR", vault.URL)
+	t.Setenv("VAULT_TOKEN", "vault-token")
+
+	aws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" || !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") ||
+			!strings.Contains(auth, "/eu-west-1/secretsmanager/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-target, Signature=") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `{"Name":"prod/bytedocs","SecretString":"{\"ai_api_key\":\"from-aws\"}"}`)
+	}))
+	defer aws.Close()
+	RegisterSecretResolver("awssm", &AWSSecretsManagerResolver{
+		Region: "eu-west-1", AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret", Endpoint: aws.URL,
+	})
+	RegisterSecretResolver("custom", SecretResolverFunc(func(_ context.Context, ref string) (string, error) {
+		return "custom-" + ref, nil
+	}))
+	defer RegisterSecretResolver("awssm", SecretResolverFunc(resolveAWSSecretFromEnv))
+	defer RegisterSecretResolver("custom", nil)
+
+	config := &Config{
+		AuthConfig: &AuthConfig{Password: "file://" + secretFile, APIKey: "vault://secret/data/bytedocs#api_key"},
+		AIConfig: &AIConfig{APIKey: "awssm://prod/bytedocs#ai_api_key",
+			Settings: map[string]interface{}{"base_url": "custom://llm", "app_name": "plain://value"}},
+	}
+	if err := ResolveSecrets(context.Background(), config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.AuthConfig.Password != "from-file" || config.AuthConfig.APIKey != "from-vault" || config.AIConfig.APIKey != "from-aws" {
+		t.Fatalf("unexpected resolved secrets: %+v %+v", config.AuthConfig, config.AIConfig)
+	}
+	if config.AIConfig.Settings["base_url"] != "custom-llm" || config.AIConfig.Settings["app_name"] != "plain://value" {
+		t.Fatalf("unexpected resolved settings: %v", config.AIConfig.Settings)
+	}
+
+	config = &Config{AuthConfig: &AuthConfig{Password: "file://" + secretFile + ".missing"}}
+	if err := ResolveSecrets(context.Background(), config); err == nil || !strings.Contains(err.Error(), "auth password") {
+		t.Fatalf("expected an error naming the field, got %v", err)
+	}
+}
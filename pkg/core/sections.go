@@ -0,0 +1,57 @@
+package core
+
+import "sort"
+
+// sectionMeta is the curated display of a section, set with SetSection
+type sectionMeta struct {
+	name        string
+	description string
+	order       int
+	federated   bool // set from a federated service, not by SetSection
+}
+
+// SetSection sets the display name, description and sidebar position of the
+// section with the given ID. Empty strings keep the generated name and
+// "<Name> related endpoints" description. Sections with a positive order come
+// first, in ascending order; the others follow sorted by ID.
+func (a *APIDocs) SetSection(id, name, description string, order int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sections[id] = sectionMeta{name: name, description: description, order: order}
+	a.dirty.Store(true)
+}
+
+// applySectionMeta applies the SetSection metadata to a generated section
+func (a *APIDocs) applySectionMeta(section *EndpointSection) {
+	meta, ok := a.sections[section.ID]
+	if !ok {
+		return
+	}
+	if meta.name != "" {
+		section.Name = meta.name
+	}
+	if meta.description != "" {
+		section.Description = meta.description
+	}
+}
+
+// sortSections orders sections by their SetSection order, then by ID
+func (a *APIDocs) sortSections(sections []EndpointSection) {
+	sort.SliceStable(sections, func(i, j int) bool {
+		left, right := a.sections[sections[i].ID].order, a.sections[sections[j].ID].order
+		if (left > 0) != (right > 0) {
+			return left > 0
+		}
+		if left != right {
+			return left < right
+		}
+		return sections[i].ID < sections[j].ID
+	})
+}
+
+// hasSectionMeta reports whether any section was curated with SetSection
+func (a *APIDocs) hasSectionMeta() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.sections) > 0
+}
@@ -0,0 +1,43 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetSection_CuratesNamesDescriptionsAndOrder(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs"})
+	docs.AddRoute("GET", "/accounts", nil)
+	docs.AddRoute("GET", "/users", nil)
+	docs.AddRoute("GET", "/users/{id}/orders", nil, WithSection("users"))
+	docs.AddRoute("GET", "/health", nil)
+	docs.SetSection("users", "People", "Accounts and their orders", 1)
+	docs.SetSection("health", "", "", 2)
+
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	sections := docs.GetDocumentation().Endpoints
+	var order []string
+	for _, section := range sections {
+		order = append(order, section.ID)
+	}
+	if strings.Join(order, ",") != "users,health,accounts" {
+		t.Fatalf("expected curated sections first, got %v", order)
+	}
+	if sections[0].Name != "People" || sections[0].Description != "Accounts and their orders" || len(sections[0].Endpoints) != 2 {
+		t.Fatalf("expected the curated users section with the @Section endpoint, got %+v", sections[0])
+	}
+	if sections[1].Name != "Health" || sections[1].Description != "Health related endpoints" {
+		t.Fatalf("expected empty values to keep the generated text, got %+v", sections[1])
+	}
+
+	spec, err := docs.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags, _ := spec["tags"].([]map[string]interface{})
+	if len(tags) != 3 || tags[0]["name"] != "People" || tags[0]["description"] != "Accounts and their orders" {
+		t.Fatalf("expected curated sections as ordered spec tags, got %v", spec["tags"])
+	}
+}
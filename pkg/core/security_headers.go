@@ -0,0 +1,117 @@
+package core
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SecurityHeadersConfig adjusts the security headers sent with every docs response
+type SecurityHeadersConfig struct {
+	Disabled              bool     // Send no security headers, e.g. when a proxy already sets them
+	ContentSecurityPolicy string   // Replaces the default policy entirely
+	ScriptSources         []string // Extra script-src sources for custom scripts, e.g. "https://cdn.example.com"
+	StyleSources          []string // Extra style-src sources for custom stylesheets
+	ConnectSources        []string // Extra connect-src sources the Try It console may call
+	FrameAncestors        []string // Origins allowed to embed the docs; empty denies framing
+}
+
+// The UI loads its libraries from these CDNs and relies on inline event
+// handlers and Tailwind's injected styles, so 'unsafe-inline' stays allowed
+// while every other source is pinned.
+var (
+	defaultScriptSources = []string{"'self'", "'unsafe-inline'", "https://cdn.jsdelivr.net", "https://cdn.tailwindcss.com"}
+	defaultStyleSources  = []string{"'self'", "'unsafe-inline'", "https://fonts.googleapis.com", "https://cdn.jsdelivr.net"}
+	defaultFontSources   = []string{"'self'", "data:", "https://fonts.gstatic.com", "https://cdn.jsdelivr.net"}
+)
+
+// setSecurityHeaders adds the Content-Security-Policy, framing, referrer and
+// content sniffing headers to a docs response
+func (a *APIDocs) setSecurityHeaders(w http.ResponseWriter) {
+	settings := a.config.SecurityHeaders
+	if settings == nil {
+		settings = &SecurityHeadersConfig{}
+	}
+	if settings.Disabled {
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Security-Policy", a.contentSecurityPolicy(settings))
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Referrer-Policy", "same-origin")
+	if len(settings.FrameAncestors) == 0 {
+		header.Set("X-Frame-Options", "DENY")
+	}
+}
+
+// contentSecurityPolicy builds the policy for the docs UI. Try It requests go
+// straight to the documented servers, so their origins are allowed to connect.
+func (a *APIDocs) contentSecurityPolicy(settings *SecurityHeadersConfig) string {
+	if settings.ContentSecurityPolicy != "" {
+		return settings.ContentSecurityPolicy
+	}
+
+	connectSources := []string{"'self'"}
+	serverURLs := []string{a.config.BaseURL}
+	for _, option := range a.config.BaseURLs {
+		serverURLs = append(serverURLs, option.expandAll()...)
+	}
+	for _, serverURL := range serverURLs {
+		if origin := urlOrigin(serverURL); origin != "" {
+			connectSources = appendSource(connectSources, origin)
+		}
+	}
+	connectSources = appendSource(connectSources, settings.ConnectSources...)
+
+	frameAncestors := []string{"'none'"}
+	if len(settings.FrameAncestors) > 0 {
+		frameAncestors = settings.FrameAncestors
+	}
+
+	directives := []string{
+		"default-src 'self'",
+		"script-src " + strings.Join(appendSource(defaultScriptSources, settings.ScriptSources...), " "),
+		"style-src " + strings.Join(appendSource(defaultStyleSources, settings.StyleSources...), " "),
+		"font-src " + strings.Join(defaultFontSources, " "),
+		"img-src 'self' data: https:",
+		"connect-src " + strings.Join(connectSources, " "),
+		"worker-src 'self' blob:",
+		"object-src 'none'",
+		"base-uri 'self'",
+		"form-action 'self'",
+		"frame-ancestors " + strings.Join(frameAncestors, " "),
+	}
+	return strings.Join(directives, "; ")
+}
+
+// appendSource returns a copy of sources with the new, non-duplicate sources appended
+func appendSource(sources []string, extra ...string) []string {
+	result := append([]string(nil), sources...)
+	for _, source := range extra {
+		source = strings.TrimSpace(source)
+		if source == "" || strings.ContainsAny(source, ";,") {
+			continue
+		}
+		duplicate := false
+		for _, existing := range result {
+			if existing == source {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			result = append(result, source)
+		}
+	}
+	return result
+}
+
+// urlOrigin returns scheme://host of an absolute URL, or "" when it has none
+func urlOrigin(rawURL string) string {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return ""
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSecurityHeaders_DefaultsAndOverrides(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", BaseURL: "https://api.example.com/v1"})
+
+	for _, path := range []string{"/docs", "/docs/openapi.json", "/docs/api-data.json"} {
+		rec := httptest.NewRecorder()
+		docs.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		header := rec.Header()
+		if header.Get("X-Frame-Options") != "DENY" || header.Get("X-Content-Type-Options") != "nosniff" || header.Get("Referrer-Policy") == "" {
+			t.Fatalf("%s: expected hardened headers, got %v", path, header)
+		}
+		csp := header.Get("Content-Security-Policy")
+		if !strings.Contains(csp, "connect-src 'self' https://api.example.com;") || !strings.Contains(csp, "frame-ancestors 'none'") || !strings.Contains(csp, "object-src 'none'") {
+			t.Fatalf("%s: unexpected policy %q", path, csp)
+		}
+	}
+
+	docs = New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", SecurityHeaders: &SecurityHeadersConfig{
+		ScriptSources:  []string{"https://scripts.example.com"},
+		FrameAncestors: []string{"https://portal.example.com"},
+	}})
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs", nil))
+	csp := rec.Header().Get("Content-Security-Policy")
+	if !strings.Contains(csp, "https://cdn.tailwindcss.com https://scripts.example.com;") || !strings.Contains(csp, "frame-ancestors https://portal.example.com") {
+		t.Fatalf("expected custom sources in policy, got %q", csp)
+	}
+	if rec.Header().Get("X-Frame-Options") != "" {
+		t.Fatalf("expected no X-Frame-Options when framing is allowed")
+	}
+
+	docs = New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", SecurityHeaders: &SecurityHeadersConfig{Disabled: true}})
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs", nil))
+	if rec.Header().Get("Content-Security-Policy") != "" {
+		t.Fatalf("expected no policy when security headers are disabled")
+	}
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ServerVariable is a "{name}" placeholder of a templated base URL, e.g. the
+// region of https://{region}.api.example.com
+type ServerVariable struct {
+	Default     string   `json:"default"`
+	Enum        []string `json:"enum,omitempty"` // allowed values; empty allows any value
+	Description string   `json:"description,omitempty"`
+}
+
+var serverVariablePattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// DefaultURL returns the URL with every variable set to its default
+func (o BaseURLOption) DefaultURL() string {
+	return o.expand(nil)
+}
+
+// expand replaces the variables of the URL, using values before the defaults
+func (o BaseURLOption) expand(values map[string]string) string {
+	return serverVariablePattern.ReplaceAllStringFunc(o.URL, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		if variable, ok := o.Variables[name]; ok {
+			return variable.Default
+		}
+		return placeholder
+	})
+}
+
+// expandAll returns the URL for every combination of enum values, or the
+// default of variables without an enum
+func (o BaseURLOption) expandAll() []string {
+	names := make([]string, 0, len(o.Variables))
+	for name := range o.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	combinations := []map[string]string{{}}
+	for _, name := range names {
+		values := o.Variables[name].Enum
+		if len(values) == 0 {
+			values = []string{o.Variables[name].Default}
+		}
+		next := make([]map[string]string, 0, len(combinations)*len(values))
+		for _, combination := range combinations {
+			for _, value := range values {
+				expanded := make(map[string]string, len(combination)+1)
+				for key, chosen := range combination {
+					expanded[key] = chosen
+				}
+				expanded[name] = value
+				next = append(next, expanded)
+			}
+		}
+		combinations = next
+	}
+
+	urls := make([]string, 0, len(combinations))
+	for _, combination := range combinations {
+		urls = append(urls, o.expand(combination))
+	}
+	return urls
+}
+
+// validateServerVariables checks that every placeholder of the URL has a
+// variable and every default is one of its enum values
+func validateServerVariables(option BaseURLOption) error {
+	for _, match := range serverVariablePattern.FindAllStringSubmatch(option.URL, -1) {
+		if _, ok := option.Variables[match[1]]; !ok {
+			return fmt.Errorf("base URL %q has no variable %q", option.URL, match[1])
+		}
+	}
+	for name, variable := range option.Variables {
+		if variable.Default == "" {
+			return fmt.Errorf("server variable %q of %q needs a default", name, option.URL)
+		}
+		if len(variable.Enum) > 0 && !slices.Contains(variable.Enum, variable.Default) {
+			return fmt.Errorf("default %q of server variable %q is not one of %s", variable.Default, name, strings.Join(variable.Enum, ", "))
+		}
+	}
+	return nil
+}
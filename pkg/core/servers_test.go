@@ -0,0 +1,90 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPIServers_DerivedFromRequest(t *testing.T) {
+	config := &Config{Title: "API", Version: "1.0.0", DocsPath: "/docs", ServersFromRequest: true}
+	docs := New(config)
+	docs.AddRoute("GET", "/ping", nil)
+
+	req := httptest.NewRequest("GET", "http://internal:8080/docs/openapi.json", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Host", "tenant.example.com")
+	req.Header.Set("X-Forwarded-Prefix", "/api/")
+
+	// Forwarded headers are ignored unless the peer is a trusted proxy
+	spec, err := docs.GetOpenAPIJSONForRequest(req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	servers := spec["servers"].([]map[string]interface{})
+	if len(servers) != 1 || servers[0]["url"] != "http://internal:8080" {
+		t.Fatalf("expected untrusted forwarded headers ignored, got %v", servers)
+	}
+
+	config.TrustedProxies = []string{"192.0.2.0/24"}
+	spec, _ = docs.GetOpenAPIJSONForRequest(req)
+	servers = spec["servers"].([]map[string]interface{})
+	if len(servers) != 1 || servers[0]["url"] != "https://tenant.example.com/api" {
+		t.Fatalf("unexpected servers: %v", servers)
+	}
+
+	docs.GetConfig().BaseURL = "https://configured.example.com"
+	spec, _ = docs.GetOpenAPIJSONForRequest(req)
+	servers = spec["servers"].([]map[string]interface{})
+	if servers[0]["url"] != "https://configured.example.com" {
+		t.Fatalf("configured base URL should win, got %v", servers)
+	}
+}
+
+func TestServerVariables_InSpecsAndSecurityPolicy(t *testing.T) {
+	regional := BaseURLOption{
+		Name: "Production",
+		URL:  "https://{region}.api.example.com/{version}",
+		Variables: map[string]ServerVariable{
+			"region":  {Default: "eu", Enum: []string{"eu", "us"}, Description: "Deployment region"},
+			"version": {Default: "v1"},
+		},
+	}
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", BaseURLs: []BaseURLOption{regional}})
+	docs.AddRoute("GET", "/users", nil)
+
+	spec, err := docs.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(spec["servers"])
+	if !strings.Contains(string(raw), `"url":"https://{region}.api.example.com/{version}"`) ||
+		!strings.Contains(string(raw), `"region":{"default":"eu","enum":["eu","us"],"description":"Deployment region"}`) {
+		t.Fatalf("expected the templated server with its variables, got %s", raw)
+	}
+
+	swagger, err := docs.GetSwaggerJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swagger["host"] != "eu.api.example.com" || swagger["basePath"] != "/v1" {
+		t.Fatalf("expected Swagger to use the variable defaults, got %v %v", swagger["host"], swagger["basePath"])
+	}
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/", nil))
+	if csp := rec.Header().Get("Content-Security-Policy"); !strings.Contains(csp, "https://eu.api.example.com") || !strings.Contains(csp, "https://us.api.example.com") {
+		t.Fatalf("expected every region to be allowed to connect, got %q", csp)
+	}
+
+	for _, option := range []BaseURLOption{
+		{Name: "Missing", URL: "https://{region}.api.example.com"},
+		{Name: "Bad default", URL: "https://{region}.api.example.com", Variables: map[string]ServerVariable{"region": {Default: "ap", Enum: []string{"eu", "us"}}}},
+	} {
+		config := &Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", BaseURLs: []BaseURLOption{option}}
+		if err := ValidateConfig(config); err == nil {
+			t.Fatalf("expected %s to be rejected", option.Name)
+		}
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/base64"
 	"fmt"
 	"html/template"
-	"net"
 	"net/http"
 	"strings"
 	"sync"
@@ -18,9 +17,22 @@ type SessionAuthMiddleware struct {
 	config    *AuthConfig
 	templates map[string]*template.Template
 	sessions  map[string]int64 // session ID -> auth time
-	ipBans    map[string]int64 // IP -> ban expiry time
-	attempts  map[string]int   // IP -> attempt count
+	guard     *loginGuard      // failed attempts and IP bans
 	mutex     sync.RWMutex
+
+	trustedProxies []string // peers whose X-Forwarded-For names the client that is banned
+
+	totpSecrets  map[string]string           // username -> base32 secret, including enrolled ones
+	totpCounters map[string]int64            // username -> last accepted TOTP counter
+	pending      map[string]pendingTOTPLogin // second factor token -> password-verified login
+}
+
+// pendingTOTPLogin is a login that passed the password and awaits its TOTP code
+type pendingTOTPLogin struct {
+	username  string
+	secret    string
+	enrolling bool // secret was generated for this login and is not stored yet
+	expires   int64
 }
 
 // SessionData represents template data for auth views
@@ -33,20 +45,47 @@ type SessionData struct {
 	BanDuration     int
 	ClientIP        string
 	BlockedAt       string
+	CSRFToken       string
+	ShowUsername    bool   // TOTP is enabled, the login form asks for the username
+	Username        string // TOTP page: user being verified
+	TOTPSecret      string // TOTP page: secret to enroll, empty for enrolled users
+	TOTPURI         string // TOTP page: otpauth:// URI rendered as QR code
 }
 
+const (
+	sessionCookieName   = "bytedocs_session"
+	authErrorCookieName = "bytedocs_auth_error"
+	csrfCookieName      = "bytedocs_csrf"
+	csrfFormField       = "csrf_token"
+	totpCookieName      = "bytedocs_2fa"
+	totpPendingTimeout  = 5 * time.Minute
+)
+
 // NewSessionAuthMiddleware creates a new session auth middleware
 func NewSessionAuthMiddleware(config *AuthConfig) (*SessionAuthMiddleware, error) {
 	if config == nil || config.Type != "session" {
 		return nil, fmt.Errorf("invalid config for session auth")
 	}
+	return newSessionAuthMiddleware(config, newLoginGuard(config), nil)
+}
 
+// newSessionAuthMiddleware creates a session auth middleware banning through
+// guard the client IPs behind trustedProxies
+func newSessionAuthMiddleware(config *AuthConfig, guard *loginGuard, trustedProxies []string) (*SessionAuthMiddleware, error) {
 	middleware := &SessionAuthMiddleware{
 		config:    config,
 		templates: make(map[string]*template.Template),
 		sessions:  make(map[string]int64),
-		ipBans:    make(map[string]int64),
-		attempts:  make(map[string]int),
+		guard:     guard,
+
+		trustedProxies: trustedProxies,
+
+		totpSecrets:  make(map[string]string, len(config.TOTPUsers)),
+		totpCounters: make(map[string]int64),
+		pending:      make(map[string]pendingTOTPLogin),
+	}
+	for username, secret := range config.TOTPUsers {
+		middleware.totpSecrets[username] = secret
 	}
 
 	// Load templates
@@ -64,6 +103,7 @@ func NewSessionAuthMiddleware(config *AuthConfig) (*SessionAuthMiddleware, error
 func (m *SessionAuthMiddleware) loadTemplates() error {
 	templatePaths := map[string]string{
 		"login":        "pkg/ui/templates/auth/login.html",
+		"totp":         "pkg/ui/templates/auth/totp.html",
 		"banned":       "pkg/ui/templates/auth/banned.html",
 		"config-error": "pkg/ui/templates/auth/config-error.html",
 	}
@@ -93,9 +133,14 @@ func (m *SessionAuthMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	ip := getClientIP(r)
+	ip := clientIPBehind(r, m.trustedProxies)
 	sessionID := m.getSessionID(r)
 
+	if m.config.LogoutEnabled && strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/logout") {
+		m.handleLogout(w, r, sessionID)
+		return
+	}
+
 	// Check if IP is banned
 	if m.isIPBanned(ip) {
 		m.renderBanned(w, r, ip)
@@ -104,7 +149,12 @@ func (m *SessionAuthMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request
 
 	// Check if already authenticated
 	if m.isAuthenticated(sessionID) {
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, withAuthIdentity(r, sessionIdentity(sessionID)))
+		return
+	}
+
+	if m.config.TOTPEnabled && r.Method == "POST" && r.FormValue("totp_code") != "" {
+		m.handleTOTP(w, r, next, ip)
 		return
 	}
 
@@ -117,34 +167,9 @@ func (m *SessionAuthMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request
 	m.renderLogin(w, r, "")
 }
 
-// getClientIP extracts client IP from request
-func getClientIP(r *http.Request) string {
-	// Check X-Forwarded-For header
-	forwarded := r.Header.Get("X-Forwarded-For")
-	if forwarded != "" {
-		parts := strings.Split(forwarded, ",")
-		if len(parts) > 0 {
-			return strings.TrimSpace(parts[0])
-		}
-	}
-
-	// Check X-Real-IP header
-	realIP := r.Header.Get("X-Real-IP")
-	if realIP != "" {
-		return realIP
-	}
-
-	// Fallback to RemoteAddr
-	ip, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		return r.RemoteAddr
-	}
-	return ip
-}
-
 // getSessionID extracts session ID from cookie
 func (m *SessionAuthMiddleware) getSessionID(r *http.Request) string {
-	cookie, err := r.Cookie("bytedocs_session")
+	cookie, err := r.Cookie(m.cookieName(sessionCookieName))
 	if err != nil {
 		return ""
 	}
@@ -153,35 +178,7 @@ func (m *SessionAuthMiddleware) getSessionID(r *http.Request) string {
 
 // isIPBanned checks if IP is currently banned
 func (m *SessionAuthMiddleware) isIPBanned(ip string) bool {
-	if !m.config.IPBanEnabled {
-		return false
-	}
-
-	// Check if IP is whitelisted
-	for _, whitelistIP := range m.config.AdminWhitelistIPs {
-		if ip == whitelistIP {
-			return false
-		}
-	}
-
-	m.mutex.RLock()
-	banExpiry, exists := m.ipBans[ip]
-	m.mutex.RUnlock()
-
-	if !exists {
-		return false
-	}
-
-	// Check if ban has expired
-	if time.Now().Unix() > banExpiry {
-		m.mutex.Lock()
-		delete(m.ipBans, ip)
-		delete(m.attempts, ip)
-		m.mutex.Unlock()
-		return false
-	}
-
-	return true
+	return m.guard.isBanned(ip)
 }
 
 // isAuthenticated checks if session is valid
@@ -214,84 +211,39 @@ func (m *SessionAuthMiddleware) isAuthenticated(sessionID string) bool {
 func (m *SessionAuthMiddleware) handleLogin(w http.ResponseWriter, r *http.Request, next http.Handler, ip, sessionID string) {
 	password := r.FormValue("password")
 
-	// Check password
-	if subtle.ConstantTimeCompare([]byte(password), []byte(m.config.Password)) == 1 {
-		// Success - clear attempts and set session
-		m.mutex.Lock()
-		delete(m.attempts, ip)
-
-		// Generate session ID if not exists
-		if sessionID == "" {
-			sessionID = generateSessionID()
-		}
-
-		m.sessions[sessionID] = time.Now().Unix()
-		m.mutex.Unlock()
-
-		// Set session cookie
-		http.SetCookie(w, &http.Cookie{
-			Name:     "bytedocs_session",
-			Value:    sessionID,
-			Path:     "/",
-			HttpOnly: true,
-			Secure:   r.TLS != nil,
-			MaxAge:   m.config.SessionExpire * 60,
-		})
-
-		// Clear any error cookie
-		http.SetCookie(w, &http.Cookie{
-			Name:   "bytedocs_auth_error",
-			Value:  "",
-			Path:   "/",
-			MaxAge: -1,
-		})
-
-		next.ServeHTTP(w, r)
+	// Reject forged cross-site login submissions
+	if !m.validCSRFToken(r) {
+		m.renderLogin(w, r, "Sesi login kedaluwarsa. Silakan coba lagi.")
 		return
 	}
 
-	// Failed login - increment attempts
-	m.mutex.Lock()
-	attempts := m.attempts[ip] + 1
-	m.attempts[ip] = attempts
-	m.mutex.Unlock()
-
-	// Ban IP if max attempts reached (unless whitelisted)
-	if attempts >= m.config.IPBanMaxAttempts && m.config.IPBanEnabled {
-		isWhitelisted := false
-		for _, whitelistIP := range m.config.AdminWhitelistIPs {
-			if ip == whitelistIP {
-				isWhitelisted = true
-				break
-			}
-		}
-
-		if !isWhitelisted {
-			banExpiry := time.Now().Add(time.Duration(m.config.IPBanDuration) * time.Minute).Unix()
-			m.mutex.Lock()
-			m.ipBans[ip] = banExpiry
-			delete(m.attempts, ip)
-			m.mutex.Unlock()
-
-			m.renderBanned(w, r, ip)
+	// Check password; with TOTP the username must belong to a registered user as well
+	username := r.FormValue("username")
+	_, knownUser := m.totpSecret(username)
+	if subtle.ConstantTimeCompare([]byte(password), []byte(m.config.Password)) == 1 && (!m.config.TOTPEnabled || knownUser) {
+		m.guard.recordSuccess(ip)
+		if m.config.TOTPEnabled {
+			m.startTOTP(w, r, username)
 			return
-		} else {
-			// If IP is whitelisted, just reset attempts instead of banning
-			m.mutex.Lock()
-			delete(m.attempts, ip)
-			m.mutex.Unlock()
 		}
+		m.startSession(w, r, next, sessionID)
+		return
+	}
+
+	// Failed login - count the attempt and ban the IP at the limit (unless whitelisted)
+	banned, remainingAttempts := m.guard.recordFailure(ip)
+	if banned {
+		m.renderBanned(w, r, ip)
+		return
 	}
 
 	// Show error
-	remainingAttempts := m.config.IPBanMaxAttempts - attempts
 	errorMessage := fmt.Sprintf("Password salah. Sisa percobaan: %d", remainingAttempts)
 
 	// Set error cookie
-	http.SetCookie(w, &http.Cookie{
-		Name:     "bytedocs_auth_error",
+	m.setCookie(w, r, &http.Cookie{
+		Name:     authErrorCookieName,
 		Value:    errorMessage,
-		Path:     "/",
 		HttpOnly: true,
 		MaxAge:   300, // 5 minutes
 	})
@@ -303,13 +255,15 @@ func (m *SessionAuthMiddleware) handleLogin(w http.ResponseWriter, r *http.Reque
 func (m *SessionAuthMiddleware) renderLogin(w http.ResponseWriter, r *http.Request, error string) {
 	// Check for error in cookie if not provided
 	if error == "" {
-		if cookie, err := r.Cookie("bytedocs_auth_error"); err == nil {
+		if cookie, err := r.Cookie(m.cookieName(authErrorCookieName)); err == nil {
 			error = cookie.Value
 		}
 	}
 
 	data := SessionData{
-		Error: error,
+		Error:        error,
+		CSRFToken:    m.csrfToken(w, r),
+		ShowUsername: m.config.TOTPEnabled,
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -317,11 +271,257 @@ func (m *SessionAuthMiddleware) renderLogin(w http.ResponseWriter, r *http.Reque
 	m.templates["login"].Execute(w, data)
 }
 
+// startSession signs the browser in with a fresh session and serves the docs
+func (m *SessionAuthMiddleware) startSession(w http.ResponseWriter, r *http.Request, next http.Handler, sessionID string) {
+	m.mutex.Lock()
+
+	// Always issue a fresh session ID so a planted cookie cannot be fixated
+	delete(m.sessions, sessionID)
+	sessionID = generateSessionID()
+
+	m.sessions[sessionID] = time.Now().Unix()
+	m.mutex.Unlock()
+
+	// Set session cookie
+	m.setCookie(w, r, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    sessionID,
+		HttpOnly: true,
+		MaxAge:   m.config.SessionExpire * 60,
+	})
+
+	// Clear any error cookie
+	m.setCookie(w, r, &http.Cookie{
+		Name:   authErrorCookieName,
+		Value:  "",
+		MaxAge: -1,
+	})
+
+	next.ServeHTTP(w, withAuthIdentity(r, sessionIdentity(sessionID)))
+}
+
+// totpSecret returns the secret of a TOTP user; an enrolling user has an empty secret
+func (m *SessionAuthMiddleware) totpSecret(username string) (string, bool) {
+	if username == "" {
+		return "", false
+	}
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	secret, ok := m.totpSecrets[username]
+	return secret, ok
+}
+
+// startTOTP asks a password-verified user for the TOTP code, generating a new
+// secret for users that still have to enroll
+func (m *SessionAuthMiddleware) startTOTP(w http.ResponseWriter, r *http.Request, username string) {
+	secret, _ := m.totpSecret(username)
+	login := pendingTOTPLogin{
+		username: username,
+		secret:   secret,
+		expires:  time.Now().Add(totpPendingTimeout).Unix(),
+	}
+	if secret == "" {
+		generated, err := generateTOTPSecret()
+		if err != nil {
+			http.Error(w, "Failed to generate TOTP secret", http.StatusInternalServerError)
+			return
+		}
+		login.secret = generated
+		login.enrolling = true
+	}
+
+	token := generateSessionID()
+	m.mutex.Lock()
+	m.pending[token] = login
+	m.mutex.Unlock()
+
+	m.setCookie(w, r, &http.Cookie{
+		Name:     totpCookieName,
+		Value:    token,
+		HttpOnly: true,
+		MaxAge:   int(totpPendingTimeout / time.Second),
+	})
+	m.renderTOTP(w, r, login, "")
+}
+
+// handleTOTP verifies the second factor of a pending login and, for enrolling
+// users, stores the new secret
+func (m *SessionAuthMiddleware) handleTOTP(w http.ResponseWriter, r *http.Request, next http.Handler, ip string) {
+	var token string
+	if cookie, err := r.Cookie(m.cookieName(totpCookieName)); err == nil {
+		token = cookie.Value
+	}
+	m.mutex.RLock()
+	login, ok := m.pending[token]
+	m.mutex.RUnlock()
+
+	if !m.validCSRFToken(r) || !ok || time.Now().Unix() > login.expires {
+		m.renderLogin(w, r, "Sesi login kedaluwarsa. Silakan coba lagi.")
+		return
+	}
+
+	counter, valid := verifyTOTP(login.secret, r.FormValue("totp_code"), time.Now())
+	m.mutex.Lock()
+	if last, used := m.totpCounters[login.username]; valid && used && counter <= last {
+		valid = false // a code is accepted only once
+	}
+	if valid {
+		m.totpCounters[login.username] = counter
+	}
+	m.mutex.Unlock()
+
+	if !valid {
+		banned, remainingAttempts := m.guard.recordFailure(ip)
+		if banned {
+			m.mutex.Lock()
+			delete(m.pending, token)
+			m.mutex.Unlock()
+			m.renderBanned(w, r, ip)
+			return
+		}
+		m.renderTOTP(w, r, login, fmt.Sprintf("Kode verifikasi salah. Sisa percobaan: %d", remainingAttempts))
+		return
+	}
+
+	if login.enrolling && m.config.OnTOTPEnroll != nil {
+		if err := m.config.OnTOTPEnroll(login.username, login.secret); err != nil {
+			m.renderTOTP(w, r, login, "Gagal menyimpan kunci autentikator. Silakan coba lagi.")
+			return
+		}
+	}
+
+	m.mutex.Lock()
+	if login.enrolling {
+		m.totpSecrets[login.username] = login.secret
+	}
+	delete(m.pending, token)
+	m.mutex.Unlock()
+
+	m.guard.recordSuccess(ip)
+	m.setCookie(w, r, &http.Cookie{
+		Name:     totpCookieName,
+		Value:    "",
+		HttpOnly: true,
+		MaxAge:   -1,
+	})
+	m.startSession(w, r, next, m.getSessionID(r))
+}
+
+// renderTOTP renders the second factor page, with the QR code while enrolling
+func (m *SessionAuthMiddleware) renderTOTP(w http.ResponseWriter, r *http.Request, login pendingTOTPLogin, error string) {
+	data := SessionData{
+		Error:     error,
+		CSRFToken: m.csrfToken(w, r),
+		Username:  login.username,
+	}
+	if login.enrolling {
+		issuer := m.config.TOTPIssuer
+		if issuer == "" {
+			issuer = m.config.Realm
+		}
+		if issuer == "" {
+			issuer = "ByteDocs"
+		}
+		data.TOTPSecret = login.secret
+		data.TOTPURI = totpURI(issuer, login.username, login.secret)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	m.templates["totp"].Execute(w, data)
+}
+
+// handleLogout ends the session and sends the browser back to the login page
+func (m *SessionAuthMiddleware) handleLogout(w http.ResponseWriter, r *http.Request, sessionID string) {
+	if sessionID != "" {
+		m.mutex.Lock()
+		delete(m.sessions, sessionID)
+		m.mutex.Unlock()
+	}
+
+	m.setCookie(w, r, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    "",
+		HttpOnly: true,
+		MaxAge:   -1,
+	})
+
+	loginPath := strings.TrimSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/logout")
+	if loginPath == "" {
+		loginPath = "/"
+	}
+	// Behind a trusted proxy or a Registry the public path starts with their prefix
+	if prefix, _ := mountPrefix(r); prefix != "" && !strings.HasPrefix(loginPath, prefix+"/") {
+		loginPath = prefix + loginPath
+	}
+	http.Redirect(w, r, loginPath, http.StatusSeeOther)
+}
+
+// cookieName appends AuthConfig.CookieScope to an auth cookie name
+func (m *SessionAuthMiddleware) cookieName(name string) string {
+	if m.config.CookieScope == "" {
+		return name
+	}
+	return name + "_" + m.config.CookieScope
+}
+
+// setCookie writes an auth cookie with the configured SameSite mode, marking
+// it Secure on HTTPS requests, when configured, and always for SameSite=None
+func (m *SessionAuthMiddleware) setCookie(w http.ResponseWriter, r *http.Request, cookie *http.Cookie) {
+	cookie.Path = "/"
+	cookie.Name = m.cookieName(cookie.Name)
+	switch strings.ToLower(m.config.CookieSameSite) {
+	case "strict":
+		cookie.SameSite = http.SameSiteStrictMode
+	case "none":
+		cookie.SameSite = http.SameSiteNoneMode
+		cookie.Secure = true
+	default:
+		cookie.SameSite = http.SameSiteLaxMode
+	}
+	if m.config.CookieSecure || isSecureRequest(r) {
+		cookie.Secure = true
+	}
+	http.SetCookie(w, cookie)
+}
+
+// isSecureRequest reports whether the client reached the docs over HTTPS,
+// directly or through a TLS-terminating proxy
+func isSecureRequest(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+// csrfToken returns the login form token of the browser, issuing one in a
+// cookie on its first visit
+func (m *SessionAuthMiddleware) csrfToken(w http.ResponseWriter, r *http.Request) string {
+	if cookie, err := r.Cookie(m.cookieName(csrfCookieName)); err == nil && len(cookie.Value) >= 32 {
+		return cookie.Value
+	}
+
+	token := generateSessionID()
+	m.setCookie(w, r, &http.Cookie{
+		Name:     csrfCookieName,
+		Value:    token,
+		HttpOnly: true,
+	})
+	return token
+}
+
+// validCSRFToken checks the submitted form token against the browser's cookie
+func (m *SessionAuthMiddleware) validCSRFToken(r *http.Request) bool {
+	cookie, err := r.Cookie(m.cookieName(csrfCookieName))
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(r.FormValue(csrfFormField))) == 1
+}
+
 // renderBanned renders the banned page
 func (m *SessionAuthMiddleware) renderBanned(w http.ResponseWriter, r *http.Request, ip string) {
 	data := SessionData{
-		MaxAttempts: m.config.IPBanMaxAttempts,
-		BanDuration: m.config.IPBanDuration,
+		MaxAttempts: m.guard.maxAttempts,
+		BanDuration: int(m.guard.banDuration / time.Minute),
 		ClientIP:    ip,
 		BlockedAt:   time.Now().Format("2006-01-02 15:04:05"),
 	}
@@ -348,7 +548,7 @@ func (m *SessionAuthMiddleware) renderConfigError(w http.ResponseWriter, r *http
 	m.templates["config-error"].Execute(w, data)
 }
 
-// cleanupRoutine periodically cleans up expired sessions and bans
+// cleanupRoutine periodically cleans up expired sessions; the guard prunes bans itself
 func (m *SessionAuthMiddleware) cleanupRoutine() {
 	ticker := time.NewTicker(10 * time.Minute)
 	defer ticker.Stop()
@@ -365,11 +565,10 @@ func (m *SessionAuthMiddleware) cleanupRoutine() {
 			}
 		}
 
-		// Clean up expired bans
-		for ip, banExpiry := range m.ipBans {
-			if now > banExpiry {
-				delete(m.ipBans, ip)
-				delete(m.attempts, ip)
+		// Clean up abandoned second factor logins
+		for token, login := range m.pending {
+			if now > login.expires {
+				delete(m.pending, token)
 			}
 		}
 
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSessionAuth_CSRFCookiesAndLogout(t *testing.T) {
+	t.Chdir("../..") // auth templates are loaded relative to the module root
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", AuthConfig: &AuthConfig{
+		Enabled: true, Type: "session", Password: "secret", SessionExpire: 60,
+		CookieSameSite: "strict", LogoutEnabled: true,
+	}})
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs", nil))
+	var csrf *http.Cookie
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == csrfCookieName {
+			csrf = cookie
+		}
+	}
+	if csrf == nil || csrf.SameSite != http.SameSiteStrictMode || !strings.Contains(rec.Body.String(), `name="csrf_token" value="`+csrf.Value+`"`) {
+		t.Fatalf("expected a CSRF cookie matching the login form, got %v", rec.Result().Cookies())
+	}
+
+	login := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/docs", strings.NewReader("password=secret&csrf_token="+token))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Forwarded-Proto", "https")
+		req.AddCookie(csrf)
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: "planted"})
+		rec := httptest.NewRecorder()
+		docs.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := login("forged"); strings.Contains(rec.Header().Get("Set-Cookie"), sessionCookieName+"=") {
+		t.Fatalf("login without the CSRF token must not create a session")
+	}
+
+	rec = login(csrf.Value)
+	var session *http.Cookie
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == sessionCookieName {
+			session = cookie
+		}
+	}
+	if session == nil || session.Value == "planted" || !session.Secure || !session.HttpOnly || session.SameSite != http.SameSiteStrictMode {
+		t.Fatalf("expected a fresh secure session cookie, got %+v", session)
+	}
+
+	req := httptest.NewRequest("GET", "/docs/logout", nil)
+	req.AddCookie(session)
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, req)
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/docs" {
+		t.Fatalf("expected logout redirect to /docs, got %d %v", rec.Code, rec.Header())
+	}
+
+	req = httptest.NewRequest("GET", "/docs/api-data.json", nil)
+	req.AddCookie(session)
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, req)
+	if strings.Contains(rec.Header().Get("Content-Type"), "json") {
+		t.Fatalf("expected the session to end on logout")
+	}
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// SharedParameter is a parameter many endpoints accept, e.g. an X-Request-ID
+// header or a tenant query parameter. It is defined once under
+// components/parameters and referenced with $ref from every matching endpoint.
+type SharedParameter struct {
+	Key       string // component name; derived from the parameter name when empty, e.g. "XRequestID"
+	Parameter Parameter
+	Paths     []string // path prefixes, "*" matches one segment, e.g. "/api/*/orders"; empty matches every endpoint
+}
+
+// AddSharedParameter attaches a parameter to every endpoint matching its
+// paths, after the ones in Config.SharedParameters
+func (a *APIDocs) AddSharedParameter(shared SharedParameter) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.shared = append(a.shared, shared)
+	a.dirty.Store(true)
+}
+
+// sharedParameters returns the shared parameters of the config and
+// AddSharedParameter by component key, in registration order
+func (a *APIDocs) sharedParameters() ([]SharedParameter, map[string]Parameter, error) {
+	all := append(append([]SharedParameter{}, a.config.SharedParameters...), a.shared...)
+	byKey := make(map[string]Parameter, len(all))
+	for i, shared := range all {
+		if shared.Parameter.Name == "" || shared.Parameter.In == "" {
+			return nil, nil, fmt.Errorf("shared parameter %q needs a name and a location", shared.Key)
+		}
+		if shared.Key == "" {
+			shared.Key = sharedParameterKey(shared.Parameter.Name)
+		}
+		if _, exists := byKey[shared.Key]; exists {
+			return nil, nil, fmt.Errorf("duplicate shared parameter %q", shared.Key)
+		}
+		shared.Parameter.Component = shared.Key
+		byKey[shared.Key] = shared.Parameter
+		all[i] = shared
+	}
+	return all, byKey, nil
+}
+
+// applySharedParameters appends the matching shared parameters the endpoint
+// does not document itself
+func applySharedParameters(endpoint *Endpoint, shared []SharedParameter) {
+	documented := make(map[string]bool, len(endpoint.Parameters))
+	for _, param := range endpoint.Parameters {
+		documented[param.Name+":"+param.In] = true
+	}
+
+	var parameters []Parameter
+	for _, definition := range shared {
+		key := definition.Parameter.Name + ":" + definition.Parameter.In
+		if documented[key] || !sharedParameterMatches(definition.Paths, endpoint.Path) {
+			continue
+		}
+		if parameters == nil {
+			// The parameters may be shared with the registered route; copy before adding
+			parameters = append([]Parameter{}, endpoint.Parameters...)
+		}
+		parameters = append(parameters, definition.Parameter)
+		documented[key] = true
+	}
+	if parameters != nil {
+		endpoint.Parameters = parameters
+	}
+}
+
+// sharedParameterMatches reports whether path lies under one of the patterns
+func sharedParameterMatches(patterns []string, path string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, pattern := range patterns {
+		pattern = strings.Trim(pattern, "/")
+		if pattern == "" {
+			return true
+		}
+		parts := strings.Split(pattern, "/")
+		if len(parts) > len(segments) {
+			continue
+		}
+		matched := true
+		for i, part := range parts {
+			// "*" matches one segment here, not the rest of the path as in a route
+			if part != "*" && strings.Trim(convertPathToOpenAPI(part), "/") != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedParameterKey turns a parameter name into a component key, e.g.
+// "X-Request-ID" into "XRequestID" and "tenant_id" into "TenantId"
+func sharedParameterKey(name string) string {
+	var key strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		key.WriteRune(r)
+	}
+	return key.String()
+}
+
+// sharedParameterRef returns the component key when param is still the
+// shared definition, so overridden copies are emitted inline
+func sharedParameterRef(param Parameter, components map[string]Parameter) (string, bool) {
+	if param.Component == "" {
+		return "", false
+	}
+	definition, ok := components[param.Component]
+	return param.Component, ok && reflect.DeepEqual(definition, param)
+}
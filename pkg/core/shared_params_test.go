@@ -0,0 +1,78 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSharedParameters_ReferencedFromMatchingEndpoints(t *testing.T) {
+	requestID := Parameter{Name: "X-Request-ID", In: "header", Type: "string", Description: "Correlation ID", Example: "req-123"}
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs",
+		SharedParameters: []SharedParameter{{Parameter: requestID}}})
+	docs.AddSharedParameter(SharedParameter{Key: "Tenant", Paths: []string{"/api/*/orders"},
+		Parameter: Parameter{Name: "tenant", In: "query", Type: "string", Required: true, Description: "Tenant slug"}})
+	docs.AddRoute("GET", "/api/v1/orders", nil)
+	docs.AddRoute("GET", "/api/v1/orders/:id", nil, WithParam("tenant", "query", "string", false, "Optional here"))
+	docs.AddRoute("GET", "/api/v1/users", nil)
+	docs.OverrideEndpoint("GET", "/api/v1/users", func(e *Endpoint) {
+		for i := range e.Parameters {
+			e.Parameters[i].Description = "Trace ID"
+		}
+	})
+
+	spec, err := docs.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(spec)
+	var openAPI struct {
+		Paths      map[string]map[string]struct{ Parameters []map[string]interface{} }
+		Components struct {
+			Parameters map[string]map[string]interface{}
+		}
+	}
+	if err := json.Unmarshal(raw, &openAPI); err != nil {
+		t.Fatal(err)
+	}
+
+	if openAPI.Components.Parameters["XRequestID"]["in"] != "header" || openAPI.Components.Parameters["Tenant"]["required"] != true {
+		t.Fatalf("expected both shared parameters as components, got %+v", openAPI.Components.Parameters)
+	}
+	list := openAPI.Paths["/api/v1/orders"]["get"].Parameters
+	if len(list) != 2 || list[0]["$ref"] != "#/components/parameters/XRequestID" || list[1]["$ref"] != "#/components/parameters/Tenant" {
+		t.Fatalf("expected both parameters referenced on the matching path, got %+v", list)
+	}
+	item := openAPI.Paths["/api/v1/orders/{id}"]["get"].Parameters
+	if len(item) != 3 || item[1]["description"] != "Optional here" || item[2]["$ref"] != "#/components/parameters/XRequestID" {
+		t.Fatalf("expected the documented tenant to win over the shared one, got %+v", item)
+	}
+	users := openAPI.Paths["/api/v1/users"]["get"].Parameters
+	if len(users) != 1 || users[0]["$ref"] != nil || users[0]["description"] != "Trace ID" {
+		t.Fatalf("expected an overridden shared parameter inline and no tenant, got %+v", users)
+	}
+
+	swagger, err := docs.GetSwaggerJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ = json.Marshal(swagger)
+	if !strings.Contains(string(raw), `{"$ref":"#/parameters/Tenant"}`) || !strings.Contains(string(raw), `"parameters":{"Tenant":`) {
+		t.Fatalf("expected Swagger parameter definitions and refs, got %s", raw)
+	}
+
+	report, err := docs.Lint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, finding := range report.Findings {
+		if finding.Rule == LintRuleUntypedParameter {
+			t.Fatalf("expected shared parameters to be resolved when linting, got %+v", finding)
+		}
+	}
+
+	docs.AddSharedParameter(SharedParameter{Parameter: requestID})
+	if err := docs.Generate(); err == nil || !strings.Contains(err.Error(), `duplicate shared parameter "XRequestID"`) {
+		t.Fatalf("expected duplicate keys to fail, got %v", err)
+	}
+}
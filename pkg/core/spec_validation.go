@@ -0,0 +1,429 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Values for Config.SpecValidation
+const (
+	SpecValidationStrict   = "strict"   // exports fail while the spec breaks the OpenAPI 3.0 schema
+	SpecValidationAnnotate = "annotate" // exports list the violations under x-spec-violations
+)
+
+// SpecViolation is a place where a spec breaks the OpenAPI 3.0 schema
+type SpecViolation struct {
+	Location string `json:"location"` // JSON pointer into the spec, e.g. "/paths/~1users/get/responses"
+	Message  string `json:"message"`
+}
+
+// SpecValidationError is returned by the exports in strict mode
+type SpecValidationError struct {
+	Violations []SpecViolation
+}
+
+func (e *SpecValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Location+": "+violation.Message)
+	}
+	return fmt.Sprintf("spec breaks the OpenAPI 3.0 schema in %d places: %s", len(e.Violations), strings.Join(messages, "; "))
+}
+
+var (
+	openAPIVersion     = regexp.MustCompile(`^3\.0\.\d+$`)
+	responseStatusCode = regexp.MustCompile(`^[1-5](?:[0-9]{2}|XX)$`)
+	pathTemplateParam  = regexp.MustCompile(`\{([^}]+)\}`)
+)
+
+// openAPISchemaKeys are the keywords the OpenAPI 3.0 schema object allows,
+// besides x- extensions
+var openAPISchemaKeys = map[string]bool{
+	"title": true, "multipleOf": true, "maximum": true, "exclusiveMaximum": true, "minimum": true,
+	"exclusiveMinimum": true, "maxLength": true, "minLength": true, "pattern": true, "maxItems": true,
+	"minItems": true, "uniqueItems": true, "maxProperties": true, "minProperties": true, "required": true,
+	"enum": true, "type": true, "not": true, "allOf": true, "oneOf": true, "anyOf": true, "items": true,
+	"properties": true, "additionalProperties": true, "description": true, "format": true, "default": true,
+	"nullable": true, "discriminator": true, "readOnly": true, "writeOnly": true, "example": true,
+	"externalDocs": true, "deprecated": true, "xml": true, "$ref": true,
+}
+
+var openAPISchemaTypes = map[string]bool{
+	"string": true, "number": true, "integer": true, "boolean": true, "array": true, "object": true,
+}
+
+var parameterLocations = map[string]bool{"query": true, "header": true, "path": true, "cookie": true}
+
+// ValidateOpenAPISpec checks a spec against the rules of the OpenAPI 3.0
+// schema that code generators rely on: required fields, response codes,
+// parameter locations and path templates, unique operationIds, schema
+// keywords and types, resolvable $refs, and null examples of schemas that
+// are not nullable. Violations are ordered by location.
+func ValidateOpenAPISpec(spec map[string]interface{}) ([]SpecViolation, error) {
+	normalized, err := normalizeSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := make([]SpecViolation, 0)
+	add := func(location, format string, args ...interface{}) {
+		violations = append(violations, SpecViolation{Location: location, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if version, _ := normalized["openapi"].(string); !openAPIVersion.MatchString(version) {
+		add("/openapi", "openapi must be a 3.0.x version, got %q", version)
+	}
+	info, _ := normalized["info"].(map[string]interface{})
+	for _, key := range []string{"title", "version"} {
+		if value, ok := info[key].(string); !ok || value == "" {
+			add("/info/"+key, "info.%s is required", key)
+		}
+	}
+
+	validator := &specValidator{root: normalized, add: add, operationIDs: make(map[string]string)}
+	paths, ok := normalized["paths"].(map[string]interface{})
+	if !ok {
+		add("/paths", "paths is required")
+	}
+	for path, value := range paths {
+		pointer := "/paths/" + jsonPointerEscape(path)
+		if !strings.HasPrefix(path, "/") {
+			add(pointer, "path %q must start with /", path)
+		}
+		if pathItem, ok := value.(map[string]interface{}); ok {
+			validator.pathItem(path, pointer, pathItem)
+		}
+	}
+
+	components, _ := normalized["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	for name, schema := range schemas {
+		validator.schema("/components/schemas/"+jsonPointerEscape(name), schema)
+	}
+	parameters, _ := components["parameters"].(map[string]interface{})
+	for name, parameter := range parameters {
+		if parameterMap, ok := parameter.(map[string]interface{}); ok {
+			validator.parameter("/components/parameters/"+jsonPointerEscape(name), parameterMap)
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Location < violations[j].Location })
+	return violations, nil
+}
+
+type specValidator struct {
+	root         map[string]interface{}
+	add          func(location, format string, args ...interface{})
+	operationIDs map[string]string
+}
+
+func (v *specValidator) pathItem(path, pointer string, pathItem map[string]interface{}) {
+	shared, _ := pathItem["parameters"].([]interface{})
+	for _, method := range lintMethods {
+		operation, ok := pathItem[method].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		operationPointer := pointer + "/" + method
+
+		if id, _ := operation["operationId"].(string); id != "" {
+			if first, exists := v.operationIDs[id]; exists {
+				v.add(operationPointer+"/operationId", "operationId %q is already used by %s", id, first)
+			} else {
+				v.operationIDs[id] = strings.ToUpper(method) + " " + path
+			}
+		}
+
+		declared := make(map[string]bool)
+		parameters, _ := operation["parameters"].([]interface{})
+		for i, value := range append(append([]interface{}{}, shared...), parameters...) {
+			parameter, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			location := fmt.Sprintf("%s/parameters/%d", operationPointer, i-len(shared))
+			if i < len(shared) {
+				location = fmt.Sprintf("%s/parameters/%d", pointer, i)
+			}
+			if ref, _ := parameter["$ref"].(string); ref != "" {
+				if parameter, ok = v.resolve(location, ref); !ok {
+					continue
+				}
+			} else {
+				v.parameter(location, parameter)
+			}
+			name, _ := parameter["name"].(string)
+			in, _ := parameter["in"].(string)
+			if in == "path" {
+				declared[name] = true
+				if !strings.Contains(path, "{"+name+"}") {
+					v.add(location, "path parameter %q does not appear in the path", name)
+				}
+			}
+		}
+		for _, match := range pathTemplateParam.FindAllStringSubmatch(path, -1) {
+			if !declared[match[1]] {
+				v.add(operationPointer+"/parameters", "path parameter %q is not declared", match[1])
+			}
+		}
+
+		if body, ok := operation["requestBody"].(map[string]interface{}); ok {
+			if ref, _ := body["$ref"].(string); ref != "" {
+				v.resolve(operationPointer+"/requestBody", ref)
+			} else {
+				v.content(operationPointer+"/requestBody", body, true)
+			}
+		}
+
+		responses, ok := operation["responses"].(map[string]interface{})
+		if !ok || len(responses) == 0 {
+			v.add(operationPointer+"/responses", "operation needs at least one response")
+		}
+		for status, value := range responses {
+			location := operationPointer + "/responses/" + jsonPointerEscape(status)
+			if status != "default" && !strings.HasPrefix(status, "x-") && !responseStatusCode.MatchString(status) {
+				v.add(location, "response code %q must be a status code, a range such as 4XX or default", status)
+			}
+			response, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if ref, _ := response["$ref"].(string); ref != "" {
+				v.resolve(location, ref)
+				continue
+			}
+			if _, ok := response["description"].(string); !ok {
+				v.add(location, "response description is required")
+			}
+			v.content(location, response, false)
+		}
+	}
+}
+
+// parameter checks a parameter object that is not a $ref
+func (v *specValidator) parameter(location string, parameter map[string]interface{}) {
+	name, _ := parameter["name"].(string)
+	in, _ := parameter["in"].(string)
+	if name == "" {
+		v.add(location, "parameter name is required")
+	}
+	if !parameterLocations[in] {
+		v.add(location, "parameter %q must be in query, header, path or cookie, got %q", name, in)
+	}
+	if required, _ := parameter["required"].(bool); in == "path" && !required {
+		v.add(location, "path parameter %q must be required", name)
+	}
+	_, hasSchema := parameter["schema"]
+	_, hasContent := parameter["content"]
+	if hasSchema == hasContent {
+		v.add(location, "parameter %q needs either a schema or a content", name)
+	}
+	if hasSchema {
+		v.schema(location+"/schema", parameter["schema"])
+	}
+	v.example(location, parameter)
+}
+
+// content checks the media types of a request body or response
+func (v *specValidator) content(location string, holder map[string]interface{}, required bool) {
+	content, ok := holder["content"].(map[string]interface{})
+	if !ok {
+		if required {
+			v.add(location, "request body content is required")
+		}
+		return
+	}
+	for mediaType, value := range content {
+		media, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		mediaLocation := location + "/content/" + jsonPointerEscape(mediaType)
+		if schema, ok := media["schema"]; ok {
+			if schema == nil {
+				v.add(mediaLocation+"/schema", "schema must be an object, not null")
+			} else {
+				v.schema(mediaLocation+"/schema", schema)
+			}
+		}
+		v.example(mediaLocation, media)
+	}
+}
+
+// example flags examples that break their schema: examples next to example,
+// and null examples of schemas that are not nullable
+func (v *specValidator) example(location string, holder map[string]interface{}) {
+	example, hasExample := holder["example"]
+	if _, hasExamples := holder["examples"]; hasExample && hasExamples {
+		v.add(location, "example and examples are mutually exclusive")
+	}
+	if !hasExample || example != nil {
+		return
+	}
+	schema, _ := holder["schema"].(map[string]interface{})
+	if nullable, _ := schema["nullable"].(bool); !nullable {
+		v.add(location+"/example", "example is null but the schema is not nullable")
+	}
+}
+
+// schema checks a schema object and its subschemas
+func (v *specValidator) schema(location string, value interface{}) {
+	schema, ok := value.(map[string]interface{})
+	if !ok {
+		v.add(location, "schema must be an object")
+		return
+	}
+	if ref, _ := schema["$ref"].(string); ref != "" {
+		v.resolve(location, ref)
+		return
+	}
+	for key := range schema {
+		if !openAPISchemaKeys[key] && !strings.HasPrefix(key, "x-") {
+			v.add(location+"/"+jsonPointerEscape(key), "%q is not an OpenAPI 3.0 schema keyword", key)
+		}
+	}
+	if schemaType, ok := schema["type"]; ok {
+		if name, _ := schemaType.(string); !openAPISchemaTypes[name] {
+			v.add(location+"/type", "type must be one of string, number, integer, boolean, array or object, got %v", schemaType)
+		}
+		if schemaType == "array" && schema["items"] == nil {
+			v.add(location, "array schema needs items")
+		}
+	}
+	if required, ok := schema["required"]; ok {
+		if list, _ := required.([]interface{}); len(list) == 0 {
+			v.add(location+"/required", "required must list at least one property")
+		}
+	}
+	if enum, ok := schema["enum"]; ok {
+		if list, _ := enum.([]interface{}); len(list) == 0 {
+			v.add(location+"/enum", "enum must list at least one value")
+		}
+	}
+
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, property := range properties {
+			v.schema(location+"/properties/"+jsonPointerEscape(name), property)
+		}
+	}
+	if items, ok := schema["items"]; ok && items != nil {
+		v.schema(location+"/items", items)
+	}
+	if additional, ok := schema["additionalProperties"]; ok {
+		if _, isBool := additional.(bool); !isBool {
+			v.schema(location+"/additionalProperties", additional)
+		}
+	}
+	if not, ok := schema["not"]; ok {
+		v.schema(location+"/not", not)
+	}
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		alternatives, _ := schema[key].([]interface{})
+		for i, alternative := range alternatives {
+			v.schema(fmt.Sprintf("%s/%s/%d", location, key, i), alternative)
+		}
+	}
+}
+
+// resolve looks up a local $ref, reporting it when it points nowhere
+func (v *specValidator) resolve(location, ref string) (map[string]interface{}, bool) {
+	if !strings.HasPrefix(ref, "#/") {
+		// External references are resolved by the consumer
+		return nil, false
+	}
+	var current interface{} = v.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			current = nil
+			break
+		}
+		current = object[part]
+	}
+	target, ok := current.(map[string]interface{})
+	if !ok {
+		v.add(location, "$ref %q does not resolve", ref)
+	}
+	return target, ok
+}
+
+// dropNullMediaFields removes the null examples and schemas that operations
+// without a documented body export with, which code generators turn into
+// broken defaults and untyped models
+func dropNullMediaFields(spec map[string]interface{}) {
+	dropNull := func(object map[string]interface{}, keys ...string) {
+		for _, key := range keys {
+			if value, ok := object[key]; ok && value == nil {
+				delete(object, key)
+			}
+		}
+	}
+	eachOperation(spec, func(path, method string, operation map[string]interface{}) {
+		params, _ := operation["parameters"].([]map[string]interface{})
+		for _, param := range params {
+			dropNull(param, "example")
+		}
+		holders := []interface{}{operation["requestBody"]}
+		responses, _ := operation["responses"].(map[string]interface{})
+		for _, response := range responses {
+			holders = append(holders, response)
+		}
+		for _, holder := range holders {
+			holderMap, _ := holder.(map[string]interface{})
+			content, _ := holderMap["content"].(map[string]interface{})
+			for _, media := range content {
+				if mediaMap, ok := media.(map[string]interface{}); ok {
+					dropNull(mediaMap, "schema", "example")
+				}
+			}
+		}
+	})
+	components, _ := spec["components"].(map[string]interface{})
+	shared, _ := components["parameters"].(map[string]interface{})
+	for _, param := range shared {
+		if paramMap, ok := param.(map[string]interface{}); ok {
+			dropNull(paramMap, "example")
+		}
+	}
+}
+
+// ValidateSpec checks the generated OpenAPI document with ValidateOpenAPISpec,
+// after dropping null examples and schemas as Config.SpecValidation does
+func (a *APIDocs) ValidateSpec() ([]SpecViolation, error) {
+	spec, err := a.GetOpenAPIJSON()
+	if err != nil {
+		var invalid *SpecValidationError
+		if errors.As(err, &invalid) {
+			return invalid.Violations, nil
+		}
+		return nil, err
+	}
+	dropNullMediaFields(spec)
+	return ValidateOpenAPISpec(spec)
+}
+
+// applySpecValidation drops null examples and validates an exported spec
+// according to Config.SpecValidation
+func (a *APIDocs) applySpecValidation(spec map[string]interface{}) error {
+	mode := a.config.SpecValidation
+	if mode == "" {
+		return nil
+	}
+	dropNullMediaFields(spec)
+	violations, err := ValidateOpenAPISpec(spec)
+	if err != nil {
+		return err
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	if mode == SpecValidationAnnotate {
+		spec["x-spec-violations"] = violations
+		return nil
+	}
+	return &SpecValidationError{Violations: violations}
+}
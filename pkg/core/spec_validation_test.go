@@ -0,0 +1,85 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSpecValidation_StrictAndAnnotate(t *testing.T) {
+	newDocs := func(mode string, schema interface{}) *APIDocs {
+		docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", SpecValidation: mode})
+		docs.AddRouteInfo(RouteInfo{Method: "GET", Path: "/users/:id", Responses: map[string]Response{
+			"200": {Description: "OK", Schema: schema},
+		}})
+		docs.AddRouteInfo(RouteInfo{Method: "DELETE", Path: "/users/:id"})
+		if err := docs.Generate(); err != nil {
+			t.Fatal(err)
+		}
+		return docs
+	}
+	valid := map[string]interface{}{"type": "object", "properties": map[string]interface{}{"id": map[string]interface{}{"type": "integer"}}}
+	invalid := map[string]interface{}{"type": "object", "properties": map[string]interface{}{
+		"tags":  map[string]interface{}{"type": "array"},
+		"owner": map[string]interface{}{"$ref": "#/components/schemas/Missing"},
+		"code":  map[string]interface{}{"type": "interface{}", "const": 1},
+	}}
+
+	spec, err := newDocs(SpecValidationStrict, valid).GetOpenAPIJSON()
+	if err != nil {
+		t.Fatalf("expected a valid spec to pass strict mode, got %v", err)
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "null") {
+		t.Fatalf("expected null examples and schemas dropped in strict mode, got %s", data)
+	}
+
+	violations, err := newDocs("", invalid).ValidateSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		messages = append(messages, violation.Location+": "+violation.Message)
+	}
+	joined := strings.Join(messages, "\n")
+	for _, expected := range []string{
+		"/properties/tags: array schema needs items",
+		"/properties/owner: $ref \"#/components/schemas/Missing\" does not resolve",
+		"/properties/code/type: type must be one of",
+		"/properties/code/const: \"const\" is not an OpenAPI 3.0 schema keyword",
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Fatalf("expected violation %q, got\n%s", expected, joined)
+		}
+	}
+
+	strict := newDocs(SpecValidationStrict, invalid)
+	var invalidSpec *SpecValidationError
+	if _, err := strict.GetOpenAPIJSON(); !errors.As(err, &invalidSpec) || len(invalidSpec.Violations) != len(violations) {
+		t.Fatalf("expected strict mode to fail with the violations, got %v", err)
+	}
+	recorder := httptest.NewRecorder()
+	strict.ServeHTTP(recorder, httptest.NewRequest("GET", "/docs/openapi.json", nil))
+	if recorder.Code != http.StatusInternalServerError || !strings.Contains(recorder.Body.String(), "array schema needs items") {
+		t.Fatalf("expected the export to fail loudly, got %d %s", recorder.Code, recorder.Body.String())
+	}
+
+	annotated, err := newDocs(SpecValidationAnnotate, invalid).GetOpenAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listed, _ := annotated["x-spec-violations"].([]SpecViolation); len(listed) != len(violations) {
+		t.Fatalf("expected the violations annotated on the spec, got %+v", annotated["x-spec-violations"])
+	}
+
+	if err := ValidateConfig(&Config{Title: "Test", DocsPath: "/docs", SpecValidation: "loose"}); err == nil {
+		t.Fatal("expected an unknown validation mode to be rejected")
+	}
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ServeStandalone serves the documentation, including its auth, chat and spec
+// endpoints, on a listener of its own, so it can stay on an internal-only port
+// (e.g. "127.0.0.1:9090") apart from the public API. It blocks like
+// http.ListenAndServe.
+func (a *APIDocs) ServeStandalone(addr string) error {
+	server := &http.Server{
+		Addr:              standaloneAddr(addr),
+		Handler:           a.StandaloneHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return server.ListenAndServe()
+}
+
+// StandaloneHandler returns the handler served by ServeStandalone, for callers
+// that run their own http.Server. The root redirects to the docs.
+func (a *APIDocs) StandaloneHandler() http.Handler {
+	docsPath := strings.TrimSuffix(a.config.DocsPath, "/")
+
+	mux := http.NewServeMux()
+	mux.Handle(docsPath+"/", a)
+	if docsPath != "" {
+		mux.Handle(docsPath, a)
+		mux.Handle("/{$}", http.RedirectHandler(docsPath+"/", http.StatusFound))
+	}
+	return mux
+}
+
+// standaloneAddr accepts a bare port such as "9090" as well as a listen address
+func standaloneAddr(addr string) string {
+	if addr != "" && !strings.Contains(addr, ":") {
+		return ":" + addr
+	}
+	return addr
+}
@@ -0,0 +1,35 @@
+package core
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStandaloneHandler_ServesOnlyDocs(t *testing.T) {
+	docs := New(&Config{Title: "API", Version: "1.0.0", DocsPath: "/docs"})
+	docs.AddRoute("GET", "/ping", nil)
+	handler := docs.StandaloneHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != 302 || rec.Header().Get("Location") != "/docs/" {
+		t.Fatalf("expected root to redirect to the docs, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/openapi.json", nil))
+	if rec.Code != 200 || !strings.Contains(rec.Body.String(), "/ping") {
+		t.Fatalf("expected the spec, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/ping", nil))
+	if rec.Code != 404 {
+		t.Fatalf("expected API routes to stay off the docs listener, got %d", rec.Code)
+	}
+
+	if standaloneAddr("9090") != ":9090" || standaloneAddr("127.0.0.1:9090") != "127.0.0.1:9090" {
+		t.Fatalf("unexpected listen address normalization")
+	}
+}
@@ -0,0 +1,269 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// summarySchemaDepth is how many levels of object properties are kept in
+// summary mode. Deeper bodies collapse to their type, $ref and required list.
+const summarySchemaDepth = 1
+
+// wantsSummary reports whether the request asked for the summarized spec (?summary=true)
+func wantsSummary(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+	summary, err := strconv.ParseBool(r.URL.Query().Get("summary"))
+	return err == nil && summary
+}
+
+// summarizeSchema returns a copy of schema without examples, descriptions or
+// property bodies nested deeper than depth. The original schema is not modified.
+func summarizeSchema(schema interface{}, depth int) interface{} {
+	if schema == nil {
+		return nil
+	}
+
+	schemaMap, ok := schema.(map[string]interface{})
+	if !ok {
+		// Normalize typed schemas (structs, typed maps) through JSON
+		raw, err := json.Marshal(schema)
+		if err != nil {
+			return schema
+		}
+		var normalized interface{}
+		if err := json.Unmarshal(raw, &normalized); err != nil {
+			return schema
+		}
+		if schemaMap, ok = normalized.(map[string]interface{}); !ok {
+			return normalized
+		}
+	}
+
+	if ref, ok := schemaMap["$ref"]; ok {
+		return map[string]interface{}{"$ref": ref}
+	}
+
+	summary := make(map[string]interface{})
+	for _, key := range []string{"type", "title", "format", "required", "enum", "nullable", "discriminator"} {
+		if value, ok := schemaMap[key]; ok {
+			summary[key] = value
+		}
+	}
+	for key, value := range schemaMap {
+		// Vendor extensions drive gateways and code generators, so they stay
+		if strings.HasPrefix(key, "x-") {
+			summary[key] = value
+		}
+	}
+
+	if items, ok := schemaMap["items"]; ok {
+		// Array items describe the same level as the array itself
+		summary["items"] = summarizeSchema(items, depth)
+	}
+	for _, key := range []string{"oneOf", "anyOf", "allOf"} {
+		// Alternatives describe the same level as the schema itself
+		if alternatives, ok := schemaMap[key].([]interface{}); ok {
+			summarized := make([]interface{}, len(alternatives))
+			for i, alternative := range alternatives {
+				summarized[i] = summarizeSchema(alternative, depth)
+			}
+			summary[key] = summarized
+		}
+	}
+
+	if depth <= 0 {
+		return summary
+	}
+
+	if properties, ok := schemaMap["properties"].(map[string]interface{}); ok {
+		summarized := make(map[string]interface{}, len(properties))
+		for name, property := range properties {
+			summarized[name] = summarizeSchema(property, depth-1)
+		}
+		summary["properties"] = summarized
+	}
+	if additional, ok := schemaMap["additionalProperties"]; ok {
+		if _, isBool := additional.(bool); isBool {
+			summary["additionalProperties"] = additional
+		} else {
+			summary["additionalProperties"] = summarizeSchema(additional, depth-1)
+		}
+	}
+
+	return summary
+}
+
+// summarizeComponentSchema strips examples from a component schema
+func summarizeComponentSchema(schema Schema) Schema {
+	summary := Schema{
+		Type:     schema.Type,
+		Required: schema.Required,
+	}
+	if len(schema.Properties) > 0 {
+		summary.Properties = make(map[string]Property, len(schema.Properties))
+		for name, property := range schema.Properties {
+			summary.Properties[name] = Property{
+				Type:   property.Type,
+				Format: property.Format,
+			}
+		}
+	}
+	return summary
+}
+
+// summarizeOpenAPI reduces a spec produced by GetOpenAPIJSON to its structure
+func summarizeOpenAPI(openAPI map[string]interface{}) map[string]interface{} {
+	if paths, ok := openAPI["paths"].(map[string]interface{}); ok {
+		for _, pathItem := range paths {
+			operations, ok := pathItem.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, op := range operations {
+				operation, ok := op.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				summarizeOperation(operation)
+			}
+		}
+	}
+
+	if components, ok := openAPI["components"].(map[string]interface{}); ok {
+		if schemas, ok := components["schemas"].(map[string]Schema); ok {
+			summarized := make(map[string]Schema, len(schemas))
+			for name, schema := range schemas {
+				summarized[name] = summarizeComponentSchema(schema)
+			}
+			components["schemas"] = summarized
+		} else if schemas, ok := components["schemas"].(map[string]interface{}); ok {
+			summarized := make(map[string]interface{}, len(schemas))
+			for name, schema := range schemas {
+				if typed, ok := schema.(Schema); ok {
+					summarized[name] = summarizeComponentSchema(typed)
+					continue
+				}
+				summarized[name] = summarizeSchema(schema, summarySchemaDepth)
+			}
+			components["schemas"] = summarized
+		}
+		if parameters, ok := components["parameters"].(map[string]interface{}); ok {
+			for _, param := range parameters {
+				if paramMap, ok := param.(map[string]interface{}); ok {
+					delete(paramMap, "example")
+				}
+			}
+		}
+	}
+
+	return openAPI
+}
+
+func summarizeOperation(operation map[string]interface{}) {
+	if params, ok := operation["parameters"].([]map[string]interface{}); ok {
+		for _, param := range params {
+			delete(param, "example")
+		}
+	}
+
+	if requestBody, ok := operation["requestBody"].(map[string]interface{}); ok {
+		summarizeContent(requestBody)
+	}
+
+	if responses, ok := operation["responses"].(map[string]interface{}); ok {
+		for _, response := range responses {
+			if responseMap, ok := response.(map[string]interface{}); ok {
+				summarizeContent(responseMap)
+			}
+		}
+	}
+}
+
+func summarizeContent(holder map[string]interface{}) {
+	content, ok := holder["content"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for contentType, media := range content {
+		mediaMap, ok := media.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		content[contentType] = map[string]interface{}{
+			"schema": summarizeSchema(mediaMap["schema"], summarySchemaDepth),
+		}
+	}
+}
+
+// summarizeDocumentation returns a copy of doc without examples or deep schema bodies
+func summarizeDocumentation(doc *Documentation) *Documentation {
+	summary := &Documentation{
+		Info:      doc.Info,
+		Endpoints: make([]EndpointSection, 0, len(doc.Endpoints)),
+	}
+
+	if len(doc.Schemas) > 0 {
+		summary.Schemas = make(map[string]Schema, len(doc.Schemas))
+		for name, schema := range doc.Schemas {
+			summary.Schemas[name] = summarizeComponentSchema(schema)
+		}
+	}
+
+	for _, section := range doc.Endpoints {
+		sectionCopy := section
+		sectionCopy.Endpoints = make([]Endpoint, 0, len(section.Endpoints))
+		for _, endpoint := range section.Endpoints {
+			sectionCopy.Endpoints = append(sectionCopy.Endpoints, summarizeEndpoint(endpoint))
+		}
+		summary.Endpoints = append(summary.Endpoints, sectionCopy)
+	}
+
+	return summary
+}
+
+func summarizeEndpoint(endpoint Endpoint) Endpoint {
+	if len(endpoint.Parameters) > 0 {
+		params := make([]Parameter, len(endpoint.Parameters))
+		for i, param := range endpoint.Parameters {
+			param.Example = nil
+			params[i] = param
+		}
+		endpoint.Parameters = params
+	}
+
+	// Presets are example values too
+	endpoint.Presets = nil
+
+	if endpoint.RequestBody != nil {
+		body := *endpoint.RequestBody
+		body.Example = nil
+		body.Examples = nil
+		body.Schema = summarizeSchema(body.Schema, summarySchemaDepth)
+		if len(body.Alternatives) > 0 {
+			body.Alternatives = make([]BodyContent, len(endpoint.RequestBody.Alternatives))
+			for i, content := range endpoint.RequestBody.Alternatives {
+				content.Example = nil
+				content.Schema = summarizeSchema(content.Schema, summarySchemaDepth)
+				body.Alternatives[i] = content
+			}
+		}
+		endpoint.RequestBody = &body
+	}
+
+	if len(endpoint.Responses) > 0 {
+		responses := make(map[string]Response, len(endpoint.Responses))
+		for status, response := range endpoint.Responses {
+			response.Example = nil
+			response.Examples = nil
+			response.Schema = summarizeSchema(response.Schema, summarySchemaDepth)
+			responses[status] = response
+		}
+		endpoint.Responses = responses
+	}
+
+	return endpoint
+}
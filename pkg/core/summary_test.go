@@ -0,0 +1,46 @@
+package core
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenAPISummaryMode_DropsExamplesAndDeepBodies(t *testing.T) {
+	type address struct {
+		City string `json:"city" example:"Jakarta"`
+	}
+	type user struct {
+		Name    string  `json:"name" binding:"required"`
+		Address address `json:"address"`
+	}
+
+	docs := New(nil)
+	docs.AddRoute("POST", "/users", nil, WithRequest(user{}), WithResponse(200, []user{}))
+
+	req := httptest.NewRequest("GET", "/docs/openapi.json?summary=true", nil)
+	spec, err := docs.GetOpenAPIJSONForRequest(req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+
+	operation := spec["paths"].(map[string]interface{})["/users"].(map[string]interface{})["post"].(map[string]interface{})
+	media := operation["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	if _, ok := media["example"]; ok {
+		t.Fatalf("summary mode should omit examples")
+	}
+	schema := media["schema"].(map[string]interface{})
+	if required := schema["required"].([]string); len(required) != 1 || required[0] != "name" {
+		t.Fatalf("summary mode should keep required lists, got %v", schema["required"])
+	}
+	addressSchema := schema["properties"].(map[string]interface{})["address"].(map[string]interface{})
+	if _, ok := addressSchema["properties"]; ok {
+		t.Fatalf("summary mode should drop nested property bodies, got %v", addressSchema)
+	}
+
+	// The full spec must be unaffected by summarizing
+	full, _ := docs.GetOpenAPIJSON()
+	fullMedia := full["paths"].(map[string]interface{})["/users"].(map[string]interface{})["post"].(map[string]interface{})["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	if fullMedia["example"] == nil {
+		t.Fatalf("full spec should still include examples")
+	}
+}
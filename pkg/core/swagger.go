@@ -0,0 +1,395 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// swaggerFormContentTypes are request content types sent as formData parameters
+var swaggerFormContentTypes = map[string]bool{
+	"application/x-www-form-urlencoded": true,
+	"multipart/form-data":               true,
+}
+
+// GetSwaggerJSON returns the documentation as a Swagger 2.0 (OpenAPI v2)
+// document, for gateways and tools that do not read OpenAPI 3
+func (a *APIDocs) GetSwaggerJSON() (map[string]interface{}, error) {
+	return a.GetSwaggerJSONForRequest(nil)
+}
+
+// GetSwaggerJSONForRequest is GetSwaggerJSON with host, basePath and schemes
+// derived from the request when no base URL is configured
+func (a *APIDocs) GetSwaggerJSONForRequest(r *http.Request) (map[string]interface{}, error) {
+	if err := a.build(); err != nil {
+		return nil, err
+	}
+	documentation := a.GetDocumentation()
+
+	swagger := map[string]interface{}{
+		"swagger": "2.0",
+		"info": map[string]interface{}{
+			"title":       documentation.Info.Title,
+			"version":     documentation.Info.Version,
+			"description": documentation.Info.Description,
+		},
+		"paths":       map[string]interface{}{},
+		"definitions": map[string]interface{}{},
+	}
+
+	baseURL := a.config.BaseURL
+	if baseURL == "" && len(a.config.BaseURLs) > 0 {
+		// Swagger 2.0 has no server variables; use their defaults
+		baseURL = a.config.BaseURLs[0].DefaultURL()
+	}
+	if baseURL == "" && r != nil {
+		baseURL = a.requestServerURL(r)
+	}
+	if parsed, err := url.Parse(baseURL); err == nil && parsed.Host != "" {
+		swagger["host"] = parsed.Host
+		swagger["basePath"] = "/"
+		if basePath := strings.TrimSuffix(parsed.Path, "/"); basePath != "" {
+			swagger["basePath"] = basePath
+		}
+		if parsed.Scheme != "" {
+			swagger["schemes"] = []string{parsed.Scheme}
+		}
+	}
+
+	definitions := swagger["definitions"].(map[string]interface{})
+	for name, schema := range documentation.Schemas {
+		definitions[name] = swaggerSchema(schema)
+	}
+
+	consumes := make(map[string]bool)
+	produces := make(map[string]bool)
+	paths := swagger["paths"].(map[string]interface{})
+	for _, section := range documentation.Endpoints {
+		for _, endpoint := range section.Endpoints {
+			pathKey := convertPathToOpenAPI(endpoint.Path)
+			pathItem, _ := paths[pathKey].(map[string]interface{})
+			if pathItem == nil {
+				pathItem = make(map[string]interface{})
+				paths[pathKey] = pathItem
+			}
+			pathItem[strings.ToLower(endpoint.Method)] = swaggerOperation(endpoint, section.Name, documentation.SharedParameters, consumes, produces)
+		}
+	}
+
+	parameters := make(map[string]interface{})
+	for key, param := range documentation.SharedParameters {
+		if param.In != "cookie" {
+			parameters[key] = swaggerParameter(param)
+		}
+	}
+	if len(parameters) > 0 {
+		swagger["parameters"] = parameters
+	}
+
+	if len(consumes) > 0 {
+		swagger["consumes"] = sortedKeys(consumes)
+	}
+	if len(produces) > 0 {
+		swagger["produces"] = sortedKeys(produces)
+	}
+	setExtensions(swagger, a.config.Extensions)
+	return swagger, nil
+}
+
+// swaggerOperation converts one endpoint, collecting its media types
+func swaggerOperation(endpoint Endpoint, tag string, shared map[string]Parameter, consumes, produces map[string]bool) map[string]interface{} {
+	operation := map[string]interface{}{
+		"summary":     endpoint.Summary,
+		"description": endpoint.Description,
+		"tags":        []string{tag},
+		"operationId": endpoint.ID,
+	}
+	if endpoint.Deprecated {
+		operation["deprecated"] = true
+	}
+	if endpoint.ExternalDocs != nil {
+		operation["externalDocs"] = endpoint.ExternalDocs
+	}
+	if endpoint.SLA > 0 {
+		operation["x-sla"] = endpoint.SLA.String()
+	}
+	if endpoint.Pagination != "" {
+		operation["x-pagination"] = endpoint.Pagination
+	}
+	setExtensions(operation, endpoint.Extensions)
+
+	parameters := make([]map[string]interface{}, 0, len(endpoint.Parameters))
+	for _, param := range endpoint.Parameters {
+		if param.In == "cookie" {
+			// Swagger 2.0 has no cookie parameters
+			continue
+		}
+		if key, ok := sharedParameterRef(param, shared); ok {
+			parameters = append(parameters, map[string]interface{}{"$ref": "#/parameters/" + key})
+			continue
+		}
+		parameters = append(parameters, swaggerParameter(param))
+	}
+
+	if body := endpoint.RequestBody; body != nil {
+		contentType := body.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		operationConsumes := make([]string, 0, len(body.Alternatives)+1)
+		for _, content := range body.Contents() {
+			consumes[content.ContentType] = true
+			operationConsumes = append(operationConsumes, content.ContentType)
+		}
+		// Swagger 2.0 has one body schema per operation; it documents the primary content type
+		operation["consumes"] = operationConsumes
+
+		schema := swaggerSchema(body.Schema)
+		if swaggerFormContentTypes[contentType] {
+			parameters = append(parameters, swaggerFormParameters(schema)...)
+		} else {
+			parameter := map[string]interface{}{
+				"name":     "body",
+				"in":       "body",
+				"required": body.Required,
+				"schema":   schema,
+			}
+			if schema == nil {
+				parameter["schema"] = map[string]interface{}{"type": "object"}
+			}
+			if description := body.description(); description != "" {
+				parameter["description"] = description
+			}
+			parameters = append(parameters, parameter)
+		}
+	}
+	operation["parameters"] = parameters
+
+	responses := make(map[string]interface{}, len(endpoint.Responses))
+	operationProduces := make(map[string]bool)
+	for status, response := range endpoint.Responses {
+		entry := map[string]interface{}{"description": response.Description}
+		if response.Schema != nil || response.Example != nil {
+			contentType := response.ContentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			produces[contentType] = true
+			operationProduces[contentType] = true
+			if response.Schema != nil {
+				entry["schema"] = swaggerSchema(response.Schema)
+			}
+			if response.Example != nil {
+				entry["examples"] = map[string]interface{}{contentType: response.Example}
+			}
+		}
+		if response.Streaming {
+			entry["x-streaming"] = true
+		}
+		if len(response.Headers) > 0 {
+			headers := make(map[string]interface{}, len(response.Headers))
+			for name, header := range response.Headers {
+				headerEntry := map[string]interface{}{"type": normalizeOpenAPIType(header.Type)}
+				if header.Description != "" {
+					headerEntry["description"] = header.Description
+				}
+				headers[name] = headerEntry
+			}
+			entry["headers"] = headers
+		}
+		responses[status] = entry
+	}
+	if len(responses) == 0 {
+		responses["default"] = map[string]interface{}{"description": "Response"}
+	}
+	operation["responses"] = responses
+	if len(operationProduces) > 0 {
+		operation["produces"] = sortedKeys(operationProduces)
+	}
+	return operation
+}
+
+// swaggerParameter converts a parameter to a Swagger 2.0 parameter object
+func swaggerParameter(param Parameter) map[string]interface{} {
+	parameter := map[string]interface{}{
+		"name":        param.Name,
+		"in":          param.In,
+		"required":    param.Required || param.In == "path",
+		"description": param.Description,
+		"type":        normalizeOpenAPIType(param.Type),
+	}
+	if parameter["type"] == "array" {
+		parameter["items"] = map[string]interface{}{"type": "string"}
+	}
+	if param.Example != nil {
+		parameter["x-example"] = param.Example
+	}
+	if param.Wildcard {
+		parameter["x-wildcard"] = true
+	}
+	return parameter
+}
+
+// swaggerFormParameters turns the properties of a form body schema into formData parameters
+func swaggerFormParameters(schema interface{}) []map[string]interface{} {
+	schemaMap, _ := schema.(map[string]interface{})
+	properties, _ := schemaMap["properties"].(map[string]interface{})
+	required := make(map[string]bool)
+	if list, ok := schemaMap["required"].([]interface{}); ok {
+		for _, name := range list {
+			if text, ok := name.(string); ok {
+				required[text] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parameters := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		property, _ := properties[name].(map[string]interface{})
+		propertyType, _ := property["type"].(string)
+		if format, _ := property["format"].(string); format == "binary" {
+			propertyType = "file"
+		}
+		if propertyType == "" || propertyType == "object" {
+			propertyType = "string"
+		}
+		parameter := map[string]interface{}{
+			"name":     name,
+			"in":       "formData",
+			"required": required[name],
+			"type":     propertyType,
+		}
+		if description, ok := property["description"].(string); ok {
+			parameter["description"] = description
+		}
+		if items, ok := property["items"]; ok && propertyType == "array" {
+			parameter["items"] = items
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters
+}
+
+// swaggerSchema converts an OpenAPI 3 schema to Swagger 2.0: component refs
+// point to definitions, nullable becomes x-nullable, and oneOf/anyOf, which
+// Swagger 2.0 lacks, fall back to their first alternative
+func swaggerSchema(schema interface{}) interface{} {
+	if schema == nil {
+		return nil
+	}
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return schema
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return schema
+	}
+	return convertSwaggerSchema(normalized)
+}
+
+// convertSwaggerSchema converts a decoded schema and its nested schemas
+func convertSwaggerSchema(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(typed))
+		for key, child := range typed {
+			switch key {
+			case "$ref":
+				if ref, ok := child.(string); ok {
+					converted[key] = strings.Replace(ref, "#/components/schemas/", "#/definitions/", 1)
+					continue
+				}
+			case "nullable":
+				converted["x-nullable"] = child
+				continue
+			case "discriminator":
+				// Swagger 2.0 names the discriminator property without a mapping
+				if discriminator, ok := child.(map[string]interface{}); ok {
+					converted[key] = discriminator["propertyName"]
+					continue
+				}
+			case "oneOf", "anyOf":
+				if alternatives, ok := child.([]interface{}); ok && len(alternatives) > 0 {
+					if first, ok := convertSwaggerSchema(alternatives[0]).(map[string]interface{}); ok {
+						for firstKey, firstValue := range first {
+							if _, exists := typed[firstKey]; !exists {
+								converted[firstKey] = firstValue
+							}
+						}
+					}
+				}
+				continue
+			case "example", "examples", "default", "enum":
+				converted[key] = child
+				continue
+			case "properties":
+				// Property names are user names, not keywords
+				if properties, ok := child.(map[string]interface{}); ok {
+					convertedProperties := make(map[string]interface{}, len(properties))
+					for name, property := range properties {
+						convertedProperties[name] = convertSwaggerSchema(property)
+					}
+					converted[key] = convertedProperties
+					continue
+				}
+			}
+			converted[key] = convertSwaggerSchema(child)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(typed))
+		for i, child := range typed {
+			converted[i] = convertSwaggerSchema(child)
+		}
+		return converted
+	default:
+		return value
+	}
+}
+
+// sortedKeys returns the keys of set in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// serveSwagger serves the Swagger 2.0 document like /openapi.json
+func (a *APIDocs) serveSwagger(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	swagger, err := a.GetSwaggerJSONForRequest(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to generate Swagger JSON: %v", err), http.StatusInternalServerError)
+		return
+	}
+	body, err := json.Marshal(swagger)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode Swagger JSON: %v", err), http.StatusInternalServerError)
+		return
+	}
+	a.writeCacheable(w, r, append(body, '\n'))
+}
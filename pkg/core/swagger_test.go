@@ -0,0 +1,52 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSwagger_DowngradesDocumentationToV2(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", BaseURL: "https://api.example.com/v1"})
+	docs.AddRoute("POST", "/users/:id", nil,
+		WithRequest(struct {
+			Name string `json:"name" binding:"required"`
+		}{}),
+		WithResponse(http.StatusCreated, struct {
+			ID string `json:"id"`
+		}{}))
+	docs.AddRoute("POST", "/avatars", nil, WithRequestContentType("multipart/form-data", struct {
+		Caption string `json:"caption"`
+	}{}))
+	docs.OverrideEndpoint("POST", "/users/:id", func(e *Endpoint) {
+		e.Responses["201"] = Response{Description: "Created", Schema: map[string]interface{}{
+			"type": "object", "nullable": true,
+			"properties": map[string]interface{}{"owner": map[string]interface{}{"$ref": "#/components/schemas/User"}},
+		}}
+	})
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/swagger.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected the Swagger document, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	var spec map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec["swagger"] != "2.0" || spec["host"] != "api.example.com" || spec["basePath"] != "/v1" || spec["definitions"] == nil {
+		t.Fatalf("expected swagger 2.0 with host and basePath, got %s", body)
+	}
+	if !strings.Contains(body, `"in":"body"`) || !strings.Contains(body, `"in":"formData"`) || !strings.Contains(body, `"in":"path"`) {
+		t.Fatalf("expected body, formData and path parameters, got %s", body)
+	}
+	if !strings.Contains(body, `"#/definitions/User"`) || !strings.Contains(body, `"x-nullable":true`) || strings.Contains(body, "components") {
+		t.Fatalf("expected refs and nullable converted to Swagger 2.0, got %s", body)
+	}
+	if !strings.Contains(body, `"consumes":["application/json","multipart/form-data"]`) || !strings.Contains(body, `"produces":["application/json"]`) {
+		t.Fatalf("expected top-level consumes and produces, got %s", body)
+	}
+}
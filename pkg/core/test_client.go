@@ -0,0 +1,175 @@
+package core
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Redirect policies of TestClientConfig
+const (
+	RedirectsFollow   = "follow"
+	RedirectsSameHost = "same-host"
+	RedirectsNone     = "none"
+)
+
+const defaultMaxRedirects = 10
+
+// TestClientConfig configures the HTTP client that sends the Try It and
+// scenario requests of the docs UI to the target API
+type TestClientConfig struct {
+	CAFile             string `json:"caFile,omitempty"`             // PEM CA bundle trusted besides the system roots
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"` // skip server certificate verification; for test environments only
+	ClientCertFile     string `json:"clientCertFile,omitempty"`     // PEM client certificate for mTLS
+	ClientKeyFile      string `json:"clientKeyFile,omitempty"`      // PEM key of ClientCertFile
+	Proxy              string `json:"proxy,omitempty"`              // outbound proxy URL; empty uses HTTP_PROXY, HTTPS_PROXY and NO_PROXY, "direct" uses none
+	Redirects          string `json:"redirects,omitempty"`          // "follow" (default), "same-host" or "none", which returns the 3xx response
+	MaxRedirects       int    `json:"maxRedirects,omitempty"`       // redirects followed before failing; 0 uses 10
+
+	// Environments replace these settings for requests to a base URL, by its
+	// name (e.g. "Staging") or host (e.g. "staging.api.example.com")
+	Environments map[string]*TestClientConfig `json:"environments,omitempty"`
+}
+
+// testClientConfigFromEnv loads the default settings from BYTEDOCS_UI_TEST_*;
+// nil when none is set
+func testClientConfigFromEnv() *TestClientConfig {
+	config := &TestClientConfig{
+		CAFile:             getEnvOrDefault("BYTEDOCS_UI_TEST_CA_FILE", ""),
+		InsecureSkipVerify: getEnvBool("BYTEDOCS_UI_TEST_INSECURE_SKIP_VERIFY", false),
+		ClientCertFile:     getEnvOrDefault("BYTEDOCS_UI_TEST_CLIENT_CERT_FILE", ""),
+		ClientKeyFile:      getEnvOrDefault("BYTEDOCS_UI_TEST_CLIENT_KEY_FILE", ""),
+		Proxy:              getEnvOrDefault("BYTEDOCS_UI_TEST_PROXY", ""),
+		Redirects:          getEnvOrDefault("BYTEDOCS_UI_TEST_REDIRECTS", ""),
+	}
+	if config.CAFile == "" && !config.InsecureSkipVerify && config.ClientCertFile == "" && config.ClientKeyFile == "" && config.Proxy == "" && config.Redirects == "" {
+		return nil
+	}
+	return config
+}
+
+// ForURL returns the settings for a request to target: those of the
+// environment whose name or host matches, else c itself
+func (c *TestClientConfig) ForURL(target string, baseURLs []BaseURLOption) *TestClientConfig {
+	if c == nil || len(c.Environments) == 0 {
+		return c
+	}
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return c
+	}
+	host := parsed.Hostname()
+	if environment, ok := c.Environments[host]; ok && environment != nil {
+		return environment
+	}
+	for _, option := range baseURLs {
+		environment, ok := c.Environments[option.Name]
+		if !ok || environment == nil {
+			continue
+		}
+		if base, err := url.Parse(option.URL); err == nil && strings.EqualFold(base.Hostname(), host) {
+			return environment
+		}
+	}
+	return c
+}
+
+// Transport builds the transport of these settings, reading the CA bundle
+// and client certificate
+func (c *TestClientConfig) Transport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c == nil {
+		return transport, nil
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify}
+	if c.CAFile != "" {
+		bundle, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.ClientCertFile != "" || c.ClientKeyFile != "" {
+		if c.ClientCertFile == "" || c.ClientKeyFile == "" {
+			return nil, fmt.Errorf("client certificate and key files are both required for mTLS")
+		}
+		certificate, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	switch c.Proxy {
+	case "":
+	case "direct":
+		transport.Proxy = nil
+	default:
+		proxyURL, err := url.Parse(c.Proxy)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", c.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return transport, nil
+}
+
+// CheckRedirect is the redirect policy of these settings for http.Client
+func (c *TestClientConfig) CheckRedirect(req *http.Request, via []*http.Request) error {
+	policy, maxRedirects := RedirectsFollow, defaultMaxRedirects
+	if c != nil {
+		if c.Redirects != "" {
+			policy = c.Redirects
+		}
+		if c.MaxRedirects > 0 {
+			maxRedirects = c.MaxRedirects
+		}
+	}
+
+	switch {
+	case policy == RedirectsNone:
+		return http.ErrUseLastResponse
+	case policy == RedirectsSameHost && !strings.EqualFold(req.URL.Host, via[0].URL.Host):
+		return http.ErrUseLastResponse
+	case len(via) >= maxRedirects:
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	return nil
+}
+
+// validate checks these settings and those of every environment
+func (c *TestClientConfig) validate() error {
+	switch c.Redirects {
+	case "", RedirectsFollow, RedirectsSameHost, RedirectsNone:
+	default:
+		return fmt.Errorf("unknown redirect policy %q, use %q, %q or %q", c.Redirects, RedirectsFollow, RedirectsSameHost, RedirectsNone)
+	}
+	if _, err := c.Transport(); err != nil {
+		return err
+	}
+	for name, environment := range c.Environments {
+		if environment == nil {
+			continue
+		}
+		if len(environment.Environments) > 0 {
+			return fmt.Errorf("test client environment %s: environments cannot be nested", name)
+		}
+		if err := environment.validate(); err != nil {
+			return fmt.Errorf("test client environment %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTestClientConfig_TLSProxyAndRedirects(t *testing.T) {
+	dir := t.TempDir()
+	writePEM := func(name, kind string, der []byte) string {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "docs"},
+		NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour),
+		KeyUsage: x509.KeyUsageDigitalSignature, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCert := writePEM("client.pem", "CERTIFICATE", der)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	clientKey := writePEM("client-key.pem", "EC PRIVATE KEY", keyDER)
+	clientCA, _ := x509.ParseCertificate(der)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: x509.NewCertPool()}
+	server.TLS.ClientCAs.AddCert(clientCA)
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // rejected handshakes are expected
+	server.StartTLS()
+	defer server.Close()
+	serverCA := writePEM("ca.pem", "CERTIFICATE", server.Certificate().Raw)
+
+	get := func(config *TestClientConfig, path string) (int, error) {
+		transport, err := config.Transport()
+		if err != nil {
+			return 0, err
+		}
+		client := &http.Client{Transport: transport, CheckRedirect: config.CheckRedirect}
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+
+	if _, err := get(&TestClientConfig{CAFile: serverCA}, "/"); err == nil {
+		t.Fatal("expected the server to require a client certificate")
+	}
+	mtls := &TestClientConfig{CAFile: serverCA, ClientCertFile: clientCert, ClientKeyFile: clientKey}
+	if status, err := get(mtls, "/old"); err != nil || status != http.StatusOK {
+		t.Fatalf("expected mTLS to succeed and follow the redirect, got %d %v", status, err)
+	}
+	mtls.Redirects = RedirectsNone
+	if status, err := get(mtls, "/old"); err != nil || status != http.StatusFound {
+		t.Fatalf("expected the redirect returned, got %d %v", status, err)
+	}
+
+	config := &TestClientConfig{Environments: map[string]*TestClientConfig{
+		"Staging": {InsecureSkipVerify: true, ClientCertFile: clientCert, ClientKeyFile: clientKey},
+	}}
+	baseURLs := []BaseURLOption{{Name: "Production", URL: "https://api.example.com"}, {Name: "Staging", URL: server.URL + "/v1"}}
+	staging := config.ForURL(server.URL+"/v1/users", baseURLs)
+	if staging != config.Environments["Staging"] || config.ForURL("https://api.example.com/users", baseURLs) != config {
+		t.Fatal("expected the environment picked by base URL")
+	}
+	if status, err := get(staging, "/"); err != nil || status != http.StatusOK {
+		t.Fatalf("expected skip-verify for the environment, got %d %v", status, err)
+	}
+	if _, err := get(config, "/"); err == nil {
+		t.Fatal("expected certificate verification outside the environment")
+	}
+
+	proxied, err := (&TestClientConfig{Proxy: "http://proxy.internal:3128"}).Transport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proxyURL, _ := proxied.Proxy(httptest.NewRequest("GET", "https://api.example.com/", nil)); proxyURL == nil || proxyURL.Host != "proxy.internal:3128" {
+		t.Fatalf("expected the proxy, got %v", proxyURL)
+	}
+
+	for _, invalid := range []*TestClientConfig{
+		{Redirects: "sometimes"},
+		{CAFile: filepath.Join(dir, "missing.pem")},
+		{ClientCertFile: clientCert},
+		{Proxy: "proxy.internal"},
+		{Environments: map[string]*TestClientConfig{"Staging": {CAFile: clientKey}}},
+	} {
+		if err := ValidateConfig(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", UIConfig: &UIConfig{TestClient: invalid}}); err == nil {
+			t.Fatalf("expected %+v rejected", invalid)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTOTP_RFC6238VectorAndEnrollment(t *testing.T) {
+	// RFC 6238 SHA-1 vector: 94287082 at T=59, truncated to 6 digits
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	if counter, ok := verifyTOTP(secret, "287082", time.Unix(59, 0)); !ok || counter != 1 {
+		t.Fatalf("expected the RFC 6238 code to verify, got %d %v", counter, ok)
+	}
+
+	t.Chdir("../..") // auth templates are loaded relative to the module root
+	var enrolled string
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", AuthConfig: &AuthConfig{
+		Enabled: true, Type: "session", Password: "secret", SessionExpire: 60, IPBanEnabled: true,
+		TOTPEnabled: true, TOTPIssuer: "Acme Docs", TOTPUsers: map[string]string{"alice": ""},
+		OnTOTPEnroll: func(username, secret string) error { enrolled = username + ":" + secret; return nil },
+	}})
+
+	csrf := &http.Cookie{Name: csrfCookieName, Value: strings.Repeat("c", 44)}
+	post := func(form string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/docs", strings.NewReader(form+"&csrf_token="+csrf.Value))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(csrf)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		docs.ServeHTTP(rec, req)
+		return rec
+	}
+	cookieNamed := func(rec *httptest.ResponseRecorder, name string) *http.Cookie {
+		for _, cookie := range rec.Result().Cookies() {
+			if cookie.Name == name && cookie.MaxAge >= 0 {
+				return cookie
+			}
+		}
+		return nil
+	}
+
+	if rec := post("username=mallory&password=secret"); cookieNamed(rec, totpCookieName) != nil {
+		t.Fatalf("unknown users must not reach the second factor")
+	}
+
+	rec := post("username=alice&password=secret")
+	pending := cookieNamed(rec, totpCookieName)
+	match := regexp.MustCompile(`otpauth://totp/Acme%20Docs:alice\?[^"]*secret=([A-Z2-7]+)`).FindStringSubmatch(rec.Body.String())
+	if pending == nil || match == nil {
+		t.Fatalf("expected the enrollment page with an otpauth URI")
+	}
+	key, _ := decodeTOTPSecret(match[1])
+	code := totpCode(key, time.Now().Unix()/totpPeriod)
+
+	if rec := post("totp_code=000000", pending); cookieNamed(rec, sessionCookieName) != nil {
+		t.Fatalf("a wrong code must not create a session")
+	}
+	rec = post("totp_code="+code, pending)
+	if cookieNamed(rec, sessionCookieName) == nil || enrolled != "alice:"+match[1] {
+		t.Fatalf("expected a session and the enrolled secret, got %q", enrolled)
+	}
+
+	// The enrolled user now gets the code form only, and a used code is not accepted twice
+	rec = post("username=alice&password=secret")
+	if strings.Contains(rec.Body.String(), "otpauth://") {
+		t.Fatalf("enrolled users must not see their secret again")
+	}
+	if rec := post("totp_code="+code, cookieNamed(rec, totpCookieName)); cookieNamed(rec, sessionCookieName) != nil {
+		t.Fatalf("a replayed code must not create a session")
+	}
+}
@@ -0,0 +1,645 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTrafficBodyBytes = 64 << 10
+	defaultTrafficRoutes    = 200
+)
+
+// TrafficConfig enables Middleware, which documents routes ByteDocs cannot
+// analyze from the requests and responses they serve. Observed routes wait in
+// a review queue, served at <docs path>/traffic, until they are published.
+// With CaptureExamples, the bodies of documented routes become examples.
+type TrafficConfig struct {
+	Enabled       bool
+	SampleRate    float64  // share of requests recorded, e.g. 0.1; 0 records every request
+	MaxBodyBytes  int64    // bytes of each request and response body inspected; larger bodies are not parsed; 0 uses 64 KiB
+	MaxRoutes     int      // distinct routes queued or published from the queue; later new routes are dropped; 0 uses 200
+	RedactFields  []string // JSON fields and query parameters whose values are replaced, on top of Config.Redaction and the built-in rules
+	PathTemplates []string // templates such as "/users/{id}" matched before IDs are guessed from numeric and UUID segments
+	AutoPublish   bool     // document observed routes right away instead of queueing them for review
+
+	CaptureExamples bool // attach the redacted bodies of documented routes as further examples, also without Enabled
+	MaxExamples     int  // captured examples kept per request body and response status; 0 uses 3
+}
+
+// ObservedRoute is a route documented from recorded traffic
+type ObservedRoute struct {
+	Method      string              `json:"method"`
+	Path        string              `json:"path"` // OpenAPI template, e.g. "/users/{userId}"
+	Requests    int                 `json:"requests"`
+	FirstSeen   time.Time           `json:"firstSeen"`
+	LastSeen    time.Time           `json:"lastSeen"`
+	Parameters  []Parameter         `json:"parameters"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// trafficStore holds the observed routes awaiting review
+type trafficStore struct {
+	mu        sync.Mutex
+	routes    map[string]*ObservedRoute // by "METHOD path"
+	discarded map[string]bool
+	published map[string]bool              // count against MaxRoutes and are not queued again
+	examples  map[string]*capturedExamples // of documented routes, by "METHOD path"
+}
+
+var (
+	uuidSegment    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	opaqueSegment  = regexp.MustCompile(`^[0-9A-Za-z]{16,}$`)
+	digitsInString = regexp.MustCompile(`[0-9]`)
+)
+
+// Middleware records the traffic of next, any http.Handler stack, when
+// Config.Traffic is enabled. Sampled requests to routes that are not
+// documented yet are turned into observed routes: the path template, query
+// and path parameters, and the schemas and redacted examples of JSON request
+// and response bodies. Requests to the docs themselves are not recorded, and
+// only 2xx and 3xx responses start a new observed route, so scanners probing
+// for missing paths do not fill the queue. Upgrade requests such as WebSocket
+// handshakes reach next with the original writer.
+func (a *APIDocs) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		config := a.config.Traffic
+		if config == nil || (!config.Enabled && !config.CaptureExamples) || strings.HasPrefix(r.URL.Path, a.config.DocsPath) ||
+			r.Header.Get("Upgrade") != "" || (config.SampleRate > 0 && rand.Float64() >= config.SampleRate) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		limit := config.MaxBodyBytes
+		if limit <= 0 {
+			limit = defaultTrafficBodyBytes
+		}
+
+		var requestBody []byte
+		if r.Body != nil && r.Body != http.NoBody {
+			requestBody, _ = io.ReadAll(io.LimitReader(r.Body, limit+1))
+			r.Body = readCloser{io.MultiReader(bytes.NewReader(requestBody), r.Body), r.Body}
+		}
+		recorder := &trafficRecorder{ResponseWriter: w, status: http.StatusOK, limit: limit}
+		next.ServeHTTP(recorder, r)
+		if recorder.hijacked {
+			return
+		}
+
+		a.recordTraffic(r, requestBody, limit, recorder)
+	})
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// trafficRecorder keeps the status and the first bytes of a response
+type trafficRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+	limit       int64
+	truncated   bool
+	hijacked    bool // the handler took over the connection, nothing to record
+}
+
+func (r *trafficRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *trafficRecorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	if remaining := r.limit - int64(r.body.Len()); remaining > 0 {
+		if int64(len(data)) > remaining {
+			r.body.Write(data[:remaining])
+			r.truncated = true
+		} else {
+			r.body.Write(data)
+		}
+	} else if len(data) > 0 {
+		r.truncated = true
+	}
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *trafficRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hands the connection to handlers that take it over themselves
+func (r *trafficRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	r.hijacked = true
+	return hijacker.Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the original writer
+func (r *trafficRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// recordTraffic merges a served request into its observed route, or
+// captures its bodies as examples when the route is documented
+func (a *APIDocs) recordTraffic(r *http.Request, requestBody []byte, limit int64, response *trafficRecorder) {
+	config := a.config.Traffic
+	method := strings.ToUpper(r.Method)
+	_, redact := a.redactors()
+
+	var body *RequestBody
+	if len(requestBody) > 0 {
+		contentType := mediaType(r.Header.Get("Content-Type"))
+		schema, example := observedBody(contentType, requestBody, int64(len(requestBody)) > limit, redact)
+		body = &RequestBody{ContentType: contentType, Schema: schema, Example: example, Required: true}
+	}
+	status := strconv.Itoa(response.status)
+	contentType := mediaType(response.Header().Get("Content-Type"))
+	responseDoc := Response{Description: http.StatusText(response.status), ContentType: contentType}
+	if response.body.Len() > 0 {
+		responseDoc.Schema, responseDoc.Example = observedBody(contentType, response.body.Bytes(), response.truncated, redact)
+	}
+
+	if documented, ok := a.documentedPath(method, r.URL.Path); ok {
+		if config.CaptureExamples {
+			var requestExample interface{}
+			if body != nil {
+				requestExample = body.Example
+			}
+			a.captureExamples(method+" "+documented, requestExample, status, responseDoc.Example)
+		}
+		return
+	}
+	if !config.Enabled {
+		return
+	}
+	path, pathValues := templatePath(r.URL.Path, config.PathTemplates)
+	key := method + " " + path
+
+	parameters := make([]Parameter, 0)
+	for _, name := range extractPathParams(path) {
+		parameters = append(parameters, observedParameter(name, "path", pathValues[name], redact))
+	}
+	query := r.URL.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parameters = append(parameters, observedParameter(name, "query", query.Get(name), redact))
+	}
+
+	now := time.Now().UTC()
+	a.traffic.mu.Lock()
+	if a.traffic.discarded[key] {
+		a.traffic.mu.Unlock()
+		return
+	}
+	route, ok := a.traffic.routes[key]
+	if !ok {
+		maxRoutes := config.MaxRoutes
+		if maxRoutes <= 0 {
+			maxRoutes = defaultTrafficRoutes
+		}
+		if response.status >= 400 || a.traffic.published[key] || len(a.traffic.routes)+len(a.traffic.published) >= maxRoutes {
+			a.traffic.mu.Unlock()
+			return
+		}
+		if a.traffic.routes == nil {
+			a.traffic.routes = make(map[string]*ObservedRoute)
+		}
+		route = &ObservedRoute{Method: method, Path: path, FirstSeen: now, Responses: make(map[string]Response)}
+		a.traffic.routes[key] = route
+	}
+	route.Requests++
+	route.LastSeen = now
+	route.Parameters = mergeObservedParameters(route.Parameters, parameters)
+	if body != nil {
+		if route.RequestBody == nil {
+			route.RequestBody = body
+		} else {
+			route.RequestBody.Schema = mergeObservedSchemas(route.RequestBody.Schema, body.Schema)
+		}
+	}
+	if existing, ok := route.Responses[status]; ok {
+		existing.Schema = mergeObservedSchemas(existing.Schema, responseDoc.Schema)
+		route.Responses[status] = existing
+	} else {
+		route.Responses[status] = responseDoc
+	}
+	a.traffic.mu.Unlock()
+
+	if config.AutoPublish {
+		a.PublishObservedRoute(method, path)
+	}
+}
+
+// routeTemplate is the method and OpenAPI path of a registered route
+type routeTemplate struct {
+	method   string
+	template string
+}
+
+// newRouteTemplates converts the paths of routes once, so matching live
+// requests needs neither a.mu nor path conversion. Callers hold a.mu.
+func newRouteTemplates(routes []RouteInfo) *[]routeTemplate {
+	templates := make([]routeTemplate, 0, len(routes))
+	for _, route := range routes {
+		templates = append(templates, routeTemplate{method: strings.ToUpper(route.Method), template: convertPathToOpenAPI(route.Path)})
+	}
+	return &templates
+}
+
+// documentedPath returns the OpenAPI path of the registered route that
+// documents a request to path, if any. It reads the templates built by
+// Generate, rebuilding them only after routes were added since.
+func (a *APIDocs) documentedPath(method, path string) (string, bool) {
+	templates := a.routeTemplates.Load()
+	if templates == nil {
+		a.mu.Lock()
+		templates = newRouteTemplates(a.currentRoutes())
+		a.routeTemplates.Store(templates)
+		a.mu.Unlock()
+	}
+	for _, route := range *templates {
+		if route.method != method {
+			continue
+		}
+		if _, ok := matchPathTemplate(route.template, path); ok {
+			return route.template, true
+		}
+	}
+	return "", false
+}
+
+// matchPathTemplate matches a request path against an OpenAPI path template,
+// returning the values of its parameters
+func matchPathTemplate(template, path string) (map[string]string, bool) {
+	templateParts := strings.Split(strings.Trim(template, "/"), "/")
+	pathParts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(templateParts) != len(pathParts) {
+		return nil, false
+	}
+	values := make(map[string]string)
+	for i, part := range templateParts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") && pathParts[i] != "" {
+			values[strings.Trim(part, "{}")] = pathParts[i]
+			continue
+		}
+		if part != pathParts[i] {
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+// templatePath turns a request path into a template: the first matching
+// configured template, or the path with numeric, UUID and opaque ID segments
+// replaced by parameters named after the preceding segment, e.g.
+// "/users/42/orders/7" into "/users/{userId}/orders/{orderId}"
+func templatePath(path string, templates []string) (string, map[string]string) {
+	for _, template := range templates {
+		template = convertPathToOpenAPI(template)
+		if values, ok := matchPathTemplate(template, path); ok {
+			return template, values
+		}
+	}
+
+	parts := strings.Split(path, "/")
+	values := make(map[string]string)
+	for i, part := range parts {
+		if !isIDSegment(part) {
+			continue
+		}
+		name := "id"
+		if i > 0 && parts[i-1] != "" && !strings.HasPrefix(parts[i-1], "{") {
+			name = camelCase(strings.TrimSuffix(parts[i-1], "s")) + "Id"
+		}
+		for suffix := 2; values[name] != ""; suffix++ {
+			name = strings.TrimRight(name, "0123456789") + strconv.Itoa(suffix)
+		}
+		values[name] = part
+		parts[i] = "{" + name + "}"
+	}
+	return strings.Join(parts, "/"), values
+}
+
+// isIDSegment reports whether a path segment looks like an identifier
+func isIDSegment(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	if _, err := strconv.ParseInt(segment, 10, 64); err == nil {
+		return true
+	}
+	return uuidSegment.MatchString(segment) || (opaqueSegment.MatchString(segment) && digitsInString.MatchString(segment))
+}
+
+// camelCase turns "line-items" into "lineItems"
+func camelCase(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	for i := 1; i < len(words); i++ {
+		words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+	}
+	if len(words) > 0 {
+		words[0] = strings.ToLower(words[0][:1]) + words[0][1:]
+	}
+	return strings.Join(words, "")
+}
+
+// observedParameter documents a path or query parameter from a sample value
+func observedParameter(name, in, value string, redact *redactor) Parameter {
+	parameter := Parameter{Name: name, In: in, Type: observedValueType(value), Required: in == "path", Example: value}
+	if example := redact.value(name, value); example != value {
+		parameter.Type = "string"
+		parameter.Example = example
+	}
+	return parameter
+}
+
+// observedValueType is the type of a textual value, e.g. "integer" for "42"
+func observedValueType(value string) string {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return "integer"
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return "number"
+	}
+	if value == "true" || value == "false" {
+		return "boolean"
+	}
+	return "string"
+}
+
+// mediaType strips the parameters of a Content-Type header
+func mediaType(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.TrimSpace(strings.ToLower(mediaType))
+	if mediaType == "" {
+		return "application/json"
+	}
+	return mediaType
+}
+
+// observedBody infers the schema and redacted example of a JSON body; other
+// and truncated bodies are documented as strings without an example
+func observedBody(contentType string, body []byte, truncated bool, redact *redactor) (interface{}, interface{}) {
+	var value interface{}
+	if truncated || !strings.Contains(contentType, "json") || json.Unmarshal(body, &value) != nil {
+		return map[string]interface{}{"type": "string"}, nil
+	}
+	return observedSchema(value), redact.value("", value)
+}
+
+// observedSchema infers the schema of a decoded JSON value
+func observedSchema(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties := make(map[string]interface{}, len(v))
+		for key, field := range v {
+			properties[key] = observedSchema(field)
+		}
+		return map[string]interface{}{"type": "object", "properties": properties}
+	case []interface{}:
+		items := map[string]interface{}{}
+		for _, item := range v {
+			items = mergeObservedSchemas(items, observedSchema(item)).(map[string]interface{})
+		}
+		return map[string]interface{}{"type": "array", "items": items}
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return map[string]interface{}{"type": "integer"}
+		}
+		return map[string]interface{}{"type": "number"}
+	case bool:
+		return map[string]interface{}{"type": "boolean"}
+	case string:
+		if _, err := time.Parse(time.RFC3339, v); err == nil {
+			return map[string]interface{}{"type": "string", "format": "date-time"}
+		}
+		return map[string]interface{}{"type": "string"}
+	}
+	return map[string]interface{}{"nullable": true}
+}
+
+// mergeObservedSchemas combines the schemas of two samples: object properties
+// are joined, integers widen to numbers and a null sample makes a schema nullable
+func mergeObservedSchemas(existing, observed interface{}) interface{} {
+	left, leftOK := existing.(map[string]interface{})
+	right, rightOK := observed.(map[string]interface{})
+	if !leftOK || len(left) == 0 {
+		return observed
+	}
+	if !rightOK || len(right) == 0 {
+		return existing
+	}
+	if left["type"] == nil || right["type"] == nil {
+		merged := copySchema(left)
+		if left["type"] == nil {
+			merged = copySchema(right)
+		}
+		merged["nullable"] = true
+		return merged
+	}
+	merged := copySchema(left)
+	switch {
+	case left["type"] == "object" && right["type"] == "object":
+		properties := make(map[string]interface{})
+		leftProperties, _ := left["properties"].(map[string]interface{})
+		rightProperties, _ := right["properties"].(map[string]interface{})
+		for name, schema := range leftProperties {
+			properties[name] = schema
+		}
+		for name, schema := range rightProperties {
+			properties[name] = mergeObservedSchemas(properties[name], schema)
+		}
+		merged["properties"] = properties
+	case left["type"] == "array" && right["type"] == "array":
+		merged["items"] = mergeObservedSchemas(left["items"], right["items"])
+	case left["type"] == "integer" && right["type"] == "number":
+		merged["type"] = "number"
+	}
+	if right["nullable"] == true {
+		merged["nullable"] = true
+	}
+	return merged
+}
+
+func copySchema(schema map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(schema)+1)
+	for key, value := range schema {
+		copied[key] = value
+	}
+	return copied
+}
+
+// mergeObservedParameters adds parameters not seen before; query parameters
+// missing from some requests stay optional
+func mergeObservedParameters(existing, observed []Parameter) []Parameter {
+	seen := make(map[string]bool, len(existing))
+	for _, parameter := range existing {
+		seen[parameter.In+" "+parameter.Name] = true
+	}
+	for _, parameter := range observed {
+		if !seen[parameter.In+" "+parameter.Name] {
+			existing = append(existing, parameter)
+		}
+	}
+	return existing
+}
+
+// ObservedRoutes returns the routes recorded by Middleware that await review,
+// ordered by path and method
+func (a *APIDocs) ObservedRoutes() []ObservedRoute {
+	a.traffic.mu.Lock()
+	defer a.traffic.mu.Unlock()
+	routes := make([]ObservedRoute, 0, len(a.traffic.routes))
+	for _, route := range a.traffic.routes {
+		observed := *route
+		observed.Parameters = append([]Parameter{}, route.Parameters...)
+		if route.RequestBody != nil {
+			body := *route.RequestBody
+			observed.RequestBody = &body
+		}
+		observed.Responses = make(map[string]Response, len(route.Responses))
+		for status, response := range route.Responses {
+			observed.Responses[status] = response
+		}
+		routes = append(routes, observed)
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// PublishObservedRoute documents an observed route and removes it from the
+// review queue. Later traffic of the route is not recorded.
+func (a *APIDocs) PublishObservedRoute(method, path string) error {
+	key := strings.ToUpper(method) + " " + path
+	a.traffic.mu.Lock()
+	route, ok := a.traffic.routes[key]
+	if ok {
+		delete(a.traffic.routes, key)
+		if a.traffic.published == nil {
+			a.traffic.published = make(map[string]bool)
+		}
+		a.traffic.published[key] = true
+	}
+	a.traffic.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no observed route %s", key)
+	}
+
+	responses := make(map[string]Response, len(route.Responses))
+	for status, response := range route.Responses {
+		responses[status] = response
+	}
+	a.AddRouteInfo(RouteInfo{
+		Method:      route.Method,
+		Path:        route.Path,
+		Parameters:  route.Parameters,
+		RequestBody: route.RequestBody,
+		Responses:   responses,
+		Extensions:  Extensions{"x-observed-requests": route.Requests},
+	})
+	return nil
+}
+
+// DiscardObservedRoute removes an observed route from the review queue and
+// stops recording it
+func (a *APIDocs) DiscardObservedRoute(method, path string) error {
+	key := strings.ToUpper(method) + " " + path
+	a.traffic.mu.Lock()
+	defer a.traffic.mu.Unlock()
+	if _, ok := a.traffic.routes[key]; !ok {
+		return fmt.Errorf("no observed route %s", key)
+	}
+	delete(a.traffic.routes, key)
+	if a.traffic.discarded == nil {
+		a.traffic.discarded = make(map[string]bool)
+	}
+	a.traffic.discarded[key] = true
+	return nil
+}
+
+// serveTraffic handles GET /traffic, the review queue, and POST
+// /traffic/publish and /traffic/discard with {"method": ..., "path": ...}.
+// Reviewing requires AuthConfig, since anyone could publish routes otherwise.
+func (a *APIDocs) serveTraffic(w http.ResponseWriter, r *http.Request, path string) {
+	if a.config.Traffic == nil || !a.config.Traffic.Enabled {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+
+	if path == "/traffic" {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := json.Marshal(a.ObservedRoutes())
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to encode observed routes: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(append(body, '\n'))
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if a.config.AuthConfig == nil || !a.config.AuthConfig.Enabled {
+		http.Error(w, "Reviewing observed routes requires docs authentication (AuthConfig)", http.StatusForbidden)
+		return
+	}
+	var target struct {
+		Method string `json:"method"`
+		Path   string `json:"path"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<10)).Decode(&target); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid route: %v", err), http.StatusBadRequest)
+		return
+	}
+	review := a.PublishObservedRoute
+	if path == "/traffic/discard" {
+		review = a.DiscardObservedRoute
+	}
+	if err := review(target.Method, target.Path); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,191 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTrafficMiddleware_RecordsReviewsAndPublishes(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", Traffic: &TrafficConfig{
+		Enabled:       true,
+		RedactFields:  []string{"pin"},
+		PathTemplates: []string{"/teams/:team/members"},
+	}, AuthConfig: &AuthConfig{Enabled: true, Type: "basic", Username: "u", Password: "p"}})
+	docs.AddRouteInfo(RouteInfo{Method: "GET", Path: "/health"})
+	app := docs.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id":7,"name":%q,"password":"hunter2","createdAt":"2024-01-01T00:00:00Z"}`, body["name"])
+	}))
+
+	send := func(method, target, body string) {
+		request := httptest.NewRequest(method, target, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusCreated || !strings.Contains(recorder.Body.String(), "hunter2") {
+			t.Fatalf("expected the response passed through unchanged, got %d %s", recorder.Code, recorder.Body.String())
+		}
+	}
+	send("POST", "/users/42/orders?dryRun=true&token=abc", `{"name":"Ada","pin":"1234"}`)
+	send("POST", "/users/43/orders", `{"name":"Bob","note":null}`)
+	send("POST", "/teams/core/members", `{}`)
+	send("GET", "/health", "")
+	send("GET", "/docs/openapi.json", "")
+
+	routes := docs.ObservedRoutes()
+	if len(routes) != 2 || routes[0].Path != "/teams/{team}/members" || routes[1].Path != "/users/{userId}/orders" {
+		t.Fatalf("expected two templated routes, got %+v", routes)
+	}
+	orders := routes[1]
+	if orders.Requests != 2 || len(orders.Parameters) != 3 {
+		t.Fatalf("expected two requests with path and query parameters, got %+v", orders)
+	}
+	for _, parameter := range orders.Parameters {
+		if parameter.Name == "userId" && (parameter.Type != "integer" || !parameter.Required) ||
+			parameter.Name == "token" && parameter.Example != "[REDACTED]" {
+			t.Fatalf("unexpected parameter %+v", parameter)
+		}
+	}
+	requestSchema := orders.RequestBody.Schema.(map[string]interface{})["properties"].(map[string]interface{})
+	if requestSchema["note"] == nil || requestSchema["name"].(map[string]interface{})["type"] != "string" {
+		t.Fatalf("expected request schemas merged across requests, got %+v", requestSchema)
+	}
+	if example := orders.RequestBody.Example.(map[string]interface{}); example["pin"] != "[REDACTED]" {
+		t.Fatalf("expected configured fields redacted, got %+v", example)
+	}
+	created := orders.Responses["201"]
+	if created.ContentType != "application/json" || created.Example.(map[string]interface{})["password"] != "[REDACTED]" ||
+		created.Schema.(map[string]interface{})["properties"].(map[string]interface{})["createdAt"].(map[string]interface{})["format"] != "date-time" {
+		t.Fatalf("expected a redacted JSON response, got %+v", created)
+	}
+
+	if err := docs.PublishObservedRoute("post", "/users/{userId}/orders"); err != nil {
+		t.Fatal(err)
+	}
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := docs.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	operation, _ := spec["paths"].(map[string]interface{})["/users/{userId}/orders"].(map[string]interface{})["post"].(map[string]interface{})
+	if operation == nil || operation["x-observed-requests"] != 2 {
+		t.Fatalf("expected the published route documented, got %+v", spec["paths"])
+	}
+	send("POST", "/users/44/orders", `{}`)
+	if routes := docs.ObservedRoutes(); len(routes) != 1 {
+		t.Fatalf("expected traffic of published routes no longer queued, got %+v", routes)
+	}
+
+	recorder := httptest.NewRecorder()
+	discard := httptest.NewRequest("POST", "/docs/traffic/discard", strings.NewReader(`{"method":"POST","path":"/teams/{team}/members"}`))
+	discard.SetBasicAuth("u", "p")
+	docs.ServeHTTP(recorder, discard)
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf("expected the route discarded, got %d %s", recorder.Code, recorder.Body.String())
+	}
+	send("POST", "/teams/core/members", `{}`)
+	recorder = httptest.NewRecorder()
+	queue := httptest.NewRequest("GET", "/docs/traffic", nil)
+	queue.SetBasicAuth("u", "p")
+	docs.ServeHTTP(recorder, queue)
+	if strings.TrimSpace(recorder.Body.String()) != "[]" {
+		t.Fatalf("expected discarded routes no longer recorded, got %s", recorder.Body.String())
+	}
+}
+
+func TestTrafficMiddleware_IgnoresErrorsAndCapsPublishedRoutes(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", Traffic: &TrafficConfig{Enabled: true, AutoPublish: true, MaxRoutes: 5}})
+	app := docs.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/wp-admin") {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	for i := 0; i < 50; i++ {
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", fmt.Sprintf("/wp-admin/probe-%02d.php", i), nil))
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("DELETE", "/things", nil))
+	}
+	for i := 0; i < 20; i++ {
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", fmt.Sprintf("/things-%d", i), nil))
+	}
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := docs.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := spec["paths"].(map[string]interface{})
+	if len(paths) != 5 || paths["/things-0"] == nil || paths["/things-5"] != nil {
+		t.Fatalf("expected only the first 5 successful routes published, got %v", paths)
+	}
+	if routes := docs.ObservedRoutes(); len(routes) != 0 {
+		t.Fatalf("expected nothing queued, got %+v", routes)
+	}
+
+	// without docs authentication anyone could publish, so reviewing is refused
+	recorder := httptest.NewRecorder()
+	docs.ServeHTTP(recorder, httptest.NewRequest("POST", "/docs/traffic/publish", strings.NewReader(`{"method":"GET","path":"/x"}`)))
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected publishing refused without AuthConfig, got %d", recorder.Code)
+	}
+}
+
+func TestTrafficMiddleware_PassesUpgradesAndHijacking(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", Traffic: &TrafficConfig{Enabled: true, AutoPublish: true}})
+	server := httptest.NewServer(docs.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			http.Error(w, "hijacking unsupported", http.StatusInternalServerError)
+			return
+		}
+		conn, buffered, err := hijacker.Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buffered.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		buffered.Flush()
+	})))
+	defer server.Close()
+
+	for _, upgrade := range []string{"websocket", ""} {
+		request, _ := http.NewRequest("GET", server.URL+"/socket", nil)
+		if upgrade != "" {
+			request.Header.Set("Connection", "Upgrade")
+			request.Header.Set("Upgrade", upgrade)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+		if response.StatusCode != http.StatusSwitchingProtocols {
+			t.Fatalf("expected the handler to hijack the connection (upgrade %q), got %d", upgrade, response.StatusCode)
+		}
+	}
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := docs.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paths, _ := spec["paths"].(map[string]interface{}); len(paths) != 0 {
+		t.Fatalf("expected hijacked connections not to be recorded, got %v", paths)
+	}
+}
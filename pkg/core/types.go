@@ -2,6 +2,7 @@ package core
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/idnexacloud/bytedocs-go/pkg/ai"
 )
@@ -24,16 +25,25 @@ type EndpointSection struct {
 
 // Endpoint represents a single API endpoint
 type Endpoint struct {
-	ID          string              `json:"id"`
-	Method      string              `json:"method"`
-	Path        string              `json:"path"`
-	Summary     string              `json:"summary"`
-	Description string              `json:"description"`
-	Parameters  []Parameter         `json:"parameters,omitempty"`
-	RequestBody *RequestBody        `json:"requestBody,omitempty"`
-	Responses   map[string]Response `json:"responses"`
-	Tags        []string            `json:"tags,omitempty"`
-	Handler     reflect.Value       `json:"-"` // Internal use
+	ID           string              `json:"id"`
+	Method       string              `json:"method"`
+	Path         string              `json:"path"`
+	Summary      string              `json:"summary"`
+	Description  string              `json:"description"`
+	Parameters   []Parameter         `json:"parameters,omitempty"`
+	RequestBody  *RequestBody        `json:"requestBody,omitempty"`
+	Responses    map[string]Response `json:"responses"`
+	Tags         []string            `json:"tags,omitempty"`
+	Deprecated   bool                `json:"deprecated,omitempty"`
+	ExternalDocs *ExternalDocs       `json:"externalDocs,omitempty"`
+	Presets      []Preset            `json:"presets,omitempty"`
+	SLA          time.Duration       `json:"sla,omitempty"`        // expected response time; nanoseconds in JSON
+	Pagination   string              `json:"pagination,omitempty"` // applied pagination style
+	GraphQL      bool                `json:"graphql,omitempty"`    // serves GraphQL, see GraphQLExtension
+	Extensions   Extensions          `json:"extensions,omitempty"` // vendor extensions emitted on the operation
+	Handler      reflect.Value       `json:"-"`                    // Internal use
+
+	generatedSummary string // summary inferred from method and path, for coverage
 }
 
 // Parameter represents endpoint parameter
@@ -44,22 +54,52 @@ type Parameter struct {
 	Required    bool        `json:"required"`
 	Description string      `json:"description"`
 	Example     interface{} `json:"example,omitempty"`
+	Component   string      `json:"-"`                  // components/parameters key of a shared parameter
+	Wildcard    bool        `json:"wildcard,omitempty"` // catch-all path parameter, exported as x-wildcard
 }
 
 // RequestBody represents request body schema
 type RequestBody struct {
+	ContentType  string        `json:"contentType"`
+	Schema       interface{}   `json:"schema"`
+	Example      interface{}   `json:"example,omitempty"`
+	Required     bool          `json:"required"`
+	Description  string        `json:"description,omitempty"`  // defaults to the schema description, e.g. the request struct's doc comment
+	Alternatives []BodyContent `json:"alternatives,omitempty"` // further accepted content types, each with its own schema
+	Examples     []Example     `json:"examples,omitempty"`     // further named examples of ContentType, e.g. captured from traffic
+}
+
+// BodyContent is a further content type a request body accepts, e.g.
+// application/xml next to application/json
+type BodyContent struct {
 	ContentType string      `json:"contentType"`
 	Schema      interface{} `json:"schema"`
 	Example     interface{} `json:"example,omitempty"`
-	Required    bool        `json:"required"`
 }
 
 // Response represents endpoint response
 type Response struct {
-	Description string      `json:"description"`
+	Description string                    `json:"description"`
+	Example     interface{}               `json:"example,omitempty"`
+	Schema      interface{}               `json:"schema,omitempty"`
+	ContentType string                    `json:"contentType,omitempty"`
+	Headers     map[string]ResponseHeader `json:"headers,omitempty"`   // e.g. the Location of redirects
+	Streaming   bool                      `json:"streaming,omitempty"` // body is a file or stream written as it is read
+	Examples    []Example                 `json:"examples,omitempty"`  // further named examples, e.g. captured from traffic
+}
+
+// Example is a further named example of a body
+type Example struct {
+	Name    string      `json:"name"`
+	Summary string      `json:"summary,omitempty"`
+	Value   interface{} `json:"value"`
+}
+
+// ResponseHeader documents a header set on a response
+type ResponseHeader struct {
+	Description string      `json:"description,omitempty"`
+	Type        string      `json:"type"`
 	Example     interface{} `json:"example,omitempty"`
-	Schema      interface{} `json:"schema,omitempty"`
-	ContentType string      `json:"contentType,omitempty"`
 }
 
 // Documentation represents complete API documentation
@@ -67,14 +107,28 @@ type Documentation struct {
 	Info      APIInfo           `json:"info"`
 	Endpoints []EndpointSection `json:"endpoints"`
 	Schemas   map[string]Schema `json:"schemas,omitempty"`
+	Guides    []Guide           `json:"guides,omitempty"`
+	Warnings  []AnalysisWarning `json:"warnings,omitempty"` // routes whose handlers could not be analyzed or were registered with conflicting definitions
+
+	SharedParameters map[string]Parameter `json:"-"` // shared parameter definitions by component key
+}
+
+// AnalysisWarning tells why the schemas of an endpoint could not be inferred,
+// e.g. a handler whose source failed to parse or a closure handler, or that
+// its route was registered more than once with conflicting definitions
+type AnalysisWarning struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
 }
 
 // Schema represents data structure schema
 type Schema struct {
-	Type       string              `json:"type"`
-	Properties map[string]Property `json:"properties,omitempty"`
-	Required   []string            `json:"required,omitempty"`
-	Example    interface{}         `json:"example,omitempty"`
+	Type        string              `json:"type"`
+	Description string              `json:"description,omitempty"`
+	Properties  map[string]Property `json:"properties,omitempty"`
+	Required    []string            `json:"required,omitempty"`
+	Example     interface{}         `json:"example,omitempty"`
 }
 
 // Property represents schema property
@@ -87,19 +141,66 @@ type Property struct {
 
 // Config represents apidocs configuration
 type Config struct {
-	Title        string           `json:"title"`
-	Version      string           `json:"version"`
-	Description  string           `json:"description"`
-	BaseURL      string           `json:"baseUrl"`  // Backward compatibility - single URL
-	BaseURLs     []BaseURLOption  `json:"baseUrls"` // New field - multiple URLs
-	DocsPath     string           `json:"docsPath"`
+	Title       string          `json:"title"`
+	Version     string          `json:"version"`
+	Description string          `json:"description"`
+	BaseURL     string          `json:"baseUrl"`  // Backward compatibility - single URL
+	BaseURLs    []BaseURLOption `json:"baseUrls"` // New field - multiple URLs
+	DocsPath    string          `json:"docsPath"`
+	RootPrefix  string          `json:"rootPrefix,omitempty"` // Public path prefix added by a reverse proxy, e.g. "/myapp"
+
+	TrustedProxies     []string `json:"-"` // IPs or CIDRs of reverse proxies whose Forwarded and X-Forwarded-* headers are honored; ignored from anyone else
+	ServersFromRequest bool     `json:"-"` // Derive the spec servers from each request instead of BaseURL/BaseURLs, which must then be empty
+
+	SeparatePort string           `json:"-"` // Serve the docs on their own listener, e.g. "127.0.0.1:9090", instead of the app router
 	AutoDetect   bool             `json:"autoDetect"`
 	IncludeTypes []reflect.Type   `json:"-"`
-	ExcludePaths []string         `json:"excludePaths"`
+	ExcludePaths []string         `json:"excludePaths"` // Path segments or prefixes never documented; nil hides DefaultExcludePaths from detected routes
 	Middlewares  []MiddlewareFunc `json:"-"`
 	AuthConfig   *AuthConfig      `json:"authConfig,omitempty"`
 	UIConfig     *UIConfig        `json:"uiConfig,omitempty"`
 	AIConfig     *ai.AIConfig     `json:"aiConfig,omitempty"`
+
+	SkipMethods     []string `json:"skipMethods,omitempty"`     // Methods never documented, e.g. "HEAD"
+	SkipAutoOPTIONS bool     `json:"skipAutoOptions,omitempty"` // Hide HEAD/OPTIONS routes that sit next to other methods on the same path
+	SpecKeyOrder    string   `json:"specKeyOrder,omitempty"`    // "alphabetical" (default) or "openapi" for conventional OpenAPI key order
+	SpecValidation  string   `json:"specValidation,omitempty"`  // "strict" fails exports that break the OpenAPI 3.0 schema, "annotate" lists the violations under x-spec-violations
+
+	GroupingStrategy string          `json:"-"` // Section of each endpoint: "last" (default), "prefix", "resource", "tag" or "file"
+	GroupingFunc     GroupingFunc    `json:"-"` // Custom grouping; an empty result falls back to GroupingStrategy
+	OperationID      OperationIDFunc `json:"-"` // Custom operationId; an empty result keeps the generated "<method>-<path>" ID
+
+	AnalysisCacheDir string `json:"-"` // Directory for the persistent analyzer cache; empty disables it
+	AnalysisWorkers  int    `json:"-"` // Files parsed and analyzed concurrently; 0 uses GOMAXPROCS
+	Debug            bool   `json:"-"` // Trace the handler analysis, served at <docs path>/debug/analysis; bypasses the analyzer cache
+	HealthDetails    bool   `json:"-"` // Include the AI client and federated services, with their errors, in <docs path>/healthz, which skips the docs login
+
+	ErrorResponseType string   `json:"-"` // Shared error envelope struct, e.g. "ErrorResponse", documented for every error response
+	ErrorHelpers      []string `json:"-"` // Error helper functions as "name:statusArgIndex", e.g. "writeError:1"
+	ResponseEnvelopes []string `json:"-"` // Envelope structs as "Type.PayloadField", e.g. "APIResponse.Data", whose payload is documented with its actual type
+	UnwrapEnvelopes   bool     `json:"-"` // Document only the payload of enveloped responses instead of the whole envelope
+	FakeExamples      bool     `json:"-"` // Replace placeholder examples ("string", 0) with realistic values derived from field names and formats
+	ExampleLocale     string   `json:"-"` // Market of FakeExamples values, e.g. "de-DE" or "id-ID"; "en-US" by default, more with RegisterExampleLocale
+
+	EventServers []EventServer `json:"-"` // Message brokers listed in the AsyncAPI export, e.g. Kafka or NATS
+
+	Pagination       *Pagination       `json:"-"` // Paging convention documented on every list endpoint (GET without a trailing path parameter)
+	SharedParameters []SharedParameter `json:"-"` // Parameters such as X-Request-ID attached to all or path-matched endpoints via $ref
+	Extensions       Extensions        `json:"-"` // Vendor extensions of the root document, e.g. x-logo
+	DefaultResponses map[int]Response  `json:"-"` // Responses such as 401, 429 and 500 added to every endpoint that does not document the status itself
+
+	Guides    []Guide `json:"-"` // Markdown pages listed above the endpoints
+	GuidesDir string  `json:"-"` // Directory of *.md guides, listed after Guides in file name order
+
+	SecurityHeaders *SecurityHeadersConfig `json:"-"` // CSP and hardening headers of docs responses; nil uses the defaults
+	AllowRawMarkup  bool                   `json:"-"` // Render summaries and descriptions unsanitized in the UI; only for trusted sources
+
+	Refresh       *RefreshConfig      `json:"-"` // Token or webhook secret of POST <docs path>/refresh; nil disables the endpoint
+	Notifications *NotificationConfig `json:"-"` // Slack, Teams and custom notifications of added, removed and breaking endpoint changes
+	Analytics     *AnalyticsConfig    `json:"-"` // In-memory counts of viewed and tried endpoints and searches, served at <docs path>/analytics
+	Federation    *FederationConfig   `json:"-"` // OpenAPI documents of sibling services merged in as one section each
+	Traffic       *TrafficConfig      `json:"-"` // Routes documented from live traffic by Middleware, reviewed at <docs path>/traffic
+	Redaction     *RedactionConfig    `json:"-"` // Removes emails, tokens, card numbers and other secrets from examples, captured traffic and the AI context
 }
 
 // AuthConfig represents authentication configuration
@@ -118,12 +219,25 @@ type AuthConfig struct {
 	IPBanMaxAttempts  int      `json:"ipBanMaxAttempts"`  // Max failed attempts before ban (default: 5)
 	IPBanDuration     int      `json:"ipBanDuration"`     // Ban duration in minutes (default: 60)
 	AdminWhitelistIPs []string `json:"adminWhitelistIPs"` // IPs that cannot be banned (default: ["127.0.0.1"])
+
+	// Session cookie hardening
+	CookieSameSite string `json:"cookieSameSite,omitempty"` // "lax" (default), "strict" or "none" (implies Secure)
+	CookieSecure   bool   `json:"cookieSecure,omitempty"`   // Always mark cookies Secure; otherwise only on HTTPS requests
+	LogoutEnabled  bool   `json:"logoutEnabled,omitempty"`  // Serve <docsPath>/logout to end the session
+	CookieScope    string `json:"cookieScope,omitempty"`    // Appended to the cookie names, so several docs on one host keep separate sessions
+
+	// TOTP second factor for session auth
+	TOTPEnabled  bool                                `json:"totpEnabled,omitempty"`
+	TOTPIssuer   string                              `json:"totpIssuer,omitempty"` // Name shown in authenticator apps (default: Realm)
+	TOTPUsers    map[string]string                   `json:"-"`                    // Username -> base32 secret; an empty secret enrolls on the next login
+	OnTOTPEnroll func(username, secret string) error `json:"-"`                    // Persists secrets created during enrollment
 }
 
 // BaseURLOption represents a selectable base URL option
 type BaseURLOption struct {
-	Name string `json:"name"` // Display name like "Production", "Staging"
-	URL  string `json:"url"`  // The actual URL
+	Name      string                    `json:"name"`                // Display name like "Production", "Staging"
+	URL       string                    `json:"url"`                 // The actual URL, may contain "{name}" variables
+	Variables map[string]ServerVariable `json:"variables,omitempty"` // Values of the URL's variables, e.g. "region"
 }
 
 // UIConfig represents UI customization options
@@ -136,6 +250,25 @@ type UIConfig struct {
 	Favicon     string `json:"favicon"`
 	Title       string `json:"title"`
 	Subtitle    string `json:"subtitle"`
+
+	FlatNavigation bool `json:"flatNavigation,omitempty"` // List endpoints flat per section instead of nesting them by resource path
+
+	// Try It / scenario execution limits. Zero uses the defaults, a negative
+	// MaxConcurrentRequests disables limiting.
+	MaxConcurrentRequests int `json:"maxConcurrentRequests,omitempty"` // Outbound requests running at once (default: 10)
+	MaxQueuedRequests     int `json:"maxQueuedRequests,omitempty"`     // Requests waiting for a slot before 503 (default: 50)
+	QueueTimeout          int `json:"queueTimeout,omitempty"`          // Seconds a request may wait for a slot (default: 30)
+
+	// Try It / scenario body sizes. Response bodies over MaxResponseBytes are
+	// cut; they and binary bodies are kept for download for 10 minutes.
+	MaxRequestBytes  int64 `json:"maxRequestBytes,omitempty"`  // Test request payload accepted (default: 10 MiB)
+	MaxResponseBytes int64 `json:"maxResponseBytes,omitempty"` // Response body returned inline (default: 1 MiB)
+	MaxDownloadBytes int64 `json:"maxDownloadBytes,omitempty"` // Response body kept for download (default: 50 MiB, negative disables downloads)
+	MaxKeptBytes     int64 `json:"maxKeptBytes,omitempty"`     // Kept downloads, and kept uploads, on disk at once; the oldest are removed first (default: 512 MiB)
+
+	// TestClient configures TLS, proxy and redirects of those requests. It
+	// holds proxy credentials and key paths, so it is never sent to the page.
+	TestClient *TestClientConfig `json:"-"`
 }
 
 // MiddlewareFunc represents middleware function
@@ -143,20 +276,49 @@ type MiddlewareFunc func(endpoint *Endpoint) *Endpoint
 
 // RouteInfo represents route information from framework
 type RouteInfo struct {
-	Method      string
-	Path        string
-	Handler     interface{}
-	Middlewares []interface{}
-	Summary     string              `json:"summary,omitempty"`
-	Description string              `json:"description,omitempty"`
-	Parameters  []Parameter         `json:"parameters,omitempty"`
-	RequestBody *RequestBody        `json:"requestBody,omitempty"`
-	Responses   map[string]Response `json:"responses,omitempty"`
+	Method       string
+	Path         string
+	Handler      interface{}
+	Middlewares  []interface{}
+	Summary      string              `json:"summary,omitempty"`
+	Description  string              `json:"description,omitempty"`
+	Parameters   []Parameter         `json:"parameters,omitempty"`
+	RequestBody  *RequestBody        `json:"requestBody,omitempty"`
+	Responses    map[string]Response `json:"responses,omitempty"`
+	Deprecated   bool                `json:"deprecated,omitempty"`
+	Section      string              `json:"section,omitempty"`      // Section ID from @Section or WithSection; overrides Config.GroupingStrategy
+	ExternalDocs *ExternalDocs       `json:"externalDocs,omitempty"` // from @Docs or WithExternalDocs
+	Presets      []Preset            `json:"presets,omitempty"`      // from @Preset or WithPreset
+	SLA          time.Duration       `json:"sla,omitempty"`          // from @SLA or WithSLA
+	Pagination   string              `json:"pagination,omitempty"`   // from @Paginate or WithPagination; "none" opts out of Config.Pagination
+	Accepts      []string            `json:"accepts,omitempty"`      // from @Accept or WithAccepts; content types sharing the request body schema
+	Extensions   Extensions          `json:"extensions,omitempty"`   // from @x-<name> or WithExtension; vendor extensions of the operation, e.g. x-rate-limit
+	ResponseDocs map[string]string   `json:"responseDocs,omitempty"` // from @Response or WithResponseDescription; descriptions by status code replacing the generated ones
+	Warnings     []string            `json:"warnings,omitempty"`     // why the parser could not analyze the handler, see Documentation.Warnings
+	Trace        []string            `json:"trace,omitempty"`        // analysis steps of the handler when Config.Debug is set
+
+	detected bool // added by the route detector; Refresh replaces these routes
+}
+
+// Preset is a named set of try-it values, e.g. "create admin user"
+type Preset struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Params      map[string]string `json:"params,omitempty"` // path, query and header values by parameter name
+	Body        interface{}       `json:"body,omitempty"`
+}
+
+// ExternalDocs links an endpoint to a guide outside the generated docs
+type ExternalDocs struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
 }
 
 // Type aliases for backward compatibility
 type AIConfig = ai.AIConfig
 type AIFeatures = ai.AIFeatures
+type AIFallback = ai.AIFallback
 type ChatRequest = ai.ChatRequest
 type ChatResponse = ai.ChatResponse
+type ChatSource = ai.ChatSource
 type LLMClient = ai.Client
@@ -0,0 +1,465 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	tsIdentifier   = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	tsNamePart     = regexp.MustCompile(`[A-Za-z0-9]+`)
+	tsPathParamRef = regexp.MustCompile(`\{([^{}]+)\}`)
+)
+
+// tsOperation is one endpoint of the generated client
+type tsOperation struct {
+	name        string // client method, e.g. "getUsersId"
+	typeName    string // prefix of its types, e.g. "GetUsersId"
+	endpoint    Endpoint
+	pathParams  []Parameter
+	queryParams []Parameter
+	bodyType    string
+	contentType string
+	response    string
+	responseRaw bool // the success response is not JSON
+}
+
+// GetTypeScript returns TypeScript interfaces for the documented schemas,
+// request and response types per endpoint, and a typed fetch client
+func (a *APIDocs) GetTypeScript() (string, error) {
+	if err := a.build(); err != nil {
+		return "", err
+	}
+	documentation := a.GetDocumentation()
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "// Generated by ByteDocs from %s %s. Do not edit.\n\n",
+		strings.ReplaceAll(documentation.Info.Title, "\n", " "), strings.ReplaceAll(documentation.Info.Version, "\n", " "))
+
+	names := make([]string, 0, len(documentation.Schemas))
+	for name := range documentation.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeTSDeclaration(&out, tsTypeName(name), tsSchema(documentation.Schemas[name]))
+	}
+
+	operations := make([]*tsOperation, 0)
+	usedNames := make(map[string]bool)
+	for _, section := range documentation.Endpoints {
+		for _, endpoint := range section.Endpoints {
+			operation := newTSOperation(endpoint, usedNames)
+			operations = append(operations, operation)
+
+			if len(operation.pathParams) > 0 {
+				writeTSParams(&out, operation.typeName+"PathParams", operation.pathParams)
+			}
+			if len(operation.queryParams) > 0 {
+				writeTSParams(&out, operation.typeName+"Query", operation.queryParams)
+			}
+			if endpoint.RequestBody != nil && operation.bodyType == operation.typeName+"Request" {
+				writeTSDeclaration(&out, operation.bodyType, tsSchema(endpoint.RequestBody.Schema))
+			}
+			if operation.response == operation.typeName+"Response" {
+				writeTSDeclaration(&out, operation.response, tsSchema(successResponse(endpoint).Schema))
+			}
+		}
+	}
+
+	writeTSClient(&out, operations)
+	return out.String(), nil
+}
+
+// newTSOperation names an endpoint's method and types and picks its body and response types
+func newTSOperation(endpoint Endpoint, usedNames map[string]bool) *tsOperation {
+	typeName := tsTypeName(endpoint.ID)
+	if typeName == "" {
+		typeName = tsTypeName(endpoint.Method + " " + endpoint.Path)
+	}
+	for base, suffix := typeName, 2; usedNames[typeName]; suffix++ {
+		typeName = fmt.Sprintf("%s%d", base, suffix)
+	}
+	usedNames[typeName] = true
+
+	operation := &tsOperation{
+		name:     strings.ToLower(typeName[:1]) + typeName[1:],
+		typeName: typeName,
+		endpoint: endpoint,
+		response: "void",
+	}
+	for _, param := range endpoint.Parameters {
+		switch param.In {
+		case "path":
+			operation.pathParams = append(operation.pathParams, param)
+		case "query":
+			operation.queryParams = append(operation.queryParams, param)
+		}
+	}
+
+	if body := endpoint.RequestBody; body != nil {
+		operation.contentType = body.ContentType
+		switch body.ContentType {
+		case "multipart/form-data":
+			operation.bodyType = "FormData"
+		case "application/x-www-form-urlencoded":
+			operation.bodyType = "Record<string, string>"
+		default:
+			operation.contentType = "application/json"
+			operation.bodyType = typeName + "Request"
+		}
+	}
+
+	if response := successResponse(endpoint); response != nil {
+		if response.ContentType != "" && !strings.Contains(response.ContentType, "json") {
+			operation.response = "string"
+			operation.responseRaw = true
+		} else if response.Schema != nil {
+			operation.response = typeName + "Response"
+		}
+	}
+	return operation
+}
+
+// successResponse returns the lowest documented 2xx response, or nil
+func successResponse(endpoint Endpoint) *Response {
+	statuses := make([]string, 0)
+	for status := range endpoint.Responses {
+		if strings.HasPrefix(status, "2") {
+			statuses = append(statuses, status)
+		}
+	}
+	if len(statuses) == 0 {
+		return nil
+	}
+	sort.Strings(statuses)
+	response := endpoint.Responses[statuses[0]]
+	return &response
+}
+
+// tsSchema decodes a schema into plain JSON maps, including schemas built from typed Go values
+func tsSchema(schema interface{}) map[string]interface{} {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil
+	}
+	return decoded
+}
+
+// writeTSDeclaration writes an interface for object schemas and a type alias otherwise
+func writeTSDeclaration(out *strings.Builder, name string, schema map[string]interface{}) {
+	if description, _ := schema["description"].(string); description != "" {
+		fmt.Fprintf(out, "/** %s */\n", tsComment(description))
+	}
+	if schema != nil && schema["properties"] != nil && schema["$ref"] == nil {
+		fmt.Fprintf(out, "export interface %s %s\n\n", name, tsObjectType(schema, 0))
+		return
+	}
+	fmt.Fprintf(out, "export type %s = %s;\n\n", name, tsType(schema, 0))
+}
+
+// writeTSParams writes an interface of path or query parameters
+func writeTSParams(out *strings.Builder, name string, params []Parameter) {
+	fmt.Fprintf(out, "export interface %s {\n", name)
+	for _, param := range params {
+		if param.Description != "" {
+			fmt.Fprintf(out, "  /** %s */\n", tsComment(param.Description))
+		}
+		optional := "?"
+		if param.Required || param.In == "path" {
+			optional = ""
+		}
+		fmt.Fprintf(out, "  %s%s: %s;\n", tsPropertyName(param.Name), optional, tsParamType(param.Type))
+	}
+	out.WriteString("}\n\n")
+}
+
+// tsParamType returns the TypeScript type of a parameter's Go or OpenAPI type
+func tsParamType(paramType string) string {
+	switch strings.ToLower(paramType) {
+	case "integer", "number":
+		return "number"
+	}
+	switch normalizeOpenAPIType(paramType) {
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		return "string[]"
+	default:
+		return "string"
+	}
+}
+
+// tsType returns the TypeScript type of a decoded schema
+func tsType(schema map[string]interface{}, depth int) string {
+	if schema == nil {
+		return "unknown"
+	}
+
+	var result string
+	switch {
+	case schema["$ref"] != nil:
+		ref, _ := schema["$ref"].(string)
+		result = tsTypeName(ref[strings.LastIndex(ref, "/")+1:])
+	case schema["oneOf"] != nil || schema["anyOf"] != nil:
+		alternatives, _ := schema["oneOf"].([]interface{})
+		if alternatives == nil {
+			alternatives, _ = schema["anyOf"].([]interface{})
+		}
+		result = tsJoinTypes(alternatives, " | ", depth)
+	case schema["allOf"] != nil:
+		parts, _ := schema["allOf"].([]interface{})
+		result = tsJoinTypes(parts, " & ", depth)
+	case schema["enum"] != nil:
+		values, _ := schema["enum"].([]interface{})
+		literals := make([]string, 0, len(values))
+		for _, value := range values {
+			literal, _ := json.Marshal(value)
+			literals = append(literals, string(literal))
+		}
+		result = strings.Join(literals, " | ")
+	default:
+		schemaType, _ := schema["type"].(string)
+		switch schemaType {
+		case "string":
+			result = "string"
+		case "integer", "number":
+			result = "number"
+		case "boolean":
+			result = "boolean"
+		case "array":
+			items, _ := schema["items"].(map[string]interface{})
+			itemType := tsType(items, depth)
+			if strings.ContainsAny(itemType, "|&") {
+				itemType = "(" + itemType + ")"
+			}
+			result = itemType + "[]"
+		case "object", "":
+			if schema["properties"] != nil {
+				result = tsObjectType(schema, depth)
+			} else if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+				result = "Record<string, " + tsType(additional, depth) + ">"
+			} else if schemaType == "object" {
+				result = "Record<string, unknown>"
+			} else {
+				result = "unknown"
+			}
+		default:
+			result = "unknown"
+		}
+	}
+
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		result += " | null"
+	}
+	return result
+}
+
+// tsJoinTypes joins the types of schemas with separator
+func tsJoinTypes(schemas []interface{}, separator string, depth int) string {
+	types := make([]string, 0, len(schemas))
+	for _, schema := range schemas {
+		schemaMap, _ := schema.(map[string]interface{})
+		types = append(types, tsType(schemaMap, depth))
+	}
+	if len(types) == 0 {
+		return "unknown"
+	}
+	return strings.Join(types, separator)
+}
+
+// tsObjectType returns an inline object type with one property per line
+func tsObjectType(schema map[string]interface{}, depth int) string {
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := make(map[string]bool)
+	if list, ok := schema["required"].([]interface{}); ok {
+		for _, name := range list {
+			if text, ok := name.(string); ok {
+				required[text] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	indent := strings.Repeat("  ", depth+1)
+	var out strings.Builder
+	out.WriteString("{\n")
+	for _, name := range names {
+		property, _ := properties[name].(map[string]interface{})
+		if description, _ := property["description"].(string); description != "" {
+			fmt.Fprintf(&out, "%s/** %s */\n", indent, tsComment(description))
+		}
+		optional := "?"
+		if required[name] {
+			optional = ""
+		}
+		modifier := ""
+		if readOnly, _ := property["readOnly"].(bool); readOnly {
+			modifier = "readonly "
+		}
+		fmt.Fprintf(&out, "%s%s%s%s: %s;\n", indent, modifier, tsPropertyName(name), optional, tsType(property, depth+1))
+	}
+	out.WriteString(strings.Repeat("  ", depth) + "}")
+	return out.String()
+}
+
+// tsTypeName turns an operation ID or schema name into a PascalCase identifier
+func tsTypeName(name string) string {
+	var out strings.Builder
+	for _, part := range tsNamePart.FindAllString(name, -1) {
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		out.WriteString(string(runes))
+	}
+	result := out.String()
+	if result != "" && unicode.IsDigit(rune(result[0])) {
+		result = "T" + result
+	}
+	return result
+}
+
+// tsPropertyName quotes property names that are not identifiers
+func tsPropertyName(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	quoted, _ := json.Marshal(name)
+	return string(quoted)
+}
+
+// tsComment keeps descriptions from closing their comment
+func tsComment(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "*/", "*\\/"), "\n", " ")
+}
+
+// writeTSClient writes createClient with one method per endpoint
+func writeTSClient(out *strings.Builder, operations []*tsOperation) {
+	out.WriteString(`export interface ClientOptions {
+  baseUrl: string;
+  headers?: Record<string, string>;
+  fetch?: typeof fetch;
+}
+
+export class ApiError extends Error {
+  status: number;
+  body: string;
+
+  constructor(status: number, body: string) {
+    super(` + "`Request failed with status ${status}`" + `);
+    this.status = status;
+    this.body = body;
+  }
+}
+
+export function createClient(options: ClientOptions) {
+  const doFetch = options.fetch ?? fetch;
+
+  async function request(method: string, path: string, query?: object, body?: unknown, contentType?: string): Promise<Response> {
+    const url = new URL(options.baseUrl.replace(/\/$/, "") + path);
+    for (const [key, value] of Object.entries(query ?? {})) {
+      if (value === undefined || value === null) continue;
+      for (const item of Array.isArray(value) ? value : [value]) url.searchParams.append(key, String(item));
+    }
+    const headers: Record<string, string> = { ...options.headers };
+    let payload: BodyInit | undefined;
+    if (body instanceof FormData) {
+      payload = body;
+    } else if (contentType === "application/x-www-form-urlencoded") {
+      payload = new URLSearchParams(body as Record<string, string>);
+    } else if (body !== undefined) {
+      headers["Content-Type"] = contentType ?? "application/json";
+      payload = JSON.stringify(body);
+    }
+    const response = await doFetch(url.toString(), { method, headers, body: payload });
+    if (!response.ok) throw new ApiError(response.status, await response.text());
+    return response;
+  }
+
+  return {
+`)
+	for _, operation := range operations {
+		args := make([]string, 0, 3)
+		path := convertPathToOpenAPI(operation.endpoint.Path)
+		if len(operation.pathParams) > 0 {
+			args = append(args, "params: "+operation.typeName+"PathParams")
+			path = tsPathParamRef.ReplaceAllStringFunc(path, func(match string) string {
+				name := match[1 : len(match)-1]
+				accessor := "params." + name
+				if !tsIdentifier.MatchString(name) {
+					accessor = "params[" + tsPropertyName(name) + "]"
+				}
+				return "${encodeURIComponent(String(" + accessor + "))}"
+			})
+		}
+		if operation.bodyType != "" {
+			args = append(args, "body: "+operation.bodyType)
+		}
+		query := "undefined"
+		if len(operation.queryParams) > 0 {
+			args = append(args, "query?: "+operation.typeName+"Query")
+			query = "query"
+		}
+		body := "undefined"
+		if operation.bodyType != "" {
+			body = "body"
+		}
+		contentType := "undefined"
+		if operation.contentType != "" {
+			contentType = strconv.Quote(operation.contentType)
+		}
+
+		if summary := operation.endpoint.Summary; summary != "" {
+			fmt.Fprintf(out, "    /** %s */\n", tsComment(summary))
+		}
+		fmt.Fprintf(out, "    async %s(%s): Promise<%s> {\n", operation.name, strings.Join(args, ", "), operation.response)
+		call := fmt.Sprintf("request(%q, `%s`, %s, %s, %s)", strings.ToUpper(operation.endpoint.Method), strings.ReplaceAll(path, "`", "\\`"), query, body, contentType)
+		switch {
+		case operation.response == "void":
+			fmt.Fprintf(out, "      await %s;\n", call)
+		case operation.responseRaw:
+			fmt.Fprintf(out, "      return (await %s).text();\n", call)
+		default:
+			fmt.Fprintf(out, "      return (await %s).json();\n", call)
+		}
+		out.WriteString("    },\n")
+	}
+	out.WriteString("  };\n}\n")
+}
+
+// serveTypeScript serves the generated TypeScript types and client
+func (a *APIDocs) serveTypeScript(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	source, err := a.GetTypeScript()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to generate TypeScript: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/typescript; charset=utf-8")
+	a.writeCacheable(w, r, []byte(source))
+}
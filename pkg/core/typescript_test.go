@@ -0,0 +1,46 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTypeScript_GeneratesTypesAndClient(t *testing.T) {
+	docs := New(&Config{Title: "Test", Version: "1.0.0", DocsPath: "/docs", AuthConfig: &AuthConfig{Enabled: true, Type: "basic", Username: "u", Password: "p"}})
+	docs.AddRoute("POST", "/users/:id", nil,
+		WithRequest(struct {
+			Name  string   `json:"name" binding:"required"`
+			Tags  []string `json:"tags"`
+			Admin bool     `json:"is-admin"`
+		}{}),
+		WithResponse(http.StatusCreated, struct {
+			ID int `json:"id"`
+		}{}))
+	docs.AddRoute("GET", "/users", nil, WithParam("page", "query", "integer", false, "Page number"))
+	docs.AddRoute("DELETE", "/users/:id", nil)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/docs/types.ts", nil)
+	req.SetBasicAuth("u", "p")
+	docs.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/typescript") {
+		t.Fatalf("expected TypeScript, got %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	source := rec.Body.String()
+	for _, want := range []string{
+		"name: string;",
+		"tags?: string[];",
+		`"is-admin"?: boolean;`,
+		"id?: number;",
+		"page?: number;",
+		"export function createClient(options: ClientOptions)",
+		"${encodeURIComponent(String(params.id))}",
+		"Promise<void>",
+	} {
+		if !strings.Contains(source, want) {
+			t.Fatalf("expected %q in the generated TypeScript, got:\n%s", want, source)
+		}
+	}
+}
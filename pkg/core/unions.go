@@ -0,0 +1,190 @@
+package core
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// UnionVariant is one concrete type of a discriminated union
+type UnionVariant struct {
+	Value   string      // discriminator value selecting the variant
+	Name    string      // type name, the components/schemas key of the variant
+	Schema  interface{} // object schema of the variant
+	Example interface{}
+}
+
+// union is a discriminated union registered with RegisterUnion
+type union struct {
+	discriminator string
+	values        []string
+	variants      map[string]interface{}
+}
+
+var (
+	unions      = make(map[reflect.Type]union)
+	unionsMutex sync.RWMutex
+)
+
+// RegisterUnion documents fields of the interface type of iface, given as a nil
+// pointer such as (*Payment)(nil), as one of variants, keyed by the value of
+// the discriminator property that tells them apart. Variants are values of the
+// concrete types, used like the values passed to SchemaFromValue. Registering
+// nil variants removes the union.
+func RegisterUnion(iface interface{}, discriminator string, variants map[string]interface{}) {
+	t := reflect.TypeOf(iface)
+	if t == nil {
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	unionsMutex.Lock()
+	defer unionsMutex.Unlock()
+	if variants == nil {
+		delete(unions, t)
+		return
+	}
+	values := make([]string, 0, len(variants))
+	for value := range variants {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	unions[t] = union{discriminator: discriminator, values: values, variants: variants}
+}
+
+func lookupUnion(t reflect.Type) (union, bool) {
+	unionsMutex.RLock()
+	defer unionsMutex.RUnlock()
+	registered, ok := unions[t]
+	return registered, ok
+}
+
+// unionSchemaFromReflect builds the schema of a registered union
+func unionSchemaFromReflect(registered union, visited map[reflect.Type]bool) (map[string]interface{}, interface{}) {
+	variants := make([]UnionVariant, 0, len(registered.values))
+	for _, value := range registered.values {
+		variant := reflect.ValueOf(registered.variants[value])
+		if !variant.IsValid() {
+			continue
+		}
+		schema, example := schemaFromReflect(variant, visited)
+		t := variant.Type()
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		variants = append(variants, UnionVariant{Value: value, Name: t.Name(), Schema: schema, Example: example})
+	}
+	return UnionSchema(registered.discriminator, variants)
+}
+
+// UnionSchema builds a oneOf schema with a discriminator mapping from the
+// variants of a union. Each variant gets its name as title, the discriminator
+// as a required property fixed to its value, and its example; the first
+// variant provides the example of the union.
+func UnionSchema(discriminator string, variants []UnionVariant) (map[string]interface{}, interface{}) {
+	alternatives := make([]interface{}, 0, len(variants))
+	mapping := make(map[string]interface{}, len(variants))
+	var example interface{}
+	for _, variant := range variants {
+		schema := map[string]interface{}{"type": "object"}
+		if source, ok := variant.Schema.(map[string]interface{}); ok {
+			for key, value := range source {
+				schema[key] = value
+			}
+		}
+		properties := map[string]interface{}{}
+		if source, ok := schema["properties"].(map[string]interface{}); ok {
+			for key, value := range source {
+				properties[key] = value
+			}
+		}
+		properties[discriminator] = map[string]interface{}{"type": "string", "enum": []interface{}{variant.Value}}
+		schema["properties"] = properties
+
+		required := []string{discriminator}
+		for _, name := range requiredNames(schema["required"]) {
+			if name != discriminator {
+				required = append(required, name)
+			}
+		}
+		schema["required"] = required
+
+		variantExample := map[string]interface{}{}
+		if source, ok := variant.Example.(map[string]interface{}); ok {
+			for key, value := range source {
+				variantExample[key] = value
+			}
+		}
+		variantExample[discriminator] = variant.Value
+		schema["example"] = variantExample
+
+		if variant.Name != "" {
+			schema["title"] = variant.Name
+			mapping[variant.Value] = "#/components/schemas/" + variant.Name
+		}
+		alternatives = append(alternatives, schema)
+		if example == nil {
+			example = variantExample
+		}
+	}
+
+	schema := map[string]interface{}{
+		"oneOf":         alternatives,
+		"discriminator": map[string]interface{}{"propertyName": discriminator, "mapping": mapping},
+	}
+	if example == nil {
+		example = map[string]interface{}{}
+	}
+	return schema, example
+}
+
+// unionComponents collects the named variants of the unions in the request
+// bodies and responses, which discriminator mappings point to
+func unionComponents(documentation *Documentation) map[string]interface{} {
+	components := make(map[string]interface{})
+	for _, section := range documentation.Endpoints {
+		for _, endpoint := range section.Endpoints {
+			if endpoint.RequestBody != nil {
+				for _, media := range endpoint.RequestBody.Contents() {
+					collectUnionVariants(media.Schema, components)
+				}
+			}
+			for _, response := range endpoint.Responses {
+				collectUnionVariants(response.Schema, components)
+			}
+		}
+	}
+	return components
+}
+
+func collectUnionVariants(schema interface{}, components map[string]interface{}) {
+	schemaMap, ok := schema.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if _, ok := schemaMap["discriminator"].(map[string]interface{}); ok {
+		alternatives, _ := schemaMap["oneOf"].([]interface{})
+		for _, alternative := range alternatives {
+			if variant, ok := alternative.(map[string]interface{}); ok {
+				if name, _ := variant["title"].(string); name != "" {
+					components[name] = variant
+				}
+			}
+		}
+	}
+	for _, key := range []string{"oneOf", "anyOf", "allOf"} {
+		alternatives, _ := schemaMap[key].([]interface{})
+		for _, alternative := range alternatives {
+			collectUnionVariants(alternative, components)
+		}
+	}
+	if properties, ok := schemaMap["properties"].(map[string]interface{}); ok {
+		for _, property := range properties {
+			collectUnionVariants(property, components)
+		}
+	}
+	collectUnionVariants(schemaMap["items"], components)
+	collectUnionVariants(schemaMap["additionalProperties"], components)
+}
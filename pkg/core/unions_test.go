@@ -0,0 +1,77 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testPayment interface{ isPayment() }
+
+type testCardPayment struct {
+	Number string `json:"number" binding:"required" example:"4242424242424242"`
+}
+
+func (testCardPayment) isPayment() {}
+
+type testBankTransfer struct {
+	IBAN string `json:"iban" example:"DE89370400440532013000"`
+}
+
+func (testBankTransfer) isPayment() {}
+
+func TestUnions_OneOfWithDiscriminator(t *testing.T) {
+	RegisterUnion((*testPayment)(nil), "method", map[string]interface{}{"card": testCardPayment{}, "bank_transfer": testBankTransfer{}})
+	defer RegisterUnion((*testPayment)(nil), "", nil)
+
+	type order struct {
+		Payment testPayment `json:"payment"`
+	}
+	schema, example := SchemaFromValue(order{})
+	payment := schema.(map[string]interface{})["properties"].(map[string]interface{})["payment"].(map[string]interface{})
+	variants, _ := payment["oneOf"].([]interface{})
+	if len(variants) != 2 {
+		t.Fatalf("expected the registered variants as oneOf, got %+v", payment)
+	}
+	discriminator := payment["discriminator"].(map[string]interface{})
+	mapping := discriminator["mapping"].(map[string]interface{})
+	if discriminator["propertyName"] != "method" || mapping["card"] != "#/components/schemas/testCardPayment" {
+		t.Fatalf("expected a discriminator mapping to the variant schemas, got %+v", discriminator)
+	}
+	card := variants[1].(map[string]interface{})
+	if card["title"] != "testCardPayment" || fmt.Sprint(card["required"]) != "[method number]" {
+		t.Fatalf("expected the variant to require its discriminator, got %+v", card)
+	}
+	if paymentExample := example.(map[string]interface{})["payment"].(map[string]interface{}); paymentExample["method"] != "bank_transfer" || paymentExample["iban"] != "DE89370400440532013000" {
+		t.Fatalf("expected the first variant as example, got %+v", paymentExample)
+	}
+
+	docs := New(&Config{DocsPath: "/docs"})
+	docs.AddRouteInfo(RouteInfo{Method: "POST", Path: "/orders", RequestBody: &RequestBody{ContentType: "application/json", Schema: schema, Example: example}})
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := docs.GetOpenAPIJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	if _, ok := schemas["testBankTransfer"]; !ok {
+		t.Fatalf("expected the variants as component schemas for the mapping, got %+v", schemas)
+	}
+	swagger, err := docs.GetSwaggerJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := json.Marshal(swagger); !strings.Contains(string(raw), `"discriminator":"method"`) {
+		t.Fatalf("expected a Swagger 2.0 discriminator property name, got %s", raw)
+	}
+	ts, err := docs.GetTypeScript()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(ts, `method: "card";`) || !strings.Contains(ts, " | ") {
+		t.Fatalf("expected a discriminated TypeScript union, got %s", ts)
+	}
+}
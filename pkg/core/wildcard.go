@@ -0,0 +1,56 @@
+package core
+
+import (
+	"strings"
+	"unicode"
+)
+
+// wildcardDescription notes how a catch-all path parameter matches
+const wildcardDescription = "Rest of the path; matches several segments, slashes included"
+
+// wildcardSegment returns the parameter name of a catch-all path segment:
+// "*filepath" (Gin, httprouter), "*" (Echo, Fiber), "+" (Fiber),
+// "{path...}" (net/http) and "{path:.*}" (Gorilla Mux). Unnamed wildcards
+// are documented as "wildcard".
+func wildcardSegment(segment string) (string, bool) {
+	switch {
+	case strings.HasPrefix(segment, "*") || strings.HasPrefix(segment, "+"):
+		name := segment[1:]
+		if name == "" || strings.IndexFunc(name, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+			// Fiber numbers further wildcards: "*2", "+2"
+			name = "wildcard" + name
+		}
+		return name, true
+	case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "...}"):
+		return strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "...}"), true
+	case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+		name, pattern, found := strings.Cut(strings.Trim(segment, "{}"), ":")
+		if found && (strings.Contains(pattern, ".*") || strings.Contains(pattern, ".+")) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// markWildcardParameters flags the path parameters of the catch-all
+// segments of path and notes how they match
+func markWildcardParameters(path string, params []Parameter) []Parameter {
+	wildcards := make(map[string]bool)
+	for _, segment := range strings.Split(path, "/") {
+		if name, ok := wildcardSegment(segment); ok {
+			wildcards[name] = true
+		}
+	}
+	if len(wildcards) == 0 {
+		return params
+	}
+	for i := range params {
+		if params[i].In == "path" && wildcards[params[i].Name] {
+			params[i].Wildcard = true
+			if params[i].Description == "" {
+				params[i].Description = wildcardDescription
+			}
+		}
+	}
+	return params
+}
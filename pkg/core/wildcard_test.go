@@ -0,0 +1,52 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWildcardPaths_DocumentedAsParameters(t *testing.T) {
+	docs := New(&Config{DocsPath: "/docs"})
+	docs.AddRoute("GET", "/files/*filepath", nil)
+	docs.AddRoute("GET", "/static/*", nil)
+	docs.AddRoute("GET", "/assets/{path...}", nil)
+	docs.AddRoute("GET", "/blobs/{key:.*}", nil)
+	docs.AddRoute("GET", "/users/{$}", nil)
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for route, path := range map[string]string{"/files/*filepath": "/files/{filepath}", "/static/*": "/static/{wildcard}", "/assets/{path...}": "/assets/{path}", "/blobs/{key:.*}": "/blobs/{key}", "/users/{$}": "/users/"} {
+		if got := convertPathToOpenAPI(route); got != path {
+			t.Fatalf("expected %s to become %s, got %s", route, path, got)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/openapi.json", nil))
+	var spec struct {
+		Paths map[string]map[string]struct {
+			Parameters []map[string]interface{} `json:"parameters"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatal(err)
+	}
+	for path, name := range map[string]string{"/files/{filepath}": "filepath", "/static/{wildcard}": "wildcard", "/assets/{path}": "path", "/blobs/{key}": "key"} {
+		params := spec.Paths[path]["get"].Parameters
+		if len(params) != 1 || params[0]["name"] != name || params[0]["in"] != "path" || params[0]["x-wildcard"] != true {
+			t.Fatalf("expected %s to document the wildcard parameter %q, got %+v", path, name, params)
+		}
+	}
+	if params := spec.Paths["/users/"]["get"].Parameters; len(params) != 0 {
+		t.Fatalf("expected {$} not to be a parameter, got %+v", params)
+	}
+
+	rec = httptest.NewRecorder()
+	docs.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/swagger.json", nil))
+	if !strings.Contains(rec.Body.String(), `"x-wildcard":true`) {
+		t.Fatalf("expected the Swagger document to flag wildcard parameters, got %s", rec.Body.String())
+	}
+}
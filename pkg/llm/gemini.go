@@ -49,13 +49,18 @@ func NewGeminiClient(config *ai.AIConfig) (*GeminiClient, error) {
 
 // Chat implements the Chat method for Gemini
 func (c *GeminiClient) Chat(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	model := c.model
+	if request.Model != "" {
+		model = request.Model
+	}
+
 	// Build the prompt by combining system prompt and user message
 	fullPrompt := c.buildSystemPrompt(request) + "\n\nUser: " + request.Message
 
 	// Make API call using the official genai library
 	result, err := c.client.Models.GenerateContent(
 		ctx,
-		c.model,
+		model,
 		genai.Text(fullPrompt),
 		nil,
 	)
@@ -63,7 +68,7 @@ func (c *GeminiClient) Chat(ctx context.Context, request ai.ChatRequest) (*ai.Ch
 		return &ai.ChatResponse{
 			Error:    err.Error(),
 			Provider: c.GetProvider(),
-			Model:    c.model,
+			Model:    model,
 		}, err
 	}
 
@@ -73,19 +78,25 @@ func (c *GeminiClient) Chat(ctx context.Context, request ai.ChatRequest) (*ai.Ch
 		return &ai.ChatResponse{
 			Error:    "No response content returned",
 			Provider: c.GetProvider(),
-			Model:    c.model,
+			Model:    model,
 		}, fmt.Errorf("no response content")
 	}
 
 	// Get tokens used (Gemini API might not always provide this)
-	tokensUsed := 0
-	// Note: Usage information might not be available in this version of the API
+	tokensUsed, promptTokens, completionTokens := 0, 0, 0
+	if usage := result.UsageMetadata; usage != nil {
+		promptTokens = int(usage.PromptTokenCount)
+		completionTokens = int(usage.CandidatesTokenCount)
+		tokensUsed = int(usage.TotalTokenCount)
+	}
 
 	return &ai.ChatResponse{
-		Response:   responseText,
-		Provider:   c.GetProvider(),
-		Model:      c.model,
-		TokensUsed: tokensUsed,
+		Response:         responseText,
+		Provider:         c.GetProvider(),
+		Model:            model,
+		TokensUsed:       tokensUsed,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
 	}, nil
 }
 
@@ -101,6 +112,11 @@ func (c *GeminiClient) GetModel() string {
 
 // buildSystemPrompt creates a system prompt based on the request context
 func (c *GeminiClient) buildSystemPrompt(request ai.ChatRequest) string {
+	// A configured prompt template replaces the built-in instructions
+	if request.SystemPrompt != "" {
+		return request.SystemPrompt
+	}
+
 	basePrompt := `You are an API documentation assistant. You MUST ONLY provide information about the exact API endpoints defined in the OpenAPI specification provided below.
 
 CRITICAL RULES:
@@ -31,7 +31,7 @@ func NewOpenAIClient(config *ai.AIConfig) (*OpenAIClient, error) {
 	client := openai.NewClient(option.WithAPIKey(apiKey))
 
 	// Default model
-	model := "gpt-3.5-turbo"
+	model := "gpt-4o-mini"
 	if config.Features.Model != "" {
 		model = config.Features.Model
 	}
@@ -45,20 +45,25 @@ func NewOpenAIClient(config *ai.AIConfig) (*OpenAIClient, error) {
 
 // Chat implements the Chat method for OpenAI
 func (c *OpenAIClient) Chat(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	model := c.model
+	if request.Model != "" {
+		model = request.Model
+	}
+
 	// Make API call using the simple pattern from official docs
 	chatCompletion, err := c.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
 		Messages: []openai.ChatCompletionMessageParamUnion{
 			openai.SystemMessage(c.buildSystemPrompt(request)),
 			openai.UserMessage(request.Message),
 		},
-		Model: openai.ChatModelGPT4oMini,
+		Model: openai.ChatModel(model),
 	})
 
 	if err != nil {
 		return &ai.ChatResponse{
 			Error:    err.Error(),
 			Provider: c.GetProvider(),
-			Model:    c.model,
+			Model:    model,
 		}, err
 	}
 
@@ -67,7 +72,7 @@ func (c *OpenAIClient) Chat(ctx context.Context, request ai.ChatRequest) (*ai.Ch
 		return &ai.ChatResponse{
 			Error:    "No response choices returned",
 			Provider: c.GetProvider(),
-			Model:    c.model,
+			Model:    model,
 		}, fmt.Errorf("no response choices")
 	}
 
@@ -78,10 +83,12 @@ func (c *OpenAIClient) Chat(ctx context.Context, request ai.ChatRequest) (*ai.Ch
 	}
 
 	return &ai.ChatResponse{
-		Response:   chatCompletion.Choices[0].Message.Content,
-		Provider:   c.GetProvider(),
-		Model:      string(chatCompletion.Model),
-		TokensUsed: tokensUsed,
+		Response:         chatCompletion.Choices[0].Message.Content,
+		Provider:         c.GetProvider(),
+		Model:            string(chatCompletion.Model),
+		TokensUsed:       tokensUsed,
+		PromptTokens:     int(chatCompletion.Usage.PromptTokens),
+		CompletionTokens: int(chatCompletion.Usage.CompletionTokens),
 	}, nil
 }
 
@@ -97,6 +104,11 @@ func (c *OpenAIClient) GetModel() string {
 
 // buildSystemPrompt creates a system prompt based on the request context
 func (c *OpenAIClient) buildSystemPrompt(request ai.ChatRequest) string {
+	// A configured prompt template replaces the built-in instructions
+	if request.SystemPrompt != "" {
+		return request.SystemPrompt
+	}
+
 	basePrompt := `You are an API documentation assistant. You MUST ONLY provide information about the exact API endpoints defined in the OpenAPI specification provided below.
 
 CRITICAL RULES:
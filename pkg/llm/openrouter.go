@@ -48,6 +48,11 @@ func NewOpenRouterClient(config *ai.AIConfig) (*OpenRouterClient, error) {
 
 // Chat implements the Chat method for OpenRouter
 func (c *OpenRouterClient) Chat(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	model := c.model
+	if request.Model != "" {
+		model = request.Model
+	}
+
 	// Build messages
 	messages := []openai.ChatCompletionMessageParamUnion{
 		openai.SystemMessage(c.buildSystemPrompt(request)),
@@ -57,7 +62,7 @@ func (c *OpenRouterClient) Chat(ctx context.Context, request ai.ChatRequest) (*a
 	// Prepare parameters
 	params := openai.ChatCompletionNewParams{
 		Messages: messages,
-		Model:    openai.ChatModel(c.model),
+		Model:    openai.ChatModel(model),
 	}
 
 	// Set max tokens based on config
@@ -79,7 +84,7 @@ func (c *OpenRouterClient) Chat(ctx context.Context, request ai.ChatRequest) (*a
 		return &ai.ChatResponse{
 			Error:    err.Error(),
 			Provider: c.GetProvider(),
-			Model:    c.model,
+			Model:    model,
 		}, err
 	}
 
@@ -88,7 +93,7 @@ func (c *OpenRouterClient) Chat(ctx context.Context, request ai.ChatRequest) (*a
 		return &ai.ChatResponse{
 			Error:    "No response choices returned",
 			Provider: c.GetProvider(),
-			Model:    c.model,
+			Model:    model,
 		}, fmt.Errorf("no response choices")
 	}
 
@@ -99,10 +104,12 @@ func (c *OpenRouterClient) Chat(ctx context.Context, request ai.ChatRequest) (*a
 	}
 
 	return &ai.ChatResponse{
-		Response:   chatCompletion.Choices[0].Message.Content,
-		Provider:   c.GetProvider(),
-		Model:      string(chatCompletion.Model),
-		TokensUsed: tokensUsed,
+		Response:         chatCompletion.Choices[0].Message.Content,
+		Provider:         c.GetProvider(),
+		Model:            string(chatCompletion.Model),
+		TokensUsed:       tokensUsed,
+		PromptTokens:     int(chatCompletion.Usage.PromptTokens),
+		CompletionTokens: int(chatCompletion.Usage.CompletionTokens),
 	}, nil
 }
 
@@ -118,6 +125,11 @@ func (c *OpenRouterClient) GetModel() string {
 
 // buildSystemPrompt creates a system prompt based on the request context
 func (c *OpenRouterClient) buildSystemPrompt(request ai.ChatRequest) string {
+	// A configured prompt template replaces the built-in instructions
+	if request.SystemPrompt != "" {
+		return request.SystemPrompt
+	}
+
 	basePrompt := `You are an API documentation assistant. You MUST ONLY provide information about the exact API endpoints defined in the OpenAPI specification provided below.
 
 CRITICAL RULES:
@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// acceptShorthands expands the short forms of "@Accept json,xml"
+var acceptShorthands = map[string]string{
+	"json":      "application/json",
+	"xml":       "application/xml",
+	"yaml":      "application/x-yaml",
+	"protobuf":  "application/protobuf",
+	"proto":     "application/protobuf",
+	"form":      "application/x-www-form-urlencoded",
+	"multipart": "multipart/form-data",
+	"plain":     "text/plain",
+}
+
+// acceptAnnotation returns the content types of an "@Accept <types>" comment
+// line, e.g. "@Accept json,xml" or "@Accept application/json application/xml"
+func acceptAnnotation(line string) ([]string, bool) {
+	rest, found := strings.CutPrefix(line, "@Accept")
+	if !found || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return nil, false
+	}
+
+	var contentTypes []string
+	for _, value := range strings.FieldsFunc(rest, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		value = strings.ToLower(value)
+		if full, ok := acceptShorthands[value]; ok {
+			value = full
+		}
+		if !strings.Contains(value, "/") {
+			fmt.Printf("⚠️  Ignoring @Accept %q: expected json, xml, yaml, protobuf, form, multipart, plain or a content type\n", value)
+			continue
+		}
+		contentTypes = append(contentTypes, value)
+	}
+	return contentTypes, len(contentTypes) > 0
+}
@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStructFieldAccessModes(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+type User struct {
+	ID       string ` + "`json:\"id\" readonly:\"true\"`" + `
+	Email    string ` + "`json:\"email\" binding:\"required\"`" + `
+	// @writeOnly Login secret
+	Password string ` + "`json:\"password\" binding:\"required\"`" + `
+}
+
+// CreateUser creates a user
+func CreateUser(c *gin.Context) {
+	var body User
+	c.ShouldBindJSON(&body)
+	c.JSON(201, body)
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := analyzeDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, _ := analysis.handlers["createuser"][0].metadata.RequestBody.Schema.(map[string]interface{})
+	properties, _ := schema["properties"].(map[string]interface{})
+	id, _ := properties["id"].(map[string]interface{})
+	password, _ := properties["password"].(map[string]interface{})
+	if id["readOnly"] != true || password["writeOnly"] != true {
+		t.Fatalf("expected readOnly and writeOnly properties, got %+v", properties)
+	}
+	if password["description"] != "Login secret" {
+		t.Fatalf("expected the marker to be dropped from the description, got %q", password["description"])
+	}
+}
@@ -0,0 +1,371 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// analysisCacheVersion invalidates cache files written by older analyzers.
+// Bump it whenever the shape of the cached metadata or the analysis output changes.
+const analysisCacheVersion = 17
+
+var (
+	analysisCacheDir      string
+	analysisCacheDirMutex sync.RWMutex
+)
+
+// handlerCacheEntry is the serializable form of an analyzed handler.
+type handlerCacheEntry[M any] struct {
+	FilePath     string `json:"filePath"`
+	FuncName     string `json:"funcName"`
+	ReceiverName string `json:"receiverName"`
+	StartLine    int    `json:"startLine"`
+	Metadata     M      `json:"metadata"`
+}
+
+// cachedSourceFile records the hashes of one source file at analysis time.
+// DeclHash covers only the package-level declarations and function signatures,
+// i.e. everything that handlers in other files can depend on.
+type cachedSourceFile struct {
+	Hash     string `json:"hash"`
+	DeclHash string `json:"declHash"`
+}
+
+// analysisCacheFile is the on-disk layout of a cached directory analysis.
+type analysisCacheFile[M any] struct {
+	Version  int                               `json:"version"`
+	Dir      string                            `json:"dir"`
+	Settings string                            `json:"settings"`
+	Files    map[string]cachedSourceFile       `json:"files"`
+	Handlers map[string][]handlerCacheEntry[M] `json:"handlers"`
+}
+
+// SetAnalysisCacheDir enables the persistent analyzer cache in dir.
+// Handler metadata is stored per source directory and invalidated per file
+// when the file's content hash changes. An empty dir disables the cache.
+// Config.AnalysisCacheDir overrides it for one docs instance.
+func SetAnalysisCacheDir(dir string) {
+	analysisCacheDirMutex.Lock()
+	defer analysisCacheDirMutex.Unlock()
+	analysisCacheDir = dir
+}
+
+// getAnalysisCacheDir returns the cache directory of the current analysis,
+// or "" when debugging, since cached handlers carry no traces
+func getAnalysisCacheDir() string {
+	return currentAnalysisSettings().analysisCacheDir()
+}
+
+// withAnalysisCache returns the handlers for dir from the disk cache, re-analyzing
+// only what changed since the cache was written:
+//   - no file changed: the cached handlers are returned as-is
+//   - only function bodies changed: analyze runs for the changed files alone and
+//     their handlers replace the cached ones
+//   - declarations changed or files were added/removed: analyze runs for all files
+//
+// analyze receives the set of file names to collect handlers from, or nil for all.
+// Cache failures never break analysis; they only cost a re-parse.
+func withAnalysisCache[M any](kind, dir string, analyze func(only map[string]bool) (map[string][]handlerCacheEntry[M], error)) (map[string][]handlerCacheEntry[M], error) {
+	cacheDir := getAnalysisCacheDir()
+	if cacheDir == "" {
+		return analyze(nil)
+	}
+
+	hashes, err := hashGoFiles(dir)
+	if err != nil {
+		return analyze(nil)
+	}
+
+	settings := currentAnalysisSettings().fingerprint() + "|" + core.ResponseHelpersFingerprint()
+	path := analysisCachePath(cacheDir, kind, dir, settings)
+	var cached analysisCacheFile[M]
+	if data, err := os.ReadFile(path); err != nil || json.Unmarshal(data, &cached) != nil ||
+		cached.Version != analysisCacheVersion || cached.Settings != settings || cached.Handlers == nil {
+		cached = analysisCacheFile[M]{}
+	}
+
+	files := make(map[string]cachedSourceFile, len(hashes))
+	changed := make(map[string]bool)
+	incremental := cached.Handlers != nil && len(cached.Files) == len(hashes)
+	for name, hash := range hashes {
+		previous, ok := cached.Files[name]
+		if ok && previous.Hash == hash {
+			files[name] = previous
+			continue
+		}
+		changed[name] = true
+
+		declHash, err := hashGoDeclarations(filepath.Join(dir, name))
+		if err != nil || !ok || declHash != previous.DeclHash {
+			incremental = false
+		}
+		files[name] = cachedSourceFile{Hash: hash, DeclHash: declHash}
+	}
+
+	if incremental && len(changed) == 0 {
+		return cached.Handlers, nil
+	}
+
+	var handlers map[string][]handlerCacheEntry[M]
+	if incremental {
+		fresh, err := analyze(changed)
+		if err != nil {
+			return nil, err
+		}
+		handlers = mergeChangedHandlers(cached.Handlers, fresh, changed)
+	} else {
+		if handlers, err = analyze(nil); err != nil {
+			return nil, err
+		}
+		// Every file is re-analyzed, so record declaration hashes for all of them
+		for name, file := range files {
+			if file.DeclHash == "" {
+				file.DeclHash, _ = hashGoDeclarations(filepath.Join(dir, name))
+				files[name] = file
+			}
+		}
+	}
+
+	data, err := json.Marshal(analysisCacheFile[M]{
+		Version:  analysisCacheVersion,
+		Dir:      dir,
+		Settings: settings,
+		Files:    files,
+		Handlers: handlers,
+	})
+	if err == nil && os.MkdirAll(cacheDir, 0755) == nil {
+		// Write atomically so concurrent processes never read a partial file
+		tmp := path + ".tmp"
+		if os.WriteFile(tmp, data, 0644) == nil {
+			if err := os.Rename(tmp, path); err != nil {
+				os.Remove(tmp)
+			}
+		}
+	}
+
+	return handlers, nil
+}
+
+// mergeChangedHandlers drops the cached handlers declared in changed files and
+// adds the freshly analyzed ones
+func mergeChangedHandlers[M any](cached, fresh map[string][]handlerCacheEntry[M], changed map[string]bool) map[string][]handlerCacheEntry[M] {
+	merged := make(map[string][]handlerCacheEntry[M], len(cached))
+	for key, entries := range cached {
+		for _, entry := range entries {
+			if !changed[filepath.Base(entry.FilePath)] {
+				merged[key] = append(merged[key], entry)
+			}
+		}
+	}
+	for key, entries := range fresh {
+		merged[key] = append(merged[key], entries...)
+	}
+	return merged
+}
+
+// analysisCachePath returns the cache file for an analyzer kind, source
+// directory and settings, so docs with different settings keep their own
+func analysisCachePath(cacheDir, kind, dir, settings string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	sum := sha256.Sum256([]byte(dir + "\x00" + settings))
+	return filepath.Join(cacheDir, kind+"-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+// isAnalyzedGoFile reports whether a file name is a non-test Go source file
+func isAnalyzedGoFile(name string) bool {
+	return strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go")
+}
+
+// hashGoFiles hashes the contents of each non-test Go file in dir, keyed by file name
+func hashGoFiles(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !isAnalyzedGoFile(name) {
+			continue
+		}
+
+		file, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		hasher := sha256.New()
+		_, err = io.Copy(hasher, file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		hashes[name] = hex.EncodeToString(hasher.Sum(nil))
+	}
+
+	return hashes, nil
+}
+
+// hashGoDeclarations hashes the source of a file's package-level declarations
+// and function signatures, ignoring function bodies. Handlers only depend on
+// other files through these, so a change that keeps this hash stable only
+// requires re-analyzing the changed file itself.
+func hashGoDeclarations(path string) (string, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return "", err
+	}
+
+	hasher := sha256.New()
+	io.WriteString(hasher, file.Name.Name+"\x00")
+	for _, decl := range file.Decls {
+		start, end := decl.Pos(), decl.End()
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			end = fn.Type.End()
+			if helper := inferResponseHelper(fn); helper != nil {
+				// Callers of a response helper document what its body writes
+				fmt.Fprintf(hasher, "%+v", *helper)
+			}
+		}
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Doc != nil {
+			// Type doc comments become schema descriptions
+			start = gen.Doc.Pos()
+		}
+		hasher.Write(src[fset.Position(start).Offset:fset.Position(end).Offset])
+		hasher.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// restrictPackageFiles limits parsed packages to the files named in only, so
+// the framework analyzers can collect handlers from changed files alone. A nil
+// only keeps every file.
+func restrictPackageFiles(pkgs map[string]*ast.Package, only map[string]bool) map[string]*ast.Package {
+	if only == nil {
+		return pkgs
+	}
+
+	restricted := make(map[string]*ast.Package, len(pkgs))
+	for name, pkg := range pkgs {
+		files := make(map[string]*ast.File)
+		for path, file := range pkg.Files {
+			if only[filepath.Base(path)] {
+				files[path] = file
+			}
+		}
+		restricted[name] = &ast.Package{Name: pkg.Name, Files: files}
+	}
+	return restricted
+}
+
+// convertHandlers maps every handler in a keyed handler set
+func convertHandlers[A, B any](handlers map[string][]A, convert func(A) B) map[string][]B {
+	converted := make(map[string][]B, len(handlers))
+	for key, list := range handlers {
+		items := make([]B, 0, len(list))
+		for _, item := range list {
+			items = append(items, convert(item))
+		}
+		converted[key] = items
+	}
+	return converted
+}
+
+// PrewarmAnalysis analyzes the Go sources in dirs for every supported framework
+// with the process-wide analyzer settings, so the first request to docs that
+// don't override them does not pay for parsing. It blocks until all analyzers
+// finished; use `go parser.PrewarmAnalysis(".")` to warm up in the background.
+func PrewarmAnalysis(dirs ...string) {
+	(*analysisSettings)(nil).scoped(func() { prewarmAnalysis(dirs) })()
+}
+
+func prewarmAnalysis(dirs []string) {
+	warmers := []func(dir, abs string){
+		func(dir, abs string) {
+			aliasAnalysis(&analysisMutex, analysisCache, abs, loadPackageAnalysis(dir))
+		},
+		func(dir, abs string) {
+			aliasAnalysis(&stdlibAnalysisMutex, stdlibAnalysisCache, abs, loadStdlibPackageAnalysis(dir))
+		},
+		func(dir, abs string) {
+			aliasAnalysis(&echoAnalysisMutex, echoAnalysisCache, abs, loadEchoPackageAnalysis(dir))
+		},
+		func(dir, abs string) {
+			aliasAnalysis(&fiberAnalysisMutex, fiberAnalysisCache, abs, loadFiberPackageAnalysis(dir))
+		},
+		func(dir, abs string) {
+			aliasAnalysis(&gorillaMuxAnalysisMutex, gorillaMuxAnalysisCache, abs, loadGorillaMuxPackageAnalysis(dir))
+		},
+	}
+
+	var wg sync.WaitGroup
+	for _, dir := range dirs {
+		dir = filepath.Clean(dir)
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			abs = dir
+		}
+		for _, warm := range warmers {
+			wg.Add(1)
+			go func(warm func(dir, abs string)) {
+				defer wg.Done()
+				warm(dir, abs)
+			}(warm)
+		}
+	}
+	wg.Wait()
+}
+
+// resetAnalysisCaches drops the in-memory analyses of every analyzer and settings
+func resetAnalysisCaches() {
+	clearAnalysisCache(&analysisMutex, analysisCache)
+	clearAnalysisCache(&stdlibAnalysisMutex, stdlibAnalysisCache)
+	clearAnalysisCache(&echoAnalysisMutex, echoAnalysisCache)
+	clearAnalysisCache(&fiberAnalysisMutex, fiberAnalysisCache)
+	clearAnalysisCache(&gorillaMuxAnalysisMutex, gorillaMuxAnalysisCache)
+	clearAnalysisCache(&controllerAnalysisMutex, controllerAnalysisCache)
+	resetAnalysisFailures()
+	structDocs.Clear()
+	unionDefinitions.Clear()
+}
+
+func clearAnalysisCache[T any](mutex *sync.RWMutex, cache map[string]*T) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	clear(cache)
+}
+
+// aliasAnalysis stores an analysis under its absolute directory as well, since
+// handlers resolved through runtime file info look up absolute paths
+func aliasAnalysis[T any](mutex *sync.RWMutex, cache map[string]*T, abs string, analysis *T) {
+	if analysis == nil {
+		return
+	}
+	key := analysisKey(abs)
+	mutex.Lock()
+	defer mutex.Unlock()
+	if _, ok := cache[key]; !ok {
+		cache[key] = analysis
+	}
+}
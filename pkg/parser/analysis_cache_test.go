@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAnalysisCacheReusesResultsUntilSourcesChange(t *testing.T) {
+	srcDir := t.TempDir()
+	cacheDir := t.TempDir()
+	SetAnalysisCacheDir(cacheDir)
+	defer SetAnalysisCacheDir("")
+
+	source := filepath.Join(srcDir, "main.go")
+	if err := os.WriteFile(source, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	analyze := func(only map[string]bool) (map[string][]handlerCacheEntry[HandlerMetadata], error) {
+		calls++
+		return map[string][]handlerCacheEntry[HandlerMetadata]{
+			"getusers": {{FilePath: source, FuncName: "GetUsers", Metadata: HandlerMetadata{Info: HandlerInfo{Summary: "List users"}}}},
+		}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		handlers, err := withAnalysisCache("gin", srcDir, analyze)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if handlers["getusers"][0].Metadata.Info.Summary != "List users" {
+			t.Fatalf("unexpected cached handlers: %+v", handlers)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single analysis with a warm cache, got %d", calls)
+	}
+
+	if err := os.WriteFile(source, []byte("package main\n\nfunc GetUsers() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := withAnalysisCache("gin", srcDir, analyze); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected re-analysis after a source change, got %d calls", calls)
+	}
+}
+
+func TestAnalysisCacheReanalyzesOnlyChangedFiles(t *testing.T) {
+	srcDir := t.TempDir()
+	SetAnalysisCacheDir(t.TempDir())
+	defer SetAnalysisCacheDir("")
+
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(srcDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("users.go", "package main\n\nfunc GetUsers() { println(1) }\n")
+	write("orders.go", "package main\n\ntype Order struct{ ID int }\n\nfunc GetOrders() {}\n")
+
+	var lastOnly map[string]bool
+	summaries := map[string]string{"users.go": "v1", "orders.go": "v1"}
+	analyze := func(only map[string]bool) (map[string][]handlerCacheEntry[HandlerMetadata], error) {
+		lastOnly = only
+		handlers := make(map[string][]handlerCacheEntry[HandlerMetadata])
+		for name, funcName := range map[string]string{"users.go": "GetUsers", "orders.go": "GetOrders"} {
+			if only != nil && !only[name] {
+				continue
+			}
+			handlers[funcName] = append(handlers[funcName], handlerCacheEntry[HandlerMetadata]{
+				FilePath: filepath.Join(srcDir, name),
+				FuncName: funcName,
+				Metadata: HandlerMetadata{Info: HandlerInfo{Summary: summaries[name]}},
+			})
+		}
+		return handlers, nil
+	}
+
+	if _, err := withAnalysisCache("gin", srcDir, analyze); err != nil {
+		t.Fatal(err)
+	}
+
+	// A body-only change re-analyzes just that file and keeps the others cached
+	summaries["users.go"], summaries["orders.go"] = "v2", "stale"
+	write("users.go", "package main\n\nfunc GetUsers() { println(2) }\n")
+	handlers, err := withAnalysisCache("gin", srcDir, analyze)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lastOnly) != 1 || !lastOnly["users.go"] {
+		t.Fatalf("expected only users.go to be re-analyzed, got %v", lastOnly)
+	}
+	if handlers["GetUsers"][0].Metadata.Info.Summary != "v2" || handlers["GetOrders"][0].Metadata.Info.Summary != "v1" {
+		t.Fatalf("unexpected merged handlers: %+v", handlers)
+	}
+	if len(handlers["GetUsers"]) != 1 {
+		t.Fatalf("expected the stale GetUsers entry to be replaced, got %+v", handlers["GetUsers"])
+	}
+
+	// Changing a type declaration can affect handlers anywhere, so everything is re-analyzed
+	write("orders.go", "package main\n\ntype Order struct{ ID string }\n\nfunc GetOrders() {}\n")
+	if _, err := withAnalysisCache("gin", srcDir, analyze); err != nil {
+		t.Fatal(err)
+	}
+	if lastOnly != nil {
+		t.Fatalf("expected a full re-analysis after a declaration change, got %v", lastOnly)
+	}
+}
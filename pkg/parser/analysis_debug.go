@@ -0,0 +1,141 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+	"sync/atomic"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// analysisDebug makes the analyzers record a trace per handler
+var analysisDebug atomic.Bool
+
+// SetAnalysisDebug enables analysis traces: per handler, which binding and
+// response calls were matched, which types they resolved to and why inference
+// fell back to defaults. Traces need a fresh analysis, so the persistent
+// analysis cache is bypassed while debugging. Config.Debug enables them for
+// one docs instance.
+func SetAnalysisDebug(enabled bool) {
+	analysisDebug.Store(enabled)
+}
+
+// handlerTrace collects the analysis steps of one handler; nil when debugging is off
+type handlerTrace struct {
+	steps []string
+}
+
+func newHandlerTrace() *handlerTrace {
+	if !currentAnalysisSettings().debugEnabled() {
+		return nil
+	}
+	return &handlerTrace{}
+}
+
+func (t *handlerTrace) add(format string, args ...interface{}) {
+	if t == nil {
+		return
+	}
+	t.steps = append(t.steps, fmt.Sprintf(format, args...))
+}
+
+// finish notes what inference left to defaults and returns the steps
+func (t *handlerTrace) finish(hasBody bool, responses int) []string {
+	if t == nil {
+		return nil
+	}
+	if !hasBody {
+		t.add("no request body inferred: no binding call bound a resolvable type")
+	}
+	if responses == 0 {
+		t.add("no response call matched: the endpoint is documented with default responses")
+	}
+	return t.steps
+}
+
+// traceBinding records a request body binding call and the type it bound
+func (ctx *analysisContext) traceBinding(call *ast.CallExpr, body *core.RequestBody) {
+	if ctx.trace == nil {
+		return
+	}
+	bound := "its argument"
+	if len(call.Args) > 0 {
+		if typ := exprToString(resolveTypeFromArg(call.Args[len(call.Args)-1], ctx)); typ != "" {
+			bound = typ
+		}
+	}
+	if body == nil {
+		ctx.trace.add("%s: the type of %s could not be resolved, no request body documented", callName(call), bound)
+		return
+	}
+	ctx.trace.add("%s: request body %s as %s", callName(call), bound, body.ContentType)
+}
+
+// traceParameters records parameters a binding call added
+func (ctx *analysisContext) traceParameters(call *ast.CallExpr, params []core.Parameter) {
+	if ctx.trace == nil || len(params) == 0 {
+		return
+	}
+	names := make([]string, 0, len(params))
+	for _, param := range params {
+		names = append(names, param.In+" "+param.Name)
+	}
+	ctx.trace.add("%s: parameters %s", callName(call), strings.Join(names, ", "))
+}
+
+// traceResponse records a response call, its status and payload type
+func (ctx *analysisContext) traceResponse(call *ast.CallExpr, statusExpr ast.Expr, statusCode string, payloadExpr ast.Expr, schema interface{}) {
+	if ctx.trace == nil {
+		return
+	}
+	step := fmt.Sprintf("%s: %s response", callName(call), statusCode)
+	if statusExpr == nil || extractStatusCode(statusExpr, ctx) == "" {
+		status := "the status"
+		if statusExpr != nil {
+			status = types.ExprString(statusExpr)
+		}
+		step += fmt.Sprintf(" (%s could not be resolved to a constant, defaulted to 200)", status)
+	}
+	if payload := payloadTypeName(payloadExpr); payload != "" {
+		step += ", payload " + payload
+	}
+	if isGenericObjectSchema(schema) {
+		step += "; the payload type could not be resolved, documented as a generic object"
+	}
+	ctx.trace.add("%s", step)
+}
+
+// callName names a call for traces, e.g. "c.ShouldBindJSON"
+func callName(call *ast.CallExpr) string {
+	return types.ExprString(call.Fun)
+}
+
+// payloadTypeName names the type of a resolved response payload
+func payloadTypeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		if name := exprToString(e.Type); name != "" {
+			return name + " literal"
+		}
+		return "literal"
+	case *ast.BasicLit:
+		return "literal " + e.Value
+	}
+	return exprToString(expr)
+}
+
+// isGenericObjectSchema reports a schema that says nothing beyond "object"
+func isGenericObjectSchema(schema interface{}) bool {
+	m, ok := schema.(map[string]interface{})
+	if !ok {
+		return schema == nil
+	}
+	if m["type"] != "object" {
+		return false
+	}
+	_, properties := m["properties"]
+	_, additional := m["additionalProperties"]
+	return !properties && !additional
+}
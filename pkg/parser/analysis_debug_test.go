@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAnalysisDebugTracesBindingsResponsesAndFallbacks(t *testing.T) {
+	SetAnalysisDebug(true)
+	t.Cleanup(func() { SetAnalysisDebug(false) })
+
+	dir := t.TempDir()
+	source := `package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateUserRequest struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type User struct {
+	ID   int    ` + "`json:\"id\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}
+
+func CreateUser(c *gin.Context) {
+	var req CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(status(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, User{Name: req.Name})
+}
+
+func Ping(c *gin.Context) {
+	c.Status(http.StatusNoContent)
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "users.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := analyzeDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trace := strings.Join(analysis.handlers["createuser"][0].metadata.Trace, "\n")
+	for _, step := range []string{
+		"c.ShouldBindJSON: request body CreateUserRequest as application/json",
+		"c.JSON: 200 response (status(err) could not be resolved to a constant, defaulted to 200)",
+		"c.JSON: 201 response, payload User literal",
+	} {
+		if !strings.Contains(trace, step) {
+			t.Fatalf("expected trace step %q, got\n%s", step, trace)
+		}
+	}
+
+	ping := analysis.handlers["ping"][0].metadata.Trace
+	if len(ping) != 2 || !strings.Contains(ping[0], "no request body") || !strings.Contains(ping[1], "default responses") {
+		t.Fatalf("expected the fallbacks of a handler without bindings and responses, got %v", ping)
+	}
+}
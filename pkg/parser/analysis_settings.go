@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// analysisSettings are the analyzer options of one docs instance. Options it
+// leaves unset fall back to the process-wide defaults of SetErrorConvention,
+// SetResponseEnvelopes, SetAnalysisCacheDir, SetAnalysisWorkers and
+// SetAnalysisDebug; a nil *analysisSettings uses the defaults alone.
+type analysisSettings struct {
+	errorType    string
+	errorHelpers map[string]int    // nil keeps the default helpers
+	envelopes    map[string]string // nil keeps the default envelopes
+	cacheDir     string
+	workers      int
+	debug        bool
+}
+
+var (
+	// analysisScope serializes the analyses run with instance settings, so
+	// the analyzers can read them from activeAnalysis
+	analysisScope  sync.Mutex
+	activeAnalysis atomic.Pointer[analysisSettings]
+)
+
+// newAnalysisSettings reads the analyzer cache directory, worker count, error
+// convention, response envelopes and debug mode of config
+func newAnalysisSettings(config *core.Config) *analysisSettings {
+	settings := &analysisSettings{}
+	if config == nil {
+		return settings
+	}
+	settings.cacheDir = config.AnalysisCacheDir
+	settings.workers = config.AnalysisWorkers
+	settings.debug = config.Debug
+	settings.errorType = config.ErrorResponseType
+	if len(config.ErrorHelpers) > 0 {
+		helpers, err := parseErrorHelpers(config.ErrorHelpers)
+		if err != nil {
+			fmt.Printf("⚠️  Ignoring error helpers: %v\n", err)
+		} else {
+			settings.errorHelpers = helpers
+		}
+	}
+	if len(config.ResponseEnvelopes) > 0 {
+		envelopes, err := core.ParseResponseEnvelopes(config.ResponseEnvelopes)
+		if err != nil {
+			fmt.Printf("⚠️  Ignoring response envelopes: %v\n", err)
+		} else {
+			settings.envelopes = envelopes
+		}
+	}
+	return settings
+}
+
+// scoped returns fn running with s as the settings of every analysis it
+// starts, e.g. a route detector. Analyses outside a scope use the defaults.
+func (s *analysisSettings) scoped(fn func()) func() {
+	return func() {
+		analysisScope.Lock()
+		defer analysisScope.Unlock()
+		activeAnalysis.Store(s)
+		defer activeAnalysis.Store(nil)
+		fn()
+	}
+}
+
+// currentAnalysisSettings returns the settings of the running scope, or nil
+func currentAnalysisSettings() *analysisSettings {
+	return activeAnalysis.Load()
+}
+
+// errorConvention returns the error convention and whether its envelope was
+// registered explicitly. Helpers without a type keep the default envelope.
+func (s *analysisSettings) errorConvention() (ErrorConvention, bool) {
+	errorConventionMutex.RLock()
+	convention, configured := errorConvention, errorConventionSet
+	errorConventionMutex.RUnlock()
+	if s == nil {
+		return convention, configured
+	}
+	if s.errorType != "" {
+		convention.Type, configured = s.errorType, true
+	}
+	if s.errorHelpers != nil {
+		convention.Helpers = s.errorHelpers
+	}
+	return convention, configured
+}
+
+// responseEnvelope returns the payload field of an envelope type
+func (s *analysisSettings) responseEnvelope(typeName string) (string, bool) {
+	if s != nil && s.envelopes != nil {
+		field, ok := s.envelopes[typeName]
+		return field, ok
+	}
+	responseEnvelopesMutex.RLock()
+	defer responseEnvelopesMutex.RUnlock()
+	field, ok := responseEnvelopes[typeName]
+	return field, ok
+}
+
+// responseEnvelopes returns the envelope payload fields by type
+func (s *analysisSettings) responseEnvelopes() map[string]string {
+	if s != nil && s.envelopes != nil {
+		return s.envelopes
+	}
+	responseEnvelopesMutex.RLock()
+	defer responseEnvelopesMutex.RUnlock()
+	return responseEnvelopes
+}
+
+// analysisCacheDir returns the persistent cache directory, or "" when
+// debugging, since cached handlers carry no traces
+func (s *analysisSettings) analysisCacheDir() string {
+	if s.debugEnabled() {
+		return ""
+	}
+	if s != nil && s.cacheDir != "" {
+		return s.cacheDir
+	}
+	analysisCacheDirMutex.RLock()
+	defer analysisCacheDirMutex.RUnlock()
+	return analysisCacheDir
+}
+
+// analysisWorkers returns how many files are analyzed concurrently, 0 for GOMAXPROCS
+func (s *analysisSettings) analysisWorkers() int {
+	if s != nil && s.workers > 0 {
+		return s.workers
+	}
+	analysisWorkersMutex.RLock()
+	defer analysisWorkersMutex.RUnlock()
+	return analysisWorkers
+}
+
+// debugEnabled reports whether handlers are analyzed with traces
+func (s *analysisSettings) debugEnabled() bool {
+	return s != nil && s.debug || analysisDebug.Load()
+}
+
+// fingerprint identifies the settings that change analysis results. The
+// in-memory caches are partitioned by it and persisted caches record it.
+func (s *analysisSettings) fingerprint() string {
+	convention, configured := s.errorConvention()
+	helpers := make([]string, 0, len(convention.Helpers))
+	for name, index := range convention.Helpers {
+		helpers = append(helpers, name+":"+strconv.Itoa(index))
+	}
+	sort.Strings(helpers)
+
+	envelopes := make([]string, 0)
+	for typeName, field := range s.responseEnvelopes() {
+		envelopes = append(envelopes, typeName+"."+field)
+	}
+	sort.Strings(envelopes)
+
+	return strings.Join([]string{
+		convention.Type, strconv.FormatBool(configured), strings.Join(helpers, ","),
+		strings.Join(envelopes, ","), strconv.FormatBool(s.debugEnabled()),
+	}, "|")
+}
+
+// analysisKey keys the in-memory analysis of dir under the current settings
+func analysisKey(dir string) string {
+	return currentAnalysisSettings().fingerprint() + "\x00" + dir
+}
+
+// resetCaches drops the in-memory analyses made with s, leaving those of
+// docs with other settings alone
+func (s *analysisSettings) resetCaches() {
+	analysisScope.Lock()
+	defer analysisScope.Unlock()
+
+	prefix := s.fingerprint() + "\x00"
+	clearAnalysisPartition(&analysisMutex, analysisCache, prefix)
+	clearAnalysisPartition(&stdlibAnalysisMutex, stdlibAnalysisCache, prefix)
+	clearAnalysisPartition(&echoAnalysisMutex, echoAnalysisCache, prefix)
+	clearAnalysisPartition(&fiberAnalysisMutex, fiberAnalysisCache, prefix)
+	clearAnalysisPartition(&gorillaMuxAnalysisMutex, gorillaMuxAnalysisCache, prefix)
+	clearAnalysisPartition(&controllerAnalysisMutex, controllerAnalysisCache, prefix)
+	// Struct docs are only read while analyzing, which the scope rules out
+	structDocs.Clear()
+	unionDefinitions.Clear()
+}
+
+func clearAnalysisPartition[T any](mutex *sync.RWMutex, cache map[string]*T, prefix string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for key := range cache {
+		if strings.HasPrefix(key, prefix) {
+			delete(cache, key)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// analysisFailures remembers why a package directory could not be analyzed,
+// so the routes of its handlers can tell why they have no schemas
+var (
+	analysisFailures      = make(map[string]error)
+	analysisFailuresMutex sync.RWMutex
+)
+
+// recordAnalysisFailure keeps the error that stopped the analysis of dir.
+// Analyzers record such errors instead of returning them, so a directory that
+// fails to parse does not break docs generation; its routes report the error
+// as a warning.
+func recordAnalysisFailure(dir string, err error) {
+	analysisFailuresMutex.Lock()
+	defer analysisFailuresMutex.Unlock()
+	analysisFailures[dir] = err
+}
+
+// resetAnalysisFailures forgets recorded failures, e.g. before a refresh
+func resetAnalysisFailures() {
+	analysisFailuresMutex.Lock()
+	defer analysisFailuresMutex.Unlock()
+	clear(analysisFailures)
+}
+
+// unresolvedHandlerWarnings explains a handler that is not a Go function
+func unresolvedHandlerWarnings() []string {
+	return []string{"handler could not be resolved to a Go function, so its request and response schemas were not inferred"}
+}
+
+// packageWarnings explains why the package of ref could not be analyzed
+func packageWarnings(ref handlerRef) []string {
+	analysisFailuresMutex.RLock()
+	err := analysisFailures[ref.dir]
+	analysisFailuresMutex.RUnlock()
+	if err != nil {
+		return []string{fmt.Sprintf("analysis of %s failed: %s", displayPath(ref.dir), trimWorkingDir(err.Error()))}
+	}
+	return []string{fmt.Sprintf("source of %s could not be analyzed", displayPath(ref.dir))}
+}
+
+// handlerNotFoundWarnings explains a handler missing from its analyzed package
+func handlerNotFoundWarnings(ref handlerRef) []string {
+	name := ref.funcName
+	if ref.receiverName != "" {
+		name = ref.receiverName + "." + name
+	}
+	if outer, _, closure := strings.Cut(name, ".func"); closure {
+		return []string{fmt.Sprintf("handler is an anonymous function in %s; schemas are only inferred from declared functions and methods", outer)}
+	}
+	if ref.file == "" && ref.dir == "." {
+		return []string{fmt.Sprintf("source of handler %s was not found; schemas are inferred from source files, which must be available at runtime", name)}
+	}
+	return []string{fmt.Sprintf("handler %s was not found in the analyzed source of %s", name, displayPath(ref.dir))}
+}
+
+// displayPath makes dir relative to the working directory, so warnings shown
+// in the docs do not expose where the server is installed
+func displayPath(dir string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return dir
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	if rel, err := filepath.Rel(wd, abs); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return filepath.Base(abs)
+}
+
+// trimWorkingDir strips the working directory from file paths in an error message
+func trimWorkingDir(message string) string {
+	if wd, err := os.Getwd(); err == nil {
+		message = strings.ReplaceAll(message, wd+string(filepath.Separator), "")
+	}
+	return message
+}
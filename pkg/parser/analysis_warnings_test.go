@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAnalysisWarningsExplainMissingSchemas(t *testing.T) {
+	t.Cleanup(resetAnalysisCaches)
+	dir := t.TempDir()
+	source := filepath.Join(dir, "handlers.go")
+	if err := os.WriteFile(source, []byte("package handlers\n\nfunc ListUsers(c echo.Context) error {\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata := getEchoHandlerMetadataByName("ListUsers", dir)
+	if len(metadata.Warnings) != 1 || !strings.Contains(metadata.Warnings[0], "failed: ") {
+		t.Fatalf("expected the parse error as warning, got %v", metadata.Warnings)
+	}
+
+	// Fixed sources are analyzed again after a refresh
+	if err := os.WriteFile(source, []byte("package handlers\n\nfunc Health() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	resetAnalysisCaches()
+	metadata = getEchoHandlerMetadataByName("ListUsers", dir)
+	if len(metadata.Warnings) != 1 || !strings.Contains(metadata.Warnings[0], "handler ListUsers was not found") {
+		t.Fatalf("expected the missing handler as warning, got %v", metadata.Warnings)
+	}
+
+	closure := getHandlerMetadata(gin.HandlerFunc(func(c *gin.Context) {}))
+	if len(closure.Warnings) != 1 || !strings.Contains(closure.Warnings[0], "anonymous function in TestAnalysisWarningsExplainMissingSchemas") {
+		t.Fatalf("expected closures to be reported, got %v", closure.Warnings)
+	}
+	if unresolved := getHandlerMetadata(42); len(unresolved.Warnings) != 1 {
+		t.Fatalf("expected a non-function handler to be reported, got %v", unresolved.Warnings)
+	}
+}
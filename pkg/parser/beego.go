@@ -0,0 +1,313 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// beegoRESTMethods are the controller methods Beego dispatches to by HTTP
+// method when a router has no mapping
+var beegoRESTMethods = []string{"Get", "Post", "Put", "Patch", "Delete", "Head", "Options"}
+
+// beegoRouterCalls are the Beego functions that register a function handler for one method
+var beegoRouterCalls = map[string]string{
+	"Get": http.MethodGet, "Post": http.MethodPost, "Put": http.MethodPut, "Patch": http.MethodPatch,
+	"Delete": http.MethodDelete, "Head": http.MethodHead, "Options": http.MethodOptions,
+}
+
+// beegoAdapter reads the routes of a Beego application from its router
+// source, since Beego cannot list its routes without being imported
+type beegoAdapter struct {
+	routersDir string
+}
+
+// ListRoutes returns the routes registered in the router source
+func (a beegoAdapter) ListRoutes() []FrameworkRoute {
+	routes, err := parseBeegoRouters(a.routersDir)
+	if err != nil {
+		fmt.Printf("⚠️  Could not read Beego routers in %s: %v\n", a.routersDir, err)
+	}
+	return routes
+}
+
+// ResolveHandler returns the controller method of a route, or nil for
+// function handlers, whose source is not analyzed
+func (a beegoAdapter) ResolveHandler(handler interface{}) interface{} {
+	if action, ok := handler.(controllerAction); ok {
+		return action
+	}
+	return nil
+}
+
+// AnalyzeHandler documents a controller method from its source
+func (a beegoAdapter) AnalyzeHandler(handler interface{}) core.RouteInfo {
+	return analyzeControllerAction(handler.(controllerAction))
+}
+
+// beegoRouterFile collects the routes of one router source file
+type beegoRouterFile struct {
+	dir        string
+	imports    map[string]string // import path by local name
+	beego      map[string]bool   // local names of the Beego packages
+	namespaces map[string]string // prefix of namespace variables
+	routes     []FrameworkRoute
+}
+
+// parseBeegoRouters reads the routes registered with Router, Include,
+// NewNamespace and its NS* options in the Go files of dir
+func parseBeegoRouters(dir string) ([]FrameworkRoute, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parseGoDir(fset, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []FrameworkRoute
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			router := &beegoRouterFile{
+				dir:        dir,
+				imports:    make(map[string]string),
+				beego:      make(map[string]bool),
+				namespaces: make(map[string]string),
+			}
+			for _, spec := range file.Imports {
+				path, _ := strconv.Unquote(spec.Path.Value)
+				name := filepath.Base(path)
+				if spec.Name != nil {
+					name = spec.Name.Name
+				}
+				router.imports[name] = path
+				if strings.Contains(path, "/beego") {
+					router.beego[name] = true
+				}
+			}
+			ast.Inspect(file, router.visit)
+			routes = append(routes, router.routes...)
+		}
+	}
+	return routes, nil
+}
+
+// visit registers the routes of top-level registrations; namespaces are
+// walked by namespace so their routes get the prefix
+func (b *beegoRouterFile) visit(n ast.Node) bool {
+	switch node := n.(type) {
+	case *ast.AssignStmt:
+		// ns := web.NewNamespace("/v1", ...)
+		if len(node.Lhs) == 1 && len(node.Rhs) == 1 {
+			if ident, ok := node.Lhs[0].(*ast.Ident); ok {
+				if call, ok := node.Rhs[0].(*ast.CallExpr); ok && b.beegoCall(call) == "NewNamespace" {
+					b.namespaces[ident.Name] = b.namespace("", call)
+					return false
+				}
+			}
+		}
+	case *ast.CallExpr:
+		sel, ok := node.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		name := b.beegoCall(node)
+		if name == "NewNamespace" {
+			b.namespace("", node)
+			return false
+		}
+		prefix := ""
+		if ident, ok := sel.X.(*ast.Ident); ok {
+			if namespace, ok := b.namespaces[ident.Name]; ok {
+				// ns.Router(...) on a namespace variable
+				name, prefix = sel.Sel.Name, namespace
+			}
+		}
+		switch name {
+		case "Router":
+			b.router(prefix, node.Args)
+		case "Include":
+			b.include(prefix, node.Args)
+		default:
+			if method, ok := beegoRouterCalls[name]; ok && len(node.Args) == 2 {
+				b.function(method, prefix, node.Args[0])
+			}
+		}
+	}
+	return true
+}
+
+// beegoCall returns the function name of a call to a Beego package function, or ""
+func (b *beegoRouterFile) beegoCall(call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	if ident, ok := sel.X.(*ast.Ident); ok && b.beego[ident.Name] {
+		return sel.Sel.Name
+	}
+	return ""
+}
+
+// namespace registers the routes of NewNamespace or NSNamespace and returns its prefix
+func (b *beegoRouterFile) namespace(parent string, call *ast.CallExpr) string {
+	if len(call.Args) == 0 {
+		return parent
+	}
+	prefix := parent + literalKeyToString(call.Args[0])
+	for _, arg := range call.Args[1:] {
+		option, ok := arg.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		switch name := b.beegoCall(option); name {
+		case "NSNamespace":
+			b.namespace(prefix, option)
+		case "NSRouter":
+			b.router(prefix, option.Args)
+		case "NSInclude":
+			b.include(prefix, option.Args)
+		default:
+			if method, ok := beegoRouterCalls[strings.TrimPrefix(name, "NS")]; ok && strings.HasPrefix(name, "NS") && len(option.Args) == 2 {
+				b.function(method, prefix, option.Args[0])
+			}
+		}
+	}
+	return prefix
+}
+
+// router registers Router(path, &Controller{}, "get:List;post:Create"); without
+// a mapping the controller's Get, Post, ... methods handle their HTTP method
+func (b *beegoRouterFile) router(prefix string, args []ast.Expr) {
+	if len(args) < 2 {
+		return
+	}
+	path := beegoPath(prefix + literalKeyToString(args[0]))
+	dir, controller, ok := b.controller(args[1])
+	if !ok {
+		return
+	}
+
+	if len(args) < 3 {
+		defined := make(map[string]bool)
+		for _, method := range controllerMethods(beegoControllers, dir, controller) {
+			defined[method.funcName] = true
+		}
+		for _, method := range beegoRESTMethods {
+			if defined[method] {
+				b.add(strings.ToUpper(method), path, controllerAction{framework: beegoControllers, dir: dir, controller: controller, method: method, path: path})
+			}
+		}
+		return
+	}
+
+	for _, mapping := range strings.Split(literalKeyToString(args[2]), ";") {
+		methods, action, found := strings.Cut(mapping, ":")
+		if !found {
+			continue
+		}
+		for _, method := range strings.Split(methods, ",") {
+			method = strings.ToUpper(strings.TrimSpace(method))
+			if method == "*" {
+				fmt.Printf("⏭️  Skipping any-method route: %s\n", path)
+				continue
+			}
+			b.add(method, path, controllerAction{framework: beegoControllers, dir: dir, controller: controller, method: strings.TrimSpace(action), path: path})
+		}
+	}
+}
+
+// include registers the "@router" comments of the controller methods
+func (b *beegoRouterFile) include(prefix string, args []ast.Expr) {
+	for _, arg := range args {
+		dir, controller, ok := b.controller(arg)
+		if !ok {
+			continue
+		}
+		for _, method := range controllerMethods(beegoControllers, dir, controller) {
+			for _, route := range method.metadata.Routes {
+				path := beegoPath(prefix + route.Path)
+				for _, httpMethod := range route.Methods {
+					b.add(httpMethod, path, controllerAction{framework: beegoControllers, dir: dir, controller: controller, method: method.funcName, path: path})
+				}
+			}
+		}
+	}
+}
+
+// function registers a function handler such as NSGet("/health", healthCheck)
+func (b *beegoRouterFile) function(method, prefix string, path ast.Expr) {
+	b.add(method, beegoPath(prefix+literalKeyToString(path)), nil)
+}
+
+func (b *beegoRouterFile) add(method, path string, handler interface{}) {
+	b.routes = append(b.routes, FrameworkRoute{Method: method, Path: path, Handler: handler})
+}
+
+// controller resolves &controllers.UserController{} to the package directory and type
+func (b *beegoRouterFile) controller(expr ast.Expr) (dir, name string, ok bool) {
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return "", "", false
+	}
+	switch typ := lit.Type.(type) {
+	case *ast.Ident:
+		return b.dir, typ.Name, true
+	case *ast.SelectorExpr:
+		pkg, ok := typ.X.(*ast.Ident)
+		if !ok {
+			return "", "", false
+		}
+		dir, found := packageDirForPath(b.imports[pkg.Name])
+		if !found {
+			dir = filepath.Join(filepath.Dir(b.dir), pkg.Name)
+		}
+		return dir, typ.Sel.Name, true
+	}
+	return "", "", false
+}
+
+// beegoPath converts Beego parameters to {name}: ":id", ":id:int" and
+// ":id([0-9]+)" to "{id}". The catch-alls "*" and "*.*" become "*splat" and
+// "*path", which the docs document as wildcard parameters.
+func beegoPath(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if len(path) > 1 {
+		// "@router / [get]" in a namespace documents the namespace path
+		path = strings.TrimSuffix(path, "/")
+	}
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		switch {
+		case part == "*":
+			parts[i] = "*splat"
+		case part == "*.*":
+			parts[i] = "*path"
+		case strings.HasPrefix(part, ":"):
+			name := part[1:]
+			if end := strings.IndexAny(name, ":("); end >= 0 {
+				name = name[:end]
+			}
+			parts[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// SetupBeegoDocs sets up documentation for a Beego application from the
+// router source in routersDir, e.g. "routers". Beego is not imported, so
+// mount the returned APIDocs yourself:
+//
+//	web.Handler("/docs/*", docs)
+func SetupBeegoDocs(routersDir string, config *core.Config) *core.APIDocs {
+	return SetupFrameworkDocs(beegoAdapter{routersDir: routersDir}, config)
+}
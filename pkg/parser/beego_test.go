@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBeegoAdapter_ReadsNamespaceRoutes(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "routers", "router.go"), `package routers
+
+import (
+	"example.com/app/controllers"
+	"github.com/beego/beego/v2/server/web"
+)
+
+func init() {
+	ns := web.NewNamespace("/v1",
+		web.NSNamespace("/user",
+			web.NSInclude(&controllers.UserController{}),
+		),
+		web.NSRouter("/orders/:id:int", &controllers.OrderController{}, "get:GetOne;put,patch:Update"),
+		web.NSGet("/health", health),
+	)
+	web.AddNamespace(ns)
+	web.Router("/products", &controllers.ProductController{})
+}
+`)
+	writeTestFile(t, filepath.Join(dir, "controllers", "controllers.go"), `package controllers
+
+type User struct {
+	ID   int    `+"`json:\"id\"`"+`
+	Name string `+"`json:\"name\"`"+`
+}
+
+type UserController struct{ web.Controller }
+
+// @Title Get
+// @Description get user by uid
+// @Param uid path string true "The key for staticblock"
+// @router /:uid [get]
+func (u *UserController) Get() {
+	user := User{}
+	u.Data["json"] = user
+	u.ServeJSON()
+}
+
+// @Title CreateUser
+// @router / [post]
+func (u *UserController) Post() {
+	var user User
+	json.Unmarshal(u.Ctx.Input.RequestBody, &user)
+	u.Ctx.Output.SetStatus(201)
+	u.Data["json"] = map[string]string{"uid": "1"}
+	u.ServeJSON()
+}
+
+type OrderController struct{ web.Controller }
+
+func (o *OrderController) GetOne() {
+	o.CustomAbort(404, "order not found")
+}
+
+func (o *OrderController) Update() {}
+
+type ProductController struct{ web.Controller }
+
+func (p *ProductController) Get()  {}
+func (p *ProductController) Post() {}
+`)
+
+	routes := beegoAdapter{routersDir: filepath.Join(dir, "routers")}.ListRoutes()
+	byRoute := make(map[string]FrameworkRoute)
+	for _, route := range routes {
+		byRoute[route.Method+" "+route.Path] = route
+	}
+	for _, want := range []string{"GET /v1/user/{uid}", "POST /v1/user", "GET /v1/orders/{id}", "PUT /v1/orders/{id}", "PATCH /v1/orders/{id}", "GET /v1/health", "GET /products", "POST /products"} {
+		if _, ok := byRoute[want]; !ok {
+			t.Errorf("expected route %s, got %v", want, routes)
+		}
+	}
+	if len(routes) != 8 {
+		t.Fatalf("expected 8 routes, got %d: %v", len(routes), routes)
+	}
+
+	adapter := beegoAdapter{}
+	get := adapter.AnalyzeHandler(adapter.ResolveHandler(byRoute["GET /v1/user/{uid}"].Handler))
+	if get.Summary != "Get" || get.Description != "get user by uid" || len(get.Parameters) != 1 {
+		t.Errorf("expected the bee annotations, got %+v", get)
+	}
+	if response, ok := get.Responses["200"]; !ok || response.ContentType != "application/json" {
+		t.Errorf("expected c.Data[\"json\"] to be served as the 200 response, got %+v", get.Responses)
+	}
+
+	post := adapter.AnalyzeHandler(adapter.ResolveHandler(byRoute["POST /v1/user"].Handler))
+	if post.RequestBody == nil || post.RequestBody.ContentType != "application/json" {
+		t.Errorf("expected the unmarshalled request body, got %+v", post.RequestBody)
+	}
+	if _, ok := post.Responses["201"]; !ok {
+		t.Errorf("expected SetStatus to apply to the next response, got %+v", post.Responses)
+	}
+
+	order := adapter.AnalyzeHandler(adapter.ResolveHandler(byRoute["GET /v1/orders/{id}"].Handler))
+	if _, ok := order.Responses["404"]; !ok {
+		t.Errorf("expected CustomAbort to document a 404, got %+v", order.Responses)
+	}
+	if adapter.ResolveHandler(byRoute["GET /v1/health"].Handler) != nil {
+		t.Error("expected function handlers to stay unresolved")
+	}
+}
@@ -0,0 +1,542 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// controllerFramework selects the conventions of a controller-based framework
+// whose routes are read from source rather than from a running router
+type controllerFramework string
+
+const (
+	beegoControllers controllerFramework = "beego"
+	revelControllers controllerFramework = "revel"
+)
+
+// controllerAction is a controller method a Beego router or a Revel routes
+// file dispatches to. It is the handler of the routes of both adapters.
+type controllerAction struct {
+	framework  controllerFramework
+	dir        string // controllers package directory
+	controller string // receiver type
+	method     string
+	path       string // route path; Revel action arguments named in it are path parameters
+}
+
+// controllerRoute is a route declared on a controller method with a Beego
+// "@router <path> [methods]" comment
+type controllerRoute struct {
+	Path    string
+	Methods []string
+}
+
+// controllerHandlerMetadata stores extracted documentation data for a controller method.
+type controllerHandlerMetadata struct {
+	Info        StdlibHandlerInfo
+	Arguments   []core.Parameter // Revel action arguments, bound from the path, query or form
+	RequestBody *core.RequestBody
+	Responses   map[string]core.Response
+	Routes      []controllerRoute
+	Trace       []string
+}
+
+// controllerAnalyzedHandler keeps track of metadata for an individual controller method within a package.
+type controllerAnalyzedHandler struct {
+	filePath     string
+	funcName     string
+	receiverName string
+	startLine    int
+	metadata     controllerHandlerMetadata
+}
+
+func (h controllerAnalyzedHandler) sourceLocation() (string, string, int) {
+	return h.filePath, h.receiverName, h.startLine
+}
+
+// controllerPackageAnalysis caches the controller methods of a directory
+type controllerPackageAnalysis struct {
+	handlers map[string][]controllerAnalyzedHandler
+}
+
+// Beego and Revel controllers follow different conventions, so analyses are cached per framework and directory
+var (
+	controllerAnalysisCache = make(map[string]*controllerPackageAnalysis)
+	controllerAnalysisMutex sync.RWMutex
+)
+
+// lookupControllerAction returns the metadata of the controller method of action
+func lookupControllerAction(action controllerAction) (controllerHandlerMetadata, []string) {
+	ref := handlerRef{funcName: action.method, receiverName: action.controller, dir: action.dir}
+	packageMeta := loadControllerPackageAnalysis(action.framework, action.dir)
+	if packageMeta == nil {
+		return controllerHandlerMetadata{}, packageWarnings(ref)
+	}
+	if candidate, ok := selectHandler(packageMeta.handlers[strings.ToLower(action.method)], ref); ok {
+		return candidate.metadata, nil
+	}
+	return controllerHandlerMetadata{}, handlerNotFoundWarnings(ref)
+}
+
+// controllerMethods returns the analyzed methods of controller in dir in source order
+func controllerMethods(framework controllerFramework, dir, controller string) []controllerAnalyzedHandler {
+	packageMeta := loadControllerPackageAnalysis(framework, dir)
+	if packageMeta == nil {
+		return nil
+	}
+	var methods []controllerAnalyzedHandler
+	for _, candidates := range packageMeta.handlers {
+		for _, candidate := range candidates {
+			if candidate.receiverName == controller {
+				methods = append(methods, candidate)
+			}
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		if methods[i].filePath != methods[j].filePath {
+			return methods[i].filePath < methods[j].filePath
+		}
+		return methods[i].startLine < methods[j].startLine
+	})
+	return methods
+}
+
+// loadControllerPackageAnalysis parses and caches metadata for all controller methods within a directory.
+func loadControllerPackageAnalysis(framework controllerFramework, dir string) *controllerPackageAnalysis {
+	key := analysisKey(string(framework) + ":" + dir)
+
+	controllerAnalysisMutex.RLock()
+	if cached, ok := controllerAnalysisCache[key]; ok {
+		controllerAnalysisMutex.RUnlock()
+		return cached
+	}
+	controllerAnalysisMutex.RUnlock()
+
+	controllerAnalysisMutex.Lock()
+	defer controllerAnalysisMutex.Unlock()
+
+	if cached, ok := controllerAnalysisCache[key]; ok {
+		return cached
+	}
+
+	fset := token.NewFileSet()
+	pkgs, err := parseGoDir(fset, dir)
+	if err != nil {
+		recordAnalysisFailure(dir, err)
+		controllerAnalysisCache[key] = nil
+		return nil
+	}
+
+	structs := collectStructDefinitions(pkgs)
+	functions := collectFunctionSignatures(pkgs)
+	pkgAnalysis := &controllerPackageAnalysis{
+		handlers: collectControllerHandlerMetadata(framework, fset, pkgs, structs, functions),
+	}
+	controllerAnalysisCache[key] = pkgAnalysis
+	return pkgAnalysis
+}
+
+// collectControllerHandlerMetadata extracts documentation metadata for every method declaration.
+func collectControllerHandlerMetadata(framework controllerFramework, fset *token.FileSet, pkgs map[string]*ast.Package, structs map[string]*ast.StructType, functions map[string][]functionSignature) map[string][]controllerAnalyzedHandler {
+	return collectPerFile(pkgs, func(file *ast.File, handlers map[string][]controllerAnalyzedHandler) {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || !fn.Name.IsExported() {
+				continue
+			}
+
+			var comments []string
+			if fn.Doc != nil {
+				comments = extractCommentsText(fn.Doc.List)
+			}
+			metadata := analyzeControllerMethod(framework, fn, structs, functions)
+			metadata.Info, metadata.Routes = parseControllerComments(comments)
+
+			pos := fset.Position(fn.Pos())
+			key := strings.ToLower(fn.Name.Name)
+			handlers[key] = append(handlers[key], controllerAnalyzedHandler{
+				filePath:     pos.Filename,
+				funcName:     fn.Name.Name,
+				receiverName: receiverDeclName(fn.Recv),
+				startLine:    pos.Line,
+				metadata:     metadata,
+			})
+		}
+	})
+}
+
+// parseControllerComments parses the usual annotations plus the Beego
+// "@Title", "@Description" and "@router" comments generated by bee
+func parseControllerComments(comments []string) (StdlibHandlerInfo, []controllerRoute) {
+	info := parseStdlibHandlerInfo(comments)
+	var routes []controllerRoute
+	for _, line := range comments {
+		switch {
+		case strings.HasPrefix(line, "@Title "):
+			if info.Summary == "" {
+				info.Summary = strings.TrimSpace(strings.TrimPrefix(line, "@Title "))
+			}
+		case strings.HasPrefix(line, "@Description "):
+			if info.Description == "" {
+				info.Description = strings.TrimSpace(strings.TrimPrefix(line, "@Description "))
+			}
+		case strings.HasPrefix(line, "@router "):
+			// @router /:uid [get,post]
+			fields := strings.Fields(strings.TrimPrefix(line, "@router "))
+			if len(fields) == 0 {
+				continue
+			}
+			route := controllerRoute{Path: fields[0], Methods: []string{"GET"}}
+			if len(fields) > 1 {
+				route.Methods = nil
+				for _, method := range strings.Split(strings.Trim(fields[1], "[]"), ",") {
+					if method = strings.TrimSpace(method); method != "" {
+						route.Methods = append(route.Methods, strings.ToUpper(method))
+					}
+				}
+			}
+			routes = append(routes, route)
+		}
+	}
+	return info, routes
+}
+
+// analyzeControllerMethod inspects a Beego or Revel controller method to infer request bodies and responses.
+func analyzeControllerMethod(framework controllerFramework, fn *ast.FuncDecl, structs map[string]*ast.StructType, functions map[string][]functionSignature) controllerHandlerMetadata {
+	metadata := controllerHandlerMetadata{
+		Responses: make(map[string]core.Response),
+	}
+	if framework == revelControllers {
+		metadata.Arguments = revelActionArguments(fn)
+	}
+	if fn.Body == nil {
+		return metadata
+	}
+
+	ctx := &analysisContext{
+		structs:   structs,
+		functions: functions,
+		variables: make(map[string]ast.Expr),
+		values:    make(map[string]ast.Expr),
+		trace:     newHandlerTrace(),
+	}
+	registerFuncParamTypes(fn, ctx)
+
+	// Both frameworks set the status and, for Beego, the payload before the call that writes the response
+	var status ast.Expr
+	data := make(map[string]ast.Expr)
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.DeclStmt:
+			registerDeclarationTypes(node, ctx)
+		case *ast.AssignStmt:
+			registerAssignmentTypes(node, ctx)
+			for i, lhs := range node.Lhs {
+				if i >= len(node.Rhs) {
+					break
+				}
+				if key, ok := beegoDataKey(lhs); ok {
+					data[key] = node.Rhs[i]
+				} else if isRevelStatusField(lhs) {
+					status = node.Rhs[i]
+				}
+			}
+		case *ast.RangeStmt:
+			registerRangeTypes(node, ctx)
+		case *ast.CallExpr:
+			if arg, ok := controllerBindingArg(framework, node); ok && metadata.RequestBody == nil {
+				resolved := buildRequestBodyFromExpr(resolveTypeFromArg(arg, ctx), ctx)
+				if resolved != nil {
+					resolved.ContentType = controllerBindingContentType(node)
+					metadata.RequestBody = resolved
+				}
+				ctx.traceBinding(node, resolved)
+			}
+
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "SetStatus" && len(node.Args) == 1 {
+				status = node.Args[0]
+				return true
+			}
+
+			contentType, statusExpr, dataExpr, ok := errorCallInfo(node, ctx)
+			if !ok {
+				if framework == beegoControllers {
+					contentType, statusExpr, dataExpr, ok = beegoResponseCallInfo(node, data)
+				} else {
+					contentType, statusExpr, dataExpr, ok = revelResponseCallInfo(node)
+				}
+				if ok && statusExpr == nil {
+					statusExpr = status
+				}
+			}
+			if ok {
+				addControllerResponse(&metadata, node, contentType, statusExpr, dataExpr, ctx)
+				status = nil
+			}
+		}
+		return true
+	})
+
+	metadata.Trace = ctx.trace.finish(metadata.RequestBody != nil, len(metadata.Responses))
+	return metadata
+}
+
+// addControllerResponse documents the response written by call
+func addControllerResponse(metadata *controllerHandlerMetadata, call *ast.CallExpr, contentType string, statusExpr, dataExpr ast.Expr, ctx *analysisContext) {
+	statusCode := extractStatusCode(statusExpr, ctx)
+	if statusCode == "" {
+		statusCode = "200"
+	}
+	payloadExpr := resolveResponsePayloadExpr(errorPayloadExpr(statusCode, dataExpr, ctx), ctx)
+	schema, example := buildSchemaFromExpr(payloadExpr, ctx, make(map[string]bool))
+	example = normalizeExampleWithSchema(schema, example)
+	if example == nil {
+		example = defaultExampleFromSchema(schema)
+	}
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	response := core.Response{
+		Description: statusTextFromCode(statusCode),
+		Example:     example,
+		Schema:      schema,
+		ContentType: contentType,
+	}
+	if response.Description == "" {
+		response.Description = "Response"
+	}
+	metadata.Responses[statusCode] = response
+	ctx.traceResponse(call, statusExpr, statusCode, payloadExpr, schema)
+}
+
+// controllerBindingArg returns the target of a request body binding:
+// c.BindJSON(&req), c.Bind(&req), c.ParseForm(&req) and
+// json.Unmarshal(c.Ctx.Input.RequestBody, &req) in Beego,
+// c.Params.BindJSON(&req) in Revel
+func controllerBindingArg(framework controllerFramework, call *ast.CallExpr) (ast.Expr, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) == 0 {
+		return nil, false
+	}
+	switch sel.Sel.Name {
+	case "BindJSON":
+		return call.Args[0], true
+	case "Bind", "BindXML", "BindYAML", "BindForm", "ParseForm":
+		return call.Args[0], framework == beegoControllers
+	case "Unmarshal":
+		if len(call.Args) == 2 && strings.HasSuffix(exprToString(call.Args[0]), "RequestBody") {
+			return call.Args[1], true
+		}
+	}
+	return nil, false
+}
+
+// controllerBindingContentType returns the content type a binding call reads
+func controllerBindingContentType(call *ast.CallExpr) string {
+	switch call.Fun.(*ast.SelectorExpr).Sel.Name {
+	case "BindXML":
+		return "application/xml"
+	case "BindYAML":
+		return "application/yaml"
+	case "BindForm", "ParseForm":
+		return "application/x-www-form-urlencoded"
+	}
+	return "application/json"
+}
+
+// beegoDataKey reports whether expr is c.Data["json"] or another entry of
+// the data a Beego controller serves, and returns the key
+func beegoDataKey(expr ast.Expr) (string, bool) {
+	index, ok := expr.(*ast.IndexExpr)
+	if !ok {
+		return "", false
+	}
+	sel, ok := index.X.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Data" {
+		return "", false
+	}
+	key := literalKeyToString(index.Index)
+	return key, key != ""
+}
+
+// beegoResponseCallInfo detects Beego responses: ServeJSON and friends serve
+// the matching c.Data entry, c.Ctx.Output.JSON its argument
+func beegoResponseCallInfo(call *ast.CallExpr, data map[string]ast.Expr) (contentType string, statusExpr ast.Expr, dataExpr ast.Expr, ok bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", nil, nil, false
+	}
+
+	switch sel.Sel.Name {
+	case "ServeJSON", "ServeJSONP":
+		if payload, ok := data["json"]; ok {
+			return "application/json", nil, payload, true
+		}
+		if payload, ok := data["jsonp"]; ok {
+			return "application/javascript", nil, payload, true
+		}
+	case "ServeXML":
+		if payload, ok := data["xml"]; ok {
+			return "application/xml", nil, payload, true
+		}
+	case "ServeYAML":
+		if payload, ok := data["yaml"]; ok {
+			return "application/yaml", nil, payload, true
+		}
+	case "JSON", "JSONResp":
+		if len(call.Args) >= 1 {
+			return "application/json", nil, call.Args[0], true
+		}
+	case "XML", "XMLResp":
+		if len(call.Args) >= 1 {
+			return "application/xml", nil, call.Args[0], true
+		}
+	case "WriteString":
+		if len(call.Args) == 1 {
+			return "text/plain", nil, call.Args[0], true
+		}
+	case "CustomAbort":
+		if len(call.Args) == 2 {
+			return "text/plain", call.Args[0], call.Args[1], true
+		}
+	case "Abort":
+		// c.Abort("404") renders the registered error page of the status
+		if len(call.Args) == 1 {
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				code := strings.Trim(lit.Value, "\"`")
+				if _, err := strconv.Atoi(code); err == nil {
+					return "text/html", &ast.BasicLit{Kind: token.INT, Value: code}, &ast.BasicLit{Kind: token.STRING, Value: `""`}, true
+				}
+			}
+		}
+	}
+	return "", nil, nil, false
+}
+
+// isRevelStatusField reports whether expr is c.Response.Status
+func isRevelStatusField(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Status" {
+		return false
+	}
+	inner, ok := sel.X.(*ast.SelectorExpr)
+	return ok && inner.Sel.Name == "Response"
+}
+
+// revelResponseCallInfo detects the results a Revel action returns
+func revelResponseCallInfo(call *ast.CallExpr) (contentType string, statusExpr ast.Expr, dataExpr ast.Expr, ok bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", nil, nil, false
+	}
+
+	empty := &ast.BasicLit{Kind: token.STRING, Value: `""`}
+	switch sel.Sel.Name {
+	case "RenderJSON":
+		if len(call.Args) == 1 {
+			return "application/json", nil, call.Args[0], true
+		}
+	case "RenderJSONP":
+		if len(call.Args) == 2 {
+			return "application/javascript", nil, call.Args[1], true
+		}
+	case "RenderXML":
+		if len(call.Args) == 1 {
+			return "application/xml", nil, call.Args[0], true
+		}
+	case "RenderText":
+		if len(call.Args) >= 1 {
+			return "text/plain", nil, call.Args[0], true
+		}
+	case "RenderHTML":
+		if len(call.Args) == 1 {
+			return "text/html", nil, call.Args[0], true
+		}
+	case "NotFound":
+		return "text/html", &ast.BasicLit{Kind: token.INT, Value: "404"}, empty, true
+	case "Forbidden":
+		return "text/html", &ast.BasicLit{Kind: token.INT, Value: "403"}, empty, true
+	case "RenderError":
+		return "text/html", &ast.BasicLit{Kind: token.INT, Value: "500"}, empty, true
+	}
+	return "", nil, nil, false
+}
+
+// revelActionArguments documents the arguments of a Revel action, which
+// Revel binds by name from the path, query string or form
+func revelActionArguments(fn *ast.FuncDecl) []core.Parameter {
+	var params []core.Parameter
+	if fn.Type.Params == nil {
+		return params
+	}
+	for _, field := range fn.Type.Params.List {
+		ident, ok := field.Type.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		schema, _ := primitiveSchemaForIdent(ident.Name)
+		if schema == nil {
+			continue
+		}
+		for _, name := range field.Names {
+			params = append(params, core.Parameter{Name: name.Name, In: "query", Type: schema["type"].(string)})
+		}
+	}
+	return params
+}
+
+// analyzeControllerAction documents the controller method of action
+func analyzeControllerAction(action controllerAction) core.RouteInfo {
+	metadata, warnings := lookupControllerAction(action)
+
+	params := metadata.Info.Parameters
+	pathParams := make(map[string]bool)
+	for _, name := range routePathParams(action.path) {
+		pathParams[name] = true
+	}
+	for _, arg := range metadata.Arguments {
+		if pathParams[arg.Name] {
+			arg.In = "path"
+			arg.Required = true
+		}
+		params = appendParameters(params, arg)
+	}
+
+	return core.RouteInfo{
+		Summary:      metadata.Info.Summary,
+		Description:  metadata.Info.Description,
+		Parameters:   params,
+		Section:      metadata.Info.Section,
+		ExternalDocs: metadata.Info.ExternalDocs,
+		Presets:      metadata.Info.Presets,
+		SLA:          metadata.Info.SLA,
+		Pagination:   metadata.Info.Pagination,
+		Accepts:      metadata.Info.Accepts,
+		ResponseDocs: metadata.Info.ResponseDocs,
+		Extensions:   metadata.Info.Extensions,
+		RequestBody:  metadata.RequestBody,
+		Responses:    metadata.Responses,
+		Warnings:     warnings,
+		Trace:        metadata.Trace,
+	}
+}
+
+// routePathParams returns the names of the {name} and *name parameters of a path
+func routePathParams(path string) []string {
+	var names []string
+	for _, part := range strings.Split(path, "/") {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			names = append(names, strings.Trim(part, "{}"))
+		} else if strings.HasPrefix(part, "*") && len(part) > 1 {
+			names = append(names, part[1:])
+		}
+	}
+	return names
+}
@@ -0,0 +1,21 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// docsAnnotation returns the external docs of a "@Docs <url> [description]"
+// comment line, shown as a "Read more" link on the handler's endpoints
+func docsAnnotation(line string) (*core.ExternalDocs, bool) {
+	rest, found := strings.CutPrefix(line, "@Docs")
+	if !found || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return nil, false
+	}
+	url, description, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	if url == "" {
+		return nil, false
+	}
+	return &core.ExternalDocs{URL: url, Description: strings.TrimSpace(description)}, true
+}
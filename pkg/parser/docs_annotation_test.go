@@ -0,0 +1,28 @@
+package parser
+
+import "testing"
+
+func TestDocsAnnotationSetsExternalDocs(t *testing.T) {
+	comments := []string{
+		"CreateRefund refunds an order",
+		"@Docs https://wiki.example.com/refunds Refund policy",
+		`@Param id path string true "Order ID"`,
+	}
+
+	info := parseHandlerInfo(comments)
+	if info.ExternalDocs == nil || info.ExternalDocs.URL != "https://wiki.example.com/refunds" || info.ExternalDocs.Description != "Refund policy" {
+		t.Fatalf("expected external docs with URL and description, got %+v", info.ExternalDocs)
+	}
+	if info.Summary != "CreateRefund refunds an order" || len(info.Parameters) != 1 {
+		t.Fatalf("@Docs must not disturb summary or params, got %+v", info)
+	}
+	if stdlibInfo := parseStdlibHandlerInfo([]string{"@Docs https://wiki.example.com/refunds"}); stdlibInfo.ExternalDocs == nil || stdlibInfo.ExternalDocs.Description != "" {
+		t.Fatalf("expected stdlib handlers to read @Docs without description, got %+v", stdlibInfo.ExternalDocs)
+	}
+
+	for _, line := range []string{"@Docsify https://x", "@Docs", "@Docs   "} {
+		if docs, ok := docsAnnotation(line); ok {
+			t.Fatalf("%q must not be a docs annotation, got %+v", line, docs)
+		}
+	}
+}
@@ -9,29 +9,25 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/idnexacloud/bytedocs-go/pkg/core"
 )
 
-// Global registry for Echo route tracking
-var (
-	globalEchoDocs   *core.APIDocs
-	echoDocsConfig   *core.Config
-	echoDocsMutex    sync.RWMutex
-	echoFuncComments map[string][]string
-)
-
-func init() {
-	echoFuncComments = make(map[string][]string)
-}
-
 // EchoHandlerInfo holds parsed comment information for Echo handlers
 type EchoHandlerInfo struct {
-	Summary     string
-	Description string
-	Parameters  []core.Parameter
+	Summary      string
+	Description  string
+	Parameters   []core.Parameter
+	Section      string             // from "@Section <id>"
+	ExternalDocs *core.ExternalDocs // from "@Docs <url> [description]"
+	Presets      []core.Preset      // from "@Preset <name> [param=value ...] [json body]"
+	SLA          time.Duration      // from "@SLA <duration>"
+	Pagination   string             // from "@Paginate <style>"
+	Accepts      []string           // from "@Accept <content types>"
+	ResponseDocs map[string]string  // from "@Response <status> <description>"
+	Extensions   core.Extensions    // from "@x-<name> [value]"
 }
 
 // parseEchoHandlerComments parses Go source files to extract Echo handler comments
@@ -76,6 +72,44 @@ func parseEchoHandlerInfo(comments []string) EchoHandlerInfo {
 	paramRegex := regexp.MustCompile(`@Param\s+(\w+)\s+(\w+)\s+(\w+)\s+(true|false)\s+"([^"]*)"`)
 
 	for _, line := range comments {
+		if section, ok := sectionAnnotation(line); ok {
+			info.Section = section
+			continue
+		}
+		if externalDocs, ok := docsAnnotation(line); ok {
+			info.ExternalDocs = externalDocs
+			continue
+		}
+		if preset, ok := presetAnnotation(line); ok {
+			info.Presets = append(info.Presets, preset)
+			continue
+		}
+		if sla, ok := slaAnnotation(line); ok {
+			info.SLA = sla
+			continue
+		}
+		if style, ok := paginateAnnotation(line); ok {
+			info.Pagination = style
+			continue
+		}
+		if accepts, ok := acceptAnnotation(line); ok {
+			info.Accepts = append(info.Accepts, accepts...)
+			continue
+		}
+		if status, description, ok := responseAnnotation(line); ok {
+			if info.ResponseDocs == nil {
+				info.ResponseDocs = make(map[string]string)
+			}
+			info.ResponseDocs[status] = description
+			continue
+		}
+		if name, value, ok := extensionAnnotation(line); ok {
+			if info.Extensions == nil {
+				info.Extensions = make(core.Extensions)
+			}
+			info.Extensions[name] = value
+			continue
+		}
 		// Parse @Param annotations
 		if matches := paramRegex.FindStringSubmatch(line); len(matches) == 6 {
 			param := core.Parameter{
@@ -148,8 +182,9 @@ func getEchoRoutes(e *echo.Echo) []EchoRoute {
 }
 
 
-// SetupEchoDocs sets up documentation for an Echo instance with auto-detection
-func SetupEchoDocs(e *echo.Echo, config *core.Config) {
+// SetupEchoDocs sets up documentation for an Echo instance with auto-detection.
+// The returned APIDocs can be used to customize the generated documentation
+func SetupEchoDocs(e *echo.Echo, config *core.Config) *core.APIDocs {
 	if config == nil {
 		config = &core.Config{
 			Title:      "API Documentation",
@@ -159,25 +194,20 @@ func SetupEchoDocs(e *echo.Echo, config *core.Config) {
 		}
 	}
 
-	echoDocsMutex.Lock()
-	echoDocsConfig = config
-	globalEchoDocs = core.New(config)
-	echoDocsMutex.Unlock()
+	analysis := newAnalysisSettings(config)
 
-	// Set up the docs route that does auto-detection
-	docsHandler := func(c echo.Context) error {
-		echoDocsMutex.Lock()
-		defer echoDocsMutex.Unlock()
+	docs := core.New(config)
 
-		endpointsCount := len(globalEchoDocs.GetDocumentation().Endpoints)
+	// Refresh analyzes the handlers from source again
+	docs.OnRefresh(analysis.resetCaches)
 
-		if endpointsCount == 0 && config.AutoDetect {
+	// Routes are detected on Warmup or the first docs request, and again on Refresh
+	if config.AutoDetect {
+		docs.SetRouteDetector(analysis.scoped(func() {
 			routes := getEchoRoutes(e)
 
 			for _, route := range routes {
-				if strings.HasPrefix(route.Path, config.DocsPath) ||
-					strings.Contains(route.Path, "/static") ||
-					strings.Contains(route.Path, "/assets") {
+				if strings.HasPrefix(route.Path, config.DocsPath) {
 					continue
 				}
 
@@ -186,13 +216,13 @@ func SetupEchoDocs(e *echo.Echo, config *core.Config) {
 
 				if strings.Contains(route.Name, ".") {
 					parts := strings.Split(route.Name, ".")
-					funcName = parts[len(parts)-1]
+					funcName = strings.TrimSuffix(parts[len(parts)-1], methodValueSuffix)
 				} else {
 					funcName = route.Name
 				}
 
 				if funcName != "" {
-					metadata = getEchoHandlerMetadataByName(funcName, ".")
+					metadata = getEchoHandlerMetadataByName(route.Name, ".")
 				}
 
 				if metadata.Info.Summary == "" && metadata.Info.Description == "" {
@@ -203,29 +233,48 @@ func SetupEchoDocs(e *echo.Echo, config *core.Config) {
 				}
 
 				routeInfo := core.RouteInfo{
-					Method:      route.Method,
-					Path:        route.Path,
-					Handler:     nil,
-					Summary:     metadata.Info.Summary,
-					Description: metadata.Info.Description,
-					Parameters:  metadata.Info.Parameters,
-					RequestBody: metadata.RequestBody,
-					Responses:   metadata.Responses,
+					Method:       route.Method,
+					Path:         route.Path,
+					Handler:      nil,
+					Summary:      metadata.Info.Summary,
+					Description:  metadata.Info.Description,
+					Parameters:   metadata.Info.Parameters,
+					Section:      metadata.Info.Section,
+					ExternalDocs: metadata.Info.ExternalDocs,
+					Presets:      metadata.Info.Presets,
+					SLA:          metadata.Info.SLA,
+					Pagination:   metadata.Info.Pagination,
+					Accepts:      metadata.Info.Accepts,
+					ResponseDocs: metadata.Info.ResponseDocs,
+					Extensions:   metadata.Info.Extensions,
+					RequestBody:  metadata.RequestBody,
+					Responses:    metadata.Responses,
+					Warnings:     metadata.Warnings,
+					Trace:        metadata.Trace,
 				}
 
-				globalEchoDocs.AddRouteInfo(routeInfo)
+				docs.AddRouteInfo(routeInfo)
 			}
 
-			globalEchoDocs.Generate()
-		}
+			docs.Generate()
+		}))
+	}
+
+	if serveDocsSeparately(docs, config) {
+		return docs
+	}
 
-		globalEchoDocs.ServeHTTP(c.Response().Writer, c.Request())
+	// Set up the docs route that does auto-detection
+	docsHandler := func(c echo.Context) error {
+		docs.ServeHTTP(c.Response().Writer, c.Request())
 		return nil
 	}
 
 	// Register the docs routes
 	e.Any(config.DocsPath, docsHandler)
 	e.Any(config.DocsPath+"/*path", docsHandler)
+
+	return docs
 }
 
 // EchoMiddleware creates Echo middleware for automatic route documentation
@@ -2,9 +2,7 @@ package parser
 
 import (
 	"go/ast"
-	"go/parser"
 	"go/token"
-	"io/fs"
 	"strings"
 	"sync"
 
@@ -16,6 +14,8 @@ type EchoHandlerMetadata struct {
 	Info        EchoHandlerInfo
 	RequestBody *core.RequestBody
 	Responses   map[string]core.Response
+	Warnings    []string `json:"-"` // why no schemas were inferred; not cached
+	Trace       []string `json:"-"` // analysis steps when debugging; not cached
 }
 
 // echoAnalyzedHandler keeps track of metadata for an individual Echo handler within a package.
@@ -38,30 +38,30 @@ var (
 	echoAnalysisMutex sync.RWMutex
 )
 
-// getEchoHandlerMetadataByName gets handler metadata by analyzing the function name from parsed files
-func getEchoHandlerMetadataByName(funcName string, dir string) EchoHandlerMetadata {
-	packageMeta := loadEchoPackageAnalysis(dir)
-	if packageMeta == nil {
-		return EchoHandlerMetadata{}
-	}
+// getEchoHandlerMetadataByName gets handler metadata for a route name. Echo names
+// routes after the handler's runtime symbol, e.g. "main.(*UserHandler).List-fm",
+// which identifies the receiver and package; bare names fall back to dir.
+func getEchoHandlerMetadataByName(name string, dir string) EchoHandlerMetadata {
+	ref := handlerRefFromName(name, dir)
 
-	key := strings.ToLower(funcName)
-	candidates := packageMeta.handlers[key]
-	if len(candidates) == 0 {
-		return EchoHandlerMetadata{}
+	packageMeta := loadEchoPackageAnalysis(ref.dir)
+	if packageMeta == nil {
+		return EchoHandlerMetadata{Warnings: packageWarnings(ref)}
 	}
 
-	if len(candidates) > 0 {
-		return candidates[0].metadata
+	if candidate, ok := selectHandler(packageMeta.handlers[strings.ToLower(ref.funcName)], ref); ok {
+		return candidate.metadata
 	}
 
-	return EchoHandlerMetadata{}
+	return EchoHandlerMetadata{Warnings: handlerNotFoundWarnings(ref)}
 }
 
 // loadEchoPackageAnalysis parses and caches metadata for all Echo handlers within a directory.
 func loadEchoPackageAnalysis(dir string) *echoPackageAnalysis {
+	key := analysisKey(dir)
+
 	echoAnalysisMutex.RLock()
-	if cached, ok := echoAnalysisCache[dir]; ok {
+	if cached, ok := echoAnalysisCache[key]; ok {
 		echoAnalysisMutex.RUnlock()
 		return cached
 	}
@@ -70,41 +70,70 @@ func loadEchoPackageAnalysis(dir string) *echoPackageAnalysis {
 	echoAnalysisMutex.Lock()
 	defer echoAnalysisMutex.Unlock()
 
-	if cached, ok := echoAnalysisCache[dir]; ok {
+	if cached, ok := echoAnalysisCache[key]; ok {
 		return cached
 	}
 
-	pkgAnalysis, err := analyzeEchoDirectory(dir)
+	pkgAnalysis, err := analyzeEchoDirectoryCached(dir)
 	if err != nil {
-		// Silently ignore analysis errors to avoid breaking docs generation.
-		echoAnalysisCache[dir] = nil
+		recordAnalysisFailure(dir, err)
+		echoAnalysisCache[key] = nil
 		return nil
 	}
 
-	echoAnalysisCache[dir] = pkgAnalysis
+	echoAnalysisCache[key] = pkgAnalysis
 	return pkgAnalysis
 }
 
+// analyzeEchoDirectoryCached runs analyzeEchoDirectory through the persistent analysis cache when it is enabled.
+func analyzeEchoDirectoryCached(dir string) (*echoPackageAnalysis, error) {
+	if getAnalysisCacheDir() == "" {
+		return analyzeEchoDirectory(dir, nil)
+	}
+
+	handlers, err := withAnalysisCache("echo", dir, func(only map[string]bool) (map[string][]handlerCacheEntry[EchoHandlerMetadata], error) {
+		pkgAnalysis, err := analyzeEchoDirectory(dir, only)
+		if err != nil {
+			return nil, err
+		}
+		return convertHandlers(pkgAnalysis.handlers, func(h echoAnalyzedHandler) handlerCacheEntry[EchoHandlerMetadata] {
+			return handlerCacheEntry[EchoHandlerMetadata]{
+				FilePath:     h.filePath,
+				FuncName:     h.funcName,
+				ReceiverName: h.receiverName,
+				StartLine:    h.startLine,
+				Metadata:     h.metadata,
+			}
+		}), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &echoPackageAnalysis{
+		handlers: convertHandlers(handlers, func(e handlerCacheEntry[EchoHandlerMetadata]) echoAnalyzedHandler {
+			return echoAnalyzedHandler{
+				filePath:     e.FilePath,
+				funcName:     e.FuncName,
+				receiverName: e.ReceiverName,
+				startLine:    e.StartLine,
+				metadata:     e.Metadata,
+			}
+		}),
+	}, nil
+}
+
 // analyzeEchoDirectory walks all Go files in a directory to extract Echo handler metadata.
-func analyzeEchoDirectory(dir string) (*echoPackageAnalysis, error) {
+func analyzeEchoDirectory(dir string, only map[string]bool) (*echoPackageAnalysis, error) {
 	fset := token.NewFileSet()
-	pkgs, err := parser.ParseDir(fset, dir, func(info fs.FileInfo) bool {
-		if info.IsDir() {
-			return false
-		}
-		name := info.Name()
-		if !strings.HasSuffix(name, ".go") {
-			return false
-		}
-		return !strings.HasSuffix(name, "_test.go")
-	}, parser.ParseComments)
+	pkgs, err := parseGoDir(fset, dir)
 	if err != nil {
 		return nil, err
 	}
 
 	structs := collectStructDefinitions(pkgs)
 	functions := collectFunctionSignatures(pkgs)
-	handlers := collectEchoHandlerMetadata(fset, pkgs, structs, functions)
+	handlers := collectEchoHandlerMetadata(fset, restrictPackageFiles(pkgs, only), structs, functions)
 
 	return &echoPackageAnalysis{
 		handlers:  handlers,
@@ -114,51 +143,48 @@ func analyzeEchoDirectory(dir string) (*echoPackageAnalysis, error) {
 
 // collectEchoHandlerMetadata extracts documentation metadata for Echo function declarations.
 func collectEchoHandlerMetadata(fset *token.FileSet, pkgs map[string]*ast.Package, structs map[string]*ast.StructType, functions map[string][]functionSignature) map[string][]echoAnalyzedHandler {
-	handlers := make(map[string][]echoAnalyzedHandler)
-
-	for _, pkg := range pkgs {
-		for _, file := range pkg.Files {
-			for _, decl := range file.Decls {
-				fn, ok := decl.(*ast.FuncDecl)
-				if !ok {
-					continue
-				}
-
-				// Check if this is likely an Echo handler (has echo.Context parameter)
-				if !isEchoHandler(fn) {
-					continue
-				}
+	return collectPerFile(pkgs, func(file *ast.File, handlers map[string][]echoAnalyzedHandler) {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
 
-				var comments []string
-				if fn.Doc != nil {
-					comments = extractCommentsText(fn.Doc.List)
-				}
-				info := parseEchoHandlerInfo(comments)
-				analysis := analyzeEchoHandlerDetails(fn, structs, functions)
-
-				pos := fset.Position(fn.Pos())
-				receiverName := receiverTypeName(fn.Recv)
-				funcName := fn.Name.Name
-
-				key := strings.ToLower(funcName)
-				handlerEntry := echoAnalyzedHandler{
-					filePath:     pos.Filename,
-					funcName:     funcName,
-					receiverName: receiverName,
-					startLine:    pos.Line,
-					metadata: EchoHandlerMetadata{
-						Info:        info,
-						RequestBody: analysis.RequestBody,
-						Responses:   analysis.Responses,
-					},
-				}
+			// Check if this is likely an Echo handler (has echo.Context parameter)
+			if !isEchoHandler(fn) {
+				continue
+			}
 
-				handlers[key] = append(handlers[key], handlerEntry)
+			var comments []string
+			if fn.Doc != nil {
+				comments = extractCommentsText(fn.Doc.List)
+			}
+			info := parseEchoHandlerInfo(comments)
+			analysis := analyzeEchoHandlerDetails(fn, structs, functions)
+			// Parameters documented in comments win over those inferred from bindings
+			info.Parameters = appendParameters(info.Parameters, analysis.Parameters...)
+
+			pos := fset.Position(fn.Pos())
+			receiverName := receiverDeclName(fn.Recv)
+			funcName := fn.Name.Name
+
+			key := strings.ToLower(funcName)
+			handlerEntry := echoAnalyzedHandler{
+				filePath:     pos.Filename,
+				funcName:     funcName,
+				receiverName: receiverName,
+				startLine:    pos.Line,
+				metadata: EchoHandlerMetadata{
+					Info:        info,
+					RequestBody: analysis.RequestBody,
+					Responses:   analysis.Responses,
+					Trace:       analysis.Trace,
+				},
 			}
-		}
-	}
 
-	return handlers
+			handlers[key] = append(handlers[key], handlerEntry)
+		}
+	})
 }
 
 // isEchoHandler checks if a function is likely an Echo handler by looking for echo.Context parameter
@@ -187,6 +213,8 @@ func isEchoHandler(fn *ast.FuncDecl) bool {
 type echoHandlerAnalysis struct {
 	RequestBody *core.RequestBody
 	Responses   map[string]core.Response
+	Parameters  []core.Parameter
+	Trace       []string // analysis steps, when debugging
 }
 
 // analyzeEchoHandlerDetails inspects an Echo handler function to infer request bodies and responses.
@@ -204,7 +232,9 @@ func analyzeEchoHandlerDetails(fn *ast.FuncDecl, structs map[string]*ast.StructT
 		functions: functions,
 		variables: make(map[string]ast.Expr),
 		values:    make(map[string]ast.Expr),
+		trace:     newHandlerTrace(),
 	}
+	registerFuncParamTypes(fn, ctx)
 
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
 		switch node := n.(type) {
@@ -215,22 +245,40 @@ func analyzeEchoHandlerDetails(fn *ast.FuncDecl, structs map[string]*ast.StructT
 		case *ast.RangeStmt:
 			registerRangeTypes(node, ctx)
 		case *ast.CallExpr:
-			// Detect request body binding for Echo
-			if analysis.RequestBody == nil && isEchoBindingCall(node) {
-				if len(node.Args) > 0 {
-					if resolved := resolveEchoRequestBody(node, node.Args[0], ctx); resolved != nil {
+			// Detect request binding for Echo; param and query fields become parameters
+			if isEchoBindingCall(node) && len(node.Args) > 0 {
+				params := resolveEchoParameters(node.Args[0], ctx)
+				ctx.traceParameters(node, params)
+				analysis.Parameters = appendParameters(analysis.Parameters, params...)
+				if analysis.RequestBody == nil {
+					resolved := resolveEchoRequestBody(node, node.Args[0], ctx)
+					ctx.traceBinding(node, resolved)
+					if resolved != nil {
 						analysis.RequestBody = resolved
 					}
 				}
 			}
 
+			if statusCode, response, ok := streamResponse(node, ctx); ok {
+				analysis.Responses[statusCode] = response
+				ctx.traceResponse(node, &ast.BasicLit{Kind: token.INT, Value: statusCode}, statusCode, nil, response.Schema)
+				return true
+			}
+
 			// Detect response generation calls for Echo
-			if contentType, statusExpr, dataExpr, ok := echoResponseCallInfo(node, ctx); ok {
+			contentType, statusExpr, dataExpr, ok := errorCallInfo(node, ctx)
+			if !ok {
+				contentType, statusExpr, dataExpr, ok = echoResponseCallInfo(node, ctx)
+			}
+			if !ok {
+				contentType, statusExpr, dataExpr, ok = responseHelperCallInfo(node, ctx)
+			}
+			if ok {
 				statusCode := extractStatusCode(statusExpr, ctx)
 				if statusCode == "" {
 					statusCode = "200"
 				}
-				payloadExpr := resolveResponsePayloadExpr(dataExpr, ctx)
+				payloadExpr := resolveResponsePayloadExpr(errorPayloadExpr(statusCode, dataExpr, ctx), ctx)
 				schema, example := buildSchemaFromExpr(payloadExpr, ctx, make(map[string]bool))
 				example = normalizeExampleWithSchema(schema, example)
 				if example == nil {
@@ -249,11 +297,13 @@ func analyzeEchoHandlerDetails(fn *ast.FuncDecl, structs map[string]*ast.StructT
 					response.Description = "Response"
 				}
 				analysis.Responses[statusCode] = response
+				ctx.traceResponse(node, statusExpr, statusCode, payloadExpr, schema)
 			}
 		}
 		return true
 	})
 
+	analysis.Trace = ctx.trace.finish(analysis.RequestBody != nil, len(analysis.Responses))
 	return analysis
 }
 
@@ -271,7 +321,7 @@ func isEchoBindingCall(call *ast.CallExpr) bool {
 }
 
 func resolveEchoRequestBody(call *ast.CallExpr, arg ast.Expr, ctx *analysisContext) *core.RequestBody {
-	typeExpr := resolveTypeFromArg(arg, ctx)
+	typeExpr := echoBodyExpr(resolveTypeFromArg(arg, ctx), ctx)
 	if typeExpr == nil {
 		return nil
 	}
@@ -298,7 +348,102 @@ func resolveEchoRequestBody(call *ast.CallExpr, arg ast.Expr, ctx *analysisConte
 	return body
 }
 
+// echoParamBindings are the parameter tags echo's default binder reads besides the body
+var echoParamBindings = []paramBinding{
+	{tag: "param", in: "path", tagged: true},
+	{tag: "query", in: "query", tagged: true},
+}
+
+// resolveEchoParameters documents the param and query tagged fields of a bound struct
+func resolveEchoParameters(arg ast.Expr, ctx *analysisContext) []core.Parameter {
+	typeExpr := resolveTypeFromArg(arg, ctx)
+	if typeExpr == nil {
+		return nil
+	}
+	var params []core.Parameter
+	for _, binding := range echoParamBindings {
+		params = append(params, structParameters(typeExpr, ctx, binding, make(map[string]bool))...)
+	}
+	return params
+}
+
+// echoBodyExpr strips the fields echo binds from path and query parameters out
+// of a bound struct. It returns nil when no body fields remain.
+func echoBodyExpr(typeExpr ast.Expr, ctx *analysisContext) ast.Expr {
+	base := typeExpr
+	if star, ok := base.(*ast.StarExpr); ok {
+		base = star.X
+	}
+	ident, ok := base.(*ast.Ident)
+	if !ok || ctx == nil {
+		return typeExpr
+	}
+	structType, ok := ctx.structs[ident.Name]
+	if !ok || structType.Fields == nil {
+		return typeExpr
+	}
+
+	filtered, changed := filterEchoBodyFields(structType, ctx, map[string]bool{ident.Name: true})
+	if !changed {
+		return typeExpr
+	}
+	if len(filtered.Fields.List) == 0 {
+		return nil
+	}
+	return filtered
+}
+
+// filterEchoBodyFields drops parameter-only fields, including those of embedded structs
+func filterEchoBodyFields(structType *ast.StructType, ctx *analysisContext, visited map[string]bool) (*ast.StructType, bool) {
+	fields := make([]*ast.Field, 0, len(structType.Fields.List))
+	changed := false
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 {
+			embedded := field.Type
+			if star, ok := embedded.(*ast.StarExpr); ok {
+				embedded = star.X
+			}
+			if ident, ok := embedded.(*ast.Ident); ok && !visited[ident.Name] {
+				if nested, ok := ctx.structs[ident.Name]; ok && nested.Fields != nil {
+					visited[ident.Name] = true
+					filtered, nestedChanged := filterEchoBodyFields(nested, ctx, visited)
+					delete(visited, ident.Name)
+					if nestedChanged {
+						changed = true
+						if len(filtered.Fields.List) > 0 {
+							fields = append(fields, &ast.Field{Type: filtered})
+						}
+						continue
+					}
+				}
+			}
+			fields = append(fields, field)
+			continue
+		}
+
+		if isEchoParamField(field) {
+			changed = true
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return &ast.StructType{Fields: &ast.FieldList{List: fields}}, changed
+}
+
+// isEchoParamField reports whether a field is only bound from path or query parameters
+func isEchoParamField(field *ast.Field) bool {
+	for _, bodyTag := range []string{"json", "xml", "form"} {
+		if getStructTag(field, bodyTag) != "" {
+			return false
+		}
+	}
+	return getStructTag(field, "param") != "" || getStructTag(field, "query") != ""
+}
+
 func echoResponseCallInfo(call *ast.CallExpr, ctx *analysisContext) (contentType string, statusExpr ast.Expr, dataExpr ast.Expr, ok bool) {
+	if contentType, statusExpr, dataExpr, ok := templateCallInfo(call, ctx); ok {
+		return contentType, statusExpr, dataExpr, true
+	}
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
 		return "", nil, nil, false
@@ -319,18 +464,15 @@ func echoResponseCallInfo(call *ast.CallExpr, ctx *analysisContext) (contentType
 			return "application/xml", call.Args[0], call.Args[1], true
 		}
 	case "HTML":
-		if len(call.Args) >= 3 {
-			return "text/html", call.Args[0], call.Args[2], true
+		if len(call.Args) >= 2 {
+			return "text/html", call.Args[0], call.Args[1], true
 		}
-	case "Blob":
+	case "Render":
+		// c.Render(code, name, data) renders through the echo Renderer
 		if len(call.Args) >= 3 {
-			ct := resolveContentType(call.Args[1], ctx)
-			if ct == "" {
-				ct = "application/octet-stream"
-			}
-			return ct, call.Args[0], call.Args[2], true
+			return "text/html", call.Args[0], htmlTemplateBody(call.Args[1]), true
 		}
-	case "Stream":
+	case "Blob":
 		if len(call.Args) >= 3 {
 			ct := resolveContentType(call.Args[1], ctx)
 			if ct == "" {
@@ -338,18 +480,6 @@ func echoResponseCallInfo(call *ast.CallExpr, ctx *analysisContext) (contentType
 			}
 			return ct, call.Args[0], call.Args[2], true
 		}
-	case "File":
-		if len(call.Args) >= 1 {
-			return "application/octet-stream", &ast.BasicLit{Kind: 9, Value: "200"}, call.Args[0], true
-		}
-	case "Attachment":
-		if len(call.Args) >= 2 {
-			return "application/octet-stream", &ast.BasicLit{Kind: 9, Value: "200"}, call.Args[0], true
-		}
-	case "Inline":
-		if len(call.Args) >= 2 {
-			return "application/octet-stream", &ast.BasicLit{Kind: 9, Value: "200"}, call.Args[0], true
-		}
 	case "NoContent":
 		if len(call.Args) >= 1 {
 			return "", call.Args[0], &ast.BasicLit{Kind: 10, Value: `""`}, true
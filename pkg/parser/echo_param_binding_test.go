@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEchoBindSplitsParametersFromBody(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+type Paging struct {
+	Page int ` + "`query:\"page\"`" + `
+}
+
+type UpdateUserRequest struct {
+	Paging
+	ID    string ` + "`param:\"id\"`" + `
+	Force bool   ` + "`query:\"force\"`" + `
+	Name  string ` + "`json:\"name\" validate:\"required\"`" + `
+}
+
+type DeleteUserRequest struct {
+	ID string ` + "`param:\"id\"`" + `
+}
+
+func UpdateUser(c echo.Context) error {
+	var req UpdateUserRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	return c.JSON(200, req)
+}
+
+func DeleteUser(c echo.Context) error {
+	req := new(DeleteUserRequest)
+	if err := c.Bind(req); err != nil {
+		return err
+	}
+	return c.NoContent(204)
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := analyzeEchoDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := analysis.handlers["updateuser"][0].metadata
+	locations := make(map[string]string)
+	for _, param := range update.Info.Parameters {
+		locations[param.Name] = param.In
+	}
+	if locations["id"] != "path" || locations["force"] != "query" || locations["page"] != "query" || len(locations) != 3 {
+		t.Fatalf("unexpected parameters %+v", update.Info.Parameters)
+	}
+
+	if update.RequestBody == nil {
+		t.Fatal("expected a request body")
+	}
+	props, _ := update.RequestBody.Schema.(map[string]interface{})["properties"].(map[string]interface{})
+	if len(props) != 1 || props["name"] == nil {
+		t.Errorf("expected only json fields in the body, got %+v", update.RequestBody.Schema)
+	}
+
+	remove := analysis.handlers["deleteuser"][0].metadata
+	if remove.RequestBody != nil {
+		t.Errorf("expected no body for a parameter-only struct, got %+v", remove.RequestBody)
+	}
+	if len(remove.Info.Parameters) != 1 || remove.Info.Parameters[0].In != "path" {
+		t.Errorf("unexpected parameters %+v", remove.Info.Parameters)
+	}
+}
@@ -0,0 +1,172 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrorConvention describes how an application reports errors, so every
+// error helper and framework error constructor is documented as a non-2xx
+// response with the same error schema.
+type ErrorConvention struct {
+	// Type is the error envelope struct, e.g. "ErrorResponse". It must be
+	// declared in the analyzed package; a package qualifier is ignored.
+	Type string
+	// Helpers maps error helper functions to the index of their status code
+	// argument, e.g. {"writeError": 1} for writeError(w, status, msg).
+	Helpers map[string]int
+}
+
+// defaultErrorConvention keeps the long-standing writeError(w, status, ...) helper detection
+var defaultErrorConvention = ErrorConvention{
+	Type:    "ErrorResponse",
+	Helpers: map[string]int{"writeError": 1},
+}
+
+var (
+	errorConvention      = defaultErrorConvention
+	errorConventionSet   bool
+	errorConventionMutex sync.RWMutex
+)
+
+// SetErrorConvention registers the application's error envelope and helpers
+// as the process-wide default. Config.ErrorResponseType and
+// Config.ErrorHelpers override it for one docs instance. Analyses are cached
+// per convention, so the new one applies on the next run.
+func SetErrorConvention(convention ErrorConvention) {
+	errorConventionMutex.Lock()
+	defer errorConventionMutex.Unlock()
+	errorConvention = convention
+	errorConventionSet = true
+}
+
+// getErrorConvention returns the error convention of the current analysis
+func getErrorConvention() (ErrorConvention, bool) {
+	return currentAnalysisSettings().errorConvention()
+}
+
+// parseErrorHelpers parses "writeError:1,respondError:2" into a helper map.
+// A helper without an index takes the status as its second argument.
+func parseErrorHelpers(values []string) (map[string]int, error) {
+	helpers := make(map[string]int, len(values))
+	for _, value := range values {
+		name, index, found := strings.Cut(strings.TrimSpace(value), ":")
+		if name == "" {
+			continue
+		}
+		helpers[name] = 1
+		if found {
+			parsed, err := strconv.Atoi(index)
+			if err != nil || parsed < 0 {
+				return nil, fmt.Errorf("invalid status argument index in error helper %q", value)
+			}
+			helpers[name] = parsed
+		}
+	}
+	return helpers, nil
+}
+
+// errorCallInfo recognizes error helpers and framework error constructors:
+// configured helpers, echo.NewHTTPError, fiber.NewError, gin's AbortWithStatus
+// and AbortWithError, and http.Error.
+func errorCallInfo(call *ast.CallExpr, ctx *analysisContext) (contentType string, statusExpr ast.Expr, dataExpr ast.Expr, ok bool) {
+	convention, configured := getErrorConvention()
+
+	var name, qualifier string
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		name = fun.Name
+	case *ast.SelectorExpr:
+		name = fun.Sel.Name
+		if ident, isIdent := fun.X.(*ast.Ident); isIdent {
+			qualifier = ident.Name
+		}
+	default:
+		return "", nil, nil, false
+	}
+
+	if index, isHelper := convention.Helpers[name]; isHelper {
+		if index < len(call.Args) {
+			return "application/json", call.Args[index], errorEnvelopeExpr(convention, ctx, defaultMessageBody()), true
+		}
+		return "", nil, nil, false
+	}
+
+	// Framework constructors only use the envelope when it was registered explicitly;
+	// otherwise they document the framework's default error body
+	if !configured {
+		convention.Type = ""
+	}
+
+	switch {
+	case qualifier == "echo" && name == "NewHTTPError" && len(call.Args) >= 1:
+		return "application/json", call.Args[0], errorEnvelopeExpr(convention, ctx, defaultMessageBody()), true
+	case qualifier == "fiber" && name == "NewError" && len(call.Args) >= 1:
+		return "application/json", call.Args[0], errorEnvelopeExpr(convention, ctx, defaultMessageBody()), true
+	case qualifier == "http" && name == "Error" && len(call.Args) >= 3:
+		return "text/plain", call.Args[2], httpErrorMessage(call.Args[1], ctx), true
+	case qualifier != "" && (name == "AbortWithStatus" || name == "AbortWithError") && len(call.Args) >= 1:
+		return "", call.Args[0], errorEnvelopeExpr(convention, ctx, &ast.BasicLit{Kind: token.STRING, Value: `""`}), true
+	}
+
+	return "", nil, nil, false
+}
+
+// errorPayloadExpr swaps ad-hoc map payloads of error responses, e.g.
+// c.AbortWithStatusJSON(400, gin.H{...}), for the registered error envelope
+func errorPayloadExpr(statusCode string, dataExpr ast.Expr, ctx *analysisContext) ast.Expr {
+	convention, configured := getErrorConvention()
+	if !configured || convention.Type == "" {
+		return dataExpr
+	}
+	if code, err := strconv.Atoi(statusCode); err != nil || code < 400 {
+		return dataExpr
+	}
+
+	if dataExpr != nil {
+		lit, ok := dataExpr.(*ast.CompositeLit)
+		if !ok {
+			return dataExpr
+		}
+		switch t := lit.Type.(type) {
+		case *ast.MapType:
+		case *ast.SelectorExpr:
+			if t.Sel.Name != "H" && t.Sel.Name != "Map" {
+				return dataExpr
+			}
+		default:
+			return dataExpr
+		}
+	}
+
+	return errorEnvelopeExpr(convention, ctx, dataExpr)
+}
+
+// errorEnvelopeExpr returns a composite literal of the envelope type, or
+// fallback when no envelope is registered or it is not declared in the package
+func errorEnvelopeExpr(convention ErrorConvention, ctx *analysisContext, fallback ast.Expr) ast.Expr {
+	typeName := convention.Type
+	if index := strings.LastIndex(typeName, "."); index >= 0 {
+		typeName = typeName[index+1:]
+	}
+	if typeName == "" || ctx == nil || ctx.structs[typeName] == nil {
+		return fallback
+	}
+	return &ast.CompositeLit{Type: &ast.Ident{Name: typeName}}
+}
+
+// defaultMessageBody mirrors the {"message": "..."} body of echo's and fiber's
+// default error handlers
+func defaultMessageBody() ast.Expr {
+	return &ast.CompositeLit{
+		Type: &ast.MapType{Key: &ast.Ident{Name: "string"}, Value: &ast.Ident{Name: "string"}},
+		Elts: []ast.Expr{&ast.KeyValueExpr{
+			Key:   &ast.BasicLit{Kind: token.STRING, Value: `"message"`},
+			Value: &ast.BasicLit{Kind: token.STRING, Value: `"error message"`},
+		}},
+	}
+}
@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+func TestErrorConventionDocumentsSharedErrorSchema(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+type ErrorResponse struct {
+	Code    string ` + "`json:\"code\"`" + `
+	Message string ` + "`json:\"message\"`" + `
+}
+
+func GetUser(c *gin.Context) {
+	if c.Param("id") == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "missing id"})
+		return
+	}
+	if !exists(c) {
+		respondError(c, http.StatusNotFound, "not found")
+		return
+	}
+	c.AbortWithStatus(http.StatusUnauthorized)
+}
+
+func CreateItem(c echo.Context) error {
+	return echo.NewHTTPError(http.StatusConflict, "already exists")
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		errorConventionMutex.Lock()
+		errorConvention, errorConventionSet = defaultErrorConvention, false
+		errorConventionMutex.Unlock()
+	}()
+
+	// Without a registered envelope, framework errors keep their default bodies
+	echoAnalysis, err := analyzeEchoDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conflict := echoAnalysis.handlers["createitem"][0].metadata.Responses["409"]
+	if props, _ := conflict.Schema.(map[string]interface{})["properties"].(map[string]interface{}); props["message"] == nil {
+		t.Fatalf("expected echo default error body, got %+v", conflict.Schema)
+	}
+
+	SetErrorConvention(ErrorConvention{Type: "ErrorResponse", Helpers: map[string]int{"respondError": 1}})
+
+	analysis, err := analyzeDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	responses := analysis.handlers["getuser"][0].metadata.Responses
+	for _, status := range []string{"400", "404", "401"} {
+		schema, _ := responses[status].Schema.(map[string]interface{})
+		props, _ := schema["properties"].(map[string]interface{})
+		if props["code"] == nil || props["message"] == nil {
+			t.Fatalf("expected %s to use the ErrorResponse schema, got %+v", status, responses[status])
+		}
+	}
+}
+
+func TestErrorConventionIsPerDocsInstance(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+type ErrorResponse struct {
+	Message string ` + "`json:\"message\"`" + `
+}
+
+type Problem struct {
+	Title string ` + "`json:\"title\"`" + `
+}
+
+func GetUser(c *gin.Context) {
+	respondError(c, http.StatusNotFound, "not found")
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(resetAnalysisCaches)
+
+	// Helpers alone keep the default ErrorResponse envelope
+	helpersOnly := newAnalysisSettings(&core.Config{ErrorHelpers: []string{"respondError:1"}})
+	problems := newAnalysisSettings(&core.Config{ErrorResponseType: "Problem", ErrorHelpers: []string{"respondError:1"}})
+	notFound := func(settings *analysisSettings) map[string]interface{} {
+		var analysis *packageAnalysis
+		settings.scoped(func() { analysis = loadPackageAnalysis(dir) })()
+		if analysis == nil {
+			t.Fatal("expected the package analyzed")
+		}
+		schema, _ := analysis.handlers["getuser"][0].metadata.Responses["404"].Schema.(map[string]interface{})
+		props, _ := schema["properties"].(map[string]interface{})
+		return props
+	}
+
+	if props := notFound(helpersOnly); props["message"] == nil {
+		t.Fatalf("expected helpers alone to keep ErrorResponse, got %+v", props)
+	}
+	if props := notFound(problems); props["title"] == nil {
+		t.Fatalf("expected the second instance to use Problem, got %+v", props)
+	}
+	if props := notFound(helpersOnly); props["message"] == nil {
+		t.Fatalf("expected the first instance unaffected by the second, got %+v", props)
+	}
+	if convention, _ := getErrorConvention(); convention.Type != "ErrorResponse" || convention.Helpers["writeError"] != 1 {
+		t.Fatalf("expected the process-wide convention unchanged, got %+v", convention)
+	}
+
+	// A refresh of one instance keeps the analyses of the other
+	problems.resetCaches()
+	analysisMutex.RLock()
+	_, kept := analysisCache[helpersOnly.fingerprint()+"\x00"+dir]
+	_, stale := analysisCache[problems.fingerprint()+"\x00"+dir]
+	analysisMutex.RUnlock()
+	if !kept || stale {
+		t.Fatalf("expected only the refreshed instance's analyses dropped, other kept=%v, refreshed kept=%v", kept, stale)
+	}
+}
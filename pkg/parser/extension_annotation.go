@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"go/ast"
+	"strings"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// extensionAnnotation returns the vendor extension of an "@x-<name> [value]"
+// comment line, e.g. "@x-rate-limit 100", `@x-code-samples [{"lang": "curl"}]`
+// or "@x-internal", which is true
+func extensionAnnotation(line string) (string, interface{}, bool) {
+	if !strings.HasPrefix(strings.ToLower(line), "@x-") {
+		return "", nil, false
+	}
+	name, value := line[1:], ""
+	if index := strings.IndexAny(name, " \t"); index >= 0 {
+		name, value = name[:index], name[index+1:]
+	}
+	if len(name) <= len("x-") {
+		return "", nil, false
+	}
+	return name, core.ParseExtensionValue(value), true
+}
+
+// structDoc is the doc comment of a collected struct type: its description
+// and the vendor extensions of its "@x-<name>" lines
+type structDoc struct {
+	description string
+	extensions  core.Extensions
+}
+
+// parseStructDoc splits the doc comment lines of a struct type
+func parseStructDoc(lines []string) structDoc {
+	var doc structDoc
+	description := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if name, value, ok := extensionAnnotation(line); ok {
+			if doc.extensions == nil {
+				doc.extensions = make(core.Extensions)
+			}
+			doc.extensions[name] = value
+			continue
+		}
+		description = append(description, line)
+	}
+	doc.description = strings.Join(description, " ")
+	return doc
+}
+
+// structExtensions returns the vendor extensions of a collected struct type
+func structExtensions(structType *ast.StructType) core.Extensions {
+	doc, _ := structDocs.Load(structType)
+	parsed, _ := doc.(structDoc)
+	return parsed.extensions
+}
+
+// applyStructDoc sets the description and vendor extensions of a struct schema
+func applyStructDoc(schema map[string]interface{}, structType *ast.StructType) {
+	if description := structDescription(structType); description != "" {
+		schema["description"] = description
+	}
+	for name, value := range structExtensions(structType) {
+		schema[name] = value
+	}
+}
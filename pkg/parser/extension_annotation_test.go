@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtensionAnnotations(t *testing.T) {
+	info := parseHandlerInfo([]string{
+		"ListOrders lists orders",
+		"@x-internal",
+		`@x-rate-limit {"requests": 100, "window": "1m"}`,
+		"@x-owner payments team",
+	})
+	if info.Extensions["x-internal"] != true || info.Extensions["x-owner"] != "payments team" {
+		t.Fatalf("expected flag and text extensions, got %+v", info.Extensions)
+	}
+	if limit, _ := info.Extensions["x-rate-limit"].(map[string]interface{}); limit["requests"] != float64(100) {
+		t.Fatalf("expected a JSON extension value, got %+v", info.Extensions["x-rate-limit"])
+	}
+	if info.Summary != "ListOrders lists orders" {
+		t.Fatalf("extensions must not disturb the summary, got %+v", info)
+	}
+	if _, _, ok := extensionAnnotation("@x-"); ok {
+		t.Fatal("expected an extension without a name to be ignored")
+	}
+
+	dir := t.TempDir()
+	source := `package api
+
+// Order is a placed order
+// @x-entity order
+type Order struct {
+	ID    string ` + "`json:\"id\" extensions:\"x-order=1,x-immutable,!x-nullable\"`" + `
+}
+
+// GetOrder returns an order
+func GetOrder(c *gin.Context) {
+	c.JSON(200, Order{})
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	analysis, err := analyzeDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, _ := analysis.handlers["getorder"][0].metadata.Responses["200"].Schema.(map[string]interface{})
+	if schema["x-entity"] != "order" || schema["description"] != "Order is a placed order" {
+		t.Fatalf("expected the doc comment extension apart from the description, got %+v", schema)
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	id, _ := properties["id"].(map[string]interface{})
+	if id["x-order"] != float64(1) || id["x-immutable"] != true || id["x-nullable"] != false {
+		t.Fatalf("expected the extensions tag on the property, got %+v", id)
+	}
+}
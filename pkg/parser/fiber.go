@@ -12,29 +12,25 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/idnexacloud/bytedocs-go/pkg/core"
 	"github.com/gofiber/fiber/v2"
 )
 
-// Global registry for Fiber route tracking
-var (
-	globalFiberDocs   *core.APIDocs
-	fiberDocsConfig   *core.Config
-	fiberDocsMutex    sync.RWMutex
-	fiberFuncComments map[string][]string
-)
-
-func init() {
-	fiberFuncComments = make(map[string][]string)
-}
-
 // FiberHandlerInfo holds parsed comment information for Fiber handlers
 type FiberHandlerInfo struct {
-	Summary     string
-	Description string
-	Parameters  []core.Parameter
+	Summary      string
+	Description  string
+	Parameters   []core.Parameter
+	Section      string             // from "@Section <id>"
+	ExternalDocs *core.ExternalDocs // from "@Docs <url> [description]"
+	Presets      []core.Preset      // from "@Preset <name> [param=value ...] [json body]"
+	SLA          time.Duration      // from "@SLA <duration>"
+	Pagination   string             // from "@Paginate <style>"
+	Accepts      []string           // from "@Accept <content types>"
+	ResponseDocs map[string]string  // from "@Response <status> <description>"
+	Extensions   core.Extensions    // from "@x-<name> [value]"
 }
 
 // parseFiberHandlerComments parses Go source files to extract Fiber handler comments
@@ -79,6 +75,44 @@ func parseFiberHandlerInfo(comments []string) FiberHandlerInfo {
 	paramRegex := regexp.MustCompile(`@Param\s+(\w+)\s+(\w+)\s+(\w+)\s+(true|false)\s+"([^"]*)"`)
 
 	for _, line := range comments {
+		if section, ok := sectionAnnotation(line); ok {
+			info.Section = section
+			continue
+		}
+		if externalDocs, ok := docsAnnotation(line); ok {
+			info.ExternalDocs = externalDocs
+			continue
+		}
+		if preset, ok := presetAnnotation(line); ok {
+			info.Presets = append(info.Presets, preset)
+			continue
+		}
+		if sla, ok := slaAnnotation(line); ok {
+			info.SLA = sla
+			continue
+		}
+		if style, ok := paginateAnnotation(line); ok {
+			info.Pagination = style
+			continue
+		}
+		if accepts, ok := acceptAnnotation(line); ok {
+			info.Accepts = append(info.Accepts, accepts...)
+			continue
+		}
+		if status, description, ok := responseAnnotation(line); ok {
+			if info.ResponseDocs == nil {
+				info.ResponseDocs = make(map[string]string)
+			}
+			info.ResponseDocs[status] = description
+			continue
+		}
+		if name, value, ok := extensionAnnotation(line); ok {
+			if info.Extensions == nil {
+				info.Extensions = make(core.Extensions)
+			}
+			info.Extensions[name] = value
+			continue
+		}
 		// Parse @Param annotations
 		if matches := paramRegex.FindStringSubmatch(line); len(matches) == 6 {
 			param := core.Parameter{
@@ -118,7 +152,7 @@ func extractFiberHandlerName(handler interface{}) string {
 
 	parts := strings.Split(funcName, ".")
 	if len(parts) > 0 {
-		return parts[len(parts)-1]
+		return strings.TrimSuffix(parts[len(parts)-1], methodValueSuffix)
 	}
 
 	return ""
@@ -136,8 +170,7 @@ func getFiberRoutes(app *fiber.App) []FiberRoute {
 	var routes []FiberRoute
 	seen := make(map[string]struct{})
 
-	// Fiber automatically registers HEAD routes alongside GET. They pollute the docs sidebar
-	// with duplicated endpoints, so walk the flattened route list and skip unwanted entries.
+	// Walk the flattened route list, dropping duplicates and routes without handlers
 	for _, route := range app.GetRoutes(true) {
 		method := strings.TrimSpace(strings.ToUpper(route.Method))
 		path := strings.TrimSpace(route.Path)
@@ -145,9 +178,6 @@ func getFiberRoutes(app *fiber.App) []FiberRoute {
 		if method == "" || path == "" {
 			continue
 		}
-		if method == fiber.MethodHead {
-			continue
-		}
 
 		key := method + " " + path
 		if _, exists := seen[key]; exists {
@@ -171,8 +201,9 @@ func getFiberRoutes(app *fiber.App) []FiberRoute {
 	return routes
 }
 
-// SetupFiberDocs sets up documentation for a Fiber app with auto-detection
-func SetupFiberDocs(app *fiber.App, config *core.Config) {
+// SetupFiberDocs sets up documentation for a Fiber app with auto-detection.
+// The returned APIDocs can be used to customize the generated documentation
+func SetupFiberDocs(app *fiber.App, config *core.Config) *core.APIDocs {
 	if config == nil {
 		config = &core.Config{
 			Title:      "API Documentation",
@@ -182,25 +213,25 @@ func SetupFiberDocs(app *fiber.App, config *core.Config) {
 		}
 	}
 
-	fiberDocsMutex.Lock()
-	fiberDocsConfig = config
-	globalFiberDocs = core.New(config)
-	fiberDocsMutex.Unlock()
+	analysis := newAnalysisSettings(config)
 
-	// Set up the docs route that does auto-detection
-	docsHandler := func(c *fiber.Ctx) error {
-		fiberDocsMutex.Lock()
-		defer fiberDocsMutex.Unlock()
+	docs := core.New(config)
 
-		endpointsCount := len(globalFiberDocs.GetDocumentation().Endpoints)
+	// Refresh analyzes the handlers from source again
+	docs.OnRefresh(analysis.resetCaches)
 
-		if endpointsCount == 0 && config.AutoDetect {
+	// Routes are detected on Warmup or the first docs request, and again on Refresh
+	if config.AutoDetect {
+		docs.SetRouteDetector(analysis.scoped(func() {
 			routes := getFiberRoutes(app)
 
 			for _, route := range routes {
-				if strings.HasPrefix(route.Path, config.DocsPath) ||
-					strings.Contains(route.Path, "/static") ||
-					strings.Contains(route.Path, "/assets") {
+				if strings.HasPrefix(route.Path, config.DocsPath) {
+					continue
+				}
+				// Fiber registers a HEAD route alongside every GET; without
+				// SkipMethods these copies stay out of the docs
+				if route.Method == fiber.MethodHead && config.SkipMethods == nil {
 					continue
 				}
 
@@ -208,7 +239,7 @@ func SetupFiberDocs(app *fiber.App, config *core.Config) {
 				handlerName := extractFiberHandlerName(route.Handler)
 
 				if handlerName != "" {
-					metadata = getFiberHandlerMetadataByName(handlerName, ".")
+					metadata = getFiberHandlerMetadata(route.Handler)
 				}
 
 				if metadata.Info.Summary == "" && metadata.Info.Description == "" {
@@ -219,22 +250,39 @@ func SetupFiberDocs(app *fiber.App, config *core.Config) {
 				}
 
 				routeInfo := core.RouteInfo{
-					Method:      route.Method,
-					Path:        route.Path,
-					Handler:     route.Handler,
-					Summary:     metadata.Info.Summary,
-					Description: metadata.Info.Description,
-					Parameters:  metadata.Info.Parameters,
-					RequestBody: metadata.RequestBody,
-					Responses:   metadata.Responses,
+					Method:       route.Method,
+					Path:         route.Path,
+					Handler:      route.Handler,
+					Summary:      metadata.Info.Summary,
+					Description:  metadata.Info.Description,
+					Parameters:   metadata.Info.Parameters,
+					Section:      metadata.Info.Section,
+					ExternalDocs: metadata.Info.ExternalDocs,
+					Presets:      metadata.Info.Presets,
+					SLA:          metadata.Info.SLA,
+					Pagination:   metadata.Info.Pagination,
+					Accepts:      metadata.Info.Accepts,
+					ResponseDocs: metadata.Info.ResponseDocs,
+					Extensions:   metadata.Info.Extensions,
+					RequestBody:  metadata.RequestBody,
+					Responses:    metadata.Responses,
+					Warnings:     metadata.Warnings,
+					Trace:        metadata.Trace,
 				}
 
-				globalFiberDocs.AddRouteInfo(routeInfo)
+				docs.AddRouteInfo(routeInfo)
 			}
 
-			globalFiberDocs.Generate()
-		}
+			docs.Generate()
+		}))
+	}
+
+	if serveDocsSeparately(docs, config) {
+		return docs
+	}
 
+	// Set up the docs route that does auto-detection
+	docsHandler := func(c *fiber.Ctx) error {
 		// Serve documentation directly using Fiber's response writer
 		// Convert Fiber request to standard HTTP request
 		uri := c.Request().URI()
@@ -266,13 +314,15 @@ func SetupFiberDocs(app *fiber.App, config *core.Config) {
 		w := &simpleFiberResponseWriter{ctx: c}
 
 		// Serve documentation
-		globalFiberDocs.ServeHTTP(w, req)
+		docs.ServeHTTP(w, req)
 		return nil
 	}
 
 	// Register the docs routes
 	app.All(config.DocsPath, docsHandler)
 	app.All(config.DocsPath+"/*", docsHandler)
+
+	return docs
 }
 
 // bodyReader implements io.ReadCloser for request body
@@ -2,9 +2,7 @@ package parser
 
 import (
 	"go/ast"
-	"go/parser"
 	"go/token"
-	"io/fs"
 	"strings"
 	"sync"
 
@@ -16,6 +14,8 @@ type FiberHandlerMetadata struct {
 	Info        FiberHandlerInfo
 	RequestBody *core.RequestBody
 	Responses   map[string]core.Response
+	Warnings    []string `json:"-"` // why no schemas were inferred; not cached
+	Trace       []string `json:"-"` // analysis steps when debugging; not cached
 }
 
 // fiberAnalyzedHandler keeps track of metadata for an individual Fiber handler within a package.
@@ -38,30 +38,39 @@ var (
 	fiberAnalysisMutex sync.RWMutex
 )
 
+// getFiberHandlerMetadata resolves a Fiber handler, including controller method values, to its metadata
+func getFiberHandlerMetadata(handler interface{}) FiberHandlerMetadata {
+	ref, ok := handlerRefFromFunc(handler)
+	if !ok {
+		return FiberHandlerMetadata{Warnings: unresolvedHandlerWarnings()}
+	}
+	return lookupFiberHandlerMetadata(ref)
+}
+
 // getFiberHandlerMetadataByName gets handler metadata by analyzing the function name from parsed files
 func getFiberHandlerMetadataByName(funcName string, dir string) FiberHandlerMetadata {
-	packageMeta := loadFiberPackageAnalysis(dir)
-	if packageMeta == nil {
-		return FiberHandlerMetadata{}
-	}
+	return lookupFiberHandlerMetadata(handlerRefFromName(funcName, dir))
+}
 
-	key := strings.ToLower(funcName)
-	candidates := packageMeta.handlers[key]
-	if len(candidates) == 0 {
-		return FiberHandlerMetadata{}
+func lookupFiberHandlerMetadata(ref handlerRef) FiberHandlerMetadata {
+	packageMeta := loadFiberPackageAnalysis(ref.dir)
+	if packageMeta == nil {
+		return FiberHandlerMetadata{Warnings: packageWarnings(ref)}
 	}
 
-	if len(candidates) > 0 {
-		return candidates[0].metadata
+	if candidate, ok := selectHandler(packageMeta.handlers[strings.ToLower(ref.funcName)], ref); ok {
+		return candidate.metadata
 	}
 
-	return FiberHandlerMetadata{}
+	return FiberHandlerMetadata{Warnings: handlerNotFoundWarnings(ref)}
 }
 
 // loadFiberPackageAnalysis parses and caches metadata for all Fiber handlers within a directory.
 func loadFiberPackageAnalysis(dir string) *fiberPackageAnalysis {
+	key := analysisKey(dir)
+
 	fiberAnalysisMutex.RLock()
-	if cached, ok := fiberAnalysisCache[dir]; ok {
+	if cached, ok := fiberAnalysisCache[key]; ok {
 		fiberAnalysisMutex.RUnlock()
 		return cached
 	}
@@ -70,41 +79,70 @@ func loadFiberPackageAnalysis(dir string) *fiberPackageAnalysis {
 	fiberAnalysisMutex.Lock()
 	defer fiberAnalysisMutex.Unlock()
 
-	if cached, ok := fiberAnalysisCache[dir]; ok {
+	if cached, ok := fiberAnalysisCache[key]; ok {
 		return cached
 	}
 
-	pkgAnalysis, err := analyzeFiberDirectory(dir)
+	pkgAnalysis, err := analyzeFiberDirectoryCached(dir)
 	if err != nil {
-		// Silently ignore analysis errors to avoid breaking docs generation.
-		fiberAnalysisCache[dir] = nil
+		recordAnalysisFailure(dir, err)
+		fiberAnalysisCache[key] = nil
 		return nil
 	}
 
-	fiberAnalysisCache[dir] = pkgAnalysis
+	fiberAnalysisCache[key] = pkgAnalysis
 	return pkgAnalysis
 }
 
+// analyzeFiberDirectoryCached runs analyzeFiberDirectory through the persistent analysis cache when it is enabled.
+func analyzeFiberDirectoryCached(dir string) (*fiberPackageAnalysis, error) {
+	if getAnalysisCacheDir() == "" {
+		return analyzeFiberDirectory(dir, nil)
+	}
+
+	handlers, err := withAnalysisCache("fiber", dir, func(only map[string]bool) (map[string][]handlerCacheEntry[FiberHandlerMetadata], error) {
+		pkgAnalysis, err := analyzeFiberDirectory(dir, only)
+		if err != nil {
+			return nil, err
+		}
+		return convertHandlers(pkgAnalysis.handlers, func(h fiberAnalyzedHandler) handlerCacheEntry[FiberHandlerMetadata] {
+			return handlerCacheEntry[FiberHandlerMetadata]{
+				FilePath:     h.filePath,
+				FuncName:     h.funcName,
+				ReceiverName: h.receiverName,
+				StartLine:    h.startLine,
+				Metadata:     h.metadata,
+			}
+		}), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &fiberPackageAnalysis{
+		handlers: convertHandlers(handlers, func(e handlerCacheEntry[FiberHandlerMetadata]) fiberAnalyzedHandler {
+			return fiberAnalyzedHandler{
+				filePath:     e.FilePath,
+				funcName:     e.FuncName,
+				receiverName: e.ReceiverName,
+				startLine:    e.StartLine,
+				metadata:     e.Metadata,
+			}
+		}),
+	}, nil
+}
+
 // analyzeFiberDirectory walks all Go files in a directory to extract Fiber handler metadata.
-func analyzeFiberDirectory(dir string) (*fiberPackageAnalysis, error) {
+func analyzeFiberDirectory(dir string, only map[string]bool) (*fiberPackageAnalysis, error) {
 	fset := token.NewFileSet()
-	pkgs, err := parser.ParseDir(fset, dir, func(info fs.FileInfo) bool {
-		if info.IsDir() {
-			return false
-		}
-		name := info.Name()
-		if !strings.HasSuffix(name, ".go") {
-			return false
-		}
-		return !strings.HasSuffix(name, "_test.go")
-	}, parser.ParseComments)
+	pkgs, err := parseGoDir(fset, dir)
 	if err != nil {
 		return nil, err
 	}
 
 	structs := collectStructDefinitions(pkgs)
 	functions := collectFunctionSignatures(pkgs)
-	handlers := collectFiberHandlerMetadata(fset, pkgs, structs, functions)
+	handlers := collectFiberHandlerMetadata(fset, restrictPackageFiles(pkgs, only), structs, functions)
 
 	return &fiberPackageAnalysis{
 		handlers:  handlers,
@@ -114,51 +152,46 @@ func analyzeFiberDirectory(dir string) (*fiberPackageAnalysis, error) {
 
 // collectFiberHandlerMetadata extracts documentation metadata for Fiber function declarations.
 func collectFiberHandlerMetadata(fset *token.FileSet, pkgs map[string]*ast.Package, structs map[string]*ast.StructType, functions map[string][]functionSignature) map[string][]fiberAnalyzedHandler {
-	handlers := make(map[string][]fiberAnalyzedHandler)
-
-	for _, pkg := range pkgs {
-		for _, file := range pkg.Files {
-			for _, decl := range file.Decls {
-				fn, ok := decl.(*ast.FuncDecl)
-				if !ok {
-					continue
-				}
-
-				// Check if this is likely a Fiber handler (has *fiber.Ctx parameter)
-				if !isFiberHandler(fn) {
-					continue
-				}
+	return collectPerFile(pkgs, func(file *ast.File, handlers map[string][]fiberAnalyzedHandler) {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
 
-				var comments []string
-				if fn.Doc != nil {
-					comments = extractCommentsText(fn.Doc.List)
-				}
-				info := parseFiberHandlerInfo(comments)
-				analysis := analyzeFiberHandlerDetails(fn, structs, functions)
-
-				pos := fset.Position(fn.Pos())
-				receiverName := receiverTypeName(fn.Recv)
-				funcName := fn.Name.Name
-
-				key := strings.ToLower(funcName)
-				handlerEntry := fiberAnalyzedHandler{
-					filePath:     pos.Filename,
-					funcName:     funcName,
-					receiverName: receiverName,
-					startLine:    pos.Line,
-					metadata: FiberHandlerMetadata{
-						Info:        info,
-						RequestBody: analysis.RequestBody,
-						Responses:   analysis.Responses,
-					},
-				}
+			// Check if this is likely a Fiber handler (has *fiber.Ctx parameter)
+			if !isFiberHandler(fn) {
+				continue
+			}
 
-				handlers[key] = append(handlers[key], handlerEntry)
+			var comments []string
+			if fn.Doc != nil {
+				comments = extractCommentsText(fn.Doc.List)
+			}
+			info := parseFiberHandlerInfo(comments)
+			analysis := analyzeFiberHandlerDetails(fn, structs, functions)
+
+			pos := fset.Position(fn.Pos())
+			receiverName := receiverDeclName(fn.Recv)
+			funcName := fn.Name.Name
+
+			key := strings.ToLower(funcName)
+			handlerEntry := fiberAnalyzedHandler{
+				filePath:     pos.Filename,
+				funcName:     funcName,
+				receiverName: receiverName,
+				startLine:    pos.Line,
+				metadata: FiberHandlerMetadata{
+					Info:        info,
+					RequestBody: analysis.RequestBody,
+					Responses:   analysis.Responses,
+					Trace:       analysis.Trace,
+				},
 			}
-		}
-	}
 
-	return handlers
+			handlers[key] = append(handlers[key], handlerEntry)
+		}
+	})
 }
 
 // isFiberHandler checks if a function is likely a Fiber handler by looking for *fiber.Ctx parameter
@@ -195,6 +228,7 @@ func isFiberHandler(fn *ast.FuncDecl) bool {
 type fiberHandlerAnalysis struct {
 	RequestBody *core.RequestBody
 	Responses   map[string]core.Response
+	Trace       []string // analysis steps, when debugging
 }
 
 // analyzeFiberHandlerDetails inspects a Fiber handler function to infer request bodies and responses.
@@ -212,7 +246,9 @@ func analyzeFiberHandlerDetails(fn *ast.FuncDecl, structs map[string]*ast.Struct
 		functions: functions,
 		variables: make(map[string]ast.Expr),
 		values:    make(map[string]ast.Expr),
+		trace:     newHandlerTrace(),
 	}
+	registerFuncParamTypes(fn, ctx)
 
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
 		switch node := n.(type) {
@@ -226,19 +262,34 @@ func analyzeFiberHandlerDetails(fn *ast.FuncDecl, structs map[string]*ast.Struct
 			// Detect request body binding for Fiber
 			if analysis.RequestBody == nil && isFiberBindingCall(node) {
 				if len(node.Args) > 0 {
-					if resolved := resolveFiberRequestBody(node, node.Args[0], ctx); resolved != nil {
+					resolved := resolveFiberRequestBody(node, node.Args[0], ctx)
+					ctx.traceBinding(node, resolved)
+					if resolved != nil {
 						analysis.RequestBody = resolved
 					}
 				}
 			}
 
+			if statusCode, response, ok := streamResponse(node, ctx); ok {
+				analysis.Responses[statusCode] = response
+				ctx.traceResponse(node, &ast.BasicLit{Kind: token.INT, Value: statusCode}, statusCode, nil, response.Schema)
+				return true
+			}
+
 			// Detect response generation calls for Fiber
-			if contentType, statusExpr, dataExpr, ok := fiberResponseCallInfo(node, ctx); ok {
+			contentType, statusExpr, dataExpr, ok := errorCallInfo(node, ctx)
+			if !ok {
+				contentType, statusExpr, dataExpr, ok = fiberResponseCallInfo(node, ctx)
+			}
+			if !ok {
+				contentType, statusExpr, dataExpr, ok = responseHelperCallInfo(node, ctx)
+			}
+			if ok {
 				statusCode := extractStatusCode(statusExpr, ctx)
 				if statusCode == "" {
 					statusCode = "200"
 				}
-				payloadExpr := resolveResponsePayloadExpr(dataExpr, ctx)
+				payloadExpr := resolveResponsePayloadExpr(errorPayloadExpr(statusCode, dataExpr, ctx), ctx)
 				schema, example := buildSchemaFromExpr(payloadExpr, ctx, make(map[string]bool))
 				example = normalizeExampleWithSchema(schema, example)
 				if example == nil {
@@ -257,11 +308,13 @@ func analyzeFiberHandlerDetails(fn *ast.FuncDecl, structs map[string]*ast.Struct
 					response.Description = "Response"
 				}
 				analysis.Responses[statusCode] = response
+				ctx.traceResponse(node, statusExpr, statusCode, payloadExpr, schema)
 			}
 		}
 		return true
 	})
 
+	analysis.Trace = ctx.trace.finish(analysis.RequestBody != nil, len(analysis.Responses))
 	return analysis
 }
 
@@ -329,10 +382,6 @@ func fiberResponseCallInfo(call *ast.CallExpr, ctx *analysisContext) (contentTyp
 		if len(call.Args) >= 1 {
 			return "application/xml", &ast.BasicLit{Kind: 9, Value: "200"}, call.Args[0], true
 		}
-	case "SendFile":
-		if len(call.Args) >= 1 {
-			return "application/octet-stream", &ast.BasicLit{Kind: 9, Value: "200"}, call.Args[0], true
-		}
 	case "SendStatus":
 		if len(call.Args) >= 1 {
 			return "", call.Args[0], &ast.BasicLit{Kind: 10, Value: `""`}, true
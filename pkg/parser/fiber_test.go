@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+func TestSetupFiberDocs_HidesHeadCopiesAndStaticRoutesByDefault(t *testing.T) {
+	documented := func(config *core.Config) []string {
+		app := fiber.New()
+		app.Get("/users", func(c *fiber.Ctx) error { return nil })
+		app.Get("/healthz", func(c *fiber.Ctx) error { return nil })
+		app.Get("/static/app.js", func(c *fiber.Ctx) error { return nil })
+		SetupFiberDocs(app, config)
+
+		response, err := app.Test(httptest.NewRequest("GET", "/docs/api-data.json", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(response.Body)
+		var documentation core.Documentation
+		if err := json.Unmarshal(body, &documentation); err != nil {
+			t.Fatalf("expected the docs data, got %d %s", response.StatusCode, body)
+		}
+		var routes []string
+		for _, section := range documentation.Endpoints {
+			for _, endpoint := range section.Endpoints {
+				routes = append(routes, endpoint.Method+" "+endpoint.Path)
+			}
+		}
+		sort.Strings(routes)
+		return routes
+	}
+
+	if routes := documented(&core.Config{DocsPath: "/docs", AutoDetect: true}); strings.Join(routes, ",") != "GET /users" {
+		t.Fatalf("expected only the application route, got %v", routes)
+	}
+	routes := documented(&core.Config{DocsPath: "/docs", AutoDetect: true, ExcludePaths: []string{}, SkipMethods: []string{}})
+	if strings.Join(routes, ",") != "GET /healthz,GET /static/app.js,GET /users,HEAD /healthz,HEAD /static/app.js,HEAD /users" {
+		t.Fatalf("expected explicit empty filters to document every route, got %v", routes)
+	}
+}
@@ -0,0 +1,158 @@
+package parser
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// FrameworkRoute is a route registered on a router, as listed by a FrameworkAdapter
+type FrameworkRoute struct {
+	Method  string
+	Path    string      // ":id" and "{id}" parameters are both understood
+	Handler interface{} // as registered on the router
+}
+
+// FrameworkAdapter documents a router ByteDocs has no built-in support for,
+// e.g. httprouter, bunrouter or goji
+type FrameworkAdapter interface {
+	// ListRoutes returns the routes registered on the router
+	ListRoutes() []FrameworkRoute
+	// ResolveHandler returns the handler function behind a registered
+	// handler, unwrapping router-specific types and middleware, or nil
+	ResolveHandler(handler interface{}) interface{}
+	// AnalyzeHandler documents a resolved handler; Method and Path of the
+	// result are set from the route. Handlers that take an
+	// http.ResponseWriter and an *http.Request can use AnalyzeHTTPHandler.
+	AnalyzeHandler(handler interface{}) core.RouteInfo
+}
+
+// frameworkOpener returns the adapter of a router it supports
+type frameworkOpener struct {
+	name string
+	open func(router interface{}) (FrameworkAdapter, bool)
+}
+
+var (
+	frameworks      []frameworkOpener
+	frameworksMutex sync.RWMutex
+)
+
+// RegisterFramework makes a router type documentable with SetupDocs. open
+// returns the adapter of router, or false when router is not of its type.
+// Registering a name again replaces its opener; call it from an init function.
+func RegisterFramework(name string, open func(router interface{}) (FrameworkAdapter, bool)) {
+	if open == nil {
+		return
+	}
+	frameworksMutex.Lock()
+	defer frameworksMutex.Unlock()
+	for i, framework := range frameworks {
+		if framework.name == name {
+			frameworks[i].open = open
+			return
+		}
+	}
+	frameworks = append(frameworks, frameworkOpener{name: name, open: open})
+}
+
+// SetupDocs sets up documentation for a router of a registered framework.
+// Mount the returned APIDocs, an http.Handler, under config.DocsPath.
+func SetupDocs(router interface{}, config *core.Config) (*core.APIDocs, error) {
+	frameworksMutex.RLock()
+	defer frameworksMutex.RUnlock()
+	for _, framework := range frameworks {
+		if adapter, ok := framework.open(router); ok {
+			return SetupFrameworkDocs(adapter, config), nil
+		}
+	}
+	return nil, fmt.Errorf("no framework registered for router %T", router)
+}
+
+// SetupFrameworkDocs sets up documentation for the router of adapter with
+// auto-detection. Mount the returned APIDocs, an http.Handler, under
+// config.DocsPath unless the docs are served on a separate port.
+func SetupFrameworkDocs(adapter FrameworkAdapter, config *core.Config) *core.APIDocs {
+	if config == nil {
+		config = &core.Config{
+			Title:      "API Documentation",
+			Version:    "1.0.0",
+			DocsPath:   "/docs",
+			AutoDetect: true,
+		}
+	}
+
+	analysis := newAnalysisSettings(config)
+
+	docs := core.New(config)
+
+	// Refresh analyzes the handlers from source again
+	docs.OnRefresh(analysis.resetCaches)
+
+	// Routes are detected on Warmup or the first docs request, and again on Refresh
+	if config.AutoDetect {
+		docs.SetRouteDetector(analysis.scoped(func() {
+			routes := adapter.ListRoutes()
+			fmt.Printf("🔍 Detecting routes, found: %d\n", len(routes))
+
+			for _, route := range routes {
+				// Skip docs routes and static files
+				if strings.HasPrefix(route.Path, config.DocsPath) {
+					fmt.Printf("⏭️  Skipping route: %s\n", route.Path)
+					continue
+				}
+
+				routeInfo := core.RouteInfo{Warnings: unresolvedHandlerWarnings()}
+				if handler := adapter.ResolveHandler(route.Handler); handler != nil {
+					routeInfo = adapter.AnalyzeHandler(handler)
+					routeInfo.Handler = handler
+				}
+				routeInfo.Method = route.Method
+				routeInfo.Path = route.Path
+
+				fmt.Printf("✅ Adding route: %s %s\n", route.Method, route.Path)
+				docs.AddRouteInfo(routeInfo)
+			}
+
+			docs.Generate()
+		}))
+	}
+
+	serveDocsSeparately(docs, config)
+	return docs
+}
+
+// AnalyzeHTTPHandler documents a handler that takes an http.ResponseWriter
+// and an *http.Request, plus any router-specific arguments such as route
+// params, from its source and doc comment
+func AnalyzeHTTPHandler(handler interface{}) core.RouteInfo {
+	var metadata GorillaMuxHandlerMetadata
+	if h, ok := handler.(http.Handler); ok {
+		metadata = getGorillaMuxHandlerMetadata(h)
+	} else if ref, ok := handlerRefFromFunc(handler); ok {
+		metadata = lookupGorillaMuxHandlerMetadata(ref)
+	} else {
+		return core.RouteInfo{Warnings: unresolvedHandlerWarnings()}
+	}
+
+	return core.RouteInfo{
+		Summary:      metadata.Info.Summary,
+		Description:  metadata.Info.Description,
+		Parameters:   metadata.Info.Parameters,
+		Section:      metadata.Info.Section,
+		ExternalDocs: metadata.Info.ExternalDocs,
+		Presets:      metadata.Info.Presets,
+		SLA:          metadata.Info.SLA,
+		Pagination:   metadata.Info.Pagination,
+		Accepts:      metadata.Info.Accepts,
+		ResponseDocs: metadata.Info.ResponseDocs,
+		Extensions:   metadata.Info.Extensions,
+		RequestBody:  metadata.RequestBody,
+		Responses:    metadata.Responses,
+		Warnings:     metadata.Warnings,
+		Trace:        metadata.Trace,
+	}
+}
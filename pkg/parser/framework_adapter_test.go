@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+type testRouter struct {
+	routes []FrameworkRoute
+}
+
+// testHandle mimics a router-specific handler type wrapping the real handler
+type testHandle struct {
+	fn func(http.ResponseWriter, *http.Request)
+}
+
+type testAdapter struct {
+	router *testRouter
+}
+
+func (a testAdapter) ListRoutes() []FrameworkRoute { return a.router.routes }
+
+func (a testAdapter) ResolveHandler(handler interface{}) interface{} {
+	if handle, ok := handler.(testHandle); ok {
+		return handle.fn
+	}
+	return nil
+}
+
+func (a testAdapter) AnalyzeHandler(handler interface{}) core.RouteInfo {
+	return core.RouteInfo{Method: "IGNORED", Summary: "Get a user", Section: "users"}
+}
+
+func TestSetupDocs_UsesRegisteredFramework(t *testing.T) {
+	RegisterFramework("test-router", func(router interface{}) (FrameworkAdapter, bool) {
+		r, ok := router.(*testRouter)
+		return testAdapter{router: r}, ok
+	})
+
+	router := &testRouter{routes: []FrameworkRoute{
+		{Method: "GET", Path: "/users/:id", Handler: testHandle{fn: func(w http.ResponseWriter, r *http.Request) {}}},
+		{Method: "POST", Path: "/users", Handler: "unresolvable"},
+		{Method: "GET", Path: "/docs/*path", Handler: testHandle{}},
+	}}
+	docs, err := SetupDocs(router, &core.Config{DocsPath: "/docs", AutoDetect: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := docs.Warmup(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	documentation := docs.GetDocumentation()
+	var endpoints []core.Endpoint
+	for _, section := range documentation.Endpoints {
+		endpoints = append(endpoints, section.Endpoints...)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("expected the docs route to be skipped, got %+v", endpoints)
+	}
+	found := false
+	for _, endpoint := range endpoints {
+		if endpoint.Method == "GET" && endpoint.Path == "/users/{id}" && endpoint.Summary == "Get a user" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected the analyzed route at its listed method and path, got %+v", endpoints)
+	}
+	if len(documentation.Warnings) != 1 || documentation.Warnings[0].Path != "/users" || !strings.Contains(documentation.Warnings[0].Message, "could not be resolved") {
+		t.Fatalf("expected a warning for the unresolved handler, got %+v", documentation.Warnings)
+	}
+
+	if _, err := SetupDocs(struct{}{}, nil); err == nil {
+		t.Fatal("expected an error for a router without a registered framework")
+	}
+}
+
+// sourceAdapter documents every route with the analysis of GetUser in dir
+type sourceAdapter struct {
+	dir string
+}
+
+func (a sourceAdapter) ListRoutes() []FrameworkRoute {
+	return []FrameworkRoute{{Method: "GET", Path: "/users/:id", Handler: "GetUser"}}
+}
+
+func (a sourceAdapter) ResolveHandler(handler interface{}) interface{} { return handler }
+
+func (a sourceAdapter) AnalyzeHandler(handler interface{}) core.RouteInfo {
+	analysis := loadPackageAnalysis(a.dir)
+	if analysis == nil {
+		return core.RouteInfo{}
+	}
+	metadata := analysis.handlers["getuser"][0].metadata
+	return core.RouteInfo{Responses: metadata.Responses}
+}
+
+func TestRegistry_TenantsKeepTheirErrorConventions(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+type ErrorResponse struct {
+	Message string ` + "`json:\"message\"`" + `
+}
+
+type Problem struct {
+	Title string ` + "`json:\"title\"`" + `
+}
+
+func GetUser(c *gin.Context) {
+	respondError(c, http.StatusNotFound, "not found")
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(resetAnalysisCaches)
+
+	orders := SetupFrameworkDocs(sourceAdapter{dir: dir}, &core.Config{Title: "Orders", DocsPath: "/docs", AutoDetect: true,
+		ErrorHelpers: []string{"respondError:1"}})
+	billing := SetupFrameworkDocs(sourceAdapter{dir: dir}, &core.Config{Title: "Billing", DocsPath: "/docs", AutoDetect: true,
+		ErrorResponseType: "Problem", ErrorHelpers: []string{"respondError:1"}})
+	registry := core.NewRegistry()
+	for _, tenant := range []core.Tenant{{Name: "orders", Prefix: "/orders", Docs: orders}, {Name: "billing", Prefix: "/billing", Docs: billing}} {
+		if err := registry.Mount(tenant); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := registry.Warmup(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	notFound := func(docs *core.APIDocs) map[string]interface{} {
+		for _, section := range docs.GetDocumentation().Endpoints {
+			for _, endpoint := range section.Endpoints {
+				schema, _ := endpoint.Responses["404"].Schema.(map[string]interface{})
+				props, _ := schema["properties"].(map[string]interface{})
+				return props
+			}
+		}
+		return nil
+	}
+	if props := notFound(orders); props["message"] == nil {
+		t.Fatalf("expected orders to document ErrorResponse, got %+v", props)
+	}
+	if props := notFound(billing); props["title"] == nil {
+		t.Fatalf("expected billing to document Problem, got %+v", props)
+	}
+
+	// Refreshing one tenant leaves the analyses of the other cached
+	if err := billing.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	ordersKey := newAnalysisSettings(&core.Config{ErrorHelpers: []string{"respondError:1"}}).fingerprint() + "\x00" + dir
+	analysisMutex.RLock()
+	_, kept := analysisCache[ordersKey]
+	analysisMutex.RUnlock()
+	if !kept {
+		t.Fatal("expected the orders analysis to survive a billing refresh")
+	}
+	if props := notFound(orders); props["message"] == nil {
+		t.Fatalf("expected orders unchanged by the billing refresh, got %+v", props)
+	}
+}
@@ -6,22 +6,24 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/idnexacloud/bytedocs-go/pkg/core"
 	"github.com/gin-gonic/gin"
 )
 
-var (
-	globalDocs *core.APIDocs
-	docsConfig *core.Config
-	docsMutex  sync.RWMutex
-)
-
 type HandlerInfo struct {
-	Summary     string
-	Description string
-	Parameters  []core.Parameter
+	Summary      string
+	Description  string
+	Parameters   []core.Parameter
+	Section      string             // from "@Section <id>"
+	ExternalDocs *core.ExternalDocs // from "@Docs <url> [description]"
+	Presets      []core.Preset      // from "@Preset <name> [param=value ...] [json body]"
+	SLA          time.Duration      // from "@SLA <duration>"
+	Pagination   string             // from "@Paginate <style>"
+	Accepts      []string           // from "@Accept <content types>"
+	ResponseDocs map[string]string  // from "@Response <status> <description>"
+	Extensions   core.Extensions    // from "@x-<name> [value]"
 }
 
 func extractCommentsText(comments []*ast.Comment) []string {
@@ -45,6 +47,44 @@ func parseHandlerInfo(comments []string) HandlerInfo {
 	paramRegex := regexp.MustCompile(`@Param\s+(\w+)\s+(\w+)\s+(\w+)\s+(true|false)\s+"([^"]*)"`)
 
 	for _, line := range comments {
+		if section, ok := sectionAnnotation(line); ok {
+			info.Section = section
+			continue
+		}
+		if externalDocs, ok := docsAnnotation(line); ok {
+			info.ExternalDocs = externalDocs
+			continue
+		}
+		if preset, ok := presetAnnotation(line); ok {
+			info.Presets = append(info.Presets, preset)
+			continue
+		}
+		if sla, ok := slaAnnotation(line); ok {
+			info.SLA = sla
+			continue
+		}
+		if style, ok := paginateAnnotation(line); ok {
+			info.Pagination = style
+			continue
+		}
+		if accepts, ok := acceptAnnotation(line); ok {
+			info.Accepts = append(info.Accepts, accepts...)
+			continue
+		}
+		if status, description, ok := responseAnnotation(line); ok {
+			if info.ResponseDocs == nil {
+				info.ResponseDocs = make(map[string]string)
+			}
+			info.ResponseDocs[status] = description
+			continue
+		}
+		if name, value, ok := extensionAnnotation(line); ok {
+			if info.Extensions == nil {
+				info.Extensions = make(core.Extensions)
+			}
+			info.Extensions[name] = value
+			continue
+		}
 		if matches := paramRegex.FindStringSubmatch(line); len(matches) == 6 {
 			param := core.Parameter{
 				Name:        matches[1],
@@ -86,8 +126,9 @@ func extractHandlerName(handler interface{}) string {
 	return ""
 }
 
-// SetupGinDocs sets up documentation for a Gin engine with auto-detection
-func SetupGinDocs(engine *gin.Engine, config *core.Config) {
+// SetupGinDocs sets up documentation for a Gin engine with auto-detection.
+// The returned APIDocs can be used to customize the generated documentation
+func SetupGinDocs(engine *gin.Engine, config *core.Config) *core.APIDocs {
 	if config == nil {
 		config = &core.Config{
 			Title:      "API Documentation",
@@ -97,47 +138,60 @@ func SetupGinDocs(engine *gin.Engine, config *core.Config) {
 		}
 	}
 
-	docsMutex.Lock()
-	docsConfig = config
-	globalDocs = core.New(config)
-	docsMutex.Unlock()
-
+	analysis := newAnalysisSettings(config)
 
-	engine.Any(config.DocsPath+"/*path", func(c *gin.Context) {
-		docsMutex.Lock()
-		defer docsMutex.Unlock()
+	docs := core.New(config)
 
-		endpointsCount := len(globalDocs.GetDocumentation().Endpoints)
+	// Refresh analyzes the handlers from source again
+	docs.OnRefresh(analysis.resetCaches)
 
-		if endpointsCount == 0 && config.AutoDetect {
+	// Routes are detected on Warmup or the first docs request, and again on Refresh
+	if config.AutoDetect {
+		docs.SetRouteDetector(analysis.scoped(func() {
 			routes := engine.Routes()
 
 			for _, route := range routes {
-				if strings.HasPrefix(route.Path, config.DocsPath) ||
-					strings.Contains(route.Path, "/static") ||
-					strings.Contains(route.Path, "/assets") {
+				if strings.HasPrefix(route.Path, config.DocsPath) {
 					continue
 				}
 
 				metadata := getHandlerMetadata(route.HandlerFunc)
 
 				routeInfo := core.RouteInfo{
-					Method:      route.Method,
-					Path:        route.Path,
-					Handler:     route.HandlerFunc,
-					Summary:     metadata.Info.Summary,
-					Description: metadata.Info.Description,
-					Parameters:  metadata.Info.Parameters,
-					RequestBody: metadata.RequestBody,
-					Responses:   metadata.Responses,
+					Method:       route.Method,
+					Path:         route.Path,
+					Handler:      route.HandlerFunc,
+					Summary:      metadata.Info.Summary,
+					Description:  metadata.Info.Description,
+					Parameters:   metadata.Info.Parameters,
+					Section:      metadata.Info.Section,
+					ExternalDocs: metadata.Info.ExternalDocs,
+					Presets:      metadata.Info.Presets,
+					SLA:          metadata.Info.SLA,
+					Pagination:   metadata.Info.Pagination,
+					Accepts:      metadata.Info.Accepts,
+					ResponseDocs: metadata.Info.ResponseDocs,
+					Extensions:   metadata.Info.Extensions,
+					RequestBody:  metadata.RequestBody,
+					Responses:    metadata.Responses,
+					Warnings:     metadata.Warnings,
+					Trace:        metadata.Trace,
 				}
 
-				globalDocs.AddRouteInfo(routeInfo)
+				docs.AddRouteInfo(routeInfo)
 			}
 
-			globalDocs.Generate()
-		}
+			docs.Generate()
+		}))
+	}
 
-		globalDocs.ServeHTTP(c.Writer, c.Request)
+	if serveDocsSeparately(docs, config) {
+		return docs
+	}
+
+	engine.Any(config.DocsPath+"/*path", func(c *gin.Context) {
+		docs.ServeHTTP(c.Writer, c.Request)
 	})
+
+	return docs
 }
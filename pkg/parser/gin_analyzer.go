@@ -3,13 +3,9 @@ package parser
 import (
 	"encoding/json"
 	"go/ast"
-	"go/parser"
 	"go/token"
-	"io/fs"
 	"net/http"
-	"path/filepath"
 	"reflect"
-	"runtime"
 	"strconv"
 	"strings"
 	"sync"
@@ -22,6 +18,8 @@ type HandlerMetadata struct {
 	Info        HandlerInfo
 	RequestBody *core.RequestBody
 	Responses   map[string]core.Response
+	Warnings    []string `json:"-"` // why no schemas were inferred; not cached
+	Trace       []string `json:"-"` // analysis steps when debugging; not cached
 }
 
 // analyzedHandler keeps track of metadata for an individual handler within a package.
@@ -42,6 +40,7 @@ type packageAnalysis struct {
 type functionSignature struct {
 	receiver string
 	results  []ast.Expr
+	helper   *core.ResponseHelper // set when the function writes a response it is given
 }
 
 var (
@@ -55,57 +54,29 @@ func getHandlerMetadata(handler interface{}) HandlerMetadata {
 		return HandlerMetadata{}
 	}
 
-	handlerValue := reflect.ValueOf(handler)
-	if handlerValue.Kind() != reflect.Func {
-		return HandlerMetadata{}
-	}
-
-	fn := runtime.FuncForPC(handlerValue.Pointer())
-	if fn == nil {
-		return HandlerMetadata{}
-	}
-
-	entry := fn.Entry()
-	file, line := fn.FileLine(entry)
-	if file == "" {
-		return HandlerMetadata{}
+	ref, ok := handlerRefFromFunc(handler)
+	if !ok {
+		return HandlerMetadata{Warnings: unresolvedHandlerWarnings()}
 	}
 
-	packageMeta := loadPackageAnalysis(filepath.Dir(file))
+	packageMeta := loadPackageAnalysis(ref.dir)
 	if packageMeta == nil {
-		return HandlerMetadata{}
-	}
-
-	runtimeName := fn.Name()
-	funcName, receiverName := parseRuntimeFuncName(runtimeName)
-
-	key := strings.ToLower(funcName)
-	candidates := packageMeta.handlers[key]
-	if len(candidates) == 0 {
-		return HandlerMetadata{}
+		return HandlerMetadata{Warnings: packageWarnings(ref)}
 	}
 
-	normalizedFile := filepath.Clean(file)
-	for _, candidate := range candidates {
-		if filepath.Clean(candidate.filePath) != normalizedFile {
-			continue
-		}
-		// Receiver names must match; empty receiver matches standalone functions.
-		if candidate.receiverName != receiverName {
-			continue
-		}
-		if line >= candidate.startLine {
-			return candidate.metadata
-		}
+	if candidate, ok := selectHandler(packageMeta.handlers[strings.ToLower(ref.funcName)], ref); ok {
+		return candidate.metadata
 	}
 
-	return HandlerMetadata{}
+	return HandlerMetadata{Warnings: handlerNotFoundWarnings(ref)}
 }
 
 // loadPackageAnalysis parses and caches metadata for all handlers within a directory.
 func loadPackageAnalysis(dir string) *packageAnalysis {
+	key := analysisKey(dir)
+
 	analysisMutex.RLock()
-	if cached, ok := analysisCache[dir]; ok {
+	if cached, ok := analysisCache[key]; ok {
 		analysisMutex.RUnlock()
 		return cached
 	}
@@ -114,21 +85,59 @@ func loadPackageAnalysis(dir string) *packageAnalysis {
 	analysisMutex.Lock()
 	defer analysisMutex.Unlock()
 
-	if cached, ok := analysisCache[dir]; ok {
+	if cached, ok := analysisCache[key]; ok {
 		return cached
 	}
 
-	pkgAnalysis, err := analyzeDirectory(dir)
+	pkgAnalysis, err := analyzeDirectoryCached("gin", dir, analyzeDirectory)
 	if err != nil {
-		// Silently ignore analysis errors to avoid breaking docs generation.
-		analysisCache[dir] = nil
+		recordAnalysisFailure(dir, err)
+		analysisCache[key] = nil
 		return nil
 	}
 
-	analysisCache[dir] = pkgAnalysis
+	analysisCache[key] = pkgAnalysis
 	return pkgAnalysis
 }
 
+// analyzeDirectoryCached runs analyze through the persistent analysis cache when it is enabled.
+func analyzeDirectoryCached(kind, dir string, analyze func(dir string, only map[string]bool) (*packageAnalysis, error)) (*packageAnalysis, error) {
+	if getAnalysisCacheDir() == "" {
+		return analyze(dir, nil)
+	}
+
+	handlers, err := withAnalysisCache(kind, dir, func(only map[string]bool) (map[string][]handlerCacheEntry[HandlerMetadata], error) {
+		pkgAnalysis, err := analyze(dir, only)
+		if err != nil {
+			return nil, err
+		}
+		return convertHandlers(pkgAnalysis.handlers, func(h analyzedHandler) handlerCacheEntry[HandlerMetadata] {
+			return handlerCacheEntry[HandlerMetadata]{
+				FilePath:     h.filePath,
+				FuncName:     h.funcName,
+				ReceiverName: h.receiverName,
+				StartLine:    h.startLine,
+				Metadata:     h.metadata,
+			}
+		}), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &packageAnalysis{
+		handlers: convertHandlers(handlers, func(e handlerCacheEntry[HandlerMetadata]) analyzedHandler {
+			return analyzedHandler{
+				filePath:     e.FilePath,
+				funcName:     e.FuncName,
+				receiverName: e.ReceiverName,
+				startLine:    e.StartLine,
+				metadata:     e.Metadata,
+			}
+		}),
+	}, nil
+}
+
 // parseRuntimeFuncName extracts the function and receiver names from a runtime symbol.
 func parseRuntimeFuncName(fullName string) (funcName string, receiverName string) {
 	trimmed := fullName
@@ -182,25 +191,16 @@ func normalizeReceiverName(receiver string) string {
 }
 
 // analyzeDirectory walks all Go files in a directory to extract handler metadata.
-func analyzeDirectory(dir string) (*packageAnalysis, error) {
+func analyzeDirectory(dir string, only map[string]bool) (*packageAnalysis, error) {
 	fset := token.NewFileSet()
-	pkgs, err := parser.ParseDir(fset, dir, func(info fs.FileInfo) bool {
-		if info.IsDir() {
-			return false
-		}
-		name := info.Name()
-		if !strings.HasSuffix(name, ".go") {
-			return false
-		}
-		return !strings.HasSuffix(name, "_test.go")
-	}, parser.ParseComments)
+	pkgs, err := parseGoDir(fset, dir)
 	if err != nil {
 		return nil, err
 	}
 
 	structs := collectStructDefinitions(pkgs)
 	functions := collectFunctionSignatures(pkgs)
-	handlers := collectHandlerMetadata(fset, pkgs, structs, functions)
+	handlers := collectHandlerMetadata(fset, restrictPackageFiles(pkgs, only), structs, functions)
 
 	return &packageAnalysis{
 		handlers:  handlers,
@@ -237,6 +237,7 @@ func collectFunctionSignatures(pkgs map[string]*ast.Package) map[string][]functi
 				signature := functionSignature{
 					receiver: receiver,
 					results:  results,
+					helper:   inferResponseHelper(fn),
 				}
 
 				key := funcName
@@ -253,9 +254,77 @@ func collectFunctionSignatures(pkgs map[string]*ast.Package) map[string][]functi
 		}
 	}
 
+	collectInterfaceSignatures(pkgs, functions)
+
 	return functions
 }
 
+// collectInterfaceSignatures registers the methods of interface declarations,
+// including embedded interfaces, so calls on injected services such as
+// h.users.GetUser(id) resolve to the DTO types of the interface signature.
+func collectInterfaceSignatures(pkgs map[string]*ast.Package, functions map[string][]functionSignature) {
+	interfaces := make(map[string]*ast.InterfaceType)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range genDecl.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					if iface, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+						interfaces[typeSpec.Name.Name] = iface
+					}
+				}
+			}
+		}
+	}
+
+	var register func(name string, iface *ast.InterfaceType, visited map[string]bool)
+	register = func(name string, iface *ast.InterfaceType, visited map[string]bool) {
+		if iface.Methods == nil {
+			return
+		}
+		for _, method := range iface.Methods.List {
+			funcType, ok := method.Type.(*ast.FuncType)
+			if !ok {
+				// Embedded interface
+				if ident, ok := method.Type.(*ast.Ident); ok && interfaces[ident.Name] != nil && !visited[ident.Name] {
+					visited[ident.Name] = true
+					register(name, interfaces[ident.Name], visited)
+				}
+				continue
+			}
+
+			results := make([]ast.Expr, 0)
+			if funcType.Results != nil {
+				for _, result := range funcType.Results.List {
+					if len(result.Names) == 0 {
+						results = append(results, result.Type)
+					} else {
+						for range result.Names {
+							results = append(results, result.Type)
+						}
+					}
+				}
+			}
+			for _, methodName := range method.Names {
+				signature := functionSignature{receiver: name, results: results}
+				functions[name+"."+methodName.Name] = append(functions[name+"."+methodName.Name], signature)
+				functions[methodName.Name] = append(functions[methodName.Name], signature)
+			}
+		}
+	}
+
+	for name, iface := range interfaces {
+		register(name, iface, map[string]bool{name: true})
+	}
+}
+
 // collectStructDefinitions builds a lookup map of struct declarations in the parsed packages.
 func collectStructDefinitions(pkgs map[string]*ast.Package) map[string]*ast.StructType {
 	structs := make(map[string]*ast.StructType)
@@ -272,11 +341,22 @@ func collectStructDefinitions(pkgs map[string]*ast.Package) map[string]*ast.Stru
 					if !ok {
 						continue
 					}
+					doc := typeSpec.Doc
+					if doc == nil && len(genDecl.Specs) == 1 {
+						doc = genDecl.Doc
+					}
+					if _, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+						collectUnionDefinition(typeSpec.Name.Name, doc, structs)
+						continue
+					}
 					structType, ok := typeSpec.Type.(*ast.StructType)
 					if !ok {
 						continue
 					}
 					structs[typeSpec.Name.Name] = structType
+					if doc != nil {
+						structDocs.Store(structType, parseStructDoc(extractCommentsText(doc.List)))
+					}
 				}
 			}
 		}
@@ -285,48 +365,56 @@ func collectStructDefinitions(pkgs map[string]*ast.Package) map[string]*ast.Stru
 	return structs
 }
 
-// collectHandlerMetadata extracts documentation metadata for function declarations.
-func collectHandlerMetadata(fset *token.FileSet, pkgs map[string]*ast.Package, structs map[string]*ast.StructType, functions map[string][]functionSignature) map[string][]analyzedHandler {
-	handlers := make(map[string][]analyzedHandler)
+// structDocs holds the parsed doc comments of the collected struct types by
+// *ast.StructType, so schemas built from any analyzer carry them
+var structDocs sync.Map
 
-	for _, pkg := range pkgs {
-		for _, file := range pkg.Files {
-			for _, decl := range file.Decls {
-				fn, ok := decl.(*ast.FuncDecl)
-				if !ok {
-					continue
-				}
+// structDescription returns the doc comment of a collected struct type
+func structDescription(structType *ast.StructType) string {
+	doc, _ := structDocs.Load(structType)
+	parsed, _ := doc.(structDoc)
+	return parsed.description
+}
 
-				var comments []string
-				if fn.Doc != nil {
-					comments = extractCommentsText(fn.Doc.List)
-				}
-				info := parseHandlerInfo(comments)
-				analysis := analyzeHandlerDetails(fn, structs, functions)
+// collectHandlerMetadata extracts documentation metadata for function declarations.
+func collectHandlerMetadata(fset *token.FileSet, pkgs map[string]*ast.Package, structs map[string]*ast.StructType, functions map[string][]functionSignature) map[string][]analyzedHandler {
+	return collectPerFile(pkgs, func(file *ast.File, handlers map[string][]analyzedHandler) {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
 
-				pos := fset.Position(fn.Pos())
-				receiverName := receiverTypeName(fn.Recv)
-				funcName := fn.Name.Name
+			var comments []string
+			if fn.Doc != nil {
+				comments = extractCommentsText(fn.Doc.List)
+			}
+			info := parseHandlerInfo(comments)
+			analysis := analyzeHandlerDetails(fn, structs, functions)
+			// Parameters documented in comments win over those inferred from bindings
+			info.Parameters = appendParameters(info.Parameters, analysis.Parameters...)
 
-				key := strings.ToLower(funcName)
-				handlerEntry := analyzedHandler{
-					filePath:     pos.Filename,
-					funcName:     funcName,
-					receiverName: receiverName,
-					startLine:    pos.Line,
-					metadata: HandlerMetadata{
-						Info:        info,
-						RequestBody: analysis.RequestBody,
-						Responses:   analysis.Responses,
-					},
-				}
+			pos := fset.Position(fn.Pos())
+			receiverName := receiverDeclName(fn.Recv)
+			funcName := fn.Name.Name
 
-				handlers[key] = append(handlers[key], handlerEntry)
+			key := strings.ToLower(funcName)
+			handlerEntry := analyzedHandler{
+				filePath:     pos.Filename,
+				funcName:     funcName,
+				receiverName: receiverName,
+				startLine:    pos.Line,
+				metadata: HandlerMetadata{
+					Info:        info,
+					RequestBody: analysis.RequestBody,
+					Responses:   analysis.Responses,
+					Trace:       analysis.Trace,
+				},
 			}
-		}
-	}
 
-	return handlers
+			handlers[key] = append(handlers[key], handlerEntry)
+		}
+	})
 }
 
 // receiverTypeName returns a normalized receiver type ("" for functions).
@@ -450,6 +538,9 @@ func statusTextFromCode(code string) string {
 }
 
 func responseCallInfo(call *ast.CallExpr, ctx *analysisContext) (contentType string, statusExpr ast.Expr, dataExpr ast.Expr, ok bool) {
+	if contentType, statusExpr, dataExpr, ok := templateCallInfo(call, ctx); ok {
+		return contentType, statusExpr, dataExpr, true
+	}
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
 		return "", nil, nil, false
@@ -457,6 +548,16 @@ func responseCallInfo(call *ast.CallExpr, ctx *analysisContext) (contentType str
 
 	method := sel.Sel.Name
 	switch method {
+	case "HTML":
+		// c.HTML(code, name, data) renders a loaded template
+		if len(call.Args) >= 3 {
+			return "text/html", call.Args[0], htmlTemplateBody(call.Args[1]), true
+		}
+	case "Render":
+		// c.Render(code, renderer), e.g. render.HTML
+		if len(call.Args) >= 2 {
+			return "text/html", call.Args[0], htmlTemplateBody(nil), true
+		}
 	case "JSON", "IndentedJSON", "PureJSON", "SecureJSON":
 		if len(call.Args) >= 2 {
 			return "application/json", call.Args[0], call.Args[1], true
@@ -570,7 +671,7 @@ func resolveResponsePayloadExpr(expr ast.Expr, ctx *analysisContext) ast.Expr {
 					return resolveResponsePayloadExpr(e.Args[0], ctx)
 				}
 			}
-			receiverType := resolveTypeFromArg(sel.X, ctx)
+			receiverType := callReceiverType(sel.X, ctx)
 			receiverName := exprToString(receiverType)
 			if results := lookupFunctionResult(ctx, receiverName, sel.Sel.Name); len(results) > 0 {
 				return results[0]
@@ -596,6 +697,8 @@ func resolveResponsePayloadExpr(expr ast.Expr, ctx *analysisContext) ast.Expr {
 type handlerAnalysis struct {
 	RequestBody *core.RequestBody
 	Responses   map[string]core.Response
+	Parameters  []core.Parameter
+	Trace       []string // analysis steps, when debugging
 }
 
 type analysisContext struct {
@@ -603,6 +706,7 @@ type analysisContext struct {
 	functions map[string][]functionSignature
 	variables map[string]ast.Expr
 	values    map[string]ast.Expr
+	trace     *handlerTrace // nil unless analysis debugging is enabled
 }
 
 // analyzeHandlerDetails inspects a handler function to infer request bodies and responses.
@@ -620,7 +724,9 @@ func analyzeHandlerDetails(fn *ast.FuncDecl, structs map[string]*ast.StructType,
 		functions: functions,
 		variables: make(map[string]ast.Expr),
 		values:    make(map[string]ast.Expr),
+		trace:     newHandlerTrace(),
 	}
+	registerFuncParamTypes(fn, ctx)
 
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
 		switch node := n.(type) {
@@ -632,21 +738,41 @@ func analyzeHandlerDetails(fn *ast.FuncDecl, structs map[string]*ast.StructType,
 			registerRangeTypes(node, ctx)
 		case *ast.CallExpr:
 			// Detect request body binding
-			if analysis.RequestBody == nil && isBindingCall(node) {
+			if isBindingCall(node) {
 				if len(node.Args) > 0 {
-					if resolved := resolveRequestBody(node, node.Args[0], ctx); resolved != nil {
-						analysis.RequestBody = resolved
+					resolved := resolveRequestBody(node, node.Args[0], ctx)
+					ctx.traceBinding(node, resolved)
+					if resolved != nil {
+						analysis.RequestBody = mergeRequestBody(analysis.RequestBody, resolved)
 					}
 				}
 			}
 
+			// Detect uri/query/header struct binding
+			params := resolveBoundParameters(node, ctx, paramBindingMethods)
+			ctx.traceParameters(node, params)
+			analysis.Parameters = appendParameters(analysis.Parameters, params...)
+
+			if statusCode, response, ok := streamResponse(node, ctx); ok {
+				analysis.Responses[statusCode] = response
+				ctx.traceResponse(node, &ast.BasicLit{Kind: token.INT, Value: statusCode}, statusCode, nil, response.Schema)
+				return true
+			}
+
 			// Detect response generation calls
-			if contentType, statusExpr, dataExpr, ok := responseCallInfo(node, ctx); ok {
+			contentType, statusExpr, dataExpr, ok := errorCallInfo(node, ctx)
+			if !ok {
+				contentType, statusExpr, dataExpr, ok = responseCallInfo(node, ctx)
+			}
+			if !ok {
+				contentType, statusExpr, dataExpr, ok = responseHelperCallInfo(node, ctx)
+			}
+			if ok {
 				statusCode := extractStatusCode(statusExpr, ctx)
 				if statusCode == "" {
 					statusCode = "200"
 				}
-				payloadExpr := resolveResponsePayloadExpr(dataExpr, ctx)
+				payloadExpr := resolveResponsePayloadExpr(errorPayloadExpr(statusCode, dataExpr, ctx), ctx)
 				schema, example := buildSchemaFromExpr(payloadExpr, ctx, make(map[string]bool))
 				example = normalizeExampleWithSchema(schema, example)
 				if example == nil {
@@ -665,11 +791,13 @@ func analyzeHandlerDetails(fn *ast.FuncDecl, structs map[string]*ast.StructType,
 					response.Description = "Response"
 				}
 				analysis.Responses[statusCode] = response
+				ctx.traceResponse(node, statusExpr, statusCode, payloadExpr, schema)
 			}
 		}
 		return true
 	})
 
+	analysis.Trace = ctx.trace.finish(analysis.RequestBody != nil, len(analysis.Responses))
 	return analysis
 }
 
@@ -730,6 +858,114 @@ func resolveRequestBody(call *ast.CallExpr, arg ast.Expr, ctx *analysisContext)
 	return body
 }
 
+// paramBinding describes a binding call that fills a struct from request parameters
+type paramBinding struct {
+	tag    string // struct tag holding the parameter name
+	in     string // parameter location
+	tagged bool   // skip fields without the tag instead of using their Go name
+}
+
+// paramBindingMethods are the gin binding methods that read path, query and header parameters
+var paramBindingMethods = map[string]paramBinding{
+	"BindUri":          {tag: "uri", in: "path", tagged: true},
+	"ShouldBindUri":    {tag: "uri", in: "path", tagged: true},
+	"BindQuery":        {tag: "form", in: "query"},
+	"ShouldBindQuery":  {tag: "form", in: "query"},
+	"BindHeader":       {tag: "header", in: "header"},
+	"ShouldBindHeader": {tag: "header", in: "header"},
+}
+
+// resolveBoundParameters expands the struct bound by a parameter binding call
+// into documented parameters
+func resolveBoundParameters(call *ast.CallExpr, ctx *analysisContext, methods map[string]paramBinding) []core.Parameter {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) == 0 {
+		return nil
+	}
+	binding, ok := methods[sel.Sel.Name]
+	if !ok {
+		return nil
+	}
+	return structParameters(resolveTypeFromArg(call.Args[0], ctx), ctx, binding, make(map[string]bool))
+}
+
+// structParameters turns the tagged fields of a struct into parameters. Fields
+// without a tag use their Go name unless the binding only reads tagged fields.
+func structParameters(typeExpr ast.Expr, ctx *analysisContext, binding paramBinding, visited map[string]bool) []core.Parameter {
+	if star, ok := typeExpr.(*ast.StarExpr); ok {
+		typeExpr = star.X
+	}
+	ident, ok := typeExpr.(*ast.Ident)
+	if !ok || ctx == nil || visited[ident.Name] {
+		return nil
+	}
+	structType, ok := ctx.structs[ident.Name]
+	if !ok || structType.Fields == nil {
+		return nil
+	}
+	visited[ident.Name] = true
+	defer delete(visited, ident.Name)
+
+	var params []core.Parameter
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 {
+			params = append(params, structParameters(field.Type, ctx, binding, visited)...)
+			continue
+		}
+
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			paramName := strings.Split(getStructTag(field, binding.tag), ",")[0]
+			if paramName == "-" || (paramName == "" && binding.tagged) {
+				continue
+			}
+			if paramName == "" {
+				paramName = name.Name
+			}
+
+			schema, _ := buildSchemaFromExpr(field.Type, ctx, make(map[string]bool))
+			paramType := "string"
+			if schemaMap, ok := schema.(map[string]interface{}); ok {
+				if typ, ok := schemaMap["type"].(string); ok {
+					paramType = typ
+				}
+			}
+
+			param := core.Parameter{
+				Name:        paramName,
+				In:          binding.in,
+				Type:        paramType,
+				Required:    binding.in == "path" || isFieldRequired("", getStructTag(field, "binding"), getStructTag(field, "validate")),
+				Description: fieldComment(field),
+			}
+			if raw := getStructTag(field, "example"); raw != "" {
+				param.Example = convertExampleValue(raw, schema, raw)
+			}
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// appendParameters adds params that are not documented yet, matching by name and location
+func appendParameters(existing []core.Parameter, params ...core.Parameter) []core.Parameter {
+	for _, param := range params {
+		duplicate := false
+		for _, current := range existing {
+			if current.Name == param.Name && current.In == param.In {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			existing = append(existing, param)
+		}
+	}
+	return existing
+}
+
 func registerDeclarationTypes(decl *ast.DeclStmt, ctx *analysisContext) {
 	if ctx == nil {
 		return
@@ -854,7 +1090,7 @@ func inferTypeFromExpr(expr ast.Expr, ctx *analysisContext) ast.Expr {
 			if full == "json.Marshal" || full == "json.MarshalIndent" {
 				return &ast.ArrayType{Elt: &ast.Ident{Name: "byte"}}
 			}
-			receiverType := resolveTypeFromArg(sel.X, ctx)
+			receiverType := callReceiverType(sel.X, ctx)
 			receiverName := exprToString(receiverType)
 			if results := lookupFunctionResult(ctx, receiverName, sel.Sel.Name); len(results) > 0 {
 				return results[0]
@@ -912,6 +1148,66 @@ func resolveTypeFromArg(expr ast.Expr, ctx *analysisContext) ast.Expr {
 	return expr
 }
 
+// registerFuncParamTypes records the types of a handler's receiver and
+// parameters, so injected dependencies like h.service or svc can be resolved
+func registerFuncParamTypes(fn *ast.FuncDecl, ctx *analysisContext) {
+	for _, fields := range []*ast.FieldList{fn.Recv, fn.Type.Params} {
+		if fields == nil {
+			continue
+		}
+		for _, field := range fields.List {
+			for _, name := range field.Names {
+				if name.Name != "_" {
+					ctx.variables[name.Name] = field.Type
+				}
+			}
+		}
+	}
+}
+
+// callReceiverType resolves the type of a method call receiver, following
+// struct fields such as the service in h.service.GetUser(id)
+func callReceiverType(expr ast.Expr, ctx *analysisContext) ast.Expr {
+	if sel, ok := expr.(*ast.SelectorExpr); ok && ctx != nil {
+		if fieldType := structFieldType(callReceiverType(sel.X, ctx), sel.Sel.Name, ctx, make(map[string]bool)); fieldType != nil {
+			return fieldType
+		}
+	}
+	return resolveTypeFromArg(expr, ctx)
+}
+
+// structFieldType returns the declared type of a struct field, searching embedded structs
+func structFieldType(typeExpr ast.Expr, fieldName string, ctx *analysisContext, visited map[string]bool) ast.Expr {
+	if star, ok := typeExpr.(*ast.StarExpr); ok {
+		typeExpr = star.X
+	}
+	ident, ok := typeExpr.(*ast.Ident)
+	if !ok || visited[ident.Name] {
+		return nil
+	}
+	structType, ok := ctx.structs[ident.Name]
+	if !ok || structType.Fields == nil {
+		return nil
+	}
+	visited[ident.Name] = true
+
+	for _, field := range structType.Fields.List {
+		for _, name := range field.Names {
+			if name.Name == fieldName {
+				return field.Type
+			}
+		}
+	}
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 {
+			if fieldType := structFieldType(field.Type, fieldName, ctx, visited); fieldType != nil {
+				return fieldType
+			}
+		}
+	}
+	return nil
+}
+
 func lookupFunctionResult(ctx *analysisContext, receiver, name string) []ast.Expr {
 	if ctx == nil {
 		return nil
@@ -1035,9 +1331,15 @@ func buildSchemaFromExpr(expr ast.Expr, ctx *analysisContext, visited map[string
 		if valueSchema != nil {
 			schema["additionalProperties"] = valueSchema
 		}
+		keyType := mapKeyTypeName(e.Key)
+		keyKind := ""
+		if primitive, _ := primitiveSchemaForIdent(keyType); primitive != nil {
+			keyKind = keyType
+		}
+		key := core.MapKeyHints(schema, keyType, keyKind)
 		example := map[string]interface{}{}
 		if valueExample != nil {
-			example["key"] = valueExample
+			example[key] = valueExample
 		}
 		return schema, example
 	case *ast.InterfaceType:
@@ -1062,7 +1364,7 @@ func buildSchemaFromExpr(expr ast.Expr, ctx *analysisContext, visited map[string
 					return buildSchemaFromExpr(e.Args[0], ctx, visited)
 				}
 			}
-			receiverType := resolveTypeFromArg(sel.X, ctx)
+			receiverType := callReceiverType(sel.X, ctx)
 			receiverName := exprToString(receiverType)
 			if results := lookupFunctionResult(ctx, receiverName, sel.Sel.Name); len(results) > 0 {
 				return buildSchemaFromExpr(results[0], ctx, visited)
@@ -1106,10 +1408,14 @@ func buildSchemaFromCompositeLiteral(lit *ast.CompositeLit, ctx *analysisContext
 						example[key] = value
 					}
 				}
-				return schema, example
+				return applyResponseEnvelope(t.Name, lit, structType, nil, schema, example, ctx, visited)
 			}
 		}
 		return buildSchemaFromExpr(t, ctx, visited)
+	case *ast.IndexExpr:
+		return buildGenericLiteralSchema(lit, t.X, []ast.Expr{t.Index}, ctx, visited)
+	case *ast.IndexListExpr:
+		return buildGenericLiteralSchema(lit, t.X, t.Indices, ctx, visited)
 	case *ast.MapType:
 		return buildMapLiteralSchema(lit, ctx, visited)
 	case *ast.ArrayType:
@@ -1142,6 +1448,26 @@ func buildSchemaFromCompositeLiteral(lit *ast.CompositeLit, ctx *analysisContext
 	}
 }
 
+// buildGenericLiteralSchema builds the schema of a literal of a generic
+// struct, such as Envelope[User]{...}
+func buildGenericLiteralSchema(lit *ast.CompositeLit, genericType ast.Expr, typeArgs []ast.Expr, ctx *analysisContext, visited map[string]bool) (interface{}, interface{}) {
+	ident, ok := genericType.(*ast.Ident)
+	if !ok || ctx == nil || ctx.structs[ident.Name] == nil {
+		return buildSchemaFromExpr(genericType, ctx, visited)
+	}
+	structType := ctx.structs[ident.Name]
+	schema, example := buildStructSchema(structType, ctx, visited)
+	if literalExample := buildStructLiteralExample(lit, structType, ctx, visited); len(literalExample) > 0 {
+		if example == nil {
+			example = make(map[string]interface{})
+		}
+		for key, value := range literalExample {
+			example[key] = value
+		}
+	}
+	return applyResponseEnvelope(ident.Name, lit, structType, typeArgs, schema, example, ctx, visited)
+}
+
 func buildMapLiteralSchema(lit *ast.CompositeLit, ctx *analysisContext, visited map[string]bool) (interface{}, interface{}) {
 	schema := map[string]interface{}{"type": "object"}
 	properties := make(map[string]interface{})
@@ -1199,6 +1525,20 @@ func literalKeyToString(expr ast.Expr) string {
 	return ""
 }
 
+// mapKeyTypeName returns the key type of a map type expression as written,
+// without its package qualifier
+func mapKeyTypeName(expr ast.Expr) string {
+	switch key := expr.(type) {
+	case *ast.Ident:
+		return key.Name
+	case *ast.SelectorExpr:
+		return key.Sel.Name
+	case *ast.StarExpr:
+		return mapKeyTypeName(key.X)
+	}
+	return ""
+}
+
 func primitiveSchemaForIdent(name string) (map[string]interface{}, interface{}) {
 	lower := strings.ToLower(name)
 	switch lower {
@@ -1246,6 +1586,12 @@ func schemaForSelector(fullName string) (map[string]interface{}, interface{}) {
 }
 
 func buildStructSchema(structType *ast.StructType, ctx *analysisContext, visited map[string]bool) (map[string]interface{}, map[string]interface{}) {
+	if definition, ok := lookupUnionDefinition(structType); ok {
+		schema, example := buildUnionSchema(definition, ctx, visited)
+		applyStructDoc(schema, structType)
+		return schema, example
+	}
+
 	properties := make(map[string]interface{})
 	example := make(map[string]interface{})
 	requiredFields := make([]string, 0)
@@ -1294,10 +1640,20 @@ func buildStructSchema(structType *ast.StructType, ctx *analysisContext, visited
 				continue
 			}
 
-			if description := fieldComment(field); description != "" {
-				if schemaMap, ok := schema.(map[string]interface{}); ok {
+			description, readOnly, writeOnly := core.FieldAccessMode(func(key string) string {
+				return getStructTag(field, key)
+			}, fieldComment(field))
+			if schemaMap, ok := schema.(map[string]interface{}); ok {
+				if description != "" {
 					schemaMap["description"] = description
 				}
+				core.SetAccessMode(schemaMap, readOnly, writeOnly)
+				core.MapKeyTags(schemaMap, func(key string) string {
+					return getStructTag(field, key)
+				})
+				core.FieldExtensions(schemaMap, func(key string) string {
+					return getStructTag(field, key)
+				})
 			}
 
 			if tagExample := getStructTag(field, "example"); tagExample != "" {
@@ -1325,6 +1681,7 @@ func buildStructSchema(structType *ast.StructType, ctx *analysisContext, visited
 	if len(requiredFields) > 0 {
 		schema["required"] = requiredFields
 	}
+	applyStructDoc(schema, structType)
 
 	return schema, example
 }
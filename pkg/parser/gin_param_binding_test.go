@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGinParameterBindingStructs(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+type Paging struct {
+	Page  int ` + "`form:\"page\" example:\"2\"`" + `
+	Limit int ` + "`form:\"limit,default=20\"`" + `
+}
+
+type ListQuery struct {
+	Paging
+	// Search filters users by name
+	Search string   ` + "`form:\"q\" binding:\"required\"`" + `
+	Tags   []string
+	Secret string   ` + "`form:\"-\"`" + `
+}
+
+type UserURI struct {
+	ID    string ` + "`uri:\"id\"`" + `
+	Other string
+}
+
+type TraceHeader struct {
+	RequestID string ` + "`header:\"X-Request-ID\"`" + `
+}
+
+// ListUsers lists users
+// @Param q query string false "Custom search description"
+func ListUsers(c *gin.Context) {
+	var query ListQuery
+	var uri UserURI
+	var header TraceHeader
+	if err := c.ShouldBindQuery(&query); err != nil {
+		return
+	}
+	c.ShouldBindUri(&uri)
+	c.ShouldBindHeader(&header)
+	c.JSON(200, gin.H{})
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := analyzeDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := analysis.handlers["listusers"][0].metadata.Info.Parameters
+
+	byName := make(map[string]int)
+	for i, param := range params {
+		byName[param.In+":"+param.Name] = i
+	}
+	if len(params) != 6 {
+		t.Fatalf("expected 6 parameters, got %+v", params)
+	}
+
+	q := params[byName["query:q"]]
+	if q.Description != "Custom search description" || q.Required {
+		t.Errorf("expected the @Param declaration to win, got %+v", q)
+	}
+	page := params[byName["query:page"]]
+	if page.Type != "integer" || fmt.Sprint(page.Example) != "2" {
+		t.Errorf("unexpected page parameter %+v", page)
+	}
+	if tags := params[byName["query:Tags"]]; tags.Type != "array" {
+		t.Errorf("expected untagged field to use its name with array type, got %+v", tags)
+	}
+	if id := params[byName["path:id"]]; !id.Required || id.Type != "string" {
+		t.Errorf("expected required path parameter, got %+v", id)
+	}
+	if _, ok := byName["header:X-Request-ID"]; !ok {
+		t.Errorf("expected header parameter, got %+v", params)
+	}
+	if _, ok := byName["query:Secret"]; ok {
+		t.Errorf("expected form:\"-\" field to be skipped")
+	}
+}
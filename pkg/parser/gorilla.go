@@ -12,28 +12,25 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/idnexacloud/bytedocs-go/pkg/core"
 	"github.com/gorilla/mux"
 )
 
-// Global registry for Gorilla Mux route tracking
-var (
-	globalGorillaDocs   *core.APIDocs
-	gorillaDocsConfig   *core.Config
-	gorillaDocsMutex    sync.RWMutex
-	gorillaFuncComments map[string][]string
-)
-
-func init() {
-	gorillaFuncComments = make(map[string][]string)
-}
-
 // GorillaHandlerInfo holds parsed comment information for Gorilla Mux handlers
 type GorillaHandlerInfo struct {
-	Summary     string
-	Description string
-	Parameters  []core.Parameter
+	Summary      string
+	Description  string
+	Parameters   []core.Parameter
+	Section      string             // from "@Section <id>"
+	ExternalDocs *core.ExternalDocs // from "@Docs <url> [description]"
+	Presets      []core.Preset      // from "@Preset <name> [param=value ...] [json body]"
+	SLA          time.Duration      // from "@SLA <duration>"
+	Pagination   string             // from "@Paginate <style>"
+	Accepts      []string           // from "@Accept <content types>"
+	ResponseDocs map[string]string  // from "@Response <status> <description>"
+	Extensions   core.Extensions    // from "@x-<name> [value]"
 }
 
 // parseGorillaHandlerComments parses Go source files to extract Gorilla Mux handler comments
@@ -78,6 +75,44 @@ func parseGorillaHandlerInfo(comments []string) GorillaHandlerInfo {
 	paramRegex := regexp.MustCompile(`@Param\s+(\w+)\s+(\w+)\s+(\w+)\s+(true|false)\s+"([^"]*)"`)
 
 	for _, line := range comments {
+		if section, ok := sectionAnnotation(line); ok {
+			info.Section = section
+			continue
+		}
+		if externalDocs, ok := docsAnnotation(line); ok {
+			info.ExternalDocs = externalDocs
+			continue
+		}
+		if preset, ok := presetAnnotation(line); ok {
+			info.Presets = append(info.Presets, preset)
+			continue
+		}
+		if sla, ok := slaAnnotation(line); ok {
+			info.SLA = sla
+			continue
+		}
+		if style, ok := paginateAnnotation(line); ok {
+			info.Pagination = style
+			continue
+		}
+		if accepts, ok := acceptAnnotation(line); ok {
+			info.Accepts = append(info.Accepts, accepts...)
+			continue
+		}
+		if status, description, ok := responseAnnotation(line); ok {
+			if info.ResponseDocs == nil {
+				info.ResponseDocs = make(map[string]string)
+			}
+			info.ResponseDocs[status] = description
+			continue
+		}
+		if name, value, ok := extensionAnnotation(line); ok {
+			if info.Extensions == nil {
+				info.Extensions = make(core.Extensions)
+			}
+			info.Extensions[name] = value
+			continue
+		}
 		// Parse @Param annotations
 		if matches := paramRegex.FindStringSubmatch(line); len(matches) == 6 {
 			param := core.Parameter{
@@ -144,7 +179,7 @@ func extractGorillaHandlerName(handler http.Handler) string {
 
 	parts := strings.Split(funcName, ".")
 	if len(parts) > 0 {
-		return parts[len(parts)-1]
+		return strings.TrimSuffix(parts[len(parts)-1], methodValueSuffix)
 	}
 
 	return ""
@@ -346,8 +381,9 @@ func (m *GorillaMuxWrapper) GetRoutes() []GorillaRoute {
 	return allRoutes
 }
 
-// SetupGorillaMuxDocs sets up documentation for a Gorilla Mux router with auto-detection
-func SetupGorillaMuxDocs(router *GorillaMuxWrapper, config *core.Config) {
+// SetupGorillaMuxDocs sets up documentation for a Gorilla Mux router with auto-detection.
+// The returned APIDocs can be used to customize the generated documentation
+func SetupGorillaMuxDocs(router *GorillaMuxWrapper, config *core.Config) *core.APIDocs {
 	if config == nil {
 		config = &core.Config{
 			Title:      "API Documentation",
@@ -357,22 +393,16 @@ func SetupGorillaMuxDocs(router *GorillaMuxWrapper, config *core.Config) {
 		}
 	}
 
-	gorillaDocsMutex.Lock()
-	gorillaDocsConfig = config
-	globalGorillaDocs = core.New(config)
-	gorillaDocsMutex.Unlock()
+	analysis := newAnalysisSettings(config)
 
-	// Set up the docs route that does auto-detection
-	router.HandleFunc(config.DocsPath+"/", func(w http.ResponseWriter, r *http.Request) {
-		fmt.Printf("🚀 Gorilla Mux docs handler called for path: %s\n", r.URL.Path)
-		gorillaDocsMutex.Lock()
-		defer gorillaDocsMutex.Unlock()
+	docs := core.New(config)
 
-		// Check if we need to detect routes
-		endpointsCount := len(globalGorillaDocs.GetDocumentation().Endpoints)
-		fmt.Printf("🔍 Current endpoints count: %d, AutoDetect: %t\n", endpointsCount, config.AutoDetect)
+	// Refresh analyzes the handlers from source again
+	docs.OnRefresh(analysis.resetCaches)
 
-		if endpointsCount == 0 && config.AutoDetect {
+	// Routes are detected on Warmup or the first docs request, and again on Refresh
+	if config.AutoDetect {
+		docs.SetRouteDetector(analysis.scoped(func() {
 			// Parse handler metadata first
 			fmt.Printf("📝 Parsing Gorilla Mux handler metadata...\n")
 
@@ -384,9 +414,7 @@ func SetupGorillaMuxDocs(router *GorillaMuxWrapper, config *core.Config) {
 				fmt.Printf("📍 Route: %s %s\n", route.Method, route.Path)
 
 				// Skip docs routes and static files
-				if strings.HasPrefix(route.Path, config.DocsPath) ||
-					strings.Contains(route.Path, "/static") ||
-					strings.Contains(route.Path, "/assets") {
+				if strings.HasPrefix(route.Path, config.DocsPath) {
 					fmt.Printf("⏭️  Skipping route: %s\n", route.Path)
 					continue
 				}
@@ -415,23 +443,41 @@ func SetupGorillaMuxDocs(router *GorillaMuxWrapper, config *core.Config) {
 					handlerInfos := parseGorillaHandlerComments("main.go", "examples/gorilla-mux/main.go")
 					if handlerInfo, exists := handlerInfos[handlerName]; exists {
 						metadata.Info = GorillaMuxHandlerInfo{
-							Summary:     handlerInfo.Summary,
-							Description: handlerInfo.Description,
-							Parameters:  handlerInfo.Parameters,
+							Summary:      handlerInfo.Summary,
+							Description:  handlerInfo.Description,
+							Parameters:   handlerInfo.Parameters,
+							Section:      handlerInfo.Section,
+							ExternalDocs: handlerInfo.ExternalDocs,
+							Presets:      handlerInfo.Presets,
+							SLA:          handlerInfo.SLA,
+							Pagination:   handlerInfo.Pagination,
+							Accepts:      handlerInfo.Accepts,
+							ResponseDocs: handlerInfo.ResponseDocs,
+							Extensions:   handlerInfo.Extensions,
 						}
 						fmt.Printf("   ✅ Comment parsing successful for %s\n", handlerName)
 					}
 				}
 
 				routeInfo := core.RouteInfo{
-					Method:      route.Method,
-					Path:        route.Path,
-					Handler:     route.Handler,
-					Summary:     metadata.Info.Summary,
-					Description: metadata.Info.Description,
-					Parameters:  metadata.Info.Parameters,
-					RequestBody: metadata.RequestBody,
-					Responses:   metadata.Responses,
+					Method:       route.Method,
+					Path:         route.Path,
+					Handler:      route.Handler,
+					Summary:      metadata.Info.Summary,
+					Description:  metadata.Info.Description,
+					Parameters:   metadata.Info.Parameters,
+					Section:      metadata.Info.Section,
+					ExternalDocs: metadata.Info.ExternalDocs,
+					Presets:      metadata.Info.Presets,
+					SLA:          metadata.Info.SLA,
+					Pagination:   metadata.Info.Pagination,
+					Accepts:      metadata.Info.Accepts,
+					ResponseDocs: metadata.Info.ResponseDocs,
+					Extensions:   metadata.Info.Extensions,
+					RequestBody:  metadata.RequestBody,
+					Responses:    metadata.Responses,
+					Warnings:     metadata.Warnings,
+					Trace:        metadata.Trace,
 				}
 
 				fmt.Printf("✅ Adding Gorilla Mux route: %s %s (handler: %s)\n", route.Method, route.Path, handlerName)
@@ -455,21 +501,32 @@ func SetupGorillaMuxDocs(router *GorillaMuxWrapper, config *core.Config) {
 				}
 
 				// Add to documentation
-				globalGorillaDocs.AddRouteInfo(routeInfo)
+				docs.AddRouteInfo(routeInfo)
 			}
 
 			fmt.Printf("📚 Generating Gorilla Mux documentation...\n")
 			// Generate documentation
-			globalGorillaDocs.Generate()
+			docs.Generate()
 
-			fmt.Printf("📊 Final endpoints count: %d\n", len(globalGorillaDocs.GetDocumentation().Endpoints))
-		}
+			fmt.Printf("📊 Final endpoints count: %d\n", len(docs.GetDocumentation().Endpoints))
+		}))
+	}
+
+	if serveDocsSeparately(docs, config) {
+		return docs
+	}
+
+	// Set up the docs route that does auto-detection
+	router.HandleFunc(config.DocsPath+"/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Printf("🚀 Gorilla Mux docs handler called for path: %s\n", r.URL.Path)
 
 		// Serve documentation
-		globalGorillaDocs.ServeHTTP(w, r)
+		docs.ServeHTTP(w, r)
 	})
 
 	router.PathPrefix(config.DocsPath + "/").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		globalGorillaDocs.ServeHTTP(w, r)
+		docs.ServeHTTP(w, r)
 	})
+
+	return docs
 }
@@ -2,24 +2,30 @@ package parser
 
 import (
 	"go/ast"
-	"go/parser"
 	"go/token"
-	"io/fs"
 	"net/http"
-	"path/filepath"
 	"reflect"
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/idnexacloud/bytedocs-go/pkg/core"
 )
 
 // GorillaMuxHandlerInfo holds parsed comment information for Gorilla-Mux handlers
 type GorillaMuxHandlerInfo struct {
-	Summary     string
-	Description string
-	Parameters  []core.Parameter
+	Summary      string
+	Description  string
+	Parameters   []core.Parameter
+	Section      string             // from "@Section <id>"
+	ExternalDocs *core.ExternalDocs // from "@Docs <url> [description]"
+	Presets      []core.Preset      // from "@Preset <name> [param=value ...] [json body]"
+	SLA          time.Duration      // from "@SLA <duration>"
+	Pagination   string             // from "@Paginate <style>"
+	Accepts      []string           // from "@Accept <content types>"
+	ResponseDocs map[string]string  // from "@Response <status> <description>"
+	Extensions   core.Extensions    // from "@x-<name> [value]"
 }
 
 // parseGorillaMuxHandlerInfo parses handler comments to extract structured information
@@ -29,6 +35,44 @@ func parseGorillaMuxHandlerInfo(comments []string) GorillaMuxHandlerInfo {
 	}
 
 	for _, line := range comments {
+		if section, ok := sectionAnnotation(line); ok {
+			info.Section = section
+			continue
+		}
+		if externalDocs, ok := docsAnnotation(line); ok {
+			info.ExternalDocs = externalDocs
+			continue
+		}
+		if preset, ok := presetAnnotation(line); ok {
+			info.Presets = append(info.Presets, preset)
+			continue
+		}
+		if sla, ok := slaAnnotation(line); ok {
+			info.SLA = sla
+			continue
+		}
+		if style, ok := paginateAnnotation(line); ok {
+			info.Pagination = style
+			continue
+		}
+		if accepts, ok := acceptAnnotation(line); ok {
+			info.Accepts = append(info.Accepts, accepts...)
+			continue
+		}
+		if status, description, ok := responseAnnotation(line); ok {
+			if info.ResponseDocs == nil {
+				info.ResponseDocs = make(map[string]string)
+			}
+			info.ResponseDocs[status] = description
+			continue
+		}
+		if name, value, ok := extensionAnnotation(line); ok {
+			if info.Extensions == nil {
+				info.Extensions = make(core.Extensions)
+			}
+			info.Extensions[name] = value
+			continue
+		}
 		if info.Summary == "" && !strings.HasPrefix(line, "@") {
 			// First non-annotation line becomes summary
 			info.Summary = line
@@ -46,6 +90,8 @@ type GorillaMuxHandlerMetadata struct {
 	Info        GorillaMuxHandlerInfo
 	RequestBody *core.RequestBody
 	Responses   map[string]core.Response
+	Warnings    []string `json:"-"` // why no schemas were inferred; not cached
+	Trace       []string `json:"-"` // analysis steps when debugging; not cached
 }
 
 // gorillaMuxAnalyzedHandler keeps track of metadata for an individual Gorilla-Mux handler within a package.
@@ -70,22 +116,20 @@ var (
 
 // getGorillaMuxHandlerMetadataByName gets handler metadata by analyzing the function name from parsed files
 func getGorillaMuxHandlerMetadataByName(funcName string, dir string) GorillaMuxHandlerMetadata {
-	packageMeta := loadGorillaMuxPackageAnalysis(dir)
-	if packageMeta == nil {
-		return GorillaMuxHandlerMetadata{}
-	}
+	return lookupGorillaMuxHandlerMetadata(handlerRefFromName(funcName, dir))
+}
 
-	key := strings.ToLower(funcName)
-	candidates := packageMeta.handlers[key]
-	if len(candidates) == 0 {
-		return GorillaMuxHandlerMetadata{}
+func lookupGorillaMuxHandlerMetadata(ref handlerRef) GorillaMuxHandlerMetadata {
+	packageMeta := loadGorillaMuxPackageAnalysis(ref.dir)
+	if packageMeta == nil {
+		return GorillaMuxHandlerMetadata{Warnings: packageWarnings(ref)}
 	}
 
-	if len(candidates) > 0 {
-		return candidates[0].metadata
+	if candidate, ok := selectHandler(packageMeta.handlers[strings.ToLower(ref.funcName)], ref); ok {
+		return candidate.metadata
 	}
 
-	return GorillaMuxHandlerMetadata{}
+	return GorillaMuxHandlerMetadata{Warnings: handlerNotFoundWarnings(ref)}
 }
 
 func getGorillaMuxHandlerMetadata(handler http.Handler) GorillaMuxHandlerMetadata {
@@ -94,52 +138,29 @@ func getGorillaMuxHandlerMetadata(handler http.Handler) GorillaMuxHandlerMetadat
 	}
 
 	var fn *runtime.Func
-	var runtimeName string
-
-	switch h := handler.(type) {
-	case http.HandlerFunc:
-		fn = runtime.FuncForPC(reflect.ValueOf(h).Pointer())
-	case http.Handler:
-		value := reflect.ValueOf(h)
-		if value.Kind() == reflect.Func {
-			fn = runtime.FuncForPC(value.Pointer())
-		} else {
-			method := value.MethodByName("ServeHTTP")
-			if method.IsValid() {
-				fn = runtime.FuncForPC(method.Pointer())
-			}
-		}
-	default:
-		value := reflect.ValueOf(handler)
-		if value.Kind() == reflect.Func {
-			fn = runtime.FuncForPC(value.Pointer())
-		}
-	}
-
-	if fn == nil {
-		return GorillaMuxHandlerMetadata{}
-	}
 
-	runtimeName = fn.Name()
-	funcName := runtimeName
-	if idx := strings.LastIndex(funcName, "."); idx != -1 {
-		funcName = funcName[idx+1:]
+	value := reflect.ValueOf(handler)
+	if value.Kind() == reflect.Func {
+		fn = runtime.FuncForPC(value.Pointer())
+	} else if method, ok := value.Type().MethodByName("ServeHTTP"); ok {
+		// Handler types are documented by their ServeHTTP method
+		fn = runtime.FuncForPC(method.Func.Pointer())
 	}
 
-	entry := fn.Entry()
-	file, _ := fn.FileLine(entry)
-	if file == "" {
-		return GorillaMuxHandlerMetadata{}
+	ref, ok := handlerRefFromRuntimeFunc(fn, ".")
+	if !ok {
+		return GorillaMuxHandlerMetadata{Warnings: unresolvedHandlerWarnings()}
 	}
-	dir := filepath.Clean(filepath.Dir(file))
 
-	return getGorillaMuxHandlerMetadataByName(funcName, dir)
+	return lookupGorillaMuxHandlerMetadata(ref)
 }
 
 // loadGorillaMuxPackageAnalysis parses and caches metadata for all Gorilla-Mux handlers within a directory.
 func loadGorillaMuxPackageAnalysis(dir string) *gorillaMuxPackageAnalysis {
+	key := analysisKey(dir)
+
 	gorillaMuxAnalysisMutex.RLock()
-	if cached, ok := gorillaMuxAnalysisCache[dir]; ok {
+	if cached, ok := gorillaMuxAnalysisCache[key]; ok {
 		gorillaMuxAnalysisMutex.RUnlock()
 		return cached
 	}
@@ -148,41 +169,70 @@ func loadGorillaMuxPackageAnalysis(dir string) *gorillaMuxPackageAnalysis {
 	gorillaMuxAnalysisMutex.Lock()
 	defer gorillaMuxAnalysisMutex.Unlock()
 
-	if cached, ok := gorillaMuxAnalysisCache[dir]; ok {
+	if cached, ok := gorillaMuxAnalysisCache[key]; ok {
 		return cached
 	}
 
-	pkgAnalysis, err := analyzeGorillaMuxDirectory(dir)
+	pkgAnalysis, err := analyzeGorillaMuxDirectoryCached(dir)
 	if err != nil {
-		// Silently ignore analysis errors to avoid breaking docs generation.
-		gorillaMuxAnalysisCache[dir] = nil
+		recordAnalysisFailure(dir, err)
+		gorillaMuxAnalysisCache[key] = nil
 		return nil
 	}
 
-	gorillaMuxAnalysisCache[dir] = pkgAnalysis
+	gorillaMuxAnalysisCache[key] = pkgAnalysis
 	return pkgAnalysis
 }
 
+// analyzeGorillaMuxDirectoryCached runs analyzeGorillaMuxDirectory through the persistent analysis cache when it is enabled.
+func analyzeGorillaMuxDirectoryCached(dir string) (*gorillaMuxPackageAnalysis, error) {
+	if getAnalysisCacheDir() == "" {
+		return analyzeGorillaMuxDirectory(dir, nil)
+	}
+
+	handlers, err := withAnalysisCache("gorilla-mux", dir, func(only map[string]bool) (map[string][]handlerCacheEntry[GorillaMuxHandlerMetadata], error) {
+		pkgAnalysis, err := analyzeGorillaMuxDirectory(dir, only)
+		if err != nil {
+			return nil, err
+		}
+		return convertHandlers(pkgAnalysis.handlers, func(h gorillaMuxAnalyzedHandler) handlerCacheEntry[GorillaMuxHandlerMetadata] {
+			return handlerCacheEntry[GorillaMuxHandlerMetadata]{
+				FilePath:     h.filePath,
+				FuncName:     h.funcName,
+				ReceiverName: h.receiverName,
+				StartLine:    h.startLine,
+				Metadata:     h.metadata,
+			}
+		}), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &gorillaMuxPackageAnalysis{
+		handlers: convertHandlers(handlers, func(e handlerCacheEntry[GorillaMuxHandlerMetadata]) gorillaMuxAnalyzedHandler {
+			return gorillaMuxAnalyzedHandler{
+				filePath:     e.FilePath,
+				funcName:     e.FuncName,
+				receiverName: e.ReceiverName,
+				startLine:    e.StartLine,
+				metadata:     e.Metadata,
+			}
+		}),
+	}, nil
+}
+
 // analyzeGorillaMuxDirectory walks all Go files in a directory to extract Gorilla-Mux handler metadata.
-func analyzeGorillaMuxDirectory(dir string) (*gorillaMuxPackageAnalysis, error) {
+func analyzeGorillaMuxDirectory(dir string, only map[string]bool) (*gorillaMuxPackageAnalysis, error) {
 	fset := token.NewFileSet()
-	pkgs, err := parser.ParseDir(fset, dir, func(info fs.FileInfo) bool {
-		if info.IsDir() {
-			return false
-		}
-		name := info.Name()
-		if !strings.HasSuffix(name, ".go") {
-			return false
-		}
-		return !strings.HasSuffix(name, "_test.go")
-	}, parser.ParseComments)
+	pkgs, err := parseGoDir(fset, dir)
 	if err != nil {
 		return nil, err
 	}
 
 	structs := collectStructDefinitions(pkgs)
 	functions := collectFunctionSignatures(pkgs)
-	handlers := collectGorillaMuxHandlerMetadata(fset, pkgs, structs, functions)
+	handlers := collectGorillaMuxHandlerMetadata(fset, restrictPackageFiles(pkgs, only), structs, functions)
 
 	return &gorillaMuxPackageAnalysis{
 		handlers:  handlers,
@@ -192,51 +242,46 @@ func analyzeGorillaMuxDirectory(dir string) (*gorillaMuxPackageAnalysis, error)
 
 // collectGorillaMuxHandlerMetadata extracts documentation metadata for Gorilla-Mux function declarations.
 func collectGorillaMuxHandlerMetadata(fset *token.FileSet, pkgs map[string]*ast.Package, structs map[string]*ast.StructType, functions map[string][]functionSignature) map[string][]gorillaMuxAnalyzedHandler {
-	handlers := make(map[string][]gorillaMuxAnalyzedHandler)
-
-	for _, pkg := range pkgs {
-		for _, file := range pkg.Files {
-			for _, decl := range file.Decls {
-				fn, ok := decl.(*ast.FuncDecl)
-				if !ok {
-					continue
-				}
-
-				// Check if this is likely a Gorilla-Mux handler (has http.ResponseWriter and *http.Request parameters)
-				if !isGorillaMuxHandler(fn) {
-					continue
-				}
+	return collectPerFile(pkgs, func(file *ast.File, handlers map[string][]gorillaMuxAnalyzedHandler) {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
 
-				var comments []string
-				if fn.Doc != nil {
-					comments = extractCommentsText(fn.Doc.List)
-				}
-				info := parseGorillaMuxHandlerInfo(comments)
-				analysis := analyzeGorillaMuxHandlerDetails(fn, structs, functions)
-
-				pos := fset.Position(fn.Pos())
-				receiverName := receiverTypeName(fn.Recv)
-				funcName := fn.Name.Name
-
-				key := strings.ToLower(funcName)
-				handlerEntry := gorillaMuxAnalyzedHandler{
-					filePath:     pos.Filename,
-					funcName:     funcName,
-					receiverName: receiverName,
-					startLine:    pos.Line,
-					metadata: GorillaMuxHandlerMetadata{
-						Info:        info,
-						RequestBody: analysis.RequestBody,
-						Responses:   analysis.Responses,
-					},
-				}
+			// Check if this is likely a Gorilla-Mux handler (has http.ResponseWriter and *http.Request parameters)
+			if !isGorillaMuxHandler(fn) {
+				continue
+			}
 
-				handlers[key] = append(handlers[key], handlerEntry)
+			var comments []string
+			if fn.Doc != nil {
+				comments = extractCommentsText(fn.Doc.List)
+			}
+			info := parseGorillaMuxHandlerInfo(comments)
+			analysis := analyzeGorillaMuxHandlerDetails(fn, structs, functions)
+
+			pos := fset.Position(fn.Pos())
+			receiverName := receiverDeclName(fn.Recv)
+			funcName := fn.Name.Name
+
+			key := strings.ToLower(funcName)
+			handlerEntry := gorillaMuxAnalyzedHandler{
+				filePath:     pos.Filename,
+				funcName:     funcName,
+				receiverName: receiverName,
+				startLine:    pos.Line,
+				metadata: GorillaMuxHandlerMetadata{
+					Info:        info,
+					RequestBody: analysis.RequestBody,
+					Responses:   analysis.Responses,
+					Trace:       analysis.Trace,
+				},
 			}
-		}
-	}
 
-	return handlers
+			handlers[key] = append(handlers[key], handlerEntry)
+		}
+	})
 }
 
 // isGorillaMuxHandler checks if a function is likely a Gorilla-Mux handler
@@ -282,6 +327,7 @@ func isGorillaMuxHandler(fn *ast.FuncDecl) bool {
 type gorillaMuxHandlerAnalysis struct {
 	RequestBody *core.RequestBody
 	Responses   map[string]core.Response
+	Trace       []string // analysis steps, when debugging
 }
 
 // analyzeGorillaMuxHandlerDetails inspects a Gorilla-Mux handler function to infer request bodies and responses.
@@ -299,7 +345,9 @@ func analyzeGorillaMuxHandlerDetails(fn *ast.FuncDecl, structs map[string]*ast.S
 		functions: functions,
 		variables: make(map[string]ast.Expr),
 		values:    make(map[string]ast.Expr),
+		trace:     newHandlerTrace(),
 	}
+	registerFuncParamTypes(fn, ctx)
 
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
 		switch node := n.(type) {
@@ -311,21 +359,41 @@ func analyzeGorillaMuxHandlerDetails(fn *ast.FuncDecl, structs map[string]*ast.S
 			registerRangeTypes(node, ctx)
 		case *ast.CallExpr:
 			// Detect request body binding for Gorilla-Mux (json.Decoder)
-			if analysis.RequestBody == nil && isGorillaMuxBindingCall(node) {
+			if isGorillaMuxBindingCall(node) {
 				if len(node.Args) > 0 {
-					if resolved := resolveGorillaMuxRequestBody(node, node.Args[0], ctx); resolved != nil {
-						analysis.RequestBody = resolved
+					resolved := resolveGorillaMuxRequestBody(node, node.Args[0], ctx)
+					ctx.traceBinding(node, resolved)
+					if resolved != nil {
+						analysis.RequestBody = mergeRequestBody(analysis.RequestBody, resolved)
 					}
 				}
 			}
 
+			if statusCode, response, ok := redirectResponse(node, ctx); ok {
+				analysis.Responses[statusCode] = response
+				ctx.traceResponse(node, &ast.BasicLit{Kind: token.INT, Value: statusCode}, statusCode, nil, response.Schema)
+			}
+
+			if statusCode, response, ok := streamResponse(node, ctx); ok {
+				analysis.Responses[statusCode] = response
+				ctx.traceResponse(node, &ast.BasicLit{Kind: token.INT, Value: statusCode}, statusCode, nil, response.Schema)
+				return true
+			}
+
 			// Detect response generation calls for Gorilla-Mux
-			if contentType, statusExpr, dataExpr, ok := gorillaMuxResponseCallInfo(node, ctx); ok {
+			contentType, statusExpr, dataExpr, ok := errorCallInfo(node, ctx)
+			if !ok {
+				contentType, statusExpr, dataExpr, ok = gorillaMuxResponseCallInfo(node, ctx)
+			}
+			if !ok {
+				contentType, statusExpr, dataExpr, ok = responseHelperCallInfo(node, ctx)
+			}
+			if ok {
 				statusCode := extractStatusCode(statusExpr, ctx)
 				if statusCode == "" {
 					statusCode = "200"
 				}
-				payloadExpr := resolveResponsePayloadExpr(dataExpr, ctx)
+				payloadExpr := resolveResponsePayloadExpr(errorPayloadExpr(statusCode, dataExpr, ctx), ctx)
 				schema, example := buildSchemaFromExpr(payloadExpr, ctx, make(map[string]bool))
 				example = normalizeExampleWithSchema(schema, example)
 				if example == nil {
@@ -344,11 +412,13 @@ func analyzeGorillaMuxHandlerDetails(fn *ast.FuncDecl, structs map[string]*ast.S
 					response.Description = "Response"
 				}
 				analysis.Responses[statusCode] = response
+				ctx.traceResponse(node, statusExpr, statusCode, payloadExpr, schema)
 			}
 		}
 		return true
 	})
 
+	analysis.Trace = ctx.trace.finish(analysis.RequestBody != nil, len(analysis.Responses))
 	return analysis
 }
 
@@ -378,6 +448,9 @@ func resolveGorillaMuxRequestBody(call *ast.CallExpr, arg ast.Expr, ctx *analysi
 
 	body.Required = true
 
+	if body.ContentType == "" {
+		body.ContentType = decoderContentType(call)
+	}
 	if body.ContentType == "" {
 		if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
 			if mime, found := gorillaMuxBindingMethods[sel.Sel.Name]; found && mime != "auto" {
@@ -394,6 +467,10 @@ func resolveGorillaMuxRequestBody(call *ast.CallExpr, arg ast.Expr, ctx *analysi
 }
 
 func gorillaMuxResponseCallInfo(call *ast.CallExpr, ctx *analysisContext) (contentType string, statusExpr ast.Expr, dataExpr ast.Expr, ok bool) {
+	if contentType, statusExpr, dataExpr, ok := templateCallInfo(call, ctx); ok {
+		return contentType, statusExpr, dataExpr, true
+	}
+
 	// Check for writeJSON helper function first (plain ident call)
 	if ident, ok := call.Fun.(*ast.Ident); ok {
 		if ident.Name == "writeJSON" && len(call.Args) >= 3 {
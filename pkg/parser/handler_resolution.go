@@ -0,0 +1,230 @@
+package parser
+
+import (
+	"bufio"
+	"go/ast"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// methodValueSuffix marks the compiler-generated wrapper of a method value
+// such as h.GetUsers; the wrapper has no source position of its own
+const methodValueSuffix = "-fm"
+
+// handlerRef identifies a handler function for the package analyzers
+type handlerRef struct {
+	funcName     string
+	receiverName string // receiver type without pointer or type arguments, empty for functions
+	file         string // source file, empty when unknown (method values, trimmed paths)
+	line         int
+	dir          string // package directory to analyze
+	nameOnly     bool   // only the function name is known, any receiver matches
+}
+
+// packageDirs remembers the source directory of every import path seen with a
+// real source position, so method values of the same package can be resolved
+var (
+	packageDirs      = make(map[string]string)
+	packageDirsMutex sync.RWMutex
+)
+
+// handlerRefFromFunc resolves a handler func value, including method values of controllers
+func handlerRefFromFunc(handler interface{}) (handlerRef, bool) {
+	if handler == nil {
+		return handlerRef{}, false
+	}
+	value := reflect.ValueOf(handler)
+	if value.Kind() != reflect.Func || value.IsNil() {
+		return handlerRef{}, false
+	}
+	return handlerRefFromRuntimeFunc(runtime.FuncForPC(value.Pointer()), ".")
+}
+
+// handlerRefFromRuntimeFunc resolves a runtime function, falling back to the
+// package directory of its symbol, or fallbackDir, when it has no usable source file
+func handlerRefFromRuntimeFunc(fn *runtime.Func, fallbackDir string) (handlerRef, bool) {
+	if fn == nil {
+		return handlerRef{}, false
+	}
+
+	file, line := fn.FileLine(fn.Entry())
+	if file != "" && !strings.HasPrefix(file, "<") && !strings.HasSuffix(fn.Name(), methodValueSuffix) {
+		dir := filepath.Dir(file)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			ref := handlerRefFromName(fn.Name(), dir)
+			ref.file = filepath.Clean(file)
+			ref.line = line
+			rememberPackageDir(packagePathFromSymbol(fn.Name()), dir)
+			return ref, true
+		}
+	}
+
+	ref := handlerRefFromName(fn.Name(), fallbackDir)
+	return ref, ref.funcName != ""
+}
+
+// handlerRefFromName resolves a runtime symbol such as
+// "example.com/app/handlers.(*UserHandler).GetUsers-fm" or a bare function
+// name. Bare names only identify the function, not its receiver.
+func handlerRefFromName(symbol string, fallbackDir string) handlerRef {
+	symbol = strings.TrimSuffix(symbol, methodValueSuffix)
+
+	pkgPath := packagePathFromSymbol(symbol)
+	if pkgPath == "" {
+		return handlerRef{funcName: symbol, dir: fallbackDir, nameOnly: true}
+	}
+
+	funcName, receiverName := parseRuntimeFuncName(symbol)
+	ref := handlerRef{
+		funcName:     funcName,
+		receiverName: receiverBaseName(receiverName),
+		dir:          fallbackDir,
+	}
+	if dir, ok := packageDirForPath(pkgPath); ok {
+		ref.dir = dir
+	}
+	return ref
+}
+
+// packagePathFromSymbol returns the import path of a runtime symbol, or "" for bare names
+func packagePathFromSymbol(symbol string) string {
+	slash := strings.LastIndex(symbol, "/")
+	dot := strings.Index(symbol[slash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+	return symbol[:slash+1+dot]
+}
+
+func rememberPackageDir(pkgPath, dir string) {
+	if pkgPath == "" {
+		return
+	}
+	packageDirsMutex.Lock()
+	packageDirs[pkgPath] = dir
+	packageDirsMutex.Unlock()
+}
+
+// packageDirForPath finds the directory of an import path from previously
+// resolved handlers or, for packages of the current module, from go.mod
+func packageDirForPath(pkgPath string) (string, bool) {
+	packageDirsMutex.RLock()
+	dir, ok := packageDirs[pkgPath]
+	packageDirsMutex.RUnlock()
+	if ok {
+		return dir, true
+	}
+
+	root, modulePath := findModule()
+	if modulePath == "" {
+		return "", false
+	}
+	if pkgPath == modulePath {
+		return root, true
+	}
+	if rel, found := strings.CutPrefix(pkgPath, modulePath+"/"); found {
+		return filepath.Join(root, filepath.FromSlash(rel)), true
+	}
+	return "", false
+}
+
+// findModule walks up from the working directory to the nearest go.mod and
+// returns its directory and module path
+func findModule() (root string, modulePath string) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", ""
+	}
+	for {
+		if file, err := os.Open(filepath.Join(dir, "go.mod")); err == nil {
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				if path, found := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); found {
+					modulePath = strings.Trim(strings.TrimSpace(path), `"`)
+					break
+				}
+			}
+			file.Close()
+			return dir, modulePath
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// receiverBaseName strips pointers and type arguments, so "*Repo[...]" and the
+// declared receiver "Repo" compare equal. A type cannot declare the same method
+// on both the value and the pointer receiver, so the base name is unambiguous.
+func receiverBaseName(receiver string) string {
+	receiver = strings.TrimPrefix(strings.TrimSpace(receiver), "*")
+	if idx := strings.Index(receiver, "["); idx != -1 {
+		receiver = receiver[:idx]
+	}
+	return receiver
+}
+
+// receiverDeclName returns the receiver type of a method declaration,
+// including generic receivers such as (r *Repo[T])
+func receiverDeclName(fieldList *ast.FieldList) string {
+	if fieldList == nil || len(fieldList.List) == 0 {
+		return ""
+	}
+	typ := fieldList.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+	return exprToString(typ)
+}
+
+// handlerCandidate is implemented by the analyzed handlers of every framework
+type handlerCandidate interface {
+	sourceLocation() (filePath string, receiverName string, startLine int)
+}
+
+func (h analyzedHandler) sourceLocation() (string, string, int) {
+	return h.filePath, h.receiverName, h.startLine
+}
+
+func (h echoAnalyzedHandler) sourceLocation() (string, string, int) {
+	return h.filePath, h.receiverName, h.startLine
+}
+
+func (h fiberAnalyzedHandler) sourceLocation() (string, string, int) {
+	return h.filePath, h.receiverName, h.startLine
+}
+
+func (h gorillaMuxAnalyzedHandler) sourceLocation() (string, string, int) {
+	return h.filePath, h.receiverName, h.startLine
+}
+
+// selectHandler picks the candidate ref refers to. Candidates must declare the
+// same receiver and, when the source position is known, live in the same file
+// before the handler's entry line. Name-only references take the first candidate.
+func selectHandler[H handlerCandidate](candidates []H, ref handlerRef) (H, bool) {
+	for _, candidate := range candidates {
+		file, receiver, startLine := candidate.sourceLocation()
+		if !ref.nameOnly && receiverBaseName(receiver) != ref.receiverName {
+			continue
+		}
+		if ref.file != "" && (filepath.Clean(file) != ref.file || ref.line < startLine) {
+			continue
+		}
+		return candidate, true
+	}
+
+	var zero H
+	return zero, false
+}
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type resolutionController struct{}
+
+func (c *resolutionController) List() {}
+
+func TestHandlerRefFromMethodValue(t *testing.T) {
+	controller := &resolutionController{}
+	ref, ok := handlerRefFromFunc(controller.List)
+	if !ok {
+		t.Fatal("expected method value to resolve")
+	}
+	if ref.funcName != "List" || ref.receiverName != "resolutionController" || ref.nameOnly {
+		t.Fatalf("unexpected ref %+v", ref)
+	}
+	wd, _ := os.Getwd()
+	if filepath.Clean(ref.dir) != wd {
+		t.Errorf("expected package dir %s, got %s", wd, ref.dir)
+	}
+}
+
+func TestEchoLookupMatchesReceiver(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+type UserHandler struct{}
+type OrderHandler struct{}
+
+// List users
+func (h *UserHandler) List(c echo.Context) error {
+	return c.JSON(200, []string{"alice"})
+}
+
+// List orders
+func (h OrderHandler) List(c echo.Context) error {
+	return c.JSON(202, []int{1})
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	orders := getEchoHandlerMetadataByName("example.com/api.(*OrderHandler).List-fm", dir)
+	if _, ok := orders.Responses["202"]; !ok || orders.Info.Summary != "List orders" {
+		t.Errorf("expected OrderHandler.List, got %+v", orders)
+	}
+	users := getEchoHandlerMetadataByName("example.com/api.(*UserHandler).List-fm", dir)
+	if _, ok := users.Responses["200"]; !ok || users.Info.Summary != "List users" {
+		t.Errorf("expected UserHandler.List, got %+v", users)
+	}
+	if missing := getEchoHandlerMetadataByName("example.com/api.(*Other).List-fm", dir); missing.Responses != nil {
+		t.Errorf("expected no match for an unknown receiver, got %+v", missing)
+	}
+	if byName := getEchoHandlerMetadataByName("List", dir); byName.Responses == nil {
+		t.Error("expected bare names to match any receiver")
+	}
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+)
+
+// templateCallInfo detects templates rendered straight to the response,
+// tmpl.Execute(w, data) and tmpl.ExecuteTemplate(w, name, data), where w is an
+// http.ResponseWriter or a framework context's Writer. They respond 200 with
+// text/html.
+func templateCallInfo(call *ast.CallExpr, ctx *analysisContext) (contentType string, statusExpr ast.Expr, dataExpr ast.Expr, ok bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) < 2 || !isResponseWriterExpr(call.Args[0], ctx) {
+		return "", nil, nil, false
+	}
+
+	var name ast.Expr
+	switch sel.Sel.Name {
+	case "Execute":
+	case "ExecuteTemplate":
+		if len(call.Args) < 3 {
+			return "", nil, nil, false
+		}
+		name = call.Args[1]
+	default:
+		return "", nil, nil, false
+	}
+	return "text/html", &ast.BasicLit{Kind: token.INT, Value: "200"}, htmlTemplateBody(name), true
+}
+
+// isResponseWriterExpr reports whether expr is an http.ResponseWriter
+// variable or a Writer field such as gin's c.Writer
+func isResponseWriterExpr(expr ast.Expr, ctx *analysisContext) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if ctx == nil {
+			return false
+		}
+		typ, ok := ctx.variables[e.Name]
+		return ok && exprToString(typ) == "http.ResponseWriter"
+	case *ast.SelectorExpr:
+		return e.Sel.Name == "Writer"
+	case *ast.CallExpr:
+		// echo's c.Response()
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		return ok && sel.Sel.Name == "Response" && len(e.Args) == 0
+	}
+	return false
+}
+
+// htmlTemplateBody is the documented body of a rendered template, naming the
+// template when it is a string literal
+func htmlTemplateBody(name ast.Expr) ast.Expr {
+	body := "<html>...</html>"
+	if template, ok := stringLiteral(name); ok && template != "" {
+		body = "<!-- " + template + " -->\n" + body
+	}
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(body)}
+}
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTemplateResponsesDocumentedAsHTML(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+import (
+	"html/template"
+	"net/http"
+)
+
+var pages = template.Must(template.ParseGlob("*.html"))
+
+// GinHome renders the home page
+func GinHome(c *gin.Context) {
+	c.HTML(http.StatusOK, "home.html", gin.H{"title": "Home"})
+}
+
+// EchoHome renders the home page
+func EchoHome(c echo.Context) error {
+	return c.Render(http.StatusAccepted, "home.html", nil)
+}
+
+// StdlibHome renders the home page
+func StdlibHome(w http.ResponseWriter, r *http.Request) {
+	pages.ExecuteTemplate(w, "home.html", nil)
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ginAnalysis, err := analyzeDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response := ginAnalysis.handlers["ginhome"][0].metadata.Responses["200"]; response.ContentType != "text/html" || response.Example != "<!-- home.html -->\n<html>...</html>" {
+		t.Fatalf("expected c.HTML to be documented as text/html, got %+v", response)
+	}
+
+	echoAnalysis, err := analyzeEchoDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response := echoAnalysis.handlers["echohome"][0].metadata.Responses["202"]; response.ContentType != "text/html" {
+		t.Fatalf("expected c.Render to be documented as text/html with its status, got %+v", echoAnalysis.handlers["echohome"][0].metadata.Responses)
+	}
+
+	stdlibAnalysis, err := analyzeStdlibDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response := stdlibAnalysis.handlers["stdlibhome"][0].metadata.Responses["200"]; response.ContentType != "text/html" {
+		t.Fatalf("expected ExecuteTemplate to be documented as text/html, got %+v", stdlibAnalysis.handlers["stdlibhome"][0].metadata.Responses)
+	}
+}
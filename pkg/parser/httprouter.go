@@ -0,0 +1,141 @@
+package parser
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+	"github.com/julienschmidt/httprouter"
+)
+
+// SetupDocs documents routers wrapped in an HTTPRouterWrapper
+func init() {
+	RegisterFramework("httprouter", func(router interface{}) (FrameworkAdapter, bool) {
+		wrapper, ok := router.(*HTTPRouterWrapper)
+		return httpRouterAdapter{router: wrapper}, ok
+	})
+}
+
+// HTTPRouterWrapper wraps httprouter.Router to track registered routes, as
+// httprouter cannot list them
+type HTTPRouterWrapper struct {
+	*httprouter.Router
+	routes []FrameworkRoute
+	mutex  sync.RWMutex
+}
+
+// NewHTTPRouterWrapper creates a new wrapper for httprouter.Router
+func NewHTTPRouterWrapper() *HTTPRouterWrapper {
+	return &HTTPRouterWrapper{Router: httprouter.New()}
+}
+
+// Handle registers a new request handle with the given path and method
+func (r *HTTPRouterWrapper) Handle(method, path string, handle httprouter.Handle) {
+	r.record(method, path, handle)
+	r.Router.Handle(method, path, handle)
+}
+
+// Handler registers an http.Handler as a request handle
+func (r *HTTPRouterWrapper) Handler(method, path string, handler http.Handler) {
+	r.record(method, path, handler)
+	r.Router.Handler(method, path, handler)
+}
+
+// HandlerFunc registers an http.HandlerFunc as a request handle
+func (r *HTTPRouterWrapper) HandlerFunc(method, path string, handler http.HandlerFunc) {
+	r.record(method, path, handler)
+	r.Router.HandlerFunc(method, path, handler)
+}
+
+// GET is a shortcut for Handle(http.MethodGet, path, handle)
+func (r *HTTPRouterWrapper) GET(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodGet, path, handle)
+}
+
+// HEAD is a shortcut for Handle(http.MethodHead, path, handle)
+func (r *HTTPRouterWrapper) HEAD(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodHead, path, handle)
+}
+
+// OPTIONS is a shortcut for Handle(http.MethodOptions, path, handle)
+func (r *HTTPRouterWrapper) OPTIONS(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodOptions, path, handle)
+}
+
+// POST is a shortcut for Handle(http.MethodPost, path, handle)
+func (r *HTTPRouterWrapper) POST(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodPost, path, handle)
+}
+
+// PUT is a shortcut for Handle(http.MethodPut, path, handle)
+func (r *HTTPRouterWrapper) PUT(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodPut, path, handle)
+}
+
+// PATCH is a shortcut for Handle(http.MethodPatch, path, handle)
+func (r *HTTPRouterWrapper) PATCH(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodPatch, path, handle)
+}
+
+// DELETE is a shortcut for Handle(http.MethodDelete, path, handle)
+func (r *HTTPRouterWrapper) DELETE(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodDelete, path, handle)
+}
+
+func (r *HTTPRouterWrapper) record(method, path string, handler interface{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.routes = append(r.routes, FrameworkRoute{Method: method, Path: path, Handler: handler})
+}
+
+// GetRoutes returns all registered routes
+func (r *HTTPRouterWrapper) GetRoutes() []FrameworkRoute {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	// Make a copy to avoid race conditions
+	routes := make([]FrameworkRoute, len(r.routes))
+	copy(routes, r.routes)
+	return routes
+}
+
+// httpRouterAdapter documents the routes of an HTTPRouterWrapper
+type httpRouterAdapter struct {
+	router *HTTPRouterWrapper
+}
+
+// ListRoutes returns the recorded routes; the docs understand named
+// parameters ":name" and catch-all parameters "*name" as they are
+func (a httpRouterAdapter) ListRoutes() []FrameworkRoute {
+	return a.router.GetRoutes()
+}
+
+// ResolveHandler returns the registered httprouter.Handle or http.Handler
+func (a httpRouterAdapter) ResolveHandler(handler interface{}) interface{} {
+	return handler
+}
+
+// AnalyzeHandler documents a handler from its source; httprouter handles
+// take their Params after the writer and request
+func (a httpRouterAdapter) AnalyzeHandler(handler interface{}) core.RouteInfo {
+	return AnalyzeHTTPHandler(handler)
+}
+
+// SetupHTTPRouterDocs sets up documentation for an httprouter router with auto-detection.
+// The returned APIDocs can be used to customize the generated documentation
+func SetupHTTPRouterDocs(router *HTTPRouterWrapper, config *core.Config) *core.APIDocs {
+	docs := SetupFrameworkDocs(httpRouterAdapter{router: router}, config)
+
+	config = docs.GetConfig()
+	if config.SeparatePort != "" {
+		return docs
+	}
+
+	// httprouter has no any-method route, so the docs are mounted for every method they handle
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions} {
+		router.Router.Handler(method, strings.TrimSuffix(config.DocsPath, "/")+"/*path", docs)
+	}
+
+	return docs
+}
@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestSetupHTTPRouterDocs_DocumentsRecordedRoutes(t *testing.T) {
+	router := NewHTTPRouterWrapper()
+	router.GET("/users/:id", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {})
+	router.HandlerFunc(http.MethodPost, "/users", func(w http.ResponseWriter, r *http.Request) {})
+	router.GET("/files/*filepath", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {})
+
+	SetupHTTPRouterDocs(router, &core.Config{DocsPath: "/docs", AutoDetect: true})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/docs/api-data.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected the docs to be mounted on the router, got %d", rec.Code)
+	}
+	var documentation core.Documentation
+	if err := json.Unmarshal(rec.Body.Bytes(), &documentation); err != nil {
+		t.Fatal(err)
+	}
+	paths := make(map[string]core.Endpoint)
+	for _, section := range documentation.Endpoints {
+		for _, endpoint := range section.Endpoints {
+			paths[endpoint.Method+" "+endpoint.Path] = endpoint
+		}
+	}
+	if len(paths) != 3 {
+		t.Fatalf("expected the three application routes, got %v", paths)
+	}
+	files, ok := paths["GET /files/{filepath}"]
+	if !ok || len(files.Parameters) != 1 || files.Parameters[0].Name != "filepath" || files.Parameters[0].In != "path" {
+		t.Fatalf("expected the catch-all to be a path parameter, got %+v", files)
+	}
+	if _, ok := paths["GET /users/{id}"]; !ok {
+		t.Fatalf("expected the named parameter route, got %v", paths)
+	}
+
+	if _, err := SetupDocs(NewHTTPRouterWrapper(), nil); err != nil {
+		t.Fatalf("expected the httprouter adapter to be registered: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInjectedInterfaceResultsResolveToDTOs(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+type UserDTO struct {
+	ID    int    ` + "`json:\"id\"`" + `
+	Email string ` + "`json:\"email\"`" + `
+}
+
+type Reader interface {
+	GetUser(ctx context.Context, id string) (*UserDTO, error)
+}
+
+type UserService interface {
+	Reader
+	ListUsers(ctx context.Context) ([]UserDTO, error)
+}
+
+type base struct {
+	users UserService
+}
+
+type UserHandler struct {
+	base
+}
+
+func (h *UserHandler) GetUser(c *gin.Context) {
+	user, err := h.users.GetUser(c, c.Param("id"))
+	if err != nil {
+		return
+	}
+	c.JSON(200, user)
+}
+
+func ListUsers(svc UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		users, _ := svc.ListUsers(c)
+		c.JSON(200, users)
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := analyzeDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var getUser HandlerMetadata
+	for _, candidate := range analysis.handlers["getuser"] {
+		if candidate.receiverName == "UserHandler" {
+			getUser = candidate.metadata
+		}
+	}
+	schema, _ := getUser.Responses["200"].Schema.(map[string]interface{})
+	if props, _ := schema["properties"].(map[string]interface{}); props["email"] == nil {
+		t.Errorf("expected UserDTO schema through the embedded interface, got %+v", getUser.Responses["200"].Schema)
+	}
+
+	list := analysis.handlers["listusers"][0].metadata.Responses["200"].Schema.(map[string]interface{})
+	if list["type"] != "array" {
+		t.Fatalf("expected array schema for injected service results, got %+v", list)
+	}
+	if props, _ := list["items"].(map[string]interface{})["properties"].(map[string]interface{}); props["id"] == nil {
+		t.Errorf("expected UserDTO items, got %+v", list)
+	}
+}
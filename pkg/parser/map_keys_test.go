@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMapKeyTypesAndPatterns(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+type Profile struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type Directory struct {
+	Profiles  map[UserID]Profile ` + "`json:\"profiles\" keydescription:\"ID of the user\"`" + `
+	Shards    map[int64]string   ` + "`json:\"shards\"`" + `
+	Countries map[string]int     ` + "`json:\"countries\" keypattern:\"^[A-Z]{2}$\"`" + `
+}
+
+// ListDirectory lists the directory
+func ListDirectory(c *gin.Context) {
+	c.JSON(200, Directory{})
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := analyzeDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := analysis.handlers["listdirectory"][0].metadata.Responses["200"]
+	schema, _ := response.Schema.(map[string]interface{})
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	profiles, _ := properties["profiles"].(map[string]interface{})
+	if profiles["x-additionalPropertiesName"] != "UserID" || profiles["x-key-description"] != "ID of the user" {
+		t.Fatalf("expected the key type and description, got %+v", profiles)
+	}
+	shards, _ := properties["shards"].(map[string]interface{})
+	if patterns, _ := shards["x-patternProperties"].(map[string]interface{}); patterns["^-?[0-9]+$"] == nil {
+		t.Fatalf("expected integer keys to document their pattern, got %+v", shards)
+	}
+	countries, _ := properties["countries"].(map[string]interface{})
+	if patterns, _ := countries["x-patternProperties"].(map[string]interface{}); patterns["^[A-Z]{2}$"] == nil {
+		t.Fatalf("expected the keypattern tag as a pattern hint, got %+v", countries)
+	}
+}
@@ -12,27 +12,24 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/idnexacloud/bytedocs-go/pkg/core"
 )
 
-// Global registry for net/http route tracking
-var (
-	globalNetHTTPDocs   *core.APIDocs
-	netHTTPDocsConfig   *core.Config
-	netHTTPDocsMutex    sync.RWMutex
-	netHTTPFuncComments map[string][]string
-)
-
-func init() {
-	netHTTPFuncComments = make(map[string][]string)
-}
-
 // NetHTTPHandlerInfo holds parsed comment information for net/http handlers
 type NetHTTPHandlerInfo struct {
-	Summary     string
-	Description string
-	Parameters  []core.Parameter
+	Summary      string
+	Description  string
+	Parameters   []core.Parameter
+	Section      string             // from "@Section <id>"
+	ExternalDocs *core.ExternalDocs // from "@Docs <url> [description]"
+	Presets      []core.Preset      // from "@Preset <name> [param=value ...] [json body]"
+	SLA          time.Duration      // from "@SLA <duration>"
+	Pagination   string             // from "@Paginate <style>"
+	Accepts      []string           // from "@Accept <content types>"
+	ResponseDocs map[string]string  // from "@Response <status> <description>"
+	Extensions   core.Extensions    // from "@x-<name> [value]"
 }
 
 // NetHTTPHandlerMetadata stores extracted documentation data for a net/http handler function.
@@ -40,22 +37,32 @@ type NetHTTPHandlerMetadata struct {
 	Info        NetHTTPHandlerInfo
 	RequestBody *core.RequestBody
 	Responses   map[string]core.Response
+	Warnings    []string `json:"-"` // why no schemas were inferred; not cached
+	Trace       []string `json:"-"` // analysis steps when debugging; not cached
 }
 
-// getNetHTTPHandlerMetadataByName gets handler metadata by analyzing the function name from parsed files
-func getNetHTTPHandlerMetadataByName(funcName string, dir string) NetHTTPHandlerMetadata {
-	// For net/http, we'll reuse the analysis logic from gorilla_mux_analyzer.go
-	// since both use the same writeJSON pattern
-	gorillaMeta := getGorillaMuxHandlerMetadataByName(funcName, dir)
+// getNetHTTPHandlerMetadata resolves a net/http handler, including controller method values, to its metadata
+func getNetHTTPHandlerMetadata(handler http.Handler) NetHTTPHandlerMetadata {
+	gorillaMeta := getGorillaMuxHandlerMetadata(handler)
 
 	return NetHTTPHandlerMetadata{
 		Info: NetHTTPHandlerInfo{
-			Summary:     gorillaMeta.Info.Summary,
-			Description: gorillaMeta.Info.Description,
-			Parameters:  gorillaMeta.Info.Parameters,
+			Summary:      gorillaMeta.Info.Summary,
+			Description:  gorillaMeta.Info.Description,
+			Parameters:   gorillaMeta.Info.Parameters,
+			Section:      gorillaMeta.Info.Section,
+			ExternalDocs: gorillaMeta.Info.ExternalDocs,
+			Presets:      gorillaMeta.Info.Presets,
+			SLA:          gorillaMeta.Info.SLA,
+			Pagination:   gorillaMeta.Info.Pagination,
+			Accepts:      gorillaMeta.Info.Accepts,
+			ResponseDocs: gorillaMeta.Info.ResponseDocs,
+			Extensions:   gorillaMeta.Info.Extensions,
 		},
 		RequestBody: gorillaMeta.RequestBody,
 		Responses:   gorillaMeta.Responses,
+		Warnings:    gorillaMeta.Warnings,
+		Trace:       gorillaMeta.Trace,
 	}
 }
 
@@ -101,6 +108,44 @@ func parseNetHTTPHandlerInfo(comments []string) NetHTTPHandlerInfo {
 	paramRegex := regexp.MustCompile(`@Param\s+(\w+)\s+(\w+)\s+(\w+)\s+(true|false)\s+"([^"]*)"`)
 
 	for _, line := range comments {
+		if section, ok := sectionAnnotation(line); ok {
+			info.Section = section
+			continue
+		}
+		if externalDocs, ok := docsAnnotation(line); ok {
+			info.ExternalDocs = externalDocs
+			continue
+		}
+		if preset, ok := presetAnnotation(line); ok {
+			info.Presets = append(info.Presets, preset)
+			continue
+		}
+		if sla, ok := slaAnnotation(line); ok {
+			info.SLA = sla
+			continue
+		}
+		if style, ok := paginateAnnotation(line); ok {
+			info.Pagination = style
+			continue
+		}
+		if accepts, ok := acceptAnnotation(line); ok {
+			info.Accepts = append(info.Accepts, accepts...)
+			continue
+		}
+		if status, description, ok := responseAnnotation(line); ok {
+			if info.ResponseDocs == nil {
+				info.ResponseDocs = make(map[string]string)
+			}
+			info.ResponseDocs[status] = description
+			continue
+		}
+		if name, value, ok := extensionAnnotation(line); ok {
+			if info.Extensions == nil {
+				info.Extensions = make(core.Extensions)
+			}
+			info.Extensions[name] = value
+			continue
+		}
 		// Parse @Param annotations
 		if matches := paramRegex.FindStringSubmatch(line); len(matches) == 6 {
 			param := core.Parameter{
@@ -144,7 +189,7 @@ func extractNetHTTPHandlerName(handler http.Handler) string {
 
 	parts := strings.Split(funcName, ".")
 	if len(parts) > 0 {
-		return parts[len(parts)-1]
+		return strings.TrimSuffix(parts[len(parts)-1], methodValueSuffix)
 	}
 
 	return ""
@@ -211,8 +256,9 @@ func (m *NetHTTPMuxWrapper) GetRoutes() []NetHTTPRoute {
 	return routes
 }
 
-// SetupNetHTTPDocs sets up documentation for a net/http ServeMux with auto-detection
-func SetupNetHTTPDocs(mux *NetHTTPMuxWrapper, config *core.Config) {
+// SetupNetHTTPDocs sets up documentation for a net/http ServeMux with auto-detection.
+// The returned APIDocs can be used to customize the generated documentation
+func SetupNetHTTPDocs(mux *NetHTTPMuxWrapper, config *core.Config) *core.APIDocs {
 	if config == nil {
 		config = &core.Config{
 			Title:      "API Documentation",
@@ -222,22 +268,16 @@ func SetupNetHTTPDocs(mux *NetHTTPMuxWrapper, config *core.Config) {
 		}
 	}
 
-	netHTTPDocsMutex.Lock()
-	netHTTPDocsConfig = config
-	globalNetHTTPDocs = core.New(config)
-	netHTTPDocsMutex.Unlock()
+	analysis := newAnalysisSettings(config)
 
-	// Set up the docs route that does auto-detection
-	mux.HandleFunc(config.DocsPath+"/", func(w http.ResponseWriter, r *http.Request) {
-		fmt.Printf("🚀 Net/HTTP docs handler called for path: %s\n", r.URL.Path)
-		netHTTPDocsMutex.Lock()
-		defer netHTTPDocsMutex.Unlock()
+	docs := core.New(config)
 
-		// Check if we need to detect routes
-		endpointsCount := len(globalNetHTTPDocs.GetDocumentation().Endpoints)
-		fmt.Printf("🔍 Current endpoints count: %d, AutoDetect: %t\n", endpointsCount, config.AutoDetect)
+	// Refresh analyzes the handlers from source again
+	docs.OnRefresh(analysis.resetCaches)
 
-		if endpointsCount == 0 && config.AutoDetect {
+	// Routes are detected on Warmup or the first docs request, and again on Refresh
+	if config.AutoDetect {
+		docs.SetRouteDetector(analysis.scoped(func() {
 			// Parse handler comments first
 			fmt.Printf("📝 Parsing net/http handler comments...\n")
 			handlerInfos := parseNetHTTPHandlerComments("main.go", "examples/net-http/main.go")
@@ -251,9 +291,7 @@ func SetupNetHTTPDocs(mux *NetHTTPMuxWrapper, config *core.Config) {
 				fmt.Printf("📍 Route: %s %s\n", route.Method, route.Path)
 
 				// Skip docs routes and static files
-				if strings.HasPrefix(route.Path, config.DocsPath) ||
-					strings.Contains(route.Path, "/static") ||
-					strings.Contains(route.Path, "/assets") {
+				if strings.HasPrefix(route.Path, config.DocsPath) {
 					fmt.Printf("⏭️  Skipping route: %s\n", route.Path)
 					continue
 				}
@@ -263,18 +301,28 @@ func SetupNetHTTPDocs(mux *NetHTTPMuxWrapper, config *core.Config) {
 				handlerInfo := handlerInfos[handlerName]
 
 				// Perform AST analysis to get metadata (request/response structures)
-				metadata := getNetHTTPHandlerMetadataByName(handlerName, ".")
+				metadata := getNetHTTPHandlerMetadata(route.Handler)
 
 				// Create route info from net/http route with AST-analyzed data
 				routeInfo := core.RouteInfo{
-					Method:      route.Method,
-					Path:        route.Path,
-					Handler:     route.Handler,
-					Summary:     handlerInfo.Summary,
-					Description: handlerInfo.Description,
-					Parameters:  handlerInfo.Parameters,
-					RequestBody: metadata.RequestBody,
-					Responses:   metadata.Responses,
+					Method:       route.Method,
+					Path:         route.Path,
+					Handler:      route.Handler,
+					Summary:      handlerInfo.Summary,
+					Description:  handlerInfo.Description,
+					Parameters:   handlerInfo.Parameters,
+					Section:      handlerInfo.Section,
+					ExternalDocs: handlerInfo.ExternalDocs,
+					Presets:      handlerInfo.Presets,
+					SLA:          handlerInfo.SLA,
+					Pagination:   handlerInfo.Pagination,
+					Accepts:      handlerInfo.Accepts,
+					ResponseDocs: handlerInfo.ResponseDocs,
+					Extensions:   handlerInfo.Extensions,
+					RequestBody:  metadata.RequestBody,
+					Responses:    metadata.Responses,
+					Warnings:     metadata.Warnings,
+					Trace:        metadata.Trace,
 				}
 
 				fmt.Printf("✅ Adding net/http route: %s %s (handler: %s)\n", route.Method, route.Path, handlerName)
@@ -300,20 +348,31 @@ func SetupNetHTTPDocs(mux *NetHTTPMuxWrapper, config *core.Config) {
 				}
 
 				// Add to documentation
-				globalNetHTTPDocs.AddRouteInfo(routeInfo)
+				docs.AddRouteInfo(routeInfo)
 			}
 
 			fmt.Printf("📚 Generating net/http documentation...\n")
 			// Generate documentation
-			globalNetHTTPDocs.Generate()
+			docs.Generate()
 
-			fmt.Printf("📊 Final endpoints count: %d\n", len(globalNetHTTPDocs.GetDocumentation().Endpoints))
-		}
+			fmt.Printf("📊 Final endpoints count: %d\n", len(docs.GetDocumentation().Endpoints))
+		}))
+	}
+
+	if serveDocsSeparately(docs, config) {
+		return docs
+	}
+
+	// Set up the docs route that does auto-detection
+	mux.HandleFunc(config.DocsPath+"/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Printf("🚀 Net/HTTP docs handler called for path: %s\n", r.URL.Path)
 
 		// Serve documentation
-		globalNetHTTPDocs.ServeHTTP(w, r)
+		docs.ServeHTTP(w, r)
 	})
 
+
+	return docs
 }
 
 // NetHTTPMiddleware creates net/http middleware for automatic route documentation
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+	"sync"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// unionDefinition is an interface type documented as a discriminated union
+// with a "@oneOf <property> <value>=<Type>..." comment line
+type unionDefinition struct {
+	discriminator string
+	values        []string
+	types         []string
+}
+
+// unionDefinitions holds the unions by the placeholder *ast.StructType that
+// stands for their interface type among the collected structs
+var unionDefinitions sync.Map
+
+// oneOfAnnotation parses a "@oneOf type card=CardPayment bank=BankTransfer"
+// comment line
+func oneOfAnnotation(line string) (unionDefinition, bool) {
+	rest, found := strings.CutPrefix(line, "@oneOf")
+	if !found || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return unionDefinition{}, false
+	}
+	fields := strings.Fields(rest)
+	if len(fields) < 2 {
+		fmt.Printf("⚠️  Ignoring @oneOf %q: expected a discriminator property and value=Type variants\n", strings.TrimSpace(rest))
+		return unionDefinition{}, false
+	}
+
+	definition := unionDefinition{discriminator: fields[0]}
+	for _, variant := range fields[1:] {
+		value, typeName, ok := strings.Cut(variant, "=")
+		if !ok || value == "" || typeName == "" {
+			fmt.Printf("⚠️  Ignoring @oneOf variant %q: expected value=Type\n", variant)
+			continue
+		}
+		if dot := strings.LastIndex(typeName, "."); dot >= 0 {
+			typeName = typeName[dot+1:]
+		}
+		definition.values = append(definition.values, value)
+		definition.types = append(definition.types, strings.TrimPrefix(typeName, "*"))
+	}
+	return definition, len(definition.values) > 0
+}
+
+// collectUnionDefinition registers an interface type whose doc comment has a
+// @oneOf line, so fields and bodies of that type are documented as the union
+func collectUnionDefinition(name string, doc *ast.CommentGroup, structs map[string]*ast.StructType) {
+	if doc == nil {
+		return
+	}
+	var description []string
+	var definition unionDefinition
+	found := false
+	for _, line := range extractCommentsText(doc.List) {
+		if parsed, ok := oneOfAnnotation(line); ok {
+			definition, found = parsed, true
+			continue
+		}
+		description = append(description, line)
+	}
+	if !found {
+		return
+	}
+
+	placeholder := &ast.StructType{Fields: &ast.FieldList{}}
+	structs[name] = placeholder
+	unionDefinitions.Store(placeholder, definition)
+	if len(description) > 0 {
+		structDocs.Store(placeholder, parseStructDoc(description))
+	}
+}
+
+func lookupUnionDefinition(structType *ast.StructType) (unionDefinition, bool) {
+	definition, ok := unionDefinitions.Load(structType)
+	if !ok {
+		return unionDefinition{}, false
+	}
+	return definition.(unionDefinition), true
+}
+
+// buildUnionSchema builds the oneOf schema of a union from its variant structs
+func buildUnionSchema(definition unionDefinition, ctx *analysisContext, visited map[string]bool) (map[string]interface{}, map[string]interface{}) {
+	variants := make([]core.UnionVariant, 0, len(definition.values))
+	for i, value := range definition.values {
+		typeName := definition.types[i]
+		variant := core.UnionVariant{Value: value, Name: typeName}
+		if structType, ok := ctx.structs[typeName]; ok && !visited[typeName] {
+			visited[typeName] = true
+			variant.Schema, variant.Example = buildStructSchema(structType, ctx, visited)
+			visited[typeName] = false
+		}
+		variants = append(variants, variant)
+	}
+
+	schema, example := core.UnionSchema(definition.discriminator, variants)
+	exampleMap, _ := example.(map[string]interface{})
+	return schema, exampleMap
+}
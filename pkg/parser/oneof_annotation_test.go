@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOneOfAnnotationDocumentsInterfaceUnions(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+// Payment is how an order is paid
+// @oneOf method card=CardPayment bank_transfer=BankTransfer
+type Payment interface{ isPayment() }
+
+type CardPayment struct {
+	Number string ` + "`json:\"number\" binding:\"required\"`" + `
+}
+
+type BankTransfer struct {
+	IBAN string ` + "`json:\"iban\"`" + `
+}
+
+type CreateOrder struct {
+	Payment Payment ` + "`json:\"payment\"`" + `
+}
+
+// PlaceOrder places an order
+func PlaceOrder(c *gin.Context) {
+	var body CreateOrder
+	c.ShouldBindJSON(&body)
+	c.JSON(201, body)
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := analyzeDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata := analysis.handlers["placeorder"][0].metadata
+	schema, _ := metadata.RequestBody.Schema.(map[string]interface{})
+	payment, _ := schema["properties"].(map[string]interface{})["payment"].(map[string]interface{})
+	variants, _ := payment["oneOf"].([]interface{})
+	if len(variants) != 2 || payment["description"] != "Payment is how an order is paid" {
+		t.Fatalf("expected the @oneOf variants and the interface doc comment, got %+v", payment)
+	}
+	discriminator, _ := payment["discriminator"].(map[string]interface{})
+	mapping, _ := discriminator["mapping"].(map[string]interface{})
+	if discriminator["propertyName"] != "method" || mapping["bank_transfer"] != "#/components/schemas/BankTransfer" {
+		t.Fatalf("expected a discriminator mapping, got %+v", discriminator)
+	}
+	card, _ := variants[0].(map[string]interface{})
+	cardProperties, _ := card["properties"].(map[string]interface{})
+	if card["title"] != "CardPayment" || cardProperties["number"] == nil {
+		t.Fatalf("expected the variant struct schema, got %+v", card)
+	}
+	example, _ := metadata.RequestBody.Example.(map[string]interface{})
+	if paymentExample, _ := example["payment"].(map[string]interface{}); paymentExample["method"] != "card" {
+		t.Fatalf("expected the first variant as example, got %+v", example)
+	}
+}
@@ -0,0 +1,28 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// paginateAnnotation returns the pagination style of a "@Paginate <style>"
+// comment line: offset, cursor, page, or none to opt out of Config.Pagination
+func paginateAnnotation(line string) (string, bool) {
+	rest, found := strings.CutPrefix(line, "@Paginate")
+	if !found || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return "", false
+	}
+	style := strings.ToLower(strings.TrimSpace(rest))
+	if style == "" {
+		return "", false
+	}
+
+	switch style {
+	case core.PaginationOffset, core.PaginationCursor, core.PaginationPage, core.PaginationNone:
+		return style, true
+	}
+	fmt.Printf("⚠️  Ignoring @Paginate %q: expected offset, cursor, page or none\n", style)
+	return "", false
+}
@@ -0,0 +1,28 @@
+package parser
+
+import "testing"
+
+func TestPaginateAnnotationSetsStyle(t *testing.T) {
+	comments := []string{
+		"ListOrders lists orders",
+		"@Paginate cursor",
+		`@Param status query string false "Order status"`,
+	}
+
+	info := parseHandlerInfo(comments)
+	if info.Pagination != "cursor" {
+		t.Fatalf("expected cursor pagination, got %q", info.Pagination)
+	}
+	if info.Summary != "ListOrders lists orders" || len(info.Parameters) != 1 {
+		t.Fatalf("@Paginate must not disturb summary or params, got %+v", info)
+	}
+	if stdlibInfo := parseStdlibHandlerInfo([]string{"@Paginate None"}); stdlibInfo.Pagination != "none" {
+		t.Fatalf("expected stdlib handlers to opt out with none, got %q", stdlibInfo.Pagination)
+	}
+
+	for _, line := range []string{"@Paginated offset", "@Paginate", "@Paginate infinite"} {
+		if style, ok := paginateAnnotation(line); ok {
+			t.Fatalf("%q must not be a pagination annotation, got %q", line, style)
+		}
+	}
+}
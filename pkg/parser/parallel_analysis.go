@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+var (
+	analysisWorkers      int
+	analysisWorkersMutex sync.RWMutex
+)
+
+// SetAnalysisWorkers bounds how many files are parsed and analyzed concurrently.
+// Zero or a negative value uses GOMAXPROCS. Config.AnalysisWorkers overrides
+// it for one docs instance.
+func SetAnalysisWorkers(workers int) {
+	analysisWorkersMutex.Lock()
+	defer analysisWorkersMutex.Unlock()
+	analysisWorkers = workers
+}
+
+func getAnalysisWorkers() int {
+	if workers := currentAnalysisSettings().analysisWorkers(); workers > 0 {
+		return workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// runBounded calls fn for every index in [0, n) on at most getAnalysisWorkers goroutines
+func runBounded(n int, fn func(i int)) {
+	workers := getAnalysisWorkers()
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// parseGoDir parses the non-test Go files in dir concurrently. It mirrors
+// parser.ParseDir: files are grouped by package name and keyed by their path.
+func parseGoDir(fset *token.FileSet, dir string) (map[string]*ast.Package, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && isAnalyzedGoFile(entry.Name()) {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	files := make([]*ast.File, len(paths))
+	errs := make([]error, len(paths))
+	runBounded(len(paths), func(i int) {
+		files[i], errs[i] = parser.ParseFile(fset, paths[i], nil, parser.ParseComments)
+	})
+
+	pkgs := make(map[string]*ast.Package)
+	for i, file := range files {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		name := file.Name.Name
+		pkg, ok := pkgs[name]
+		if !ok {
+			pkg = &ast.Package{Name: name, Files: make(map[string]*ast.File)}
+			pkgs[name] = pkg
+		}
+		pkg.Files[paths[i]] = file
+	}
+
+	return pkgs, nil
+}
+
+// collectPerFile runs collect for every parsed file concurrently and merges the
+// per-file results in file path order, so the output does not depend on scheduling
+func collectPerFile[H any](pkgs map[string]*ast.Package, collect func(file *ast.File, handlers map[string][]H)) map[string][]H {
+	paths := make([]string, 0)
+	files := make(map[string]*ast.File)
+	for _, pkg := range pkgs {
+		for path, file := range pkg.Files {
+			paths = append(paths, path)
+			files[path] = file
+		}
+	}
+	sort.Strings(paths)
+
+	results := make([]map[string][]H, len(paths))
+	runBounded(len(paths), func(i int) {
+		results[i] = make(map[string][]H)
+		collect(files[paths[i]], results[i])
+	})
+
+	handlers := make(map[string][]H)
+	for _, result := range results {
+		for key, entries := range result {
+			handlers[key] = append(handlers[key], entries...)
+		}
+	}
+	return handlers
+}
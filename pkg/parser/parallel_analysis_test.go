@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParallelAnalysisMatchesSequential(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 40; i++ {
+		source := fmt.Sprintf(`package api
+
+type Item%[1]d struct {
+	ID   int    `+"`json:\"id\"`"+`
+	Name string `+"`json:\"name\" binding:\"required\"`"+`
+}
+
+// GetItem%[1]d returns item %[1]d
+func GetItem%[1]d(c *gin.Context) {
+	c.JSON(200, Item%[1]d{ID: %[1]d})
+}
+`, i)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("item%d.go", i)), []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer SetAnalysisWorkers(0)
+
+	SetAnalysisWorkers(1)
+	sequential, err := analyzeDirectory(dir, nil)
+	if err != nil {
+		t.Fatalf("sequential analysis failed: %v", err)
+	}
+
+	SetAnalysisWorkers(8)
+	parallel, err := analyzeDirectory(dir, nil)
+	if err != nil {
+		t.Fatalf("parallel analysis failed: %v", err)
+	}
+
+	if len(parallel.handlers) != 40 {
+		t.Fatalf("expected 40 handlers, got %d", len(parallel.handlers))
+	}
+	if !reflect.DeepEqual(sequential.handlers, parallel.handlers) {
+		t.Fatalf("parallel analysis differs from sequential analysis")
+	}
+	if summary := parallel.handlers["getitem7"][0].metadata.Info.Summary; summary != "GetItem7 returns item 7" {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+}
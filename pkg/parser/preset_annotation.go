@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// presetAnnotation returns the try-it preset of a
+// `@Preset "<name>" [param=value ...] [json body]` comment line, e.g.
+// `@Preset "create admin user" X-Tenant=acme {"name": "Ada", "role": "admin"}`
+func presetAnnotation(line string) (core.Preset, bool) {
+	rest, found := strings.CutPrefix(line, "@Preset")
+	if !found || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return core.Preset{}, false
+	}
+	rest = strings.TrimSpace(rest)
+
+	var preset core.Preset
+	if strings.HasPrefix(rest, `"`) {
+		quoted, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return core.Preset{}, false
+		}
+		preset.Name, _ = strconv.Unquote(quoted)
+		rest = strings.TrimSpace(rest[len(quoted):])
+	} else {
+		preset.Name, rest, _ = strings.Cut(rest, " ")
+		rest = strings.TrimSpace(rest)
+	}
+	if preset.Name == "" {
+		return core.Preset{}, false
+	}
+
+	for rest != "" && rest[0] != '{' && rest[0] != '[' {
+		var field string
+		field, rest, _ = strings.Cut(rest, " ")
+		rest = strings.TrimSpace(rest)
+		name, value, ok := strings.Cut(field, "=")
+		if !ok || name == "" {
+			fmt.Printf("⚠️  Ignoring @Preset %q: expected param=value, got %q\n", preset.Name, field)
+			return core.Preset{}, false
+		}
+		if preset.Params == nil {
+			preset.Params = make(map[string]string)
+		}
+		preset.Params[name] = value
+	}
+
+	if rest != "" {
+		if err := json.Unmarshal([]byte(rest), &preset.Body); err != nil {
+			fmt.Printf("⚠️  Ignoring @Preset %q: invalid JSON body: %v\n", preset.Name, err)
+			return core.Preset{}, false
+		}
+	}
+	return preset, true
+}
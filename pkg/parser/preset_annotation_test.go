@@ -0,0 +1,33 @@
+package parser
+
+import "testing"
+
+func TestPresetAnnotationAddsNamedPresets(t *testing.T) {
+	comments := []string{
+		"CreateUser creates a user",
+		`@Preset "create admin user" X-Tenant=acme {"name": "Ada", "role": "admin"}`,
+		`@Preset guest`,
+	}
+
+	info := parseHandlerInfo(comments)
+	if len(info.Presets) != 2 || info.Summary != "CreateUser creates a user" {
+		t.Fatalf("expected two presets and an untouched summary, got %+v", info)
+	}
+	admin := info.Presets[0]
+	body, _ := admin.Body.(map[string]interface{})
+	if admin.Name != "create admin user" || admin.Params["X-Tenant"] != "acme" || body["role"] != "admin" {
+		t.Fatalf("expected name, params and JSON body, got %+v", admin)
+	}
+	if guest := info.Presets[1]; guest.Name != "guest" || guest.Params != nil || guest.Body != nil {
+		t.Fatalf("expected a bare preset, got %+v", guest)
+	}
+	if fiberInfo := parseFiberHandlerInfo(comments); len(fiberInfo.Presets) != 2 {
+		t.Fatalf("expected fiber handlers to read @Preset, got %+v", fiberInfo.Presets)
+	}
+
+	for _, line := range []string{"@Presets x", "@Preset", `@Preset "broken`, `@Preset x {"name":`, `@Preset x novalue`} {
+		if preset, ok := presetAnnotation(line); ok {
+			t.Fatalf("%q must not be a preset annotation, got %+v", line, preset)
+		}
+	}
+}
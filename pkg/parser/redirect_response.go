@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"html"
+	"net/http"
+	"strconv"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// redirectResponse documents an http.Redirect(w, r, url, code) call as a 3xx
+// response with a Location header and the short HTML body net/http writes
+func redirectResponse(call *ast.CallExpr, ctx *analysisContext) (string, core.Response, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Redirect" || len(call.Args) < 4 {
+		return "", core.Response{}, false
+	}
+	if qualifier, ok := sel.X.(*ast.Ident); !ok || qualifier.Name != "http" {
+		return "", core.Response{}, false
+	}
+
+	statusCode := extractStatusCode(call.Args[3], ctx)
+	if statusCode == "" {
+		statusCode = strconv.Itoa(http.StatusFound)
+	}
+	description := statusTextFromCode(statusCode)
+	if description == "" {
+		description = "Redirect"
+	}
+
+	location := core.ResponseHeader{Description: "URL the client is redirected to", Type: "string"}
+	example := ""
+	if url, ok := stringLiteral(call.Args[2]); ok {
+		location.Example = url
+		example = "<a href=\"" + html.EscapeString(url) + "\">" + description + "</a>."
+	}
+	return statusCode, core.Response{
+		Description: description,
+		Example:     example,
+		Schema:      map[string]interface{}{"type": "string"},
+		ContentType: "text/html",
+		Headers:     map[string]core.ResponseHeader{"Location": location},
+	}, true
+}
+
+// httpErrorMessage returns the message of an http.Error call as a string
+// literal: the literal passed, the text of http.StatusText(code), or a
+// placeholder for messages only known at runtime
+func httpErrorMessage(expr ast.Expr, ctx *analysisContext) ast.Expr {
+	if _, ok := stringLiteral(expr); ok {
+		return expr
+	}
+	if call, ok := expr.(*ast.CallExpr); ok && len(call.Args) == 1 {
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "StatusText" {
+			if text := statusTextFromCode(extractStatusCode(call.Args[0], ctx)); text != "" {
+				return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(text)}
+			}
+		}
+	}
+	return &ast.BasicLit{Kind: token.STRING, Value: `"error message"`}
+}
+
+// stringLiteral returns the value of a string literal expression
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
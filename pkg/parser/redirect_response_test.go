@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStdlibRedirectAndErrorResponses(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+import "net/http"
+
+// Login redirects to the dashboard
+func Login(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue("user") == "" {
+		http.Error(w, "missing user", http.StatusBadRequest)
+		return
+	}
+	if r.FormValue("token") == "" {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := analyzeStdlibDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	responses := analysis.handlers["login"][0].metadata.Responses
+	redirect, ok := responses["303"]
+	if !ok || redirect.Headers["Location"].Example != "/dashboard" || redirect.ContentType != "text/html" {
+		t.Fatalf("expected a 303 redirect with a Location header, got %+v", responses)
+	}
+	if badRequest := responses["400"]; badRequest.ContentType != "text/plain" || badRequest.Example != "missing user" {
+		t.Fatalf("expected the http.Error message as text/plain example, got %+v", badRequest)
+	}
+	if unauthorized := responses["401"]; unauthorized.Example != "Unauthorized" {
+		t.Fatalf("expected the status text as message, got %+v", unauthorized)
+	}
+}
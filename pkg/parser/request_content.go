@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"go/ast"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// mergeRequestBody adds a body bound in another content type, e.g. a
+// ShouldBindXML next to a ShouldBindJSON, as an alternative of the first one
+func mergeRequestBody(existing, found *core.RequestBody) *core.RequestBody {
+	if existing == nil {
+		return found
+	}
+	for _, content := range existing.Contents() {
+		if content.ContentType == found.ContentType {
+			return existing
+		}
+	}
+	existing.Alternatives = append(existing.Alternatives, core.BodyContent{
+		ContentType: found.ContentType,
+		Schema:      found.Schema,
+		Example:     found.Example,
+	})
+	return existing
+}
+
+// decoderContentTypes maps encoding packages to the content type their
+// NewDecoder(...).Decode(&v) reads
+var decoderContentTypes = map[string]string{
+	"json": "application/json",
+	"xml":  "application/xml",
+	"yaml": "application/x-yaml",
+}
+
+// decoderContentType returns the content type of a pkg.NewDecoder(r.Body).Decode
+// call, or "" when the decoder's package is not known
+func decoderContentType(call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	inner, ok := sel.X.(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+	newDecoder, ok := inner.Fun.(*ast.SelectorExpr)
+	if !ok || newDecoder.Sel.Name != "NewDecoder" {
+		return ""
+	}
+	if pkg, ok := newDecoder.X.(*ast.Ident); ok {
+		return decoderContentTypes[pkg.Name]
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRequestBodyContentTypes(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+type CreateUser struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type CreateUserXML struct {
+	FullName string ` + "`xml:\"full_name\"`" + `
+}
+
+// CreateUser creates a user from JSON or XML
+// @Accept yaml
+func CreateUser(c *gin.Context) {
+	if c.ContentType() == "application/xml" {
+		var body CreateUserXML
+		c.ShouldBindXML(&body)
+		return
+	}
+	var body CreateUser
+	c.ShouldBindJSON(&body)
+	c.ShouldBindJSON(&body)
+}
+
+// ImportUsers reads JSON or XML
+func ImportUsers(w http.ResponseWriter, r *http.Request) {
+	var body CreateUser
+	if r.Header.Get("Content-Type") == "application/xml" {
+		var xmlBody CreateUserXML
+		xml.NewDecoder(r.Body).Decode(&xmlBody)
+		return
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := analyzeDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata := analysis.handlers["createuser"][0].metadata
+	body := metadata.RequestBody
+	if body == nil || body.ContentType != "application/xml" || len(body.Alternatives) != 1 || body.Alternatives[0].ContentType != "application/json" {
+		t.Fatalf("expected the XML body with a JSON alternative, got %+v", body)
+	}
+	if len(metadata.Info.Accepts) != 1 || metadata.Info.Accepts[0] != "application/x-yaml" {
+		t.Fatalf("expected @Accept yaml to be expanded, got %v", metadata.Info.Accepts)
+	}
+
+	stdlib, err := analyzeStdlibDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body = stdlib.handlers["importusers"][0].metadata.RequestBody
+	if body == nil || body.ContentType != "application/xml" || len(body.Alternatives) != 1 || body.Alternatives[0].ContentType != "application/json" {
+		t.Fatalf("expected decoder packages to set the content types, got %+v", body)
+	}
+
+	if contentTypes, ok := acceptAnnotation("@Accept json, application/vnd.api+json"); !ok || len(contentTypes) != 2 || contentTypes[0] != "application/json" {
+		t.Fatalf("expected shorthands and full content types, got %v", contentTypes)
+	}
+	for _, line := range []string{"@Accepts json", "@Accept", "@Accept bogus"} {
+		if contentTypes, ok := acceptAnnotation(line); ok {
+			t.Fatalf("%q must not be an accept annotation, got %v", line, contentTypes)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// responseAnnotation returns the status code and description of an
+// `@Response <status> "description"` comment line, e.g.
+// `@Response 404 "User not found with the given ID"`; the quotes are optional
+func responseAnnotation(line string) (string, string, bool) {
+	rest, found := strings.CutPrefix(line, "@Response")
+	if !found || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return "", "", false
+	}
+	status, description, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	description = strings.TrimSpace(description)
+	if unquoted, err := strconv.Unquote(description); err == nil {
+		description = unquoted
+	}
+
+	if code, err := strconv.Atoi(status); err != nil || code < 100 || code > 599 {
+		fmt.Printf("⚠️  Ignoring @Response %q: expected a status code like 404\n", status)
+		return "", "", false
+	}
+	if description == "" {
+		fmt.Printf("⚠️  Ignoring @Response %s: expected a description\n", status)
+		return "", "", false
+	}
+	return status, description, true
+}
@@ -0,0 +1,29 @@
+package parser
+
+import "testing"
+
+func TestResponseAnnotationDescribesStatuses(t *testing.T) {
+	comments := []string{
+		"GetUser returns a user",
+		`@Response 404 "User not found with the given ID"`,
+		"@Response 409 Email already registered",
+		`@Param id path string true "User ID"`,
+	}
+
+	info := parseHandlerInfo(comments)
+	if info.ResponseDocs["404"] != "User not found with the given ID" || info.ResponseDocs["409"] != "Email already registered" {
+		t.Fatalf("expected descriptions for 404 and 409, got %+v", info.ResponseDocs)
+	}
+	if info.Summary != "GetUser returns a user" || len(info.Parameters) != 1 {
+		t.Fatalf("@Response must not disturb summary or params, got %+v", info)
+	}
+	if echoInfo := parseEchoHandlerInfo([]string{`@Response 401 "Token expired"`}); echoInfo.ResponseDocs["401"] != "Token expired" {
+		t.Fatalf("expected echo handlers to read @Response, got %+v", echoInfo.ResponseDocs)
+	}
+
+	for _, line := range []string{"@Responses 404 gone", "@Response", "@Response 404", "@Response abc gone", "@Response 42 gone"} {
+		if status, description, ok := responseAnnotation(line); ok {
+			t.Fatalf("%q must not be a response annotation, got %s %q", line, status, description)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+	"sync"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+var (
+	responseEnvelopes      map[string]string
+	responseEnvelopesMutex sync.RWMutex
+)
+
+// SetResponseEnvelopes registers envelope structs, such as APIResponse, by the
+// Go name of their payload field, such as Data, as the process-wide default.
+// Literals of an envelope are documented with the schema of the value
+// assigned to the payload field instead of its declared type, typically
+// interface{}. Config.ResponseEnvelopes overrides them for one docs instance.
+// Analyses are cached per envelopes, so they apply on the next run.
+func SetResponseEnvelopes(envelopes map[string]string) {
+	responseEnvelopesMutex.Lock()
+	defer responseEnvelopesMutex.Unlock()
+	responseEnvelopes = envelopes
+}
+
+// lookupResponseEnvelope returns the payload field of an envelope type in the current analysis
+func lookupResponseEnvelope(typeName string) (string, bool) {
+	return currentAnalysisSettings().responseEnvelope(typeName)
+}
+
+// applyResponseEnvelope replaces the payload property of an envelope literal
+// with the schema of the value it holds, or of the type argument of a generic
+// envelope such as Envelope[User]{}, and marks the envelope schema. The
+// example of a generic envelope without a payload value gets one of the type.
+func applyResponseEnvelope(typeName string, lit *ast.CompositeLit, structType *ast.StructType, typeArgs []ast.Expr, schema interface{}, example map[string]interface{}, ctx *analysisContext, visited map[string]bool) (interface{}, map[string]interface{}) {
+	fieldName, ok := lookupResponseEnvelope(typeName)
+	schemaMap, isMap := schema.(map[string]interface{})
+	if !ok || !isMap || structType == nil || structType.Fields == nil {
+		return schema, example
+	}
+
+	var field *ast.Field
+	for _, candidate := range structType.Fields.List {
+		for _, name := range candidate.Names {
+			if name.Name == fieldName {
+				field = candidate
+			}
+		}
+	}
+	if field == nil {
+		return schema, example
+	}
+	jsonName, skip := resolveJSONFieldName(fieldName, getStructTag(field, "json"))
+	if skip || jsonName == "" {
+		return schema, example
+	}
+
+	var payloadExpr ast.Expr
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok && key.Name == fieldName {
+				payloadExpr = kv.Value
+			}
+		}
+	}
+
+	var payloadSchema interface{}
+	payloadType := ""
+	if payloadExpr != nil {
+		payloadSchema, _ = buildSchemaFromExpr(payloadExpr, ctx, visited)
+		payloadType = envelopePayloadType(payloadExpr, ctx)
+	} else if len(typeArgs) == 1 {
+		var payloadExample interface{}
+		payloadSchema, payloadExample = buildSchemaFromExpr(typeArgs[0], ctx, visited)
+		payloadType = exprToString(typeArgs[0])
+		if example != nil && payloadExample != nil {
+			example[jsonName] = payloadExample
+		}
+	}
+
+	envelope := make(map[string]interface{}, len(schemaMap)+2)
+	for key, value := range schemaMap {
+		envelope[key] = value
+	}
+	if properties, ok := schemaMap["properties"].(map[string]interface{}); ok && payloadSchema != nil {
+		replaced := make(map[string]interface{}, len(properties))
+		for key, value := range properties {
+			replaced[key] = value
+		}
+		replaced[jsonName] = payloadSchema
+		envelope["properties"] = replaced
+	}
+	core.MarkEnvelope(envelope, jsonName, payloadType)
+	return envelope, example
+}
+
+// envelopePayloadType returns the Go type of a payload value as written, e.g.
+// "User" or "[]User", or "" when it cannot be told from the source
+func envelopePayloadType(expr ast.Expr, ctx *analysisContext) string {
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		if e.Type != nil {
+			return exprToString(e.Type)
+		}
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return envelopePayloadType(e.X, ctx)
+		}
+	case *ast.StarExpr:
+		return envelopePayloadType(e.X, ctx)
+	case *ast.Ident:
+		if ctx == nil {
+			return ""
+		}
+		if value, ok := ctx.values[e.Name]; ok && value != nil {
+			if name := envelopePayloadType(value, ctx); name != "" {
+				return name
+			}
+		}
+		if typ, ok := ctx.variables[e.Name]; ok && typ != nil {
+			return strings.TrimPrefix(exprToString(typ), "*")
+		}
+	}
+	return ""
+}
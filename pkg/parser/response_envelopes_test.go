@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+func TestResponseEnvelopePayloads(t *testing.T) {
+	SetResponseEnvelopes(map[string]string{"APIResponse": "Data", "Envelope": "Data"})
+	defer SetResponseEnvelopes(nil)
+
+	dir := t.TempDir()
+	source := `package api
+
+type User struct {
+	ID   int    ` + "`json:\"id\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type APIResponse struct {
+	Success bool        ` + "`json:\"success\"`" + `
+	Data    interface{} ` + "`json:\"data\"`" + `
+}
+
+type Envelope[T any] struct {
+	Data T ` + "`json:\"data\"`" + `
+}
+
+// GetUser returns a user
+func GetUser(c *gin.Context) {
+	user := User{ID: 1, Name: "Ada"}
+	c.JSON(200, APIResponse{Success: true, Data: user})
+}
+
+// ListUsers lists users
+func ListUsers(c *gin.Context) {
+	c.JSON(200, Envelope[[]User]{})
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := analyzeDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, _ := analysis.handlers["getuser"][0].metadata.Responses["200"].Schema.(map[string]interface{})
+	if schema["x-envelope"] != "data" || schema["x-envelope-type"] != "User" {
+		t.Fatalf("expected the envelope to name its payload, got %+v", schema)
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	data, _ := properties["data"].(map[string]interface{})
+	if fields, _ := data["properties"].(map[string]interface{}); fields["name"] == nil {
+		t.Fatalf("expected the payload documented as User, got %+v", data)
+	}
+
+	response := analysis.handlers["listusers"][0].metadata.Responses["200"]
+	schema, _ = response.Schema.(map[string]interface{})
+	properties, _ = schema["properties"].(map[string]interface{})
+	if data, _ := properties["data"].(map[string]interface{}); data["type"] != "array" || schema["x-envelope-type"] != "[]User" {
+		t.Fatalf("expected the type argument of a generic envelope as payload, got %+v", schema)
+	}
+	if example, _ := response.Example.(map[string]interface{}); example == nil {
+		t.Fatalf("expected an example, got %+v", response.Example)
+	} else if items, _ := example["data"].([]interface{}); len(items) == 0 {
+		t.Fatalf("expected a payload example of the type argument, got %+v", example)
+	}
+}
+
+func TestResponseEnvelopesArePerDocsInstance(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+type User struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type APIResponse struct {
+	Data interface{} ` + "`json:\"data\"`" + `
+}
+
+func GetUser(c *gin.Context) {
+	c.JSON(200, APIResponse{Data: User{Name: "Ada"}})
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(resetAnalysisCaches)
+
+	enveloped := newAnalysisSettings(&core.Config{ResponseEnvelopes: []string{"APIResponse.Data"}})
+	plain := newAnalysisSettings(&core.Config{})
+	envelopeOf := func(settings *analysisSettings) interface{} {
+		var analysis *packageAnalysis
+		settings.scoped(func() { analysis = loadPackageAnalysis(dir) })()
+		if analysis == nil {
+			t.Fatal("expected the package analyzed")
+		}
+		schema, _ := analysis.handlers["getuser"][0].metadata.Responses["200"].Schema.(map[string]interface{})
+		return schema["x-envelope-type"]
+	}
+
+	if envelope := envelopeOf(enveloped); envelope != "User" {
+		t.Fatalf("expected the configured envelope unwrapped, got %v", envelope)
+	}
+	if envelope := envelopeOf(plain); envelope != nil {
+		t.Fatalf("expected docs without envelopes unaffected, got %v", envelope)
+	}
+	if _, ok := lookupResponseEnvelope("APIResponse"); ok {
+		t.Fatal("expected the process-wide envelopes unchanged")
+	}
+}
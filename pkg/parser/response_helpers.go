@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// responseCallDetectors recognize the response calls of every supported framework
+var responseCallDetectors = []func(*ast.CallExpr, *analysisContext) (string, ast.Expr, ast.Expr, bool){
+	responseCallInfo,
+	echoResponseCallInfo,
+	fiberResponseCallInfo,
+	stdlibResponseCallInfo,
+	gorillaMuxResponseCallInfo,
+}
+
+// inferResponseHelper recognizes a function that writes a response with the
+// status code or payload it receives as parameters, such as
+// respondJSON(w, status, data), so calls to it document that response.
+// Only direct response calls count, one level of indirection.
+func inferResponseHelper(fn *ast.FuncDecl) *core.ResponseHelper {
+	if fn.Body == nil || fn.Type.Params == nil {
+		return nil
+	}
+	params := make(map[string]int)
+	index := 0
+	for _, field := range fn.Type.Params.List {
+		if len(field.Names) == 0 {
+			index++
+			continue
+		}
+		for _, name := range field.Names {
+			params[name.Name] = index
+			index++
+		}
+	}
+	if len(params) == 0 {
+		return nil
+	}
+
+	ctx := &analysisContext{variables: make(map[string]ast.Expr), values: make(map[string]ast.Expr)}
+	registerFuncParamTypes(fn, ctx)
+	helper := core.ResponseHelper{StatusArg: -1, DataArg: -1}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			// Closures, e.g. handler factories, respond later and elsewhere
+			return false
+		case *ast.CallExpr:
+			for _, detect := range responseCallDetectors {
+				contentType, statusExpr, dataExpr, ok := detect(node, ctx)
+				if !ok {
+					continue
+				}
+				if ident, isIdent := statusExpr.(*ast.Ident); isIdent && helper.StatusArg < 0 {
+					if position, isParam := params[ident.Name]; isParam {
+						helper.StatusArg = position
+					}
+				}
+				if ident, isIdent := dataExpr.(*ast.Ident); isIdent && helper.DataArg < 0 {
+					if position, isParam := params[ident.Name]; isParam {
+						helper.DataArg = position
+						helper.ContentType = contentType
+					}
+				}
+				break
+			}
+		}
+		return true
+	})
+
+	if helper.StatusArg < 0 && helper.DataArg < 0 {
+		return nil
+	}
+	return &helper
+}
+
+// responseHelperCallInfo detects calls to response helpers, registered with
+// core.RegisterResponseHelper or inferred from the functions of the package
+func responseHelperCallInfo(call *ast.CallExpr, ctx *analysisContext) (contentType string, statusExpr ast.Expr, dataExpr ast.Expr, ok bool) {
+	var name string
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		name = fun.Name
+	case *ast.SelectorExpr:
+		name = fun.Sel.Name
+	default:
+		return "", nil, nil, false
+	}
+
+	helper, found := core.LookupResponseHelper(name)
+	if !found && ctx != nil {
+		for _, signature := range ctx.functions[name] {
+			if signature.helper != nil {
+				helper, found = *signature.helper, true
+				break
+			}
+		}
+	}
+	if !found || helper.StatusArg >= len(call.Args) || helper.DataArg >= len(call.Args) {
+		return "", nil, nil, false
+	}
+
+	statusExpr = &ast.BasicLit{Kind: token.INT, Value: "200"}
+	if helper.StatusArg >= 0 {
+		statusExpr = call.Args[helper.StatusArg]
+	}
+	dataExpr = &ast.BasicLit{Kind: token.STRING, Value: `""`}
+	if helper.DataArg >= 0 {
+		dataExpr = call.Args[helper.DataArg]
+	}
+	contentType = helper.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	return contentType, statusExpr, dataExpr, true
+}
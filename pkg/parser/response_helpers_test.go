@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+func TestResponseHelpersRegisteredAndInferred(t *testing.T) {
+	dir := t.TempDir()
+	helpers := `package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+`
+	handlers := `package api
+
+import "net/http"
+
+type User struct {
+	ID string ` + "`json:\"id\"`" + `
+}
+
+// CreateUser creates a user
+func CreateUser(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusCreated, User{ID: "u-1"})
+}
+
+// ListUsers lists users
+func ListUsers(w http.ResponseWriter, r *http.Request) {
+	send(r, []User{}, w, http.StatusPartialContent)
+}
+`
+	for name, source := range map[string]string{"helpers.go": helpers, "handlers.go": handlers} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	core.RegisterResponseHelper("render.send", 3, 1)
+	defer core.UnregisterResponseHelper("send")
+
+	analysis, err := analyzeStdlibDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := analysis.handlers["createuser"][0].metadata.Responses["201"]
+	if properties, _ := created.Schema.(map[string]interface{})["properties"].(map[string]interface{}); properties["id"] == nil {
+		t.Fatalf("expected the inferred respondJSON helper to document a 201 User, got %+v", analysis.handlers["createuser"][0].metadata.Responses)
+	}
+	listed := analysis.handlers["listusers"][0].metadata.Responses["206"]
+	if schema, _ := listed.Schema.(map[string]interface{}); schema["type"] != "array" {
+		t.Fatalf("expected the registered send helper to document a 206 list, got %+v", analysis.handlers["listusers"][0].metadata.Responses)
+	}
+}
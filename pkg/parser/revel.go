@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// revelAdapter reads the routes of a Revel application from conf/routes and
+// analyzes the actions in app/controllers
+type revelAdapter struct {
+	appPath string
+}
+
+// ListRoutes returns the routes of conf/routes that call a controller action
+func (a revelAdapter) ListRoutes() []FrameworkRoute {
+	routes, err := parseRevelRoutes(filepath.Join(a.appPath, "conf", "routes"), filepath.Join(a.appPath, "app", "controllers"))
+	if err != nil {
+		fmt.Printf("⚠️  Could not read Revel routes: %v\n", err)
+	}
+	return routes
+}
+
+// ResolveHandler returns the controller action of a route
+func (a revelAdapter) ResolveHandler(handler interface{}) interface{} {
+	if action, ok := handler.(controllerAction); ok {
+		return action
+	}
+	return nil
+}
+
+// AnalyzeHandler documents a controller action from its source
+func (a revelAdapter) AnalyzeHandler(handler interface{}) core.RouteInfo {
+	return analyzeControllerAction(handler.(controllerAction))
+}
+
+// parseRevelRoutes reads a Revel routes file:
+//
+//	GET     /users/:id          Users.Show
+//	POST    /users              Users.Create
+//	GET     /public/*filepath   Static.Serve("public")
+//
+// Static files, modules, websockets, any-method routes and routes with a
+// dynamic controller or action are skipped.
+func parseRevelRoutes(routesFile, controllersDir string) ([]FrameworkRoute, error) {
+	file, err := os.Open(routesFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var routes []FrameworkRoute
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "module:") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+
+		method := strings.ToUpper(fields[0])
+		action, _, _ := strings.Cut(fields[2], "(")
+		controller, name, found := strings.Cut(action, ".")
+		switch {
+		case !found, strings.HasPrefix(controller, ":"), strings.HasPrefix(name, ":"), controller == "Static":
+			continue
+		case method == "*" || method == "WS":
+			fmt.Printf("⏭️  Skipping route: %s %s\n", method, fields[1])
+			continue
+		}
+
+		routes = append(routes, FrameworkRoute{
+			Method:  method,
+			Path:    fields[1],
+			Handler: controllerAction{framework: revelControllers, dir: controllersDir, controller: controller, method: name, path: convertRevelPath(fields[1])},
+		})
+	}
+	return routes, scanner.Err()
+}
+
+// convertRevelPath writes ":name" and "*name" parameters as "{name}", as the docs do
+func convertRevelPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") || (strings.HasPrefix(part, "*") && len(part) > 1) {
+			parts[i] = "{" + part[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// SetupRevelDocs sets up documentation for the Revel application in appPath,
+// reading its routes from conf/routes and its actions from app/controllers.
+// Revel is not imported, so serve the docs on Config.SeparatePort or put the
+// returned APIDocs, an http.Handler, in front of the Revel server.
+func SetupRevelDocs(appPath string, config *core.Config) *core.APIDocs {
+	return SetupFrameworkDocs(revelAdapter{appPath: appPath}, config)
+}
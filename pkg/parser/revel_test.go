@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRevelAdapter_ReadsRoutesFile(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "conf", "routes"), `# Routes
+module:testrunner
+
+GET     /                       App.Index
+GET     /users/:id              Users.Show
+POST    /users                  Users.Create
+GET     /public/*filepath       Static.Serve("public")
+*       /:controller/:action    :controller.:action
+`)
+	writeTestFile(t, filepath.Join(dir, "app", "controllers", "users.go"), `package controllers
+
+type User struct {
+	ID   int    `+"`json:\"id\"`"+`
+	Name string `+"`json:\"name\"`"+`
+}
+
+type App struct{ *revel.Controller }
+
+func (c App) Index() revel.Result {
+	return c.RenderText("ok")
+}
+
+type Users struct{ *revel.Controller }
+
+// Show returns a user
+func (c Users) Show(id int, fields string) revel.Result {
+	if id == 0 {
+		return c.NotFound("no user %d", id)
+	}
+	return c.RenderJSON(User{ID: id})
+}
+
+// Create adds a user
+func (c Users) Create() revel.Result {
+	var user User
+	c.Params.BindJSON(&user)
+	c.Response.Status = http.StatusCreated
+	return c.RenderJSON(user)
+}
+`)
+
+	adapter := revelAdapter{appPath: dir}
+	routes := adapter.ListRoutes()
+	if len(routes) != 3 {
+		t.Fatalf("expected the three action routes, got %v", routes)
+	}
+
+	show := adapter.AnalyzeHandler(adapter.ResolveHandler(routes[1].Handler))
+	if routes[1].Path != "/users/:id" || show.Summary != "Show returns a user" {
+		t.Fatalf("expected Users.Show, got %s %+v", routes[1].Path, show)
+	}
+	params := make(map[string]string)
+	for _, param := range show.Parameters {
+		params[param.Name] = param.In + ":" + param.Type
+	}
+	if params["id"] != "path:integer" || params["fields"] != "query:string" {
+		t.Errorf("expected action arguments as path and query parameters, got %v", params)
+	}
+	if _, ok := show.Responses["404"]; !ok {
+		t.Errorf("expected NotFound to document a 404, got %+v", show.Responses)
+	}
+	if _, ok := show.Responses["200"]; !ok {
+		t.Errorf("expected RenderJSON to document a 200, got %+v", show.Responses)
+	}
+
+	create := adapter.AnalyzeHandler(adapter.ResolveHandler(routes[2].Handler))
+	if create.RequestBody == nil {
+		t.Error("expected c.Params.BindJSON to document the request body")
+	}
+	if _, ok := create.Responses["201"]; !ok {
+		t.Errorf("expected c.Response.Status to apply to RenderJSON, got %+v", create.Responses)
+	}
+}
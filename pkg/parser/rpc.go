@@ -0,0 +1,304 @@
+package parser
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// RPCProtocol is the HTTP mapping of a protobuf service
+type RPCProtocol string
+
+const (
+	// Connect serves "POST <prefix>/<package>.<Service>/<Method>" with
+	// lowerCamelCase JSON fields, as connect-go does
+	Connect RPCProtocol = "connect"
+	// Twirp serves "POST <prefix>/<package>.<Service>/<Method>" under the
+	// "/twirp" prefix by default, with the original proto field names
+	Twirp RPCProtocol = "twirp"
+)
+
+// RPCService is a Connect or Twirp service to document. Generated code
+// exposes the descriptor, e.g. userv1.File_user_v1_user_proto.Services().ByName("UserService").
+type RPCService struct {
+	Protocol   RPCProtocol
+	Descriptor protoreflect.ServiceDescriptor
+	PathPrefix string // where the handler is mounted; Twirp defaults to "/twirp"
+}
+
+// rpcMethod is the handler of a documented RPC
+type rpcMethod struct {
+	service RPCService
+	method  protoreflect.MethodDescriptor
+}
+
+// rpcAdapter documents the unary methods of Connect and Twirp services
+type rpcAdapter struct {
+	services []RPCService
+}
+
+// NewRPCAdapter returns an adapter that documents the unary methods of
+// services, for SetupFrameworkDocs
+func NewRPCAdapter(services ...RPCService) FrameworkAdapter {
+	return rpcAdapter{services: services}
+}
+
+// AddRPCServices documents the unary methods of services next to the routes
+// of docs, e.g. a Connect API mounted on the same Gin engine
+func AddRPCServices(docs *core.APIDocs, services ...RPCService) {
+	adapter := rpcAdapter{services: services}
+	for _, route := range adapter.ListRoutes() {
+		routeInfo := adapter.AnalyzeHandler(route.Handler)
+		routeInfo.Method = route.Method
+		routeInfo.Path = route.Path
+		docs.AddRouteInfo(routeInfo)
+	}
+}
+
+// ListRoutes returns a POST route per unary method. Streaming methods need
+// the protocol's message envelopes, so they are skipped.
+func (a rpcAdapter) ListRoutes() []FrameworkRoute {
+	var routes []FrameworkRoute
+	for _, service := range a.services {
+		if service.Descriptor == nil {
+			continue
+		}
+		methods := service.Descriptor.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			method := methods.Get(i)
+			path := rpcPath(service, method)
+			if method.IsStreamingClient() || method.IsStreamingServer() {
+				fmt.Printf("⏭️  Skipping streaming RPC: %s\n", path)
+				continue
+			}
+			routes = append(routes, FrameworkRoute{Method: http.MethodPost, Path: path, Handler: rpcMethod{service: service, method: method}})
+		}
+	}
+	return routes
+}
+
+// ResolveHandler returns the method descriptor of a route
+func (a rpcAdapter) ResolveHandler(handler interface{}) interface{} {
+	if method, ok := handler.(rpcMethod); ok {
+		return method
+	}
+	return nil
+}
+
+// AnalyzeHandler documents the request and response messages and the error
+// model of a method
+func (a rpcAdapter) AnalyzeHandler(handler interface{}) core.RouteInfo {
+	rpc := handler.(rpcMethod)
+	service := rpc.service.Descriptor
+	jsonNames := rpc.service.Protocol != Twirp
+
+	comments := rpc.method.ParentFile().SourceLocations().ByDescriptor(rpc.method)
+	description := strings.TrimSpace(comments.LeadingComments)
+	summary, description, _ := strings.Cut(description, "\n")
+
+	requestSchema := rpcMessageSchema(rpc.method.Input(), jsonNames, make(map[protoreflect.FullName]bool))
+	responseSchema := rpcMessageSchema(rpc.method.Output(), jsonNames, make(map[protoreflect.FullName]bool))
+	protoContentType := "application/proto"
+	if rpc.service.Protocol == Twirp {
+		protoContentType = "application/protobuf"
+	}
+
+	return core.RouteInfo{
+		Summary:     strings.TrimSpace(summary),
+		Description: strings.TrimSpace(description),
+		Section:     string(service.Name()),
+		RequestBody: &core.RequestBody{
+			ContentType: "application/json",
+			Schema:      requestSchema,
+			Example:     defaultExampleFromSchema(requestSchema),
+			Required:    true,
+		},
+		Accepts: []string{protoContentType},
+		Responses: map[string]core.Response{
+			"200": {
+				Description: string(rpc.method.Output().Name()),
+				Schema:      responseSchema,
+				Example:     defaultExampleFromSchema(responseSchema),
+				ContentType: "application/json",
+			},
+			"default": rpcErrorResponse(rpc.service.Protocol),
+		},
+	}
+}
+
+// rpcPath returns the route of method, e.g. "/twirp/acme.user.v1.UserService/GetUser"
+func rpcPath(service RPCService, method protoreflect.MethodDescriptor) string {
+	prefix := service.PathPrefix
+	if prefix == "" && service.Protocol == Twirp {
+		prefix = "/twirp"
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + string(service.Descriptor.FullName()) + "/" + string(method.Name())
+}
+
+// rpcMessageSchema builds the JSON schema of a message following the
+// protobuf JSON mapping: 64-bit integers and bytes are strings, enums are
+// their value names and well-known types use their JSON forms
+func rpcMessageSchema(message protoreflect.MessageDescriptor, jsonNames bool, visited map[protoreflect.FullName]bool) map[string]interface{} {
+	if schema, ok := rpcWellKnownSchema(message, jsonNames, visited); ok {
+		return schema
+	}
+	if visited[message.FullName()] {
+		// Recursive messages are documented one level deep
+		return map[string]interface{}{"type": "object"}
+	}
+	visited[message.FullName()] = true
+	defer delete(visited, message.FullName())
+
+	properties := make(map[string]interface{})
+	fields := message.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		name := string(field.Name())
+		if jsonNames {
+			name = field.JSONName()
+		}
+		properties[name] = rpcFieldSchema(field, jsonNames, visited)
+	}
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
+
+// rpcFieldSchema returns the schema of a field, including repeated and map fields
+func rpcFieldSchema(field protoreflect.FieldDescriptor, jsonNames bool, visited map[protoreflect.FullName]bool) map[string]interface{} {
+	if field.IsMap() {
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": rpcValueSchema(field.MapValue(), jsonNames, visited),
+		}
+	}
+	schema := rpcValueSchema(field, jsonNames, visited)
+	if field.IsList() {
+		return map[string]interface{}{"type": "array", "items": schema}
+	}
+	return schema
+}
+
+// rpcValueSchema returns the schema of a single value of a field
+func rpcValueSchema(field protoreflect.FieldDescriptor, jsonNames bool, visited map[protoreflect.FullName]bool) map[string]interface{} {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]interface{}{"type": "string", "format": "int64"}
+	case protoreflect.FloatKind:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case protoreflect.StringKind:
+		return map[string]interface{}{"type": "string"}
+	case protoreflect.BytesKind:
+		return map[string]interface{}{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		values := field.Enum().Values()
+		names := make([]interface{}, 0, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		return map[string]interface{}{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return rpcMessageSchema(field.Message(), jsonNames, visited)
+	}
+	return map[string]interface{}{}
+}
+
+// rpcWellKnownSchema returns the JSON form of the google.protobuf types
+func rpcWellKnownSchema(message protoreflect.MessageDescriptor, jsonNames bool, visited map[protoreflect.FullName]bool) (map[string]interface{}, bool) {
+	switch message.FullName() {
+	case "google.protobuf.Timestamp":
+		return map[string]interface{}{"type": "string", "format": "date-time"}, true
+	case "google.protobuf.Duration":
+		return map[string]interface{}{"type": "string", "example": "1.5s"}, true
+	case "google.protobuf.FieldMask":
+		return map[string]interface{}{"type": "string", "example": "name,email"}, true
+	case "google.protobuf.Struct", "google.protobuf.Empty":
+		return map[string]interface{}{"type": "object"}, true
+	case "google.protobuf.Any":
+		return map[string]interface{}{"type": "object", "properties": map[string]interface{}{"@type": map[string]interface{}{"type": "string"}}}, true
+	case "google.protobuf.ListValue":
+		return map[string]interface{}{"type": "array", "items": map[string]interface{}{}}, true
+	case "google.protobuf.Value":
+		return map[string]interface{}{}, true
+	}
+	if message.ParentFile().Package() == "google.protobuf" && strings.HasSuffix(string(message.Name()), "Value") {
+		// Wrappers such as Int64Value are their value
+		if value := message.Fields().ByName("value"); value != nil {
+			return rpcValueSchema(value, jsonNames, visited), true
+		}
+	}
+	return nil, false
+}
+
+// connectErrorStatus and twirpErrorStatus map the error codes of the
+// protocols to the HTTP status of the error response
+var (
+	connectErrorStatus = map[string]int{
+		"canceled": 499, "unknown": 500, "invalid_argument": 400, "deadline_exceeded": 504,
+		"not_found": 404, "already_exists": 409, "permission_denied": 403, "resource_exhausted": 429,
+		"failed_precondition": 400, "aborted": 409, "out_of_range": 400, "unimplemented": 501,
+		"internal": 500, "unavailable": 503, "data_loss": 500, "unauthenticated": 401,
+	}
+	twirpErrorStatus = map[string]int{
+		"canceled": 408, "unknown": 500, "invalid_argument": 400, "malformed": 400,
+		"deadline_exceeded": 408, "not_found": 404, "bad_route": 404, "already_exists": 409,
+		"permission_denied": 403, "unauthenticated": 401, "resource_exhausted": 429,
+		"failed_precondition": 412, "aborted": 409, "out_of_range": 400, "unimplemented": 501,
+		"internal": 500, "unavailable": 503, "data_loss": 500,
+	}
+)
+
+// rpcErrorResponse documents the error model of a protocol; the HTTP status
+// follows the error code, so it is the default response
+func rpcErrorResponse(protocol RPCProtocol) core.Response {
+	statuses, message := connectErrorStatus, "message"
+	if protocol == Twirp {
+		statuses, message = twirpErrorStatus, "msg"
+	}
+
+	codes := make([]string, 0, len(statuses))
+	for code := range statuses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	lines := make([]string, 0, len(codes))
+	enum := make([]interface{}, 0, len(codes))
+	for _, code := range codes {
+		lines = append(lines, fmt.Sprintf("%s: %d", code, statuses[code]))
+		enum = append(enum, code)
+	}
+
+	properties := map[string]interface{}{
+		"code":  map[string]interface{}{"type": "string", "enum": enum},
+		message: map[string]interface{}{"type": "string"},
+	}
+	example := map[string]interface{}{"code": "not_found", message: "user not found"}
+	if protocol == Twirp {
+		properties["meta"] = map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "string"}}
+	} else {
+		properties["details"] = map[string]interface{}{"type": "array", "items": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"type":  map[string]interface{}{"type": "string"},
+				"value": map[string]interface{}{"type": "string", "format": "byte"},
+			},
+		}}
+	}
+
+	return core.Response{
+		Description: fmt.Sprintf("%s error; the HTTP status follows the code (%s)", strings.ToUpper(string(protocol[:1]))+string(protocol[1:]), strings.Join(lines, ", ")),
+		Schema:      map[string]interface{}{"type": "object", "properties": properties},
+		Example:     example,
+		ContentType: "application/json",
+	}
+}
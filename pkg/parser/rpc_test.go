@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func testUserService(t *testing.T) protoreflect.ServiceDescriptor {
+	t.Helper()
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("acme/user/v1/user.proto"),
+		Package:    proto.String("acme.user.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("GetUserRequest"), Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("user_id"), JsonName: proto.String("userId"), Number: proto.Int32(1), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum()},
+			}},
+			{Name: proto.String("User"), Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("display_name"), JsonName: proto.String("displayName"), Number: proto.Int32(1), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+				{Name: proto.String("created_at"), JsonName: proto.String("createdAt"), Number: proto.Int32(2), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: proto.String(".google.protobuf.Timestamp")},
+				{Name: proto.String("friends"), JsonName: proto.String("friends"), Number: proto.Int32(3), Label: repeated, Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: proto.String(".acme.user.v1.User")},
+			}},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("UserService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("GetUser"), InputType: proto.String(".acme.user.v1.GetUserRequest"), OutputType: proto.String(".acme.user.v1.User")},
+				{Name: proto.String("WatchUsers"), InputType: proto.String(".acme.user.v1.GetUserRequest"), OutputType: proto.String(".acme.user.v1.User"), ServerStreaming: proto.Bool(true)},
+			},
+		}},
+	}
+	files, err := protodesc.NewFiles(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto), file,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	descriptor, err := files.FindDescriptorByName("acme.user.v1.UserService")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return descriptor.(protoreflect.ServiceDescriptor)
+}
+
+func TestRPCAdapter_DocumentsUnaryMethods(t *testing.T) {
+	service := testUserService(t)
+
+	connect := NewRPCAdapter(RPCService{Protocol: Connect, Descriptor: service, PathPrefix: "/api"})
+	routes := connect.ListRoutes()
+	if len(routes) != 1 || routes[0].Method != "POST" || routes[0].Path != "/api/acme.user.v1.UserService/GetUser" {
+		t.Fatalf("expected the unary method only, got %+v", routes)
+	}
+	route := connect.AnalyzeHandler(connect.ResolveHandler(routes[0].Handler))
+	request := route.RequestBody.Schema.(map[string]interface{})["properties"].(map[string]interface{})
+	if userID := request["userId"].(map[string]interface{}); userID["type"] != "string" || userID["format"] != "int64" {
+		t.Errorf("expected int64 fields as JSON strings under their JSON name, got %v", request)
+	}
+	user := route.Responses["200"].Schema.(map[string]interface{})["properties"].(map[string]interface{})
+	if createdAt := user["createdAt"].(map[string]interface{}); createdAt["format"] != "date-time" {
+		t.Errorf("expected timestamps as date-time strings, got %v", createdAt)
+	}
+	if friends := user["friends"].(map[string]interface{}); friends["type"] != "array" {
+		t.Errorf("expected the recursive repeated field as an array, got %v", friends)
+	}
+	if route.Section != "UserService" || route.Responses["default"].Schema.(map[string]interface{})["properties"].(map[string]interface{})["details"] == nil {
+		t.Errorf("expected the Connect error model, got %+v", route)
+	}
+
+	twirp := NewRPCAdapter(RPCService{Protocol: Twirp, Descriptor: service})
+	routes = twirp.ListRoutes()
+	if routes[0].Path != "/twirp/acme.user.v1.UserService/GetUser" {
+		t.Fatalf("expected the default Twirp prefix, got %s", routes[0].Path)
+	}
+	route = twirp.AnalyzeHandler(twirp.ResolveHandler(routes[0].Handler))
+	if _, ok := route.RequestBody.Schema.(map[string]interface{})["properties"].(map[string]interface{})["user_id"]; !ok {
+		t.Errorf("expected Twirp to use the original field names, got %v", route.RequestBody.Schema)
+	}
+
+	docs := core.New(&core.Config{DocsPath: "/docs"})
+	AddRPCServices(docs, RPCService{Protocol: Connect, Descriptor: service})
+	if err := docs.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	if sections := docs.GetDocumentation().Endpoints; len(sections) != 1 || sections[0].Endpoints[0].Path != "/acme.user.v1.UserService/GetUser" {
+		t.Fatalf("expected the RPC next to the other routes, got %+v", sections)
+	}
+}
@@ -0,0 +1,14 @@
+package parser
+
+import "strings"
+
+// sectionAnnotation returns the section ID of a "@Section <id>" comment line,
+// which puts the handler's endpoints into that section
+func sectionAnnotation(line string) (string, bool) {
+	rest, found := strings.CutPrefix(line, "@Section")
+	if !found || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return "", false
+	}
+	section := strings.TrimSpace(rest)
+	return section, section != ""
+}
@@ -0,0 +1,28 @@
+package parser
+
+import "testing"
+
+func TestSectionAnnotationSetsHandlerSection(t *testing.T) {
+	comments := []string{
+		"ListOrders lists the orders of a user",
+		"@Section orders",
+		`@Param id path string true "User ID"`,
+	}
+
+	info := parseHandlerInfo(comments)
+	if info.Section != "orders" {
+		t.Fatalf("expected section orders, got %q", info.Section)
+	}
+	if info.Summary != "ListOrders lists the orders of a user" || len(info.Parameters) != 1 {
+		t.Fatalf("@Section must not disturb summary or params, got %+v", info)
+	}
+	if echoInfo := parseEchoHandlerInfo(comments); echoInfo.Section != "orders" {
+		t.Fatalf("expected echo handlers to read @Section, got %q", echoInfo.Section)
+	}
+
+	for _, line := range []string{"@Sections orders", "@Section", "@Section   "} {
+		if section, ok := sectionAnnotation(line); ok {
+			t.Fatalf("%q must not be a section annotation, got %q", line, section)
+		}
+	}
+}
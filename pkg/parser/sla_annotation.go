@@ -0,0 +1,31 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// slaAnnotation returns the expected response time of a "@SLA <duration>"
+// comment line, e.g. "@SLA 200ms" or "@SLA 1.5s"; a bare number is milliseconds
+func slaAnnotation(line string) (time.Duration, bool) {
+	rest, found := strings.CutPrefix(line, "@SLA")
+	if !found || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return 0, false
+	}
+	value := strings.TrimSpace(rest)
+	if value == "" {
+		return 0, false
+	}
+
+	budget, err := time.ParseDuration(value)
+	if millis, numErr := strconv.ParseFloat(value, 64); numErr == nil {
+		budget, err = time.Duration(millis*float64(time.Millisecond)), nil
+	}
+	if err != nil || budget <= 0 {
+		fmt.Printf("⚠️  Ignoring @SLA %q: expected a duration like 200ms\n", value)
+		return 0, false
+	}
+	return budget, true
+}
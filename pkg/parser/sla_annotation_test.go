@@ -0,0 +1,31 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSLAAnnotationSetsBudget(t *testing.T) {
+	comments := []string{
+		"GetOrder returns an order",
+		"@SLA 200ms",
+		`@Param id path string true "Order ID"`,
+	}
+
+	info := parseHandlerInfo(comments)
+	if info.SLA != 200*time.Millisecond {
+		t.Fatalf("expected a 200ms budget, got %v", info.SLA)
+	}
+	if info.Summary != "GetOrder returns an order" || len(info.Parameters) != 1 {
+		t.Fatalf("@SLA must not disturb summary or params, got %+v", info)
+	}
+	if stdlibInfo := parseStdlibHandlerInfo([]string{"@SLA 250"}); stdlibInfo.SLA != 250*time.Millisecond {
+		t.Fatalf("expected a bare number to be milliseconds, got %v", stdlibInfo.SLA)
+	}
+
+	for _, line := range []string{"@SLAs 200ms", "@SLA", "@SLA fast", "@SLA -1s", "@SLA 0"} {
+		if budget, ok := slaAnnotation(line); ok {
+			t.Fatalf("%q must not be an SLA annotation, got %v", line, budget)
+		}
+	}
+}
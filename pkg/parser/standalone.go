@@ -0,0 +1,24 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// serveDocsSeparately starts the docs server on Config.SeparatePort in the
+// background. It reports whether it did, in which case the docs routes must
+// not be registered on the application router.
+func serveDocsSeparately(docs *core.APIDocs, config *core.Config) bool {
+	if config.SeparatePort == "" {
+		return false
+	}
+
+	go func() {
+		fmt.Printf("📚 Serving documentation separately on %s%s\n", config.SeparatePort, config.DocsPath)
+		if err := docs.ServeStandalone(config.SeparatePort); err != nil {
+			fmt.Printf("❌ Documentation server on %s stopped: %v\n", config.SeparatePort, err)
+		}
+	}()
+	return true
+}
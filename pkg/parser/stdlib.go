@@ -12,27 +12,24 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/idnexacloud/bytedocs-go/pkg/core"
 )
 
-// Global registry for stdlib route tracking
-var (
-	globalStdlibDocs   *core.APIDocs
-	stdlibDocsConfig   *core.Config
-	stdlibDocsMutex    sync.RWMutex
-	stdlibFuncComments map[string][]string
-)
-
-func init() {
-	stdlibFuncComments = make(map[string][]string)
-}
-
 // StdlibHandlerInfo holds parsed comment information for stdlib handlers
 type StdlibHandlerInfo struct {
-	Summary     string
-	Description string
-	Parameters  []core.Parameter
+	Summary      string
+	Description  string
+	Parameters   []core.Parameter
+	Section      string             // from "@Section <id>"
+	ExternalDocs *core.ExternalDocs // from "@Docs <url> [description]"
+	Presets      []core.Preset      // from "@Preset <name> [param=value ...] [json body]"
+	SLA          time.Duration      // from "@SLA <duration>"
+	Pagination   string             // from "@Paginate <style>"
+	Accepts      []string           // from "@Accept <content types>"
+	ResponseDocs map[string]string  // from "@Response <status> <description>"
+	Extensions   core.Extensions    // from "@x-<name> [value]"
 }
 
 // parseStdlibHandlerComments parses Go source files to extract stdlib handler comments
@@ -77,6 +74,44 @@ func parseStdlibHandlerInfo(comments []string) StdlibHandlerInfo {
 	paramRegex := regexp.MustCompile(`@Param\s+(\w+)\s+(\w+)\s+(\w+)\s+(true|false)\s+"([^"]*)"`)
 
 	for _, line := range comments {
+		if section, ok := sectionAnnotation(line); ok {
+			info.Section = section
+			continue
+		}
+		if externalDocs, ok := docsAnnotation(line); ok {
+			info.ExternalDocs = externalDocs
+			continue
+		}
+		if preset, ok := presetAnnotation(line); ok {
+			info.Presets = append(info.Presets, preset)
+			continue
+		}
+		if sla, ok := slaAnnotation(line); ok {
+			info.SLA = sla
+			continue
+		}
+		if style, ok := paginateAnnotation(line); ok {
+			info.Pagination = style
+			continue
+		}
+		if accepts, ok := acceptAnnotation(line); ok {
+			info.Accepts = append(info.Accepts, accepts...)
+			continue
+		}
+		if status, description, ok := responseAnnotation(line); ok {
+			if info.ResponseDocs == nil {
+				info.ResponseDocs = make(map[string]string)
+			}
+			info.ResponseDocs[status] = description
+			continue
+		}
+		if name, value, ok := extensionAnnotation(line); ok {
+			if info.Extensions == nil {
+				info.Extensions = make(core.Extensions)
+			}
+			info.Extensions[name] = value
+			continue
+		}
 		// Parse @Param annotations
 		if matches := paramRegex.FindStringSubmatch(line); len(matches) == 6 {
 			param := core.Parameter{
@@ -187,8 +222,9 @@ func (m *StdlibMuxWrapper) GetRoutes() []StdlibRoute {
 	return routes
 }
 
-// SetupStdlibDocs sets up documentation for a stdlib ServeMux with auto-detection
-func SetupStdlibDocs(mux *StdlibMuxWrapper, config *core.Config) {
+// SetupStdlibDocs sets up documentation for a stdlib ServeMux with auto-detection.
+// The returned APIDocs can be used to customize the generated documentation
+func SetupStdlibDocs(mux *StdlibMuxWrapper, config *core.Config) *core.APIDocs {
 	if config == nil {
 		config = &core.Config{
 			Title:      "API Documentation",
@@ -198,22 +234,16 @@ func SetupStdlibDocs(mux *StdlibMuxWrapper, config *core.Config) {
 		}
 	}
 
-	stdlibDocsMutex.Lock()
-	stdlibDocsConfig = config
-	globalStdlibDocs = core.New(config)
-	stdlibDocsMutex.Unlock()
+	analysis := newAnalysisSettings(config)
 
-	// Set up the docs route that does auto-detection
-	mux.HandleFunc(config.DocsPath+"/", func(w http.ResponseWriter, r *http.Request) {
-		fmt.Printf("🚀 Stdlib docs handler called for path: %s\n", r.URL.Path)
-		stdlibDocsMutex.Lock()
-		defer stdlibDocsMutex.Unlock()
+	docs := core.New(config)
 
-		// Check if we need to detect routes
-		endpointsCount := len(globalStdlibDocs.GetDocumentation().Endpoints)
-		fmt.Printf("🔍 Current endpoints count: %d, AutoDetect: %t\n", endpointsCount, config.AutoDetect)
+	// Refresh analyzes the handlers from source again
+	docs.OnRefresh(analysis.resetCaches)
 
-		if endpointsCount == 0 && config.AutoDetect {
+	// Routes are detected on Warmup or the first docs request, and again on Refresh
+	if config.AutoDetect {
+		docs.SetRouteDetector(analysis.scoped(func() {
 			// Parse handler comments first
 			fmt.Printf("📝 Parsing stdlib handler comments...\n")
 			handlerInfos := parseStdlibHandlerComments("main.go", "examples/stdlib/main.go", "examples/net-http/main.go")
@@ -227,9 +257,7 @@ func SetupStdlibDocs(mux *StdlibMuxWrapper, config *core.Config) {
 				fmt.Printf("📍 Route: %s %s\n", route.Method, route.Path)
 
 				// Skip docs routes and static files
-				if strings.HasPrefix(route.Path, config.DocsPath) ||
-					strings.Contains(route.Path, "/static") ||
-					strings.Contains(route.Path, "/assets") {
+				if strings.HasPrefix(route.Path, config.DocsPath) {
 					fmt.Printf("⏭️  Skipping route: %s\n", route.Path)
 					continue
 				}
@@ -242,14 +270,24 @@ func SetupStdlibDocs(mux *StdlibMuxWrapper, config *core.Config) {
 				metadata := getStdlibHandlerMetadata(route.Handler)
 
 				routeInfo := core.RouteInfo{
-					Method:      route.Method,
-					Path:        route.Path,
-					Handler:     route.Handler,
-					Summary:     handlerInfo.Summary,
-					Description: handlerInfo.Description,
-					Parameters:  handlerInfo.Parameters,
-					RequestBody: metadata.RequestBody,
-					Responses:   metadata.Responses,
+					Method:       route.Method,
+					Path:         route.Path,
+					Handler:      route.Handler,
+					Summary:      handlerInfo.Summary,
+					Description:  handlerInfo.Description,
+					Parameters:   handlerInfo.Parameters,
+					Section:      handlerInfo.Section,
+					ExternalDocs: handlerInfo.ExternalDocs,
+					Presets:      handlerInfo.Presets,
+					SLA:          handlerInfo.SLA,
+					Pagination:   handlerInfo.Pagination,
+					Accepts:      handlerInfo.Accepts,
+					ResponseDocs: handlerInfo.ResponseDocs,
+					Extensions:   handlerInfo.Extensions,
+					RequestBody:  metadata.RequestBody,
+					Responses:    metadata.Responses,
+					Warnings:     metadata.Warnings,
+					Trace:        metadata.Trace,
 				}
 
 				fmt.Printf("✅ Adding stdlib route: %s %s (handler: %s)\n", route.Method, route.Path, handlerName)
@@ -261,22 +299,33 @@ func SetupStdlibDocs(mux *StdlibMuxWrapper, config *core.Config) {
 				}
 
 				// Add to documentation
-				globalStdlibDocs.AddRouteInfo(routeInfo)
+				docs.AddRouteInfo(routeInfo)
 			}
 
 			fmt.Printf("📚 Generating stdlib documentation...\n")
 			// Generate documentation
-			globalStdlibDocs.Generate()
+			docs.Generate()
 
-			fmt.Printf("📊 Final endpoints count: %d\n", len(globalStdlibDocs.GetDocumentation().Endpoints))
-		}
+			fmt.Printf("📊 Final endpoints count: %d\n", len(docs.GetDocumentation().Endpoints))
+		}))
+	}
+
+	if serveDocsSeparately(docs, config) {
+		return docs
+	}
+
+	// Set up the docs route that does auto-detection
+	mux.HandleFunc(config.DocsPath+"/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Printf("🚀 Stdlib docs handler called for path: %s\n", r.URL.Path)
 
 		// Serve documentation
-		globalStdlibDocs.ServeHTTP(w, r)
+		docs.ServeHTTP(w, r)
 	})
+
+	return docs
 }
 
 // SetupStdlibHTTPDocs is an alias for SetupStdlibDocs for net/http compatibility
-func SetupStdlibHTTPDocs(mux *StdlibMuxWrapper, config *core.Config) {
-	SetupStdlibDocs(mux, config)
+func SetupStdlibHTTPDocs(mux *StdlibMuxWrapper, config *core.Config) *core.APIDocs {
+	return SetupStdlibDocs(mux, config)
 }
\ No newline at end of file
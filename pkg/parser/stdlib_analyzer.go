@@ -2,14 +2,9 @@ package parser
 
 import (
 	"go/ast"
-	"go/parser"
 	"go/token"
-	"io/fs"
-	"net/http"
-	"path/filepath"
-	"reflect"
-	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/idnexacloud/bytedocs-go/pkg/core"
 )
@@ -19,120 +14,88 @@ type StdlibHandlerMetadata struct {
 	Info        StdlibHandlerInfo
 	RequestBody *core.RequestBody
 	Responses   map[string]core.Response
+	Warnings    []string `json:"-"` // why no schemas were inferred; not cached
+	Trace       []string `json:"-"` // analysis steps when debugging; not cached
 }
 
+// stdlib handlers are analyzed differently from Gin handlers, so they need their own cache
+var (
+	stdlibAnalysisCache = make(map[string]*packageAnalysis)
+	stdlibAnalysisMutex sync.RWMutex
+)
+
 // getStdlibHandlerMetadata analyzes a stdlib handler function and returns its documentation metadata.
 func getStdlibHandlerMetadata(handler interface{}) StdlibHandlerMetadata {
 	if handler == nil {
 		return StdlibHandlerMetadata{}
 	}
 
-	handlerValue := reflect.ValueOf(handler)
-	if handlerValue.Kind() != reflect.Func {
-		if handlerFunc, ok := handler.(http.HandlerFunc); ok {
-			handlerValue = reflect.ValueOf(handlerFunc)
-		} else {
-			return StdlibHandlerMetadata{}
-		}
-	}
-
-	fn := runtime.FuncForPC(handlerValue.Pointer())
-	if fn == nil {
-		return StdlibHandlerMetadata{}
-	}
-
-	entry := fn.Entry()
-	file, line := fn.FileLine(entry)
-	if file == "" {
-		return StdlibHandlerMetadata{}
+	ref, ok := handlerRefFromFunc(handler)
+	if !ok {
+		return StdlibHandlerMetadata{Warnings: unresolvedHandlerWarnings()}
 	}
 
-	packageMeta := loadStdlibPackageAnalysis(filepath.Dir(file))
+	packageMeta := loadStdlibPackageAnalysis(ref.dir)
 	if packageMeta == nil {
-		return StdlibHandlerMetadata{}
-	}
-
-	runtimeName := fn.Name()
-	funcName, receiverName := parseRuntimeFuncName(runtimeName)
-
-	key := strings.ToLower(funcName)
-	candidates := packageMeta.handlers[key]
-	if len(candidates) == 0 {
-		return StdlibHandlerMetadata{}
+		return StdlibHandlerMetadata{Warnings: packageWarnings(ref)}
 	}
 
-	normalizedFile := filepath.Clean(file)
-	for _, candidate := range candidates {
-		if filepath.Clean(candidate.filePath) != normalizedFile {
-			continue
-		}
-		// Receiver names must match; empty receiver matches standalone functions.
-		if candidate.receiverName != receiverName {
-			continue
-		}
-		if line >= candidate.startLine {
-			return StdlibHandlerMetadata{
-				Info: StdlibHandlerInfo{
-					Summary:     candidate.metadata.Info.Summary,
-					Description: candidate.metadata.Info.Description,
-					Parameters:  candidate.metadata.Info.Parameters,
-				},
-				RequestBody: candidate.metadata.RequestBody,
-				Responses:   candidate.metadata.Responses,
-			}
+	if candidate, ok := selectHandler(packageMeta.handlers[strings.ToLower(ref.funcName)], ref); ok {
+		return StdlibHandlerMetadata{
+			Info: StdlibHandlerInfo{
+				Summary:     candidate.metadata.Info.Summary,
+				Description: candidate.metadata.Info.Description,
+				Parameters:  candidate.metadata.Info.Parameters,
+			},
+			RequestBody: candidate.metadata.RequestBody,
+			Responses:   candidate.metadata.Responses,
+			Trace:       candidate.metadata.Trace,
 		}
 	}
 
-	return StdlibHandlerMetadata{}
+	return StdlibHandlerMetadata{Warnings: handlerNotFoundWarnings(ref)}
 }
 
 // loadStdlibPackageAnalysis parses and caches metadata for all handlers within a directory.
 func loadStdlibPackageAnalysis(dir string) *packageAnalysis {
-	analysisMutex.RLock()
-	if cached, ok := analysisCache[dir]; ok {
-		analysisMutex.RUnlock()
+	key := analysisKey(dir)
+
+	stdlibAnalysisMutex.RLock()
+	if cached, ok := stdlibAnalysisCache[key]; ok {
+		stdlibAnalysisMutex.RUnlock()
 		return cached
 	}
-	analysisMutex.RUnlock()
+	stdlibAnalysisMutex.RUnlock()
 
-	analysisMutex.Lock()
-	defer analysisMutex.Unlock()
+	stdlibAnalysisMutex.Lock()
+	defer stdlibAnalysisMutex.Unlock()
 
-	if cached, ok := analysisCache[dir]; ok {
+	if cached, ok := stdlibAnalysisCache[key]; ok {
 		return cached
 	}
 
-	pkgAnalysis, err := analyzeStdlibDirectory(dir)
+	pkgAnalysis, err := analyzeDirectoryCached("stdlib", dir, analyzeStdlibDirectory)
 	if err != nil {
-		// Silently ignore analysis errors to avoid breaking docs generation.
-		analysisCache[dir] = nil
+		recordAnalysisFailure(dir, err)
+		stdlibAnalysisCache[key] = nil
 		return nil
 	}
 
-	analysisCache[dir] = pkgAnalysis
+	stdlibAnalysisCache[key] = pkgAnalysis
 	return pkgAnalysis
 }
 
 // analyzeStdlibDirectory walks all Go files in a directory to extract stdlib handler metadata.
-func analyzeStdlibDirectory(dir string) (*packageAnalysis, error) {
+func analyzeStdlibDirectory(dir string, only map[string]bool) (*packageAnalysis, error) {
 	fset := token.NewFileSet()
-	pkgs, err := parser.ParseDir(fset, dir, func(info fs.FileInfo) bool {
-		if info.IsDir() {
-			return false
-		}
-		name := info.Name()
-		if !strings.HasSuffix(name, ".go") {
-			return false
-		}
-		return !strings.HasSuffix(name, "_test.go")
-	}, parser.ParseComments)
+	pkgs, err := parseGoDir(fset, dir)
 	if err != nil {
 		return nil, err
 	}
 
 	structs := collectStructDefinitions(pkgs)
 	functions := collectFunctionSignatures(pkgs)
-	handlers := collectStdlibHandlerMetadata(fset, pkgs, structs, functions)
+	handlers := collectStdlibHandlerMetadata(fset, restrictPackageFiles(pkgs, only), structs, functions)
 
 	return &packageAnalysis{
 		handlers:  handlers,
@@ -142,55 +105,50 @@ func analyzeStdlibDirectory(dir string) (*packageAnalysis, error) {
 
 // collectStdlibHandlerMetadata extracts documentation metadata for stdlib function declarations.
 func collectStdlibHandlerMetadata(fset *token.FileSet, pkgs map[string]*ast.Package, structs map[string]*ast.StructType, functions map[string][]functionSignature) map[string][]analyzedHandler {
-	handlers := make(map[string][]analyzedHandler)
-
-	for _, pkg := range pkgs {
-		for _, file := range pkg.Files {
-			for _, decl := range file.Decls {
-				fn, ok := decl.(*ast.FuncDecl)
-				if !ok {
-					continue
-				}
+	return collectPerFile(pkgs, func(file *ast.File, handlers map[string][]analyzedHandler) {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
 
-				// Only analyze functions that look like HTTP handlers (have http.ResponseWriter and *http.Request params)
-				if !isStdlibHTTPHandler(fn) {
-					continue
-				}
+			// Only analyze functions that look like HTTP handlers (have http.ResponseWriter and *http.Request params)
+			if !isStdlibHTTPHandler(fn) {
+				continue
+			}
 
-				var comments []string
-				if fn.Doc != nil {
-					comments = extractCommentsText(fn.Doc.List)
-				}
-				info := parseStdlibHandlerInfo(comments)
-				analysis := analyzeStdlibHandlerDetails(fn, structs, functions)
-
-				pos := fset.Position(fn.Pos())
-				receiverName := receiverTypeName(fn.Recv)
-				funcName := fn.Name.Name
-
-				key := strings.ToLower(funcName)
-				handlerEntry := analyzedHandler{
-					filePath:     pos.Filename,
-					funcName:     funcName,
-					receiverName: receiverName,
-					startLine:    pos.Line,
-					metadata: HandlerMetadata{
-						Info:        HandlerInfo{
-							Summary:     info.Summary,
-							Description: info.Description,
-							Parameters:  info.Parameters,
-						},
-						RequestBody: analysis.RequestBody,
-						Responses:   analysis.Responses,
+			var comments []string
+			if fn.Doc != nil {
+				comments = extractCommentsText(fn.Doc.List)
+			}
+			info := parseStdlibHandlerInfo(comments)
+			analysis := analyzeStdlibHandlerDetails(fn, structs, functions)
+
+			pos := fset.Position(fn.Pos())
+			receiverName := receiverDeclName(fn.Recv)
+			funcName := fn.Name.Name
+
+			key := strings.ToLower(funcName)
+			handlerEntry := analyzedHandler{
+				filePath:     pos.Filename,
+				funcName:     funcName,
+				receiverName: receiverName,
+				startLine:    pos.Line,
+				metadata: HandlerMetadata{
+					Info:        HandlerInfo{
+						Summary:     info.Summary,
+						Description: info.Description,
+						Parameters:  info.Parameters,
 					},
-				}
-
-				handlers[key] = append(handlers[key], handlerEntry)
+					RequestBody: analysis.RequestBody,
+					Responses:   analysis.Responses,
+					Trace:       analysis.Trace,
+				},
 			}
-		}
-	}
 
-	return handlers
+			handlers[key] = append(handlers[key], handlerEntry)
+		}
+	})
 }
 
 // isStdlibHTTPHandler checks if a function is an HTTP handler by looking at its parameters
@@ -235,7 +193,9 @@ func analyzeStdlibHandlerDetails(fn *ast.FuncDecl, structs map[string]*ast.Struc
 		functions: functions,
 		variables: make(map[string]ast.Expr),
 		values:    make(map[string]ast.Expr),
+		trace:     newHandlerTrace(),
 	}
+	registerFuncParamTypes(fn, ctx)
 
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
 		switch node := n.(type) {
@@ -248,19 +208,39 @@ func analyzeStdlibHandlerDetails(fn *ast.FuncDecl, structs map[string]*ast.Struc
 			registerRangeTypes(node, ctx)
 		case *ast.CallExpr:
 			// Detect request body binding for stdlib (json.NewDecoder, etc.)
-			if analysis.RequestBody == nil && isStdlibBindingCall(node) {
-				if resolved := resolveStdlibRequestBody(node, ctx); resolved != nil {
-					analysis.RequestBody = resolved
+			if isStdlibBindingCall(node) {
+				resolved := resolveStdlibRequestBody(node, ctx)
+				ctx.traceBinding(node, resolved)
+				if resolved != nil {
+					analysis.RequestBody = mergeRequestBody(analysis.RequestBody, resolved)
 				}
 			}
 
+			if statusCode, response, ok := redirectResponse(node, ctx); ok {
+				analysis.Responses[statusCode] = response
+				ctx.traceResponse(node, &ast.BasicLit{Kind: token.INT, Value: statusCode}, statusCode, nil, response.Schema)
+			}
+
+			if statusCode, response, ok := streamResponse(node, ctx); ok {
+				analysis.Responses[statusCode] = response
+				ctx.traceResponse(node, &ast.BasicLit{Kind: token.INT, Value: statusCode}, statusCode, nil, response.Schema)
+				return true
+			}
+
 			// Detect response generation calls for stdlib
-			if contentType, statusExpr, dataExpr, ok := stdlibResponseCallInfo(node, ctx); ok {
+			contentType, statusExpr, dataExpr, ok := errorCallInfo(node, ctx)
+			if !ok {
+				contentType, statusExpr, dataExpr, ok = stdlibResponseCallInfo(node, ctx)
+			}
+			if !ok {
+				contentType, statusExpr, dataExpr, ok = responseHelperCallInfo(node, ctx)
+			}
+			if ok {
 				statusCode := extractStatusCode(statusExpr, ctx)
 				if statusCode == "" {
 					statusCode = "200"
 				}
-				payloadExpr := resolveResponsePayloadExpr(dataExpr, ctx)
+				payloadExpr := resolveResponsePayloadExpr(errorPayloadExpr(statusCode, dataExpr, ctx), ctx)
 				schema, example := buildSchemaFromExpr(payloadExpr, ctx, make(map[string]bool))
 				example = normalizeExampleWithSchema(schema, example)
 				if example == nil {
@@ -279,11 +259,13 @@ func analyzeStdlibHandlerDetails(fn *ast.FuncDecl, structs map[string]*ast.Struc
 					response.Description = "Response"
 				}
 				analysis.Responses[statusCode] = response
+				ctx.traceResponse(node, statusExpr, statusCode, payloadExpr, schema)
 			}
 		}
 		return true
 	})
 
+	analysis.Trace = ctx.trace.finish(analysis.RequestBody != nil, len(analysis.Responses))
 	return analysis
 }
 
@@ -317,7 +299,10 @@ func resolveStdlibRequestBody(call *ast.CallExpr, ctx *analysisContext) *core.Re
 				body := buildRequestBodyFromExpr(typeExpr, ctx)
 				if body != nil {
 					body.Required = true
-					body.ContentType = "application/json"
+					body.ContentType = decoderContentType(call)
+					if body.ContentType == "" {
+						body.ContentType = "application/json"
+					}
 					return body
 				}
 			}
@@ -328,6 +313,10 @@ func resolveStdlibRequestBody(call *ast.CallExpr, ctx *analysisContext) *core.Re
 
 // stdlibResponseCallInfo detects stdlib response calls like json.NewEncoder().Encode() or writeJSON()
 func stdlibResponseCallInfo(call *ast.CallExpr, ctx *analysisContext) (contentType string, statusExpr ast.Expr, dataExpr ast.Expr, ok bool) {
+	if contentType, statusExpr, dataExpr, ok := templateCallInfo(call, ctx); ok {
+		return contentType, statusExpr, dataExpr, true
+	}
+
 	// First check for direct function calls like writeJSON(w, status, data)
 	if ident, ok := call.Fun.(*ast.Ident); ok {
 		switch ident.Name {
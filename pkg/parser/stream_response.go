@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// streamResponse documents file and stream responses as binary bodies marked
+// streaming: io.Copy(w, src), http.ServeFile, http.ServeContent, gin's c.File,
+// c.FileAttachment and c.DataFromReader, echo's c.File, c.Attachment,
+// c.Inline and c.Stream, and fiber's c.SendFile, c.Download and c.SendStream.
+// The content type is detected from the file name when it is a string literal.
+func streamResponse(call *ast.CallExpr, ctx *analysisContext) (string, core.Response, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", core.Response{}, false
+	}
+	name := sel.Sel.Name
+	qualifier := ""
+	if ident, ok := sel.X.(*ast.Ident); ok {
+		qualifier = ident.Name
+	}
+
+	var statusExpr ast.Expr
+	contentType := ""
+	args := call.Args
+	switch {
+	case qualifier == "io" && (name == "Copy" || name == "CopyN" || name == "CopyBuffer") && len(args) >= 2:
+		if !isResponseWriterExpr(args[0], ctx) {
+			return "", core.Response{}, false
+		}
+	case qualifier == "http" && name == "ServeFile" && len(args) >= 3:
+		contentType = mimeTypeOf(args[2])
+	case qualifier == "http" && name == "ServeContent" && len(args) >= 5:
+		contentType = mimeTypeOf(args[2])
+	case !isFrameworkContextExpr(sel.X, ctx):
+		// Storage clients and the like have Download and File methods too
+		return "", core.Response{}, false
+	case (name == "File" || name == "SendFile") && len(args) >= 1:
+		contentType = mimeTypeOf(args[0])
+	case (name == "FileAttachment" || name == "Attachment" || name == "Inline") && len(args) >= 2:
+		if contentType = mimeTypeOf(args[1]); contentType == "" {
+			contentType = mimeTypeOf(args[0])
+		}
+	case name == "Download" && len(args) >= 1:
+		contentType = mimeTypeOf(args[len(args)-1])
+	case name == "DataFromReader" && len(args) >= 4:
+		statusExpr = args[0]
+		contentType = resolveContentType(args[2], ctx)
+	case name == "Stream" && len(args) == 3:
+		// echo's c.Stream(code, contentType, reader); gin's c.Stream takes a callback
+		statusExpr = args[0]
+		contentType = resolveContentType(args[1], ctx)
+	case name == "SendStream" && len(args) >= 1:
+	default:
+		return "", core.Response{}, false
+	}
+
+	if chained, ok := sel.X.(*ast.CallExpr); ok && statusExpr == nil && len(chained.Args) == 1 {
+		// fiber's c.Status(code).SendStream(reader)
+		if status, ok := chained.Fun.(*ast.SelectorExpr); ok && status.Sel.Name == "Status" {
+			statusExpr = chained.Args[0]
+		}
+	}
+	statusCode := ""
+	if statusExpr != nil {
+		statusCode = extractStatusCode(statusExpr, ctx)
+	}
+	if statusCode == "" {
+		statusCode = "200"
+	}
+	if !strings.Contains(contentType, "/") {
+		// Unknown, or a constant such as echo.MIMEOctetStream that was not resolved
+		contentType = "application/octet-stream"
+	}
+	description := statusTextFromCode(statusCode)
+	if description == "" {
+		description = "Response"
+	}
+	return statusCode, core.Response{
+		Description: description,
+		Schema:      map[string]interface{}{"type": "string", "format": "binary"},
+		ContentType: contentType,
+		Streaming:   true,
+	}, true
+}
+
+// mimeTypeOf returns the media type of a file name given as a string literal,
+// without parameters such as charset
+func mimeTypeOf(expr ast.Expr) string {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	name, _ := stringLiteral(lit)
+	mediaType := mime.TypeByExtension(path.Ext(name))
+	if index := strings.Index(mediaType, ";"); index >= 0 {
+		mediaType = strings.TrimSpace(mediaType[:index])
+	}
+	return mediaType
+}
+
+// isFrameworkContextExpr reports whether expr is a handler context such as a
+// *gin.Context, echo.Context or *fiber.Ctx parameter, or fiber's c.Status(code)
+func isFrameworkContextExpr(expr ast.Expr, ctx *analysisContext) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if ctx == nil {
+			return false
+		}
+		typ, ok := ctx.variables[e.Name]
+		if !ok {
+			return false
+		}
+		typeName := strings.TrimPrefix(exprToString(typ), "*")
+		return strings.HasSuffix(typeName, ".Context") || strings.HasSuffix(typeName, ".Ctx")
+	case *ast.CallExpr:
+		if sel, ok := e.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Status" {
+			return isFrameworkContextExpr(sel.X, ctx)
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStreamResponsesDocumentedAsBinary(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+import (
+	"io"
+	"net/http"
+	"os"
+)
+
+// Report serves the monthly report
+func Report(w http.ResponseWriter, r *http.Request) {
+	http.ServeFile(w, r, "reports/monthly.pdf")
+}
+
+// Export streams an export
+func Export(w http.ResponseWriter, r *http.Request) {
+	file, _ := os.Open("export.bin")
+	io.Copy(w, file)
+}
+
+// Invoice downloads an invoice
+func Invoice(c *gin.Context) {
+	c.FileAttachment("invoices/latest.pdf", "invoice.pdf")
+}
+
+// Backup downloads a backup from storage
+func Backup(c *gin.Context) {
+	storage.Download("backup.zip")
+	c.JSON(200, nil)
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdlibAnalysis, err := analyzeStdlibDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := stdlibAnalysis.handlers["report"][0].metadata.Responses["200"]
+	if !report.Streaming || report.ContentType != "application/pdf" {
+		t.Fatalf("expected http.ServeFile to stream a PDF, got %+v", report)
+	}
+	if export := stdlibAnalysis.handlers["export"][0].metadata.Responses["200"]; !export.Streaming || export.ContentType != "application/octet-stream" {
+		t.Fatalf("expected io.Copy to the response to stream binary data, got %+v", export)
+	}
+
+	ginAnalysis, err := analyzeDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invoice := ginAnalysis.handlers["invoice"][0].metadata.Responses["200"]
+	if !invoice.Streaming || invoice.ContentType != "application/pdf" {
+		t.Fatalf("expected c.FileAttachment to stream a PDF, got %+v", invoice)
+	}
+	if backup := ginAnalysis.handlers["backup"][0].metadata.Responses["200"]; backup.Streaming {
+		t.Fatalf("expected Download on a non-context receiver to be ignored, got %+v", backup)
+	}
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStructDocCommentsBecomeSchemaDescriptions(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+// CreateOrder is the payload to place an order.
+// Prices are taken from the catalog.
+type CreateOrder struct {
+	Items []OrderItem ` + "`json:\"items\"`" + `
+}
+
+type (
+	// OrderItem is one product of an order
+	OrderItem struct {
+		SKU string ` + "`json:\"sku\"`" + ` // catalog SKU
+	}
+)
+
+// Order is a placed order
+type Order struct {
+	ID string ` + "`json:\"id\"`" + `
+}
+
+// PlaceOrder places an order
+func PlaceOrder(c *gin.Context) {
+	var body CreateOrder
+	c.ShouldBindJSON(&body)
+	c.JSON(201, Order{ID: "o-1"})
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := analyzeDirectory(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata := analysis.handlers["placeorder"][0].metadata
+	schema, _ := metadata.RequestBody.Schema.(map[string]interface{})
+	if schema["description"] != "CreateOrder is the payload to place an order. Prices are taken from the catalog." {
+		t.Fatalf("expected the struct doc comment as the schema description, got %+v", schema)
+	}
+	items, _ := schema["properties"].(map[string]interface{})["items"].(map[string]interface{})
+	item, _ := items["items"].(map[string]interface{})
+	if item["description"] != "OrderItem is one product of an order" {
+		t.Fatalf("expected the doc comment of a grouped type spec, got %+v", item)
+	}
+	response, _ := metadata.Responses["201"].Schema.(map[string]interface{})
+	if response["description"] != "Order is a placed order" {
+		t.Fatalf("expected the response schema to carry its doc comment, got %+v", metadata.Responses["201"])
+	}
+}
@@ -0,0 +1,274 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"image"
+	_ "image/gif"  // register decoders for image dimensions
+	_ "image/jpeg" // of binary previews
+	_ "image/png"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Body types reported in TestResponse.BodyType
+const (
+	bodyTypeJSON   = "json"
+	bodyTypeXML    = "xml"
+	bodyTypeHTML   = "html"
+	bodyTypeText   = "text"
+	bodyTypeBinary = "binary"
+	bodyTypeEmpty  = "empty"
+)
+
+// maxPreviewLength caps the readable preview generated for HTML bodies
+const maxPreviewLength = 2000
+
+// maxDataURLBytes caps images returned inline as a data URL preview
+const maxDataURLBytes = 256 << 10
+
+var (
+	pdfPagePattern      = regexp.MustCompile(`/Type\s*/Page[^s]`)
+	htmlTitlePattern    = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	htmlNonTextPattern  = regexp.MustCompile(`(?is)<(script|style|noscript|head)[^>]*>.*?</(script|style|noscript|head)>`)
+	htmlCommentPattern  = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlBlockTagPattern = regexp.MustCompile(`(?i)<\s*/?\s*(br|p|div|li|tr|h[1-6]|pre|section|article|table)[^>]*>`)
+	htmlTagPattern      = regexp.MustCompile(`(?s)<[^>]+>`)
+	blankLinesPattern   = regexp.MustCompile(`\n\s*\n+`)
+	inlineSpacePattern  = regexp.MustCompile(`[ \t\r\f\v]+`)
+)
+
+// renderedBody describes how a response body should be presented in the UI
+type renderedBody struct {
+	BodyType  string
+	Body      string
+	Title     string
+	Preview   string
+	MediaType string
+	DataURL   string
+}
+
+// renderResponseBody detects the body type from the Content-Type header, falling
+// back to content sniffing, and produces a readable rendering for it. size is
+// the full body size; body holds only its start when complete is false.
+func renderResponseBody(contentType string, body []byte, size int64, complete bool) renderedBody {
+	whole := body
+	if !complete {
+		body = trimPartialRune(body)
+	}
+	rendered := renderedBody{
+		BodyType: detectBodyType(contentType, body),
+		Body:     string(body),
+	}
+
+	switch rendered.BodyType {
+	case bodyTypeJSON:
+		var jsonData interface{}
+		if err := json.Unmarshal(body, &jsonData); err == nil {
+			if prettyJSON, err := json.MarshalIndent(jsonData, "", "  "); err == nil {
+				rendered.Body = string(prettyJSON)
+			}
+		}
+	case bodyTypeXML:
+		if pretty, err := prettyPrintXML(body); err == nil {
+			rendered.Body = pretty
+		}
+	case bodyTypeHTML:
+		rendered.Title, rendered.Preview = htmlPreview(string(body))
+	case bodyTypeBinary:
+		rendered.Body = ""
+		rendered.MediaType, rendered.Preview, rendered.DataURL = binaryPreview(contentType, whole, size, complete)
+	}
+
+	return rendered
+}
+
+// detectBodyType classifies a response body as json, xml, html, text, binary or empty
+func detectBodyType(contentType string, body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return bodyTypeEmpty
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "" {
+		mediaType = ""
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return bodyTypeJSON
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		return bodyTypeHTML
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return bodyTypeXML
+	case strings.HasPrefix(mediaType, "text/"):
+		return bodyTypeText
+	}
+
+	// Missing or generic content type: sniff the body
+	trimmed := bytes.TrimSpace(body)
+	if json.Valid(trimmed) {
+		return bodyTypeJSON
+	}
+	sniffed := http.DetectContentType(body)
+	switch {
+	case strings.HasPrefix(sniffed, "text/html"):
+		return bodyTypeHTML
+	case strings.HasPrefix(sniffed, "text/xml"):
+		return bodyTypeXML
+	case strings.HasPrefix(sniffed, "text/"):
+		return bodyTypeText
+	}
+	if utf8.Valid(body) {
+		return bodyTypeText
+	}
+	return bodyTypeBinary
+}
+
+// prettyPrintXML re-indents an XML document
+func prettyPrintXML(body []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+
+	var buf bytes.Buffer
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		// Whitespace-only character data would fight the indentation
+		if charData, ok := token.(xml.CharData); ok && len(bytes.TrimSpace(charData)) == 0 {
+			continue
+		}
+		if err := encoder.EncodeToken(xml.CopyToken(token)); err != nil {
+			return "", err
+		}
+		// The encoder only indents elements, so start the root on its own line
+		if _, ok := token.(xml.ProcInst); ok {
+			if err := encoder.Flush(); err != nil {
+				return "", err
+			}
+			buf.WriteByte('\n')
+		}
+	}
+	if err := encoder.Flush(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// htmlPreview returns the page title and a plain-text rendering of an HTML page
+func htmlPreview(page string) (string, string) {
+	title := ""
+	if match := htmlTitlePattern.FindStringSubmatch(page); len(match) > 1 {
+		title = strings.TrimSpace(html.UnescapeString(match[1]))
+	}
+
+	text := htmlNonTextPattern.ReplaceAllString(page, "")
+	text = htmlCommentPattern.ReplaceAllString(text, "")
+	text = htmlBlockTagPattern.ReplaceAllString(text, "\n")
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = inlineSpacePattern.ReplaceAllString(text, " ")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text = blankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	text = strings.TrimSpace(text)
+
+	if len(text) > maxPreviewLength {
+		cut := maxPreviewLength
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = text[:cut] + "…"
+	}
+
+	return title, text
+}
+
+// binaryPreview describes a binary body by its media type, size and, for
+// images, dimensions. Small images also get a data URL to show inline. When
+// body is incomplete and size no larger, the full size is unknown.
+func binaryPreview(contentType string, body []byte, size int64, complete bool) (string, string, string) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "" || mediaType == "application/octet-stream" {
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(body))
+	}
+
+	kind := mediaType
+	details := ""
+	switch {
+	case mediaType == "application/pdf":
+		kind = "PDF document"
+		if pages := len(pdfPagePattern.FindAll(body, -1)); complete && pages > 0 {
+			details = fmt.Sprintf(", %d pages", pages)
+			if pages == 1 {
+				details = ", 1 page"
+			}
+		}
+	case strings.HasPrefix(mediaType, "image/"):
+		kind = strings.ToUpper(strings.TrimPrefix(mediaType, "image/")) + " image"
+		if config, _, err := image.DecodeConfig(bytes.NewReader(body)); err == nil {
+			details = fmt.Sprintf(", %d×%d px", config.Width, config.Height)
+		}
+	case strings.HasPrefix(mediaType, "audio/"), strings.HasPrefix(mediaType, "video/"):
+		kind = strings.Replace(mediaType, "/", " (", 1) + ")"
+	case mediaType == "application/zip" || mediaType == "application/x-gzip" || mediaType == "application/gzip":
+		kind = "archive (" + mediaType + ")"
+	}
+
+	sizeText := formatBytes(size)
+	if !complete && size <= int64(len(body)) {
+		sizeText = "over " + sizeText
+	}
+	preview := fmt.Sprintf("Binary response: %s%s, %s", kind, details, sizeText)
+
+	dataURL := ""
+	if complete && strings.HasPrefix(mediaType, "image/") && inlineMediaTypes[mediaType] && len(body) <= maxDataURLBytes {
+		dataURL = "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(body)
+	}
+	return mediaType, preview, dataURL
+}
+
+// formatBytes formats a size as B, KB or MB
+func formatBytes(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", size)
+}
+
+// trimPartialRune drops an incomplete UTF-8 sequence left at the end of a
+// truncated body so text is not mistaken for binary
+func trimPartialRune(body []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(body); i++ {
+		if utf8.RuneStart(body[len(body)-i]) {
+			if !utf8.FullRune(body[len(body)-i:]) {
+				return body[:len(body)-i]
+			}
+			break
+		}
+	}
+	return body
+}
@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectBodyType_ContentTypesAndCharsets(t *testing.T) {
+	for _, tc := range []struct {
+		contentType string
+		body        string
+		want        string
+	}{
+		{"application/json; charset=utf-8", `{"ok":true}`, bodyTypeJSON},
+		{"application/vnd.api+json", `{"data":[]}`, bodyTypeJSON},
+		{"text/html; charset=ISO-8859-1", "<p>hi</p>", bodyTypeHTML},
+		{"application/xhtml+xml", "<html/>", bodyTypeHTML},
+		{"application/xml; charset=utf-8", "<a/>", bodyTypeXML},
+		{"text/xml", "<a/>", bodyTypeXML},
+		{"application/atom+xml", "<feed/>", bodyTypeXML},
+		{"text/csv; charset=utf-8", "a,b\n1,2", bodyTypeText},
+		{"text/plain; charset=windows-1252", "caf\xe9", bodyTypeText},
+		{"invalid;;type", `[1,2]`, bodyTypeJSON},
+		{"", `<?xml version="1.0"?><a/>`, bodyTypeXML},
+		{"application/octet-stream", "héllo", bodyTypeText},
+		{"", "caf\xe9 \x00\x01", bodyTypeBinary},
+		{"application/json", "", bodyTypeEmpty},
+	} {
+		if got := detectBodyType(tc.contentType, []byte(tc.body)); got != tc.want {
+			t.Errorf("detect %q %q: expected %s, got %s", tc.contentType, tc.body, tc.want, got)
+		}
+	}
+}
+
+func TestRenderResponseBody_PrettyPrintsJSONAndXML(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"json", "application/json", `{"a":[1,2]}`, "{\n  \"a\": [\n    1,\n    2\n  ]\n}"},
+		{"invalid json kept", "application/json", `{"a":`, `{"a":`},
+		{"xml", "application/xml", "<a><b x=\"1\">text</b>\n  <c/></a>", "<a>\n  <b x=\"1\">text</b>\n  <c></c>\n</a>"},
+		{"xml declaration", "text/xml", `<?xml version="1.0"?><a><b/></a>`, "<?xml version=\"1.0\"?>\n<a>\n  <b></b>\n</a>"},
+		{"unclosed tags repaired", "application/xml", "<a><b></a>", "<a>\n  <b></b>\n</a>"},
+		{"invalid xml kept", "application/xml", "<a><b", "<a><b"},
+	} {
+		if got := renderResponseBody(tc.contentType, []byte(tc.body), int64(len(tc.body)), true).Body; got != tc.want {
+			t.Errorf("%s: expected\n%s\ngot\n%s", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestRenderResponseBody_HTMLPreview(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		page    string
+		title   string
+		preview string
+	}{
+		{
+			name:    "title and text",
+			page:    "<html><head><title> Not &amp; Found </title><style>p{}</style></head><body><h1>404</h1><p>Page <b>missing</b></p></body></html>",
+			title:   "Not & Found",
+			preview: "404\n\nPage missing",
+		},
+		{
+			name:    "scripts and comments dropped",
+			page:    "<script>alert(1)</script><!-- hidden --><div>visible</div><noscript>js</noscript>",
+			preview: "visible",
+		},
+		{
+			name:    "line breaks and spaces",
+			page:    "one<br>two\t\t  three<li>four</li>",
+			preview: "one\ntwo three\nfour",
+		},
+	} {
+		rendered := renderResponseBody("text/html; charset=utf-8", []byte(tc.page), int64(len(tc.page)), true)
+		if rendered.BodyType != bodyTypeHTML || rendered.Title != tc.title || rendered.Preview != tc.preview || rendered.Body != tc.page {
+			t.Errorf("%s: expected title %q and preview %q with the page kept, got %+v", tc.name, tc.title, tc.preview, rendered)
+		}
+	}
+
+	// Long previews are cut on a rune boundary
+	long := "<p>" + strings.Repeat("é", maxPreviewLength) + "</p>"
+	_, preview := htmlPreview(long)
+	if !strings.HasSuffix(preview, "…") || len(preview) > maxPreviewLength+len("…") || !strings.HasPrefix(preview, "é") {
+		t.Fatalf("expected the preview cut at %d bytes, got %d bytes", maxPreviewLength, len(preview))
+	}
+}
+
+func TestBinaryPreview_KindsAndSizes(t *testing.T) {
+	for _, tc := range []struct {
+		contentType string
+		body        string
+		size        int64
+		complete    bool
+		mediaType   string
+		preview     string
+	}{
+		{"audio/mpeg", "\xff\xfb\x90", 3 << 20, true, "audio/mpeg", "Binary response: audio (mpeg), 3.0 MB"},
+		{"video/mp4; codecs=avc1", "\x00\x00", 1536, true, "video/mp4", "Binary response: video (mp4), 1.5 KB"},
+		{"application/zip", "PK\x03\x04", 10, true, "application/zip", "Binary response: archive (application/zip), 10 B"},
+		{"", "\x1f\x8b\x08\x00", 4, false, "application/x-gzip", "Binary response: archive (application/x-gzip), over 4 B"},
+		{"application/x-custom", "\x00\x01", 2, true, "application/x-custom", "Binary response: application/x-custom, 2 B"},
+	} {
+		mediaType, preview, dataURL := binaryPreview(tc.contentType, []byte(tc.body), tc.size, tc.complete)
+		if mediaType != tc.mediaType || preview != tc.preview || dataURL != "" {
+			t.Errorf("%q: expected %s %q, got %s %q %q", tc.contentType, tc.mediaType, tc.preview, mediaType, preview, dataURL)
+		}
+	}
+}
+
+func TestTrimPartialRune(t *testing.T) {
+	for _, tc := range []struct {
+		body string
+		want string
+	}{
+		{"abc", "abc"},
+		{"ab\xc3", "ab"},
+		{"ab\xe2\x82", "ab"},
+		{"ab\xe2\x82\xac", "ab\xe2\x82\xac"},
+		{"", ""},
+	} {
+		if got := string(trimPartialRune([]byte(tc.body))); got != tc.want {
+			t.Errorf("trim %q: expected %q, got %q", tc.body, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package ui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// cookieJarCookie identifies the cookie jar of a docs visitor
+	cookieJarCookie = "bytedocs_cookie_jar"
+	// cookieJarTTL is how long an unused cookie jar is kept
+	cookieJarTTL  = time.Hour
+	maxCookieJars = 1000
+)
+
+// JarCookie is a cookie stored in a test cookie jar
+type JarCookie struct {
+	Name     string     `json:"name"`
+	Value    string     `json:"value"`
+	Domain   string     `json:"domain"`
+	Path     string     `json:"path"`
+	Expires  *time.Time `json:"expires,omitempty"` // nil for session cookies
+	Secure   bool       `json:"secure,omitempty"`
+	HttpOnly bool       `json:"http_only,omitempty"`
+}
+
+// testCookieJar keeps the cookies of test requests like a browser does, and
+// remembers their attributes so they can be listed
+type testCookieJar struct {
+	jar *cookiejar.Jar
+
+	mu       sync.Mutex
+	received map[string]JarCookie // by domain, path and name
+	lastUsed time.Time
+}
+
+func newTestCookieJar() *testCookieJar {
+	jar, _ := cookiejar.New(nil)
+	return &testCookieJar{jar: jar, received: make(map[string]JarCookie), lastUsed: time.Now()}
+}
+
+// SetCookies implements http.CookieJar
+func (j *testCookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.jar.SetCookies(u, cookies)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.lastUsed = time.Now()
+	for _, cookie := range cookies {
+		stored := JarCookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   strings.TrimPrefix(cookie.Domain, "."),
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HttpOnly: cookie.HttpOnly,
+		}
+		if stored.Domain == "" {
+			stored.Domain = u.Hostname()
+		}
+		if stored.Path == "" || !strings.HasPrefix(stored.Path, "/") {
+			stored.Path = defaultCookiePath(u.Path)
+		}
+		switch {
+		case cookie.MaxAge > 0:
+			expires := time.Now().Add(time.Duration(cookie.MaxAge) * time.Second)
+			stored.Expires = &expires
+		case !cookie.Expires.IsZero():
+			expires := cookie.Expires
+			stored.Expires = &expires
+		}
+		j.received[stored.Domain+";"+stored.Path+";"+stored.Name] = stored
+	}
+}
+
+// Cookies implements http.CookieJar
+func (j *testCookieJar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	j.lastUsed = time.Now()
+	j.mu.Unlock()
+	return j.jar.Cookies(u)
+}
+
+// list returns the cookies the jar still holds, deleted and expired ones
+// left out, by domain, path and name
+func (j *testCookieJar) list() []JarCookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	cookies := make([]JarCookie, 0, len(j.received))
+	for key, stored := range j.received {
+		scheme := "http"
+		if stored.Secure {
+			scheme = "https"
+		}
+		held := false
+		for _, cookie := range j.jar.Cookies(&url.URL{Scheme: scheme, Host: stored.Domain, Path: stored.Path}) {
+			if cookie.Name == stored.Name && cookie.Value == stored.Value {
+				held = true
+				break
+			}
+		}
+		if !held {
+			delete(j.received, key)
+			continue
+		}
+		cookies = append(cookies, stored)
+	}
+	sort.Slice(cookies, func(a, b int) bool {
+		if cookies[a].Domain != cookies[b].Domain {
+			return cookies[a].Domain < cookies[b].Domain
+		}
+		if cookies[a].Path != cookies[b].Path {
+			return cookies[a].Path < cookies[b].Path
+		}
+		return cookies[a].Name < cookies[b].Name
+	})
+	return cookies
+}
+
+// defaultCookiePath is the path of a cookie set without one (RFC 6265 5.1.4)
+func defaultCookiePath(requestPath string) string {
+	i := strings.LastIndex(requestPath, "/")
+	if i <= 0 {
+		return "/"
+	}
+	return requestPath[:i]
+}
+
+// cookieJar returns the cookie jar of the docs visitor of r. With create, a
+// visitor without one gets a new jar and the cookie identifying it.
+func (h *Handler) cookieJar(w http.ResponseWriter, r *http.Request, create bool) *testCookieJar {
+	h.jarsMu.Lock()
+	defer h.jarsMu.Unlock()
+
+	now := time.Now()
+	var oldestID string
+	var oldest time.Time
+	for id, jar := range h.jars {
+		jar.mu.Lock()
+		lastUsed := jar.lastUsed
+		jar.mu.Unlock()
+		if now.Sub(lastUsed) > cookieJarTTL {
+			delete(h.jars, id)
+			continue
+		}
+		if oldestID == "" || lastUsed.Before(oldest) {
+			oldestID, oldest = id, lastUsed
+		}
+	}
+
+	if cookie, err := r.Cookie(cookieJarCookie); err == nil {
+		if jar, ok := h.jars[cookie.Value]; ok {
+			return jar
+		}
+	}
+	if !create {
+		return nil
+	}
+
+	id, err := randomID()
+	if err != nil {
+		return nil
+	}
+	if len(h.jars) >= maxCookieJars {
+		delete(h.jars, oldestID)
+	}
+	jar := newTestCookieJar()
+	h.jars[id] = jar
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieJarCookie,
+		Value:    id,
+		Path:     h.cookieJarPath(),
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	return jar
+}
+
+func (h *Handler) cookieJarPath() string {
+	if h.config.DocsPath == "" {
+		return "/"
+	}
+	return h.config.DocsPath
+}
+
+// serveCookies lists the cookies in the visitor's jar, or empties it on DELETE
+func (h *Handler) serveCookies(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		cookies := make([]JarCookie, 0)
+		if jar := h.cookieJar(w, r, false); jar != nil {
+			cookies = jar.list()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		json.NewEncoder(w).Encode(map[string]interface{}{"cookies": cookies})
+	case http.MethodDelete:
+		if cookie, err := r.Cookie(cookieJarCookie); err == nil {
+			h.jarsMu.Lock()
+			delete(h.jars, cookie.Value)
+			h.jarsMu.Unlock()
+		}
+		http.SetCookie(w, &http.Cookie{Name: cookieJarCookie, Path: h.cookieJarPath(), MaxAge: -1, HttpOnly: true, SameSite: http.SameSiteStrictMode})
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// tryIt posts a test request using the cookie jar, sending cookies as the
+// docs visitor's browser would
+func tryIt(t *testing.T, handler *Handler, target string, cookies ...*http.Cookie) (TestResponse, *httptest.ResponseRecorder) {
+	t.Helper()
+	payload, _ := json.Marshal(TestRequest{Method: "GET", URL: target, UseCookieJar: true})
+	request := httptest.NewRequest(http.MethodPost, "/docs/test", bytes.NewReader(payload))
+	for _, cookie := range cookies {
+		request.AddCookie(cookie)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	var response TestResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode %s: %v", recorder.Body.String(), err)
+	}
+	return response, recorder
+}
+
+// jarCookie returns the cookie identifying the visitor's jar set on recorder
+func jarCookie(recorder *httptest.ResponseRecorder) *http.Cookie {
+	for _, cookie := range recorder.Result().Cookies() {
+		if cookie.Name == cookieJarCookie {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestCookieJar_KeepsSessionsUntilCleared(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/", HttpOnly: true})
+			http.SetCookie(w, &http.Cookie{Name: "theme", Value: "dark", Path: "/", MaxAge: 3600})
+		case "/logout":
+			http.SetCookie(w, &http.Cookie{Name: "session", Path: "/", MaxAge: -1})
+		default:
+			session, err := r.Cookie("session")
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(session.Value))
+		}
+	}))
+	defer server.Close()
+
+	handler := NewHandler(nil, &core.Config{DocsPath: "/docs"})
+	_, login := tryIt(t, handler, server.URL+"/login")
+	visitor := jarCookie(login)
+	if visitor == nil || visitor.Path != "/docs" || !visitor.HttpOnly {
+		t.Fatalf("expected a cookie identifying the jar, got %v", login.Result().Cookies())
+	}
+
+	if me, _ := tryIt(t, handler, server.URL+"/me", visitor); me.StatusCode != http.StatusOK || me.Body != "abc" {
+		t.Fatalf("expected the session cookie sent back, got %d %q", me.StatusCode, me.Body)
+	}
+	if me, _ := tryIt(t, handler, server.URL+"/me"); me.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected other visitors to have their own jar, got %d", me.StatusCode)
+	}
+
+	listing := httptest.NewRequest(http.MethodGet, "/docs/test/cookies", nil)
+	listing.AddCookie(visitor)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, listing)
+	var listed struct {
+		Cookies []JarCookie `json:"cookies"`
+	}
+	json.Unmarshal(recorder.Body.Bytes(), &listed)
+	if len(listed.Cookies) != 2 || listed.Cookies[0].Name != "session" || !listed.Cookies[0].HttpOnly || listed.Cookies[0].Expires != nil ||
+		listed.Cookies[1].Name != "theme" || listed.Cookies[1].Expires == nil {
+		t.Fatalf("expected the session and theme cookies listed, got %+v", listed.Cookies)
+	}
+
+	// Cookies deleted by the API leave the listing
+	tryIt(t, handler, server.URL+"/logout", visitor)
+	if cookies := handler.cookieJar(nil, listing, false).list(); len(cookies) != 1 || cookies[0].Name != "theme" {
+		t.Fatalf("expected the deleted session cookie dropped, got %+v", cookies)
+	}
+
+	clearing := httptest.NewRequest(http.MethodDelete, "/docs/test/cookies", nil)
+	clearing.AddCookie(visitor)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, clearing)
+	if cleared := jarCookie(recorder); recorder.Code != http.StatusNoContent || cleared == nil || cleared.MaxAge >= 0 {
+		t.Fatalf("expected the jar cleared and its cookie removed, got %d %v", recorder.Code, recorder.Result().Cookies())
+	}
+	if jar := handler.cookieJar(nil, listing, false); jar != nil {
+		t.Fatal("expected the cleared jar gone")
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, listing)
+	if recorder.Body.String() != "{\"cookies\":[]}\n" {
+		t.Fatalf("expected an empty listing after clearing, got %s", recorder.Body.String())
+	}
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/docs/test/cookies", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected PUT not allowed, got %d", recorder.Code)
+	}
+}
+
+func TestCookieJar_ExpiresAndEvictsJars(t *testing.T) {
+	handler := NewHandler(nil, &core.Config{DocsPath: "/docs"})
+	visit := func(id string) *http.Request {
+		request := httptest.NewRequest(http.MethodGet, "/docs/test/cookies", nil)
+		request.AddCookie(&http.Cookie{Name: cookieJarCookie, Value: id})
+		return request
+	}
+
+	// Jars unused for longer than cookieJarTTL are dropped
+	stale := newTestCookieJar()
+	stale.lastUsed = time.Now().Add(-cookieJarTTL - time.Minute)
+	fresh := newTestCookieJar()
+	fresh.lastUsed = time.Now().Add(-cookieJarTTL + time.Minute)
+	handler.jars["stale"], handler.jars["fresh"] = stale, fresh
+	if jar := handler.cookieJar(nil, visit("stale"), false); jar != nil {
+		t.Fatal("expected the stale jar expired")
+	}
+	if jar := handler.cookieJar(nil, visit("fresh"), false); jar != fresh || len(handler.jars) != 1 {
+		t.Fatalf("expected only the fresh jar kept, got %d jars", len(handler.jars))
+	}
+
+	// Using a jar keeps it alive
+	fresh.SetCookies(&url.URL{Scheme: "http", Host: "api.example.com", Path: "/"}, []*http.Cookie{{Name: "a", Value: "1"}})
+	if time.Since(fresh.lastUsed) > time.Minute {
+		t.Fatal("expected setting cookies to refresh the jar")
+	}
+
+	// At maxCookieJars the least recently used jar makes room for a new one
+	for i := len(handler.jars); i < maxCookieJars; i++ {
+		jar := newTestCookieJar()
+		jar.lastUsed = time.Now().Add(-time.Duration(i) * time.Second)
+		handler.jars["jar"+strconv.Itoa(i)] = jar
+	}
+	oldest := "jar" + strconv.Itoa(maxCookieJars-1)
+	recorder := httptest.NewRecorder()
+	created := handler.cookieJar(recorder, visit("unknown"), true)
+	if created == nil || len(handler.jars) != maxCookieJars {
+		t.Fatalf("expected a new jar within the cap, got %d jars", len(handler.jars))
+	}
+	if _, ok := handler.jars[oldest]; ok {
+		t.Fatal("expected the least recently used jar evicted")
+	}
+	if id := jarCookie(recorder); id == nil || handler.jars[id.Value] != created {
+		t.Fatalf("expected the new jar identified by its cookie, got %v", recorder.Result().Cookies())
+	}
+}
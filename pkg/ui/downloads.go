@@ -0,0 +1,246 @@
+package ui
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+const (
+	defaultMaxRequestBytes  = 10 << 20
+	defaultMaxResponseBytes = 1 << 20
+	defaultMaxDownloadBytes = 50 << 20
+	defaultMaxKeptBytes     = 512 << 20
+
+	// keepTTL is how long kept response bodies and uploads are available
+	keepTTL = 10 * time.Minute
+)
+
+// bodyLimits are the Try It body sizes of the UI config, defaults applied
+type bodyLimits struct {
+	request  int64
+	response int64
+	download int64 // 0 disables downloads
+	kept     int64 // of each temporary file store
+}
+
+func newBodyLimits(uiConfig *core.UIConfig) bodyLimits {
+	limits := bodyLimits{
+		request:  defaultMaxRequestBytes,
+		response: defaultMaxResponseBytes,
+		download: defaultMaxDownloadBytes,
+		kept:     defaultMaxKeptBytes,
+	}
+	if uiConfig == nil {
+		return limits
+	}
+	if uiConfig.MaxRequestBytes > 0 {
+		limits.request = uiConfig.MaxRequestBytes
+	}
+	if uiConfig.MaxResponseBytes > 0 {
+		limits.response = uiConfig.MaxResponseBytes
+	}
+	if uiConfig.MaxDownloadBytes > 0 {
+		limits.download = uiConfig.MaxDownloadBytes
+	} else if uiConfig.MaxDownloadBytes < 0 {
+		limits.download = 0
+	}
+	if uiConfig.MaxKeptBytes > 0 {
+		limits.kept = uiConfig.MaxKeptBytes
+	}
+	if limits.download > 0 && limits.download < limits.response {
+		limits.download = limits.response
+	}
+	// A store must hold at least one file of the largest size it accepts
+	if limits.kept < limits.download {
+		limits.kept = limits.download
+	}
+	if limits.kept < limits.request {
+		limits.kept = limits.request
+	}
+	return limits
+}
+
+// readLimited reads up to limit+1 bytes of r; more than limit bytes means r
+// holds more than its inline part
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	return io.ReadAll(io.LimitReader(r, limit+1))
+}
+
+// keptFile is a body saved to a temporary file
+type keptFile struct {
+	file        string
+	name        string
+	contentType string
+	size        int64
+	expires     time.Time
+}
+
+// tempFileStore keeps bodies in temporary files for a while: truncated and
+// binary response bodies for download, and files uploaded for test requests.
+// At most maxBytes are kept; the oldest files make room for new ones.
+type tempFileStore struct {
+	mu       sync.Mutex
+	items    map[string]*keptFile
+	total    int64
+	maxBytes int64
+}
+
+func newTempFileStore(maxBytes int64) *tempFileStore {
+	return &tempFileStore{items: make(map[string]*keptFile), maxBytes: maxBytes}
+}
+
+// keep saves head followed by the rest of the body, up to limit bytes. It
+// returns the file ID, or "" when the body is larger than limit.
+func (s *tempFileStore) keep(name, contentType string, head []byte, rest io.Reader, limit int64) (string, int64, error) {
+	s.purge()
+
+	file, err := os.CreateTemp("", "bytedocs-response-*")
+	if err != nil {
+		return "", 0, err
+	}
+	written, err := file.Write(head)
+	size := int64(written)
+	if err == nil && rest != nil {
+		var copied int64
+		copied, err = io.Copy(file, io.LimitReader(rest, limit-size+1))
+		size += copied
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || size > limit || size > s.maxBytes {
+		os.Remove(file.Name())
+		return "", size, err
+	}
+
+	id, err := randomID()
+	if err != nil {
+		os.Remove(file.Name())
+		return "", size, err
+	}
+	s.mu.Lock()
+	for s.total+size > s.maxBytes {
+		s.removeOldest()
+	}
+	s.items[id] = &keptFile{file: file.Name(), name: name, contentType: contentType, size: size, expires: time.Now().Add(keepTTL)}
+	s.total += size
+	s.mu.Unlock()
+	return id, size, nil
+}
+
+func (s *tempFileStore) get(id string) (*keptFile, bool) {
+	s.purge()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kept, ok := s.items[id]
+	return kept, ok
+}
+
+// purge removes expired files
+func (s *tempFileStore) purge() {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, kept := range s.items {
+		if now.After(kept.expires) {
+			s.remove(id)
+		}
+	}
+}
+
+// removeOldest removes the file kept first; s.mu must be held
+func (s *tempFileStore) removeOldest() {
+	oldest := ""
+	for id, kept := range s.items {
+		if oldest == "" || kept.expires.Before(s.items[oldest].expires) {
+			oldest = id
+		}
+	}
+	s.remove(oldest)
+}
+
+// remove deletes a kept file; s.mu must be held
+func (s *tempFileStore) remove(id string) {
+	kept := s.items[id]
+	os.Remove(kept.file)
+	s.total -= kept.size
+	delete(s.items, id)
+}
+
+func randomID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// inlineMediaTypes may be shown in the browser with ?inline=1; anything else,
+// notably HTML and SVG, is always served as an attachment so it cannot run
+// scripts on the docs origin
+var inlineMediaTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+	"audio/mpeg":      true,
+	"audio/wav":       true,
+	"audio/ogg":       true,
+	"video/mp4":       true,
+	"video/webm":      true,
+}
+
+// serveDownload serves a kept response body
+func (h *Handler) serveDownload(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	download, ok := h.downloads.get(id)
+	if !ok {
+		http.Error(w, "Download not found or expired", http.StatusNotFound)
+		return
+	}
+	file, err := os.Open(download.file)
+	if err != nil {
+		http.Error(w, "Download not found or expired", http.StatusNotFound)
+		return
+	}
+	defer file.Close()
+
+	contentType := download.contentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	disposition := "attachment"
+	if r.URL.Query().Get("inline") == "1" && inlineMediaTypes[mediaType] {
+		disposition = "inline"
+	}
+	filename := "response"
+	if extensions, err := mime.ExtensionsByType(mediaType); err == nil && len(extensions) > 0 {
+		filename += extensions[0]
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(download.size, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, filename))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	w.Header().Set("Cache-Control", "no-store")
+	if r.Method == http.MethodHead {
+		return
+	}
+	io.Copy(w, file)
+}
@@ -0,0 +1,231 @@
+package ui
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// pngImage encodes a blank PNG of the given size
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// fetch serves a GET of target from handler
+func fetch(handler *Handler, target string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	return recorder
+}
+
+func TestExecuteTestRequest_TruncatesOverMaxResponseBytes(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, strings.Repeat("a", len(r.URL.Path)-1))
+	}))
+	defer server.Close()
+
+	handler := NewHandler(nil, &core.Config{DocsPath: "/docs", UIConfig: &core.UIConfig{MaxResponseBytes: 16}})
+
+	// /<16 chars> answers exactly the limit
+	atLimit := handler.executeTestRequest(TestRequest{Method: "GET", URL: server.URL + "/" + strings.Repeat("x", 16)}, nil)
+	if atLimit.Error != "" || atLimit.Truncated || atLimit.DownloadURL != "" || atLimit.Body != strings.Repeat("a", 16) || atLimit.ResponseSize != 16 {
+		t.Fatalf("expected a body at the limit returned whole, got %+v", atLimit)
+	}
+
+	overLimit := handler.executeTestRequest(TestRequest{Method: "GET", URL: server.URL + "/" + strings.Repeat("x", 17)}, nil)
+	if !overLimit.Truncated || overLimit.Body != strings.Repeat("a", 16) || overLimit.ResponseSize != 17 {
+		t.Fatalf("expected a body over the limit truncated to it, got %+v", overLimit)
+	}
+	if !strings.HasPrefix(overLimit.DownloadURL, "/docs/test/download/") {
+		t.Fatalf("expected a download link for the full body, got %q", overLimit.DownloadURL)
+	}
+	download := fetch(handler, overLimit.DownloadURL)
+	if download.Code != http.StatusOK || download.Body.String() != strings.Repeat("a", 17) {
+		t.Fatalf("expected the full body downloaded, got %d %q", download.Code, download.Body.String())
+	}
+	if disposition := download.Header().Get("Content-Disposition"); !strings.HasPrefix(disposition, "attachment;") {
+		t.Fatalf("expected text downloads served as attachments, got %q", disposition)
+	}
+
+	// Bodies over MaxDownloadBytes are truncated without a download
+	handler = NewHandler(nil, &core.Config{DocsPath: "/docs", UIConfig: &core.UIConfig{MaxResponseBytes: 16, MaxDownloadBytes: 20}})
+	tooLarge := handler.executeTestRequest(TestRequest{Method: "GET", URL: server.URL + "/" + strings.Repeat("x", 21)}, nil)
+	if !tooLarge.Truncated || tooLarge.DownloadURL != "" || len(tooLarge.Body) != 16 {
+		t.Fatalf("expected no download over MaxDownloadBytes, got %+v", tooLarge)
+	}
+}
+
+func TestServeDownload_ExpiresKeptBodies(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	handler := NewHandler(nil, &core.Config{DocsPath: "/docs"})
+	id, size, err := handler.downloads.keep("", "application/pdf", []byte("%PDF-1.4"), strings.NewReader(" rest"), 64)
+	if err != nil || id == "" || size != 13 {
+		t.Fatalf("expected the body kept, got %q %d %v", id, size, err)
+	}
+
+	inline := fetch(handler, handler.downloadURL(id)+"?inline=1")
+	if inline.Code != http.StatusOK || inline.Body.String() != "%PDF-1.4 rest" {
+		t.Fatalf("expected the kept body served, got %d %q", inline.Code, inline.Body.String())
+	}
+	if disposition := inline.Header().Get("Content-Disposition"); !strings.HasPrefix(disposition, "inline;") {
+		t.Fatalf("expected PDFs viewable inline, got %q", disposition)
+	}
+
+	kept, _ := handler.downloads.get(id)
+	handler.downloads.mu.Lock()
+	kept.expires = time.Now().Add(-time.Second)
+	handler.downloads.mu.Unlock()
+
+	if expired := fetch(handler, handler.downloadURL(id)); expired.Code != http.StatusNotFound {
+		t.Fatalf("expected an expired download gone, got %d", expired.Code)
+	}
+	if _, err := os.Stat(kept.file); !os.IsNotExist(err) {
+		t.Fatalf("expected the expired file removed, got %v", err)
+	}
+	if missing := fetch(handler, handler.downloadURL("unknown")); missing.Code != http.StatusNotFound {
+		t.Fatalf("expected unknown downloads not found, got %d", missing.Code)
+	}
+
+	// Bodies larger than the limit are not kept at all
+	if id, size, err := handler.downloads.keep("", "text/plain", []byte("head"), strings.NewReader("tail"), 6); id != "" || size != 7 || err != nil {
+		t.Fatalf("expected a body over the limit rejected, got %q %d %v", id, size, err)
+	}
+}
+
+func TestTempFileStore_RemovesOldestOverMaxKeptBytes(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	store := newTempFileStore(10)
+	keep := func(body string) string {
+		t.Helper()
+		id, _, err := store.keep("", "text/plain", []byte(body), nil, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	first := keep("aaaa")
+	second := keep("bbbb")
+	store.mu.Lock()
+	store.items[first].expires = store.items[second].expires.Add(-time.Second)
+	store.mu.Unlock()
+	firstFile := store.items[first].file
+
+	third := keep("cccc")
+	if _, ok := store.get(first); ok {
+		t.Fatal("expected the oldest file removed to make room")
+	}
+	if _, err := os.Stat(firstFile); !os.IsNotExist(err) {
+		t.Fatalf("expected the removed file deleted from disk, got %v", err)
+	}
+	if _, ok := store.get(second); !ok {
+		t.Fatal("expected the newer file kept")
+	}
+	if _, ok := store.get(third); !ok || store.total != 8 {
+		t.Fatalf("expected 8 bytes kept, got %d", store.total)
+	}
+
+	// A body larger than the whole store is refused
+	if id, _, err := store.keep("", "text/plain", []byte("0123456789"), strings.NewReader("x"), 20); id != "" || err != nil || store.total != 8 {
+		t.Fatalf("expected a body over the store size refused, got %q %v with %d bytes kept", id, err, store.total)
+	}
+
+	// Expiry releases the space too
+	store.mu.Lock()
+	for _, kept := range store.items {
+		kept.expires = time.Now().Add(-time.Second)
+	}
+	store.mu.Unlock()
+	store.purge()
+	if store.total != 0 || len(store.items) != 0 {
+		t.Fatalf("expected expired files released, got %d bytes in %d files", store.total, len(store.items))
+	}
+
+	limits := newBodyLimits(&core.UIConfig{MaxKeptBytes: 1 << 20})
+	if limits.kept != defaultMaxDownloadBytes {
+		t.Fatalf("expected the store to hold at least one file of the largest size, got %d", limits.kept)
+	}
+}
+
+func TestRenderResponseBody_DetectsBinary(t *testing.T) {
+	picture := pngImage(t, 3, 2)
+
+	// Generic content types are sniffed
+	rendered := renderResponseBody("application/octet-stream", picture, int64(len(picture)), true)
+	if rendered.BodyType != bodyTypeBinary || rendered.Body != "" || rendered.MediaType != "image/png" {
+		t.Fatalf("expected a binary PNG, got %+v", rendered)
+	}
+	if !strings.Contains(rendered.Preview, "PNG image, 3×2 px") || !strings.HasPrefix(rendered.DataURL, "data:image/png;base64,") {
+		t.Fatalf("expected a PNG preview with a data URL, got %+v", rendered)
+	}
+
+	// Incomplete bodies report a lower bound and get no data URL
+	partial := renderResponseBody("image/png", picture[:20], 20, false)
+	if !strings.HasSuffix(partial.Preview, "over 20 B") || partial.DataURL != "" {
+		t.Fatalf("expected a partial image previewed without data URL, got %+v", partial)
+	}
+
+	pdf := []byte("%PDF-1.4\n1 0 obj << /Type /Page >>\n2 0 obj << /Type /Page >>\n\x00\xff")
+	if rendered := renderResponseBody("", pdf, 2048, true); rendered.BodyType != bodyTypeBinary || rendered.Preview != "Binary response: PDF document, 2 pages, 2.0 KB" {
+		t.Fatalf("expected a two-page PDF, got %+v", rendered)
+	}
+
+	for _, tc := range []struct {
+		contentType string
+		body        []byte
+		want        string
+	}{
+		{"", []byte(`{"ok":true}`), bodyTypeJSON},
+		{"application/problem+json", []byte(`{}`), bodyTypeJSON},
+		{"", []byte("<!DOCTYPE html><p>hi</p>"), bodyTypeHTML},
+		{"", []byte("plain words"), bodyTypeText},
+		{"application/octet-stream", []byte{0x00, 0xfe, 0xff, 0x01}, bodyTypeBinary},
+		{"text/plain", []byte("  \n"), bodyTypeEmpty},
+	} {
+		if got := detectBodyType(tc.contentType, tc.body); got != tc.want {
+			t.Errorf("detect %q %q: expected %s, got %s", tc.contentType, tc.body, tc.want, got)
+		}
+	}
+
+	// A rune cut at the truncation boundary must not turn text into binary
+	text := []byte("héllo wörld")
+	if rendered := renderResponseBody("", text[:9], int64(len(text)), false); rendered.BodyType != bodyTypeText || rendered.Body != "héllo w" {
+		t.Fatalf("expected truncated text kept as text, got %+v", rendered)
+	}
+}
+
+func TestExecuteTestRequest_OffersBinaryBodiesForDownload(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	picture := pngImage(t, 1, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(picture)
+	}))
+	defer server.Close()
+
+	handler := NewHandler(nil, &core.Config{DocsPath: "/docs"})
+	response := handler.executeTestRequest(TestRequest{Method: "GET", URL: server.URL}, nil)
+	if response.BodyType != bodyTypeBinary || response.Body != "" || response.Truncated || response.DownloadURL == "" {
+		t.Fatalf("expected a binary body offered for download, got %+v", response)
+	}
+	download := fetch(handler, response.DownloadURL+"?inline=1")
+	if !bytes.Equal(download.Body.Bytes(), picture) || download.Header().Get("Content-Security-Policy") != "sandbox" {
+		t.Fatalf("expected the sandboxed image downloaded, got %d bytes %v", download.Body.Len(), download.Header())
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/idnexacloud/bytedocs-go/pkg/ai"
 	"github.com/idnexacloud/bytedocs-go/pkg/core"
@@ -22,6 +23,18 @@ type Handler struct {
 	config    *core.Config
 	template  *template.Template
 	llmClient ai.Client
+	limiter   *executionLimiter
+	limits    bodyLimits
+	downloads *tempFileStore
+	uploads   *tempFileStore
+
+	// transports of the test client settings, built on first use
+	transportsMu sync.Mutex
+	transports   map[*core.TestClientConfig]*http.Transport
+
+	// cookie jars of docs visitors by the ID in their cookieJarCookie
+	jarsMu sync.Mutex
+	jars   map[string]*testCookieJar
 }
 
 // NewHandler creates a new UI handler
@@ -44,11 +57,18 @@ func NewHandler(docs *core.APIDocs, config *core.Config) *Handler {
 		}
 	}
 
+	limits := newBodyLimits(config.UIConfig)
 	return &Handler{
-		docs:      docs,
-		config:    config,
-		template:  tmpl,
-		llmClient: llmClient,
+		docs:       docs,
+		config:     config,
+		template:   tmpl,
+		llmClient:  llmClient,
+		limiter:    newExecutionLimiter(config.UIConfig),
+		limits:     limits,
+		downloads:  newTempFileStore(limits.kept),
+		uploads:    newTempFileStore(limits.kept),
+		transports: make(map[*core.TestClientConfig]*http.Transport),
+		jars:       make(map[string]*testCookieJar),
 	}
 }
 
@@ -73,6 +93,12 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.serveScenarioExecution(w, r)
 	case strings.HasPrefix(path, "/scenarios"):
 		h.serveScenarios(w, r)
+	case strings.HasPrefix(path, "/test/download/"):
+		h.serveDownload(w, r, strings.TrimPrefix(path, "/test/download/"))
+	case path == "/test/cookies":
+		h.serveCookies(w, r)
+	case path == "/test/upload":
+		h.serveUpload(w, r)
 	case path == "/test":
 		h.serveTestEndpoint(w, r)
 	case strings.HasPrefix(path, "/static/"):
@@ -115,7 +141,7 @@ func (h *Handler) serveIndex(w http.ResponseWriter, r *http.Request) {
 	// Inject the API data script before closing </body>
 	injection := fmt.Sprintf(`<script>window.__API_DOCS_DATA__ = %s;</script>
     <script>window.__API_DOCS_CONFIG__ = %s;</script>
-</body>`, string(docsJSON), mustMarshalJSON(h.config))
+</body>`, string(docsJSON), mustMarshalJSON(h.config.Public()))
 
 	htmlContent = strings.Replace(htmlContent, "</body>", injection, 1)
 
@@ -127,18 +153,18 @@ func (h *Handler) serveIndex(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) serveEmbeddedTemplate(w http.ResponseWriter, r *http.Request) {
 	docs := h.docs.GetDocumentation()
 	docsJSON, _ := json.Marshal(docs)
-	configJSON, _ := json.Marshal(h.config)
+	configJSON, _ := json.Marshal(h.config.Public())
 
 	data := struct {
 		Title        string
 		DocsDataJSON string
 		ConfigJSON   string
-		Config       *core.Config
+		Config       *core.PublicConfig
 	}{
 		Title:        h.config.Title,
 		DocsDataJSON: string(docsJSON),
 		ConfigJSON:   string(configJSON),
-		Config:       h.config,
+		Config:       h.config.Public(),
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+const (
+	defaultMaxConcurrentRequests = 10
+	defaultMaxQueuedRequests     = 50
+	defaultQueueTimeout          = 30 * time.Second
+)
+
+// errExecutionBusy is returned when no execution slot became available
+var errExecutionBusy = errors.New("too many concurrent test executions, please retry later")
+
+// executionLimiter caps concurrent outbound Try It / scenario executions.
+// Callers beyond the cap wait in a bounded queue; once the queue is full or
+// the wait times out they are rejected so the caller can answer with 503.
+type executionLimiter struct {
+	slots        chan struct{}
+	waiting      int64
+	maxQueued    int64
+	queueTimeout time.Duration
+}
+
+// newExecutionLimiter builds a limiter from the UI config. It returns nil,
+// meaning unlimited, when MaxConcurrentRequests is negative.
+func newExecutionLimiter(uiConfig *core.UIConfig) *executionLimiter {
+	maxConcurrent := defaultMaxConcurrentRequests
+	maxQueued := defaultMaxQueuedRequests
+	queueTimeout := defaultQueueTimeout
+
+	if uiConfig != nil {
+		if uiConfig.MaxConcurrentRequests < 0 {
+			return nil
+		}
+		if uiConfig.MaxConcurrentRequests > 0 {
+			maxConcurrent = uiConfig.MaxConcurrentRequests
+		}
+		if uiConfig.MaxQueuedRequests > 0 {
+			maxQueued = uiConfig.MaxQueuedRequests
+		}
+		if uiConfig.QueueTimeout > 0 {
+			queueTimeout = time.Duration(uiConfig.QueueTimeout) * time.Second
+		}
+	}
+
+	return &executionLimiter{
+		slots:        make(chan struct{}, maxConcurrent),
+		maxQueued:    int64(maxQueued),
+		queueTimeout: queueTimeout,
+	}
+}
+
+// acquire blocks until an execution slot is free and returns its release func
+func (l *executionLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	// Fast path: a slot is free right away
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	default:
+	}
+
+	if atomic.AddInt64(&l.waiting, 1) > l.maxQueued {
+		atomic.AddInt64(&l.waiting, -1)
+		return nil, errExecutionBusy
+	}
+	defer atomic.AddInt64(&l.waiting, -1)
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	case <-timer.C:
+		return nil, errExecutionBusy
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *executionLimiter) release() {
+	<-l.slots
+}
+
+// retryAfter is the Retry-After hint sent with 503 responses, in seconds
+func (l *executionLimiter) retryAfter() int {
+	if l == nil || l.queueTimeout < time.Second {
+		return 1
+	}
+	return int(l.queueTimeout / time.Second)
+}
+
+// writeBusyResponse answers a rejected execution with 503 and a Retry-After header
+func (h *Handler) writeBusyResponse(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", strconv.Itoa(h.limiter.retryAfter()))
+	w.WriteHeader(http.StatusServiceUnavailable)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":       err.Error(),
+		"retry_after": h.limiter.retryAfter(),
+	})
+}
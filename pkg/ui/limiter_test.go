@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+func TestExecutionLimiter_CapsAndQueuesExecutions(t *testing.T) {
+	limiter := newExecutionLimiter(&core.UIConfig{MaxConcurrentRequests: 2, MaxQueuedRequests: 1, QueueTimeout: 5})
+	first, err := limiter.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := limiter.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The third execution waits in the queue and runs once a slot is released
+	acquired := make(chan error)
+	go func() {
+		release, err := limiter.acquire(context.Background())
+		if err == nil {
+			release()
+		}
+		acquired <- err
+	}()
+	for deadline := time.Now().Add(time.Second); atomic.LoadInt64(&limiter.waiting) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the third execution queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The queue holds one, so a fourth is turned away at once
+	if _, err := limiter.acquire(context.Background()); !errors.Is(err, errExecutionBusy) {
+		t.Fatalf("expected a full queue rejected, got %v", err)
+	}
+
+	first()
+	if err := <-acquired; err != nil {
+		t.Fatalf("expected the queued execution to get the released slot, got %v", err)
+	}
+	if atomic.LoadInt64(&limiter.waiting) != 0 || len(limiter.slots) != 1 {
+		t.Fatalf("expected one slot held and nothing queued, got %d and %d", len(limiter.slots), atomic.LoadInt64(&limiter.waiting))
+	}
+}
+
+func TestExecutionLimiter_QueueWaitEnds(t *testing.T) {
+	limiter := newExecutionLimiter(&core.UIConfig{MaxConcurrentRequests: 1})
+	limiter.queueTimeout = 20 * time.Millisecond
+	release, _ := limiter.acquire(context.Background())
+
+	started := time.Now()
+	if _, err := limiter.acquire(context.Background()); !errors.Is(err, errExecutionBusy) || time.Since(started) < limiter.queueTimeout {
+		t.Fatalf("expected the wait to time out as busy, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := limiter.acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a canceled caller to stop waiting, got %v", err)
+	}
+
+	// Timed out and canceled callers leave the queue and the slots as they were
+	release()
+	if atomic.LoadInt64(&limiter.waiting) != 0 || len(limiter.slots) != 0 {
+		t.Fatalf("expected an idle limiter, got %d slots and %d queued", len(limiter.slots), atomic.LoadInt64(&limiter.waiting))
+	}
+	if again, err := limiter.acquire(context.Background()); err != nil {
+		t.Fatalf("expected the slot free again, got %v", err)
+	} else {
+		again()
+	}
+}
+
+func TestExecutionLimiter_IsPerHandler(t *testing.T) {
+	config := &core.Config{DocsPath: "/docs", UIConfig: &core.UIConfig{MaxConcurrentRequests: 1, MaxQueuedRequests: 1, QueueTimeout: 1}}
+	busy, idle := NewHandler(nil, config), NewHandler(nil, config)
+	busy.limiter.queueTimeout = 10 * time.Millisecond
+	release, _ := busy.limiter.acquire(context.Background())
+	defer release()
+
+	recorder := httptest.NewRecorder()
+	busy.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/docs/test", strings.NewReader(`{"method":"GET","url":"http://127.0.0.1:1"}`)))
+	var body map[string]interface{}
+	json.Unmarshal(recorder.Body.Bytes(), &body)
+	if recorder.Code != http.StatusServiceUnavailable || recorder.Header().Get("Retry-After") != "1" || body["retry_after"] != float64(1) {
+		t.Fatalf("expected a busy handler to answer 503 with Retry-After, got %d %v %s", recorder.Code, recorder.Header(), recorder.Body.String())
+	}
+
+	// Another docs instance has its own slots
+	if other, err := idle.limiter.acquire(context.Background()); err != nil {
+		t.Fatalf("expected another handler unaffected, got %v", err)
+	} else {
+		other()
+	}
+
+	if unlimited := newExecutionLimiter(&core.UIConfig{MaxConcurrentRequests: -1}); unlimited != nil {
+		t.Fatal("expected a negative MaxConcurrentRequests to disable limiting")
+	}
+	var unlimited *executionLimiter
+	if release, err := unlimited.acquire(context.Background()); err != nil || unlimited.retryAfter() != 1 {
+		t.Fatalf("expected a nil limiter to admit everything, got %v", err)
+	} else {
+		release()
+	}
+	if defaults := newExecutionLimiter(nil); cap(defaults.slots) != defaultMaxConcurrentRequests || defaults.retryAfter() != 30 {
+		t.Fatalf("expected the default limits, got %d slots and %ds", cap(defaults.slots), defaults.retryAfter())
+	}
+}
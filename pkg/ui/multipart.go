@@ -0,0 +1,224 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TestRequestPart is a part of a multipart/form-data test request: a text
+// field, or a file given as base64 content or by the ID of an upload
+type TestRequestPart struct {
+	Name        string `json:"name"`
+	Value       string `json:"value,omitempty"`        // text field value
+	Filename    string `json:"filename,omitempty"`     // sends the part as a file; defaults to the uploaded file name
+	ContentType string `json:"content_type,omitempty"` // file content type; detected when empty
+	Content     string `json:"content,omitempty"`      // base64 file content, a data: URL is accepted too
+	UploadID    string `json:"upload_id,omitempty"`    // file uploaded to /test/upload
+}
+
+// isFile reports whether the part is sent as a file
+func (p TestRequestPart) isFile() bool {
+	return p.Filename != "" || p.Content != "" || p.UploadID != ""
+}
+
+// UploadResponse describes a file uploaded for test requests
+type UploadResponse struct {
+	UploadID    string    `json:"upload_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// serveUpload keeps the "file" field of a multipart upload so test requests
+// can send it by its ID instead of inlining it as base64
+func (h *Handler) serveUpload(w http.ResponseWriter, r *http.Request) {
+	// Enable CORS
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.limits.request)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Expected a multipart/form-data upload", http.StatusBadRequest)
+		return
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			http.Error(w, `Upload has no "file" field`, http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			h.writeUploadError(w, err)
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		filename := part.FileName()
+		contentType := part.Header.Get("Content-Type")
+		if byExtension := mime.TypeByExtension(filepath.Ext(filename)); byExtension != "" && (contentType == "" || contentType == "application/octet-stream") {
+			contentType = byExtension
+		}
+		id, size, err := h.uploads.keep(filename, contentType, nil, part, h.limits.request)
+		part.Close()
+		if err != nil {
+			h.writeUploadError(w, err)
+			return
+		}
+		if id == "" {
+			h.writeUploadError(w, &http.MaxBytesError{Limit: h.limits.request})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(UploadResponse{
+			UploadID:    id,
+			Filename:    filename,
+			ContentType: contentType,
+			Size:        size,
+			ExpiresAt:   time.Now().Add(keepTTL),
+		})
+		return
+	}
+}
+
+func (h *Handler) writeUploadError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, fmt.Sprintf("Upload is larger than %d bytes", h.limits.request), http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, fmt.Sprintf("Failed to read upload: %v", err), http.StatusBadRequest)
+}
+
+// multipartBody encodes parts as a multipart/form-data body of at most
+// limit bytes and returns it with its Content-Type
+func (h *Handler) multipartBody(parts []TestRequestPart, limit int64) (*bytes.Buffer, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for _, part := range parts {
+		if part.Name == "" {
+			return nil, "", fmt.Errorf("multipart part without a name")
+		}
+		if !part.isFile() {
+			if err := writer.WriteField(part.Name, part.Value); err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+		if err := h.writeFilePart(writer, part); err != nil {
+			return nil, "", fmt.Errorf("part %s: %w", part.Name, err)
+		}
+		if int64(buf.Len()) > limit {
+			return nil, "", fmt.Errorf("multipart body is larger than %d bytes", limit)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return &buf, writer.FormDataContentType(), nil
+}
+
+// writeFilePart writes a file part from its base64 content or upload
+func (h *Handler) writeFilePart(writer *multipart.Writer, part TestRequestPart) error {
+	var content io.Reader = strings.NewReader("")
+	filename, contentType := part.Filename, part.ContentType
+	switch {
+	case part.UploadID != "":
+		upload, ok := h.uploads.get(part.UploadID)
+		if !ok {
+			return fmt.Errorf("upload %s not found or expired", part.UploadID)
+		}
+		file, err := os.Open(upload.file)
+		if err != nil {
+			return fmt.Errorf("upload %s not found or expired", part.UploadID)
+		}
+		defer file.Close()
+		content = file
+		if filename == "" {
+			filename = upload.name
+		}
+		if contentType == "" {
+			contentType = upload.contentType
+		}
+	case part.Content != "":
+		data, dataType, err := decodeFileContent(part.Content)
+		if err != nil {
+			return err
+		}
+		content = bytes.NewReader(data)
+		if contentType == "" {
+			contentType = dataType
+		}
+		if contentType == "" {
+			contentType = http.DetectContentType(data)
+		}
+	}
+	if filename == "" {
+		filename = part.Name
+	}
+	if contentType == "" {
+		if contentType = mime.TypeByExtension(filepath.Ext(filename)); contentType == "" {
+			contentType = "application/octet-stream"
+		}
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(part.Name), quoteEscaper.Replace(filename)))
+	header.Set("Content-Type", contentType)
+	target, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(target, content)
+	return err
+}
+
+// decodeFileContent decodes base64 file content, or a data: URL along with
+// its media type
+func decodeFileContent(content string) ([]byte, string, error) {
+	mediaType := ""
+	if strings.HasPrefix(content, "data:") {
+		meta, data, ok := strings.Cut(strings.TrimPrefix(content, "data:"), ",")
+		if !ok || !strings.HasSuffix(meta, ";base64") {
+			return nil, "", fmt.Errorf("file content must be a base64 data URL")
+		}
+		mediaType, content = strings.TrimSuffix(meta, ";base64"), data
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(content))
+	if err != nil {
+		if data, err = base64.RawStdEncoding.DecodeString(strings.TrimSpace(content)); err != nil {
+			return nil, "", fmt.Errorf("file content is not valid base64: %w", err)
+		}
+	}
+	return data, mediaType, nil
+}
@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// upload posts content as the "file" field of a multipart upload
+func upload(handler *Handler, filename, contentType string, content []byte) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("note", "ignored")
+	header := make(map[string][]string)
+	header["Content-Disposition"] = []string{`form-data; name="file"; filename="` + filename + `"`}
+	header["Content-Type"] = []string{contentType}
+	part, _ := writer.CreatePart(header)
+	part.Write(content)
+	writer.Close()
+
+	request := httptest.NewRequest(http.MethodPost, "/docs/test/upload", &body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// receivedPart is a part read back from an encoded multipart body
+type receivedPart struct {
+	filename    string
+	contentType string
+	content     string
+}
+
+func readParts(t *testing.T, body io.Reader, contentType string) map[string]receivedPart {
+	t.Helper()
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := make(map[string]receivedPart)
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(part)
+		parts[part.FormName()] = receivedPart{part.FileName(), part.Header.Get("Content-Type"), string(content)}
+	}
+}
+
+func TestMultipartBody_EncodesBase64DataURLsAndUploads(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	handler := NewHandler(nil, &core.Config{DocsPath: "/docs"})
+
+	uploaded := upload(handler, "report.csv", "application/octet-stream", []byte("id,total\n1,9\n"))
+	var response UploadResponse
+	if err := json.Unmarshal(uploaded.Body.Bytes(), &response); err != nil || uploaded.Code != http.StatusOK {
+		t.Fatalf("upload failed: %d %s", uploaded.Code, uploaded.Body.String())
+	}
+	if response.UploadID == "" || response.Filename != "report.csv" || response.ContentType != "text/csv; charset=utf-8" || response.Size != 13 {
+		t.Fatalf("expected the upload described with its extension type, got %+v", response)
+	}
+
+	body, contentType, err := handler.multipartBody([]TestRequestPart{
+		{Name: "title", Value: "Q3"},
+		{Name: "raw", Content: base64.StdEncoding.EncodeToString([]byte("%PDF-1.4 raw"))},
+		{Name: "unpadded", Filename: "notes.txt", Content: base64.RawStdEncoding.EncodeToString([]byte("hi"))},
+		{Name: "avatar", Filename: "me.gif", Content: "data:image/gif;base64," + base64.StdEncoding.EncodeToString([]byte("GIF89a"))},
+		{Name: "report", UploadID: response.UploadID},
+		{Name: "renamed", UploadID: response.UploadID, Filename: "totals.csv", ContentType: "text/plain"},
+	}, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := readParts(t, body, contentType)
+	for name, want := range map[string]receivedPart{
+		"title":    {"", "", "Q3"},
+		"raw":      {"raw", "application/pdf", "%PDF-1.4 raw"},
+		"unpadded": {"notes.txt", "text/plain; charset=utf-8", "hi"},
+		"avatar":   {"me.gif", "image/gif", "GIF89a"},
+		"report":   {"report.csv", "text/csv; charset=utf-8", "id,total\n1,9\n"},
+		"renamed":  {"totals.csv", "text/plain", "id,total\n1,9\n"},
+	} {
+		if got := parts[name]; got != want {
+			t.Errorf("part %s: expected %+v, got %+v", name, want, got)
+		}
+	}
+
+	for _, tc := range []struct {
+		part TestRequestPart
+		want string
+	}{
+		{TestRequestPart{Value: "x"}, "part without a name"},
+		{TestRequestPart{Name: "file", Content: "not base64!"}, "not valid base64"},
+		{TestRequestPart{Name: "file", Content: "data:text/plain,hello"}, "must be a base64 data URL"},
+		{TestRequestPart{Name: "file", UploadID: "unknown"}, "upload unknown not found or expired"},
+	} {
+		if _, _, err := handler.multipartBody([]TestRequestPart{tc.part}, 1<<20); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("part %+v: expected %q, got %v", tc.part, tc.want, err)
+		}
+	}
+}
+
+func TestExecuteTestRequest_SendsMultipartParts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("document")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"owner": r.FormValue("owner"), "file": header.Filename, "content": string(content)})
+	}))
+	defer server.Close()
+
+	handler := NewHandler(nil, &core.Config{DocsPath: "/docs"})
+	parts := []TestRequestPart{
+		{Name: "owner", Value: "ada"},
+		{Name: "document", Filename: "a.txt", Content: base64.StdEncoding.EncodeToString([]byte("hello"))},
+	}
+	response := handler.executeTestRequest(TestRequest{
+		Method:  "POST",
+		URL:     server.URL,
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Parts:   parts,
+	}, nil)
+	if response.StatusCode != http.StatusOK || !strings.Contains(response.Body, `"content": "hello"`) || !strings.Contains(response.Body, `"owner": "ada"`) {
+		t.Fatalf("expected the parts received as a form, got %d %s %s", response.StatusCode, response.Body, response.Error)
+	}
+
+	conflicting := handler.executeTestRequest(TestRequest{Method: "POST", URL: server.URL, Body: "{}", Parts: parts}, nil)
+	if conflicting.Error != "Body and parts cannot both be set" {
+		t.Fatalf("expected body and parts rejected together, got %q", conflicting.Error)
+	}
+}
+
+func TestUploads_EnforceTheRequestSizeLimit(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	handler := NewHandler(nil, &core.Config{DocsPath: "/docs", UIConfig: &core.UIConfig{MaxRequestBytes: 1024}})
+
+	if tooLarge := upload(handler, "big.bin", "application/octet-stream", bytes.Repeat([]byte("x"), 2048)); tooLarge.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected an oversized upload rejected with 413, got %d %s", tooLarge.Code, tooLarge.Body.String())
+	}
+	if small := upload(handler, "small.bin", "application/octet-stream", bytes.Repeat([]byte("x"), 512)); small.Code != http.StatusOK {
+		t.Fatalf("expected an upload under the limit kept, got %d %s", small.Code, small.Body.String())
+	}
+
+	noFile := httptest.NewRequest(http.MethodPost, "/docs/test/upload", strings.NewReader("--b\r\nContent-Disposition: form-data; name=\"other\"\r\n\r\nx\r\n--b--\r\n"))
+	noFile.Header.Set("Content-Type", "multipart/form-data; boundary=b")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, noFile)
+	if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), `no "file" field`) {
+		t.Fatalf("expected an upload without a file rejected, got %d %s", recorder.Code, recorder.Body.String())
+	}
+	if get := fetch(handler, "/docs/test/upload"); get.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected GET uploads not allowed, got %d", get.Code)
+	}
+
+	content := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("x"), 2048))
+	if _, _, err := handler.multipartBody([]TestRequestPart{{Name: "file", Content: content}}, handler.limits.request); err == nil || !strings.Contains(err.Error(), "larger than 1024 bytes") {
+		t.Fatalf("expected an oversized multipart body rejected, got %v", err)
+	}
+}
@@ -27,10 +27,16 @@ type ScenarioRequest struct {
 	URL          string                 `json:"url"`
 	Headers      map[string]string      `json:"headers,omitempty"`
 	Body         string                 `json:"body,omitempty"`
+	Parts        []TestRequestPart      `json:"parts,omitempty"`
 	Config       RequestConfig          `json:"config"`
 	Variables    map[string]string      `json:"variables,omitempty"`
 	Tests        []string               `json:"tests,omitempty"`
 	Dependencies []string               `json:"dependencies,omitempty"`
+
+	// Scripts run before the request is sent and after its response
+	// arrives, see script.go
+	PreRequestScript   string `json:"pre_request_script,omitempty"`
+	PostResponseScript string `json:"post_response_script,omitempty"`
 }
 
 // ScenarioConfig represents scenario configuration
@@ -41,6 +47,11 @@ type ScenarioConfig struct {
 	BaseURL        string            `json:"base_url"`
 	Auth           AuthConfig        `json:"auth"`
 	Environment    map[string]string `json:"environment,omitempty"`
+	KeepCookies    bool              `json:"keep_cookies,omitempty"` // send cookies set by earlier requests of a run
+
+	// Scripts run around every request, before the request's own scripts
+	PreRequestScript   string `json:"pre_request_script,omitempty"`
+	PostResponseScript string `json:"post_response_script,omitempty"`
 }
 
 // RequestConfig represents request-specific configuration
@@ -151,6 +162,10 @@ func (h *Handler) createScenario(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Scenario name is required", http.StatusBadRequest)
 		return
 	}
+	if err := validateScenarioScripts(&scenario); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	// Set defaults
 	if scenario.Config.ExecutionMode == "" {
@@ -205,6 +220,10 @@ func (h *Handler) updateScenario(w http.ResponseWriter, r *http.Request, scenari
 		http.Error(w, "Scenario name is required", http.StatusBadRequest)
 		return
 	}
+	if err := validateScenarioScripts(&updates); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	scenarios[scenarioID] = &updates
 
@@ -274,6 +293,10 @@ func (h *Handler) importScenarios(w http.ResponseWriter, r *http.Request) {
 			errors = append(errors, fmt.Sprintf("Scenario missing name: %s", scenario.ID))
 			continue
 		}
+		if err := validateScenarioScripts(&scenario); err != nil {
+			errors = append(errors, fmt.Sprintf("Scenario %s: %v", scenario.Name, err))
+			continue
+		}
 
 		// Generate new ID if not exists or conflicts
 		if scenario.ID == "" || scenarios[scenario.ID] != nil {
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+// Scenario scripts run with the engine of pkg/core, which the docs UI
+// scenario runner uses too; see core.RunScenarioScripts
+
+// validateScenarioScripts parses the scripts of a scenario so syntax errors
+// are reported when it is saved rather than when it runs
+func validateScenarioScripts(scenario *Scenario) error {
+	check := func(name, source string) error {
+		if err := core.ValidateScript(source); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	}
+	if err := check("scenario pre-request script", scenario.Config.PreRequestScript); err != nil {
+		return err
+	}
+	if err := check("scenario post-response script", scenario.Config.PostResponseScript); err != nil {
+		return err
+	}
+	for _, request := range scenario.Requests {
+		if err := check(fmt.Sprintf("request %s pre-request script", request.ID), request.PreRequestScript); err != nil {
+			return err
+		}
+		if err := check(fmt.Sprintf("request %s post-response script", request.ID), request.PostResponseScript); err != nil {
+			return err
+		}
+	}
+	return nil
+}
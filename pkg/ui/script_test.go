@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
+)
+
+func TestExecuteScenario_ScriptsSignExtractAndAssert(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write([]byte(r.Method + "\n" + r.URL.RequestURI() + "\n" + r.Header.Get("X-Timestamp") + "\n" + string(body)))
+		if hex.EncodeToString(mac.Sum(nil)) != r.Header.Get("X-Signature") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data":{"id":42}}`))
+	}))
+	defer server.Close()
+
+	handler := NewHandler(nil, &core.Config{})
+	result := handler.executeScenario(&Scenario{
+		Config: ScenarioConfig{
+			ContinueOnFail: true,
+			Environment:    map[string]string{"secret": "s3cret"},
+			PreRequestScript: "set ts = unix()\nheader X-Timestamp = ts\n" +
+				`header X-Signature = hmacSHA256(secret, method + "\n" + path + "\n" + ts + "\n" + body)`,
+		},
+		Requests: []ScenarioRequest{
+			{ID: "create", Method: "POST", URL: server.URL + "/users?at={{ts}}", Body: `{"at":"{{ts}}"}`,
+				Headers:            map[string]string{"Content-Type": "application/json"},
+				PostResponseScript: "assert status == 201\nset userId = json(\"data.id\")"},
+			{ID: "expect-200", Method: "GET", URL: server.URL + "/users/{{userId}}",
+				PostResponseScript: "assert status == 200, \"found\""},
+			{ID: "broken", Method: "GET", URL: server.URL + "/users",
+				PreRequestScript: "set a = 1 / 0"},
+		},
+	})
+
+	if len(result.Results) != 3 {
+		t.Fatalf("expected three results, got %+v", result.Results)
+	}
+	create, expect200, broken := result.Results[0], result.Results[1], result.Results[2]
+	if !create.Success || create.StatusCode != http.StatusCreated || len(create.Tests) != 1 || !create.Tests[0].Passed {
+		t.Fatalf("expected the signed request accepted, got %+v", create)
+	}
+	if create.Variables["userId"] != "42" || result.Variables["userId"] != "42" || result.Variables["ts"] == "" {
+		t.Fatalf("expected the script variables kept for later requests, got %v", result.Variables)
+	}
+	if expect200.Success || expect200.StatusCode != http.StatusCreated || expect200.Error != "1 of 1 assertions failed" || expect200.Tests[0].Name != "found" {
+		t.Fatalf("expected the failed assertion to fail a 2xx request, got %+v", expect200)
+	}
+	if broken.Success || broken.StatusCode != 0 || !strings.HasPrefix(broken.Error, "pre-request script: line 1: division by zero") {
+		t.Fatalf("expected the request not sent after a script error, got %+v", broken)
+	}
+	if result.Successful != 1 || result.Failed != 2 {
+		t.Fatalf("expected 1 successful and 2 failed requests, got %d and %d", result.Successful, result.Failed)
+	}
+
+	if err := validateScenarioScripts(&Scenario{Requests: []ScenarioRequest{{ID: "r1", PostResponseScript: "assert ("}}}); err == nil ||
+		!strings.Contains(err.Error(), "request r1 post-response script: line 1") {
+		t.Fatalf("expected the syntax error located, got %v", err)
+	}
+}
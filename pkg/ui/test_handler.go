@@ -2,11 +2,14 @@ package ui
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/idnexacloud/bytedocs-go/pkg/core"
 )
 
 // TestRequest represents a test request
@@ -15,9 +18,11 @@ type TestRequest struct {
 	URL        string            `json:"url"`
 	Headers    map[string]string `json:"headers,omitempty"`
 	Body       string            `json:"body,omitempty"`
+	Parts      []TestRequestPart `json:"parts,omitempty"` // multipart/form-data body, instead of Body
 	Parameters map[string]string `json:"parameters,omitempty"`
 	Auth       TestAuthConfig    `json:"auth,omitempty"`
 	Timeout    int               `json:"timeout,omitempty"`
+	UseCookieJar bool            `json:"use_cookie_jar,omitempty"` // send and keep cookies in the caller's jar, see /test/cookies
 }
 
 // TestAuthConfig represents authentication for test requests
@@ -41,6 +46,16 @@ type TestResponse struct {
 	RequestInfo  TestRequest            `json:"request_info"`
 	ResponseSize int64                  `json:"response_size"`
 	Timestamp    time.Time              `json:"timestamp"`
+
+	// Rendering metadata so the UI can present non-JSON bodies readably
+	ContentType string `json:"content_type,omitempty"`
+	BodyType    string `json:"body_type"`         // "json", "xml", "html", "text", "binary", "empty"
+	Title       string `json:"title,omitempty"`   // HTML page title
+	Preview     string `json:"preview,omitempty"` // Plain-text rendering of HTML or a binary notice
+	MediaType   string `json:"media_type,omitempty"`   // Detected type of binary bodies, e.g. "image/png"
+	DataURL     string `json:"data_url,omitempty"`     // Base64 data URL of small images for an inline preview
+	Truncated   bool   `json:"truncated,omitempty"`    // Body holds only the first UIConfig.MaxResponseBytes
+	DownloadURL string `json:"download_url,omitempty"` // Full body of truncated and binary responses for 10 minutes; add ?inline=1 to view images, PDFs and media
 }
 
 // serveTestEndpoint handles test execution requests
@@ -63,19 +78,39 @@ func (h *Handler) serveTestEndpoint(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	var testReq TestRequest
+	r.Body = http.MaxBytesReader(w, r.Body, h.limits.request)
 	if err := json.NewDecoder(r.Body).Decode(&testReq); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, fmt.Sprintf("Test request is larger than %d bytes", h.limits.request), http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 
+	release, err := h.limiter.acquire(r.Context())
+	if err != nil {
+		h.writeBusyResponse(w, err)
+		return
+	}
+	defer release()
+
 	// Execute test request
-	response := h.executeTestRequest(testReq)
+	var jar http.CookieJar
+	if testReq.UseCookieJar {
+		if visitorJar := h.cookieJar(w, r, true); visitorJar != nil {
+			jar = visitorJar
+		}
+	}
+	response := h.executeTestRequest(testReq, jar)
 
 	json.NewEncoder(w).Encode(response)
 }
 
-// executeTestRequest executes a test request and returns the response
-func (h *Handler) executeTestRequest(testReq TestRequest) TestResponse {
+// executeTestRequest executes a test request and returns the response; jar,
+// when not nil, sends and keeps the cookies
+func (h *Handler) executeTestRequest(testReq TestRequest, jar http.CookieJar) TestResponse {
 	startTime := time.Now()
 
 	response := TestResponse{
@@ -112,7 +147,22 @@ func (h *Handler) executeTestRequest(testReq TestRequest) TestResponse {
 
 	// Create HTTP request
 	var bodyReader io.Reader
-	if testReq.Body != "" && (testReq.Method == "POST" || testReq.Method == "PUT" || testReq.Method == "PATCH") {
+	multipartType := ""
+	hasBody := testReq.Method == "POST" || testReq.Method == "PUT" || testReq.Method == "PATCH"
+	if len(testReq.Parts) > 0 && hasBody {
+		if testReq.Body != "" {
+			response.Error = "Body and parts cannot both be set"
+			response.Duration = time.Since(startTime).Milliseconds()
+			return response
+		}
+		buf, contentType, err := h.multipartBody(testReq.Parts, h.limits.request)
+		if err != nil {
+			response.Error = fmt.Sprintf("Failed to build multipart body: %v", err)
+			response.Duration = time.Since(startTime).Milliseconds()
+			return response
+		}
+		bodyReader, multipartType = buf, contentType
+	} else if testReq.Body != "" && hasBody {
 		bodyReader = strings.NewReader(testReq.Body)
 	}
 
@@ -130,8 +180,11 @@ func (h *Handler) executeTestRequest(testReq TestRequest) TestResponse {
 		}
 	}
 
-	// Set Content-Type for requests with body
-	if testReq.Body != "" && req.Header.Get("Content-Type") == "" {
+	// Set Content-Type for requests with body; a multipart body needs its own
+	// boundary whatever the headers say
+	if multipartType != "" {
+		req.Header.Set("Content-Type", multipartType)
+	} else if testReq.Body != "" && req.Header.Get("Content-Type") == "" {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
@@ -144,10 +197,15 @@ func (h *Handler) executeTestRequest(testReq TestRequest) TestResponse {
 		timeout = time.Duration(testReq.Timeout) * time.Millisecond
 	}
 
-	client := &http.Client{
-		Timeout: timeout,
+	client, err := h.testClient(fullURL, timeout)
+	if err != nil {
+		response.Error = fmt.Sprintf("Failed to configure HTTP client: %v", err)
+		response.Duration = time.Since(startTime).Milliseconds()
+		return response
 	}
 
+	client.Jar = jar
+
 	// Execute request
 	resp, err := client.Do(req)
 	if err != nil {
@@ -157,35 +215,91 @@ func (h *Handler) executeTestRequest(testReq TestRequest) TestResponse {
 	}
 	defer resp.Body.Close()
 
-	// Read response body
-	bodyBytes, err := io.ReadAll(resp.Body)
+	// Read response body up to the inline limit
+	readBytes, err := readLimited(resp.Body, h.limits.response)
 	if err != nil {
 		response.Error = fmt.Sprintf("Failed to read response: %v", err)
 		response.Duration = time.Since(startTime).Milliseconds()
 		return response
 	}
+	contentType := resp.Header.Get("Content-Type")
+	complete := int64(len(readBytes)) <= h.limits.response
+	bodyBytes := readBytes
+	if !complete {
+		bodyBytes = readBytes[:h.limits.response]
+	}
+	response.ResponseSize = int64(len(bodyBytes))
+
+	// Keep the whole of a larger body for download when it fits
+	if !complete && h.limits.download > 0 {
+		if id, size, err := h.downloads.keep("", contentType, readBytes, resp.Body, h.limits.download); err == nil && id != "" {
+			response.DownloadURL = h.downloadURL(id)
+			response.ResponseSize = size
+		}
+	}
+	if !complete && response.DownloadURL == "" && resp.ContentLength > response.ResponseSize {
+		response.ResponseSize = resp.ContentLength
+	}
 
 	// Build response
 	response.StatusCode = resp.StatusCode
 	response.Headers = resp.Header
-	response.Body = string(bodyBytes)
-	response.ResponseSize = int64(len(bodyBytes))
 	response.Duration = time.Since(startTime).Milliseconds()
 	response.Success = resp.StatusCode >= 200 && resp.StatusCode < 400
-
-	// Pretty format JSON response if possible
-	if strings.Contains(resp.Header.Get("Content-Type"), "application/json") {
-		var jsonData interface{}
-		if err := json.Unmarshal(bodyBytes, &jsonData); err == nil {
-			if prettyJSON, err := json.MarshalIndent(jsonData, "", "  "); err == nil {
-				response.Body = string(prettyJSON)
-			}
+	response.Truncated = !complete
+
+	// Pretty format JSON/XML and build readable previews for HTML error pages
+	// and binary bodies
+	rendered := renderResponseBody(contentType, bodyBytes, response.ResponseSize, complete)
+	response.ContentType = contentType
+	response.BodyType = rendered.BodyType
+	response.Body = rendered.Body
+	response.Title = rendered.Title
+	response.Preview = rendered.Preview
+	response.MediaType = rendered.MediaType
+	response.DataURL = rendered.DataURL
+
+	// Binary bodies are not returned inline, offer them for download instead
+	if complete && rendered.BodyType == bodyTypeBinary && h.limits.download > 0 {
+		if id, _, err := h.downloads.keep("", contentType, bodyBytes, nil, h.limits.download); err == nil && id != "" {
+			response.DownloadURL = h.downloadURL(id)
 		}
 	}
 
 	return response
 }
 
+// downloadURL returns the URL of a kept response body
+func (h *Handler) downloadURL(id string) string {
+	return h.config.DocsPath + "/test/download/" + id
+}
+
+// testClient returns a client with the UIConfig.TestClient settings for the
+// target URL; transports are built once per settings and reused
+func (h *Handler) testClient(target string, timeout time.Duration) (*http.Client, error) {
+	var settings *core.TestClientConfig
+	if h.config.UIConfig != nil {
+		settings = h.config.UIConfig.TestClient.ForURL(target, h.config.BaseURLs)
+	}
+
+	h.transportsMu.Lock()
+	defer h.transportsMu.Unlock()
+	transport, ok := h.transports[settings]
+	if !ok {
+		var err error
+		if transport, err = settings.Transport(); err != nil {
+			return nil, err
+		}
+		h.transports[settings] = transport
+	}
+
+	return &http.Client{
+		Timeout:       timeout,
+		Transport:     transport,
+		CheckRedirect: settings.CheckRedirect,
+	}, nil
+}
+
 // setAuthentication sets authentication headers based on auth config
 func (h *Handler) setAuthentication(req *http.Request, auth TestAuthConfig) {
 	switch auth.Type {
@@ -237,6 +351,14 @@ func (h *Handler) serveScenarioExecution(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// A scenario holds one slot for its whole run so it is never rejected halfway
+	release, err := h.limiter.acquire(r.Context())
+	if err != nil {
+		h.writeBusyResponse(w, err)
+		return
+	}
+	defer release()
+
 	// Execute scenario
 	results := h.executeScenario(scenario)
 
@@ -305,9 +427,14 @@ func (h *Handler) executeScenario(scenario *Scenario) ScenarioExecutionResult {
 		result.Error = "Parallel execution not yet implemented"
 		result.Status = "failed"
 	} else {
-		// Sequential execution
+		// Sequential execution, with a cookie jar of its own when the scenario
+		// keeps cookies between requests
+		var jar http.CookieJar
+		if scenario.Config.KeepCookies {
+			jar = newTestCookieJar()
+		}
 		for _, scenarioReq := range scenario.Requests {
-			requestResult := h.executeScenarioRequest(scenarioReq, scenario.Config, result.Variables)
+			requestResult := h.executeScenarioRequest(scenarioReq, scenario.Config, result.Variables, jar)
 			result.Results = append(result.Results, requestResult)
 
 			if requestResult.Success {
@@ -345,7 +472,7 @@ func (h *Handler) executeScenario(scenario *Scenario) ScenarioExecutionResult {
 }
 
 // executeScenarioRequest executes a single request within a scenario
-func (h *Handler) executeScenarioRequest(scenarioReq ScenarioRequest, config ScenarioConfig, variables map[string]string) ScenarioRequestResult {
+func (h *Handler) executeScenarioRequest(scenarioReq ScenarioRequest, config ScenarioConfig, variables map[string]string, jar http.CookieJar) ScenarioRequestResult {
 	result := ScenarioRequestResult{
 		RequestID: scenarioReq.ID,
 		Method:    scenarioReq.Method,
@@ -354,12 +481,42 @@ func (h *Handler) executeScenarioRequest(scenarioReq ScenarioRequest, config Sce
 		Variables: make(map[string]string),
 	}
 
+	// Variables of this request: the scenario's, then those its scripts set
+	scope := make(map[string]string, len(variables))
+	for key, value := range variables {
+		scope[key] = value
+	}
+
+	// Use example body if configured
+	body := scenarioReq.Body
+	if scenarioReq.Config.UseExampleBody && len(scenarioReq.Config.Body) > 0 {
+		if bodyJSON, err := json.Marshal(scenarioReq.Config.Body); err == nil {
+			body = string(bodyJSON)
+		}
+	}
+
+	// Run the pre-request scripts, which may set variables and headers
+	env := &core.ScriptEnv{
+		Variables: scope,
+		Assigned:  result.Variables,
+		Request:   &core.ScriptRequest{Method: scenarioReq.Method, URL: scenarioReq.URL, Body: body},
+		Replace:   func(text string) string { return h.replaceVariables(text, scope) },
+		Headers:   make(map[string]string, len(scenarioReq.Headers)),
+	}
+	for key, value := range scenarioReq.Headers {
+		env.Headers[key] = value
+	}
+	if err := core.RunScenarioScripts(env, "pre-request", config.PreRequestScript, scenarioReq.PreRequestScript); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
 	// Build test request from scenario request
 	testReq := TestRequest{
 		Method:  scenarioReq.Method,
-		URL:     h.replaceVariables(scenarioReq.URL, variables),
-		Headers: scenarioReq.Headers,
-		Body:    h.replaceVariables(scenarioReq.Body, variables),
+		URL:     h.replaceVariables(scenarioReq.URL, scope),
+		Headers: env.Headers,
+		Body:    h.replaceVariables(body, scope),
 		Auth: TestAuthConfig{
 			Type:     config.Auth.Type,
 			Token:    config.Auth.Token,
@@ -370,16 +527,14 @@ func (h *Handler) executeScenarioRequest(scenarioReq ScenarioRequest, config Sce
 		},
 		Timeout: config.Timeout,
 	}
-
-	// Use example body if configured
-	if scenarioReq.Config.UseExampleBody && len(scenarioReq.Config.Body) > 0 {
-		if bodyJSON, err := json.Marshal(scenarioReq.Config.Body); err == nil {
-			testReq.Body = string(bodyJSON)
-		}
+	for _, part := range scenarioReq.Parts {
+		part.Value = h.replaceVariables(part.Value, scope)
+		part.Filename = h.replaceVariables(part.Filename, scope)
+		testReq.Parts = append(testReq.Parts, part)
 	}
 
 	// Execute the request
-	testResponse := h.executeTestRequest(testReq)
+	testResponse := h.executeTestRequest(testReq, jar)
 
 	// Map test response to scenario result
 	result.StatusCode = testResponse.StatusCode
@@ -387,19 +542,50 @@ func (h *Handler) executeScenarioRequest(scenarioReq ScenarioRequest, config Sce
 	result.Success = testResponse.Success
 	result.Error = testResponse.Error
 
-	// Parse response for variable extraction
+	// Parse response for display
 	if testResponse.Success && testResponse.Body != "" {
 		var responseData interface{}
 		if err := json.Unmarshal([]byte(testResponse.Body), &responseData); err == nil {
 			result.Response = responseData
-			// TODO: Extract variables from response based on scenario configuration
 		} else {
 			result.Response = testResponse.Body
 		}
 	}
 
-	// TODO: Execute test assertions
-	// For now, just basic status code check
+	// Run the post-response scripts, which extract variables and assert
+	env.Request = nil
+	env.Response = &core.ScriptResponse{
+		StatusCode: testResponse.StatusCode,
+		Headers:    testResponse.Headers,
+		Body:       testResponse.Body,
+		Duration:   testResponse.Duration,
+	}
+	if testResponse.Error == "" {
+		if err := core.RunScenarioScripts(env, "post-response", config.PostResponseScript, scenarioReq.PostResponseScript); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+	}
+
+	// Assertions decide success; without any, check for a 2xx status
+	if len(env.Tests) > 0 {
+		for _, test := range env.Tests {
+			result.Tests = append(result.Tests, TestResult(test))
+		}
+		if result.Error == "" {
+			failed := 0
+			for _, test := range env.Tests {
+				if !test.Passed {
+					failed++
+				}
+			}
+			result.Success = failed == 0
+			if failed > 0 {
+				result.Error = fmt.Sprintf("%d of %d assertions failed", failed, len(env.Tests))
+			}
+		}
+		return result
+	}
 	result.Tests = []TestResult{
 		{
 			Name:   "Status code is 2xx",